@@ -0,0 +1,128 @@
+package api
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// accessLogPolicy decides which requests reach the access log.
+type accessLogPolicy struct {
+	// SlowThreshold flags requests at least this slow with slow=true.
+	SlowThreshold time.Duration
+	// SampleAfter is how many 2xx responses per route and second are always
+	// logged before SampleRates applies.
+	SampleAfter int
+	// SampleRates is the fraction of the remaining 2xx responses logged per
+	// route, with "*" as the default. Missing routes log everything.
+	SampleRates map[string]float64
+}
+
+func (p accessLogPolicy) rate(route string) float64 {
+	if r, ok := p.SampleRates[route]; ok {
+		return r
+	}
+	if r, ok := p.SampleRates["*"]; ok {
+		return r
+	}
+	return 1
+}
+
+// accessLog writes one JSON line per request to logger, separate from the
+// application logs. Errors and slow requests are always logged; 2xx responses
+// are sampled once a route exceeds SampleAfter requests in the current second.
+func accessLog(logger *zap.Logger, policy accessLogPolicy) gin.HandlerFunc {
+	s := &accessSampler{policy: policy, windows: map[string]*accessWindow{}}
+
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+		latency := time.Since(start)
+
+		route := ctx.FullPath()
+		status := ctx.Writer.Status()
+		slow := policy.SlowThreshold > 0 && latency >= policy.SlowThreshold
+		if status < 300 && status >= 200 && !slow && !s.keep(route, start) {
+			return
+		}
+
+		fields := []zap.Field{
+			zap.String("method", ctx.Request.Method),
+			zap.String("route", route),
+			zap.Int("status", status),
+			zap.Float64("latency_ms", float64(latency.Microseconds())/1000),
+			zap.Int("bytes", max(ctx.Writer.Size(), 0)),
+			zap.String("client_ip", ctx.ClientIP()),
+			zap.String("role", callerRole(ctx)),
+			zap.Bool("slow", slow),
+		}
+		// Las rutas con parámetros no se loguean con el path real para no
+		// exponer IDs; solo las no registradas, que no tienen plantilla
+		if route == "" {
+			fields = append(fields, zap.String("path", ctx.Request.URL.Path))
+		}
+		if len(ctx.Errors) > 0 {
+			fields = append(fields, zap.String("error", ctx.Errors.String()))
+		}
+		logger.Info("access", fields...)
+	}
+}
+
+// accessSampler cuenta las respuestas 2xx por ruta en ventanas de un segundo.
+type accessSampler struct {
+	policy  accessLogPolicy
+	mu      sync.Mutex
+	windows map[string]*accessWindow
+}
+
+type accessWindow struct {
+	second int64
+	count  int
+}
+
+// keep es determinístico: pasado SampleAfter loguea una de cada 1/rate.
+func (s *accessSampler) keep(route string, at time.Time) bool {
+	rate := s.policy.rate(route)
+	if rate >= 1 {
+		return true
+	}
+	if rate <= 0 {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w, ok := s.windows[route]
+	if !ok {
+		w = &accessWindow{}
+		s.windows[route] = w
+	}
+	if sec := at.Unix(); w.second != sec {
+		w.second, w.count = sec, 0
+	}
+	w.count++
+
+	over := w.count - s.policy.SampleAfter
+	if over <= 0 {
+		return true
+	}
+	every := int(1/rate + 0.5)
+	return over%every == 0
+}
+
+// newAccessLogger crea un logger JSON sin el sampling de zap, que ya aplica
+// accessLog por ruta.
+func newAccessLogger(output string) (*zap.Logger, error) {
+	cfg := zap.NewProductionConfig()
+	cfg.Sampling = nil
+	cfg.DisableCaller = true
+	cfg.DisableStacktrace = true
+	cfg.OutputPaths = []string{output}
+	logger, err := cfg.Build()
+	if err != nil {
+		return nil, err
+	}
+	return logger.Named("access"), nil
+}
@@ -0,0 +1,25 @@
+package api
+
+import (
+	"api_sales/internal/analytics"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recordUsage reports every request to a registered route to recorder. The
+// tenant is read after the handlers run, once authentication has set it.
+// Unregistered paths are skipped: without a route template the only name for
+// them is the raw path, which may carry IDs.
+func recordUsage(recorder *analytics.Recorder) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			return
+		}
+		recorder.Record(ctx.Request.Method, route, ctx.Writer.Status(), time.Since(start), tenantID(ctx))
+	}
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"api_sales/internal/keys"
+	"api_sales/internal/sales"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiKeyHeader carries the caller's API key.
+const apiKeyHeader = "X-API-Key"
+
+// authenticate resolves the caller role from the API key header. Requests
+// without a key keep the default role; unknown or retired keys get 401.
+func authenticate(manager *keys.Manager) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		secret := ctx.GetHeader(apiKeyHeader)
+		if secret == "" {
+			ctx.Next()
+			return
+		}
+
+		key, ok := manager.Authenticate(secret)
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid api key"})
+			return
+		}
+		if key.Role != "" {
+			ctx.Set(roleKey, key.Role)
+		}
+		ctx.Set(apiKeyIDKey, key.ID)
+		if key.Tenant != "" {
+			ctx.Set(tenantKey, key.Tenant)
+		}
+		ctx.Set(callerKey, &sales.Caller{UserID: key.UserID, Role: callerRole(ctx), Team: key.Team})
+		ctx.Next()
+	}
+}
+
+// handleCreateKey handles the POST /admin/keys endpoint. The secret is only
+// returned in this response.
+func handleCreateKey(manager *keys.Manager) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req struct {
+			Purpose string   `json:"purpose"`
+			Role    string   `json:"role"`
+			UserID  string   `json:"user_id"`
+			Team    []string `json:"team"`
+			Tenant  string   `json:"tenant"`
+		}
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request payload"})
+			return
+		}
+
+		key, err := manager.Create(req.Purpose, req.Role)
+		if err == nil && (req.UserID != "" || len(req.Team) > 0) {
+			key, err = manager.SetOwner(key.ID, req.UserID, req.Team)
+		}
+		if err == nil && req.Tenant != "" {
+			key, err = manager.SetTenant(key.ID, req.Tenant)
+		}
+		if err != nil {
+			if err == keys.ErrInvalidPurpose {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create key"})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, key)
+	}
+}
+
+// handleListKeys handles the GET /admin/keys endpoint.
+func handleListKeys(manager *keys.Manager) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"results": manager.List(ctx.Query("purpose"))})
+	}
+}
+
+// handleRetireKey handles the POST /admin/keys/:kid/retire endpoint. With
+// ?immediate=true the key stops being accepted right away.
+func handleRetireKey(manager *keys.Manager) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key, err := manager.Retire(ctx.Param("kid"), ctx.Query("immediate") == "true")
+		if err != nil {
+			if err == keys.ErrKeyNotFound {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retire key"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, key)
+	}
+}
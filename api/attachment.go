@@ -0,0 +1,104 @@
+package api
+
+import (
+	"api_sales/internal/sales"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// multipartOverhead deja lugar a los encabezados del formulario además del archivo.
+const multipartOverhead = 64 << 10
+
+// handleAddAttachment handles the POST /sales/:id/attachments endpoint,
+// uploading the multipart field "file". The file is scanned before it is
+// stored; infected files are quarantined and reported with 422.
+func (h *salesHandler) handleAddAttachment(ctx *gin.Context) {
+	maxBytes := h.salesService.AttachmentMaxBytes()
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, int64(maxBytes)+multipartOverhead)
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		h.logger.Warn("failed to read attachment upload", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "expected a multipart file field named file"})
+		return
+	}
+	if header.Size > int64(maxBytes) {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": sales.ErrAttachmentSize.Error(), "max_bytes": maxBytes})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		h.logger.Error("failed to open attachment upload", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to add attachment"})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		h.logger.Error("failed to read attachment upload", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to add attachment"})
+		return
+	}
+
+	contentType := header.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	attachment, err := h.salesService.AddAttachment(ctx.Request.Context(), ctx.Param("id"), header.Filename, contentType, data, operatorID(ctx))
+	if err != nil {
+		h.attachmentError(ctx, err, "failed to add attachment")
+		return
+	}
+	if attachment.ScanStatus == sales.ScanQuarantined {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": sales.ErrAttachmentQuarantined.Error(), "attachment": attachment})
+		return
+	}
+	ctx.JSON(http.StatusCreated, attachment)
+}
+
+// handleGetAttachments handles the GET /sales/:id/attachments endpoint,
+// listing quarantined attachments too.
+func (h *salesHandler) handleGetAttachments(ctx *gin.Context) {
+	attachments, err := h.salesService.GetSaleAttachments(ctx.Param("id"))
+	if err != nil {
+		h.attachmentError(ctx, err, "failed to get attachments")
+		return
+	}
+	ctx.Header("Cache-Control", "private, no-store")
+	ctx.JSON(http.StatusOK, gin.H{"results": attachments})
+}
+
+// handleGetAttachment handles the GET /sales/:id/attachments/:attachment_id
+// endpoint, downloading the file. Quarantined files are refused.
+func (h *salesHandler) handleGetAttachment(ctx *gin.Context) {
+	attachment, data, err := h.salesService.GetAttachmentFile(ctx.Request.Context(), ctx.Param("id"), ctx.Param("attachment_id"))
+	if err != nil {
+		h.attachmentError(ctx, err, "failed to get attachment")
+		return
+	}
+
+	ctx.Header("Cache-Control", "private, no-store")
+	ctx.Header("ETag", `"`+attachment.SHA256+`"`)
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	ctx.Data(http.StatusOK, attachment.ContentType, data)
+}
+
+func (h *salesHandler) attachmentError(ctx *gin.Context, err error, msg string) {
+	switch err {
+	case sales.ErrNotFound:
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "sale not found"})
+	case sales.ErrAttachmentNotFound:
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case sales.ErrAttachmentSize:
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error(), "max_bytes": h.salesService.AttachmentMaxBytes()})
+	case sales.ErrAttachmentQuarantined:
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error(msg, zap.Error(err), zap.String("sale_id", ctx.Param("id")))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+	}
+}
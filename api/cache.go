@@ -0,0 +1,156 @@
+package api
+
+import (
+	"api_sales/internal/sales"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxCachedResponses limita la memoria usada por el cache de respuestas.
+const maxCachedResponses = 1000
+
+type cachedResponse struct {
+	status      int
+	contentType string
+	body        []byte
+	validators  http.Header
+	expiresAt   time.Time
+}
+
+// validatorHeaders son las cabeceras de caché HTTP que se reproducen en un HIT.
+var validatorHeaders = []string{"Cache-Control", "ETag", "Last-Modified"}
+
+// responseCache keeps GET responses keyed by route and normalized query. It
+// implements sales.Notifier so every sale write drops all cached entries.
+type responseCache struct {
+	mu      sync.RWMutex
+	entries map[string]cachedResponse
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{
+		entries: map[string]cachedResponse{},
+	}
+}
+
+// Notify invalidates the cache on any sale event.
+func (c *responseCache) Notify(string, *sales.Sale) {
+	c.invalidate()
+}
+
+func (c *responseCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[string]cachedResponse{}
+}
+
+func (c *responseCache) get(key string) (cachedResponse, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	r, ok := c.entries[key]
+	if !ok || time.Now().After(r.expiresAt) {
+		return cachedResponse{}, false
+	}
+	return r, true
+}
+
+func (c *responseCache) set(key string, r cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= maxCachedResponses {
+		now := time.Now()
+		for k, e := range c.entries {
+			if now.After(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		// Sin entradas vencidas se descarta una cualquiera
+		for k := range c.entries {
+			if len(c.entries) < maxCachedResponses {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = r
+}
+
+// middleware caches successful responses of the route for ttl. A zero ttl
+// disables caching for the route.
+func (c *responseCache) middleware(ttl time.Duration) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// Las lecturas fuertes nunca se sirven desde cache
+		if ttl <= 0 || ctx.Query("consistency") == string(sales.ConsistencyStrong) {
+			ctx.Next()
+			return
+		}
+
+		// El rol y el usuario forman parte de la clave porque la respuesta depende de ellos
+		scope := callerRole(ctx)
+		if c := caller(ctx); c != nil {
+			scope += ":" + c.UserID + ":" + strings.Join(c.Team, ",")
+		}
+		key := scope + " " + ctx.FullPath() + "?" + normalizeQuery(ctx.Request.URL.Query())
+		// La búsqueda en streaming es otra representación de la misma ruta
+		if wantsNDJSON(ctx.Request) {
+			key = "ndjson " + key
+		}
+		if r, ok := c.get(key); ok {
+			ctx.Header("X-Cache", "HIT")
+			for name, values := range r.validators {
+				ctx.Header(name, values[0])
+			}
+			lastModified, _ := http.ParseTime(r.validators.Get("Last-Modified"))
+			if etag := r.validators.Get("ETag"); etag != "" && notModified(ctx.Request, etag, lastModified) {
+				ctx.AbortWithStatus(http.StatusNotModified)
+				return
+			}
+			ctx.Data(r.status, r.contentType, r.body)
+			ctx.Abort()
+			return
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = recorder
+		ctx.Header("X-Cache", "MISS")
+		ctx.Next()
+
+		if recorder.Status() == http.StatusOK {
+			validators := http.Header{}
+			for _, name := range validatorHeaders {
+				if v := recorder.Header().Get(name); v != "" {
+					validators.Set(name, v)
+				}
+			}
+			c.set(key, cachedResponse{
+				status:      recorder.Status(),
+				contentType: recorder.Header().Get("Content-Type"),
+				body:        recorder.body.Bytes(),
+				validators:  validators,
+				expiresAt:   time.Now().Add(ttl),
+			})
+		}
+	}
+}
+
+// normalizeQuery ordena claves y valores y descarta parámetros vacíos, para que
+// filtros equivalentes compartan la misma entrada.
+func normalizeQuery(q url.Values) string {
+	normalized := url.Values{}
+	for k, values := range q {
+		for _, v := range values {
+			if v = strings.TrimSpace(v); v != "" {
+				normalized.Add(k, v)
+			}
+		}
+		sort.Strings(normalized[k])
+	}
+	return normalized.Encode()
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"api_sales/internal/chaos"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// injectFaults adds latency and fails requests at the injector's rates. An
+// error fault answers 503 without running the handler; a partial fault runs
+// it and then discards the response with a 504, as when a reply is lost after
+// the write committed.
+func injectFaults(injector *chaos.Injector) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		switch injector.Inject() {
+		case chaos.FaultError:
+			ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": chaos.ErrInjected.Error()})
+		case chaos.FaultPartial:
+			real := ctx.Writer
+			ctx.Writer = &discardWriter{ResponseWriter: real}
+			ctx.Next()
+			ctx.Writer = real
+			ctx.AbortWithStatusJSON(http.StatusGatewayTimeout, gin.H{"error": chaos.ErrInjected.Error()})
+		default:
+			ctx.Next()
+		}
+	}
+}
+
+// discardWriter descarta la respuesta del handler sin escribirla.
+type discardWriter struct {
+	gin.ResponseWriter
+	header http.Header
+}
+
+func (w *discardWriter) WriteHeader(code int)              {}
+func (w *discardWriter) WriteHeaderNow()                   {}
+func (w *discardWriter) Write(b []byte) (int, error)       { return len(b), nil }
+func (w *discardWriter) WriteString(s string) (int, error) { return len(s), nil }
+func (w *discardWriter) Written() bool                     { return false }
+func (w *discardWriter) Header() http.Header {
+	if w.header == nil {
+		w.header = http.Header{}
+	}
+	return w.header
+}
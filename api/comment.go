@@ -0,0 +1,88 @@
+package api
+
+import (
+	"api_sales/internal/sales"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type commentRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// commentError responde los errores comunes a los endpoints de comentarios.
+func (h *salesHandler) commentError(ctx *gin.Context, err error, msg string) {
+	switch err {
+	case sales.ErrNotFound:
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "sale not found"})
+	case sales.ErrCommentNotFound:
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case sales.ErrInvalidComment:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case sales.ErrNotCommentAuthor:
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error(msg, zap.Error(err), zap.String("sale_id", ctx.Param("id")))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+	}
+}
+
+// handleAddComment handles the POST /sales/:id/comments endpoint. The author
+// is the operator of the request.
+func (h *salesHandler) handleAddComment(ctx *gin.Context) {
+	var req commentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("failed to bind JSON request", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request payload"})
+		return
+	}
+
+	comment, err := h.salesService.AddComment(ctx.Param("id"), operatorID(ctx), req.Body)
+	if err != nil {
+		h.commentError(ctx, err, "failed to add comment")
+		return
+	}
+	ctx.JSON(http.StatusCreated, comment)
+}
+
+// handleGetComments handles the GET /sales/:id/comments endpoint.
+func (h *salesHandler) handleGetComments(ctx *gin.Context) {
+	comments, err := h.salesService.GetSaleComments(ctx.Param("id"))
+	if err != nil {
+		h.commentError(ctx, err, "failed to get comments")
+		return
+	}
+	ctx.Header("Cache-Control", "private, no-store")
+	ctx.JSON(http.StatusOK, gin.H{"results": comments})
+}
+
+// handleUpdateComment handles the PATCH /sales/:id/comments/:comment_id
+// endpoint. Only the author can edit a comment.
+func (h *salesHandler) handleUpdateComment(ctx *gin.Context) {
+	var req commentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("failed to bind JSON request", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request payload"})
+		return
+	}
+
+	comment, err := h.salesService.UpdateComment(ctx.Param("id"), ctx.Param("comment_id"), operatorID(ctx), req.Body)
+	if err != nil {
+		h.commentError(ctx, err, "failed to update comment")
+		return
+	}
+	ctx.JSON(http.StatusOK, comment)
+}
+
+// handleDeleteComment handles the DELETE /sales/:id/comments/:comment_id
+// endpoint. Admins can delete any comment.
+func (h *salesHandler) handleDeleteComment(ctx *gin.Context) {
+	err := h.salesService.DeleteComment(ctx.Param("id"), ctx.Param("comment_id"), operatorID(ctx), callerRole(ctx) == adminRole)
+	if err != nil {
+		h.commentError(ctx, err, "failed to delete comment")
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var groupInFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "sales_route_group_in_flight",
+	Help: "Requests being processed per route group.",
+}, []string{"group"})
+
+var groupQueued = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "sales_route_group_queued",
+	Help: "Requests waiting for a slot per route group.",
+}, []string{"group"})
+
+var groupRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "sales_route_group_rejected_total",
+	Help: "Requests rejected because their route group was at its limit, by reason (queue_full or timeout).",
+}, []string{"group", "reason"})
+
+// routeGroupLimit bounds the requests of a route group: limit processed at
+// once and up to queue more waiting for a slot.
+type routeGroupLimit struct {
+	name  string
+	slots chan struct{}
+	queue chan struct{}
+}
+
+// concurrencyLimiter maps route prefixes to their group, with "*" as the
+// default for the rest of the routes.
+type concurrencyLimiter struct {
+	groups map[string]*routeGroupLimit
+	wait   time.Duration
+	logger *zap.Logger
+}
+
+func newConcurrencyLimiter(limits map[string]int, queue int, wait time.Duration, logger *zap.Logger) *concurrencyLimiter {
+	l := &concurrencyLimiter{groups: make(map[string]*routeGroupLimit), wait: wait, logger: logger}
+	for prefix, limit := range limits {
+		if limit > 0 {
+			l.groups[prefix] = &routeGroupLimit{name: prefix, slots: make(chan struct{}, limit), queue: make(chan struct{}, queue)}
+		}
+	}
+	return l
+}
+
+// group retorna el grupo del prefijo más largo que contiene la ruta.
+func (l *concurrencyLimiter) group(route string) *routeGroupLimit {
+	var best *routeGroupLimit
+	for prefix, group := range l.groups {
+		if route != prefix && !strings.HasPrefix(route, strings.TrimSuffix(prefix, "/")+"/") {
+			continue
+		}
+		if best == nil || len(prefix) > len(best.name) {
+			best = group
+		}
+	}
+	if best == nil {
+		return l.groups["*"]
+	}
+	return best
+}
+
+// acquire toma un lugar del grupo. Sin lugares libres la request espera en la
+// cola hasta wait; con la cola llena se rechaza de inmediato.
+func (l *concurrencyLimiter) acquire(ctx *gin.Context, group *routeGroupLimit) (bool, string) {
+	select {
+	case group.slots <- struct{}{}:
+		return true, ""
+	default:
+	}
+	select {
+	case group.queue <- struct{}{}:
+	default:
+		return false, "queue_full"
+	}
+	queued := groupQueued.WithLabelValues(group.name)
+	queued.Inc()
+	defer func() {
+		<-group.queue
+		queued.Dec()
+	}()
+
+	timer := time.NewTimer(l.wait)
+	defer timer.Stop()
+	select {
+	case group.slots <- struct{}{}:
+		return true, ""
+	case <-timer.C:
+		return false, "timeout"
+	case <-ctx.Request.Context().Done():
+		return false, "timeout"
+	}
+}
+
+// limitConcurrency caps the in-flight requests of each route group so a burst
+// of clients can't pile onto the in-memory storage: requests past the limit
+// queue briefly and are rejected with 503 once the queue is full or their
+// wait runs out.
+func limitConcurrency(l *concurrencyLimiter) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		group := l.group(ctx.FullPath())
+		if group == nil || ctx.FullPath() == "" {
+			ctx.Next()
+			return
+		}
+
+		ok, reason := l.acquire(ctx, group)
+		if !ok {
+			groupRejected.WithLabelValues(group.name, reason).Inc()
+			l.logger.Debug("route group at its concurrency limit", zap.String("group", group.name), zap.String("reason", reason))
+			ctx.Header("Retry-After", "1")
+			ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "too many concurrent requests, try again later"})
+			return
+		}
+		inFlight := groupInFlight.WithLabelValues(group.name)
+		inFlight.Inc()
+		defer func() {
+			inFlight.Dec()
+			<-group.slots
+		}()
+		ctx.Next()
+	}
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"api_sales/internal/sales"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setValidators writes Cache-Control, ETag and Last-Modified and answers 304
+// when the client's If-None-Match / If-Modified-Since still match. It returns
+// true when the response has already been written.
+func setValidators(ctx *gin.Context, maxAge time.Duration, etag string, lastModified time.Time) bool {
+	if maxAge > 0 {
+		// Las respuestas autenticadas dependen del rol o del usuario: no van a
+		// caches compartidos
+		visibility := "public"
+		if caller(ctx) != nil || ctx.GetHeader(apiKeyHeader) != "" || ctx.GetHeader("Authorization") != "" {
+			visibility = "private"
+		}
+		ctx.Header("Cache-Control", visibility+", max-age="+strconv.Itoa(int(maxAge.Seconds())))
+	} else {
+		ctx.Header("Cache-Control", "no-cache")
+	}
+	ctx.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		ctx.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(ctx.Request, etag, lastModified) {
+		ctx.Status(http.StatusNotModified)
+		ctx.Writer.WriteHeaderNow()
+		return true
+	}
+	return false
+}
+
+// notModified aplica las reglas de RFC 9110: If-None-Match tiene prioridad
+// sobre If-Modified-Since.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(ims)
+		if err == nil && !lastModified.Truncate(time.Second).After(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// salesETag deriva un validador débil de los IDs y versiones del resultado,
+// independiente del orden en que se devuelven.
+func salesETag(results []*sales.Sale) string {
+	keys := make([]string, 0, len(results))
+	for _, s := range results {
+		keys = append(keys, s.ID+":"+strconv.Itoa(s.Version))
+	}
+	sort.Strings(keys)
+	return weakETag(strings.Join(keys, ","))
+}
+
+// statsETag deriva un validador débil de los metadatos agregados.
+func statsETag(m sales.SalesMetadata) string {
+	return weakETag(fmt.Sprintf("%+v|%d", m, m.LastModified().UnixNano()))
+}
+
+func weakETag(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
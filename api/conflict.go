@@ -0,0 +1,20 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// conflict answers 409 with message and the current state of the sale, so the
+// client can retry or give up without another GET. If the sale can't be read
+// the body carries only the error.
+func (h *salesHandler) conflict(ctx *gin.Context, saleID, message string) {
+	body := gin.H{"error": message}
+	if state, err := h.salesService.CurrentState(saleID); err == nil {
+		body["current_status"] = state.Status
+		body["current_fulfillment_status"] = state.FulfillmentStatus
+		body["version"] = state.Version
+	}
+	ctx.JSON(http.StatusConflict, body)
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"api_sales/internal/sales"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// handleOpenDispute handles the POST /sales/:id/disputes endpoint.
+func (h *salesHandler) handleOpenDispute(ctx *gin.Context) {
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("failed to bind JSON request", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request payload"})
+		return
+	}
+
+	dispute, err := h.salesService.OpenDispute(ctx.Param("id"), req.Reason, actor(ctx))
+	if err != nil {
+		h.logger.Warn("failed to open dispute", zap.Error(err), zap.String("sale_id", ctx.Param("id")))
+		switch err {
+		case sales.ErrNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "sale not found"})
+		case sales.ErrNotDisputable:
+			h.conflict(ctx, ctx.Param("id"), err.Error())
+		default:
+			if err.Error() == "dispute reason is required" {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open dispute"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dispute)
+}
+
+// handleListDisputes handles the GET /sales/:id/disputes endpoint.
+func (h *salesHandler) handleListDisputes(ctx *gin.Context) {
+	disputes, err := h.salesService.GetSaleDisputes(ctx.Param("id"))
+	if err != nil {
+		if err == sales.ErrNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "sale not found"})
+			return
+		}
+		h.logger.Error("failed to list disputes", zap.Error(err), zap.String("sale_id", ctx.Param("id")))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list disputes"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"disputes": disputes})
+}
+
+// handleResolveDispute handles the POST /disputes/:id/resolve endpoint. The
+// body's outcome is won or lost.
+func (h *salesHandler) handleResolveDispute(ctx *gin.Context) {
+	var req struct {
+		Outcome string `json:"outcome"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("failed to bind JSON request", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request payload"})
+		return
+	}
+
+	dispute, err := h.salesService.ResolveDispute(ctx.Param("id"), req.Outcome, actor(ctx))
+	if err != nil {
+		h.logger.Warn("failed to resolve dispute", zap.Error(err), zap.String("dispute_id", ctx.Param("id")))
+		switch err {
+		case sales.ErrDisputeNotFound, sales.ErrNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case sales.ErrInvalidOutcome:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case sales.ErrDisputeResolved:
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve dispute"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dispute)
+}
@@ -0,0 +1,312 @@
+package api
+
+import (
+	"api_sales/internal/sales"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// saleDTO is the wire format of a sale. It is mapped field by field from
+// sales.Sale so the domain model can change without changing responses, and
+// internal fields such as the tenant never reach clients. Version stays:
+// clients send it back to resolve conflicts.
+type saleDTO struct {
+	ID                string            `json:"id"`
+	UserID            string            `json:"user_id"`
+	Amount            float64           `json:"amount"`
+	Status            string            `json:"status"`
+	LineItems         []lineItemDTO     `json:"line_items,omitempty"`
+	Subtotal          float64           `json:"subtotal,omitempty"`
+	DiscountPercent   float64           `json:"discount_percent,omitempty"`
+	Discount          float64           `json:"discount,omitempty"`
+	TaxPercent        float64           `json:"tax_percent,omitempty"`
+	Tax               float64           `json:"tax,omitempty"`
+	RecurringSaleID   string            `json:"recurring_sale_id,omitempty"`
+	ERPPosting        string            `json:"erp_posting_status,omitempty"`
+	FulfillmentStatus string            `json:"fulfillment_status,omitempty"`
+	Carrier           string            `json:"carrier,omitempty"`
+	TrackingNumber    string            `json:"tracking_number,omitempty"`
+	ShippedAt         *time.Time        `json:"shipped_at,omitempty"`
+	DeliveredAt       *time.Time        `json:"delivered_at,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+	Version           int               `json:"version"`
+	PendingSince      *time.Time        `json:"pending_since,omitempty"`
+	DecidedAt         *time.Time        `json:"decided_at,omitempty"`
+	PendingAge        string            `json:"pending_age,omitempty"`
+	SLABreached       bool              `json:"sla_breached,omitempty"`
+}
+
+// lineItemDTO is the wire format of a line item, in requests and responses.
+type lineItemDTO struct {
+	SKU         string  `json:"sku"`
+	Description string  `json:"description,omitempty"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+}
+
+func newSaleDTO(sale *sales.Sale) saleDTO {
+	return saleDTO{
+		ID:                sale.ID,
+		UserID:            sale.UserID,
+		Amount:            sale.Amount,
+		Status:            sale.Status,
+		LineItems:         newLineItemDTOs(sale.LineItems),
+		Subtotal:          sale.Subtotal,
+		DiscountPercent:   sale.DiscountPercent,
+		Discount:          sale.Discount,
+		TaxPercent:        sale.TaxPercent,
+		Tax:               sale.Tax,
+		RecurringSaleID:   sale.RecurringSaleID,
+		ERPPosting:        sale.ERPPosting,
+		FulfillmentStatus: sale.FulfillmentStatus,
+		Carrier:           sale.Carrier,
+		TrackingNumber:    sale.TrackingNumber,
+		ShippedAt:         sale.ShippedAt,
+		DeliveredAt:       sale.DeliveredAt,
+		Metadata:          sale.Metadata,
+		CreatedAt:         sale.CreatedAt,
+		UpdatedAt:         sale.UpdatedAt,
+		Version:           sale.Version,
+		PendingSince:      sale.PendingSince,
+		DecidedAt:         sale.DecidedAt,
+		PendingAge:        sale.PendingAge,
+		SLABreached:       sale.SLABreached,
+	}
+}
+
+func newLineItemDTOs(items []sales.LineItem) []lineItemDTO {
+	if items == nil {
+		return nil
+	}
+	result := make([]lineItemDTO, len(items))
+	for i, item := range items {
+		result[i] = lineItemDTO{SKU: item.SKU, Description: item.Description, Quantity: item.Quantity, UnitPrice: item.UnitPrice}
+	}
+	return result
+}
+
+func (item lineItemDTO) toDomain() sales.LineItem {
+	return sales.LineItem{SKU: item.SKU, Description: item.Description, Quantity: item.Quantity, UnitPrice: item.UnitPrice}
+}
+
+// salePageDTO is a page of GET /me/sales.
+type salePageDTO struct {
+	Results    []saleDTO `json:"results"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+// auditEntryDTO is an entry of the audit trail of a sale, with its snapshots
+// in the sale wire format.
+type auditEntryDTO struct {
+	ID         string    `json:"id"`
+	SaleID     string    `json:"sale_id"`
+	Action     string    `json:"action"`
+	Version    int       `json:"version"`
+	Before     *saleDTO  `json:"before,omitempty"`
+	After      *saleDTO  `json:"after,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	Actor      string    `json:"actor,omitempty"`
+	OnBehalfOf string    `json:"on_behalf_of,omitempty"`
+	PrevHash   string    `json:"prev_hash"`
+	Hash       string    `json:"hash"`
+}
+
+// assignmentDTO is the wire format of a reviewer's claim on a sale.
+type assignmentDTO struct {
+	SaleID       string    `json:"sale_id"`
+	Reviewer     string    `json:"reviewer"`
+	AutoAssigned bool      `json:"auto_assigned"`
+	ClaimedAt    time.Time `json:"claimed_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+func newAssignmentDTO(a *sales.Assignment) *assignmentDTO {
+	if a == nil {
+		return nil
+	}
+	return &assignmentDTO{SaleID: a.SaleID, Reviewer: a.Reviewer, AutoAssigned: a.AutoAssigned, ClaimedAt: a.ClaimedAt, ExpiresAt: a.ExpiresAt}
+}
+
+// attachmentDTO is the wire format of a file attached to a sale.
+type attachmentDTO struct {
+	ID          string     `json:"id"`
+	SaleID      string     `json:"sale_id"`
+	Filename    string     `json:"filename"`
+	ContentType string     `json:"content_type"`
+	Size        int        `json:"size"`
+	SHA256      string     `json:"sha256"`
+	UploadedBy  string     `json:"uploaded_by,omitempty"`
+	UploadedAt  time.Time  `json:"uploaded_at"`
+	ScanStatus  string     `json:"scan_status"`
+	ScanResult  string     `json:"scan_result,omitempty"`
+	ScannedAt   *time.Time `json:"scanned_at,omitempty"`
+}
+
+func newAttachmentDTO(a *sales.Attachment) *attachmentDTO {
+	if a == nil {
+		return nil
+	}
+	return &attachmentDTO{
+		ID:          a.ID,
+		SaleID:      a.SaleID,
+		Filename:    a.Filename,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		SHA256:      a.SHA256,
+		UploadedBy:  a.UploadedBy,
+		UploadedAt:  a.UploadedAt,
+		ScanStatus:  a.ScanStatus,
+		ScanResult:  a.ScanResult,
+		ScannedAt:   a.ScannedAt,
+	}
+}
+
+// reviewItemDTO is an entry of a reviewer's queue.
+type reviewItemDTO struct {
+	Assignment *assignmentDTO `json:"assignment"`
+	Sale       saleDTO        `json:"sale"`
+}
+
+// receiptFieldsDTO holds what OCR read from a receipt.
+type receiptFieldsDTO struct {
+	Amount *float64 `json:"amount,omitempty"`
+	Date   string   `json:"date,omitempty"`
+	Text   string   `json:"text"`
+}
+
+// receiptDraftDTO is the response of POST /sales/from-receipt.
+type receiptDraftDTO struct {
+	Sale       saleDTO          `json:"sale"`
+	Extracted  receiptFieldsDTO `json:"extracted"`
+	Attachment *attachmentDTO   `json:"attachment,omitempty"`
+}
+
+// saleMapper maps the sales of a response to their wire format for the
+// caller: only admins see sensitive metadata in clear. Every sale a handler
+// responds goes through it.
+type saleMapper struct {
+	redact func(*sales.Sale) *sales.Sale
+}
+
+// sales retorna el mapper del llamador de la petición.
+func (h *salesHandler) sales(ctx *gin.Context) saleMapper {
+	return mapperFor(ctx, h.salesService)
+}
+
+func (h *recurringHandler) sales(ctx *gin.Context) saleMapper {
+	return mapperFor(ctx, h.salesService)
+}
+
+func mapperFor(ctx *gin.Context, svc SalesService) saleMapper {
+	if callerRole(ctx) == adminRole {
+		return saleMapper{}
+	}
+	return saleMapper{redact: svc.RedactSensitive}
+}
+
+// visible retorna la venta tal como la puede ver el llamador.
+func (m saleMapper) visible(sale *sales.Sale) *sales.Sale {
+	if m.redact == nil || sale == nil {
+		return sale
+	}
+	return m.redact(sale)
+}
+
+func (m saleMapper) dto(sale *sales.Sale) saleDTO {
+	return newSaleDTO(m.visible(sale))
+}
+
+// dtos mapea una lista; nunca retorna nil para que se serialice [].
+func (m saleMapper) dtos(list []*sales.Sale) []saleDTO {
+	result := make([]saleDTO, len(list))
+	for i, sale := range list {
+		result[i] = m.dto(sale)
+	}
+	return result
+}
+
+// snapshot mapea un snapshot de auditoría, que puede faltar.
+func (m saleMapper) snapshot(sale *sales.Sale) *saleDTO {
+	if sale == nil {
+		return nil
+	}
+	dto := m.dto(sale)
+	return &dto
+}
+
+func (m saleMapper) auditEntries(entries []*sales.AuditEntry) []auditEntryDTO {
+	result := make([]auditEntryDTO, len(entries))
+	for i, e := range entries {
+		result[i] = auditEntryDTO{
+			ID:         e.ID,
+			SaleID:     e.SaleID,
+			Action:     e.Action,
+			Version:    e.Version,
+			Before:     m.snapshot(e.Before),
+			After:      m.snapshot(e.After),
+			CreatedAt:  e.CreatedAt,
+			Actor:      e.Actor,
+			OnBehalfOf: e.OnBehalfOf,
+			PrevHash:   e.PrevHash,
+			Hash:       e.Hash,
+		}
+	}
+	return result
+}
+
+func (m saleMapper) page(page sales.Page) salePageDTO {
+	return salePageDTO{Results: m.dtos(page.Results), NextCursor: page.NextCursor}
+}
+
+func (m saleMapper) reviewItems(queue []sales.ReviewItem) []reviewItemDTO {
+	result := make([]reviewItemDTO, len(queue))
+	for i, item := range queue {
+		result[i] = reviewItemDTO{Assignment: newAssignmentDTO(item.Assignment), Sale: m.dto(item.Sale)}
+	}
+	return result
+}
+
+func (m saleMapper) receiptDraft(draft *sales.ReceiptDraft) receiptDraftDTO {
+	extracted := receiptFieldsDTO{Amount: draft.Extracted.Amount, Date: draft.Extracted.Date, Text: draft.Extracted.Text}
+	return receiptDraftDTO{Sale: m.dto(draft.Sale), Extracted: extracted, Attachment: newAttachmentDTO(draft.Attachment)}
+}
+
+// createSaleRequest is the body of POST /sales.
+type createSaleRequest struct {
+	UserID   string            `json:"user_id"`
+	Amount   float64           `json:"amount"`
+	Status   string            `json:"status"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// patchSaleRequest is the body of PATCH /sales/:id: either a status change
+// or an edit of the sale fields.
+type patchSaleRequest struct {
+	Status          string         `json:"status"`
+	UserID          *string        `json:"user_id"`
+	Amount          *float64       `json:"amount"`
+	LineItems       *[]lineItemDTO `json:"line_items"`
+	DiscountPercent *float64       `json:"discount_percent"`
+	TaxPercent      *float64       `json:"tax_percent"`
+}
+
+// edit retorna los campos a editar; vacío si la petición cambia el estado.
+func (r patchSaleRequest) edit() sales.SaleEdit {
+	edit := sales.SaleEdit{
+		UserID:          r.UserID,
+		Amount:          r.Amount,
+		DiscountPercent: r.DiscountPercent,
+		TaxPercent:      r.TaxPercent,
+	}
+	if r.LineItems != nil {
+		items := make([]sales.LineItem, len(*r.LineItems))
+		for i, item := range *r.LineItems {
+			items[i] = item.toDomain()
+		}
+		edit.LineItems = &items
+	}
+	return edit
+}
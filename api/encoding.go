@@ -0,0 +1,117 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ugorji/go/codec"
+	"go.uber.org/zap"
+)
+
+// msgpackHandle usa la especificación actual (str8 y bin), que es la que
+// esperan los clientes de otros lenguajes.
+var msgpackHandle = &codec.MsgpackHandle{WriteExt: true}
+
+// msgpackMediaTypes are the Accept values that select MessagePack.
+var msgpackMediaTypes = []string{"application/msgpack", "application/x-msgpack", "application/vnd.msgpack"}
+
+// wantsMsgPack reports whether the client asked for MessagePack.
+func wantsMsgPack(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	for _, mt := range msgpackMediaTypes {
+		if strings.Contains(accept, mt) {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeResponses re-encodes the JSON responses of every endpoint as
+// MessagePack for clients that ask for it. Other responses (NDJSON and CSV
+// exports, empty bodies) are streamed unchanged.
+func encodeResponses(logger *zap.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Header("Vary", "Accept")
+		if !wantsMsgPack(ctx.Request) {
+			ctx.Next()
+			return
+		}
+
+		writer := &jsonCapture{ResponseWriter: ctx.Writer}
+		ctx.Writer = writer
+		ctx.Next()
+		ctx.Writer = writer.ResponseWriter
+		if !writer.capturing {
+			return
+		}
+
+		body, err := decodeJSON(writer.body.Bytes())
+		if err != nil {
+			// Sin poder convertir se responde el JSON original
+			logger.Warn("failed to re-encode response as msgpack", zap.Error(err), zap.String("path", ctx.FullPath()))
+			ctx.Writer.Write(writer.body.Bytes())
+			return
+		}
+		ctx.Writer.Header().Del("Content-Length")
+		ctx.Writer.Header().Set("Content-Type", "application/msgpack")
+		if err := codec.NewEncoder(ctx.Writer, msgpackHandle).Encode(body); err != nil {
+			logger.Warn("failed to write msgpack response", zap.Error(err))
+		}
+	}
+}
+
+// jsonCapture retiene el cuerpo cuando la respuesta es JSON y deja pasar el
+// resto tal cual.
+type jsonCapture struct {
+	gin.ResponseWriter
+	decided   bool
+	capturing bool
+	body      bytes.Buffer
+}
+
+func (w *jsonCapture) Write(b []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		ct := w.Header().Get("Content-Type")
+		w.capturing = strings.HasPrefix(ct, "application/json") || strings.Contains(ct, "+json")
+	}
+	if w.capturing {
+		return w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// decodeJSON keeps integers as int64 so MessagePack encodes them as integers
+// rather than floats.
+func decodeJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return convertNumbers(v), nil
+}
+
+func convertNumbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for k, e := range v {
+			v[k] = convertNumbers(e)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = convertNumbers(e)
+		}
+	}
+	return v
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"api_sales/internal/sales"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// handleUpdateFulfillment handles the PATCH /sales/:id/fulfillment endpoint,
+// used by shipping integrations independently of the payment status.
+func (h *salesHandler) handleUpdateFulfillment(ctx *gin.Context) {
+	var req struct {
+		Status         string `json:"status"`
+		Carrier        string `json:"carrier"`
+		TrackingNumber string `json:"tracking_number"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("failed to bind JSON request", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request payload"})
+		return
+	}
+
+	sale, err := h.salesService.UpdateFulfillment(ctx.Param("id"), sales.FulfillmentUpdate{
+		Status:         req.Status,
+		Carrier:        req.Carrier,
+		TrackingNumber: req.TrackingNumber,
+	}, actor(ctx))
+	if err != nil {
+		h.logger.Warn("failed to update fulfillment", zap.Error(err), zap.String("sale_id", ctx.Param("id")))
+		switch err {
+		case sales.ErrNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "sale not found"})
+		case sales.ErrInvalidFulfillmentStatus:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case sales.ErrInvalidTransition:
+			h.conflict(ctx, ctx.Param("id"), "invalid fulfillment transition")
+		case sales.ErrNotFulfillable:
+			h.conflict(ctx, ctx.Param("id"), err.Error())
+		default:
+			if err.Error() == "invalid shipment details" {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update fulfillment"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, h.sales(ctx).dto(sale))
+}
@@ -1,39 +1,61 @@
 package api
 
 import (
+	"api_sales/internal/jsonenc"
 	"api_sales/internal/sales"
+	"errors"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
 type salesHandler struct {
-	salesService *sales.Service
+	salesService SalesService
 	logger       *zap.Logger
+	// cacheMaxAge es el max-age anunciado en Cache-Control de los GET
+	cacheMaxAge time.Duration
+	queryPolicy sales.QueryPolicy
+	// patchDedupWindow es cuánto tiempo un PATCH que repite el estado actual
+	// responde 200 en lugar de 409
+	patchDedupWindow time.Duration
+	// receiptMaxBytes es el tamaño máximo de un recibo para OCR
+	receiptMaxBytes int
 }
 
 // NewSalesHandler creates a new sales handler.
-func NewSalesHandler(salesService *sales.Service, logger *zap.Logger) *salesHandler {
+func NewSalesHandler(salesService SalesService, logger *zap.Logger) *salesHandler {
 	return &salesHandler{
 		salesService: salesService,
 		logger:       logger,
 	}
 }
 
-func (h *salesHandler) PatchSaleHandler(saleService *sales.Service) gin.HandlerFunc {
+func (h *salesHandler) PatchSaleHandler(saleService SalesService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		saleID := c.Param("id")
-		var req struct {
-			Status string `json:"status"`
-		}
+		var req patchSaleRequest
 
 		if err := c.ShouldBindJSON(&req); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
 			return
 		}
 
-		updated, err := saleService.UpdateSaleStatus(saleID, req.Status)
+		var updated *sales.Sale
+		var err error
+		edit := req.edit()
+		if edit != (sales.SaleEdit{}) {
+			// Edición de campos: el estado se cambia en otra petición
+			if req.Status != "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "status cannot be changed while editing sale fields"})
+				return
+			}
+			updated, err = saleService.EditSaleAs(saleID, edit, actor(c))
+		} else {
+			updated, err = saleService.UpdateSaleStatusAs(saleID, req.Status, actor(c))
+		}
 		if err != nil {
 			switch err {
 			case sales.ErrNotFound:
@@ -41,23 +63,108 @@ func (h *salesHandler) PatchSaleHandler(saleService *sales.Service) gin.HandlerF
 			case sales.ErrInvalidStatus:
 				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status value"})
 			case sales.ErrInvalidTransition:
-				c.JSON(http.StatusConflict, gin.H{"error": "invalid status transition"})
+				// Reintentos de la UI: el cambio ya aplicado devuelve el estado actual
+				if c.Query("strict") != "true" {
+					if current, ok := saleService.RepeatedStatusChange(saleID, req.Status, h.patchDedupWindow); ok {
+						c.Header("Idempotent-Replayed", "true")
+						c.JSON(http.StatusOK, h.sales(c).dto(current))
+						return
+					}
+				}
+				h.conflict(c, saleID, "invalid status transition")
+			case sales.ErrNotEditable, sales.ErrPeriodClosed:
+				h.conflict(c, saleID, err.Error())
+			case sales.ErrFieldNotEditable:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			default:
+				switch err.Error() {
+				case "amount must not be negative", "amount must be greater than zero", "invalid line item",
+					"discount_percent must be between 0 and 100", "tax_percent must be between 0 and 100":
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
 			}
 			return
 		}
 
-		c.JSON(http.StatusOK, updated)
+		if wantsLinks(c) {
+			h.writeSale(c, http.StatusOK, updated)
+			return
+		}
+		c.JSON(http.StatusOK, h.sales(c).dto(updated))
 	}
 }
 
+// saleResponse is a sale with the statuses the caller can move it to next
+// and, in the HAL representation, the links that perform them.
+type saleResponse struct {
+	saleDTO
+	AllowedTransitions []string        `json:"allowed_transitions"`
+	Links              map[string]link `json:"_links,omitempty"`
+}
+
+// handleGetSaleByID handles the GET /sales/:id endpoint.
+func (h *salesHandler) handleGetSaleByID(ctx *gin.Context) {
+	sale, err := h.salesService.GetSale(ctx.Param("id"), caller(ctx))
+	if err != nil {
+		if errors.Is(err, sales.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "sale not found"})
+			return
+		}
+		h.logger.Error("failed to get sale", zap.Error(err), zap.String("sale_id", ctx.Param("id")))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve sale"})
+		return
+	}
+	h.writeSale(ctx, http.StatusOK, sale)
+}
+
+// handleGetSaleByReference handles the GET /sales/by-reference/:reference
+// endpoint, which finds a sale by ID or tracking number.
+func (h *salesHandler) handleGetSaleByReference(ctx *gin.Context) {
+	sale, err := h.salesService.GetSaleByReference(ctx.Param("reference"), caller(ctx))
+	if err != nil {
+		if err == sales.ErrNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "sale not found"})
+			return
+		}
+		h.logger.Error("failed to look up sale by reference", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve sale"})
+		return
+	}
+	h.writeSale(ctx, http.StatusOK, sale)
+}
+
+// getSalesByID handles GET /sales?ids=a,b,c: the requested sales in one call,
+// with the IDs not found (or not visible to the caller) listed in missing.
+func (h *salesHandler) getSalesByID(ctx *gin.Context, ids []string) {
+	consistency, err := sales.ParseConsistency(ctx.Query("consistency"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for i := range ids {
+		ids[i] = strings.TrimSpace(ids[i])
+	}
+
+	found, missing, err := h.salesService.GetSales(ids, caller(ctx), consistency)
+	if err != nil {
+		if err == sales.ErrTooManyIDs {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to get sales by id", zap.Error(err), zap.Int("ids", len(ids)))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve sales"})
+		return
+	}
+
+	found = h.salesService.WithSLAStatus(found, time.Now())
+	ctx.JSON(http.StatusOK, gin.H{"results": h.sales(ctx).dtos(found), "missing": missing})
+}
+
 // handleCreateSale handles the POST /sales endpoint.
 func (h *salesHandler) handleCreateSale(ctx *gin.Context) {
-	var req struct {
-		UserID string  `json:"user_id"`
-		Amount float64 `json:"amount"`
-	}
+	var req createSaleRequest
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		h.logger.Warn("failed to bind JSON request", zap.Error(err))
@@ -65,10 +172,26 @@ func (h *salesHandler) handleCreateSale(ctx *gin.Context) {
 		return
 	}
 
-	sale, err := h.salesService.CreateSale(req.UserID, req.Amount)
+	var sale *sales.Sale
+	var err error
+	origin := sales.Origin{ClientIP: ctx.ClientIP(), UserAgent: ctx.Request.UserAgent(), TenantID: tenantID(ctx)}
+	switch req.Status {
+	case "":
+		sale, err = h.salesService.CreateSaleWithOrigin(req.UserID, req.Amount, req.Metadata, origin)
+	case sales.StatusDraft:
+		sale, err = h.salesService.CreateDraftSaleWithOrigin(req.UserID, req.Amount, req.Metadata, origin)
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid status value"})
+		return
+	}
 	if err != nil {
 		h.logger.Error("failed to create sale", zap.Error(err), zap.String("user_id", req.UserID), zap.Float64("amount", req.Amount))
-		if err.Error() == "amount must be greater than zero" || err.Error() == "user not found" {
+		if errors.Is(err, sales.ErrEnrichmentFailed) {
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		switch err.Error() {
+		case "amount must be greater than zero", "user not found", "amount must not be negative", "invalid metadata entry", "too many metadata keys":
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
@@ -76,16 +199,264 @@ func (h *salesHandler) handleCreateSale(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, sale)
+	ctx.JSON(http.StatusCreated, h.sales(ctx).dto(sale))
+}
+
+// handleSubmitSale handles the POST /sales/:id/submit endpoint.
+func (h *salesHandler) handleSubmitSale(ctx *gin.Context) {
+	saleID := ctx.Param("id")
+
+	sale, err := h.salesService.SubmitSale(saleID)
+	if err != nil {
+		h.logger.Warn("failed to submit sale", zap.Error(err), zap.String("sale_id", saleID))
+		switch err {
+		case sales.ErrNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "sale not found"})
+		case sales.ErrNotDraft, sales.ErrPeriodClosed:
+			h.conflict(ctx, ctx.Param("id"), err.Error())
+		default:
+			if err.Error() == "amount must be greater than zero" || err.Error() == "user not found" {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to submit sale"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, h.sales(ctx).dto(sale))
+}
+
+// handleGetSaleAudit handles the GET /sales/:id/audit endpoint.
+func (h *salesHandler) handleGetSaleAudit(ctx *gin.Context) {
+	entries, err := h.salesService.GetSaleAudit(ctx.Param("id"), caller(ctx))
+	if err != nil {
+		if err == sales.ErrNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "sale not found"})
+			return
+		}
+		h.logger.Error("failed to get sale audit", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"results": h.sales(ctx).auditEntries(entries)})
+}
+
+// handleGetStats handles the GET /sales/stats endpoint.
+func (h *salesHandler) handleGetStats(ctx *gin.Context) {
+	stats, err := h.salesService.GetStats()
+	if err != nil {
+		h.logger.Error("failed to get sales stats", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get sales stats"})
+		return
+	}
+	if ctx.Query("tz") == "" {
+		if setValidators(ctx, h.cacheMaxAge, statsETag(stats), stats.LastModified()) {
+			return
+		}
+		ctx.JSON(http.StatusOK, stats)
+		return
+	}
+
+	// Con tz se agregan los totales por día de esa zona horaria
+	loc, err := tzQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	daily, err := h.salesService.DailyTotals(loc)
+	if err != nil {
+		h.logger.Error("failed to get daily totals", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get sales stats"})
+		return
+	}
+	if setValidators(ctx, h.cacheMaxAge, weakETag(statsETag(stats)+loc.String()), stats.LastModified()) {
+		return
+	}
+	ctx.JSON(http.StatusOK, dailyStats{SalesMetadata: stats, Timezone: loc.String(), Daily: daily})
+}
+
+// dailyStats is the stats response when a tz is requested.
+type dailyStats struct {
+	sales.SalesMetadata
+	Timezone string             `json:"timezone"`
+	Daily    []sales.DailyTotal `json:"daily"`
+}
+
+// handleGetCohorts handles the GET /sales/reports/cohorts endpoint. Cohort
+// months follow the optional tz parameter.
+func (h *salesHandler) handleGetCohorts(ctx *gin.Context) {
+	loc, err := tzQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	cohorts, err := h.salesService.Cohorts(loc)
+	if err != nil {
+		h.logger.Error("failed to get cohort report", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get cohort report"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"timezone": loc.String(), "cohorts": cohorts})
+}
+
+// handleCreateAdjustment handles the POST /sales/:id/adjustments endpoint.
+func (h *salesHandler) handleCreateAdjustment(ctx *gin.Context) {
+	var req struct {
+		Amount float64 `json:"amount"`
+		Reason string  `json:"reason"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("failed to bind JSON request", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request payload"})
+		return
+	}
+
+	adjustment, err := h.salesService.AdjustSale(ctx.Param("id"), req.Amount, req.Reason, operatorID(ctx))
+	if err != nil {
+		h.logger.Warn("failed to adjust sale", zap.Error(err), zap.String("sale_id", ctx.Param("id")))
+		switch err {
+		case sales.ErrNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "sale not found"})
+		case sales.ErrNotAdjustable, sales.ErrSaleDisputed:
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			switch err.Error() {
+			case "adjustment amount must not be zero", "adjustment reason is required", "adjustment would make the sale total negative":
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to adjust sale"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, adjustment)
+}
+
+// handleListAdjustments handles the GET /sales/:id/adjustments endpoint.
+func (h *salesHandler) handleListAdjustments(ctx *gin.Context) {
+	adjustments, err := h.salesService.GetSaleAdjustments(ctx.Param("id"))
+	if err != nil {
+		if err == sales.ErrNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "sale not found"})
+			return
+		}
+		h.logger.Error("failed to list adjustments", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"results": adjustments})
+}
+
+// handleExportAudit handles the GET /admin/audit/export endpoint, streaming
+// the whole audit log as NDJSON in append order. Entries go out exactly as
+// stored, so sales.VerifyAuditChain checks the export offline; with field
+// encryption the sensitive metadata stays encrypted. Only admins reach it.
+func (h *salesHandler) handleExportAudit(ctx *gin.Context) {
+	entries, err := h.salesService.ExportAudit()
+	if err != nil {
+		h.logger.Error("failed to export audit log", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to export audit log"})
+		return
+	}
+
+	ctx.Header("Content-Type", ndjsonMediaType)
+	enc := jsonenc.NewEncoder(ctx.Writer)
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			h.logger.Warn("failed to write audit entry", zap.Error(err))
+			return
+		}
+	}
+}
+
+// handleVerifyAudit handles the GET /admin/audit/verify endpoint.
+func (h *salesHandler) handleVerifyAudit(ctx *gin.Context) {
+	result, err := h.salesService.VerifyAudit()
+	if err != nil {
+		h.logger.Error("failed to verify audit log", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify audit log"})
+		return
+	}
+
+	status := http.StatusOK
+	if !result.Valid {
+		status = http.StatusConflict
+	}
+	ctx.JSON(status, result)
+}
+
+// handleGetUserSummary handles the GET /users/:id/sales/summary endpoint.
+func (h *salesHandler) handleGetUserSummary(ctx *gin.Context) {
+	summary, err := h.salesService.GetUserSummary(ctx.Param("id"))
+	if err != nil {
+		h.logger.Error("failed to get user sales summary", zap.Error(err), zap.String("user_id", ctx.Param("id")))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user sales summary"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, summary)
 }
 
 func (h *salesHandler) handlerGetSale(ctx *gin.Context) {
+	if ids := ctx.Query("ids"); ids != "" {
+		h.getSalesByID(ctx, strings.Split(ids, ","))
+		return
+	}
 
 	idUser := ctx.Query("user_id")
 	stateSale := ctx.Query("status")
 
+	filter := sales.SearchFilter{UserID: idUser, Status: stateSale, FulfillmentStatus: ctx.Query("fulfillment_status")}
+	switch filter.FulfillmentStatus {
+	case "", sales.FulfillmentPending, sales.FulfillmentShipped, sales.FulfillmentDelivered:
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid fulfillment_status value"})
+		return
+	}
+	var err error
+	if filter.CreatedFrom, err = timeQuery(ctx, "created_from", lowerBound); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.CreatedTo, err = timeQuery(ctx, "created_to", upperBound); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// Estado histórico para auditorías: as_of no puede estar en el futuro
+	if filter.AsOf, err = timeQuery(ctx, "as_of", lowerBound); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.AsOf != nil && filter.AsOf.After(time.Now()) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid as_of: must not be in the future"})
+		return
+	}
+	if raw := ctx.Query("exceeded_sla"); raw != "" {
+		if filter.ExceededSLA, err = time.ParseDuration(raw); err != nil || filter.ExceededSLA <= 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid exceeded_sla: expected a positive duration such as 4h"})
+			return
+		}
+	}
+	filter.Caller = caller(ctx)
+	if filter.Consistency, err = sales.ParseConsistency(ctx.Query("consistency")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Límites de costo según el rol del llamador
+	if err := h.queryPolicy.For(callerRole(ctx)).Check(filter, time.Now()); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "details": err})
+		return
+	}
+
 	// Llama al servicio para buscar y obtener los metadatos
-	salesResults, metadata, err := h.salesService.SearchSale(idUser, stateSale)
+	salesResults, metadata, err := h.salesService.SearchSale(filter)
 
 	if err != nil {
 		h.logger.Error("Error searching sales",
@@ -98,11 +469,65 @@ func (h *salesHandler) handlerGetSale(ctx *gin.Context) {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+		// El usuario del filtro no existe en el servicio de usuarios
+		if strings.Contains(err.Error(), "usuario no encontrado") {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
 		// Cualquier otro error es un Internal Server Error
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to search sales: " + err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"results": salesResults, "metadata": metadata})
+	// La antigüedad en pending se mide al instante consultado
+	slaAt := time.Now()
+	if filter.AsOf != nil {
+		slaAt = *filter.AsOf
+	}
+	salesResults = h.salesService.WithSLAStatus(salesResults, slaAt)
+
+	// Una lectura fuerte no debe quedar en caches intermedios
+	maxAge := h.cacheMaxAge
+	if filter.Consistency == sales.ConsistencyStrong {
+		maxAge = 0
+	}
+	if wantsNDJSON(ctx.Request) {
+		if setValidators(ctx, maxAge, weakETag(salesETag(salesResults)+"|ndjson"), metadata.LastModified()) {
+			return
+		}
+		h.streamSales(ctx, salesResults, metadata)
+		return
+	}
+	if setValidators(ctx, maxAge, salesETag(salesResults), metadata.LastModified()) {
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"results": h.sales(ctx).dtos(salesResults), "metadata": metadata})
+
+}
+
+// wantsNDJSON reports whether the client asked for the streaming search
+// response.
+func wantsNDJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), ndjsonMediaType)
+}
 
+// streamSales escribe una venta por línea y al final una línea con los
+// metadatos, para que los consumidores ETL procesen sin bufferear el arreglo.
+func (h *salesHandler) streamSales(ctx *gin.Context, results []*sales.Sale, metadata sales.SalesMetadata) {
+	ctx.Header("Content-Type", ndjsonMediaType)
+	ctx.Status(http.StatusOK)
+	enc := jsonenc.NewEncoder(ctx.Writer)
+	mapper := h.sales(ctx)
+	for i, sale := range results {
+		if err := enc.Encode(mapper.dto(sale)); err != nil {
+			h.logger.Warn("failed to stream sale", zap.Error(err))
+			return
+		}
+		if i%100 == 99 {
+			ctx.Writer.Flush()
+		}
+	}
+	if err := enc.Encode(gin.H{"metadata": metadata}); err != nil {
+		h.logger.Warn("failed to stream search metadata", zap.Error(err))
+	}
 }
@@ -0,0 +1,392 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"api_sales/internal/idempotency"
+	"api_sales/internal/maintenance"
+	"api_sales/internal/quota"
+	"api_sales/internal/sales"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+// newHandlerRouter monta los handlers sobre un SalesService simulado, sin
+// storage ni middlewares.
+func newHandlerRouter(t *testing.T) (*gin.Engine, *MockSalesService) {
+	gin.SetMode(gin.TestMode)
+	service := NewMockSalesService(t)
+	h := NewSalesHandler(service, zaptest.NewLogger(t))
+
+	router := gin.New()
+	router.GET("/sales/stats", h.handleGetStats)
+	router.GET("/sales/:id", h.handleGetSaleByID)
+	router.GET("/sales/:id/audit", h.handleGetSaleAudit)
+	router.GET("/sales", h.handlerGetSale)
+	router.POST("/sales", h.handleCreateSale)
+	router.POST("/sales/:id/submit", h.handleSubmitSale)
+	router.PATCH("/sales/:id", h.PatchSaleHandler(service))
+	router.POST("/sales/:id/disputes", h.handleOpenDispute)
+	router.GET("/public/sales/:reference/status", h.handleGetPublicStatus)
+	return router, service
+}
+
+func serve(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetSale_NotFound(t *testing.T) {
+	router, service := newHandlerRouter(t)
+	service.EXPECT().GetSale("missing", mock.Anything).Return(nil, sales.ErrNotFound)
+
+	w := serve(router, http.MethodGet, "/sales/missing", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "sale not found")
+}
+
+func TestGetSale_ResponseHidesTenant(t *testing.T) {
+	router, service := newHandlerRouter(t)
+	sale := &sales.Sale{ID: "s1", UserID: "u1", Amount: 10, Status: sales.StatusPending, TenantID: "acme", Version: 2}
+	service.EXPECT().GetSale("s1", mock.Anything).Return(sale, nil)
+	service.EXPECT().AllowedTransitions(sale, false).Return([]string{sales.StatusApproved}, nil)
+	service.EXPECT().RedactSensitive(sale).Return(sale)
+
+	w := serve(router, http.MethodGet, "/sales/s1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "tenant_id")
+	assert.Contains(t, w.Body.String(), `"version":2`)
+	assert.Contains(t, w.Body.String(), `"allowed_transitions":["approved"]`)
+}
+
+func TestCreateSale_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantError  string
+	}{
+		{"unknown user", errors.New("user not found"), http.StatusBadRequest, "user not found"},
+		{"enrichment", sales.ErrEnrichmentFailed, http.StatusUnprocessableEntity, sales.ErrEnrichmentFailed.Error()},
+		{"storage failure", errors.New("connection reset"), http.StatusInternalServerError, "failed to create sale"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, service := newHandlerRouter(t)
+			service.EXPECT().
+				CreateSaleWithOrigin("u1", 10.0, map[string]string(nil), mock.AnythingOfType("sales.Origin")).
+				Return(nil, tt.err)
+
+			w := serve(router, http.MethodPost, "/sales", `{"user_id":"u1","amount":10}`)
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantError)
+		})
+	}
+}
+
+func TestCreateSale_InvalidStatusSkipsService(t *testing.T) {
+	router, _ := newHandlerRouter(t)
+
+	// El mock falla si se llama a un método no esperado
+	w := serve(router, http.MethodPost, "/sales", `{"user_id":"u1","amount":10,"status":"approved"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSubmitSale_ConflictIncludesCurrentState(t *testing.T) {
+	router, service := newHandlerRouter(t)
+	service.EXPECT().SubmitSale("s1").Return(nil, sales.ErrNotDraft)
+	service.EXPECT().CurrentState("s1").Return(sales.SaleState{Status: sales.StatusApproved, Version: 3}, nil)
+
+	w := serve(router, http.MethodPost, "/sales/s1/submit", "")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"current_status":"approved"`)
+	assert.Contains(t, w.Body.String(), `"version":3`)
+}
+
+func TestPatchSale_RedactsSensitiveMetadata(t *testing.T) {
+	router, service := newHandlerRouter(t)
+	edited := &sales.Sale{ID: "s1", UserID: "u1", Amount: 20, Status: sales.StatusDraft, Metadata: map[string]string{"card_last4": "4242"}}
+	redacted := &sales.Sale{ID: "s1", UserID: "u1", Amount: 20, Status: sales.StatusDraft, Metadata: map[string]string{"card_last4": sales.MetadataMask}}
+	service.EXPECT().EditSaleAs("s1", mock.Anything, mock.Anything).Return(edited, nil)
+	service.EXPECT().RedactSensitive(edited).Return(redacted)
+
+	w := serve(router, http.MethodPatch, "/sales/s1", `{"amount":20}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "4242")
+}
+
+func TestGetSaleAudit_SnapshotsUseTheSaleDTO(t *testing.T) {
+	router, service := newHandlerRouter(t)
+	before := &sales.Sale{ID: "s1", UserID: "u1", Amount: 10, Status: sales.StatusDraft, TenantID: "acme"}
+	after := &sales.Sale{ID: "s1", UserID: "u1", Amount: 20, Status: sales.StatusDraft, TenantID: "acme"}
+	service.EXPECT().GetSaleAudit("s1", mock.Anything).Return([]*sales.AuditEntry{
+		{ID: "e1", SaleID: "s1", Action: sales.AuditActionDraftEdited, Before: before, After: after},
+	}, nil)
+	service.EXPECT().RedactSensitive(mock.Anything).RunAndReturn(func(sale *sales.Sale) *sales.Sale { return sale })
+
+	w := serve(router, http.MethodGet, "/sales/s1/audit", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"amount":20`)
+	assert.NotContains(t, w.Body.String(), "acme")
+	assert.NotContains(t, w.Body.String(), "tenant_id")
+}
+
+func TestPatchSale_StrictTransitionConflict(t *testing.T) {
+	router, service := newHandlerRouter(t)
+	service.EXPECT().UpdateSaleStatusAs("s1", sales.StatusPending, mock.Anything).Return(nil, sales.ErrInvalidTransition)
+	service.EXPECT().CurrentState("s1").Return(sales.SaleState{}, sales.ErrNotFound)
+
+	// Con strict=true no se busca un cambio repetido
+	w := serve(router, http.MethodPatch, "/sales/s1?strict=true", `{"status":"pending"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid status transition")
+}
+
+func TestGetStats_ServiceFailure(t *testing.T) {
+	router, service := newHandlerRouter(t)
+	service.EXPECT().GetStats().Return(sales.SalesMetadata{}, errors.New("storage unavailable"))
+
+	w := serve(router, http.MethodGet, "/sales/stats", "")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "failed to get sales stats")
+}
+
+// assertError verifica que la respuesta sea JSON con el mensaje de error
+// esperado.
+func assertError(t *testing.T, w *httptest.ResponseRecorder, wantStatus int, wantError string) {
+	t.Helper()
+	assert.Equal(t, wantStatus, w.Code)
+	assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "application/json"), "content type %q", w.Header().Get("Content-Type"))
+	var body struct {
+		Error string `json:"error"`
+	}
+	if assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body)) {
+		assert.Equal(t, wantError, body.Error)
+	}
+}
+
+func TestHandlers_ErrorBranches(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		setup      func(service *MockSalesService)
+		wantStatus int
+		wantError  string
+	}{
+		{
+			name: "malformed create payload", method: http.MethodPost, path: "/sales", body: `{"amount":`,
+			wantStatus: http.StatusBadRequest, wantError: "invalid request payload",
+		},
+		{
+			name: "non-positive amount", method: http.MethodPost, path: "/sales", body: `{"user_id":"u1","amount":0}`,
+			setup: func(service *MockSalesService) {
+				service.EXPECT().CreateSaleWithOrigin("u1", 0.0, map[string]string(nil), mock.Anything).
+					Return(nil, errors.New("amount must be greater than zero"))
+			},
+			wantStatus: http.StatusBadRequest, wantError: "amount must be greater than zero",
+		},
+		{
+			name: "too many ids", method: http.MethodGet, path: "/sales?ids=a,b",
+			setup: func(service *MockSalesService) {
+				service.EXPECT().GetSales([]string{"a", "b"}, mock.Anything, mock.Anything).Return(nil, nil, sales.ErrTooManyIDs)
+			},
+			wantStatus: http.StatusBadRequest, wantError: sales.ErrTooManyIDs.Error(),
+		},
+		{
+			name: "invalid status value", method: http.MethodPatch, path: "/sales/s1", body: `{"status":"unknown"}`,
+			setup: func(service *MockSalesService) {
+				service.EXPECT().UpdateSaleStatusAs("s1", "unknown", mock.Anything).Return(nil, sales.ErrInvalidStatus)
+			},
+			wantStatus: http.StatusBadRequest, wantError: "invalid status value",
+		},
+		{
+			name: "status with field edits", method: http.MethodPatch, path: "/sales/s1", body: `{"status":"approved","amount":5}`,
+			wantStatus: http.StatusBadRequest, wantError: "status cannot be changed while editing sale fields",
+		},
+		{
+			name: "submit unknown sale", method: http.MethodPost, path: "/sales/s1/submit",
+			setup: func(service *MockSalesService) {
+				service.EXPECT().SubmitSale("s1").Return(nil, sales.ErrNotFound)
+			},
+			wantStatus: http.StatusNotFound, wantError: "sale not found",
+		},
+		{
+			name: "public status of unknown sale", method: http.MethodGet, path: "/public/sales/ref-1/status",
+			setup: func(service *MockSalesService) {
+				service.EXPECT().PublicSaleStatus("ref-1").Return(sales.PublicStatus{}, sales.ErrNotFound)
+			},
+			wantStatus: http.StatusNotFound, wantError: "sale not found",
+		},
+		{
+			name: "edit in closed period", method: http.MethodPatch, path: "/sales/s1", body: `{"amount":5}`,
+			setup: func(service *MockSalesService) {
+				service.EXPECT().EditSaleAs("s1", mock.Anything, mock.Anything).Return(nil, sales.ErrPeriodClosed)
+				service.EXPECT().CurrentState("s1").Return(sales.SaleState{Status: sales.StatusApproved}, nil)
+			},
+			wantStatus: http.StatusConflict, wantError: sales.ErrPeriodClosed.Error(),
+		},
+		{
+			name: "dispute on non-disputable sale", method: http.MethodPost, path: "/sales/s1/disputes", body: `{"reason":"chargeback"}`,
+			setup: func(service *MockSalesService) {
+				service.EXPECT().OpenDispute("s1", "chargeback", mock.Anything).Return(nil, sales.ErrNotDisputable)
+				service.EXPECT().CurrentState("s1").Return(sales.SaleState{}, sales.ErrNotFound)
+			},
+			wantStatus: http.StatusConflict, wantError: sales.ErrNotDisputable.Error(),
+		},
+		{
+			name: "draft enrichment failure", method: http.MethodPost, path: "/sales", body: `{"user_id":"u1","amount":10,"status":"draft"}`,
+			setup: func(service *MockSalesService) {
+				service.EXPECT().CreateDraftSaleWithOrigin("u1", 10.0, map[string]string(nil), mock.Anything).
+					Return(nil, sales.ErrEnrichmentFailed)
+			},
+			wantStatus: http.StatusUnprocessableEntity, wantError: sales.ErrEnrichmentFailed.Error(),
+		},
+		{
+			name: "submit storage failure", method: http.MethodPost, path: "/sales/s1/submit",
+			setup: func(service *MockSalesService) {
+				service.EXPECT().SubmitSale("s1").Return(nil, errors.New("connection reset"))
+			},
+			wantStatus: http.StatusInternalServerError, wantError: "failed to submit sale",
+		},
+		{
+			name: "patch storage failure", method: http.MethodPatch, path: "/sales/s1", body: `{"status":"approved"}`,
+			setup: func(service *MockSalesService) {
+				service.EXPECT().UpdateSaleStatusAs("s1", sales.StatusApproved, mock.Anything).Return(nil, errors.New("connection reset"))
+			},
+			wantStatus: http.StatusInternalServerError, wantError: "internal error",
+		},
+		{
+			name: "get sale storage failure", method: http.MethodGet, path: "/sales/s1",
+			setup: func(service *MockSalesService) {
+				service.EXPECT().GetSale("s1", mock.Anything).Return(nil, errors.New("decrypt metadata: cipher: message authentication failed"))
+			},
+			wantStatus: http.StatusInternalServerError, wantError: "failed to retrieve sale",
+		},
+		{
+			name: "public status lookup failure", method: http.MethodGet, path: "/public/sales/ref-1/status",
+			setup: func(service *MockSalesService) {
+				service.EXPECT().PublicSaleStatus("ref-1").Return(sales.PublicStatus{}, errors.New("connection reset"))
+			},
+			wantStatus: http.StatusInternalServerError, wantError: "failed to retrieve sale status",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, service := newHandlerRouter(t)
+			if tt.setup != nil {
+				tt.setup(service)
+			}
+
+			w := serve(router, tt.method, tt.path, tt.body)
+			assertError(t, w, tt.wantStatus, tt.wantError)
+		})
+	}
+}
+
+func TestPublicStatus_RateLimitedByClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := NewMockSalesService(t)
+	h := NewSalesHandler(service, zaptest.NewLogger(t))
+	limiter := quota.NewLimiter(quota.Policy{"*": {RequestsPerSecond: 0.001, Burst: 1}})
+	router := gin.New()
+	router.GET("/public/sales/:reference/status", limitByClientIP(limiter), h.handleGetPublicStatus)
+
+	// Solo la primera petición llega al servicio
+	service.EXPECT().PublicSaleStatus("ref-1").Return(sales.PublicStatus{}, nil).Once()
+	assert.Equal(t, http.StatusOK, serve(router, http.MethodGet, "/public/sales/ref-1/status", "").Code)
+
+	w := serve(router, http.MethodGet, "/public/sales/ref-1/status", "")
+	assertError(t, w, http.StatusTooManyRequests, "rate limit exceeded")
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+}
+
+func TestCreateSale_RejectedDuringMaintenance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := NewMockSalesService(t)
+	h := NewSalesHandler(service, zaptest.NewLogger(t))
+	sw := maintenance.NewSwitch(maintenance.NewMemoryStore(), maintenance.State{Mode: maintenance.ModeReadOnly, RetryAfter: 30}, time.Minute)
+	router := gin.New()
+	router.Use(rejectDuringMaintenance(sw, zaptest.NewLogger(t)))
+	router.POST("/sales", h.handleCreateSale)
+
+	// Las escrituras no llegan al servicio en modo solo lectura
+	w := serve(router, http.MethodPost, "/sales", `{"user_id":"u1","amount":10}`)
+	assertError(t, w, http.StatusServiceUnavailable, "service under maintenance")
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+}
+
+func TestIdempotent_ScopedByPathAndCaller(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	created := 0
+	router := gin.New()
+	router.Use(gin.Recovery(), func(ctx *gin.Context) {
+		if id := ctx.GetHeader("X-Test-Key"); id != "" {
+			ctx.Set(apiKeyIDKey, id)
+		}
+	}, idempotent(idempotency.NewMemoryStore(), time.Minute, zaptest.NewLogger(t)))
+	router.POST("/sales/:id/adjustments", func(ctx *gin.Context) {
+		created++
+		ctx.JSON(http.StatusCreated, gin.H{"n": created})
+	})
+	router.POST("/panics", func(ctx *gin.Context) {
+		created++
+		if created == 1 {
+			panic("boom")
+		}
+		ctx.Status(http.StatusNoContent)
+	})
+	send := func(path, key string) int {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"amount":-10}`))
+		req.Header.Set(idempotencyHeader, "retry-1")
+		req.Header.Set("X-Test-Key", key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// La misma clave en otra venta o de otro llamador es otra petición
+	send("/sales/s1/adjustments", "k1")
+	send("/sales/s1/adjustments", "k1")
+	send("/sales/s2/adjustments", "k1")
+	send("/sales/s1/adjustments", "k2")
+	assert.Equal(t, 3, created)
+
+	// Un pánico libera la clave y el reintento se procesa
+	created = 0
+	assert.Equal(t, http.StatusInternalServerError, send("/panics", "k1"))
+	assert.Equal(t, http.StatusNoContent, send("/panics", "k1"))
+}
+
+func TestValidateOpenAPI_ReplacesDriftingResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	doc, err := loadOpenAPI()
+	assert.NoError(t, err)
+
+	router := gin.New()
+	router.Use(validateOpenAPI(doc, true, zaptest.NewLogger(t)))
+	router.GET("/ping", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"msg": "pong"})
+	})
+	router.GET("/unlisted", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"anything": true})
+	})
+
+	assertError(t, serve(router, http.MethodGet, "/ping", ""), http.StatusInternalServerError, "response does not match the API schema")
+	w := serve(router, http.MethodGet, "/unlisted", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"anything":true}`, w.Body.String())
+}
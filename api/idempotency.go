@@ -0,0 +1,116 @@
+package api
+
+import (
+	"api_sales/internal/idempotency"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const idempotencyHeader = "Idempotency-Key"
+
+// bodyRecorder copia la respuesta para poder guardarla en el store.
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// idempotent replays the stored response when a request is retried with the
+// same Idempotency-Key. Requests without the header are not affected.
+func idempotent(store idempotency.Store, ttl time.Duration, logger *zap.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(idempotencyHeader)
+		if key == "" {
+			ctx.Next()
+			return
+		}
+
+		body, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid request payload"})
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		sum := sha256.Sum256(body)
+		fingerprint := hex.EncodeToString(sum[:])
+		// La clave vale para un llamador y un recurso: el path real, no la ruta
+		scopedKey := idempotencyScope(ctx) + " " + ctx.Request.Method + " " + ctx.Request.URL.Path + " " + key
+
+		existing, reserved, err := store.Reserve(ctx.Request.Context(), scopedKey, fingerprint, ttl)
+		if err != nil {
+			logger.Error("idempotency store unavailable", zap.Error(err))
+			ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "idempotency store unavailable"})
+			return
+		}
+
+		if !reserved {
+			switch {
+			case existing.Fingerprint != fingerprint:
+				ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "idempotency key reused with a different request body"})
+			case !existing.Completed:
+				ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "a request with this idempotency key is still being processed"})
+			default:
+				ctx.Header("Idempotent-Replayed", "true")
+				ctx.Data(existing.StatusCode, existing.ContentType, existing.Body)
+				ctx.Abort()
+			}
+			return
+		}
+
+		// Los errores del servidor y los pánicos liberan la clave para permitir
+		// reintentos; el defer corre también mientras el pánico se propaga.
+		finished := false
+		defer func() {
+			if finished {
+				return
+			}
+			if err := store.Release(context.WithoutCancel(ctx.Request.Context()), scopedKey); err != nil {
+				logger.Error("failed to release idempotency key", zap.Error(err))
+			}
+		}()
+
+		recorder := &bodyRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = recorder
+		ctx.Next()
+
+		if recorder.Status() >= http.StatusInternalServerError {
+			return
+		}
+		finished = true
+
+		rec := idempotency.Record{
+			Fingerprint: fingerprint,
+			StatusCode:  recorder.Status(),
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		}
+		if err := store.Complete(ctx.Request.Context(), scopedKey, rec, ttl); err != nil {
+			logger.Error("failed to store idempotent response", zap.Error(err))
+		}
+	}
+}
+
+// idempotencyScope identifica al llamador: la API key o el usuario del token,
+// o la IP sin credenciales, dentro de su tenant.
+func idempotencyScope(ctx *gin.Context) string {
+	caller := "ip:" + ctx.ClientIP()
+	if id := ctx.GetString(apiKeyIDKey); id != "" {
+		caller = "key:" + id
+	} else if user := ctx.GetString(tokenUserKey); user != "" {
+		caller = "user:" + user
+	}
+	return tenantID(ctx) + "/" + caller
+}
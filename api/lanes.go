@@ -0,0 +1,132 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var laneInFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "sales_lane_in_flight",
+	Help: "Requests being processed per priority lane.",
+}, []string{"lane"})
+
+var laneRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "sales_lane_rejected_total",
+	Help: "Requests rejected with 503 after waiting too long for a slot in their lane.",
+}, []string{"lane"})
+
+// requestClassHeader lets clients such as bulk importers send their requests
+// through the batch lane.
+const requestClassHeader = "X-Request-Class"
+
+// requestLane separa las requests interactivas de las masivas para que cada
+// una tenga su propio límite de concurrencia.
+type requestLane string
+
+const (
+	laneInteractive requestLane = "interactive"
+	laneBatch       requestLane = "batch"
+)
+
+// batchRoutes are the exports that always go through the batch lane.
+var batchRoutes = map[string]bool{
+	"/ledger":                   true,
+	"/snapshots/:period/export": true,
+	"/admin/audit/export":       true,
+}
+
+// classifyLane returns the lane of a request: batch when the client says so
+// in X-Request-Class or for exports, interactive otherwise. The header can
+// only move a request to the batch lane, never out of it.
+func classifyLane(ctx *gin.Context) requestLane {
+	if strings.EqualFold(ctx.GetHeader(requestClassHeader), string(laneBatch)) {
+		return laneBatch
+	}
+	route := ctx.FullPath()
+	if batchRoutes[route] || (route == "/sales" && isRead(ctx.Request.Method) && wantsNDJSON(ctx.Request)) {
+		return laneBatch
+	}
+	return laneInteractive
+}
+
+// laneLimiter keeps a pool of slots per lane; a lane without slots has no
+// limit.
+type laneLimiter struct {
+	slots  map[requestLane]chan struct{}
+	wait   time.Duration
+	logger *zap.Logger
+}
+
+func newLaneLimiter(limits map[requestLane]int, wait time.Duration, logger *zap.Logger) *laneLimiter {
+	l := &laneLimiter{slots: make(map[requestLane]chan struct{}), wait: wait, logger: logger}
+	for lane, limit := range limits {
+		if limit > 0 {
+			l.slots[lane] = make(chan struct{}, limit)
+		}
+	}
+	return l
+}
+
+// acquire espera un lugar en el carril hasta wait o hasta que se cancela la
+// request.
+func (l *laneLimiter) acquire(ctx *gin.Context, slots chan struct{}) bool {
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+	}
+	timer := time.NewTimer(l.wait)
+	defer timer.Stop()
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Request.Context().Done():
+		return false
+	}
+}
+
+// processInLanes limits how many requests of each lane run at once, so a bulk
+// import filling the batch lane never takes the slots of interactive sale
+// creation. Requests that wait too long for a slot get 503 with Retry-After.
+func processInLanes(l *laneLimiter) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		path := ctx.Request.URL.Path
+		if path == "/ping" || path == "/metrics" || path == "/version" {
+			ctx.Next()
+			return
+		}
+		lane := classifyLane(ctx)
+		slots := l.slots[lane]
+		if slots == nil {
+			ctx.Next()
+			return
+		}
+
+		if !l.acquire(ctx, slots) {
+			laneRejected.WithLabelValues(string(lane)).Inc()
+			l.logger.Warn("request lane full", zap.String("lane", string(lane)), zap.String("path", ctx.FullPath()))
+			ctx.Header("Retry-After", strconv.Itoa(max(1, int(l.wait.Seconds()))))
+			ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "too many requests in progress, try again later",
+				"lane":  lane,
+			})
+			return
+		}
+		inFlight := laneInFlight.WithLabelValues(string(lane))
+		inFlight.Inc()
+		defer func() {
+			inFlight.Dec()
+			<-slots
+		}()
+		ctx.Next()
+	}
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"api_sales/internal/jsonenc"
+	"api_sales/internal/sales"
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// handleGetLedger handles the GET /ledger endpoint. The format query parameter
+// selects ndjson (default) or csv; dates carry the offset of tz (default UTC).
+func (h *salesHandler) handleGetLedger(ctx *gin.Context) {
+	format := ctx.DefaultQuery("format", "ndjson")
+	if format != "ndjson" && format != "csv" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid format: expected ndjson or csv"})
+		return
+	}
+
+	from, err := timeQuery(ctx, "from", lowerBound)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	to, err := timeQuery(ctx, "to", upperBound)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	loc, err := tzQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lines, err := h.salesService.Ledger(from, to)
+	if err != nil {
+		h.logger.Error("failed to build ledger", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to build ledger"})
+		return
+	}
+	for i := range lines {
+		lines[i].Date = lines[i].Date.In(loc)
+	}
+
+	if format == "csv" {
+		ctx.Header("Content-Type", "text/csv")
+		ctx.Header("Content-Disposition", `attachment; filename="ledger.csv"`)
+		writeLedgerCSV(ctx, lines)
+		return
+	}
+
+	ctx.Header("Content-Type", ndjsonMediaType)
+	enc := jsonenc.NewEncoder(ctx.Writer)
+	for _, line := range lines {
+		if err := enc.Encode(line); err != nil {
+			h.logger.Warn("failed to write ledger line", zap.Error(err))
+			return
+		}
+	}
+}
+
+func writeLedgerCSV(ctx *gin.Context, lines []sales.LedgerLine) {
+	w := csv.NewWriter(ctx.Writer)
+	w.Write([]string{"entry_id", "sale_id", "date", "account", "debit", "credit", "description"})
+	for _, l := range lines {
+		w.Write([]string{
+			l.EntryID,
+			l.SaleID,
+			l.Date.Format(time.RFC3339),
+			l.Account,
+			strconv.FormatFloat(l.Debit, 'f', 2, 64),
+			strconv.FormatFloat(l.Credit, 'f', 2, 64),
+			l.Description,
+		})
+	}
+	w.Flush()
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"api_sales/internal/sales"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// halMediaType is the opt-in representation of sales with _links.
+const halMediaType = "application/hal+json"
+
+// ndjsonMediaType is used by the exports and the streaming search.
+const ndjsonMediaType = "application/x-ndjson"
+
+// link is an action a client can take on a resource.
+type link struct {
+	Href   string `json:"href"`
+	Method string `json:"method"`
+}
+
+// wantsLinks reports whether the client asked for the HAL representation.
+func wantsLinks(ctx *gin.Context) bool {
+	return strings.Contains(ctx.GetHeader("Accept"), halMediaType)
+}
+
+// saleLinks maps the allowed transitions of a sale to the requests that
+// perform them. Custom statuses of the tenant (e.g. a "cancel" status) are
+// linked by name; refund is offered while the sale can be adjusted.
+func saleLinks(sale *sales.Sale, allowed []string) map[string]link {
+	self := "/sales/" + sale.ID
+	links := map[string]link{"self": {Href: self, Method: http.MethodGet}}
+	for _, status := range allowed {
+		// Las disputas se resuelven en /disputes/:id/resolve, fuera de la venta
+		if sale.Status == sales.StatusDisputed && (status == sales.StatusApproved || status == sales.StatusChargedBack) {
+			continue
+		}
+		switch status {
+		case sales.StatusPending:
+			links["submit"] = link{Href: self + "/submit", Method: http.MethodPost}
+		case sales.StatusApproved:
+			links["approve"] = link{Href: self, Method: http.MethodPatch}
+		case sales.StatusRejected:
+			links["reject"] = link{Href: self, Method: http.MethodPatch}
+		case sales.StatusDisputed:
+			links["dispute"] = link{Href: self + "/disputes", Method: http.MethodPost}
+		default:
+			links[status] = link{Href: self, Method: http.MethodPatch}
+		}
+	}
+	if sale.Status == sales.StatusApproved {
+		links["refund"] = link{Href: self + "/adjustments", Method: http.MethodPost}
+	}
+	return links
+}
+
+// writeSale responde la venta con sus transiciones permitidas y, si el
+// cliente pidió HAL, con _links.
+func (h *salesHandler) writeSale(ctx *gin.Context, status int, sale *sales.Sale) {
+	allowed, err := h.salesService.AllowedTransitions(sale, callerRole(ctx) == adminRole)
+	if err != nil {
+		h.logger.Error("failed to compute allowed transitions", zap.Error(err), zap.String("sale_id", sale.ID))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve sale"})
+		return
+	}
+
+	resp := saleResponse{saleDTO: h.sales(ctx).dto(sale), AllowedTransitions: allowed}
+	if wantsLinks(ctx) {
+		resp.Links = saleLinks(sale, allowed)
+		ctx.Header("Content-Type", halMediaType+"; charset=utf-8")
+	}
+	ctx.JSON(status, resp)
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"api_sales/internal/sales"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// operatorID returns the authenticated identity used as lease owner: the
+// admin behind an impersonated request, the caller's user, or the API key.
+// Anonymous requests fall back to the client IP, in their own namespace so
+// they can never match an authenticated owner.
+func operatorID(ctx *gin.Context) string {
+	if c := caller(ctx); c != nil {
+		if c.ImpersonatedBy != "" {
+			return c.ImpersonatedBy
+		}
+		if c.UserID != "" {
+			return c.UserID
+		}
+	}
+	if kid := ctx.GetString(apiKeyIDKey); kid != "" {
+		return "key:" + kid
+	}
+	return "ip:" + ctx.ClientIP()
+}
+
+// requireSaleLease acquires the mutation lease of the :id sale for the caller
+// and aborts with 423 Locked when another operator holds it.
+func requireSaleLease(locks *sales.MutationLocks) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		current, err := locks.Acquire(ctx.Param("id"), operatorID(ctx))
+		if err != nil {
+			respondLocked(ctx, current)
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// handleAcquireLock handles the POST /sales/:id/lock endpoint.
+func handleAcquireLock(locks *sales.MutationLocks) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		l, err := locks.Acquire(ctx.Param("id"), operatorID(ctx))
+		if err != nil {
+			respondLocked(ctx, l)
+			return
+		}
+		ctx.JSON(http.StatusOK, l)
+	}
+}
+
+// handleReleaseLock handles the DELETE /sales/:id/lock endpoint.
+func handleReleaseLock(locks *sales.MutationLocks) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if err := locks.Release(ctx.Param("id"), operatorID(ctx)); err != nil {
+			ctx.JSON(http.StatusLocked, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+func respondLocked(ctx *gin.Context, current sales.SaleLock) {
+	ctx.AbortWithStatusJSON(http.StatusLocked, gin.H{
+		"error":      sales.ErrSaleLocked.Error(),
+		"locked_by":  current.Owner,
+		"expires_at": current.ExpiresAt,
+	})
+}
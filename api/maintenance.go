@@ -0,0 +1,101 @@
+package api
+
+import (
+	"api_sales/internal/maintenance"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// maintenanceRequest is the body of PUT /admin/maintenance.
+type maintenanceRequest struct {
+	Mode       string `json:"mode"`
+	Reason     string `json:"reason"`
+	RetryAfter int    `json:"retry_after_seconds"`
+}
+
+// rejectDuringMaintenance answers 503 with Retry-After on writes in read-only
+// mode and on every request in full mode. The admin endpoints stay available
+// so the mode can be switched back.
+func rejectDuringMaintenance(sw *maintenance.Switch, logger *zap.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		path := ctx.Request.URL.Path
+		if strings.HasPrefix(path, "/admin/") || path == "/ping" || path == "/metrics" || path == "/version" {
+			ctx.Next()
+			return
+		}
+
+		state, err := sw.State(ctx.Request.Context())
+		if err != nil {
+			logger.Warn("failed to load maintenance state", zap.Error(err))
+		}
+		if state.Mode == maintenance.ModeOff || (state.Mode == maintenance.ModeReadOnly && isRead(ctx.Request.Method)) {
+			ctx.Next()
+			return
+		}
+
+		if state.RetryAfter > 0 {
+			ctx.Header("Retry-After", strconv.Itoa(state.RetryAfter))
+		}
+		ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+			"error":  "service under maintenance",
+			"mode":   state.Mode,
+			"reason": state.Reason,
+		})
+	}
+}
+
+func isRead(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// handleGetMaintenance handles the GET /admin/maintenance endpoint.
+func handleGetMaintenance(sw *maintenance.Switch, logger *zap.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		state, err := sw.State(ctx.Request.Context())
+		if err != nil {
+			logger.Error("failed to load maintenance state", zap.Error(err))
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load maintenance state"})
+			return
+		}
+		ctx.JSON(http.StatusOK, state)
+	}
+}
+
+// handleSetMaintenance handles the PUT /admin/maintenance endpoint.
+func handleSetMaintenance(sw *maintenance.Switch, logger *zap.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req maintenanceRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		mode, err := maintenance.ParseMode(req.Mode)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if req.RetryAfter < 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "retry_after_seconds must not be negative"})
+			return
+		}
+
+		state, err := sw.Set(ctx.Request.Context(), maintenance.State{
+			Mode:       mode,
+			Reason:     req.Reason,
+			RetryAfter: req.RetryAfter,
+			UpdatedBy:  operatorID(ctx),
+		})
+		if err != nil {
+			logger.Error("failed to save maintenance state", zap.Error(err))
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save maintenance state"})
+			return
+		}
+
+		logger.Info("maintenance mode changed", zap.String("mode", string(state.Mode)), zap.String("updated_by", state.UpdatedBy))
+		ctx.JSON(http.StatusOK, state)
+	}
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"api_sales/internal/sales"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// tokenUserKey is the gin context key where authenticateBearer stores the
+// user ID taken from a verified token.
+const tokenUserKey = "token_user_id"
+
+// tokenVerifier checks the bearer tokens issued to customers.
+type tokenVerifier struct {
+	secret []byte
+	parser *jwt.Parser
+}
+
+// newTokenVerifier accepts HS256 tokens signed with secret that carry an
+// expiry and, when set, the given issuer and audience.
+func newTokenVerifier(secret, issuer, audience string) *tokenVerifier {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if issuer != "" {
+		opts = append(opts, jwt.WithIssuer(issuer))
+	}
+	if audience != "" {
+		opts = append(opts, jwt.WithAudience(audience))
+	}
+	return &tokenVerifier{secret: []byte(secret), parser: jwt.NewParser(opts...)}
+}
+
+// userID returns the subject of a valid token.
+func (v *tokenVerifier) userID(token string) (string, error) {
+	claims := jwt.RegisteredClaims{}
+	if _, err := v.parser.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return v.secret, nil
+	}); err != nil {
+		return "", err
+	}
+	if claims.Subject == "" {
+		return "", jwt.ErrTokenInvalidSubject
+	}
+	return claims.Subject, nil
+}
+
+// authenticateBearer resolves the customer from an "Authorization: Bearer"
+// token. Requests without one pass through; invalid tokens get 401. Without
+// an API key the token user also becomes the caller, so searches only return
+// their own sales.
+func authenticateBearer(verifier *tokenVerifier, logger *zap.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+		if !ok || verifier == nil {
+			ctx.Next()
+			return
+		}
+
+		userID, err := verifier.userID(strings.TrimSpace(token))
+		if err != nil {
+			logger.Warn("rejected bearer token", zap.Error(err))
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			return
+		}
+		ctx.Set(tokenUserKey, userID)
+		if caller(ctx) == nil {
+			ctx.Set(callerKey, &sales.Caller{UserID: userID, Role: callerRole(ctx)})
+		}
+		ctx.Next()
+	}
+}
+
+// handleGetMySales handles the GET /me/sales endpoint: the sales of the token
+// user, newest first, paginated with ?limit= and ?cursor=.
+func (h *salesHandler) handleGetMySales(ctx *gin.Context) {
+	userID := ctx.GetString(tokenUserKey)
+	if userID == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "bearer token required"})
+		return
+	}
+
+	limit := 0
+	if raw := ctx.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit: expected a positive integer"})
+			return
+		}
+		limit = n
+	}
+
+	page, err := h.salesService.ListUserSales(userID, limit, ctx.Query("cursor"))
+	if err != nil {
+		if err == sales.ErrInvalidCursor {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to list user sales", zap.Error(err), zap.String("user_id", userID))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list sales"})
+		return
+	}
+
+	page.Results = h.salesService.WithSLAStatus(page.Results, time.Now())
+	// Respuesta por usuario: nunca en caches compartidos
+	ctx.Header("Cache-Control", "private, no-store")
+	ctx.JSON(http.StatusOK, h.sales(ctx).page(page))
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"api_sales/internal/config"
+	"api_sales/internal/migrate"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Error para las bases con migraciones sin aplicar
+var errPendingMigrations = errors.New("pending schema migrations")
+
+// migratable es un storage SQL con migraciones de esquema.
+type migratable interface {
+	Migrator() (*migrate.Runner, error)
+}
+
+// Migrate applies the pending schema migrations of the mysql and sqlite
+// sales storages of cfg, reporting each one to w. It is what the -migrate
+// flag runs before a release that disables MIGRATE_ON_START.
+func Migrate(ctx context.Context, cfg config.Config, w io.Writer) error {
+	backends := sqlBackends(cfg)
+	if len(backends) == 0 {
+		fmt.Fprintln(w, "no sales storage uses mysql or sqlite: nothing to migrate")
+		return nil
+	}
+	for _, backend := range backends {
+		runner, db, err := openMigrator(cfg, backend)
+		if err != nil {
+			return err
+		}
+		applied, err := runner.Up(ctx)
+		db.Close()
+		for _, m := range applied {
+			fmt.Fprintf(w, "%s: applied %s\n", backend, m)
+		}
+		if err != nil {
+			return fmt.Errorf("error migrating %s sales storage: %w", backend, err)
+		}
+		if len(applied) == 0 {
+			fmt.Fprintf(w, "%s: schema up to date\n", backend)
+		}
+	}
+	return nil
+}
+
+// prepareSchema aplica las migraciones pendientes con MIGRATE_ON_START y, si
+// no, falla cuando queda alguna sin aplicar.
+func prepareSchema(cfg config.Config, backend string, storage migratable) error {
+	runner, err := storage.Migrator()
+	if err != nil {
+		return err
+	}
+	if cfg.MigrateOnStart {
+		if _, err := runner.Up(context.Background()); err != nil {
+			return fmt.Errorf("error migrating %s sales storage: %w", backend, err)
+		}
+		return nil
+	}
+	return checkPending(context.Background(), runner, backend)
+}
+
+func checkPending(ctx context.Context, runner *migrate.Runner, backend string) error {
+	pending, err := runner.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%s sales storage has %d %w, starting with %s", backend, len(pending), errPendingMigrations, pending[0])
+	}
+	return nil
+}
+
+// sqlBackends lista sin repetir los backends SQL del storage de ventas y del
+// shadow, que comparten base.
+func sqlBackends(cfg config.Config) []string {
+	var backends []string
+	for _, backend := range []string{cfg.SalesStorageBackend, cfg.ShadowStorageBackend} {
+		if (backend == config.BackendMySQL || backend == config.BackendSQLite) && !slices.Contains(backends, backend) {
+			backends = append(backends, backend)
+		}
+	}
+	return backends
+}
+
+// openMigrator abre la base del backend sin migrarla; el caller la cierra.
+func openMigrator(cfg config.Config, backend string) (*migrate.Runner, *sql.DB, error) {
+	var (
+		storage migratable
+		db      *sql.DB
+		err     error
+	)
+	switch backend {
+	case config.BackendMySQL:
+		storage, db, err = openMySQLStorage(cfg)
+	case config.BackendSQLite:
+		storage, db, err = openSQLiteStorage(cfg)
+	default:
+		return nil, nil, fmt.Errorf("%s has no schema migrations", backend)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	runner, err := storage.Migrator()
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	return runner, db, nil
+}
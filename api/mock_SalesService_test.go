@@ -0,0 +1,3080 @@
+// Code generated by mockery v2.53.7. DO NOT EDIT.
+
+package api
+
+import (
+	sales "api_sales/internal/sales"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockSalesService is an autogenerated mock type for the SalesService type
+type MockSalesService struct {
+	mock.Mock
+}
+
+type MockSalesService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockSalesService) EXPECT() *MockSalesService_Expecter {
+	return &MockSalesService_Expecter{mock: &_m.Mock}
+}
+
+// AddAttachment provides a mock function with given fields: ctx, saleID, filename, contentType, data, uploadedBy
+func (_m *MockSalesService) AddAttachment(ctx context.Context, saleID string, filename string, contentType string, data []byte, uploadedBy string) (*sales.Attachment, error) {
+	ret := _m.Called(ctx, saleID, filename, contentType, data, uploadedBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddAttachment")
+	}
+
+	var r0 *sales.Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, []byte, string) (*sales.Attachment, error)); ok {
+		return rf(ctx, saleID, filename, contentType, data, uploadedBy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, []byte, string) *sales.Attachment); ok {
+		r0 = rf(ctx, saleID, filename, contentType, data, uploadedBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, []byte, string) error); ok {
+		r1 = rf(ctx, saleID, filename, contentType, data, uploadedBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_AddAttachment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddAttachment'
+type MockSalesService_AddAttachment_Call struct {
+	*mock.Call
+}
+
+// AddAttachment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - saleID string
+//   - filename string
+//   - contentType string
+//   - data []byte
+//   - uploadedBy string
+func (_e *MockSalesService_Expecter) AddAttachment(ctx interface{}, saleID interface{}, filename interface{}, contentType interface{}, data interface{}, uploadedBy interface{}) *MockSalesService_AddAttachment_Call {
+	return &MockSalesService_AddAttachment_Call{Call: _e.mock.On("AddAttachment", ctx, saleID, filename, contentType, data, uploadedBy)}
+}
+
+func (_c *MockSalesService_AddAttachment_Call) Run(run func(ctx context.Context, saleID string, filename string, contentType string, data []byte, uploadedBy string)) *MockSalesService_AddAttachment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].([]byte), args[5].(string))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_AddAttachment_Call) Return(_a0 *sales.Attachment, _a1 error) *MockSalesService_AddAttachment_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_AddAttachment_Call) RunAndReturn(run func(context.Context, string, string, string, []byte, string) (*sales.Attachment, error)) *MockSalesService_AddAttachment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AddComment provides a mock function with given fields: saleID, author, body
+func (_m *MockSalesService) AddComment(saleID string, author string, body string) (*sales.Comment, error) {
+	ret := _m.Called(saleID, author, body)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddComment")
+	}
+
+	var r0 *sales.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string) (*sales.Comment, error)); ok {
+		return rf(saleID, author, body)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) *sales.Comment); ok {
+		r0 = rf(saleID, author, body)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(saleID, author, body)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_AddComment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddComment'
+type MockSalesService_AddComment_Call struct {
+	*mock.Call
+}
+
+// AddComment is a helper method to define mock.On call
+//   - saleID string
+//   - author string
+//   - body string
+func (_e *MockSalesService_Expecter) AddComment(saleID interface{}, author interface{}, body interface{}) *MockSalesService_AddComment_Call {
+	return &MockSalesService_AddComment_Call{Call: _e.mock.On("AddComment", saleID, author, body)}
+}
+
+func (_c *MockSalesService_AddComment_Call) Run(run func(saleID string, author string, body string)) *MockSalesService_AddComment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_AddComment_Call) Return(_a0 *sales.Comment, _a1 error) *MockSalesService_AddComment_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_AddComment_Call) RunAndReturn(run func(string, string, string) (*sales.Comment, error)) *MockSalesService_AddComment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AdjustSale provides a mock function with given fields: saleID, amount, reason, createdBy
+func (_m *MockSalesService) AdjustSale(saleID string, amount float64, reason string, createdBy string) (*sales.Adjustment, error) {
+	ret := _m.Called(saleID, amount, reason, createdBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdjustSale")
+	}
+
+	var r0 *sales.Adjustment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, float64, string, string) (*sales.Adjustment, error)); ok {
+		return rf(saleID, amount, reason, createdBy)
+	}
+	if rf, ok := ret.Get(0).(func(string, float64, string, string) *sales.Adjustment); ok {
+		r0 = rf(saleID, amount, reason, createdBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.Adjustment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, float64, string, string) error); ok {
+		r1 = rf(saleID, amount, reason, createdBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_AdjustSale_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AdjustSale'
+type MockSalesService_AdjustSale_Call struct {
+	*mock.Call
+}
+
+// AdjustSale is a helper method to define mock.On call
+//   - saleID string
+//   - amount float64
+//   - reason string
+//   - createdBy string
+func (_e *MockSalesService_Expecter) AdjustSale(saleID interface{}, amount interface{}, reason interface{}, createdBy interface{}) *MockSalesService_AdjustSale_Call {
+	return &MockSalesService_AdjustSale_Call{Call: _e.mock.On("AdjustSale", saleID, amount, reason, createdBy)}
+}
+
+func (_c *MockSalesService_AdjustSale_Call) Run(run func(saleID string, amount float64, reason string, createdBy string)) *MockSalesService_AdjustSale_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(float64), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_AdjustSale_Call) Return(_a0 *sales.Adjustment, _a1 error) *MockSalesService_AdjustSale_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_AdjustSale_Call) RunAndReturn(run func(string, float64, string, string) (*sales.Adjustment, error)) *MockSalesService_AdjustSale_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AllowedTransitions provides a mock function with given fields: sale, resolveDisputes
+func (_m *MockSalesService) AllowedTransitions(sale *sales.Sale, resolveDisputes bool) ([]string, error) {
+	ret := _m.Called(sale, resolveDisputes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AllowedTransitions")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sales.Sale, bool) ([]string, error)); ok {
+		return rf(sale, resolveDisputes)
+	}
+	if rf, ok := ret.Get(0).(func(*sales.Sale, bool) []string); ok {
+		r0 = rf(sale, resolveDisputes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*sales.Sale, bool) error); ok {
+		r1 = rf(sale, resolveDisputes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_AllowedTransitions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AllowedTransitions'
+type MockSalesService_AllowedTransitions_Call struct {
+	*mock.Call
+}
+
+// AllowedTransitions is a helper method to define mock.On call
+//   - sale *sales.Sale
+//   - resolveDisputes bool
+func (_e *MockSalesService_Expecter) AllowedTransitions(sale interface{}, resolveDisputes interface{}) *MockSalesService_AllowedTransitions_Call {
+	return &MockSalesService_AllowedTransitions_Call{Call: _e.mock.On("AllowedTransitions", sale, resolveDisputes)}
+}
+
+func (_c *MockSalesService_AllowedTransitions_Call) Run(run func(sale *sales.Sale, resolveDisputes bool)) *MockSalesService_AllowedTransitions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*sales.Sale), args[1].(bool))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_AllowedTransitions_Call) Return(_a0 []string, _a1 error) *MockSalesService_AllowedTransitions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_AllowedTransitions_Call) RunAndReturn(run func(*sales.Sale, bool) ([]string, error)) *MockSalesService_AllowedTransitions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AttachmentMaxBytes provides a mock function with no fields
+func (_m *MockSalesService) AttachmentMaxBytes() int {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for AttachmentMaxBytes")
+	}
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
+// MockSalesService_AttachmentMaxBytes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AttachmentMaxBytes'
+type MockSalesService_AttachmentMaxBytes_Call struct {
+	*mock.Call
+}
+
+// AttachmentMaxBytes is a helper method to define mock.On call
+func (_e *MockSalesService_Expecter) AttachmentMaxBytes() *MockSalesService_AttachmentMaxBytes_Call {
+	return &MockSalesService_AttachmentMaxBytes_Call{Call: _e.mock.On("AttachmentMaxBytes")}
+}
+
+func (_c *MockSalesService_AttachmentMaxBytes_Call) Run(run func()) *MockSalesService_AttachmentMaxBytes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockSalesService_AttachmentMaxBytes_Call) Return(_a0 int) *MockSalesService_AttachmentMaxBytes_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSalesService_AttachmentMaxBytes_Call) RunAndReturn(run func() int) *MockSalesService_AttachmentMaxBytes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ClaimSale provides a mock function with given fields: saleID, reviewer
+func (_m *MockSalesService) ClaimSale(saleID string, reviewer string) (*sales.Assignment, error) {
+	ret := _m.Called(saleID, reviewer)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimSale")
+	}
+
+	var r0 *sales.Assignment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*sales.Assignment, error)); ok {
+		return rf(saleID, reviewer)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *sales.Assignment); ok {
+		r0 = rf(saleID, reviewer)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.Assignment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(saleID, reviewer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_ClaimSale_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimSale'
+type MockSalesService_ClaimSale_Call struct {
+	*mock.Call
+}
+
+// ClaimSale is a helper method to define mock.On call
+//   - saleID string
+//   - reviewer string
+func (_e *MockSalesService_Expecter) ClaimSale(saleID interface{}, reviewer interface{}) *MockSalesService_ClaimSale_Call {
+	return &MockSalesService_ClaimSale_Call{Call: _e.mock.On("ClaimSale", saleID, reviewer)}
+}
+
+func (_c *MockSalesService_ClaimSale_Call) Run(run func(saleID string, reviewer string)) *MockSalesService_ClaimSale_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_ClaimSale_Call) Return(_a0 *sales.Assignment, _a1 error) *MockSalesService_ClaimSale_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_ClaimSale_Call) RunAndReturn(run func(string, string) (*sales.Assignment, error)) *MockSalesService_ClaimSale_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ClosePeriod provides a mock function with given fields: period, closedBy
+func (_m *MockSalesService) ClosePeriod(period string, closedBy string) (*sales.AccountingPeriod, error) {
+	ret := _m.Called(period, closedBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClosePeriod")
+	}
+
+	var r0 *sales.AccountingPeriod
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*sales.AccountingPeriod, error)); ok {
+		return rf(period, closedBy)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *sales.AccountingPeriod); ok {
+		r0 = rf(period, closedBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.AccountingPeriod)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(period, closedBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_ClosePeriod_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClosePeriod'
+type MockSalesService_ClosePeriod_Call struct {
+	*mock.Call
+}
+
+// ClosePeriod is a helper method to define mock.On call
+//   - period string
+//   - closedBy string
+func (_e *MockSalesService_Expecter) ClosePeriod(period interface{}, closedBy interface{}) *MockSalesService_ClosePeriod_Call {
+	return &MockSalesService_ClosePeriod_Call{Call: _e.mock.On("ClosePeriod", period, closedBy)}
+}
+
+func (_c *MockSalesService_ClosePeriod_Call) Run(run func(period string, closedBy string)) *MockSalesService_ClosePeriod_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_ClosePeriod_Call) Return(_a0 *sales.AccountingPeriod, _a1 error) *MockSalesService_ClosePeriod_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_ClosePeriod_Call) RunAndReturn(run func(string, string) (*sales.AccountingPeriod, error)) *MockSalesService_ClosePeriod_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Cohorts provides a mock function with given fields: loc
+func (_m *MockSalesService) Cohorts(loc *time.Location) ([]sales.Cohort, error) {
+	ret := _m.Called(loc)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Cohorts")
+	}
+
+	var r0 []sales.Cohort
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*time.Location) ([]sales.Cohort, error)); ok {
+		return rf(loc)
+	}
+	if rf, ok := ret.Get(0).(func(*time.Location) []sales.Cohort); ok {
+		r0 = rf(loc)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sales.Cohort)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*time.Location) error); ok {
+		r1 = rf(loc)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_Cohorts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Cohorts'
+type MockSalesService_Cohorts_Call struct {
+	*mock.Call
+}
+
+// Cohorts is a helper method to define mock.On call
+//   - loc *time.Location
+func (_e *MockSalesService_Expecter) Cohorts(loc interface{}) *MockSalesService_Cohorts_Call {
+	return &MockSalesService_Cohorts_Call{Call: _e.mock.On("Cohorts", loc)}
+}
+
+func (_c *MockSalesService_Cohorts_Call) Run(run func(loc *time.Location)) *MockSalesService_Cohorts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*time.Location))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_Cohorts_Call) Return(_a0 []sales.Cohort, _a1 error) *MockSalesService_Cohorts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_Cohorts_Call) RunAndReturn(run func(*time.Location) ([]sales.Cohort, error)) *MockSalesService_Cohorts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateDraftFromReceipt provides a mock function with given fields: ctx, userID, filename, data, origin, uploadedBy
+func (_m *MockSalesService) CreateDraftFromReceipt(ctx context.Context, userID string, filename string, data []byte, origin sales.Origin, uploadedBy string) (*sales.ReceiptDraft, error) {
+	ret := _m.Called(ctx, userID, filename, data, origin, uploadedBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDraftFromReceipt")
+	}
+
+	var r0 *sales.ReceiptDraft
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []byte, sales.Origin, string) (*sales.ReceiptDraft, error)); ok {
+		return rf(ctx, userID, filename, data, origin, uploadedBy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []byte, sales.Origin, string) *sales.ReceiptDraft); ok {
+		r0 = rf(ctx, userID, filename, data, origin, uploadedBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.ReceiptDraft)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, []byte, sales.Origin, string) error); ok {
+		r1 = rf(ctx, userID, filename, data, origin, uploadedBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_CreateDraftFromReceipt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateDraftFromReceipt'
+type MockSalesService_CreateDraftFromReceipt_Call struct {
+	*mock.Call
+}
+
+// CreateDraftFromReceipt is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+//   - filename string
+//   - data []byte
+//   - origin sales.Origin
+//   - uploadedBy string
+func (_e *MockSalesService_Expecter) CreateDraftFromReceipt(ctx interface{}, userID interface{}, filename interface{}, data interface{}, origin interface{}, uploadedBy interface{}) *MockSalesService_CreateDraftFromReceipt_Call {
+	return &MockSalesService_CreateDraftFromReceipt_Call{Call: _e.mock.On("CreateDraftFromReceipt", ctx, userID, filename, data, origin, uploadedBy)}
+}
+
+func (_c *MockSalesService_CreateDraftFromReceipt_Call) Run(run func(ctx context.Context, userID string, filename string, data []byte, origin sales.Origin, uploadedBy string)) *MockSalesService_CreateDraftFromReceipt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].([]byte), args[4].(sales.Origin), args[5].(string))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_CreateDraftFromReceipt_Call) Return(_a0 *sales.ReceiptDraft, _a1 error) *MockSalesService_CreateDraftFromReceipt_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_CreateDraftFromReceipt_Call) RunAndReturn(run func(context.Context, string, string, []byte, sales.Origin, string) (*sales.ReceiptDraft, error)) *MockSalesService_CreateDraftFromReceipt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateDraftSaleWithOrigin provides a mock function with given fields: userID, amount, metadata, origin
+func (_m *MockSalesService) CreateDraftSaleWithOrigin(userID string, amount float64, metadata map[string]string, origin sales.Origin) (*sales.Sale, error) {
+	ret := _m.Called(userID, amount, metadata, origin)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDraftSaleWithOrigin")
+	}
+
+	var r0 *sales.Sale
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, float64, map[string]string, sales.Origin) (*sales.Sale, error)); ok {
+		return rf(userID, amount, metadata, origin)
+	}
+	if rf, ok := ret.Get(0).(func(string, float64, map[string]string, sales.Origin) *sales.Sale); ok {
+		r0 = rf(userID, amount, metadata, origin)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.Sale)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, float64, map[string]string, sales.Origin) error); ok {
+		r1 = rf(userID, amount, metadata, origin)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_CreateDraftSaleWithOrigin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateDraftSaleWithOrigin'
+type MockSalesService_CreateDraftSaleWithOrigin_Call struct {
+	*mock.Call
+}
+
+// CreateDraftSaleWithOrigin is a helper method to define mock.On call
+//   - userID string
+//   - amount float64
+//   - metadata map[string]string
+//   - origin sales.Origin
+func (_e *MockSalesService_Expecter) CreateDraftSaleWithOrigin(userID interface{}, amount interface{}, metadata interface{}, origin interface{}) *MockSalesService_CreateDraftSaleWithOrigin_Call {
+	return &MockSalesService_CreateDraftSaleWithOrigin_Call{Call: _e.mock.On("CreateDraftSaleWithOrigin", userID, amount, metadata, origin)}
+}
+
+func (_c *MockSalesService_CreateDraftSaleWithOrigin_Call) Run(run func(userID string, amount float64, metadata map[string]string, origin sales.Origin)) *MockSalesService_CreateDraftSaleWithOrigin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(float64), args[2].(map[string]string), args[3].(sales.Origin))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_CreateDraftSaleWithOrigin_Call) Return(_a0 *sales.Sale, _a1 error) *MockSalesService_CreateDraftSaleWithOrigin_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_CreateDraftSaleWithOrigin_Call) RunAndReturn(run func(string, float64, map[string]string, sales.Origin) (*sales.Sale, error)) *MockSalesService_CreateDraftSaleWithOrigin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateSaleWithOrigin provides a mock function with given fields: userID, amount, metadata, origin
+func (_m *MockSalesService) CreateSaleWithOrigin(userID string, amount float64, metadata map[string]string, origin sales.Origin) (*sales.Sale, error) {
+	ret := _m.Called(userID, amount, metadata, origin)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSaleWithOrigin")
+	}
+
+	var r0 *sales.Sale
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, float64, map[string]string, sales.Origin) (*sales.Sale, error)); ok {
+		return rf(userID, amount, metadata, origin)
+	}
+	if rf, ok := ret.Get(0).(func(string, float64, map[string]string, sales.Origin) *sales.Sale); ok {
+		r0 = rf(userID, amount, metadata, origin)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.Sale)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, float64, map[string]string, sales.Origin) error); ok {
+		r1 = rf(userID, amount, metadata, origin)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_CreateSaleWithOrigin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateSaleWithOrigin'
+type MockSalesService_CreateSaleWithOrigin_Call struct {
+	*mock.Call
+}
+
+// CreateSaleWithOrigin is a helper method to define mock.On call
+//   - userID string
+//   - amount float64
+//   - metadata map[string]string
+//   - origin sales.Origin
+func (_e *MockSalesService_Expecter) CreateSaleWithOrigin(userID interface{}, amount interface{}, metadata interface{}, origin interface{}) *MockSalesService_CreateSaleWithOrigin_Call {
+	return &MockSalesService_CreateSaleWithOrigin_Call{Call: _e.mock.On("CreateSaleWithOrigin", userID, amount, metadata, origin)}
+}
+
+func (_c *MockSalesService_CreateSaleWithOrigin_Call) Run(run func(userID string, amount float64, metadata map[string]string, origin sales.Origin)) *MockSalesService_CreateSaleWithOrigin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(float64), args[2].(map[string]string), args[3].(sales.Origin))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_CreateSaleWithOrigin_Call) Return(_a0 *sales.Sale, _a1 error) *MockSalesService_CreateSaleWithOrigin_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_CreateSaleWithOrigin_Call) RunAndReturn(run func(string, float64, map[string]string, sales.Origin) (*sales.Sale, error)) *MockSalesService_CreateSaleWithOrigin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateShareLink provides a mock function with given fields: saleID, ttl, actor, caller
+func (_m *MockSalesService) CreateShareLink(saleID string, ttl time.Duration, actor sales.Actor, caller *sales.Caller) (*sales.ShareLink, error) {
+	ret := _m.Called(saleID, ttl, actor, caller)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateShareLink")
+	}
+
+	var r0 *sales.ShareLink
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Duration, sales.Actor, *sales.Caller) (*sales.ShareLink, error)); ok {
+		return rf(saleID, ttl, actor, caller)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Duration, sales.Actor, *sales.Caller) *sales.ShareLink); ok {
+		r0 = rf(saleID, ttl, actor, caller)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.ShareLink)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Duration, sales.Actor, *sales.Caller) error); ok {
+		r1 = rf(saleID, ttl, actor, caller)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_CreateShareLink_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateShareLink'
+type MockSalesService_CreateShareLink_Call struct {
+	*mock.Call
+}
+
+// CreateShareLink is a helper method to define mock.On call
+//   - saleID string
+//   - ttl time.Duration
+//   - actor sales.Actor
+//   - caller *sales.Caller
+func (_e *MockSalesService_Expecter) CreateShareLink(saleID interface{}, ttl interface{}, actor interface{}, caller interface{}) *MockSalesService_CreateShareLink_Call {
+	return &MockSalesService_CreateShareLink_Call{Call: _e.mock.On("CreateShareLink", saleID, ttl, actor, caller)}
+}
+
+func (_c *MockSalesService_CreateShareLink_Call) Run(run func(saleID string, ttl time.Duration, actor sales.Actor, caller *sales.Caller)) *MockSalesService_CreateShareLink_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(time.Duration), args[2].(sales.Actor), args[3].(*sales.Caller))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_CreateShareLink_Call) Return(_a0 *sales.ShareLink, _a1 error) *MockSalesService_CreateShareLink_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_CreateShareLink_Call) RunAndReturn(run func(string, time.Duration, sales.Actor, *sales.Caller) (*sales.ShareLink, error)) *MockSalesService_CreateShareLink_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CurrentState provides a mock function with given fields: saleID
+func (_m *MockSalesService) CurrentState(saleID string) (sales.SaleState, error) {
+	ret := _m.Called(saleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CurrentState")
+	}
+
+	var r0 sales.SaleState
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (sales.SaleState, error)); ok {
+		return rf(saleID)
+	}
+	if rf, ok := ret.Get(0).(func(string) sales.SaleState); ok {
+		r0 = rf(saleID)
+	} else {
+		r0 = ret.Get(0).(sales.SaleState)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(saleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_CurrentState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CurrentState'
+type MockSalesService_CurrentState_Call struct {
+	*mock.Call
+}
+
+// CurrentState is a helper method to define mock.On call
+//   - saleID string
+func (_e *MockSalesService_Expecter) CurrentState(saleID interface{}) *MockSalesService_CurrentState_Call {
+	return &MockSalesService_CurrentState_Call{Call: _e.mock.On("CurrentState", saleID)}
+}
+
+func (_c *MockSalesService_CurrentState_Call) Run(run func(saleID string)) *MockSalesService_CurrentState_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_CurrentState_Call) Return(_a0 sales.SaleState, _a1 error) *MockSalesService_CurrentState_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_CurrentState_Call) RunAndReturn(run func(string) (sales.SaleState, error)) *MockSalesService_CurrentState_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DailyTotals provides a mock function with given fields: loc
+func (_m *MockSalesService) DailyTotals(loc *time.Location) ([]sales.DailyTotal, error) {
+	ret := _m.Called(loc)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DailyTotals")
+	}
+
+	var r0 []sales.DailyTotal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*time.Location) ([]sales.DailyTotal, error)); ok {
+		return rf(loc)
+	}
+	if rf, ok := ret.Get(0).(func(*time.Location) []sales.DailyTotal); ok {
+		r0 = rf(loc)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sales.DailyTotal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*time.Location) error); ok {
+		r1 = rf(loc)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_DailyTotals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DailyTotals'
+type MockSalesService_DailyTotals_Call struct {
+	*mock.Call
+}
+
+// DailyTotals is a helper method to define mock.On call
+//   - loc *time.Location
+func (_e *MockSalesService_Expecter) DailyTotals(loc interface{}) *MockSalesService_DailyTotals_Call {
+	return &MockSalesService_DailyTotals_Call{Call: _e.mock.On("DailyTotals", loc)}
+}
+
+func (_c *MockSalesService_DailyTotals_Call) Run(run func(loc *time.Location)) *MockSalesService_DailyTotals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*time.Location))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_DailyTotals_Call) Return(_a0 []sales.DailyTotal, _a1 error) *MockSalesService_DailyTotals_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_DailyTotals_Call) RunAndReturn(run func(*time.Location) ([]sales.DailyTotal, error)) *MockSalesService_DailyTotals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteComment provides a mock function with given fields: saleID, commentID, author, moderate
+func (_m *MockSalesService) DeleteComment(saleID string, commentID string, author string, moderate bool) error {
+	ret := _m.Called(saleID, commentID, author, moderate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteComment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, bool) error); ok {
+		r0 = rf(saleID, commentID, author, moderate)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSalesService_DeleteComment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteComment'
+type MockSalesService_DeleteComment_Call struct {
+	*mock.Call
+}
+
+// DeleteComment is a helper method to define mock.On call
+//   - saleID string
+//   - commentID string
+//   - author string
+//   - moderate bool
+func (_e *MockSalesService_Expecter) DeleteComment(saleID interface{}, commentID interface{}, author interface{}, moderate interface{}) *MockSalesService_DeleteComment_Call {
+	return &MockSalesService_DeleteComment_Call{Call: _e.mock.On("DeleteComment", saleID, commentID, author, moderate)}
+}
+
+func (_c *MockSalesService_DeleteComment_Call) Run(run func(saleID string, commentID string, author string, moderate bool)) *MockSalesService_DeleteComment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string), args[3].(bool))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_DeleteComment_Call) Return(_a0 error) *MockSalesService_DeleteComment_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSalesService_DeleteComment_Call) RunAndReturn(run func(string, string, string, bool) error) *MockSalesService_DeleteComment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EditSaleAs provides a mock function with given fields: saleID, edit, actor
+func (_m *MockSalesService) EditSaleAs(saleID string, edit sales.SaleEdit, actor sales.Actor) (*sales.Sale, error) {
+	ret := _m.Called(saleID, edit, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EditSaleAs")
+	}
+
+	var r0 *sales.Sale
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, sales.SaleEdit, sales.Actor) (*sales.Sale, error)); ok {
+		return rf(saleID, edit, actor)
+	}
+	if rf, ok := ret.Get(0).(func(string, sales.SaleEdit, sales.Actor) *sales.Sale); ok {
+		r0 = rf(saleID, edit, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.Sale)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, sales.SaleEdit, sales.Actor) error); ok {
+		r1 = rf(saleID, edit, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_EditSaleAs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EditSaleAs'
+type MockSalesService_EditSaleAs_Call struct {
+	*mock.Call
+}
+
+// EditSaleAs is a helper method to define mock.On call
+//   - saleID string
+//   - edit sales.SaleEdit
+//   - actor sales.Actor
+func (_e *MockSalesService_Expecter) EditSaleAs(saleID interface{}, edit interface{}, actor interface{}) *MockSalesService_EditSaleAs_Call {
+	return &MockSalesService_EditSaleAs_Call{Call: _e.mock.On("EditSaleAs", saleID, edit, actor)}
+}
+
+func (_c *MockSalesService_EditSaleAs_Call) Run(run func(saleID string, edit sales.SaleEdit, actor sales.Actor)) *MockSalesService_EditSaleAs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(sales.SaleEdit), args[2].(sales.Actor))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_EditSaleAs_Call) Return(_a0 *sales.Sale, _a1 error) *MockSalesService_EditSaleAs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_EditSaleAs_Call) RunAndReturn(run func(string, sales.SaleEdit, sales.Actor) (*sales.Sale, error)) *MockSalesService_EditSaleAs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExportAudit provides a mock function with no fields
+func (_m *MockSalesService) ExportAudit() ([]*sales.AuditEntry, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportAudit")
+	}
+
+	var r0 []*sales.AuditEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*sales.AuditEntry, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*sales.AuditEntry); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*sales.AuditEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_ExportAudit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExportAudit'
+type MockSalesService_ExportAudit_Call struct {
+	*mock.Call
+}
+
+// ExportAudit is a helper method to define mock.On call
+func (_e *MockSalesService_Expecter) ExportAudit() *MockSalesService_ExportAudit_Call {
+	return &MockSalesService_ExportAudit_Call{Call: _e.mock.On("ExportAudit")}
+}
+
+func (_c *MockSalesService_ExportAudit_Call) Run(run func()) *MockSalesService_ExportAudit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockSalesService_ExportAudit_Call) Return(_a0 []*sales.AuditEntry, _a1 error) *MockSalesService_ExportAudit_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_ExportAudit_Call) RunAndReturn(run func() ([]*sales.AuditEntry, error)) *MockSalesService_ExportAudit_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAttachmentFile provides a mock function with given fields: ctx, saleID, attachmentID
+func (_m *MockSalesService) GetAttachmentFile(ctx context.Context, saleID string, attachmentID string) (*sales.Attachment, []byte, error) {
+	ret := _m.Called(ctx, saleID, attachmentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAttachmentFile")
+	}
+
+	var r0 *sales.Attachment
+	var r1 []byte
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*sales.Attachment, []byte, error)); ok {
+		return rf(ctx, saleID, attachmentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *sales.Attachment); ok {
+		r0 = rf(ctx, saleID, attachmentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) []byte); ok {
+		r1 = rf(ctx, saleID, attachmentID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string) error); ok {
+		r2 = rf(ctx, saleID, attachmentID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockSalesService_GetAttachmentFile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAttachmentFile'
+type MockSalesService_GetAttachmentFile_Call struct {
+	*mock.Call
+}
+
+// GetAttachmentFile is a helper method to define mock.On call
+//   - ctx context.Context
+//   - saleID string
+//   - attachmentID string
+func (_e *MockSalesService_Expecter) GetAttachmentFile(ctx interface{}, saleID interface{}, attachmentID interface{}) *MockSalesService_GetAttachmentFile_Call {
+	return &MockSalesService_GetAttachmentFile_Call{Call: _e.mock.On("GetAttachmentFile", ctx, saleID, attachmentID)}
+}
+
+func (_c *MockSalesService_GetAttachmentFile_Call) Run(run func(ctx context.Context, saleID string, attachmentID string)) *MockSalesService_GetAttachmentFile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_GetAttachmentFile_Call) Return(_a0 *sales.Attachment, _a1 []byte, _a2 error) *MockSalesService_GetAttachmentFile_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockSalesService_GetAttachmentFile_Call) RunAndReturn(run func(context.Context, string, string) (*sales.Attachment, []byte, error)) *MockSalesService_GetAttachmentFile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPeriodSnapshot provides a mock function with given fields: ctx, period
+func (_m *MockSalesService) GetPeriodSnapshot(ctx context.Context, period string) (*sales.PeriodSnapshot, error) {
+	ret := _m.Called(ctx, period)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPeriodSnapshot")
+	}
+
+	var r0 *sales.PeriodSnapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*sales.PeriodSnapshot, error)); ok {
+		return rf(ctx, period)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *sales.PeriodSnapshot); ok {
+		r0 = rf(ctx, period)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.PeriodSnapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, period)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_GetPeriodSnapshot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPeriodSnapshot'
+type MockSalesService_GetPeriodSnapshot_Call struct {
+	*mock.Call
+}
+
+// GetPeriodSnapshot is a helper method to define mock.On call
+//   - ctx context.Context
+//   - period string
+func (_e *MockSalesService_Expecter) GetPeriodSnapshot(ctx interface{}, period interface{}) *MockSalesService_GetPeriodSnapshot_Call {
+	return &MockSalesService_GetPeriodSnapshot_Call{Call: _e.mock.On("GetPeriodSnapshot", ctx, period)}
+}
+
+func (_c *MockSalesService_GetPeriodSnapshot_Call) Run(run func(ctx context.Context, period string)) *MockSalesService_GetPeriodSnapshot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_GetPeriodSnapshot_Call) Return(_a0 *sales.PeriodSnapshot, _a1 error) *MockSalesService_GetPeriodSnapshot_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_GetPeriodSnapshot_Call) RunAndReturn(run func(context.Context, string) (*sales.PeriodSnapshot, error)) *MockSalesService_GetPeriodSnapshot_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPeriodSnapshotExport provides a mock function with given fields: ctx, period
+func (_m *MockSalesService) GetPeriodSnapshotExport(ctx context.Context, period string) ([]byte, *sales.PeriodSnapshot, error) {
+	ret := _m.Called(ctx, period)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPeriodSnapshotExport")
+	}
+
+	var r0 []byte
+	var r1 *sales.PeriodSnapshot
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]byte, *sales.PeriodSnapshot, error)); ok {
+		return rf(ctx, period)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []byte); ok {
+		r0 = rf(ctx, period)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *sales.PeriodSnapshot); ok {
+		r1 = rf(ctx, period)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*sales.PeriodSnapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, period)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockSalesService_GetPeriodSnapshotExport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPeriodSnapshotExport'
+type MockSalesService_GetPeriodSnapshotExport_Call struct {
+	*mock.Call
+}
+
+// GetPeriodSnapshotExport is a helper method to define mock.On call
+//   - ctx context.Context
+//   - period string
+func (_e *MockSalesService_Expecter) GetPeriodSnapshotExport(ctx interface{}, period interface{}) *MockSalesService_GetPeriodSnapshotExport_Call {
+	return &MockSalesService_GetPeriodSnapshotExport_Call{Call: _e.mock.On("GetPeriodSnapshotExport", ctx, period)}
+}
+
+func (_c *MockSalesService_GetPeriodSnapshotExport_Call) Run(run func(ctx context.Context, period string)) *MockSalesService_GetPeriodSnapshotExport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_GetPeriodSnapshotExport_Call) Return(_a0 []byte, _a1 *sales.PeriodSnapshot, _a2 error) *MockSalesService_GetPeriodSnapshotExport_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockSalesService_GetPeriodSnapshotExport_Call) RunAndReturn(run func(context.Context, string) ([]byte, *sales.PeriodSnapshot, error)) *MockSalesService_GetPeriodSnapshotExport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSale provides a mock function with given fields: saleID, caller
+func (_m *MockSalesService) GetSale(saleID string, caller *sales.Caller) (*sales.Sale, error) {
+	ret := _m.Called(saleID, caller)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSale")
+	}
+
+	var r0 *sales.Sale
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, *sales.Caller) (*sales.Sale, error)); ok {
+		return rf(saleID, caller)
+	}
+	if rf, ok := ret.Get(0).(func(string, *sales.Caller) *sales.Sale); ok {
+		r0 = rf(saleID, caller)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.Sale)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, *sales.Caller) error); ok {
+		r1 = rf(saleID, caller)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_GetSale_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSale'
+type MockSalesService_GetSale_Call struct {
+	*mock.Call
+}
+
+// GetSale is a helper method to define mock.On call
+//   - saleID string
+//   - caller *sales.Caller
+func (_e *MockSalesService_Expecter) GetSale(saleID interface{}, caller interface{}) *MockSalesService_GetSale_Call {
+	return &MockSalesService_GetSale_Call{Call: _e.mock.On("GetSale", saleID, caller)}
+}
+
+func (_c *MockSalesService_GetSale_Call) Run(run func(saleID string, caller *sales.Caller)) *MockSalesService_GetSale_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(*sales.Caller))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_GetSale_Call) Return(_a0 *sales.Sale, _a1 error) *MockSalesService_GetSale_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_GetSale_Call) RunAndReturn(run func(string, *sales.Caller) (*sales.Sale, error)) *MockSalesService_GetSale_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSaleAdjustments provides a mock function with given fields: saleID
+func (_m *MockSalesService) GetSaleAdjustments(saleID string) ([]*sales.Adjustment, error) {
+	ret := _m.Called(saleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSaleAdjustments")
+	}
+
+	var r0 []*sales.Adjustment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]*sales.Adjustment, error)); ok {
+		return rf(saleID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []*sales.Adjustment); ok {
+		r0 = rf(saleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*sales.Adjustment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(saleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_GetSaleAdjustments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSaleAdjustments'
+type MockSalesService_GetSaleAdjustments_Call struct {
+	*mock.Call
+}
+
+// GetSaleAdjustments is a helper method to define mock.On call
+//   - saleID string
+func (_e *MockSalesService_Expecter) GetSaleAdjustments(saleID interface{}) *MockSalesService_GetSaleAdjustments_Call {
+	return &MockSalesService_GetSaleAdjustments_Call{Call: _e.mock.On("GetSaleAdjustments", saleID)}
+}
+
+func (_c *MockSalesService_GetSaleAdjustments_Call) Run(run func(saleID string)) *MockSalesService_GetSaleAdjustments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_GetSaleAdjustments_Call) Return(_a0 []*sales.Adjustment, _a1 error) *MockSalesService_GetSaleAdjustments_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_GetSaleAdjustments_Call) RunAndReturn(run func(string) ([]*sales.Adjustment, error)) *MockSalesService_GetSaleAdjustments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSaleAttachments provides a mock function with given fields: saleID
+func (_m *MockSalesService) GetSaleAttachments(saleID string) ([]*sales.Attachment, error) {
+	ret := _m.Called(saleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSaleAttachments")
+	}
+
+	var r0 []*sales.Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]*sales.Attachment, error)); ok {
+		return rf(saleID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []*sales.Attachment); ok {
+		r0 = rf(saleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*sales.Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(saleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_GetSaleAttachments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSaleAttachments'
+type MockSalesService_GetSaleAttachments_Call struct {
+	*mock.Call
+}
+
+// GetSaleAttachments is a helper method to define mock.On call
+//   - saleID string
+func (_e *MockSalesService_Expecter) GetSaleAttachments(saleID interface{}) *MockSalesService_GetSaleAttachments_Call {
+	return &MockSalesService_GetSaleAttachments_Call{Call: _e.mock.On("GetSaleAttachments", saleID)}
+}
+
+func (_c *MockSalesService_GetSaleAttachments_Call) Run(run func(saleID string)) *MockSalesService_GetSaleAttachments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_GetSaleAttachments_Call) Return(_a0 []*sales.Attachment, _a1 error) *MockSalesService_GetSaleAttachments_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_GetSaleAttachments_Call) RunAndReturn(run func(string) ([]*sales.Attachment, error)) *MockSalesService_GetSaleAttachments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSaleAudit provides a mock function with given fields: saleID, caller
+func (_m *MockSalesService) GetSaleAudit(saleID string, caller *sales.Caller) ([]*sales.AuditEntry, error) {
+	ret := _m.Called(saleID, caller)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSaleAudit")
+	}
+
+	var r0 []*sales.AuditEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, *sales.Caller) ([]*sales.AuditEntry, error)); ok {
+		return rf(saleID, caller)
+	}
+	if rf, ok := ret.Get(0).(func(string, *sales.Caller) []*sales.AuditEntry); ok {
+		r0 = rf(saleID, caller)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*sales.AuditEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, *sales.Caller) error); ok {
+		r1 = rf(saleID, caller)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_GetSaleAudit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSaleAudit'
+type MockSalesService_GetSaleAudit_Call struct {
+	*mock.Call
+}
+
+// GetSaleAudit is a helper method to define mock.On call
+//   - saleID string
+//   - caller *sales.Caller
+func (_e *MockSalesService_Expecter) GetSaleAudit(saleID interface{}, caller interface{}) *MockSalesService_GetSaleAudit_Call {
+	return &MockSalesService_GetSaleAudit_Call{Call: _e.mock.On("GetSaleAudit", saleID, caller)}
+}
+
+func (_c *MockSalesService_GetSaleAudit_Call) Run(run func(saleID string, caller *sales.Caller)) *MockSalesService_GetSaleAudit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(*sales.Caller))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_GetSaleAudit_Call) Return(_a0 []*sales.AuditEntry, _a1 error) *MockSalesService_GetSaleAudit_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_GetSaleAudit_Call) RunAndReturn(run func(string, *sales.Caller) ([]*sales.AuditEntry, error)) *MockSalesService_GetSaleAudit_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSaleByReference provides a mock function with given fields: reference, caller
+func (_m *MockSalesService) GetSaleByReference(reference string, caller *sales.Caller) (*sales.Sale, error) {
+	ret := _m.Called(reference, caller)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSaleByReference")
+	}
+
+	var r0 *sales.Sale
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, *sales.Caller) (*sales.Sale, error)); ok {
+		return rf(reference, caller)
+	}
+	if rf, ok := ret.Get(0).(func(string, *sales.Caller) *sales.Sale); ok {
+		r0 = rf(reference, caller)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.Sale)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, *sales.Caller) error); ok {
+		r1 = rf(reference, caller)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_GetSaleByReference_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSaleByReference'
+type MockSalesService_GetSaleByReference_Call struct {
+	*mock.Call
+}
+
+// GetSaleByReference is a helper method to define mock.On call
+//   - reference string
+//   - caller *sales.Caller
+func (_e *MockSalesService_Expecter) GetSaleByReference(reference interface{}, caller interface{}) *MockSalesService_GetSaleByReference_Call {
+	return &MockSalesService_GetSaleByReference_Call{Call: _e.mock.On("GetSaleByReference", reference, caller)}
+}
+
+func (_c *MockSalesService_GetSaleByReference_Call) Run(run func(reference string, caller *sales.Caller)) *MockSalesService_GetSaleByReference_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(*sales.Caller))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_GetSaleByReference_Call) Return(_a0 *sales.Sale, _a1 error) *MockSalesService_GetSaleByReference_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_GetSaleByReference_Call) RunAndReturn(run func(string, *sales.Caller) (*sales.Sale, error)) *MockSalesService_GetSaleByReference_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSaleComments provides a mock function with given fields: saleID
+func (_m *MockSalesService) GetSaleComments(saleID string) ([]*sales.Comment, error) {
+	ret := _m.Called(saleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSaleComments")
+	}
+
+	var r0 []*sales.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]*sales.Comment, error)); ok {
+		return rf(saleID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []*sales.Comment); ok {
+		r0 = rf(saleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*sales.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(saleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_GetSaleComments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSaleComments'
+type MockSalesService_GetSaleComments_Call struct {
+	*mock.Call
+}
+
+// GetSaleComments is a helper method to define mock.On call
+//   - saleID string
+func (_e *MockSalesService_Expecter) GetSaleComments(saleID interface{}) *MockSalesService_GetSaleComments_Call {
+	return &MockSalesService_GetSaleComments_Call{Call: _e.mock.On("GetSaleComments", saleID)}
+}
+
+func (_c *MockSalesService_GetSaleComments_Call) Run(run func(saleID string)) *MockSalesService_GetSaleComments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_GetSaleComments_Call) Return(_a0 []*sales.Comment, _a1 error) *MockSalesService_GetSaleComments_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_GetSaleComments_Call) RunAndReturn(run func(string) ([]*sales.Comment, error)) *MockSalesService_GetSaleComments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSaleDisputes provides a mock function with given fields: saleID
+func (_m *MockSalesService) GetSaleDisputes(saleID string) ([]*sales.Dispute, error) {
+	ret := _m.Called(saleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSaleDisputes")
+	}
+
+	var r0 []*sales.Dispute
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]*sales.Dispute, error)); ok {
+		return rf(saleID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []*sales.Dispute); ok {
+		r0 = rf(saleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*sales.Dispute)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(saleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_GetSaleDisputes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSaleDisputes'
+type MockSalesService_GetSaleDisputes_Call struct {
+	*mock.Call
+}
+
+// GetSaleDisputes is a helper method to define mock.On call
+//   - saleID string
+func (_e *MockSalesService_Expecter) GetSaleDisputes(saleID interface{}) *MockSalesService_GetSaleDisputes_Call {
+	return &MockSalesService_GetSaleDisputes_Call{Call: _e.mock.On("GetSaleDisputes", saleID)}
+}
+
+func (_c *MockSalesService_GetSaleDisputes_Call) Run(run func(saleID string)) *MockSalesService_GetSaleDisputes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_GetSaleDisputes_Call) Return(_a0 []*sales.Dispute, _a1 error) *MockSalesService_GetSaleDisputes_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_GetSaleDisputes_Call) RunAndReturn(run func(string) ([]*sales.Dispute, error)) *MockSalesService_GetSaleDisputes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSaleShareLinks provides a mock function with given fields: saleID, caller
+func (_m *MockSalesService) GetSaleShareLinks(saleID string, caller *sales.Caller) ([]*sales.ShareLink, error) {
+	ret := _m.Called(saleID, caller)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSaleShareLinks")
+	}
+
+	var r0 []*sales.ShareLink
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, *sales.Caller) ([]*sales.ShareLink, error)); ok {
+		return rf(saleID, caller)
+	}
+	if rf, ok := ret.Get(0).(func(string, *sales.Caller) []*sales.ShareLink); ok {
+		r0 = rf(saleID, caller)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*sales.ShareLink)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, *sales.Caller) error); ok {
+		r1 = rf(saleID, caller)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_GetSaleShareLinks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSaleShareLinks'
+type MockSalesService_GetSaleShareLinks_Call struct {
+	*mock.Call
+}
+
+// GetSaleShareLinks is a helper method to define mock.On call
+//   - saleID string
+//   - caller *sales.Caller
+func (_e *MockSalesService_Expecter) GetSaleShareLinks(saleID interface{}, caller interface{}) *MockSalesService_GetSaleShareLinks_Call {
+	return &MockSalesService_GetSaleShareLinks_Call{Call: _e.mock.On("GetSaleShareLinks", saleID, caller)}
+}
+
+func (_c *MockSalesService_GetSaleShareLinks_Call) Run(run func(saleID string, caller *sales.Caller)) *MockSalesService_GetSaleShareLinks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(*sales.Caller))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_GetSaleShareLinks_Call) Return(_a0 []*sales.ShareLink, _a1 error) *MockSalesService_GetSaleShareLinks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_GetSaleShareLinks_Call) RunAndReturn(run func(string, *sales.Caller) ([]*sales.ShareLink, error)) *MockSalesService_GetSaleShareLinks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSales provides a mock function with given fields: ids, caller, consistency
+func (_m *MockSalesService) GetSales(ids []string, caller *sales.Caller, consistency sales.Consistency) ([]*sales.Sale, []string, error) {
+	ret := _m.Called(ids, caller, consistency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSales")
+	}
+
+	var r0 []*sales.Sale
+	var r1 []string
+	var r2 error
+	if rf, ok := ret.Get(0).(func([]string, *sales.Caller, sales.Consistency) ([]*sales.Sale, []string, error)); ok {
+		return rf(ids, caller, consistency)
+	}
+	if rf, ok := ret.Get(0).(func([]string, *sales.Caller, sales.Consistency) []*sales.Sale); ok {
+		r0 = rf(ids, caller, consistency)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*sales.Sale)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]string, *sales.Caller, sales.Consistency) []string); ok {
+		r1 = rf(ids, caller, consistency)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func([]string, *sales.Caller, sales.Consistency) error); ok {
+		r2 = rf(ids, caller, consistency)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockSalesService_GetSales_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSales'
+type MockSalesService_GetSales_Call struct {
+	*mock.Call
+}
+
+// GetSales is a helper method to define mock.On call
+//   - ids []string
+//   - caller *sales.Caller
+//   - consistency sales.Consistency
+func (_e *MockSalesService_Expecter) GetSales(ids interface{}, caller interface{}, consistency interface{}) *MockSalesService_GetSales_Call {
+	return &MockSalesService_GetSales_Call{Call: _e.mock.On("GetSales", ids, caller, consistency)}
+}
+
+func (_c *MockSalesService_GetSales_Call) Run(run func(ids []string, caller *sales.Caller, consistency sales.Consistency)) *MockSalesService_GetSales_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].([]string), args[1].(*sales.Caller), args[2].(sales.Consistency))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_GetSales_Call) Return(found []*sales.Sale, missing []string, err error) *MockSalesService_GetSales_Call {
+	_c.Call.Return(found, missing, err)
+	return _c
+}
+
+func (_c *MockSalesService_GetSales_Call) RunAndReturn(run func([]string, *sales.Caller, sales.Consistency) ([]*sales.Sale, []string, error)) *MockSalesService_GetSales_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetStats provides a mock function with no fields
+func (_m *MockSalesService) GetStats() (sales.SalesMetadata, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStats")
+	}
+
+	var r0 sales.SalesMetadata
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (sales.SalesMetadata, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() sales.SalesMetadata); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(sales.SalesMetadata)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_GetStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetStats'
+type MockSalesService_GetStats_Call struct {
+	*mock.Call
+}
+
+// GetStats is a helper method to define mock.On call
+func (_e *MockSalesService_Expecter) GetStats() *MockSalesService_GetStats_Call {
+	return &MockSalesService_GetStats_Call{Call: _e.mock.On("GetStats")}
+}
+
+func (_c *MockSalesService_GetStats_Call) Run(run func()) *MockSalesService_GetStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockSalesService_GetStats_Call) Return(_a0 sales.SalesMetadata, _a1 error) *MockSalesService_GetStats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_GetStats_Call) RunAndReturn(run func() (sales.SalesMetadata, error)) *MockSalesService_GetStats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserSummary provides a mock function with given fields: userID
+func (_m *MockSalesService) GetUserSummary(userID string) (*sales.UserSummary, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserSummary")
+	}
+
+	var r0 *sales.UserSummary
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*sales.UserSummary, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *sales.UserSummary); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.UserSummary)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_GetUserSummary_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserSummary'
+type MockSalesService_GetUserSummary_Call struct {
+	*mock.Call
+}
+
+// GetUserSummary is a helper method to define mock.On call
+//   - userID string
+func (_e *MockSalesService_Expecter) GetUserSummary(userID interface{}) *MockSalesService_GetUserSummary_Call {
+	return &MockSalesService_GetUserSummary_Call{Call: _e.mock.On("GetUserSummary", userID)}
+}
+
+func (_c *MockSalesService_GetUserSummary_Call) Run(run func(userID string)) *MockSalesService_GetUserSummary_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_GetUserSummary_Call) Return(_a0 *sales.UserSummary, _a1 error) *MockSalesService_GetUserSummary_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_GetUserSummary_Call) RunAndReturn(run func(string) (*sales.UserSummary, error)) *MockSalesService_GetUserSummary_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Ledger provides a mock function with given fields: from, to
+func (_m *MockSalesService) Ledger(from *time.Time, to *time.Time) ([]sales.LedgerLine, error) {
+	ret := _m.Called(from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ledger")
+	}
+
+	var r0 []sales.LedgerLine
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*time.Time, *time.Time) ([]sales.LedgerLine, error)); ok {
+		return rf(from, to)
+	}
+	if rf, ok := ret.Get(0).(func(*time.Time, *time.Time) []sales.LedgerLine); ok {
+		r0 = rf(from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sales.LedgerLine)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*time.Time, *time.Time) error); ok {
+		r1 = rf(from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_Ledger_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Ledger'
+type MockSalesService_Ledger_Call struct {
+	*mock.Call
+}
+
+// Ledger is a helper method to define mock.On call
+//   - from *time.Time
+//   - to *time.Time
+func (_e *MockSalesService_Expecter) Ledger(from interface{}, to interface{}) *MockSalesService_Ledger_Call {
+	return &MockSalesService_Ledger_Call{Call: _e.mock.On("Ledger", from, to)}
+}
+
+func (_c *MockSalesService_Ledger_Call) Run(run func(from *time.Time, to *time.Time)) *MockSalesService_Ledger_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*time.Time), args[1].(*time.Time))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_Ledger_Call) Return(_a0 []sales.LedgerLine, _a1 error) *MockSalesService_Ledger_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_Ledger_Call) RunAndReturn(run func(*time.Time, *time.Time) ([]sales.LedgerLine, error)) *MockSalesService_Ledger_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListClosedPeriods provides a mock function with no fields
+func (_m *MockSalesService) ListClosedPeriods() ([]*sales.AccountingPeriod, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListClosedPeriods")
+	}
+
+	var r0 []*sales.AccountingPeriod
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*sales.AccountingPeriod, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*sales.AccountingPeriod); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*sales.AccountingPeriod)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_ListClosedPeriods_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListClosedPeriods'
+type MockSalesService_ListClosedPeriods_Call struct {
+	*mock.Call
+}
+
+// ListClosedPeriods is a helper method to define mock.On call
+func (_e *MockSalesService_Expecter) ListClosedPeriods() *MockSalesService_ListClosedPeriods_Call {
+	return &MockSalesService_ListClosedPeriods_Call{Call: _e.mock.On("ListClosedPeriods")}
+}
+
+func (_c *MockSalesService_ListClosedPeriods_Call) Run(run func()) *MockSalesService_ListClosedPeriods_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockSalesService_ListClosedPeriods_Call) Return(_a0 []*sales.AccountingPeriod, _a1 error) *MockSalesService_ListClosedPeriods_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_ListClosedPeriods_Call) RunAndReturn(run func() ([]*sales.AccountingPeriod, error)) *MockSalesService_ListClosedPeriods_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListUserSales provides a mock function with given fields: userID, limit, cursor
+func (_m *MockSalesService) ListUserSales(userID string, limit int, cursor string) (sales.Page, error) {
+	ret := _m.Called(userID, limit, cursor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUserSales")
+	}
+
+	var r0 sales.Page
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int, string) (sales.Page, error)); ok {
+		return rf(userID, limit, cursor)
+	}
+	if rf, ok := ret.Get(0).(func(string, int, string) sales.Page); ok {
+		r0 = rf(userID, limit, cursor)
+	} else {
+		r0 = ret.Get(0).(sales.Page)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int, string) error); ok {
+		r1 = rf(userID, limit, cursor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_ListUserSales_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListUserSales'
+type MockSalesService_ListUserSales_Call struct {
+	*mock.Call
+}
+
+// ListUserSales is a helper method to define mock.On call
+//   - userID string
+//   - limit int
+//   - cursor string
+func (_e *MockSalesService_Expecter) ListUserSales(userID interface{}, limit interface{}, cursor interface{}) *MockSalesService_ListUserSales_Call {
+	return &MockSalesService_ListUserSales_Call{Call: _e.mock.On("ListUserSales", userID, limit, cursor)}
+}
+
+func (_c *MockSalesService_ListUserSales_Call) Run(run func(userID string, limit int, cursor string)) *MockSalesService_ListUserSales_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_ListUserSales_Call) Return(_a0 sales.Page, _a1 error) *MockSalesService_ListUserSales_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_ListUserSales_Call) RunAndReturn(run func(string, int, string) (sales.Page, error)) *MockSalesService_ListUserSales_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// OpenDispute provides a mock function with given fields: saleID, reason, openedBy
+func (_m *MockSalesService) OpenDispute(saleID string, reason string, openedBy sales.Actor) (*sales.Dispute, error) {
+	ret := _m.Called(saleID, reason, openedBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OpenDispute")
+	}
+
+	var r0 *sales.Dispute
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, sales.Actor) (*sales.Dispute, error)); ok {
+		return rf(saleID, reason, openedBy)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, sales.Actor) *sales.Dispute); ok {
+		r0 = rf(saleID, reason, openedBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.Dispute)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, sales.Actor) error); ok {
+		r1 = rf(saleID, reason, openedBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_OpenDispute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OpenDispute'
+type MockSalesService_OpenDispute_Call struct {
+	*mock.Call
+}
+
+// OpenDispute is a helper method to define mock.On call
+//   - saleID string
+//   - reason string
+//   - openedBy sales.Actor
+func (_e *MockSalesService_Expecter) OpenDispute(saleID interface{}, reason interface{}, openedBy interface{}) *MockSalesService_OpenDispute_Call {
+	return &MockSalesService_OpenDispute_Call{Call: _e.mock.On("OpenDispute", saleID, reason, openedBy)}
+}
+
+func (_c *MockSalesService_OpenDispute_Call) Run(run func(saleID string, reason string, openedBy sales.Actor)) *MockSalesService_OpenDispute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(sales.Actor))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_OpenDispute_Call) Return(_a0 *sales.Dispute, _a1 error) *MockSalesService_OpenDispute_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_OpenDispute_Call) RunAndReturn(run func(string, string, sales.Actor) (*sales.Dispute, error)) *MockSalesService_OpenDispute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PendingVerifications provides a mock function with given fields: stuckOnly
+func (_m *MockSalesService) PendingVerifications(stuckOnly bool) ([]sales.PendingVerification, error) {
+	ret := _m.Called(stuckOnly)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PendingVerifications")
+	}
+
+	var r0 []sales.PendingVerification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(bool) ([]sales.PendingVerification, error)); ok {
+		return rf(stuckOnly)
+	}
+	if rf, ok := ret.Get(0).(func(bool) []sales.PendingVerification); ok {
+		r0 = rf(stuckOnly)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sales.PendingVerification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(bool) error); ok {
+		r1 = rf(stuckOnly)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_PendingVerifications_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PendingVerifications'
+type MockSalesService_PendingVerifications_Call struct {
+	*mock.Call
+}
+
+// PendingVerifications is a helper method to define mock.On call
+//   - stuckOnly bool
+func (_e *MockSalesService_Expecter) PendingVerifications(stuckOnly interface{}) *MockSalesService_PendingVerifications_Call {
+	return &MockSalesService_PendingVerifications_Call{Call: _e.mock.On("PendingVerifications", stuckOnly)}
+}
+
+func (_c *MockSalesService_PendingVerifications_Call) Run(run func(stuckOnly bool)) *MockSalesService_PendingVerifications_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(bool))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_PendingVerifications_Call) Return(_a0 []sales.PendingVerification, _a1 error) *MockSalesService_PendingVerifications_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_PendingVerifications_Call) RunAndReturn(run func(bool) ([]sales.PendingVerification, error)) *MockSalesService_PendingVerifications_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PublicSaleStatus provides a mock function with given fields: reference
+func (_m *MockSalesService) PublicSaleStatus(reference string) (sales.PublicStatus, error) {
+	ret := _m.Called(reference)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublicSaleStatus")
+	}
+
+	var r0 sales.PublicStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (sales.PublicStatus, error)); ok {
+		return rf(reference)
+	}
+	if rf, ok := ret.Get(0).(func(string) sales.PublicStatus); ok {
+		r0 = rf(reference)
+	} else {
+		r0 = ret.Get(0).(sales.PublicStatus)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(reference)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_PublicSaleStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PublicSaleStatus'
+type MockSalesService_PublicSaleStatus_Call struct {
+	*mock.Call
+}
+
+// PublicSaleStatus is a helper method to define mock.On call
+//   - reference string
+func (_e *MockSalesService_Expecter) PublicSaleStatus(reference interface{}) *MockSalesService_PublicSaleStatus_Call {
+	return &MockSalesService_PublicSaleStatus_Call{Call: _e.mock.On("PublicSaleStatus", reference)}
+}
+
+func (_c *MockSalesService_PublicSaleStatus_Call) Run(run func(reference string)) *MockSalesService_PublicSaleStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_PublicSaleStatus_Call) Return(_a0 sales.PublicStatus, _a1 error) *MockSalesService_PublicSaleStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_PublicSaleStatus_Call) RunAndReturn(run func(string) (sales.PublicStatus, error)) *MockSalesService_PublicSaleStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RedactSensitive provides a mock function with given fields: sale
+func (_m *MockSalesService) RedactSensitive(sale *sales.Sale) *sales.Sale {
+	ret := _m.Called(sale)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RedactSensitive")
+	}
+
+	var r0 *sales.Sale
+	if rf, ok := ret.Get(0).(func(*sales.Sale) *sales.Sale); ok {
+		r0 = rf(sale)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.Sale)
+		}
+	}
+
+	return r0
+}
+
+// MockSalesService_RedactSensitive_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RedactSensitive'
+type MockSalesService_RedactSensitive_Call struct {
+	*mock.Call
+}
+
+// RedactSensitive is a helper method to define mock.On call
+//   - sale *sales.Sale
+func (_e *MockSalesService_Expecter) RedactSensitive(sale interface{}) *MockSalesService_RedactSensitive_Call {
+	return &MockSalesService_RedactSensitive_Call{Call: _e.mock.On("RedactSensitive", sale)}
+}
+
+func (_c *MockSalesService_RedactSensitive_Call) Run(run func(sale *sales.Sale)) *MockSalesService_RedactSensitive_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*sales.Sale))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_RedactSensitive_Call) Return(_a0 *sales.Sale) *MockSalesService_RedactSensitive_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSalesService_RedactSensitive_Call) RunAndReturn(run func(*sales.Sale) *sales.Sale) *MockSalesService_RedactSensitive_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RepeatedStatusChange provides a mock function with given fields: saleID, newStatus, window
+func (_m *MockSalesService) RepeatedStatusChange(saleID string, newStatus string, window time.Duration) (*sales.Sale, bool) {
+	ret := _m.Called(saleID, newStatus, window)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RepeatedStatusChange")
+	}
+
+	var r0 *sales.Sale
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(string, string, time.Duration) (*sales.Sale, bool)); ok {
+		return rf(saleID, newStatus, window)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, time.Duration) *sales.Sale); ok {
+		r0 = rf(saleID, newStatus, window)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.Sale)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, time.Duration) bool); ok {
+		r1 = rf(saleID, newStatus, window)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_RepeatedStatusChange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RepeatedStatusChange'
+type MockSalesService_RepeatedStatusChange_Call struct {
+	*mock.Call
+}
+
+// RepeatedStatusChange is a helper method to define mock.On call
+//   - saleID string
+//   - newStatus string
+//   - window time.Duration
+func (_e *MockSalesService_Expecter) RepeatedStatusChange(saleID interface{}, newStatus interface{}, window interface{}) *MockSalesService_RepeatedStatusChange_Call {
+	return &MockSalesService_RepeatedStatusChange_Call{Call: _e.mock.On("RepeatedStatusChange", saleID, newStatus, window)}
+}
+
+func (_c *MockSalesService_RepeatedStatusChange_Call) Run(run func(saleID string, newStatus string, window time.Duration)) *MockSalesService_RepeatedStatusChange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_RepeatedStatusChange_Call) Return(_a0 *sales.Sale, _a1 bool) *MockSalesService_RepeatedStatusChange_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_RepeatedStatusChange_Call) RunAndReturn(run func(string, string, time.Duration) (*sales.Sale, bool)) *MockSalesService_RepeatedStatusChange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResolveDispute provides a mock function with given fields: disputeID, outcome, resolvedBy
+func (_m *MockSalesService) ResolveDispute(disputeID string, outcome string, resolvedBy sales.Actor) (*sales.Dispute, error) {
+	ret := _m.Called(disputeID, outcome, resolvedBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveDispute")
+	}
+
+	var r0 *sales.Dispute
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, sales.Actor) (*sales.Dispute, error)); ok {
+		return rf(disputeID, outcome, resolvedBy)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, sales.Actor) *sales.Dispute); ok {
+		r0 = rf(disputeID, outcome, resolvedBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.Dispute)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, sales.Actor) error); ok {
+		r1 = rf(disputeID, outcome, resolvedBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_ResolveDispute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResolveDispute'
+type MockSalesService_ResolveDispute_Call struct {
+	*mock.Call
+}
+
+// ResolveDispute is a helper method to define mock.On call
+//   - disputeID string
+//   - outcome string
+//   - resolvedBy sales.Actor
+func (_e *MockSalesService_Expecter) ResolveDispute(disputeID interface{}, outcome interface{}, resolvedBy interface{}) *MockSalesService_ResolveDispute_Call {
+	return &MockSalesService_ResolveDispute_Call{Call: _e.mock.On("ResolveDispute", disputeID, outcome, resolvedBy)}
+}
+
+func (_c *MockSalesService_ResolveDispute_Call) Run(run func(disputeID string, outcome string, resolvedBy sales.Actor)) *MockSalesService_ResolveDispute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(sales.Actor))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_ResolveDispute_Call) Return(_a0 *sales.Dispute, _a1 error) *MockSalesService_ResolveDispute_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_ResolveDispute_Call) RunAndReturn(run func(string, string, sales.Actor) (*sales.Dispute, error)) *MockSalesService_ResolveDispute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RetryVerification provides a mock function with given fields: saleID
+func (_m *MockSalesService) RetryVerification(saleID string) (*sales.Verification, error) {
+	ret := _m.Called(saleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RetryVerification")
+	}
+
+	var r0 *sales.Verification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*sales.Verification, error)); ok {
+		return rf(saleID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *sales.Verification); ok {
+		r0 = rf(saleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.Verification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(saleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_RetryVerification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RetryVerification'
+type MockSalesService_RetryVerification_Call struct {
+	*mock.Call
+}
+
+// RetryVerification is a helper method to define mock.On call
+//   - saleID string
+func (_e *MockSalesService_Expecter) RetryVerification(saleID interface{}) *MockSalesService_RetryVerification_Call {
+	return &MockSalesService_RetryVerification_Call{Call: _e.mock.On("RetryVerification", saleID)}
+}
+
+func (_c *MockSalesService_RetryVerification_Call) Run(run func(saleID string)) *MockSalesService_RetryVerification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_RetryVerification_Call) Return(_a0 *sales.Verification, _a1 error) *MockSalesService_RetryVerification_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_RetryVerification_Call) RunAndReturn(run func(string) (*sales.Verification, error)) *MockSalesService_RetryVerification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReviewQueue provides a mock function with given fields: reviewer
+func (_m *MockSalesService) ReviewQueue(reviewer string) ([]sales.ReviewItem, error) {
+	ret := _m.Called(reviewer)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReviewQueue")
+	}
+
+	var r0 []sales.ReviewItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]sales.ReviewItem, error)); ok {
+		return rf(reviewer)
+	}
+	if rf, ok := ret.Get(0).(func(string) []sales.ReviewItem); ok {
+		r0 = rf(reviewer)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sales.ReviewItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(reviewer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_ReviewQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReviewQueue'
+type MockSalesService_ReviewQueue_Call struct {
+	*mock.Call
+}
+
+// ReviewQueue is a helper method to define mock.On call
+//   - reviewer string
+func (_e *MockSalesService_Expecter) ReviewQueue(reviewer interface{}) *MockSalesService_ReviewQueue_Call {
+	return &MockSalesService_ReviewQueue_Call{Call: _e.mock.On("ReviewQueue", reviewer)}
+}
+
+func (_c *MockSalesService_ReviewQueue_Call) Run(run func(reviewer string)) *MockSalesService_ReviewQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_ReviewQueue_Call) Return(_a0 []sales.ReviewItem, _a1 error) *MockSalesService_ReviewQueue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_ReviewQueue_Call) RunAndReturn(run func(string) ([]sales.ReviewItem, error)) *MockSalesService_ReviewQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeShareLink provides a mock function with given fields: linkID, actor
+func (_m *MockSalesService) RevokeShareLink(linkID string, actor sales.Actor) (*sales.ShareLink, error) {
+	ret := _m.Called(linkID, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeShareLink")
+	}
+
+	var r0 *sales.ShareLink
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, sales.Actor) (*sales.ShareLink, error)); ok {
+		return rf(linkID, actor)
+	}
+	if rf, ok := ret.Get(0).(func(string, sales.Actor) *sales.ShareLink); ok {
+		r0 = rf(linkID, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.ShareLink)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, sales.Actor) error); ok {
+		r1 = rf(linkID, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_RevokeShareLink_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeShareLink'
+type MockSalesService_RevokeShareLink_Call struct {
+	*mock.Call
+}
+
+// RevokeShareLink is a helper method to define mock.On call
+//   - linkID string
+//   - actor sales.Actor
+func (_e *MockSalesService_Expecter) RevokeShareLink(linkID interface{}, actor interface{}) *MockSalesService_RevokeShareLink_Call {
+	return &MockSalesService_RevokeShareLink_Call{Call: _e.mock.On("RevokeShareLink", linkID, actor)}
+}
+
+func (_c *MockSalesService_RevokeShareLink_Call) Run(run func(linkID string, actor sales.Actor)) *MockSalesService_RevokeShareLink_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(sales.Actor))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_RevokeShareLink_Call) Return(_a0 *sales.ShareLink, _a1 error) *MockSalesService_RevokeShareLink_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_RevokeShareLink_Call) RunAndReturn(run func(string, sales.Actor) (*sales.ShareLink, error)) *MockSalesService_RevokeShareLink_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SearchSale provides a mock function with given fields: filter
+func (_m *MockSalesService) SearchSale(filter sales.SearchFilter) ([]*sales.Sale, sales.SalesMetadata, error) {
+	ret := _m.Called(filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchSale")
+	}
+
+	var r0 []*sales.Sale
+	var r1 sales.SalesMetadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(sales.SearchFilter) ([]*sales.Sale, sales.SalesMetadata, error)); ok {
+		return rf(filter)
+	}
+	if rf, ok := ret.Get(0).(func(sales.SearchFilter) []*sales.Sale); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*sales.Sale)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(sales.SearchFilter) sales.SalesMetadata); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Get(1).(sales.SalesMetadata)
+	}
+
+	if rf, ok := ret.Get(2).(func(sales.SearchFilter) error); ok {
+		r2 = rf(filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockSalesService_SearchSale_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SearchSale'
+type MockSalesService_SearchSale_Call struct {
+	*mock.Call
+}
+
+// SearchSale is a helper method to define mock.On call
+//   - filter sales.SearchFilter
+func (_e *MockSalesService_Expecter) SearchSale(filter interface{}) *MockSalesService_SearchSale_Call {
+	return &MockSalesService_SearchSale_Call{Call: _e.mock.On("SearchSale", filter)}
+}
+
+func (_c *MockSalesService_SearchSale_Call) Run(run func(filter sales.SearchFilter)) *MockSalesService_SearchSale_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(sales.SearchFilter))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_SearchSale_Call) Return(results []*sales.Sale, metadata sales.SalesMetadata, err error) *MockSalesService_SearchSale_Call {
+	_c.Call.Return(results, metadata, err)
+	return _c
+}
+
+func (_c *MockSalesService_SearchSale_Call) RunAndReturn(run func(sales.SearchFilter) ([]*sales.Sale, sales.SalesMetadata, error)) *MockSalesService_SearchSale_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SharedReceipt provides a mock function with given fields: linkID, now
+func (_m *MockSalesService) SharedReceipt(linkID string, now time.Time) (*sales.Receipt, error) {
+	ret := _m.Called(linkID, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SharedReceipt")
+	}
+
+	var r0 *sales.Receipt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) (*sales.Receipt, error)); ok {
+		return rf(linkID, now)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time) *sales.Receipt); ok {
+		r0 = rf(linkID, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.Receipt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time) error); ok {
+		r1 = rf(linkID, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_SharedReceipt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SharedReceipt'
+type MockSalesService_SharedReceipt_Call struct {
+	*mock.Call
+}
+
+// SharedReceipt is a helper method to define mock.On call
+//   - linkID string
+//   - now time.Time
+func (_e *MockSalesService_Expecter) SharedReceipt(linkID interface{}, now interface{}) *MockSalesService_SharedReceipt_Call {
+	return &MockSalesService_SharedReceipt_Call{Call: _e.mock.On("SharedReceipt", linkID, now)}
+}
+
+func (_c *MockSalesService_SharedReceipt_Call) Run(run func(linkID string, now time.Time)) *MockSalesService_SharedReceipt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_SharedReceipt_Call) Return(_a0 *sales.Receipt, _a1 error) *MockSalesService_SharedReceipt_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_SharedReceipt_Call) RunAndReturn(run func(string, time.Time) (*sales.Receipt, error)) *MockSalesService_SharedReceipt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SubmitSale provides a mock function with given fields: saleID
+func (_m *MockSalesService) SubmitSale(saleID string) (*sales.Sale, error) {
+	ret := _m.Called(saleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubmitSale")
+	}
+
+	var r0 *sales.Sale
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*sales.Sale, error)); ok {
+		return rf(saleID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *sales.Sale); ok {
+		r0 = rf(saleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.Sale)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(saleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_SubmitSale_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SubmitSale'
+type MockSalesService_SubmitSale_Call struct {
+	*mock.Call
+}
+
+// SubmitSale is a helper method to define mock.On call
+//   - saleID string
+func (_e *MockSalesService_Expecter) SubmitSale(saleID interface{}) *MockSalesService_SubmitSale_Call {
+	return &MockSalesService_SubmitSale_Call{Call: _e.mock.On("SubmitSale", saleID)}
+}
+
+func (_c *MockSalesService_SubmitSale_Call) Run(run func(saleID string)) *MockSalesService_SubmitSale_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_SubmitSale_Call) Return(_a0 *sales.Sale, _a1 error) *MockSalesService_SubmitSale_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_SubmitSale_Call) RunAndReturn(run func(string) (*sales.Sale, error)) *MockSalesService_SubmitSale_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UnclaimSale provides a mock function with given fields: saleID, reviewer
+func (_m *MockSalesService) UnclaimSale(saleID string, reviewer string) error {
+	ret := _m.Called(saleID, reviewer)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnclaimSale")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(saleID, reviewer)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSalesService_UnclaimSale_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UnclaimSale'
+type MockSalesService_UnclaimSale_Call struct {
+	*mock.Call
+}
+
+// UnclaimSale is a helper method to define mock.On call
+//   - saleID string
+//   - reviewer string
+func (_e *MockSalesService_Expecter) UnclaimSale(saleID interface{}, reviewer interface{}) *MockSalesService_UnclaimSale_Call {
+	return &MockSalesService_UnclaimSale_Call{Call: _e.mock.On("UnclaimSale", saleID, reviewer)}
+}
+
+func (_c *MockSalesService_UnclaimSale_Call) Run(run func(saleID string, reviewer string)) *MockSalesService_UnclaimSale_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_UnclaimSale_Call) Return(_a0 error) *MockSalesService_UnclaimSale_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSalesService_UnclaimSale_Call) RunAndReturn(run func(string, string) error) *MockSalesService_UnclaimSale_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateComment provides a mock function with given fields: saleID, commentID, author, body
+func (_m *MockSalesService) UpdateComment(saleID string, commentID string, author string, body string) (*sales.Comment, error) {
+	ret := _m.Called(saleID, commentID, author, body)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateComment")
+	}
+
+	var r0 *sales.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string) (*sales.Comment, error)); ok {
+		return rf(saleID, commentID, author, body)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string, string) *sales.Comment); ok {
+		r0 = rf(saleID, commentID, author, body)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string, string) error); ok {
+		r1 = rf(saleID, commentID, author, body)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_UpdateComment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateComment'
+type MockSalesService_UpdateComment_Call struct {
+	*mock.Call
+}
+
+// UpdateComment is a helper method to define mock.On call
+//   - saleID string
+//   - commentID string
+//   - author string
+//   - body string
+func (_e *MockSalesService_Expecter) UpdateComment(saleID interface{}, commentID interface{}, author interface{}, body interface{}) *MockSalesService_UpdateComment_Call {
+	return &MockSalesService_UpdateComment_Call{Call: _e.mock.On("UpdateComment", saleID, commentID, author, body)}
+}
+
+func (_c *MockSalesService_UpdateComment_Call) Run(run func(saleID string, commentID string, author string, body string)) *MockSalesService_UpdateComment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_UpdateComment_Call) Return(_a0 *sales.Comment, _a1 error) *MockSalesService_UpdateComment_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_UpdateComment_Call) RunAndReturn(run func(string, string, string, string) (*sales.Comment, error)) *MockSalesService_UpdateComment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateFulfillment provides a mock function with given fields: saleID, update, actor
+func (_m *MockSalesService) UpdateFulfillment(saleID string, update sales.FulfillmentUpdate, actor sales.Actor) (*sales.Sale, error) {
+	ret := _m.Called(saleID, update, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateFulfillment")
+	}
+
+	var r0 *sales.Sale
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, sales.FulfillmentUpdate, sales.Actor) (*sales.Sale, error)); ok {
+		return rf(saleID, update, actor)
+	}
+	if rf, ok := ret.Get(0).(func(string, sales.FulfillmentUpdate, sales.Actor) *sales.Sale); ok {
+		r0 = rf(saleID, update, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.Sale)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, sales.FulfillmentUpdate, sales.Actor) error); ok {
+		r1 = rf(saleID, update, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_UpdateFulfillment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateFulfillment'
+type MockSalesService_UpdateFulfillment_Call struct {
+	*mock.Call
+}
+
+// UpdateFulfillment is a helper method to define mock.On call
+//   - saleID string
+//   - update sales.FulfillmentUpdate
+//   - actor sales.Actor
+func (_e *MockSalesService_Expecter) UpdateFulfillment(saleID interface{}, update interface{}, actor interface{}) *MockSalesService_UpdateFulfillment_Call {
+	return &MockSalesService_UpdateFulfillment_Call{Call: _e.mock.On("UpdateFulfillment", saleID, update, actor)}
+}
+
+func (_c *MockSalesService_UpdateFulfillment_Call) Run(run func(saleID string, update sales.FulfillmentUpdate, actor sales.Actor)) *MockSalesService_UpdateFulfillment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(sales.FulfillmentUpdate), args[2].(sales.Actor))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_UpdateFulfillment_Call) Return(_a0 *sales.Sale, _a1 error) *MockSalesService_UpdateFulfillment_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_UpdateFulfillment_Call) RunAndReturn(run func(string, sales.FulfillmentUpdate, sales.Actor) (*sales.Sale, error)) *MockSalesService_UpdateFulfillment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateSaleStatusAs provides a mock function with given fields: saleID, newStatus, actor
+func (_m *MockSalesService) UpdateSaleStatusAs(saleID string, newStatus string, actor sales.Actor) (*sales.Sale, error) {
+	ret := _m.Called(saleID, newStatus, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSaleStatusAs")
+	}
+
+	var r0 *sales.Sale
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, sales.Actor) (*sales.Sale, error)); ok {
+		return rf(saleID, newStatus, actor)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, sales.Actor) *sales.Sale); ok {
+		r0 = rf(saleID, newStatus, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sales.Sale)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, sales.Actor) error); ok {
+		r1 = rf(saleID, newStatus, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_UpdateSaleStatusAs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateSaleStatusAs'
+type MockSalesService_UpdateSaleStatusAs_Call struct {
+	*mock.Call
+}
+
+// UpdateSaleStatusAs is a helper method to define mock.On call
+//   - saleID string
+//   - newStatus string
+//   - actor sales.Actor
+func (_e *MockSalesService_Expecter) UpdateSaleStatusAs(saleID interface{}, newStatus interface{}, actor interface{}) *MockSalesService_UpdateSaleStatusAs_Call {
+	return &MockSalesService_UpdateSaleStatusAs_Call{Call: _e.mock.On("UpdateSaleStatusAs", saleID, newStatus, actor)}
+}
+
+func (_c *MockSalesService_UpdateSaleStatusAs_Call) Run(run func(saleID string, newStatus string, actor sales.Actor)) *MockSalesService_UpdateSaleStatusAs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(sales.Actor))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_UpdateSaleStatusAs_Call) Return(_a0 *sales.Sale, _a1 error) *MockSalesService_UpdateSaleStatusAs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_UpdateSaleStatusAs_Call) RunAndReturn(run func(string, string, sales.Actor) (*sales.Sale, error)) *MockSalesService_UpdateSaleStatusAs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VerifyAudit provides a mock function with no fields
+func (_m *MockSalesService) VerifyAudit() (sales.AuditVerification, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyAudit")
+	}
+
+	var r0 sales.AuditVerification
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (sales.AuditVerification, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() sales.AuditVerification); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(sales.AuditVerification)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSalesService_VerifyAudit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyAudit'
+type MockSalesService_VerifyAudit_Call struct {
+	*mock.Call
+}
+
+// VerifyAudit is a helper method to define mock.On call
+func (_e *MockSalesService_Expecter) VerifyAudit() *MockSalesService_VerifyAudit_Call {
+	return &MockSalesService_VerifyAudit_Call{Call: _e.mock.On("VerifyAudit")}
+}
+
+func (_c *MockSalesService_VerifyAudit_Call) Run(run func()) *MockSalesService_VerifyAudit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockSalesService_VerifyAudit_Call) Return(_a0 sales.AuditVerification, _a1 error) *MockSalesService_VerifyAudit_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSalesService_VerifyAudit_Call) RunAndReturn(run func() (sales.AuditVerification, error)) *MockSalesService_VerifyAudit_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WithSLAStatus provides a mock function with given fields: list, now
+func (_m *MockSalesService) WithSLAStatus(list []*sales.Sale, now time.Time) []*sales.Sale {
+	ret := _m.Called(list, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithSLAStatus")
+	}
+
+	var r0 []*sales.Sale
+	if rf, ok := ret.Get(0).(func([]*sales.Sale, time.Time) []*sales.Sale); ok {
+		r0 = rf(list, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*sales.Sale)
+		}
+	}
+
+	return r0
+}
+
+// MockSalesService_WithSLAStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithSLAStatus'
+type MockSalesService_WithSLAStatus_Call struct {
+	*mock.Call
+}
+
+// WithSLAStatus is a helper method to define mock.On call
+//   - list []*sales.Sale
+//   - now time.Time
+func (_e *MockSalesService_Expecter) WithSLAStatus(list interface{}, now interface{}) *MockSalesService_WithSLAStatus_Call {
+	return &MockSalesService_WithSLAStatus_Call{Call: _e.mock.On("WithSLAStatus", list, now)}
+}
+
+func (_c *MockSalesService_WithSLAStatus_Call) Run(run func(list []*sales.Sale, now time.Time)) *MockSalesService_WithSLAStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].([]*sales.Sale), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockSalesService_WithSLAStatus_Call) Return(_a0 []*sales.Sale) *MockSalesService_WithSLAStatus_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSalesService_WithSLAStatus_Call) RunAndReturn(run func([]*sales.Sale, time.Time) []*sales.Sale) *MockSalesService_WithSLAStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockSalesService creates a new instance of MockSalesService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockSalesService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockSalesService {
+	mock := &MockSalesService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// openAPISpec is the published contract of the core sale endpoints.
+//
+//go:embed openapi.yaml
+var openAPISpec []byte
+
+// ginParam convierte los parámetros de gin (:id) al formato de OpenAPI ({id}).
+var ginParam = regexp.MustCompile(`:([A-Za-z_][A-Za-z0-9_]*)`)
+
+// loadOpenAPI parses and validates the embedded spec.
+func loadOpenAPI() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(openAPISpec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid openapi spec: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid openapi spec: %w", err)
+	}
+	return doc, nil
+}
+
+// validateOpenAPI answers 400 to requests for the operations in doc that
+// don't match the spec. With responses set it also checks the JSON the
+// handlers answer and replaces a mismatching one with a 500, so contract
+// drift fails loudly in development and tests. Routes not in the spec pass
+// through.
+func validateOpenAPI(doc *openapi3.T, responses bool, logger *zap.Logger) gin.HandlerFunc {
+	options := &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc}
+	return func(ctx *gin.Context) {
+		route := openAPIRoute(doc, ctx)
+		if route == nil {
+			ctx.Next()
+			return
+		}
+
+		params := make(map[string]string, len(ctx.Params))
+		for _, p := range ctx.Params {
+			params[p.Key] = p.Value
+		}
+		input := &openapi3filter.RequestValidationInput{
+			Request:    ctx.Request,
+			PathParams: params,
+			Route:      route,
+			Options:    options,
+		}
+		if err := openapi3filter.ValidateRequest(ctx.Request.Context(), input); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "request does not match the API schema",
+				"details": schemaErrorDetails(err),
+			})
+			return
+		}
+		if !responses {
+			ctx.Next()
+			return
+		}
+
+		writer := &jsonCapture{ResponseWriter: ctx.Writer}
+		ctx.Writer = writer
+		ctx.Next()
+		ctx.Writer = writer.ResponseWriter
+		if !writer.capturing {
+			return
+		}
+
+		body := writer.body.Bytes()
+		// HAL y otras variantes +json no son parte del contrato publicado
+		if strings.HasPrefix(ctx.Writer.Header().Get("Content-Type"), "application/json") {
+			err := openapi3filter.ValidateResponse(ctx.Request.Context(), &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: input,
+				Status:                 ctx.Writer.Status(),
+				Header:                 ctx.Writer.Header(),
+				Body:                   io.NopCloser(bytes.NewReader(body)),
+				Options:                options,
+			})
+			if err != nil {
+				logger.Error("response does not match the openapi spec", zap.String("method", route.Method), zap.String("path", route.Path), zap.Int("status", ctx.Writer.Status()), zap.Error(err))
+				ctx.JSON(http.StatusInternalServerError, gin.H{
+					"error":   "response does not match the API schema",
+					"details": schemaErrorDetails(err),
+				})
+				return
+			}
+		}
+		ctx.Writer.Write(body)
+	}
+}
+
+// openAPIRoute busca la operación de la ruta de gin que atiende la request.
+func openAPIRoute(doc *openapi3.T, ctx *gin.Context) *routers.Route {
+	if ctx.FullPath() == "" {
+		return nil
+	}
+	path := ginParam.ReplaceAllString(ctx.FullPath(), "{$1}")
+	item := doc.Paths.Find(path)
+	if item == nil {
+		return nil
+	}
+	op := item.GetOperation(ctx.Request.Method)
+	if op == nil {
+		return nil
+	}
+	return &routers.Route{Spec: doc, Path: path, PathItem: item, Method: ctx.Request.Method, Operation: op}
+}
+
+// schemaErrorDetails resume el error de kin-openapi sin volcar el schema.
+func schemaErrorDetails(err error) string {
+	var schemaErr *openapi3.SchemaError
+	if !errors.As(err, &schemaErr) {
+		first, _, _ := strings.Cut(err.Error(), "\n")
+		return first
+	}
+	field := strings.Join(schemaErr.JSONPointer(), ".")
+	var reqErr *openapi3filter.RequestError
+	if errors.As(err, &reqErr) && reqErr.Parameter != nil {
+		field = reqErr.Parameter.Name
+	}
+	if field == "" {
+		return schemaErr.Reason
+	}
+	return field + ": " + schemaErr.Reason
+}
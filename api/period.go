@@ -0,0 +1,37 @@
+package api
+
+import (
+	"api_sales/internal/sales"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// handleClosePeriod handles the POST /admin/periods/:period/close endpoint.
+func (h *salesHandler) handleClosePeriod(ctx *gin.Context) {
+	period, err := h.salesService.ClosePeriod(ctx.Param("period"), operatorID(ctx))
+	if err != nil {
+		if err == sales.ErrInvalidPeriod {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to close accounting period", zap.Error(err), zap.String("period", ctx.Param("period")))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to close accounting period"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, period)
+}
+
+// handleListPeriods handles the GET /admin/periods endpoint.
+func (h *salesHandler) handleListPeriods(ctx *gin.Context) {
+	periods, err := h.salesService.ListClosedPeriods()
+	if err != nil {
+		h.logger.Error("failed to list accounting periods", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list accounting periods"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"results": periods})
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"api_sales/internal/sales"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RouteRegistrar adds routes to the API from an embedding application. r
+// already runs the built-in authentication, maintenance and quota middleware
+// followed by Dependencies.Middleware; svc is the sales service the built-in
+// routes use.
+type RouteRegistrar func(r gin.IRouter, svc *sales.Service)
+
+// Role returns the role authenticated for the request, so plugin middleware
+// and routes can apply their own policies.
+func Role(ctx *gin.Context) string {
+	return callerRole(ctx)
+}
+
+// registerRoutes registra las rutas de los plugins. Gin entra en pánico con
+// rutas duplicadas; se devuelve como error para que el embebido no caiga.
+func registerRoutes(r gin.IRouter, svc *sales.Service, registrars []RouteRegistrar) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("failed to register plugin routes: %v", p)
+		}
+	}()
+	for _, register := range registrars {
+		register(r, svc)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"api_sales/internal/quota"
+	"api_sales/internal/sales"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// limitByClientIP answers 429 with Retry-After when the client IP runs out of
+// tokens in limiter. Used by the unauthenticated endpoints, which have no
+// tenant to count against.
+func limitByClientIP(limiter *quota.Limiter) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ok, wait := limiter.Allow(ctx.ClientIP()); !ok {
+			ctx.Header("Retry-After", strconv.Itoa(max(1, int(wait.Round(time.Second).Seconds()))))
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// handleGetPublicStatus handles the GET /public/sales/:reference/status
+// endpoint, where customers track a purchase by sale ID or tracking number
+// without credentials.
+func (h *salesHandler) handleGetPublicStatus(ctx *gin.Context) {
+	status, err := h.salesService.PublicSaleStatus(ctx.Param("reference"))
+	if err != nil {
+		if err == sales.ErrNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "sale not found"})
+			return
+		}
+		h.logger.Error("failed to look up public sale status", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve sale status"})
+		return
+	}
+	// Sin credenciales, pero el estado cambia: los caches siempre revalidan
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.JSON(http.StatusOK, status)
+}
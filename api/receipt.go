@@ -0,0 +1,66 @@
+package api
+
+import (
+	"api_sales/internal/sales"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// handleCreateFromReceipt handles the POST /sales/from-receipt endpoint. It
+// takes a multipart form with the receipt image or PDF in "file" and the
+// buyer in "user_id", and creates a draft with the amount and date read from
+// the receipt for the caller to confirm and submit.
+func (h *salesHandler) handleCreateFromReceipt(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, int64(h.receiptMaxBytes)+multipartOverhead)
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		h.logger.Warn("failed to read receipt upload", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "expected a multipart file field named file"})
+		return
+	}
+	if header.Size > int64(h.receiptMaxBytes) {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "receipt is too large", "max_bytes": h.receiptMaxBytes})
+		return
+	}
+	userID := ctx.PostForm("user_id")
+	if userID == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		h.logger.Error("failed to open receipt upload", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create sale"})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		h.logger.Error("failed to read receipt upload", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create sale"})
+		return
+	}
+
+	origin := sales.Origin{ClientIP: ctx.ClientIP(), UserAgent: ctx.Request.UserAgent(), TenantID: tenantID(ctx)}
+	draft, err := h.salesService.CreateDraftFromReceipt(ctx.Request.Context(), userID, header.Filename, data, origin, operatorID(ctx))
+	if err != nil {
+		switch {
+		case errors.Is(err, sales.ErrUnsupportedReceipt):
+			ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		case errors.Is(err, sales.ErrEnrichmentFailed):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		case errors.Is(err, sales.ErrReceiptUnreadable):
+			ctx.JSON(http.StatusBadGateway, gin.H{"error": sales.ErrReceiptUnreadable.Error()})
+		default:
+			h.logger.Error("failed to create sale from receipt", zap.Error(err), zap.String("user_id", userID))
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create sale"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, h.sales(ctx).receiptDraft(draft))
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"api_sales/internal/sales"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type recurringHandler struct {
+	recurringService *sales.RecurringService
+	salesService     SalesService
+	logger           *zap.Logger
+}
+
+// NewRecurringHandler creates a new recurring sales handler. salesService
+// redacts the materialized sales it responds.
+func NewRecurringHandler(recurringService *sales.RecurringService, salesService SalesService, logger *zap.Logger) *recurringHandler {
+	return &recurringHandler{
+		recurringService: recurringService,
+		salesService:     salesService,
+		logger:           logger,
+	}
+}
+
+// handleCreate handles the POST /recurring-sales endpoint.
+func (h *recurringHandler) handleCreate(ctx *gin.Context) {
+	var req struct {
+		UserID    string     `json:"user_id"`
+		Amount    float64    `json:"amount"`
+		Interval  string     `json:"interval"`
+		StartDate *time.Time `json:"start_date"`
+		EndDate   *time.Time `json:"end_date"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("failed to bind JSON request", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request payload"})
+		return
+	}
+
+	var startAt time.Time
+	if req.StartDate != nil {
+		startAt = *req.StartDate
+	}
+
+	rs, err := h.recurringService.CreateRecurringSale(req.UserID, req.Amount, req.Interval, startAt, req.EndDate)
+	if err != nil {
+		h.logger.Error("failed to create recurring sale", zap.Error(err), zap.String("user_id", req.UserID))
+		switch err.Error() {
+		case "amount must be greater than zero", "user not found", "end date must be after start date", sales.ErrInvalidInterval.Error():
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create recurring sale"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, rs)
+}
+
+// handleList handles the GET /recurring-sales endpoint.
+func (h *recurringHandler) handleList(ctx *gin.Context) {
+	results, err := h.recurringService.ListRecurringSales(ctx.Query("user_id"), ctx.Query("status"))
+	if err != nil {
+		h.logger.Error("failed to list recurring sales", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list recurring sales"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"results": results})
+}
+
+// handleGet handles the GET /recurring-sales/:id endpoint.
+func (h *recurringHandler) handleGet(ctx *gin.Context) {
+	rs, err := h.recurringService.GetRecurringSale(ctx.Param("id"))
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rs)
+}
+
+// handleListSales handles the GET /recurring-sales/:id/sales endpoint.
+func (h *recurringHandler) handleListSales(ctx *gin.Context) {
+	results, err := h.recurringService.ListMaterializedSales(ctx.Param("id"), caller(ctx))
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"results": h.sales(ctx).dtos(results)})
+}
+
+// handlePause handles the POST /recurring-sales/:id/pause endpoint.
+func (h *recurringHandler) handlePause(ctx *gin.Context) {
+	h.respondUpdate(ctx, h.recurringService.PauseRecurringSale)
+}
+
+// handleResume handles the POST /recurring-sales/:id/resume endpoint.
+func (h *recurringHandler) handleResume(ctx *gin.Context) {
+	h.respondUpdate(ctx, h.recurringService.ResumeRecurringSale)
+}
+
+// handleCancel handles the DELETE /recurring-sales/:id endpoint.
+func (h *recurringHandler) handleCancel(ctx *gin.Context) {
+	h.respondUpdate(ctx, h.recurringService.CancelRecurringSale)
+}
+
+func (h *recurringHandler) respondUpdate(ctx *gin.Context, update func(id string) (*sales.RecurringSale, error)) {
+	rs, err := update(ctx.Param("id"))
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rs)
+}
+
+func (h *recurringHandler) respondError(ctx *gin.Context, err error) {
+	switch err {
+	case sales.ErrRecurringNotFound:
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "recurring sale not found"})
+	case sales.ErrInvalidRecurringState:
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("recurring sale operation failed", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
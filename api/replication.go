@@ -0,0 +1,41 @@
+package api
+
+import (
+	"api_sales/internal/sales"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// handleGetReplication handles the GET /admin/replication endpoint.
+func handleGetReplication(replicated *sales.ReplicatedStorage) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, replicated.Status())
+	}
+}
+
+// handlePromoteReplica handles the POST /admin/replication/promote endpoint:
+// the secondary storage takes over after copying what it can of the pending
+// changes within timeout.
+func handlePromoteReplica(replicated *sales.ReplicatedStorage, timeout time.Duration, logger *zap.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		promoteCtx, cancel := context.WithTimeout(ctx.Request.Context(), timeout)
+		defer cancel()
+
+		status, err := replicated.Promote(promoteCtx)
+		if err != nil {
+			if err == sales.ErrAlreadyPromoted {
+				ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			logger.Error("failed to promote replica", zap.Error(err))
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to promote replica"})
+			return
+		}
+		logger.Warn("replica promoted", zap.String("operator", operatorID(ctx)), zap.Int("unreplicated", status.Pending))
+		ctx.JSON(http.StatusOK, status)
+	}
+}
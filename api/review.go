@@ -0,0 +1,79 @@
+package api
+
+import (
+	"api_sales/internal/sales"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// handleClaimSale handles the POST /sales/:id/claim endpoint. The reviewer is
+// the operator of the request.
+func (h *salesHandler) handleClaimSale(ctx *gin.Context) {
+	saleID := ctx.Param("id")
+	assignment, err := h.salesService.ClaimSale(saleID, operatorID(ctx))
+	if err != nil {
+		switch err {
+		case sales.ErrNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "sale not found"})
+		case sales.ErrNotPending:
+			h.conflict(ctx, saleID, err.Error())
+		case sales.ErrAlreadyClaimed:
+			ctx.JSON(http.StatusConflict, gin.H{
+				"error":      err.Error(),
+				"claimed_by": assignment.Reviewer,
+				"expires_at": assignment.ExpiresAt,
+			})
+		default:
+			h.logger.Error("failed to claim sale", zap.Error(err), zap.String("sale_id", saleID))
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to claim sale"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, assignment)
+}
+
+// handleUnclaimSale handles the DELETE /sales/:id/claim endpoint.
+func (h *salesHandler) handleUnclaimSale(ctx *gin.Context) {
+	saleID := ctx.Param("id")
+	if err := h.salesService.UnclaimSale(saleID, operatorID(ctx)); err != nil {
+		switch err {
+		case sales.ErrClaimNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case sales.ErrAlreadyClaimed:
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("failed to unclaim sale", zap.Error(err), zap.String("sale_id", saleID))
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to unclaim sale"})
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// handleGetReviewQueue handles the GET /reviews/queue endpoint, listing the
+// pending sales assigned to the operator. Admins may pass ?reviewer= to see
+// another reviewer's queue.
+func (h *salesHandler) handleGetReviewQueue(ctx *gin.Context) {
+	reviewer := operatorID(ctx)
+	if other := ctx.Query("reviewer"); other != "" && other != reviewer {
+		if callerRole(ctx) != adminRole {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "only admins can see other reviewers' queues"})
+			return
+		}
+		reviewer = other
+	}
+
+	queue, err := h.salesService.ReviewQueue(reviewer)
+	if err != nil {
+		h.logger.Error("failed to get review queue", zap.Error(err), zap.String("reviewer", reviewer))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get review queue"})
+		return
+	}
+
+	ctx.Header("Cache-Control", "private, no-store")
+	ctx.JSON(http.StatusOK, gin.H{"reviewer": reviewer, "results": h.sales(ctx).reviewItems(queue)})
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"api_sales/internal/sales"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// roleKey is the gin context key where authentication stores the caller role.
+const roleKey = "role"
+
+// adminRole is required by the /admin endpoints.
+const adminRole = "admin"
+
+// defaultRole applies to callers without an authenticated role.
+const defaultRole = "default"
+
+// callerKey is the gin context key where authentication stores the caller.
+const callerKey = "caller"
+
+// caller returns the authenticated caller, or nil for anonymous requests.
+func caller(ctx *gin.Context) *sales.Caller {
+	if c, ok := ctx.Get(callerKey); ok {
+		return c.(*sales.Caller)
+	}
+	return nil
+}
+
+// callerRole returns the role of the caller for per-role policies.
+func callerRole(ctx *gin.Context) string {
+	if role := ctx.GetString(roleKey); role != "" {
+		return role
+	}
+	return defaultRole
+}
+
+// requireRole rejects callers whose role is not role with 403.
+func requireRole(role string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if callerRole(ctx) != role {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -1,50 +1,625 @@
 package api
 
 import (
+	"api_sales/internal/analytics"
+	"api_sales/internal/blob"
+	"api_sales/internal/buildinfo"
+	"api_sales/internal/calendar"
+	"api_sales/internal/chaos"
+	"api_sales/internal/chatops"
+	"api_sales/internal/clamav"
+	"api_sales/internal/config"
+	"api_sales/internal/dispatch"
+	"api_sales/internal/enrich"
+	"api_sales/internal/envelope"
+	"api_sales/internal/erp"
+	"api_sales/internal/httpclient"
+	"api_sales/internal/idempotency"
+	_ "api_sales/internal/jsonenc" // con -tags=jsoniter registra los encoders rápidos que usa ctx.JSON
+	"api_sales/internal/keys"
+	"api_sales/internal/lock"
+	"api_sales/internal/maintenance"
+	"api_sales/internal/ocr"
+	"api_sales/internal/quota"
+	"api_sales/internal/redact"
 	"api_sales/internal/sales"
+	"api_sales/internal/slo"
+	"api_sales/internal/sms"
+	"api_sales/internal/webhook"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"slices"
+	"strings"
+	"time"
+	_ "time/tzdata" // zonas horarias del parámetro tz aunque la imagen no traiga zoneinfo
 
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/gin-gonic/gin"
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
+	_ "modernc.org/sqlite"
 )
 
 // InitRoutes registers all user CRUD endpoints on the given Gin engine.
 // It initializes the storage, service, and handler, then binds each HTTP
 // method and path to the appropriate handler function.
 func InitRoutes(e *gin.Engine) {
-	userServiceURL := "http://localhost:8080/users"
-	logger, _ := zap.NewProduction()
-	defer logger.Sync()
+	if err := InitRoutesWithConfig(e, config.Load()); err != nil {
+		panic(err)
+	}
+}
 
-	// Inicialización de la lógica de ventas
-	salesStorage := sales.NewLocalStorage()
-	salesService := sales.NewService(salesStorage, logger, userServiceURL)
-	salesHandler := NewSalesHandler(salesService, logger)
+func InitRoutes2(e *gin.Engine, userServiceURL string) {
+	cfg := config.Default()
+	cfg.UserServiceURL = userServiceURL
+	if err := InitRoutesWithConfig(e, cfg); err != nil {
+		panic(err)
+	}
+}
 
-	e.POST("/sales", salesHandler.handleCreateSale)
-	e.PATCH("/sales/:id", salesHandler.PatchSaleHandler(salesService))
-	e.GET("/sales", salesHandler.handlerGetSale)
+// Dependencies overrides the components InitRoutesWithDependencies would
+// otherwise build from the configuration. Zero fields keep the defaults.
+type Dependencies struct {
+	// Storage holds the sales; defaults to in-memory storage.
+	Storage sales.Storage
+	// Reader, when set, serves the eventually consistent searches and lookups
+	// instead of Storage, e.g. a search index fed from its writes.
+	Reader sales.SaleReader
+	// Logger receives every log line, after sensitive fields are redacted.
+	Logger *zap.Logger
+	// Notifiers receive every sale event besides the configured integrations.
+	Notifiers []sales.Notifier
+	// Admin, when set, gets the /admin and /metrics routes instead of the
+	// public engine, e.g. to serve them only on an internal listener.
+	Admin *gin.Engine
+	// Middleware runs on every route after authentication, maintenance and
+	// quotas, so it can rely on the caller's role.
+	Middleware []gin.HandlerFunc
+	// Routes are registered on the public engine after the built-in ones;
+	// AdminRoutes under /admin, where the admin role is required.
+	Routes      []RouteRegistrar
+	AdminRoutes []RouteRegistrar
+}
 
-	e.GET("/ping", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"message": "pong",
+// InitRoutesWithConfig wires storage, services and handlers from cfg and
+// registers every route on e.
+func InitRoutesWithConfig(e *gin.Engine, cfg config.Config) error {
+	return InitRoutesWithDependencies(e, cfg, Dependencies{})
+}
+
+// InitRoutesWithDependencies is InitRoutesWithConfig using the components in
+// deps instead of the defaults.
+func InitRoutesWithDependencies(e *gin.Engine, cfg config.Config, deps Dependencies) error {
+	// Los datos sensibles se enmascaran antes de llegar a los logs
+	redactor := redact.New(append(cfg.RedactFields, cfg.SensitiveMetadataKeys...))
+	var logger *zap.Logger
+	if deps.Logger != nil {
+		logger = deps.Logger.WithOptions(zap.WrapCore(redactor.Core))
+	} else {
+		logger, _ = zap.NewProduction(zap.WrapCore(redactor.Core))
+	}
+	defer logger.Sync()
+
+	// Access log JSON aparte de los logs de la aplicación
+	if cfg.AccessLogOutput != "" && cfg.AccessLogOutput != "off" {
+		accessLogger, err := newAccessLogger(cfg.AccessLogOutput)
+		if err != nil {
+			return fmt.Errorf("invalid ACCESS_LOG_OUTPUT: %w", err)
+		}
+		e.Use(accessLog(accessLogger, accessLogPolicy{
+			SlowThreshold: cfg.AccessLogSlowThreshold,
+			SampleAfter:   cfg.AccessLogSampleAfter,
+			SampleRates:   cfg.AccessLogSampleRates,
+		}))
+	}
+
+	// Inyección de fallas para pruebas de resiliencia, nunca en producción
+	var injector *chaos.Injector
+	if cfg.ChaosEnabled {
+		if cfg.Environment == "production" {
+			return fmt.Errorf("CHAOS_ENABLED is not allowed when APP_ENV=production")
+		}
+		injector = chaos.NewInjector(chaos.Options{
+			LatencyRate: cfg.ChaosLatencyRate,
+			Latency:     cfg.ChaosLatency,
+			ErrorRate:   cfg.ChaosErrorRate,
+			PartialRate: cfg.ChaosPartialRate,
+			Seed:        uint64(cfg.ChaosSeed),
 		})
+		logger.Warn("chaos fault injection enabled", zap.Strings("targets", cfg.ChaosTargets))
+		if slices.Contains(cfg.ChaosTargets, "http") {
+			e.Use(injectFaults(injector))
+		}
+	}
+
+	// Un único cliente Redis compartido por los backends que lo usan
+	var redisClient *redis.Client
+	if usesRedis(cfg) {
+		redisClient = redis.NewClient(&redis.Options{Addr: cfg.RedisAddr})
+	}
+
+	locker, err := newLocker(cfg, redisClient)
+	if err != nil {
+		return err
+	}
+	idempotencyStore, err := newIdempotencyStore(cfg, redisClient)
+	if err != nil {
+		return err
+	}
+	userCache, err := newUserCache(cfg, redisClient)
+	if err != nil {
+		return err
+	}
+	maintenanceSwitch, err := newMaintenanceSwitch(cfg, redisClient)
+	if err != nil {
+		return err
+	}
+	usageTracker, err := newUsageTracker(cfg, redisClient)
+	if err != nil {
+		return err
+	}
+
+	// Proxy y TLS comunes a todos los clientes HTTP salientes
+	transport, err := httpclient.NewTransport(httpclient.Options{
+		ProxyURL:       cfg.OutboundProxyURL,
+		RootCAFiles:    cfg.TLSRootCAFiles,
+		ClientCertFile: cfg.TLSClientCertFile,
+		ClientKeyFile:  cfg.TLSClientKeyFile,
 	})
+	if err != nil {
+		return err
+	}
 
-}
+	// SLOs por endpoint; las alertas de burn rate van al chat cuando se habilitan
+	var sloTracker *slo.Tracker
+	if cfg.SLOs != "" {
+		var objectives []slo.Objective
+		if err := json.Unmarshal([]byte(cfg.SLOs), &objectives); err != nil {
+			return fmt.Errorf("invalid SLOS: %w", err)
+		}
+		if sloTracker, err = slo.NewTracker(objectives, cfg.SLOWindow, logger); err != nil {
+			return fmt.Errorf("invalid SLOS: %w", err)
+		}
+		e.Use(trackSLOs(sloTracker))
+	}
 
-func InitRoutes2(e *gin.Engine, userServiceURL string) {
-	logger, _ := zap.NewProduction()
-	defer logger.Sync()
+	// Telemetría de uso anónima, enviada en lotes fuera del request
+	if cfg.AnalyticsURL != "" {
+		sink := analytics.NewHTTPSink(cfg.AnalyticsURL, cfg.AnalyticsAPIKey, 10*time.Second)
+		sink.SetTransport(transport)
+		analyticsPool := dispatch.NewPool("analytics", 1, 100, logger)
+		recorder := analytics.NewRecorder(sink, cfg.AnalyticsTenantKey, buildinfo.Get().Features, cfg.AnalyticsBatchSize, analyticsPool, logger)
+		go recorder.Start(context.Background(), cfg.AnalyticsFlushInterval)
+		e.Use(recordUsage(recorder))
+	}
+
+	// Claves versionadas: API keys (definen el rol) y firma de webhooks
+	keyManager := keys.NewManager(cfg.KeyRotationWindow)
+	if cfg.AdminAPIKey != "" {
+		if _, err := keyManager.Add(keys.PurposeAPI, adminRole, cfg.AdminAPIKey); err != nil {
+			return err
+		}
+	}
+	if cfg.WebhookSigningSecret != "" {
+		if _, err := keyManager.Add(keys.PurposeWebhookSigning, "", cfg.WebhookSigningSecret); err != nil {
+			return err
+		}
+	}
+	var tokens *tokenVerifier
+	if cfg.JWTSecret != "" {
+		tokens = newTokenVerifier(cfg.JWTSecret, cfg.JWTIssuer, cfg.JWTAudience)
+	}
+	e.Use(encodeResponses(logger))
+	e.Use(authenticate(keyManager), authenticateBearer(tokens, logger), impersonate(logger), rejectDuringMaintenance(maintenanceSwitch, logger), enforceQuotas(usageTracker, logger))
+	e.Use(deps.Middleware...)
+	e.Use(validateSaleID)
+	if cfg.OpenAPIValidation {
+		if cfg.OpenAPIValidateResponses && cfg.Environment == "production" {
+			return fmt.Errorf("OPENAPI_VALIDATE_RESPONSES is not allowed when APP_ENV=production")
+		}
+		doc, err := loadOpenAPI()
+		if err != nil {
+			return err
+		}
+		e.Use(validateOpenAPI(doc, cfg.OpenAPIValidateResponses, logger))
+	}
+	// Descarte de tráfico de baja prioridad cuando el storage se degrada
+	var storageHealth *sales.StorageHealth
+	if cfg.ShedMaxP95Latency > 0 || cfg.ShedMaxErrorRate > 0 {
+		storageHealth = sales.NewStorageHealth(cfg.ShedWindow, 1000)
+		e.Use(shedLoad(&loadShedder{health: storageHealth, logger: logger, thresholds: sheddingThresholds{
+			MaxP95Latency: cfg.ShedMaxP95Latency,
+			MaxErrorRate:  cfg.ShedMaxErrorRate,
+			MinSamples:    cfg.ShedMinSamples,
+			RetryAfter:    cfg.ShedRetryAfter,
+		}}))
+	}
+	// Carriles de prioridad: las exportaciones y cargas masivas no ocupan los
+	// lugares de las requests interactivas
+	if cfg.LaneInteractiveConcurrency > 0 || cfg.LaneBatchConcurrency > 0 {
+		e.Use(processInLanes(newLaneLimiter(map[requestLane]int{
+			laneInteractive: cfg.LaneInteractiveConcurrency,
+			laneBatch:       cfg.LaneBatchConcurrency,
+		}, cfg.LaneQueueTimeout, logger)))
+	}
+	if len(cfg.ConcurrencyLimits) > 0 {
+		e.Use(limitConcurrency(newConcurrencyLimiter(cfg.ConcurrencyLimits, cfg.ConcurrencyQueueSize, cfg.ConcurrencyQueueTimeout, logger)))
+	}
+	internal := e
+	if deps.Admin != nil {
+		internal = deps.Admin
+		internal.Use(encodeResponses(logger), authenticate(keyManager))
+		internal.Use(deps.Middleware...)
+	}
+
+	// Cache de respuestas de búsqueda, invalidado en cada escritura de ventas
+	respCache := newResponseCache()
+	cached := func(route string) gin.HandlerFunc {
+		return respCache.middleware(cfg.ResponseCacheTTLs[route])
+	}
+
+	serviceOpts := []sales.Option{
+		sales.WithUserCache(userCache),
+		sales.WithHTTPTransport(transport),
+		sales.WithNotifier(respCache),
+		sales.WithSlowQueryThreshold(cfg.SlowQueryThreshold),
+		sales.WithStatsTTL(cfg.StatsCacheTTL),
+	}
+	if cfg.SearchCanaryPercent > 0 {
+		canaryPool := dispatch.NewPool("search-canary", 2, 1000, logger)
+		serviceOpts = append(serviceOpts, sales.WithSearchCanary(cfg.SearchCanaryPercent, canaryPool))
+	}
+	if cfg.RandomSeed != 0 {
+		serviceOpts = append(serviceOpts, sales.WithRandom(sales.NewSeededRandom(uint64(cfg.RandomSeed))))
+	}
+	for _, n := range deps.Notifiers {
+		serviceOpts = append(serviceOpts, sales.WithNotifier(n))
+	}
+	enrichers, err := newEnrichers(cfg, transport)
+	if err != nil {
+		return err
+	}
+	serviceOpts = append(serviceOpts, enrichers...)
+	if len(cfg.WebhookURLs) > 0 {
+		webhookPool := dispatch.NewPool("webhooks", cfg.WebhookWorkers, cfg.WebhookQueueSize, logger)
+		sender := webhook.NewSender(cfg.WebhookURLs, webhookPool, logger)
+		sender.SetTransport(transport)
+		sender.SetSigner(keyManager)
+		sender.SetRedactor(redactor)
+		serviceOpts = append(serviceOpts, sales.WithNotifier(sender))
+	}
+
+	var chat *chatops.Notifier
+	if cfg.ChatWebhookURL != "" {
+		chatPool := dispatch.NewPool("chatops", 1, 100, logger)
+		chat, err = chatops.NewNotifier(chatops.Options{
+			Provider:   cfg.ChatProvider,
+			WebhookURL: cfg.ChatWebhookURL,
+			Events:     cfg.ChatEvents,
+			Templates:  cfg.ChatTemplates,
+		}, chatPool, logger)
+		if err != nil {
+			return err
+		}
+		chat.SetTransport(transport)
+		if chat.Enabled(chatops.EventLargeSale) {
+			serviceOpts = append(serviceOpts, sales.WithNotifier(chat.LargeSales(cfg.LargeSaleThreshold)))
+		}
+	}
+	if sloTracker != nil {
+		if chat != nil && chat.Enabled(chatops.EventSLOBurn) {
+			sloTracker.SetAlerter(func(alert slo.Alert) {
+				chat.Send(chatops.EventSLOBurn, alert)
+			})
+		}
+		go sloTracker.Start(context.Background(), cfg.SLOCheckInterval)
+	}
+
+	// Metadata sensible cifrada en el storage si hay clave configurada
+	if cfg.FieldEncryptionKey != "" {
+		masterKey, err := base64.StdEncoding.DecodeString(cfg.FieldEncryptionKey)
+		if err != nil {
+			return fmt.Errorf("invalid FIELD_ENCRYPTION_KEY: %w", err)
+		}
+		kms, err := envelope.NewLocalKMS(masterKey)
+		if err != nil {
+			return fmt.Errorf("invalid FIELD_ENCRYPTION_KEY: %w", err)
+		}
+		serviceOpts = append(serviceOpts, sales.WithFieldEncryption(envelope.New(kms), cfg.SensitiveMetadataKeys))
+	} else {
+		serviceOpts = append(serviceOpts, sales.WithSensitiveMetadata(cfg.SensitiveMetadataKeys))
+	}
+
+	// SLA de ventas pendientes en horario hábil
+	if cfg.PendingSLA > 0 {
+		cal, err := calendar.Parse(cfg.BusinessHours, cfg.BusinessDays, cfg.BusinessTimezone, cfg.Holidays)
+		if err != nil {
+			return fmt.Errorf("invalid business calendar: %w", err)
+		}
+		serviceOpts = append(serviceOpts, sales.WithPendingSLA(cal, cfg.PendingSLA))
+	}
+
+	// Modo degradado: ventas provisorias si el servicio de usuarios no responde
+	if cfg.ProvisionalSales {
+		serviceOpts = append(serviceOpts, sales.WithProvisionalSales(cfg.VerificationMaxAttempts, cfg.VerificationBackoff, cfg.VerificationMaxBackoff))
+	}
+
+	// Revisores con asignación automática de las ventas pendientes
+	serviceOpts = append(serviceOpts, sales.WithReviewers(cfg.Reviewers, cfg.ClaimTTL))
+
+	// Snapshots inmutables de los periodos cerrados
+	if cfg.SnapshotDir != "" {
+		snapshots, err := blob.NewFileStore(cfg.SnapshotDir)
+		if err != nil {
+			return err
+		}
+		serviceOpts = append(serviceOpts, sales.WithSnapshotStore(snapshots))
+	}
+
+	// Adjuntos, escaneados antes de guardarse si hay clamd
+	if cfg.AttachmentDir != "" {
+		files, err := blob.NewFileStore(cfg.AttachmentDir)
+		if err != nil {
+			return err
+		}
+		serviceOpts = append(serviceOpts, sales.WithAttachments(files, cfg.AttachmentMaxBytes))
+		if cfg.ClamAVAddr != "" {
+			serviceOpts = append(serviceOpts, sales.WithScanner(clamav.New(cfg.ClamAVAddr, cfg.ClamAVTimeout)))
+		}
+	}
+
+	// Borradores a partir de recibos escaneados
+	if cfg.OCRURL != "" {
+		provider := ocr.NewHTTPProvider(cfg.OCRURL, cfg.OCRAPIKey, cfg.OCRTimeout)
+		provider.SetTransport(transport)
+		serviceOpts = append(serviceOpts, sales.WithOCR(provider))
+	}
+
+	// El publisher del ERP registra el estado en el servicio que se crea abajo
+	var salesService *sales.Service
+	if cfg.ERPURL != "" {
+		mapping, err := erp.LoadTemplate(cfg.ERPTemplateFile)
+		if err != nil {
+			return err
+		}
+		poster, err := erp.NewHTTPPoster(cfg.ERPURL, mapping)
+		if err != nil {
+			return err
+		}
+		poster.SetTransport(transport)
+		erpPool := dispatch.NewPool("erp", cfg.ERPWorkers, cfg.ERPQueueSize, logger)
+		serviceOpts = append(serviceOpts, sales.WithNotifier(erp.NewPublisher(poster, erpPool, postingRecorder(func(saleID, status string) error {
+			return salesService.SetERPPostingStatus(saleID, status)
+		}), logger)))
+	}
+
+	// Los opt-outs de SMS se registran aunque las alertas estén deshabilitadas
+	smsOptOuts := sms.NewMemoryOptOutStore()
+	if cfg.SMSAccountSID != "" {
+		provider := sms.NewTwilioProvider(cfg.SMSBaseURL, cfg.SMSAccountSID, cfg.SMSAuthToken, cfg.SMSFrom)
+		provider.SetTransport(transport)
+		smsPool := dispatch.NewPool("sms", 2, 100, logger)
+		serviceOpts = append(serviceOpts, sales.WithNotifier(sms.NewAlerter(provider, smsOptOuts, userLookup(func(userID string) (*sales.User, error) {
+			return salesService.GetUser(userID)
+		}), cfg.SMSHighValueThreshold, smsPool, logger)))
+	}
 
 	// Inicialización de la lógica de ventas
-	salesStorage := sales.NewLocalStorage()
-	salesService := sales.NewService(salesStorage, logger, userServiceURL)
+	salesStorage := deps.Storage
+	if salesStorage == nil {
+		if salesStorage, err = newSalesStorage(cfg, cfg.SalesStorageBackend, redisClient, logger); err != nil {
+			return err
+		}
+	}
+	if cfg.ShadowStorageBackend != "" {
+		candidate, err := newSalesStorage(cfg, cfg.ShadowStorageBackend, redisClient, logger)
+		if err != nil {
+			return err
+		}
+		shadowPool := dispatch.NewPool("shadow-storage", 2, 1000, logger)
+		salesStorage = sales.NewShadowStorage(salesStorage, candidate, shadowPool, logger)
+	}
+	// Réplica asíncrona en otra región para recuperación ante desastres
+	var replicated *sales.ReplicatedStorage
+	if cfg.ReplicationBackend != "" {
+		secondary, err := newReplicaStorage(cfg, logger)
+		if err != nil {
+			return err
+		}
+		replicated = sales.NewReplicatedStorage(salesStorage, secondary, logger)
+		go replicated.Start(context.Background())
+		salesStorage = replicated
+	}
+	if injector != nil && slices.Contains(cfg.ChaosTargets, "storage") {
+		salesStorage = chaos.NewStorage(salesStorage, injector)
+	}
+	if storageHealth != nil {
+		salesStorage = sales.NewMonitoredStorage(salesStorage, storageHealth)
+	}
+	if deps.Reader != nil {
+		serviceOpts = append(serviceOpts, sales.WithReadReplica(deps.Reader))
+	}
+	salesService = sales.NewService(salesStorage, logger, cfg.UserServiceURL, serviceOpts...)
+	if cfg.TenantStatuses != "" {
+		var vocabularies map[string][]sales.CustomStatus
+		if err := json.Unmarshal([]byte(cfg.TenantStatuses), &vocabularies); err != nil {
+			return fmt.Errorf("invalid TENANT_STATUSES: %w", err)
+		}
+		for tenant, statuses := range vocabularies {
+			if err := salesService.SetTenantStatuses(tenant, statuses); err != nil {
+				return fmt.Errorf("invalid TENANT_STATUSES for %s: %w", tenant, err)
+			}
+		}
+	}
 	salesHandler := NewSalesHandler(salesService, logger)
+	salesHandler.cacheMaxAge = cfg.HTTPCacheMaxAge
+	salesHandler.patchDedupWindow = cfg.PatchDedupWindow
+	salesHandler.receiptMaxBytes = cfg.ReceiptMaxBytes
+	if cfg.QueryLimits != "" {
+		if err := json.Unmarshal([]byte(cfg.QueryLimits), &salesHandler.queryPolicy); err != nil {
+			return fmt.Errorf("invalid QUERY_LIMITS: %w", err)
+		}
+	}
+
+	if cfg.WarmUpEnabled {
+		warmCtx, cancel := context.WithTimeout(context.Background(), cfg.WarmUpTimeout)
+		_, err := salesService.WarmUp(warmCtx, sales.WarmUpOptions{MaxUsers: cfg.WarmUpMaxUsers, Concurrency: cfg.WarmUpConcurrency})
+		cancel()
+		if err != nil {
+			logger.Warn("cache warm-up incomplete", zap.Error(err))
+		}
+	}
+
+	// Ventas recurrentes y su scheduler
+	recurringStorage, err := newRecurringStorage(cfg, redisClient)
+	if err != nil {
+		return err
+	}
+	recurringService := sales.NewRecurringService(recurringStorage, salesService, logger)
+	recurringHandler := NewRecurringHandler(recurringService, salesService, logger)
+	scheduler := sales.NewScheduler(recurringService, cfg.SchedulerInterval, locker, logger)
+	go scheduler.Start(context.Background())
+	if cfg.PendingSLA > 0 {
+		go sales.NewSLAMonitor(salesService, cfg.SLACheckInterval, locker, logger).Start(context.Background())
+	}
+
+	// Revalidación de las ventas creadas en modo degradado
+	if cfg.ProvisionalSales {
+		go sales.NewVerificationWorker(salesService, cfg.VerificationInterval, locker, logger).Start(context.Background())
+	}
+
+	// Sincronización nocturna de los usuarios conocidos
+	if cfg.UserSyncAt != "" {
+		at, err := time.Parse("15:04", cfg.UserSyncAt)
+		if err != nil {
+			return fmt.Errorf("invalid USER_SYNC_AT: %w", err)
+		}
+		loc, err := time.LoadLocation(cfg.UserSyncTimezone)
+		if err != nil {
+			return fmt.Errorf("invalid USER_SYNC_TIMEZONE: %w", err)
+		}
+		offset := time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute
+		opts := sales.UserSyncOptions{Concurrency: cfg.UserSyncConcurrency, CacheTTL: cfg.UserSyncCacheTTL}
+		go sales.NewUserSync(salesService, offset, loc, opts, locker, logger).Start(context.Background())
+	}
+
+	// Leases por venta para evitar ediciones concurrentes entre operadores
+	saleLocks := sales.NewMutationLocks(sales.DefaultLockLease)
+
+	withIdempotency := idempotent(idempotencyStore, cfg.IdempotencyTTL, logger)
+
+	e.POST("/sales", withIdempotency, salesHandler.handleCreateSale)
+	if salesService.OCREnabled() {
+		e.POST("/sales/from-receipt", salesHandler.handleCreateFromReceipt)
+	}
+	e.PATCH("/sales/:id", requireSaleLease(saleLocks), salesHandler.PatchSaleHandler(salesService))
+	e.GET("/sales", cached("/sales"), salesHandler.handlerGetSale)
+	e.GET("/sales/stats", cached("/sales/stats"), salesHandler.handleGetStats)
+	e.GET("/sales/reports/cohorts", cached("/sales/reports/cohorts"), salesHandler.handleGetCohorts)
+	e.GET("/sales/:id", salesHandler.handleGetSaleByID)
+	e.GET("/sales/by-reference/:reference", salesHandler.handleGetSaleByReference)
+	e.PATCH("/sales/:id/fulfillment", salesHandler.handleUpdateFulfillment)
+	if cfg.ShippingWebhookSecret != "" {
+		e.POST("/webhooks/shipping", handleShippingWebhook(salesService, cfg.ShippingWebhookSecret, cfg.ShippingWebhookTolerance, logger))
+	}
+	e.POST("/sales/:id/submit", requireSaleLease(saleLocks), salesHandler.handleSubmitSale)
+	e.POST("/sales/:id/lock", handleAcquireLock(saleLocks))
+	e.DELETE("/sales/:id/lock", handleReleaseLock(saleLocks))
+	e.POST("/sales/:id/claim", salesHandler.handleClaimSale)
+	e.DELETE("/sales/:id/claim", salesHandler.handleUnclaimSale)
+	e.POST("/sales/:id/comments", salesHandler.handleAddComment)
+	e.GET("/sales/:id/comments", salesHandler.handleGetComments)
+	e.PATCH("/sales/:id/comments/:comment_id", salesHandler.handleUpdateComment)
+	e.DELETE("/sales/:id/comments/:comment_id", salesHandler.handleDeleteComment)
+	if salesService.AttachmentsEnabled() {
+		e.POST("/sales/:id/attachments", salesHandler.handleAddAttachment)
+		e.GET("/sales/:id/attachments", salesHandler.handleGetAttachments)
+		e.GET("/sales/:id/attachments/:attachment_id", salesHandler.handleGetAttachment)
+	}
+	e.GET("/reviews/queue", salesHandler.handleGetReviewQueue)
+	e.GET("/sales/:id/audit", salesHandler.handleGetSaleAudit)
+	e.POST("/sales/:id/adjustments", withIdempotency, salesHandler.handleCreateAdjustment)
+	e.GET("/sales/:id/adjustments", salesHandler.handleListAdjustments)
+	e.POST("/sales/:id/disputes", withIdempotency, salesHandler.handleOpenDispute)
+	e.GET("/sales/:id/disputes", salesHandler.handleListDisputes)
+	e.POST("/disputes/:id/resolve", requireRole(adminRole), salesHandler.handleResolveDispute)
+	e.GET("/me/sales", salesHandler.handleGetMySales)
+	publicLimiter := quota.NewLimiter(quota.Policy{"*": {RequestsPerSecond: cfg.PublicLookupRate, Burst: cfg.PublicLookupBurst}})
+	e.GET("/public/sales/:reference/status", limitByClientIP(publicLimiter), salesHandler.handleGetPublicStatus)
+	if cfg.ShareLinkSecret != "" {
+		shares := &shareHandler{
+			salesService: salesService,
+			signer:       shareSigner{secret: []byte(cfg.ShareLinkSecret)},
+			baseURL:      cfg.ShareLinkBaseURL,
+			defaultTTL:   cfg.ShareLinkTTL,
+			maxTTL:       cfg.ShareLinkMaxTTL,
+			logger:       logger,
+		}
+		e.POST("/sales/:id/share", shares.handleCreate)
+		e.GET("/sales/:id/share", shares.handleList)
+		e.DELETE("/share-links/:id", shares.handleRevoke)
+		e.GET("/shared/:token", limitByClientIP(publicLimiter), shares.handleShared)
+	}
+	e.GET("/users/:id/sales/summary", salesHandler.handleGetUserSummary)
+	e.GET("/ledger", salesHandler.handleGetLedger)
+	e.GET("/snapshots/:period", salesHandler.handleGetSnapshot)
+	e.GET("/snapshots/:period/export", salesHandler.handleGetSnapshotExport)
+	e.GET("/tenants/:id/usage", requireRole(adminRole), handleGetTenantUsage(usageTracker, logger))
+	e.POST("/users/:id/sms-opt-out", handleSMSOptOut(smsOptOuts, logger))
+	e.DELETE("/users/:id/sms-opt-out", handleSMSOptIn(smsOptOuts, logger))
+
+	admin := internal.Group("/admin", requireRole(adminRole))
+	admin.GET("/periods", salesHandler.handleListPeriods)
+	admin.POST("/periods/:period/close", salesHandler.handleClosePeriod)
+	// La exportación sale tal como está guardada: solo para admins
+	admin.GET("/audit/export", salesHandler.handleExportAudit)
+	admin.GET("/audit/verify", salesHandler.handleVerifyAudit)
+	admin.POST("/keys", handleCreateKey(keyManager))
+	admin.GET("/keys", handleListKeys(keyManager))
+	admin.POST("/keys/:kid/retire", handleRetireKey(keyManager))
+	admin.GET("/maintenance", handleGetMaintenance(maintenanceSwitch, logger))
+	admin.PUT("/maintenance", handleSetMaintenance(maintenanceSwitch, logger))
+	admin.GET("/tenants/:id/statuses", handleGetTenantStatuses(salesService, logger))
+	admin.PUT("/tenants/:id/statuses", handleSetTenantStatuses(salesService, logger))
+	if sloTracker != nil {
+		admin.GET("/slo", handleGetSLO(sloTracker))
+	}
+	if replicated != nil {
+		admin.GET("/replication", handleGetReplication(replicated))
+		admin.POST("/replication/promote", handlePromoteReplica(replicated, cfg.ReplicationPromoteTimeout, logger))
+	}
+	if cfg.ProvisionalSales {
+		admin.GET("/verifications", salesHandler.handleGetVerifications)
+		admin.POST("/verifications/:id/retry", salesHandler.handleRetryVerification)
+	}
+
+	e.POST("/recurring-sales", withIdempotency, recurringHandler.handleCreate)
+	e.GET("/recurring-sales", recurringHandler.handleList)
+	e.GET("/recurring-sales/:id", recurringHandler.handleGet)
+	e.GET("/recurring-sales/:id/sales", recurringHandler.handleListSales)
+	e.POST("/recurring-sales/:id/pause", recurringHandler.handlePause)
+	e.POST("/recurring-sales/:id/resume", recurringHandler.handleResume)
+	e.DELETE("/recurring-sales/:id", recurringHandler.handleCancel)
 
-	e.POST("/sales", salesHandler.handleCreateSale)
-	e.PATCH("/sales/:id", salesHandler.PatchSaleHandler(salesService))
-	e.GET("/sales", salesHandler.handlerGetSale)
+	internal.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	e.GET("/version", func(c *gin.Context) {
+		c.JSON(http.StatusOK, buildinfo.Get())
+	})
+
+	// Las rutas inexistentes y los métodos equivocados también responden JSON
+	configureRouting(e)
+	if internal != e {
+		configureRouting(internal)
+	}
 
 	e.GET("/ping", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -52,4 +627,273 @@ func InitRoutes2(e *gin.Engine, userServiceURL string) {
 		})
 	})
 
+	// Rutas de la aplicación que embebe la API
+	if err := registerRoutes(e, salesService, deps.Routes); err != nil {
+		return err
+	}
+	if err := registerRoutes(admin, salesService, deps.AdminRoutes); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// postingRecorder adapta una función a erp.StatusRecorder.
+type postingRecorder func(saleID, status string) error
+
+func (f postingRecorder) SetERPPostingStatus(saleID, status string) error {
+	return f(saleID, status)
+}
+
+// userLookup adapta una función a sms.UserLookup.
+type userLookup func(userID string) (*sales.User, error)
+
+func (f userLookup) GetUser(userID string) (*sales.User, error) {
+	return f(userID)
+}
+
+// newLocker crea el lock distribuido configurado para el scheduler y los jobs.
+func newLocker(cfg config.Config, redisClient *redis.Client) (lock.Locker, error) {
+	switch cfg.LockBackend {
+	case "", config.LockBackendLocal:
+		return lock.NewLocalLocker(), nil
+	case config.LockBackendRedis:
+		return lock.NewRedisLocker(redisClient), nil
+	case config.LockBackendPostgres:
+		db, err := sql.Open("pgx", cfg.PostgresDSN)
+		if err != nil {
+			return nil, fmt.Errorf("error opening postgres for locks: %w", err)
+		}
+		return lock.NewPostgresLocker(db), nil
+	default:
+		return nil, fmt.Errorf("unknown lock backend %q", cfg.LockBackend)
+	}
+}
+
+// newSalesStorage crea el storage de ventas del backend indicado.
+func newSalesStorage(cfg config.Config, backend string, redisClient *redis.Client, logger *zap.Logger) (sales.Storage, error) {
+	switch backend {
+	case "", config.BackendMemory:
+		return sales.NewLocalStorage(), nil
+	case config.BackendDynamoDB:
+		awsCfg, err := awsconfig.LoadDefaultConfig(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("error loading aws config for dynamodb: %w", err)
+		}
+		storage := sales.NewDynamoDBStorage(dynamodb.NewFromConfig(awsCfg), cfg.DynamoDBTable)
+		storage.SetUserIndex(cfg.DynamoDBUserIndex)
+		return withStorageRetries(cfg, backend, storage, logger), nil
+	case config.BackendMySQL:
+		storage, err := newMySQLStorage(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return withStorageRetries(cfg, backend, storage, logger), nil
+	case config.BackendSQLite:
+		return newSQLiteStorage(cfg)
+	case config.BackendRedis:
+		storage := sales.NewRedisStorage(redisClient, "api_sales:", cfg.RedisSalesTTL, cfg.RedisSalesTimeout)
+		return withStorageRetries(cfg, backend, storage, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown sales storage backend %q", backend)
+	}
+}
+
+// newMySQLStorage abre el pool de MYSQL_DSN y prepara el esquema.
+func newMySQLStorage(cfg config.Config) (*sales.MySQLStorage, error) {
+	storage, db, err := openMySQLStorage(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := prepareSchema(cfg, config.BackendMySQL, storage); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return storage, nil
+}
+
+func openMySQLStorage(cfg config.Config) (*sales.MySQLStorage, *sql.DB, error) {
+	db, err := sql.Open("mysql", cfg.MySQLDSN)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error opening mysql for sales: %w", err)
+	}
+	db.SetMaxOpenConns(cfg.MySQLMaxOpenConns)
+	db.SetMaxIdleConns(cfg.MySQLMaxIdleConns)
+	db.SetConnMaxLifetime(cfg.MySQLConnMaxLifetime)
+
+	storage, err := sales.NewMySQLStorage(db, cfg.MySQLTable, cfg.MySQLTimeout)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	return storage, db, nil
+}
+
+// newSQLiteStorage abre el archivo de SQLITE_PATH y prepara el esquema.
+func newSQLiteStorage(cfg config.Config) (*sales.SQLiteStorage, error) {
+	storage, db, err := openSQLiteStorage(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := prepareSchema(cfg, config.BackendSQLite, storage); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return storage, nil
+}
+
+func openSQLiteStorage(cfg config.Config) (*sales.SQLiteStorage, *sql.DB, error) {
+	db, err := sql.Open("sqlite", cfg.SQLitePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error opening sqlite for sales: %w", err)
+	}
+	// SQLite admite un solo escritor: una conexión serializa las escrituras
+	// en lugar de fallar con SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+	return sales.NewSQLiteStorage(db, cfg.SQLiteTimeout), db, nil
+}
+
+// newReplicaStorage crea el storage secundario de REPLICATION_BACKEND, en la
+// región de REPLICATION_REGION.
+func newReplicaStorage(cfg config.Config, logger *zap.Logger) (sales.Storage, error) {
+	switch cfg.ReplicationBackend {
+	case config.BackendMemory:
+		return sales.NewLocalStorage(), nil
+	case config.BackendDynamoDB:
+		awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), awsconfig.WithRegion(cfg.ReplicationRegion))
+		if err != nil {
+			return nil, fmt.Errorf("error loading aws config for the dynamodb replica: %w", err)
+		}
+		table := cfg.ReplicationDynamoDBTable
+		if table == "" {
+			table = cfg.DynamoDBTable
+		}
+		return withStorageRetries(cfg, cfg.ReplicationBackend, sales.NewDynamoDBStorage(dynamodb.NewFromConfig(awsCfg), table), logger), nil
+	default:
+		return nil, fmt.Errorf("unknown replication backend %q", cfg.ReplicationBackend)
+	}
+}
+
+// withStorageRetries reintenta los errores transitorios del backend según su
+// política en STORAGE_RETRY_ATTEMPTS.
+func withStorageRetries(cfg config.Config, backend string, storage sales.Storage, logger *zap.Logger) sales.Storage {
+	attempts := cfg.StorageRetryAttempts[backend]
+	if attempts <= 1 {
+		return storage
+	}
+	policy := sales.RetryPolicy{
+		MaxAttempts: attempts,
+		Backoff:     cfg.StorageRetryBackoff[backend],
+		MaxBackoff:  cfg.StorageRetryMaxBackoff,
+	}
+	switch backend {
+	case config.BackendDynamoDB:
+		policy.Transient = sales.IsTransientDynamoDBError
+	case config.BackendMySQL:
+		policy.Transient = sales.IsTransientMySQLError
+	case config.BackendRedis:
+		policy.Transient = sales.IsTransientRedisError
+	}
+	return sales.NewRetryingStorage(storage, backend, policy, logger)
+}
+
+// newEnrichers arma los enrichers de ENRICHERS en el orden configurado; sin
+// política explícita un enricher solo advierte.
+func newEnrichers(cfg config.Config, transport http.RoundTripper) ([]sales.Option, error) {
+	opts := make([]sales.Option, 0, len(cfg.Enrichers))
+	for _, item := range cfg.Enrichers {
+		name, rawPolicy, _ := strings.Cut(item, "=")
+		policy := sales.EnrichWarn
+		if rawPolicy != "" {
+			var err error
+			if policy, err = sales.ParseEnrichPolicy(rawPolicy); err != nil {
+				return nil, err
+			}
+		}
+
+		var enricher sales.Enricher
+		switch name {
+		case enrich.NameGeoIP:
+			geo := enrich.NewGeoIP(cfg.GeoIPURL)
+			geo.SetTransport(transport)
+			enricher = geo
+		case enrich.NameSegment:
+			enricher = enrich.NewSegment(cfg.SegmentThresholds)
+		default:
+			return nil, fmt.Errorf("unknown enricher %q", name)
+		}
+		opts = append(opts, sales.WithEnricher(enricher, policy))
+	}
+	return opts, nil
+}
+
+func newIdempotencyStore(cfg config.Config, redisClient *redis.Client) (idempotency.Store, error) {
+	switch cfg.IdempotencyBackend {
+	case "", config.BackendMemory:
+		return idempotency.NewMemoryStore(), nil
+	case config.BackendRedis:
+		return idempotency.NewRedisStore(redisClient), nil
+	default:
+		return nil, fmt.Errorf("unknown idempotency backend %q", cfg.IdempotencyBackend)
+	}
+}
+
+func newRecurringStorage(cfg config.Config, redisClient *redis.Client) (sales.RecurringStorage, error) {
+	switch cfg.RecurringStorageBackend {
+	case "", config.BackendMemory:
+		return sales.NewLocalRecurringStorage(), nil
+	case config.BackendRedis:
+		return sales.NewRedisRecurringStorage(redisClient, "api_sales:", cfg.RedisSalesTimeout), nil
+	default:
+		return nil, fmt.Errorf("unknown recurring storage backend %q", cfg.RecurringStorageBackend)
+	}
+}
+
+func newUserCache(cfg config.Config, redisClient *redis.Client) (sales.UserCache, error) {
+	switch cfg.UserCacheBackend {
+	case "", config.BackendMemory:
+		return sales.NewLocalUserCache(cfg.UserCacheTTL), nil
+	case config.BackendRedis:
+		return sales.NewRedisUserCache(redisClient, cfg.UserCacheTTL), nil
+	default:
+		return nil, fmt.Errorf("unknown user cache backend %q", cfg.UserCacheBackend)
+	}
+}
+
+// newMaintenanceSwitch crea el interruptor de mantenimiento con el modo
+// configurado como estado inicial.
+func newMaintenanceSwitch(cfg config.Config, redisClient *redis.Client) (*maintenance.Switch, error) {
+	mode, err := maintenance.ParseMode(cfg.MaintenanceMode)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MAINTENANCE_MODE: %w", err)
+	}
+	initial := maintenance.State{Mode: mode, RetryAfter: int(cfg.MaintenanceRetryAfter.Seconds())}
+
+	switch cfg.MaintenanceBackend {
+	case "", config.BackendMemory:
+		return maintenance.NewSwitch(maintenance.NewMemoryStore(), initial, cfg.MaintenanceRefresh), nil
+	case config.BackendRedis:
+		return maintenance.NewSwitch(maintenance.NewRedisStore(redisClient), initial, cfg.MaintenanceRefresh), nil
+	default:
+		return nil, fmt.Errorf("unknown maintenance backend %q", cfg.MaintenanceBackend)
+	}
+}
+
+// newUsageTracker crea el contador de uso por tenant con las cuotas configuradas.
+func newUsageTracker(cfg config.Config, redisClient *redis.Client) (*quota.Tracker, error) {
+	policy := quota.Policy{}
+	if cfg.TenantQuotas != "" {
+		if err := json.Unmarshal([]byte(cfg.TenantQuotas), &policy); err != nil {
+			return nil, fmt.Errorf("invalid TENANT_QUOTAS: %w", err)
+		}
+	}
+
+	switch cfg.TenantUsageBackend {
+	case "", config.BackendMemory:
+		return quota.NewTracker(quota.NewMemoryStore(), policy), nil
+	case config.BackendRedis:
+		return quota.NewTracker(quota.NewRedisStore(redisClient), policy), nil
+	default:
+		return nil, fmt.Errorf("unknown tenant usage backend %q", cfg.TenantUsageBackend)
+	}
 }
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// configureRouting makes e answer like the rest of the API when no route
+// matches: a JSON 404 for unknown paths and a JSON 405 with Allow for known
+// paths called with another method. A trailing slash is an unknown path for
+// every method; gin's redirects answer 301 to GETs and 307 to writes, which
+// some clients replay as a GET without the body.
+func configureRouting(e *gin.Engine) {
+	e.RedirectTrailingSlash = false
+	e.RedirectFixedPath = false
+	e.HandleMethodNotAllowed = true
+
+	e.NoRoute(func(ctx *gin.Context) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  "route not found",
+			"method": ctx.Request.Method,
+			"path":   ctx.Request.URL.Path,
+		})
+	})
+	e.NoMethod(func(ctx *gin.Context) {
+		// gin ya escribió el header Allow con los métodos de la ruta
+		ctx.JSON(http.StatusMethodNotAllowed, gin.H{
+			"error":   "method not allowed",
+			"method":  ctx.Request.Method,
+			"path":    ctx.Request.URL.Path,
+			"allowed": strings.Split(ctx.Writer.Header().Get("Allow"), ", "),
+		})
+	})
+}
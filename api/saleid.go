@@ -0,0 +1,39 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// saleIDRoute is the prefix of the routes with a sale ID path parameter.
+const saleIDRoute = "/sales/:id"
+
+// validateSaleID answers 400 on the /sales/:id routes when the ID is not a
+// UUID, the format every sale is created with, so malformed IDs never reach
+// the storage and a 404 means the sale doesn't exist. Tracking numbers and
+// other references are looked up with GET /sales/by-reference/:reference.
+func validateSaleID(ctx *gin.Context) {
+	path := ctx.FullPath()
+	if path != saleIDRoute && !strings.HasPrefix(path, saleIDRoute+"/") {
+		ctx.Next()
+		return
+	}
+	if !isSaleID(ctx.Param("id")) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid sale id: expected a UUID"})
+		return
+	}
+	ctx.Next()
+}
+
+// isSaleID acepta solo la forma canónica de 36 caracteres; uuid.Parse también
+// acepta llaves y el prefijo urn:uuid:.
+func isSaleID(id string) bool {
+	if len(id) != 36 {
+		return false
+	}
+	_, err := uuid.Parse(id)
+	return err == nil
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"api_sales/internal/config"
+	"api_sales/internal/erp"
+	"api_sales/internal/selfcheck"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SelfCheck verifies cfg and the dependencies it points to before the API
+// starts serving, returning the readiness report.
+func SelfCheck(ctx context.Context, cfg config.Config) selfcheck.Report {
+	checks := []selfcheck.Check{
+		{
+			Name: "config",
+			Hint: "fix the environment variables listed in the error",
+			Run:  func(context.Context) error { return cfg.Validate() },
+		},
+		{
+			Name: "redis",
+			Hint: "check REDIS_ADDR and that Redis accepts connections",
+			Run: func(ctx context.Context) error {
+				if !usesRedis(cfg) {
+					return fmt.Errorf("%w: no backend uses redis", selfcheck.ErrSkipped)
+				}
+				client := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr})
+				defer client.Close()
+				return client.Ping(ctx).Err()
+			},
+		},
+		{
+			Name: "postgres",
+			Hint: "check POSTGRES_DSN and that Postgres accepts connections",
+			Run: func(ctx context.Context) error {
+				if cfg.LockBackend != config.LockBackendPostgres {
+					return fmt.Errorf("%w: LOCK_BACKEND is not postgres", selfcheck.ErrSkipped)
+				}
+				db, err := sql.Open("pgx", cfg.PostgresDSN)
+				if err != nil {
+					return err
+				}
+				defer db.Close()
+				return db.PingContext(ctx)
+			},
+		},
+		{
+			Name: "mysql",
+			Hint: "check MYSQL_DSN and that MySQL accepts connections",
+			Run: func(ctx context.Context) error {
+				if cfg.SalesStorageBackend != config.BackendMySQL && cfg.ShadowStorageBackend != config.BackendMySQL {
+					return fmt.Errorf("%w: no sales storage uses mysql", selfcheck.ErrSkipped)
+				}
+				db, err := sql.Open("mysql", cfg.MySQLDSN)
+				if err != nil {
+					return err
+				}
+				defer db.Close()
+				return db.PingContext(ctx)
+			},
+		},
+		{
+			Name: "sqlite",
+			Hint: "check that SQLITE_PATH is a writable SQLite file",
+			Run: func(ctx context.Context) error {
+				if cfg.SalesStorageBackend != config.BackendSQLite && cfg.ShadowStorageBackend != config.BackendSQLite {
+					return fmt.Errorf("%w: no sales storage uses sqlite", selfcheck.ErrSkipped)
+				}
+				db, err := sql.Open("sqlite", cfg.SQLitePath)
+				if err != nil {
+					return err
+				}
+				defer db.Close()
+				conn, err := db.Conn(ctx)
+				if err != nil {
+					return err
+				}
+				defer conn.Close()
+				// BEGIN IMMEDIATE toma el lock de escritura: falla si el archivo es de solo lectura
+				if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+					return err
+				}
+				_, err = conn.ExecContext(ctx, "ROLLBACK")
+				return err
+			},
+		},
+		{
+			Name: "migrations",
+			Hint: "run the API with -migrate, or set MIGRATE_ON_START=true",
+			Run: func(ctx context.Context) error {
+				// Postgres solo guarda advisory locks: migran los backends SQL de ventas
+				backends := sqlBackends(cfg)
+				if len(backends) == 0 {
+					return fmt.Errorf("%w: no sales storage uses mysql or sqlite", selfcheck.ErrSkipped)
+				}
+				for _, backend := range backends {
+					runner, db, err := openMigrator(cfg, backend)
+					if err != nil {
+						return err
+					}
+					err = checkPending(ctx, runner, backend)
+					db.Close()
+					// Con MIGRATE_ON_START las pendientes se aplican al iniciar
+					if err != nil && !(cfg.MigrateOnStart && errors.Is(err, errPendingMigrations)) {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Name: "user_service",
+			Hint: "check USER_SERVICE_URL and that the user service is running",
+			Run:  selfcheck.Reachable(&http.Client{}, cfg.UserServiceURL),
+		},
+		{
+			Name: "erp_template",
+			Hint: "check ERP_TEMPLATE_FILE exists and is a valid text/template",
+			Run: func(context.Context) error {
+				if cfg.ERPURL == "" {
+					return fmt.Errorf("%w: ERP_URL is not set", selfcheck.ErrSkipped)
+				}
+				mapping, err := erp.LoadTemplate(cfg.ERPTemplateFile)
+				if err != nil {
+					return err
+				}
+				_, err = erp.NewHTTPPoster(cfg.ERPURL, mapping)
+				return err
+			},
+		},
+	}
+	return selfcheck.Run(ctx, cfg.SelfCheckTimeout, checks)
+}
+
+// usesRedis indica si algún backend configurado necesita el cliente Redis.
+func usesRedis(cfg config.Config) bool {
+	return cfg.LockBackend == config.LockBackendRedis ||
+		cfg.IdempotencyBackend == config.BackendRedis ||
+		cfg.UserCacheBackend == config.BackendRedis ||
+		cfg.RecurringStorageBackend == config.BackendRedis ||
+		cfg.MaintenanceBackend == config.BackendRedis ||
+		cfg.TenantUsageBackend == config.BackendRedis ||
+		cfg.SalesStorageBackend == config.BackendRedis ||
+		cfg.ShadowStorageBackend == config.BackendRedis
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"api_sales/internal/sales"
+	"context"
+	"time"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.53.7 --name SalesService --inpackage --testonly --with-expecter
+
+// SalesService is what the sale handlers need from *sales.Service, so they can
+// be tested against a mock.
+type SalesService interface {
+	// Altas y cambios de estado
+	CreateSaleWithOrigin(userID string, amount float64, metadata map[string]string, origin sales.Origin) (*sales.Sale, error)
+	CreateDraftSaleWithOrigin(userID string, amount float64, metadata map[string]string, origin sales.Origin) (*sales.Sale, error)
+	CreateDraftFromReceipt(ctx context.Context, userID, filename string, data []byte, origin sales.Origin, uploadedBy string) (*sales.ReceiptDraft, error)
+	SubmitSale(saleID string) (*sales.Sale, error)
+	UpdateSaleStatusAs(saleID, newStatus string, actor sales.Actor) (*sales.Sale, error)
+	RepeatedStatusChange(saleID, newStatus string, window time.Duration) (*sales.Sale, bool)
+	CurrentState(saleID string) (sales.SaleState, error)
+	AllowedTransitions(sale *sales.Sale, resolveDisputes bool) ([]string, error)
+	EditSaleAs(saleID string, edit sales.SaleEdit, actor sales.Actor) (*sales.Sale, error)
+	UpdateFulfillment(saleID string, update sales.FulfillmentUpdate, actor sales.Actor) (*sales.Sale, error)
+
+	// Consultas y reportes
+	GetSale(saleID string, caller *sales.Caller) (*sales.Sale, error)
+	GetSaleByReference(reference string, caller *sales.Caller) (*sales.Sale, error)
+	GetSales(ids []string, caller *sales.Caller, consistency sales.Consistency) (found []*sales.Sale, missing []string, err error)
+	SearchSale(filter sales.SearchFilter) (results []*sales.Sale, metadata sales.SalesMetadata, err error)
+	ListUserSales(userID string, limit int, cursor string) (sales.Page, error)
+	GetUserSummary(userID string) (*sales.UserSummary, error)
+	GetStats() (sales.SalesMetadata, error)
+	DailyTotals(loc *time.Location) ([]sales.DailyTotal, error)
+	Cohorts(loc *time.Location) ([]sales.Cohort, error)
+	Ledger(from, to *time.Time) ([]sales.LedgerLine, error)
+	PublicSaleStatus(reference string) (sales.PublicStatus, error)
+	RedactSensitive(sale *sales.Sale) *sales.Sale
+	WithSLAStatus(list []*sales.Sale, now time.Time) []*sales.Sale
+
+	// Ajustes, disputas y períodos contables
+	AdjustSale(saleID string, amount float64, reason, createdBy string) (*sales.Adjustment, error)
+	GetSaleAdjustments(saleID string) ([]*sales.Adjustment, error)
+	OpenDispute(saleID, reason string, openedBy sales.Actor) (*sales.Dispute, error)
+	ResolveDispute(disputeID, outcome string, resolvedBy sales.Actor) (*sales.Dispute, error)
+	GetSaleDisputes(saleID string) ([]*sales.Dispute, error)
+	ClosePeriod(period, closedBy string) (*sales.AccountingPeriod, error)
+	ListClosedPeriods() ([]*sales.AccountingPeriod, error)
+	GetPeriodSnapshot(ctx context.Context, period string) (*sales.PeriodSnapshot, error)
+	GetPeriodSnapshotExport(ctx context.Context, period string) ([]byte, *sales.PeriodSnapshot, error)
+
+	// Auditoría
+	GetSaleAudit(saleID string, caller *sales.Caller) ([]*sales.AuditEntry, error)
+	ExportAudit() ([]*sales.AuditEntry, error)
+	VerifyAudit() (sales.AuditVerification, error)
+
+	// Revisión, comentarios, adjuntos y links compartidos
+	ReviewQueue(reviewer string) ([]sales.ReviewItem, error)
+	ClaimSale(saleID, reviewer string) (*sales.Assignment, error)
+	UnclaimSale(saleID, reviewer string) error
+	AddComment(saleID, author, body string) (*sales.Comment, error)
+	GetSaleComments(saleID string) ([]*sales.Comment, error)
+	UpdateComment(saleID, commentID, author, body string) (*sales.Comment, error)
+	DeleteComment(saleID, commentID, author string, moderate bool) error
+	AttachmentMaxBytes() int
+	AddAttachment(ctx context.Context, saleID, filename, contentType string, data []byte, uploadedBy string) (*sales.Attachment, error)
+	GetSaleAttachments(saleID string) ([]*sales.Attachment, error)
+	GetAttachmentFile(ctx context.Context, saleID, attachmentID string) (*sales.Attachment, []byte, error)
+	CreateShareLink(saleID string, ttl time.Duration, actor sales.Actor, caller *sales.Caller) (*sales.ShareLink, error)
+	GetSaleShareLinks(saleID string, caller *sales.Caller) ([]*sales.ShareLink, error)
+	RevokeShareLink(linkID string, actor sales.Actor) (*sales.ShareLink, error)
+	SharedReceipt(linkID string, now time.Time) (*sales.Receipt, error)
+
+	// Ventas provisionales
+	PendingVerifications(stuckOnly bool) ([]sales.PendingVerification, error)
+	RetryVerification(saleID string) (*sales.Verification, error)
+}
+
+var _ SalesService = (*sales.Service)(nil)
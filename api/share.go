@@ -0,0 +1,191 @@
+package api
+
+import (
+	"api_sales/internal/sales"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Error para tokens de links compartidos mal formados o con firma inválida
+var errInvalidShareToken = errors.New("invalid share token")
+
+// shareSigner signs the tokens of share links, so a link can't be guessed
+// from its ID or extended by editing its expiry.
+type shareSigner struct {
+	secret []byte
+}
+
+// token returns "<link id>.<expiry unix>.<signature>".
+func (s shareSigner) token(link *sales.ShareLink) string {
+	payload := link.ID + "." + strconv.FormatInt(link.ExpiresAt.Unix(), 10)
+	return payload + "." + s.sign(payload)
+}
+
+// linkID verifies token and returns the link it points to. The expiry in the
+// token is checked here so expired links don't reach the storage.
+func (s shareSigner) linkID(token string, now time.Time) (string, error) {
+	payload, sig, ok := cutLast(token, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(s.sign(payload))) {
+		return "", errInvalidShareToken
+	}
+	id, rawExpiry, ok := cutLast(payload, ".")
+	if !ok {
+		return "", errInvalidShareToken
+	}
+	expiry, err := strconv.ParseInt(rawExpiry, 10, 64)
+	if err != nil {
+		return "", errInvalidShareToken
+	}
+	if now.Unix() >= expiry {
+		return "", sales.ErrShareLinkExpired
+	}
+	return id, nil
+}
+
+func (s shareSigner) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
+
+// shareLinkResponse is a share link with the URL to send to the customer.
+type shareLinkResponse struct {
+	*sales.ShareLink
+	URL string `json:"url"`
+}
+
+// shareHandler serves the share link endpoints.
+type shareHandler struct {
+	salesService SalesService
+	signer       shareSigner
+	baseURL      string
+	defaultTTL   time.Duration
+	maxTTL       time.Duration
+	logger       *zap.Logger
+}
+
+// url arma el link público; sin base URL configurada usa el host del request.
+func (h *shareHandler) url(ctx *gin.Context, link *sales.ShareLink) string {
+	base := strings.TrimSuffix(h.baseURL, "/")
+	if base == "" {
+		scheme := "http"
+		if ctx.Request.TLS != nil || ctx.GetHeader("X-Forwarded-Proto") == "https" {
+			scheme = "https"
+		}
+		base = scheme + "://" + ctx.Request.Host
+	}
+	return base + "/shared/" + h.signer.token(link)
+}
+
+// handleCreate handles the POST /sales/:id/share endpoint. The optional
+// ttl_seconds in the body is capped at the configured maximum.
+func (h *shareHandler) handleCreate(ctx *gin.Context) {
+	var req struct {
+		TTLSeconds int `json:"ttl_seconds"`
+	}
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			h.logger.Warn("failed to bind JSON request", zap.Error(err))
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request payload"})
+			return
+		}
+	}
+	ttl := h.defaultTTL
+	if req.TTLSeconds != 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+	if ttl <= 0 || ttl > h.maxTTL {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ttl_seconds: expected between 1 and " + strconv.Itoa(int(h.maxTTL.Seconds()))})
+		return
+	}
+
+	link, err := h.salesService.CreateShareLink(ctx.Param("id"), ttl, actor(ctx), caller(ctx))
+	if err != nil {
+		if err == sales.ErrNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "sale not found"})
+			return
+		}
+		h.logger.Error("failed to create share link", zap.Error(err), zap.String("sale_id", ctx.Param("id")))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create share link"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, shareLinkResponse{ShareLink: link, URL: h.url(ctx, link)})
+}
+
+// handleList handles the GET /sales/:id/share endpoint.
+func (h *shareHandler) handleList(ctx *gin.Context) {
+	links, err := h.salesService.GetSaleShareLinks(ctx.Param("id"), caller(ctx))
+	if err != nil {
+		if err == sales.ErrNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "sale not found"})
+			return
+		}
+		h.logger.Error("failed to list share links", zap.Error(err), zap.String("sale_id", ctx.Param("id")))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list share links"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"share_links": links})
+}
+
+// handleRevoke handles the DELETE /share-links/:id endpoint.
+func (h *shareHandler) handleRevoke(ctx *gin.Context) {
+	link, err := h.salesService.RevokeShareLink(ctx.Param("id"), actor(ctx))
+	if err != nil {
+		if err == sales.ErrShareLinkNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to revoke share link", zap.Error(err), zap.String("link_id", ctx.Param("id")))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to revoke share link"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, link)
+}
+
+// handleShared handles the unauthenticated GET /shared/:token endpoint: the
+// receipt of the shared sale while the link is valid.
+func (h *shareHandler) handleShared(ctx *gin.Context) {
+	now := time.Now()
+	id, err := h.signer.linkID(ctx.Param("token"), now)
+	var receipt *sales.Receipt
+	if err == nil {
+		receipt, err = h.salesService.SharedReceipt(id, now)
+	}
+	if err != nil {
+		switch err {
+		case sales.ErrShareLinkExpired, sales.ErrShareLinkRevoked:
+			ctx.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		case errInvalidShareToken, sales.ErrShareLinkNotFound, sales.ErrNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "share link not found"})
+		default:
+			h.logger.Error("failed to load shared receipt", zap.Error(err))
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve receipt"})
+		}
+		return
+	}
+
+	// El link es la credencial: la respuesta no se guarda en caches
+	ctx.Header("Cache-Control", "private, no-store")
+	ctx.Header("Referrer-Policy", "no-referrer")
+	ctx.JSON(http.StatusOK, receipt)
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"api_sales/internal/sales"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var sheddingActive = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "sales_load_shedding_active",
+	Help: "1 while low-priority requests are rejected because storage is degraded.",
+})
+
+var shedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "sales_shed_requests_total",
+	Help: "Low-priority requests rejected with 503 while storage was degraded.",
+}, []string{"route"})
+
+// requestPriority clasifica las requests: las de baja prioridad son las que
+// más leen del storage y pueden esperar.
+type requestPriority int
+
+const (
+	priorityNormal requestPriority = iota
+	priorityLow
+)
+
+// lowPriorityRoutes are the reports and exports shed first.
+var lowPriorityRoutes = map[string]bool{
+	"/sales/stats":              true,
+	"/sales/reports/cohorts":    true,
+	"/ledger":                   true,
+	"/snapshots/:period/export": true,
+	"/users/:id/sales/summary":  true,
+}
+
+// classifyRequest returns the priority of a request. Writes are never low
+// priority; the search is only when streamed as an NDJSON export.
+func classifyRequest(ctx *gin.Context) requestPriority {
+	if !isRead(ctx.Request.Method) {
+		return priorityNormal
+	}
+	route := ctx.FullPath()
+	if lowPriorityRoutes[route] || (route == "/sales" && wantsNDJSON(ctx.Request)) {
+		return priorityLow
+	}
+	return priorityNormal
+}
+
+// sheddingThresholds are the limits of the storage health past which
+// low-priority requests are shed. A zero limit is not checked.
+type sheddingThresholds struct {
+	MaxP95Latency time.Duration
+	MaxErrorRate  float64
+	MinSamples    int
+	RetryAfter    time.Duration
+}
+
+type loadShedder struct {
+	health     *sales.StorageHealth
+	thresholds sheddingThresholds
+	logger     *zap.Logger
+	shedding   atomic.Bool
+}
+
+// degraded reports whether storage is past a threshold and why.
+func (l *loadShedder) degraded(now time.Time) (bool, string) {
+	snapshot := l.health.Snapshot(now)
+	reason := ""
+	switch {
+	case snapshot.Samples < l.thresholds.MinSamples:
+	case l.thresholds.MaxErrorRate > 0 && snapshot.ErrorRate > l.thresholds.MaxErrorRate:
+		reason = fmt.Sprintf("storage error rate %.2f above %.2f", snapshot.ErrorRate, l.thresholds.MaxErrorRate)
+	case l.thresholds.MaxP95Latency > 0 && snapshot.P95Latency > l.thresholds.MaxP95Latency:
+		reason = fmt.Sprintf("storage p95 latency %s above %s", snapshot.P95Latency, l.thresholds.MaxP95Latency)
+	}
+
+	degraded := reason != ""
+	// Solo se registran los cambios de estado
+	if l.shedding.Swap(degraded) != degraded {
+		if degraded {
+			sheddingActive.Set(1)
+			l.logger.Warn("shedding low-priority traffic", zap.String("reason", reason), zap.Int("samples", snapshot.Samples))
+		} else {
+			sheddingActive.Set(0)
+			l.logger.Info("storage recovered, load shedding stopped")
+		}
+	}
+	return degraded, reason
+}
+
+// shedLoad answers 503 with Retry-After to low-priority requests while
+// storage is degraded, keeping it available for creating and updating sales.
+// The admin endpoints are never shed.
+func shedLoad(shedder *loadShedder) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if strings.HasPrefix(ctx.Request.URL.Path, "/admin/") || classifyRequest(ctx) != priorityLow {
+			ctx.Next()
+			return
+		}
+		degraded, reason := shedder.degraded(time.Now())
+		if !degraded {
+			ctx.Next()
+			return
+		}
+
+		shedRequests.WithLabelValues(ctx.FullPath()).Inc()
+		if shedder.thresholds.RetryAfter > 0 {
+			ctx.Header("Retry-After", strconv.Itoa(int(shedder.thresholds.RetryAfter.Seconds())))
+		}
+		ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+			"error":  "service degraded, low-priority request shed",
+			"reason": reason,
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"api_sales/internal/sales"
+	"api_sales/internal/shipping"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// maxShippingWebhookBody limita el cuerpo leído antes de verificar la firma.
+const maxShippingWebhookBody = 1 << 20
+
+// handleShippingWebhook handles the POST /webhooks/shipping endpoint. Carrier
+// events are verified against secret and applied to the fulfillment of the
+// referenced sale; duplicates and events older than the sale's current
+// fulfillment status are acknowledged without changes.
+func handleShippingWebhook(svc *sales.Service, secret string, tolerance time.Duration, logger *zap.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxShippingWebhookBody))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request payload"})
+			return
+		}
+		if err := shipping.Verify(secret, ctx.GetHeader(shipping.SignatureHeader), body, time.Now(), tolerance); err != nil {
+			logger.Warn("rejected shipping webhook", zap.Error(err))
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		event, err := shipping.Decode(body)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		status, ok := event.FulfillmentStatus()
+		if !ok {
+			ctx.JSON(http.StatusOK, gin.H{"result": "ignored"})
+			return
+		}
+
+		reference := event.Reference
+		if reference == "" {
+			reference = event.TrackingNumber
+		}
+		at := event.OccurredAt
+		if at.IsZero() {
+			at = time.Now()
+		}
+		sale, applied, err := svc.ApplyFulfillmentEvent(reference, sales.FulfillmentUpdate{
+			Status:         status,
+			Carrier:        event.Carrier,
+			TrackingNumber: event.TrackingNumber,
+		}, at, sales.Actor{ID: "carrier:" + event.Carrier})
+		if err != nil {
+			logger.Warn("failed to apply shipping event", zap.Error(err), zap.String("event_id", event.ID), zap.String("reference", reference))
+			switch err {
+			case sales.ErrNotFound:
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "sale not found"})
+			case sales.ErrNotFulfillable:
+				ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				if err.Error() == "invalid shipment details" {
+					ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to apply shipping event"})
+			}
+			return
+		}
+
+		result := "applied"
+		if !applied {
+			result = "duplicate"
+		}
+		logger.Info("shipping event processed", zap.String("event_id", event.ID), zap.String("sale_id", sale.ID), zap.String("result", result))
+		ctx.JSON(http.StatusOK, gin.H{"result": result, "sale_id": sale.ID, "fulfillment_status": sale.FulfillmentStatus})
+	}
+}
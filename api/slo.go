@@ -0,0 +1,25 @@
+package api
+
+import (
+	"api_sales/internal/slo"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// trackSLOs counts every request against the SLOs of its route.
+func trackSLOs(tracker *slo.Tracker) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+		tracker.Observe(ctx.Request.Method, ctx.FullPath(), ctx.Writer.Status(), time.Since(start))
+	}
+}
+
+// handleGetSLO handles the GET /admin/slo endpoint.
+func handleGetSLO(tracker *slo.Tracker) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"slos": tracker.Status()})
+	}
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"api_sales/internal/sms"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// handleSMSOptOut handles the POST /users/:id/sms-opt-out endpoint.
+func handleSMSOptOut(store sms.OptOutStore, logger *zap.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if err := store.OptOut(ctx.Param("id")); err != nil {
+			logger.Error("failed to record sms opt-out", zap.Error(err), zap.String("user_id", ctx.Param("id")))
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// handleSMSOptIn handles the DELETE /users/:id/sms-opt-out endpoint.
+func handleSMSOptIn(store sms.OptOutStore, logger *zap.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if err := store.OptIn(ctx.Param("id")); err != nil {
+			logger.Error("failed to remove sms opt-out", zap.Error(err), zap.String("user_id", ctx.Param("id")))
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"api_sales/internal/sales"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// handleGetSnapshot handles the GET /snapshots/:period endpoint, returning the
+// totals and export checksum stored when the period was closed.
+func (h *salesHandler) handleGetSnapshot(ctx *gin.Context) {
+	snapshot, err := h.salesService.GetPeriodSnapshot(ctx.Request.Context(), ctx.Param("period"))
+	if err != nil {
+		h.snapshotError(ctx, err)
+		return
+	}
+
+	// El snapshot no cambia nunca
+	ctx.Header("Cache-Control", "public, max-age=31536000, immutable")
+	ctx.JSON(http.StatusOK, snapshot)
+}
+
+// handleGetSnapshotExport handles the GET /snapshots/:period/export endpoint,
+// streaming the NDJSON export of the period once verified against its
+// checksum.
+func (h *salesHandler) handleGetSnapshotExport(ctx *gin.Context) {
+	export, snapshot, err := h.salesService.GetPeriodSnapshotExport(ctx.Request.Context(), ctx.Param("period"))
+	if err != nil {
+		h.snapshotError(ctx, err)
+		return
+	}
+
+	ctx.Header("Cache-Control", "public, max-age=31536000, immutable")
+	ctx.Header("ETag", `"`+snapshot.Export.SHA256+`"`)
+	ctx.Header("Content-Disposition", `attachment; filename="sales-`+snapshot.Period+`.ndjson"`)
+	ctx.Data(http.StatusOK, ndjsonMediaType, export)
+}
+
+func (h *salesHandler) snapshotError(ctx *gin.Context, err error) {
+	switch err {
+	case sales.ErrInvalidPeriod:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case sales.ErrSnapshotNotFound:
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("failed to read period snapshot", zap.Error(err), zap.String("period", ctx.Param("period")))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read period snapshot"})
+	}
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"api_sales/internal/quota"
+	"api_sales/internal/sales"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// tenantHeader names the tenant of requests without a tenant-bound API key.
+const tenantHeader = "X-Tenant-ID"
+
+// tenantKey is the gin context key where authentication stores the tenant.
+const tenantKey = "tenant"
+
+// tenantID returns the tenant of the request: the one bound to the API key,
+// or the X-Tenant-ID header.
+func tenantID(ctx *gin.Context) string {
+	if t := ctx.GetString(tenantKey); t != "" {
+		return t
+	}
+	return ctx.GetHeader(tenantHeader)
+}
+
+// enforceQuotas counts every tenant request and created sale, answering 429
+// with Retry-After when the tenant exceeds its rate limit or monthly quota.
+// Requests without a tenant are not tracked. If the usage store fails the
+// request goes through rather than failing the API.
+func enforceQuotas(tracker *quota.Tracker, logger *zap.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenant := tenantID(ctx)
+		if tenant == "" {
+			ctx.Next()
+			return
+		}
+
+		retryAfter, err := tracker.Request(ctx.Request.Context(), tenant)
+		createsSale := ctx.Request.Method == http.MethodPost && ctx.FullPath() == "/sales"
+		if err == nil && createsSale {
+			retryAfter, err = tracker.CheckSales(ctx.Request.Context(), tenant)
+		}
+		switch err {
+		case nil:
+		case quota.ErrRateLimited, quota.ErrRequestQuotaExceeded, quota.ErrSalesQuotaExceeded:
+			ctx.Header("Retry-After", strconv.Itoa(int(retryAfter.Round(time.Second).Seconds())))
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		default:
+			logger.Warn("failed to track tenant usage", zap.String("tenant", tenant), zap.Error(err))
+		}
+
+		ctx.Next()
+
+		if createsSale && ctx.Writer.Status() == http.StatusCreated {
+			if err := tracker.SaleCreated(ctx.Request.Context(), tenant); err != nil {
+				logger.Warn("failed to track tenant sale", zap.String("tenant", tenant), zap.Error(err))
+			}
+		}
+	}
+}
+
+// handleGetTenantUsage handles the GET /tenants/:id/usage endpoint. ?period=
+// (YYYY-MM) selects a past month; the default is the current one.
+func handleGetTenantUsage(tracker *quota.Tracker, logger *zap.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		period := ctx.Query("period")
+		if period != "" {
+			if _, err := time.Parse("2006-01", period); err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid period: expected YYYY-MM"})
+				return
+			}
+		}
+
+		usage, err := tracker.Usage(ctx.Request.Context(), ctx.Param("id"), period)
+		if err != nil {
+			logger.Error("failed to read tenant usage", zap.Error(err), zap.String("tenant", ctx.Param("id")))
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read tenant usage"})
+			return
+		}
+		ctx.JSON(http.StatusOK, usage)
+	}
+}
+
+// handleGetTenantStatuses handles the GET /admin/tenants/:id/statuses endpoint.
+func handleGetTenantStatuses(svc *sales.Service, logger *zap.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		statuses, err := svc.TenantStatuses(ctx.Param("id"))
+		if err != nil {
+			logger.Error("failed to read tenant statuses", zap.Error(err), zap.String("tenant", ctx.Param("id")))
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read tenant statuses"})
+			return
+		}
+		if statuses == nil {
+			statuses = []sales.CustomStatus{}
+		}
+		ctx.JSON(http.StatusOK, gin.H{"statuses": statuses})
+	}
+}
+
+// handleSetTenantStatuses handles the PUT /admin/tenants/:id/statuses
+// endpoint, replacing the tenant's custom statuses.
+func handleSetTenantStatuses(svc *sales.Service, logger *zap.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req struct {
+			Statuses []sales.CustomStatus `json:"statuses"`
+		}
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request payload"})
+			return
+		}
+
+		tenant := ctx.Param("id")
+		if err := svc.SetTenantStatuses(tenant, req.Statuses); err != nil {
+			if errors.Is(err, sales.ErrInvalidVocabulary) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			logger.Error("failed to save tenant statuses", zap.Error(err), zap.String("tenant", tenant))
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save tenant statuses"})
+			return
+		}
+		logger.Info("tenant statuses updated", zap.String("tenant", tenant), zap.Int("statuses", len(req.Statuses)))
+		ctx.JSON(http.StatusOK, gin.H{"statuses": req.Statuses})
+	}
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// timeFormats describes, for the 400 errors, what time parameters accept.
+const timeFormats = "RFC3339 (2006-01-02T15:04:05Z), a date (2006-01-02), a relative duration (-24h, -7d) or now, today, yesterday"
+
+// timeBound says whether a time parameter opens or closes an inclusive range:
+// a date or day keyword in an upper bound covers until the end of that day.
+type timeBound int
+
+const (
+	lowerBound timeBound = iota
+	upperBound
+)
+
+// tzQuery parses the optional tz parameter, an IANA timezone name such as
+// America/Santiago. Without it reports use UTC.
+func tzQuery(ctx *gin.Context) (*time.Location, error) {
+	raw := ctx.Query("tz")
+	if raw == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tz: expected an IANA timezone name")
+	}
+	return loc, nil
+}
+
+// timeQuery parses an optional time parameter. Dates and day keywords are
+// days of the tz parameter's timezone.
+func timeQuery(ctx *gin.Context, param string, bound timeBound) (*time.Time, error) {
+	raw := ctx.Query(param)
+	if raw == "" {
+		return nil, nil
+	}
+	loc, err := tzQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t, err := parseTime(raw, time.Now(), loc, bound)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: expected %s", param, timeFormats)
+	}
+	return &t, nil
+}
+
+// parseTime interpreta raw respecto de now; el resultado queda en UTC.
+func parseTime(raw string, now time.Time, loc *time.Location, bound timeBound) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t.UTC(), nil
+	}
+
+	day := func(t time.Time) time.Time {
+		start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		if bound == upperBound {
+			// Rango inclusivo: hasta el último instante del día
+			return start.AddDate(0, 0, 1).Add(-time.Nanosecond).UTC()
+		}
+		return start.UTC()
+	}
+	local := now.In(loc)
+	switch strings.ToLower(raw) {
+	case "now":
+		return now.UTC(), nil
+	case "today":
+		return day(local), nil
+	case "yesterday":
+		return day(local.AddDate(0, 0, -1)), nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, raw, loc); err == nil {
+		return day(t), nil
+	}
+
+	if d, err := parseRelative(raw); err == nil {
+		return now.Add(d).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time %q", raw)
+}
+
+// parseRelative acepta duraciones con signo de Go (-24h, +90m) y días (-7d).
+func parseRelative(raw string) (time.Duration, error) {
+	if raw == "" || (raw[0] != '-' && raw[0] != '+') {
+		return 0, fmt.Errorf("relative times need a sign")
+	}
+	if days, ok := strings.CutSuffix(raw, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(raw)
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"api_sales/internal/sales"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// handleGetVerifications handles the GET /admin/verifications endpoint,
+// listing the provisional sales waiting for their user to be validated.
+// ?stuck=true lists only those that ran out of attempts.
+func (h *salesHandler) handleGetVerifications(ctx *gin.Context) {
+	items, err := h.salesService.PendingVerifications(ctx.Query("stuck") == "true")
+	if err != nil {
+		h.logger.Error("failed to get verifications", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get verifications"})
+		return
+	}
+	ctx.Header("Cache-Control", "private, no-store")
+	ctx.JSON(http.StatusOK, gin.H{"results": items})
+}
+
+// handleRetryVerification handles the POST /admin/verifications/:id/retry
+// endpoint, scheduling a provisional sale for the next worker run.
+func (h *salesHandler) handleRetryVerification(ctx *gin.Context) {
+	saleID := ctx.Param("id")
+	verification, err := h.salesService.RetryVerification(saleID)
+	if err != nil {
+		switch err {
+		case sales.ErrNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "sale not found"})
+		case sales.ErrVerificationNotFound:
+			h.conflict(ctx, saleID, err.Error())
+		default:
+			h.logger.Error("failed to retry verification", zap.Error(err), zap.String("sale_id", saleID))
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retry verification"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusAccepted, verification)
+}
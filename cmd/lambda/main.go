@@ -0,0 +1,26 @@
+// Command lambda runs the sales API on AWS Lambda behind an API Gateway proxy
+// integration. Configure it with the same environment as the server, usually
+// with SALES_STORAGE_BACKEND=dynamodb so sales survive cold starts.
+package main
+
+import (
+	"api_sales/internal/config"
+	"api_sales/internal/lambdaproxy"
+	"api_sales/salesapi"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/gin-gonic/gin"
+)
+
+func main() {
+	gin.SetMode(gin.ReleaseMode)
+	srv, err := salesapi.New(salesapi.WithConfig(config.Load()))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error initializing routes: %v\n", err)
+		os.Exit(1)
+	}
+
+	lambda.Start(lambdaproxy.Handler(srv.Handler()))
+}
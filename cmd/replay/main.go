@@ -0,0 +1,108 @@
+// Command replay sends recorded traffic to a staging instance of the sales
+// API, to validate a storage backend under realistic load before migrating:
+//
+//	replay -target https://sales.staging.internal -api-key $KEY \
+//		-changes audit.ndjson -access-log access.log -speed 2
+//
+// -changes takes the export of GET /admin/audit/export and replays the writes
+// behind it; -access-log takes the JSON access log and replays its reads. The
+// recorded pace is kept, scaled by -speed; -speed 0 replays as fast as
+// -concurrency allows. A JSON report with the status codes and latencies of
+// the target is printed at the end.
+package main
+
+import (
+	"api_sales/internal/replay"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func main() {
+	target := flag.String("target", "", "base URL of the instance to replay against")
+	accessLog := flag.String("access-log", "", "JSON access log to replay the reads of")
+	changes := flag.String("changes", "", "audit export (NDJSON) to replay the writes of")
+	speed := flag.Float64("speed", 1, "pace relative to the recording; 0 replays without waiting")
+	concurrency := flag.Int("concurrency", 8, "requests in flight at most")
+	timeout := flag.Duration("timeout", 10*time.Second, "timeout of every request")
+	apiKey := flag.String("api-key", "", "API key sent as X-API-Key")
+	token := flag.String("token", "", "bearer token sent as Authorization")
+	saleIDs := flag.String("sale-ids", "", "comma-separated sales of the target for the reads of /sales/:id routes")
+	flag.Parse()
+
+	if *target == "" || (*accessLog == "" && *changes == "") {
+		fmt.Fprintln(os.Stderr, "usage: replay -target URL [-changes FILE] [-access-log FILE] [flags]")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	if *speed < 0 {
+		fmt.Fprintln(os.Stderr, "-speed must not be negative")
+		os.Exit(2)
+	}
+
+	var requests []replay.Request
+	skipped := replay.Skipped{}
+	for _, source := range []struct {
+		path string
+		read func(io.Reader) ([]replay.Request, replay.Skipped, error)
+	}{
+		{*changes, replay.ReadChangeFeed},
+		{*accessLog, replay.ReadAccessLog},
+	} {
+		if source.path == "" {
+			continue
+		}
+		f, err := os.Open(source.path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error opening %s: %v\n", source.path, err)
+			os.Exit(1)
+		}
+		read, skips, err := source.read(f)
+		f.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error reading %s: %v\n", source.path, err)
+			os.Exit(1)
+		}
+		requests = append(requests, read...)
+		for reason, n := range skips {
+			skipped[reason] += n
+		}
+	}
+	// Con ambas fuentes las escrituras van antes que las lecturas del mismo instante
+	sort.SliceStable(requests, func(i, j int) bool { return requests[i].At.Before(requests[j].At) })
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error creating logger: %v\n", err)
+		os.Exit(1)
+	}
+	defer logger.Sync()
+
+	replayer := replay.NewReplayer(*target, *speed, *concurrency, *timeout, logger)
+	if *apiKey != "" {
+		replayer.SetHeader("X-API-Key", *apiKey)
+	}
+	if *token != "" {
+		replayer.SetHeader("Authorization", "Bearer "+*token)
+	}
+	if *saleIDs != "" {
+		replayer.AddSales(strings.Split(*saleIDs, ",")...)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	logger.Info("replaying requests", zap.Int("requests", len(requests)), zap.String("target", *target))
+	report := replayer.Run(ctx, requests)
+	for reason, n := range skipped {
+		report.Skipped[reason] += n
+	}
+	report.Write(os.Stdout)
+}
@@ -0,0 +1,210 @@
+// Package analytics sends anonymized product-usage events to an analytics
+// sink, so the team can see which API features are used. Events carry the
+// route template, never the path, a latency bucket instead of the exact
+// latency and a pseudonym of the tenant; no IDs, IPs or bodies leave the
+// service.
+package analytics
+
+import (
+	"api_sales/internal/dispatch"
+	"api_sales/internal/jsonenc"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"resty.dev/v3"
+)
+
+// Event is one API request as reported to the sink.
+type Event struct {
+	Method        string    `json:"method"`
+	Route         string    `json:"route"`
+	Status        int       `json:"status"`
+	LatencyBucket string    `json:"latency_bucket"`
+	Tenant        string    `json:"tenant,omitempty"`
+	Features      []string  `json:"features,omitempty"`
+	At            time.Time `json:"at"`
+}
+
+// Sink receives batches of events.
+type Sink interface {
+	Send(ctx context.Context, events []Event) error
+}
+
+// HTTPSink posts every batch as {"events": [...]} to an HTTP collector.
+type HTTPSink struct {
+	url    string
+	client *resty.Client
+}
+
+// NewHTTPSink creates a sink for the collector at url. A non-empty apiKey is
+// sent as a bearer token.
+func NewHTTPSink(url, apiKey string, timeout time.Duration) *HTTPSink {
+	client := resty.New().
+		SetTimeout(timeout).
+		SetRetryCount(2).
+		SetRetryWaitTime(time.Second).
+		SetAllowNonIdempotentRetry(true)
+	if apiKey != "" {
+		client.SetAuthToken(apiKey)
+	}
+	return &HTTPSink{url: url, client: client}
+}
+
+// SetTransport sends batches through rt, e.g. to apply proxy and TLS settings.
+func (s *HTTPSink) SetTransport(rt http.RoundTripper) {
+	s.client.SetTransport(rt)
+}
+
+func (s *HTTPSink) Send(ctx context.Context, events []Event) error {
+	body, err := jsonenc.Marshal(map[string][]Event{"events": events})
+	if err != nil {
+		return fmt.Errorf("failed to encode analytics events: %w", err)
+	}
+	resp, err := s.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(body).
+		Post(s.url)
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return fmt.Errorf("analytics sink returned status %d", resp.StatusCode())
+	}
+	return nil
+}
+
+// latencyBuckets son los límites superiores de cada bucket, de menor a mayor.
+var latencyBuckets = []struct {
+	limit time.Duration
+	label string
+}{
+	{10 * time.Millisecond, "<10ms"},
+	{50 * time.Millisecond, "10-50ms"},
+	{100 * time.Millisecond, "50-100ms"},
+	{250 * time.Millisecond, "100-250ms"},
+	{500 * time.Millisecond, "250-500ms"},
+	{time.Second, "500ms-1s"},
+	{5 * time.Second, "1-5s"},
+}
+
+// LatencyBucket returns the bucket reported for latency.
+func LatencyBucket(latency time.Duration) string {
+	for _, b := range latencyBuckets {
+		if latency < b.limit {
+			return b.label
+		}
+	}
+	return ">=5s"
+}
+
+// Recorder batches events and sends them to the sink on a dispatch pool.
+type Recorder struct {
+	sink      Sink
+	key       []byte
+	features  []string
+	batchSize int
+	pool      *dispatch.Pool
+	logger    *zap.Logger
+
+	mu      sync.Mutex
+	pending []Event
+}
+
+// NewRecorder sends batches of up to batchSize events to sink through pool.
+// Tenants are replaced by an HMAC with tenantKey, so the sink can group the
+// events of a tenant without learning who it is. features, e.g. the build's
+// feature flags, are attached to every event.
+func NewRecorder(sink Sink, tenantKey string, features []string, batchSize int, pool *dispatch.Pool, logger *zap.Logger) *Recorder {
+	return &Recorder{
+		sink:      sink,
+		key:       []byte(tenantKey),
+		features:  features,
+		batchSize: batchSize,
+		pool:      pool,
+		logger:    logger,
+	}
+}
+
+// Record queues the event of a request to route; the batch is sent once it
+// reaches the batch size. Requests without a tenant are reported without one.
+func (r *Recorder) Record(method, route string, status int, latency time.Duration, tenant string) {
+	event := Event{
+		Method:        method,
+		Route:         route,
+		Status:        status,
+		LatencyBucket: LatencyBucket(latency),
+		Features:      r.features,
+		// Al minuto, para que la hora no identifique un request puntual
+		At: time.Now().UTC().Truncate(time.Minute),
+	}
+	if tenant != "" {
+		event.Tenant = r.pseudonym(tenant)
+	}
+
+	r.mu.Lock()
+	r.pending = append(r.pending, event)
+	var batch []Event
+	if len(r.pending) >= r.batchSize {
+		batch, r.pending = r.pending, nil
+	}
+	r.mu.Unlock()
+
+	if batch != nil {
+		r.send(batch)
+	}
+}
+
+// Flush sends the queued events, if any.
+func (r *Recorder) Flush() {
+	r.mu.Lock()
+	batch := r.pending
+	r.pending = nil
+	r.mu.Unlock()
+
+	if len(batch) > 0 {
+		r.send(batch)
+	}
+}
+
+// Start flushes the queued events every interval until ctx is done, so quiet
+// periods are reported too.
+func (r *Recorder) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.Flush()
+		}
+	}
+}
+
+// send encola el envío del lote; si el pool está lleno se descarta, la
+// telemetría no debe frenar los requests.
+func (r *Recorder) send(batch []Event) {
+	err := r.pool.Submit(func(ctx context.Context) {
+		if err := r.sink.Send(ctx, batch); err != nil {
+			r.logger.Warn("failed to send analytics events", zap.Int("events", len(batch)), zap.Error(err))
+		}
+	})
+	if err != nil {
+		r.logger.Warn("analytics events dropped", zap.Int("events", len(batch)), zap.Error(err))
+	}
+}
+
+func (r *Recorder) pseudonym(tenant string) string {
+	m := hmac.New(sha256.New, r.key)
+	m.Write([]byte(tenant))
+	return hex.EncodeToString(m.Sum(nil))[:16]
+}
@@ -0,0 +1,69 @@
+package analytics
+
+import (
+	"api_sales/internal/dispatch"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func TestRecorder_SendsAnonymizedBatches(t *testing.T) {
+	batches := make(chan []Event, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer sink-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body struct {
+			Events []Event `json:"events"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		batches <- body.Events
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	pool := dispatch.NewPool("analytics_test", 1, 10, zaptest.NewLogger(t))
+	defer pool.Close(context.Background())
+	recorder := NewRecorder(NewHTTPSink(server.URL, "sink-key", time.Second), "tenant-key", []string{"erp"}, 2, pool, zaptest.NewLogger(t))
+
+	recorder.Record(http.MethodGet, "/sales/:id", http.StatusOK, 30*time.Millisecond, "acme")
+	recorder.Record(http.MethodPost, "/sales", http.StatusCreated, 2*time.Second, "acme")
+	recorder.Record(http.MethodGet, "/health", http.StatusOK, time.Millisecond, "")
+
+	var batch []Event
+	select {
+	case batch = <-batches:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a full batch to be sent")
+	}
+	if len(batch) != 2 {
+		t.Fatalf("expected 2 events, got %+v", batch)
+	}
+	first, second := batch[0], batch[1]
+	if first.Route != "/sales/:id" || first.LatencyBucket != "10-50ms" || second.LatencyBucket != "1-5s" {
+		t.Errorf("unexpected events %+v", batch)
+	}
+	if first.Tenant == "" || first.Tenant == "acme" || first.Tenant != second.Tenant {
+		t.Errorf("expected a stable pseudonym of the tenant, got %q and %q", first.Tenant, second.Tenant)
+	}
+	if len(first.Features) != 1 || first.Features[0] != "erp" {
+		t.Errorf("expected the features attached, got %v", first.Features)
+	}
+
+	// El resto sale en el próximo Flush
+	recorder.Flush()
+	select {
+	case batch = <-batches:
+		if len(batch) != 1 || batch[0].Tenant != "" {
+			t.Errorf("expected the event without a tenant, got %+v", batch)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the flushed batch to be sent")
+	}
+}
@@ -0,0 +1,22 @@
+// Package blob stores opaque artifacts, such as report snapshots, by key.
+// Blobs are write-once: a key can't be overwritten, so what was stored is what
+// is read back later.
+package blob
+
+import (
+	"context"
+	"errors"
+)
+
+// Error para claves inexistentes
+var ErrNotFound = errors.New("blob not found")
+
+// Error para escrituras sobre una clave ya guardada
+var ErrExists = errors.New("blob already exists")
+
+// Store persists blobs.
+type Store interface {
+	// Put stores data under key, failing with ErrExists if key is taken.
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+}
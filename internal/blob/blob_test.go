@@ -0,0 +1,36 @@
+package blob
+
+import (
+	"context"
+	"testing"
+)
+
+// TestStores_AreWriteOnce verifica que ningún store permita sobrescribir una
+// clave y que lo leído sea lo guardado.
+func TestStores_AreWriteOnce(t *testing.T) {
+	ctx := context.Background()
+	files, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore returned error: %v", err)
+	}
+
+	for name, store := range map[string]Store{"memory": NewMemoryStore(), "file": files} {
+		if _, err := store.Get(ctx, "snapshots/2024-05/manifest.json"); err != ErrNotFound {
+			t.Errorf("%s: expected ErrNotFound, got %v", name, err)
+		}
+		if err := store.Put(ctx, "snapshots/2024-05/manifest.json", []byte("v1")); err != nil {
+			t.Fatalf("%s: Put returned error: %v", name, err)
+		}
+		if err := store.Put(ctx, "snapshots/2024-05/manifest.json", []byte("v2")); err != ErrExists {
+			t.Errorf("%s: expected ErrExists, got %v", name, err)
+		}
+		data, err := store.Get(ctx, "snapshots/2024-05/manifest.json")
+		if err != nil || string(data) != "v1" {
+			t.Errorf("%s: expected the original blob, got %q, %v", name, data, err)
+		}
+	}
+
+	if err := files.Put(ctx, "../escape", []byte("x")); err == nil {
+		t.Error("expected keys outside the directory to be rejected")
+	}
+}
@@ -0,0 +1,40 @@
+package blob
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryStore keeps blobs in process memory. They are lost on restart.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	blobs map[string][]byte
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		blobs: map[string][]byte{},
+	}
+}
+
+func (m *MemoryStore) Put(ctx context.Context, key string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.blobs[key]; ok {
+		return ErrExists
+	}
+	m.blobs[key] = append([]byte(nil), data...)
+	return nil
+}
+
+func (m *MemoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	data, ok := m.blobs[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), data...), nil
+}
@@ -0,0 +1,55 @@
+// Package buildinfo exposes the build metadata injected at link time:
+//
+//	go build -ldflags "-X api_sales/internal/buildinfo.Version=1.4.0 \
+//		-X api_sales/internal/buildinfo.Commit=$(git rev-parse HEAD) \
+//		-X api_sales/internal/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ) \
+//		-X api_sales/internal/buildinfo.Features=field_encryption,erp" .
+package buildinfo
+
+import (
+	"api_sales/internal/jsonenc"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Valores inyectados con -ldflags "-X ..."
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+	// Features is a comma separated list of the feature flags enabled in the build.
+	Features = ""
+)
+
+// Info is the build metadata served on GET /version.
+type Info struct {
+	Version   string   `json:"version"`
+	Commit    string   `json:"commit"`
+	BuildTime string   `json:"build_time"`
+	GoVersion string   `json:"go_version"`
+	Features  []string `json:"features"`
+}
+
+// Get returns the build metadata. Features also lists the build tags that
+// change behavior, e.g. jsoniter.
+func Get() Info {
+	features := []string{}
+	for _, f := range strings.Split(Features, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			features = append(features, f)
+		}
+	}
+	if jsonenc.Name == "jsoniter" {
+		features = append(features, "jsoniter")
+	}
+	sort.Strings(features)
+
+	return Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+		Features:  features,
+	}
+}
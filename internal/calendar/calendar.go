@@ -0,0 +1,125 @@
+// Package calendar measures elapsed time in business hours: the working hours
+// of the working days of a timezone, skipping holidays.
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Calendar is a weekly business-hours schedule plus a list of holidays.
+type Calendar struct {
+	loc      *time.Location
+	days     map[time.Weekday]bool
+	open     clock
+	close    clock
+	holidays map[string]struct{}
+}
+
+// clock es una hora del día, en horas y minutos.
+type clock struct {
+	hour, minute int
+}
+
+func (c clock) on(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), c.hour, c.minute, 0, 0, day.Location())
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Parse builds a calendar from its textual configuration: hours as
+// "09:00-18:00", days as three-letter names ("mon", "tue", ...), an IANA
+// timezone and holidays as YYYY-MM-DD dates of that timezone.
+func Parse(hours string, days []string, tz string, holidays []string) (*Calendar, error) {
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", tz, err)
+	}
+
+	from, to, ok := strings.Cut(hours, "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid business hours %q: expected HH:MM-HH:MM", hours)
+	}
+	open, err := parseClock(from)
+	if err != nil {
+		return nil, err
+	}
+	closing, err := parseClock(to)
+	if err != nil {
+		return nil, err
+	}
+	if closing.hour*60+closing.minute <= open.hour*60+open.minute {
+		return nil, fmt.Errorf("invalid business hours %q: closing must be after opening", hours)
+	}
+
+	c := &Calendar{loc: loc, days: map[time.Weekday]bool{}, open: open, close: closing, holidays: map[string]struct{}{}}
+	for _, d := range days {
+		wd, ok := weekdays[strings.ToLower(strings.TrimSpace(d))]
+		if !ok {
+			return nil, fmt.Errorf("invalid business day %q: expected mon, tue, wed, thu, fri, sat or sun", d)
+		}
+		c.days[wd] = true
+	}
+	if len(c.days) == 0 {
+		return nil, fmt.Errorf("at least one business day is required")
+	}
+	for _, h := range holidays {
+		h = strings.TrimSpace(h)
+		if _, err := time.Parse(time.DateOnly, h); err != nil {
+			return nil, fmt.Errorf("invalid holiday %q: expected YYYY-MM-DD", h)
+		}
+		c.holidays[h] = struct{}{}
+	}
+	return c, nil
+}
+
+func parseClock(v string) (clock, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(v))
+	if err != nil {
+		return clock{}, fmt.Errorf("invalid time of day %q: expected HH:MM", v)
+	}
+	return clock{hour: t.Hour(), minute: t.Minute()}, nil
+}
+
+// IsBusinessDay reports whether the day of t, in the calendar's timezone, is
+// a working day that is not a holiday.
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	local := t.In(c.loc)
+	if !c.days[local.Weekday()] {
+		return false
+	}
+	_, holiday := c.holidays[local.Format(time.DateOnly)]
+	return !holiday
+}
+
+// Between returns the business time elapsed from from to to; zero when to is
+// not after from.
+func (c *Calendar) Between(from, to time.Time) time.Duration {
+	if !to.After(from) {
+		return 0
+	}
+
+	var total time.Duration
+	day := from.In(c.loc)
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, c.loc)
+	for !day.After(to) {
+		if c.IsBusinessDay(day) {
+			start, end := c.open.on(day), c.close.on(day)
+			if from.After(start) {
+				start = from
+			}
+			if to.Before(end) {
+				end = to
+			}
+			if end.After(start) {
+				total += end.Sub(start)
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return total
+}
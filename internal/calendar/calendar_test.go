@@ -0,0 +1,48 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+// TestBetween_CountsOnlyBusinessHours verifica que se descuenten noches,
+// fines de semana y feriados.
+func TestBetween_CountsOnlyBusinessHours(t *testing.T) {
+	cal, err := Parse("09:00-18:00", []string{"mon", "tue", "wed", "thu", "fri"}, "UTC", []string{"2024-05-01"})
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	// Viernes 17:00 a lunes 10:00: una hora el viernes y una el lunes
+	friday := time.Date(2024, 5, 3, 17, 0, 0, 0, time.UTC)
+	if got := cal.Between(friday, friday.Add(65*time.Hour)); got != 2*time.Hour {
+		t.Errorf("expected 2h across the weekend, got %v", got)
+	}
+
+	// El feriado del miércoles no suma
+	tuesday := time.Date(2024, 4, 30, 9, 0, 0, 0, time.UTC)
+	if got := cal.Between(tuesday, tuesday.AddDate(0, 0, 2)); got != 9*time.Hour {
+		t.Errorf("expected only Tuesday to count, got %v", got)
+	}
+	if cal.IsBusinessDay(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Error("expected the holiday not to be a business day")
+	}
+
+	if got := cal.Between(friday, friday.Add(-time.Hour)); got != 0 {
+		t.Errorf("expected zero for an inverted range, got %v", got)
+	}
+}
+
+func TestParse_RejectsInvalidConfiguration(t *testing.T) {
+	weekdays := []string{"mon"}
+	for name, build := range map[string]func() error{
+		"hours":    func() error { _, err := Parse("18:00-09:00", weekdays, "UTC", nil); return err },
+		"day":      func() error { _, err := Parse("09:00-18:00", []string{"monday"}, "UTC", nil); return err },
+		"timezone": func() error { _, err := Parse("09:00-18:00", weekdays, "Mars/Olympus", nil); return err },
+		"holiday":  func() error { _, err := Parse("09:00-18:00", weekdays, "UTC", []string{"01/05/2024"}); return err },
+	} {
+		if build() == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Package chaos injects latency, errors and partial failures for resilience
+// testing. It must never be enabled in production; the config refuses to.
+package chaos
+
+import (
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// ErrInjected is returned by injected failures.
+var ErrInjected = errors.New("chaos: injected failure")
+
+// Fault is what the injector decided for one call.
+type Fault int
+
+const (
+	FaultNone Fault = iota
+	// FaultError fails the call without running it.
+	FaultError
+	// FaultPartial runs the call but loses or truncates its result.
+	FaultPartial
+)
+
+// Options sets the fault rates, each a probability between 0 and 1.
+type Options struct {
+	LatencyRate float64
+	Latency     time.Duration
+	ErrorRate   float64
+	PartialRate float64
+	// Seed makes the injected faults reproducible.
+	Seed uint64
+}
+
+// Injector draws faults with the configured rates.
+type Injector struct {
+	opts  Options
+	sleep func(time.Duration)
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+func NewInjector(opts Options) *Injector {
+	return &Injector{
+		opts:  opts,
+		sleep: time.Sleep,
+		rnd:   rand.New(rand.NewPCG(opts.Seed, opts.Seed)),
+	}
+}
+
+// Inject sleeps for the configured latency when drawn and returns the fault to
+// apply to the call.
+func (i *Injector) Inject() Fault {
+	i.mu.Lock()
+	delay := i.rnd.Float64() < i.opts.LatencyRate
+	roll := i.rnd.Float64()
+	i.mu.Unlock()
+
+	if delay && i.opts.Latency > 0 {
+		i.sleep(i.opts.Latency)
+	}
+	switch {
+	case roll < i.opts.ErrorRate:
+		return FaultError
+	case roll < i.opts.ErrorRate+i.opts.PartialRate:
+		return FaultPartial
+	default:
+		return FaultNone
+	}
+}
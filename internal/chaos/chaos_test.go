@@ -0,0 +1,52 @@
+package chaos
+
+import (
+	"api_sales/internal/sales"
+	"testing"
+	"time"
+)
+
+// TestInjector_RatesAndSeed verifica que las tasas se respeten y que la misma
+// semilla reproduzca las mismas fallas.
+func TestInjector_RatesAndSeed(t *testing.T) {
+	draw := func() []Fault {
+		i := NewInjector(Options{ErrorRate: 0.2, PartialRate: 0.3, Seed: 42})
+		faults := make([]Fault, 1000)
+		for n := range faults {
+			faults[n] = i.Inject()
+		}
+		return faults
+	}
+
+	first, second := draw(), draw()
+	counts := map[Fault]int{}
+	for n := range first {
+		if first[n] != second[n] {
+			t.Fatalf("expected the same fault sequence for the same seed, differs at %d", n)
+		}
+		counts[first[n]]++
+	}
+	if counts[FaultError] < 150 || counts[FaultError] > 250 || counts[FaultPartial] < 250 || counts[FaultPartial] > 350 {
+		t.Errorf("fault counts far from the configured rates: %v", counts)
+	}
+}
+
+// TestStorage_PartialSetPersists verifica que una escritura parcial quede
+// guardada aunque reporte error.
+func TestStorage_PartialSetPersists(t *testing.T) {
+	injector := NewInjector(Options{PartialRate: 1, LatencyRate: 1, Latency: time.Second})
+	var slept time.Duration
+	injector.sleep = func(d time.Duration) { slept += d }
+
+	next := sales.NewLocalStorage()
+	storage := NewStorage(next, injector)
+	if err := storage.Set(&sales.Sale{ID: "s1"}); err != ErrInjected {
+		t.Fatalf("expected ErrInjected, got %v", err)
+	}
+	if _, err := next.Read("s1"); err != nil {
+		t.Errorf("expected the sale stored despite the error, got %v", err)
+	}
+	if slept != time.Second {
+		t.Errorf("expected injected latency of 1s, got %v", slept)
+	}
+}
@@ -0,0 +1,58 @@
+package chaos
+
+import (
+	"api_sales/internal/sales"
+	"context"
+)
+
+// Storage decorates a sales.Storage with injected faults. A partial Set
+// stores the sale but reports an error, and a partial GetAll drops half of the
+// results, the failure modes retries and reconciliation have to survive.
+type Storage struct {
+	sales.Storage
+	injector *Injector
+}
+
+func NewStorage(next sales.Storage, injector *Injector) *Storage {
+	return &Storage{Storage: next, injector: injector}
+}
+
+func (s *Storage) Set(sale *sales.Sale) error {
+	switch s.injector.Inject() {
+	case FaultError:
+		return ErrInjected
+	case FaultPartial:
+		if err := s.Storage.Set(sale); err != nil {
+			return err
+		}
+		return ErrInjected
+	}
+	return s.Storage.Set(sale)
+}
+
+func (s *Storage) Read(id string) (*sales.Sale, error) {
+	if s.injector.Inject() != FaultNone {
+		return nil, ErrInjected
+	}
+	return s.Storage.Read(id)
+}
+
+func (s *Storage) GetAll() ([]*sales.Sale, error) {
+	switch s.injector.Inject() {
+	case FaultError:
+		return nil, ErrInjected
+	case FaultPartial:
+		all, err := s.Storage.GetAll()
+		if err != nil {
+			return nil, err
+		}
+		return all[:len(all)/2], nil
+	}
+	return s.Storage.GetAll()
+}
+
+func (s *Storage) WithTx(ctx context.Context, fn func(tx sales.Storage) error) error {
+	return s.Storage.WithTx(ctx, func(tx sales.Storage) error {
+		return fn(NewStorage(tx, s.injector))
+	})
+}
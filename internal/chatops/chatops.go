@@ -0,0 +1,181 @@
+// Package chatops posts operational events to Slack or Microsoft Teams
+// incoming webhooks.
+package chatops
+
+import (
+	"api_sales/internal/dispatch"
+	"api_sales/internal/sales"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"text/template"
+	"time"
+
+	"go.uber.org/zap"
+	"resty.dev/v3"
+)
+
+// Operational events that can be enabled per deployment.
+const (
+	EventLargeSale      = "large_sale_created"
+	EventCircuitOpen    = "circuit_breaker_open"
+	EventDeadLetterGrew = "dlq_growth"
+	EventSLOBurn        = "slo_budget_burn"
+)
+
+// Supported chat providers.
+const (
+	ProviderSlack = "slack"
+	ProviderTeams = "teams"
+)
+
+// DefaultTemplates renders each event when no override is configured. The
+// template receives the data passed to Send.
+var DefaultTemplates = map[string]string{
+	EventLargeSale:      `Large sale created: {{.ID}} for {{printf "%.2f" .Amount}} by user {{.UserID}} ({{.Status}})`,
+	EventCircuitOpen:    `Circuit breaker open: {{.Name}}`,
+	EventDeadLetterGrew: `Dead-letter queue {{.Name}} grew to {{.Depth}} messages`,
+	EventSLOBurn:        `SLO {{.Name}} ({{.Severity}}): error budget burning at {{printf "%.1f" .BurnRate}}x over {{.Window}}, {{printf "%.0f" .BudgetPercent}}% left`,
+}
+
+// Options configures a Notifier.
+type Options struct {
+	Provider   string
+	WebhookURL string
+	// Events lists the enabled events; the rest are ignored by Send.
+	Events []string
+	// Templates overrides DefaultTemplates per event.
+	Templates map[string]string
+}
+
+// Notifier renders enabled events and posts them on a dispatch pool so callers
+// never wait on the chat service.
+type Notifier struct {
+	provider   string
+	webhookURL string
+	templates  map[string]*template.Template
+	pool       *dispatch.Pool
+	client     *resty.Client
+	logger     *zap.Logger
+}
+
+// NewNotifier creates a notifier for the enabled events in opts.
+func NewNotifier(opts Options, pool *dispatch.Pool, logger *zap.Logger) (*Notifier, error) {
+	if opts.Provider != ProviderSlack && opts.Provider != ProviderTeams {
+		return nil, fmt.Errorf("unsupported chat provider %q", opts.Provider)
+	}
+	if logger == nil {
+		logger, _ = zap.NewProduction()
+	}
+
+	templates := make(map[string]*template.Template, len(opts.Events))
+	for _, event := range opts.Events {
+		text, ok := opts.Templates[event]
+		if !ok {
+			if text, ok = DefaultTemplates[event]; !ok {
+				return nil, fmt.Errorf("unknown chat event %q", event)
+			}
+		}
+		tmpl, err := template.New(event).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template for %s: %w", event, err)
+		}
+		templates[event] = tmpl
+	}
+
+	client := resty.New().
+		SetTimeout(10 * time.Second).
+		SetRetryCount(2).
+		SetRetryWaitTime(500 * time.Millisecond).
+		SetAllowNonIdempotentRetry(true)
+
+	return &Notifier{
+		provider:   opts.Provider,
+		webhookURL: opts.WebhookURL,
+		templates:  templates,
+		pool:       pool,
+		client:     client,
+		logger:     logger,
+	}, nil
+}
+
+// SetTransport sends messages through rt, e.g. to apply proxy and TLS settings.
+func (n *Notifier) SetTransport(rt http.RoundTripper) {
+	n.client.SetTransport(rt)
+}
+
+// Enabled reports whether event is posted by Send.
+func (n *Notifier) Enabled(event string) bool {
+	_, ok := n.templates[event]
+	return ok
+}
+
+// Send renders and queues event. Disabled events are ignored, and messages
+// that don't fit in the queue are dropped and logged.
+func (n *Notifier) Send(event string, data any) {
+	tmpl, ok := n.templates[event]
+	if !ok {
+		return
+	}
+
+	var text bytes.Buffer
+	if err := tmpl.Execute(&text, data); err != nil {
+		n.logger.Error("failed to render chat message", zap.String("event", event), zap.Error(err))
+		return
+	}
+
+	message := text.String()
+	err := n.pool.Submit(func(ctx context.Context) {
+		if err := n.post(ctx, message); err != nil {
+			n.logger.Error("chat notification failed", zap.String("event", event), zap.Error(err))
+		}
+	})
+	if err != nil {
+		n.logger.Warn("chat notification dropped", zap.String("event", event), zap.Error(err))
+	}
+}
+
+func (n *Notifier) post(ctx context.Context, text string) error {
+	resp, err := n.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(payload(n.provider, text)).
+		Post(n.webhookURL)
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return fmt.Errorf("chat webhook returned status %d", resp.StatusCode())
+	}
+	return nil
+}
+
+// payload arma el cuerpo que espera cada proveedor.
+func payload(provider, text string) any {
+	if provider == ProviderTeams {
+		return map[string]string{
+			"@type":    "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"text":     text,
+		}
+	}
+	return map[string]string{"text": text}
+}
+
+// LargeSales returns a sales.Notifier that sends EventLargeSale for every sale
+// created with an amount at or above threshold.
+func (n *Notifier) LargeSales(threshold float64) sales.Notifier {
+	return largeSaleWatcher{notifier: n, threshold: threshold}
+}
+
+type largeSaleWatcher struct {
+	notifier  *Notifier
+	threshold float64
+}
+
+func (w largeSaleWatcher) Notify(eventType string, sale *sales.Sale) {
+	if eventType == sales.EventSaleCreated && sale.Amount >= w.threshold {
+		w.notifier.Send(EventLargeSale, sale)
+	}
+}
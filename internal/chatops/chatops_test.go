@@ -0,0 +1,57 @@
+package chatops
+
+import (
+	"api_sales/internal/dispatch"
+	"api_sales/internal/sales"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestNotifier_LargeSalesOnly verifica el umbral, los eventos habilitados y el payload de Teams.
+func TestNotifier_LargeSalesOnly(t *testing.T) {
+	var mu sync.Mutex
+	var messages []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		messages = append(messages, body)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	pool := dispatch.NewPool("test-chatops", 1, 10, nil)
+	n, err := NewNotifier(Options{
+		Provider:   ProviderTeams,
+		WebhookURL: server.URL,
+		Events:     []string{EventLargeSale},
+		Templates:  map[string]string{EventLargeSale: `big sale {{.ID}}`},
+	}, pool, nil)
+	if err != nil {
+		t.Fatalf("NewNotifier returned error: %v", err)
+	}
+
+	watcher := n.LargeSales(1000)
+	watcher.Notify(sales.EventSaleCreated, &sales.Sale{ID: "small", Amount: 10})
+	watcher.Notify(sales.EventSaleCreated, &sales.Sale{ID: "big", Amount: 5000})
+	watcher.Notify(sales.EventSaleUpdated, &sales.Sale{ID: "edited", Amount: 5000})
+	n.Send(EventCircuitOpen, map[string]string{"Name": "users"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := pool.Close(ctx); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	if len(messages) != 1 {
+		t.Fatalf("expected 1 chat message, got %d: %v", len(messages), messages)
+	}
+	if messages[0]["text"] != "big sale big" || messages[0]["@type"] != "MessageCard" {
+		t.Errorf("unexpected teams payload: %v", messages[0])
+	}
+}
@@ -0,0 +1,89 @@
+// Package clamav scans files with a clamd daemon over its INSTREAM protocol.
+package clamav
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// chunkSize es el tamaño de cada bloque enviado a clamd.
+const chunkSize = 64 * 1024
+
+// Client scans data with clamd.
+type Client struct {
+	network string
+	addr    string
+	timeout time.Duration
+}
+
+// New creates a client for clamd at addr, a host:port or the path of a unix
+// socket. Each scan fails if it takes longer than timeout.
+func New(addr string, timeout time.Duration) *Client {
+	network := "tcp"
+	if strings.HasPrefix(addr, "/") {
+		network = "unix"
+	}
+	return &Client{network: network, addr: addr, timeout: timeout}
+}
+
+// Scan sends data to clamd and returns the name of the signature it matched,
+// or "" if data is clean.
+func (c *Client) Scan(ctx context.Context, data []byte) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, c.network, c.addr)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return "", fmt.Errorf("failed to send to clamd: %w", err)
+	}
+	// Cada bloque va precedido de su largo; un largo cero cierra el stream
+	size := make([]byte, 4)
+	for len(data) > 0 {
+		n := min(len(data), chunkSize)
+		binary.BigEndian.PutUint32(size, uint32(n))
+		if _, err := conn.Write(size); err != nil {
+			return "", fmt.Errorf("failed to send to clamd: %w", err)
+		}
+		if _, err := conn.Write(data[:n]); err != nil {
+			return "", fmt.Errorf("failed to send to clamd: %w", err)
+		}
+		data = data[n:]
+	}
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err := conn.Write(size); err != nil {
+		return "", fmt.Errorf("failed to send to clamd: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil {
+		return "", fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+	return parseReply(strings.TrimSuffix(reply, "\x00"))
+}
+
+// parseReply interpreta "stream: OK", "stream: <firma> FOUND" y los errores.
+func parseReply(reply string) (string, error) {
+	result := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case result == "OK":
+		return "", nil
+	case strings.HasSuffix(result, " FOUND"):
+		return strings.TrimSuffix(result, " FOUND"), nil
+	default:
+		return "", fmt.Errorf("clamd: %s", result)
+	}
+}
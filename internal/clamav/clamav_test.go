@@ -0,0 +1,84 @@
+package clamav
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClamd responde como clamd: FOUND si el stream contiene "EICAR".
+func fakeClamd(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen returned error: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				cmd := make([]byte, len("zINSTREAM\x00"))
+				if _, err := io.ReadFull(conn, cmd); err != nil || string(cmd) != "zINSTREAM\x00" {
+					conn.Write([]byte("UNKNOWN COMMAND\x00"))
+					return
+				}
+				var data bytes.Buffer
+				size := make([]byte, 4)
+				for {
+					if _, err := io.ReadFull(conn, size); err != nil {
+						return
+					}
+					n := binary.BigEndian.Uint32(size)
+					if n == 0 {
+						break
+					}
+					if _, err := io.CopyN(&data, conn, int64(n)); err != nil {
+						return
+					}
+				}
+				if strings.Contains(data.String(), "EICAR") {
+					conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+					return
+				}
+				conn.Write([]byte("stream: OK\x00"))
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// TestScan_ReportsSignatures verifica archivos limpios, infectados y partidos
+// en varios bloques.
+func TestScan_ReportsSignatures(t *testing.T) {
+	client := New(fakeClamd(t), time.Second)
+
+	sig, err := client.Scan(context.Background(), []byte("invoice"))
+	if err != nil || sig != "" {
+		t.Errorf("expected a clean result, got %q, %v", sig, err)
+	}
+
+	large := append(bytes.Repeat([]byte("a"), chunkSize+10), []byte("EICAR")...)
+	sig, err = client.Scan(context.Background(), large)
+	if err != nil || sig != "Eicar-Test-Signature" {
+		t.Errorf("expected Eicar-Test-Signature, got %q, %v", sig, err)
+	}
+}
+
+// TestParseReply_Errors verifica que las respuestas de error de clamd se
+// devuelvan como error.
+func TestParseReply_Errors(t *testing.T) {
+	if _, err := parseReply("INSTREAM size limit exceeded. ERROR"); err == nil {
+		t.Errorf("expected an error")
+	}
+}
@@ -0,0 +1,818 @@
+// Package config loads the service configuration from environment variables.
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	LockBackendLocal    = "local"
+	LockBackendRedis    = "redis"
+	LockBackendPostgres = "postgres"
+
+	BackendMemory   = "memory"
+	BackendRedis    = "redis"
+	BackendDynamoDB = "dynamodb"
+	BackendMySQL    = "mysql"
+	BackendSQLite   = "sqlite"
+)
+
+// Config holds the settings used to wire the API at startup.
+type Config struct {
+	UserServiceURL    string
+	SchedulerInterval time.Duration
+
+	// Environment is the deployment environment, e.g. development, staging or
+	// production.
+	Environment string
+
+	// RandomSeed, when not zero, seeds every random decision of the service
+	// so runs are reproducible, e.g. in tests.
+	RandomSeed int
+
+	// ListenAddrs serve the public API; AdminListenAddrs, when set, are the
+	// only ones serving /admin and /metrics. Addresses are host:port or
+	// unix:/path/to.sock.
+	ListenAddrs      []string
+	AdminListenAddrs []string
+
+	// LockBackend selects the distributed lock: local, redis or postgres.
+	LockBackend string
+	RedisAddr   string
+	PostgresDSN string
+
+	// SalesStorageBackend selects where sales are stored: memory, dynamodb,
+	// mysql, sqlite or redis. DynamoDB uses the standard AWS environment (AWS_REGION,
+	// credentials). DynamoDBUserIndex names the table's global secondary index
+	// on user_id used to list a user's sales; empty scans the table instead.
+	SalesStorageBackend string
+	DynamoDBTable       string
+	DynamoDBUserIndex   string
+
+	// MySQL and MariaDB storage. MySQLDSN uses the go-sql-driver format, e.g.
+	// user:pass@tcp(db:3306)/shop. The pool keeps up to MySQLMaxOpenConns
+	// connections, MySQLMaxIdleConns of them idle, each reused for at most
+	// MySQLConnMaxLifetime; MySQLTimeout bounds every statement.
+	MySQLDSN             string
+	MySQLTable           string
+	MySQLMaxOpenConns    int
+	MySQLMaxIdleConns    int
+	MySQLConnMaxLifetime time.Duration
+	MySQLTimeout         time.Duration
+
+	// SQLitePath is the database file of the sqlite backend, created if it
+	// doesn't exist; SQLiteTimeout bounds every statement. Meant for a single
+	// instance: the file can't be shared between replicas.
+	SQLitePath    string
+	SQLiteTimeout time.Duration
+
+	// MigrateOnStart applies the pending schema migrations of the mysql and
+	// sqlite backends at startup. When false the API refuses to start with
+	// pending migrations, to be applied first with the -migrate flag.
+	MigrateOnStart bool
+
+	// Redis storage, on REDIS_ADDR. Each write keeps a sale for RedisSalesTTL
+	// more, or until deleted when zero; RedisSalesTimeout bounds every
+	// command. Persistence depends on the server's RDB or AOF settings.
+	RedisSalesTTL     time.Duration
+	RedisSalesTimeout time.Duration
+
+	// StorageRetryAttempts and StorageRetryBackoff set, per storage backend,
+	// how often transient errors (throttling, connection resets, deadlocks)
+	// are retried and the first backoff, doubled per attempt up to
+	// StorageRetryMaxBackoff, e.g. STORAGE_RETRY_ATTEMPTS="dynamodb=3".
+	StorageRetryAttempts   map[string]int
+	StorageRetryBackoff    map[string]time.Duration
+	StorageRetryMaxBackoff time.Duration
+
+	// ShadowStorageBackend, when set, dual-writes sales to this candidate
+	// backend and compares reads against it in the background, logging
+	// mismatches, to validate a migration under live traffic.
+	ShadowStorageBackend string
+
+	// ReplicationBackend, when set, replicates every sale write asynchronously
+	// to a secondary storage for disaster recovery; dynamodb replicas use
+	// ReplicationRegion and ReplicationDynamoDBTable (default DynamoDBTable).
+	// POST /admin/replication/promote switches to the replica, waiting up to
+	// ReplicationPromoteTimeout for pending changes.
+	ReplicationBackend        string
+	ReplicationRegion         string
+	ReplicationDynamoDBTable  string
+	ReplicationPromoteTimeout time.Duration
+
+	// IdempotencyBackend and UserCacheBackend select memory or redis stores.
+	IdempotencyBackend string
+	IdempotencyTTL     time.Duration
+	UserCacheBackend   string
+	UserCacheTTL       time.Duration
+
+	// RecurringStorageBackend selects where recurring sale definitions are
+	// kept: memory or redis. Use redis when running more than one replica, so
+	// every scheduler sees the same definitions.
+	RecurringStorageBackend string
+
+	// Load shedding answers 503 to reports and exports while, over the storage
+	// calls of the last ShedWindow (at least ShedMinSamples), the p95 latency
+	// exceeds ShedMaxP95Latency or the error rate exceeds ShedMaxErrorRate.
+	// Enabled when either limit is set.
+	ShedMaxP95Latency time.Duration
+	ShedMaxErrorRate  float64
+	ShedMinSamples    int
+	ShedWindow        time.Duration
+	ShedRetryAfter    time.Duration
+
+	// Priority lanes cap the requests processed at once per class: batch
+	// (exports and requests sent with X-Request-Class: batch) and interactive.
+	// A zero limit leaves the lane unlimited; requests wait up to
+	// LaneQueueTimeout for a slot.
+	LaneInteractiveConcurrency int
+	LaneBatchConcurrency       int
+	LaneQueueTimeout           time.Duration
+
+	// ConcurrencyLimits caps the in-flight requests per route prefix, with "*"
+	// for the routes no prefix matches, e.g. CONCURRENCY_LIMITS="/sales=64,*=32".
+	// Up to ConcurrencyQueueSize more requests per group wait at most
+	// ConcurrencyQueueTimeout for a slot; the rest get 503 right away.
+	ConcurrencyLimits       map[string]int
+	ConcurrencyQueueSize    int
+	ConcurrencyQueueTimeout time.Duration
+
+	// WarmUp preloads caches from storage before serving traffic.
+	WarmUpEnabled     bool
+	WarmUpMaxUsers    int
+	WarmUpConcurrency int
+	WarmUpTimeout     time.Duration
+
+	// StatsCacheTTL bounds how long the aggregate stats stay cached; writes
+	// on other instances sharing the storage show up within it. Zero keeps
+	// them until the next write on this instance.
+	StatsCacheTTL time.Duration
+
+	// UserSyncAt (HH:MM in UserSyncTimezone) enables a nightly refresh of every
+	// user with a sale into the user cache, kept for UserSyncCacheTTL.
+	UserSyncAt          string
+	UserSyncTimezone    string
+	UserSyncConcurrency int
+	UserSyncCacheTTL    time.Duration
+
+	// WebhookURLs receive sale events; deliveries run on a bounded pool.
+	WebhookURLs      []string
+	WebhookWorkers   int
+	WebhookQueueSize int
+
+	// ERPURL enables posting approved sales to an ERP, rendering the body
+	// from the mapping template at ERPTemplateFile.
+	ERPURL          string
+	ERPTemplateFile string
+	ERPWorkers      int
+	ERPQueueSize    int
+
+	// ChatWebhookURL posts the ChatEvents to a Slack or Teams incoming webhook.
+	// Templates override the default message per event and are read from
+	// CHAT_TEMPLATE_<EVENT>, e.g. CHAT_TEMPLATE_LARGE_SALE_CREATED.
+	ChatProvider       string
+	ChatWebhookURL     string
+	ChatEvents         []string
+	ChatTemplates      map[string]string
+	LargeSaleThreshold float64
+
+	// SMS alerts buyers of approved sales of at least SMSHighValueThreshold
+	// through a Twilio-compatible API; enabled when SMSAccountSID is set.
+	SMSBaseURL            string
+	SMSAccountSID         string
+	SMSAuthToken          string
+	SMSFrom               string
+	SMSHighValueThreshold float64
+
+	// Egress settings applied to every outbound HTTP client. HTTP_PROXY,
+	// HTTPS_PROXY and NO_PROXY are honored unless OutboundProxyURL is set.
+	OutboundProxyURL  string
+	TLSRootCAFiles    []string
+	TLSClientCertFile string
+	TLSClientKeyFile  string
+
+	// KeyRotationWindow is how long a replaced webhook signing key or retired
+	// API key is still accepted. AdminAPIKey and WebhookSigningSecret seed the
+	// first keys at startup.
+	KeyRotationWindow    time.Duration
+	AdminAPIKey          string
+	WebhookSigningSecret string
+
+	// RedactFields are masked in logs and webhook payloads.
+	RedactFields []string
+
+	// SensitiveMetadataKeys are masked for non-admin callers and, when
+	// FieldEncryptionKey (base64, 32 bytes) is set, encrypted at rest.
+	SensitiveMetadataKeys []string
+	FieldEncryptionKey    string
+
+	// SnapshotDir keeps the snapshots stored when an accounting period is
+	// closed; empty keeps them in memory.
+	SnapshotDir string
+
+	// AttachmentDir enables sale attachments, stored under it, of up to
+	// AttachmentMaxBytes. ClamAVAddr (host:port or unix socket path) scans each
+	// one before it is stored, quarantining the files that fail.
+	AttachmentDir      string
+	AttachmentMaxBytes int
+	ClamAVAddr         string
+	ClamAVTimeout      time.Duration
+
+	// OCRURL enables POST /sales/from-receipt, reading receipts of up to
+	// ReceiptMaxBytes with an HTTP OCR service that answers {"text": "..."}.
+	OCRURL          string
+	OCRAPIKey       string
+	OCRTimeout      time.Duration
+	ReceiptMaxBytes int
+
+	// ResponseCacheTTLs enables response caching per GET route, e.g.
+	// RESPONSE_CACHE_TTLS="/sales=2s,/sales/stats=10s".
+	ResponseCacheTTLs map[string]time.Duration
+
+	// HTTPCacheMaxAge is the max-age sent in Cache-Control on GET results;
+	// zero sends no-cache so clients always revalidate with ETag.
+	HTTPCacheMaxAge time.Duration
+
+	// PatchDedupWindow is how long after a status change repeating it with
+	// PATCH /sales/:id answers 200 with the current sale instead of 409;
+	// zero accepts repeats at any time. ?strict=true always answers 409.
+	PatchDedupWindow time.Duration
+
+	// TenantQuotas is a JSON object of per-tenant limits with "*" as default,
+	// e.g. TENANT_QUOTAS={"*":{"requests_per_second":50,"burst":100,"monthly_sales":10000}}.
+	// Usage is counted in TenantUsageBackend (memory or redis).
+	TenantQuotas       string
+	TenantUsageBackend string
+
+	// TenantStatuses seeds the custom terminal statuses of each tenant, e.g.
+	// TENANT_STATUSES={"acme":[{"name":"written_off","from":["approved"]}]}.
+	// Admins can replace them at runtime.
+	TenantStatuses string
+
+	// ChaosEnabled injects faults into ChaosTargets ("http" and/or "storage")
+	// for resilience testing; it is rejected when Environment is production.
+	ChaosEnabled     bool
+	ChaosTargets     []string
+	ChaosLatency     time.Duration
+	ChaosLatencyRate float64
+	ChaosErrorRate   float64
+	ChaosPartialRate float64
+	ChaosSeed        int
+
+	// OpenAPIValidation rejects requests to the operations in api/openapi.yaml
+	// that don't match the spec. OpenAPIValidateResponses also checks the
+	// responses and answers 500 on drift; it is meant for development and
+	// rejected when Environment is production.
+	OpenAPIValidation        bool
+	OpenAPIValidateResponses bool
+
+	// AccessLogOutput receives the JSON access log, separate from the app logs
+	// (a zap output path such as stdout or a file); "off" disables it.
+	// AccessLogSampleRates sample 2xx lines per route once a route exceeds
+	// AccessLogSampleAfter requests per second, e.g.
+	// ACCESS_LOG_SAMPLE_RATES="/sales=0.1,*=0.5".
+	AccessLogOutput        string
+	AccessLogSlowThreshold time.Duration
+	AccessLogSampleAfter   int
+	AccessLogSampleRates   map[string]float64
+
+	// AnalyticsURL receives anonymized usage events (route, latency bucket,
+	// tenant pseudonym and build feature flags) in batches of
+	// AnalyticsBatchSize, flushed at least every AnalyticsFlushInterval; empty
+	// disables them. Tenants are sent as an HMAC with AnalyticsTenantKey, so
+	// the sink can group them without learning who they are.
+	AnalyticsURL           string
+	AnalyticsAPIKey        string
+	AnalyticsTenantKey     string
+	AnalyticsBatchSize     int
+	AnalyticsFlushInterval time.Duration
+
+	// SLOs is a JSON array of per-route objectives, e.g.
+	// SLOS=[{"name":"create_sale","method":"POST","route":"/sales","target":0.99,"latency_ms":300}].
+	// Error budgets are counted over SLOWindow and the burn-rate alerts are
+	// evaluated every SLOCheckInterval; GET /admin/slo reports them.
+	SLOs             string
+	SLOWindow        time.Duration
+	SLOCheckInterval time.Duration
+
+	// SlowQueryThreshold logs searches slower than this; zero disables it.
+	SlowQueryThreshold time.Duration
+	// SearchCanaryPercent of the searches, 0 to 100, are filtered by the
+	// storage instead of the service and compared in the background against
+	// the service filter, logging divergences.
+	SearchCanaryPercent float64
+
+	// MaintenanceMode (off, read_only or full) applies until an admin switches
+	// it; the switch is persisted in MaintenanceBackend (memory or redis) and
+	// re-read by every instance each MaintenanceRefresh.
+	MaintenanceMode       string
+	MaintenanceBackend    string
+	MaintenanceRetryAfter time.Duration
+	MaintenanceRefresh    time.Duration
+
+	// SelfCheckEnabled runs the startup diagnostics and exits non-zero when
+	// they fail; each check gets SelfCheckTimeout.
+	SelfCheckEnabled bool
+	SelfCheckTimeout time.Duration
+
+	// JWTSecret verifies the HS256 bearer tokens of customers, whose subject
+	// is their user ID (GET /me/sales). JWTIssuer and JWTAudience, when set,
+	// must match the token claims.
+	JWTSecret   string
+	JWTIssuer   string
+	JWTAudience string
+
+	// ShippingWebhookSecret enables POST /webhooks/shipping, whose carrier
+	// events must be signed with it within ShippingWebhookTolerance.
+	ShippingWebhookSecret    string
+	ShippingWebhookTolerance time.Duration
+
+	// PublicLookupRate and PublicLookupBurst limit, per client IP, the
+	// unauthenticated GET /public/sales/:reference/status; a zero rate
+	// disables the limit.
+	PublicLookupRate  float64
+	PublicLookupBurst int
+
+	// ShareLinkSecret enables the shareable sale links (POST /sales/:id/share)
+	// and signs their tokens. Links last ShareLinkTTL unless the request asks
+	// for another duration, up to ShareLinkMaxTTL. ShareLinkBaseURL prefixes
+	// the returned URLs; empty uses the host of the request.
+	ShareLinkSecret  string
+	ShareLinkTTL     time.Duration
+	ShareLinkMaxTTL  time.Duration
+	ShareLinkBaseURL string
+
+	// PendingSLA, when set, is how long a sale may stay pending, counted in the
+	// business hours of BusinessHours (HH:MM-HH:MM) on BusinessDays in
+	// BusinessTimezone, skipping Holidays (YYYY-MM-DD). Searches report the
+	// pending age of each sale and breaches are alerted every SLACheckInterval.
+	PendingSLA       time.Duration
+	BusinessHours    string
+	BusinessDays     []string
+	BusinessTimezone string
+	Holidays         []string
+	SLACheckInterval time.Duration
+
+	// ProvisionalSales stores new sales as pending_verification when the user
+	// service is unavailable. A worker re-validates them every
+	// VerificationInterval, backing off from VerificationBackoff up to
+	// VerificationMaxBackoff, until VerificationMaxAttempts marks them stuck.
+	ProvisionalSales        bool
+	VerificationInterval    time.Duration
+	VerificationMaxAttempts int
+	VerificationBackoff     time.Duration
+	VerificationMaxBackoff  time.Duration
+
+	// Reviewers, when set, are auto-assigned the sales that enter pending in
+	// round-robin order. A claim (automatic or POST /sales/:id/claim) expires
+	// after ClaimTTL and the scheduler returns the sale to the queue.
+	Reviewers []string
+	ClaimTTL  time.Duration
+
+	// Enrichers run in order on every new sale before it is saved, as
+	// name=policy pairs with policy fail, warn or skip, e.g.
+	// ENRICHERS="segment=fail,geoip=warn". GeoIPURL is the geo service with an
+	// {ip} placeholder; SegmentThresholds maps segment to minimum amount,
+	// e.g. SEGMENT_THRESHOLDS="enterprise=10000,business=1000".
+	Enrichers         []string
+	GeoIPURL          string
+	SegmentThresholds map[string]float64
+
+	// QueryLimits is a JSON object of per-role search guardrails, e.g.
+	// QUERY_LIMITS={"default":{"require_filter":true,"max_date_range_days":31},"admin":{}}.
+	QueryLimits string
+}
+
+// Default returns the configuration used when no environment is set.
+func Default() Config {
+	return Config{
+		UserServiceURL:    "http://localhost:8080/users",
+		SchedulerInterval: time.Minute,
+		ListenAddrs:       []string{":8081"},
+		Environment:       "development",
+		LockBackend:       LockBackendLocal,
+		RedisAddr:         "localhost:6379",
+
+		SalesStorageBackend: BackendMemory,
+		DynamoDBTable:       "sales",
+
+		MySQLTable:           "sales",
+		MySQLMaxOpenConns:    10,
+		MySQLMaxIdleConns:    5,
+		MySQLConnMaxLifetime: 5 * time.Minute,
+		MySQLTimeout:         5 * time.Second,
+
+		SQLitePath:    "sales.db",
+		SQLiteTimeout: 5 * time.Second,
+
+		MigrateOnStart: true,
+
+		RedisSalesTimeout: time.Second,
+
+		StorageRetryAttempts:   map[string]int{BackendDynamoDB: 3, BackendMySQL: 3, BackendRedis: 3},
+		StorageRetryBackoff:    map[string]time.Duration{BackendDynamoDB: 50 * time.Millisecond, BackendMySQL: 50 * time.Millisecond, BackendRedis: 20 * time.Millisecond},
+		StorageRetryMaxBackoff: time.Second,
+
+		ReplicationPromoteTimeout: 30 * time.Second,
+
+		BusinessHours:    "09:00-18:00",
+		BusinessDays:     []string{"mon", "tue", "wed", "thu", "fri"},
+		BusinessTimezone: "UTC",
+		SLACheckInterval: time.Minute,
+
+		VerificationInterval:    30 * time.Second,
+		VerificationMaxAttempts: 10,
+		VerificationBackoff:     30 * time.Second,
+		VerificationMaxBackoff:  time.Hour,
+
+		ClaimTTL: 30 * time.Minute,
+
+		AttachmentMaxBytes: 10 << 20,
+		ClamAVTimeout:      30 * time.Second,
+		OCRTimeout:         30 * time.Second,
+		ReceiptMaxBytes:    10 << 20,
+
+		IdempotencyBackend: BackendMemory,
+		IdempotencyTTL:     24 * time.Hour,
+		UserCacheBackend:   BackendMemory,
+		UserCacheTTL:       5 * time.Minute,
+
+		RecurringStorageBackend: BackendMemory,
+
+		ShedMinSamples: 20,
+		ShedWindow:     30 * time.Second,
+		ShedRetryAfter: 5 * time.Second,
+
+		LaneQueueTimeout: 5 * time.Second,
+
+		ConcurrencyQueueSize:    16,
+		ConcurrencyQueueTimeout: 200 * time.Millisecond,
+
+		WarmUpMaxUsers:    1000,
+		WarmUpConcurrency: 8,
+		WarmUpTimeout:     30 * time.Second,
+
+		StatsCacheTTL: 30 * time.Second,
+
+		UserSyncTimezone:    "UTC",
+		UserSyncConcurrency: 4,
+		UserSyncCacheTTL:    26 * time.Hour,
+
+		WebhookWorkers:   4,
+		WebhookQueueSize: 1000,
+
+		ERPWorkers:   2,
+		ERPQueueSize: 1000,
+
+		ChatProvider:       "slack",
+		ChatEvents:         []string{"large_sale_created", "circuit_breaker_open", "dlq_growth"},
+		LargeSaleThreshold: 10000,
+
+		SMSHighValueThreshold: 1000,
+
+		KeyRotationWindow: 24 * time.Hour,
+
+		RedactFields: []string{"user_id", "email", "phone"},
+
+		SlowQueryThreshold: 500 * time.Millisecond,
+
+		TenantUsageBackend: BackendMemory,
+
+		ChaosTargets: []string{"http", "storage"},
+		ChaosLatency: 200 * time.Millisecond,
+
+		AccessLogOutput:        "stdout",
+		AccessLogSlowThreshold: time.Second,
+		AccessLogSampleAfter:   100,
+
+		AnalyticsBatchSize:     100,
+		AnalyticsFlushInterval: 30 * time.Second,
+
+		SLOWindow:        30 * 24 * time.Hour,
+		SLOCheckInterval: time.Minute,
+
+		MaintenanceMode:       "off",
+		MaintenanceBackend:    BackendMemory,
+		MaintenanceRetryAfter: 5 * time.Minute,
+		MaintenanceRefresh:    5 * time.Second,
+
+		SelfCheckEnabled:         true,
+		SelfCheckTimeout:         5 * time.Second,
+		ShippingWebhookTolerance: 5 * time.Minute,
+
+		PublicLookupRate:  1,
+		PublicLookupBurst: 10,
+
+		PatchDedupWindow: 10 * time.Minute,
+
+		ShareLinkTTL:    72 * time.Hour,
+		ShareLinkMaxTTL: 30 * 24 * time.Hour,
+	}
+}
+
+// Load reads the configuration from the environment on top of Default.
+func Load() Config {
+	cfg := Default()
+	cfg.UserServiceURL = getEnv("USER_SERVICE_URL", cfg.UserServiceURL)
+	cfg.SchedulerInterval = getDuration("SCHEDULER_INTERVAL", cfg.SchedulerInterval)
+	cfg.Environment = getEnv("APP_ENV", cfg.Environment)
+	cfg.RandomSeed = getInt("RANDOM_SEED", cfg.RandomSeed)
+	cfg.ListenAddrs = getList("LISTEN_ADDRS", cfg.ListenAddrs)
+	cfg.AdminListenAddrs = getList("ADMIN_LISTEN_ADDRS", cfg.AdminListenAddrs)
+	cfg.LockBackend = getEnv("LOCK_BACKEND", cfg.LockBackend)
+	cfg.RedisAddr = getEnv("REDIS_ADDR", cfg.RedisAddr)
+	cfg.PostgresDSN = getEnv("POSTGRES_DSN", cfg.PostgresDSN)
+	cfg.SalesStorageBackend = getEnv("SALES_STORAGE_BACKEND", cfg.SalesStorageBackend)
+	cfg.DynamoDBTable = getEnv("DYNAMODB_TABLE", cfg.DynamoDBTable)
+	cfg.DynamoDBUserIndex = getEnv("DYNAMODB_USER_INDEX", cfg.DynamoDBUserIndex)
+	cfg.MySQLDSN = getEnv("MYSQL_DSN", cfg.MySQLDSN)
+	cfg.MySQLTable = getEnv("MYSQL_TABLE", cfg.MySQLTable)
+	cfg.MySQLMaxOpenConns = getInt("MYSQL_MAX_OPEN_CONNS", cfg.MySQLMaxOpenConns)
+	cfg.MySQLMaxIdleConns = getInt("MYSQL_MAX_IDLE_CONNS", cfg.MySQLMaxIdleConns)
+	cfg.MySQLConnMaxLifetime = getDuration("MYSQL_CONN_MAX_LIFETIME", cfg.MySQLConnMaxLifetime)
+	cfg.MySQLTimeout = getDuration("MYSQL_TIMEOUT", cfg.MySQLTimeout)
+	cfg.SQLitePath = getEnv("SQLITE_PATH", cfg.SQLitePath)
+	cfg.SQLiteTimeout = getDuration("SQLITE_TIMEOUT", cfg.SQLiteTimeout)
+	cfg.MigrateOnStart = getBool("MIGRATE_ON_START", cfg.MigrateOnStart)
+	cfg.RedisSalesTTL = getDuration("REDIS_SALES_TTL", cfg.RedisSalesTTL)
+	cfg.RedisSalesTimeout = getDuration("REDIS_SALES_TIMEOUT", cfg.RedisSalesTimeout)
+	cfg.StorageRetryAttempts = getIntMap("STORAGE_RETRY_ATTEMPTS", cfg.StorageRetryAttempts)
+	cfg.StorageRetryBackoff = getDurationMap("STORAGE_RETRY_BACKOFF", cfg.StorageRetryBackoff)
+	cfg.StorageRetryMaxBackoff = getDuration("STORAGE_RETRY_MAX_BACKOFF", cfg.StorageRetryMaxBackoff)
+	cfg.ShadowStorageBackend = getEnv("SHADOW_STORAGE_BACKEND", cfg.ShadowStorageBackend)
+	cfg.ReplicationBackend = getEnv("REPLICATION_BACKEND", cfg.ReplicationBackend)
+	cfg.ReplicationRegion = getEnv("REPLICATION_REGION", cfg.ReplicationRegion)
+	cfg.ReplicationDynamoDBTable = getEnv("REPLICATION_DYNAMODB_TABLE", cfg.ReplicationDynamoDBTable)
+	cfg.ReplicationPromoteTimeout = getDuration("REPLICATION_PROMOTE_TIMEOUT", cfg.ReplicationPromoteTimeout)
+	cfg.IdempotencyBackend = getEnv("IDEMPOTENCY_BACKEND", cfg.IdempotencyBackend)
+	cfg.IdempotencyTTL = getDuration("IDEMPOTENCY_TTL", cfg.IdempotencyTTL)
+	cfg.UserCacheBackend = getEnv("USER_CACHE_BACKEND", cfg.UserCacheBackend)
+	cfg.UserCacheTTL = getDuration("USER_CACHE_TTL", cfg.UserCacheTTL)
+	cfg.RecurringStorageBackend = getEnv("RECURRING_STORAGE_BACKEND", cfg.RecurringStorageBackend)
+	cfg.ShedMaxP95Latency = getDuration("SHED_MAX_P95_LATENCY", cfg.ShedMaxP95Latency)
+	cfg.ShedMaxErrorRate = getFloat("SHED_MAX_ERROR_RATE", cfg.ShedMaxErrorRate)
+	cfg.ShedMinSamples = getInt("SHED_MIN_SAMPLES", cfg.ShedMinSamples)
+	cfg.ShedWindow = getDuration("SHED_WINDOW", cfg.ShedWindow)
+	cfg.ShedRetryAfter = getDuration("SHED_RETRY_AFTER", cfg.ShedRetryAfter)
+	cfg.LaneInteractiveConcurrency = getInt("LANE_INTERACTIVE_CONCURRENCY", cfg.LaneInteractiveConcurrency)
+	cfg.LaneBatchConcurrency = getInt("LANE_BATCH_CONCURRENCY", cfg.LaneBatchConcurrency)
+	cfg.LaneQueueTimeout = getDuration("LANE_QUEUE_TIMEOUT", cfg.LaneQueueTimeout)
+	cfg.ConcurrencyLimits = getIntMap("CONCURRENCY_LIMITS", cfg.ConcurrencyLimits)
+	cfg.ConcurrencyQueueSize = getInt("CONCURRENCY_QUEUE_SIZE", cfg.ConcurrencyQueueSize)
+	cfg.ConcurrencyQueueTimeout = getDuration("CONCURRENCY_QUEUE_TIMEOUT", cfg.ConcurrencyQueueTimeout)
+	cfg.WarmUpEnabled = getBool("WARMUP_ENABLED", cfg.WarmUpEnabled)
+	cfg.WarmUpMaxUsers = getInt("WARMUP_MAX_USERS", cfg.WarmUpMaxUsers)
+	cfg.WarmUpConcurrency = getInt("WARMUP_CONCURRENCY", cfg.WarmUpConcurrency)
+	cfg.WarmUpTimeout = getDuration("WARMUP_TIMEOUT", cfg.WarmUpTimeout)
+	cfg.StatsCacheTTL = getDuration("STATS_CACHE_TTL", cfg.StatsCacheTTL)
+	cfg.UserSyncAt = getEnv("USER_SYNC_AT", cfg.UserSyncAt)
+	cfg.UserSyncTimezone = getEnv("USER_SYNC_TIMEZONE", cfg.UserSyncTimezone)
+	cfg.UserSyncConcurrency = getInt("USER_SYNC_CONCURRENCY", cfg.UserSyncConcurrency)
+	cfg.UserSyncCacheTTL = getDuration("USER_SYNC_CACHE_TTL", cfg.UserSyncCacheTTL)
+	cfg.WebhookURLs = getList("WEBHOOK_URLS", cfg.WebhookURLs)
+	cfg.WebhookWorkers = getInt("WEBHOOK_WORKERS", cfg.WebhookWorkers)
+	cfg.WebhookQueueSize = getInt("WEBHOOK_QUEUE_SIZE", cfg.WebhookQueueSize)
+	cfg.ERPURL = getEnv("ERP_URL", cfg.ERPURL)
+	cfg.ERPTemplateFile = getEnv("ERP_TEMPLATE_FILE", cfg.ERPTemplateFile)
+	cfg.ERPWorkers = getInt("ERP_WORKERS", cfg.ERPWorkers)
+	cfg.ERPQueueSize = getInt("ERP_QUEUE_SIZE", cfg.ERPQueueSize)
+	cfg.ChatProvider = getEnv("CHAT_PROVIDER", cfg.ChatProvider)
+	cfg.ChatWebhookURL = getEnv("CHAT_WEBHOOK_URL", cfg.ChatWebhookURL)
+	cfg.ChatEvents = getList("CHAT_EVENTS", cfg.ChatEvents)
+	cfg.ChatTemplates = getTemplates("CHAT_TEMPLATE_", cfg.ChatEvents)
+	cfg.LargeSaleThreshold = getFloat("LARGE_SALE_THRESHOLD", cfg.LargeSaleThreshold)
+	cfg.SMSBaseURL = getEnv("SMS_BASE_URL", cfg.SMSBaseURL)
+	cfg.SMSAccountSID = getEnv("SMS_ACCOUNT_SID", cfg.SMSAccountSID)
+	cfg.SMSAuthToken = getEnv("SMS_AUTH_TOKEN", cfg.SMSAuthToken)
+	cfg.SMSFrom = getEnv("SMS_FROM", cfg.SMSFrom)
+	cfg.SMSHighValueThreshold = getFloat("SMS_HIGH_VALUE_THRESHOLD", cfg.SMSHighValueThreshold)
+	cfg.OutboundProxyURL = getEnv("OUTBOUND_PROXY_URL", cfg.OutboundProxyURL)
+	cfg.TLSRootCAFiles = getList("TLS_ROOT_CA_FILES", cfg.TLSRootCAFiles)
+	cfg.TLSClientCertFile = getEnv("TLS_CLIENT_CERT_FILE", cfg.TLSClientCertFile)
+	cfg.TLSClientKeyFile = getEnv("TLS_CLIENT_KEY_FILE", cfg.TLSClientKeyFile)
+	cfg.KeyRotationWindow = getDuration("KEY_ROTATION_WINDOW", cfg.KeyRotationWindow)
+	cfg.AdminAPIKey = getEnv("ADMIN_API_KEY", cfg.AdminAPIKey)
+	cfg.WebhookSigningSecret = getEnv("WEBHOOK_SIGNING_SECRET", cfg.WebhookSigningSecret)
+	cfg.RedactFields = getList("REDACT_FIELDS", cfg.RedactFields)
+	cfg.SensitiveMetadataKeys = getList("SENSITIVE_METADATA_KEYS", cfg.SensitiveMetadataKeys)
+	cfg.SnapshotDir = getEnv("SNAPSHOT_DIR", cfg.SnapshotDir)
+	cfg.AttachmentDir = getEnv("ATTACHMENT_DIR", cfg.AttachmentDir)
+	cfg.AttachmentMaxBytes = getInt("ATTACHMENT_MAX_BYTES", cfg.AttachmentMaxBytes)
+	cfg.ClamAVAddr = getEnv("CLAMAV_ADDR", cfg.ClamAVAddr)
+	cfg.ClamAVTimeout = getDuration("CLAMAV_TIMEOUT", cfg.ClamAVTimeout)
+	cfg.OCRURL = getEnv("OCR_URL", cfg.OCRURL)
+	cfg.OCRAPIKey = getEnv("OCR_API_KEY", cfg.OCRAPIKey)
+	cfg.OCRTimeout = getDuration("OCR_TIMEOUT", cfg.OCRTimeout)
+	cfg.ReceiptMaxBytes = getInt("RECEIPT_MAX_BYTES", cfg.ReceiptMaxBytes)
+	cfg.PendingSLA = getDuration("PENDING_SLA", cfg.PendingSLA)
+	cfg.BusinessHours = getEnv("BUSINESS_HOURS", cfg.BusinessHours)
+	cfg.BusinessDays = getList("BUSINESS_DAYS", cfg.BusinessDays)
+	cfg.BusinessTimezone = getEnv("BUSINESS_TIMEZONE", cfg.BusinessTimezone)
+	cfg.Holidays = getList("HOLIDAYS", cfg.Holidays)
+	cfg.SLACheckInterval = getDuration("SLA_CHECK_INTERVAL", cfg.SLACheckInterval)
+	cfg.ProvisionalSales = getBool("PROVISIONAL_SALES", cfg.ProvisionalSales)
+	cfg.VerificationInterval = getDuration("VERIFICATION_INTERVAL", cfg.VerificationInterval)
+	cfg.VerificationMaxAttempts = getInt("VERIFICATION_MAX_ATTEMPTS", cfg.VerificationMaxAttempts)
+	cfg.VerificationBackoff = getDuration("VERIFICATION_BACKOFF", cfg.VerificationBackoff)
+	cfg.VerificationMaxBackoff = getDuration("VERIFICATION_MAX_BACKOFF", cfg.VerificationMaxBackoff)
+	cfg.Reviewers = getList("REVIEWERS", cfg.Reviewers)
+	cfg.ClaimTTL = getDuration("CLAIM_TTL", cfg.ClaimTTL)
+	cfg.FieldEncryptionKey = getEnv("FIELD_ENCRYPTION_KEY", cfg.FieldEncryptionKey)
+	cfg.ResponseCacheTTLs = getDurationMap("RESPONSE_CACHE_TTLS", cfg.ResponseCacheTTLs)
+	cfg.HTTPCacheMaxAge = getDuration("HTTP_CACHE_MAX_AGE", cfg.HTTPCacheMaxAge)
+	cfg.PatchDedupWindow = getDuration("PATCH_DEDUP_WINDOW", cfg.PatchDedupWindow)
+	cfg.TenantQuotas = getEnv("TENANT_QUOTAS", cfg.TenantQuotas)
+	cfg.TenantUsageBackend = getEnv("TENANT_USAGE_BACKEND", cfg.TenantUsageBackend)
+	cfg.TenantStatuses = getEnv("TENANT_STATUSES", cfg.TenantStatuses)
+	cfg.ChaosEnabled = getBool("CHAOS_ENABLED", cfg.ChaosEnabled)
+	cfg.ChaosTargets = getList("CHAOS_TARGETS", cfg.ChaosTargets)
+	cfg.ChaosLatency = getDuration("CHAOS_LATENCY", cfg.ChaosLatency)
+	cfg.ChaosLatencyRate = getFloat("CHAOS_LATENCY_RATE", cfg.ChaosLatencyRate)
+	cfg.ChaosErrorRate = getFloat("CHAOS_ERROR_RATE", cfg.ChaosErrorRate)
+	cfg.ChaosPartialRate = getFloat("CHAOS_PARTIAL_RATE", cfg.ChaosPartialRate)
+	cfg.ChaosSeed = getInt("CHAOS_SEED", cfg.ChaosSeed)
+	cfg.OpenAPIValidation = getBool("OPENAPI_VALIDATION", cfg.OpenAPIValidation)
+	cfg.OpenAPIValidateResponses = getBool("OPENAPI_VALIDATE_RESPONSES", cfg.OpenAPIValidateResponses)
+	cfg.AccessLogOutput = getEnv("ACCESS_LOG_OUTPUT", cfg.AccessLogOutput)
+	cfg.AccessLogSlowThreshold = getDuration("ACCESS_LOG_SLOW_THRESHOLD", cfg.AccessLogSlowThreshold)
+	cfg.AccessLogSampleAfter = getInt("ACCESS_LOG_SAMPLE_AFTER", cfg.AccessLogSampleAfter)
+	cfg.AccessLogSampleRates = getFloatMap("ACCESS_LOG_SAMPLE_RATES", cfg.AccessLogSampleRates)
+	cfg.AnalyticsURL = getEnv("ANALYTICS_URL", cfg.AnalyticsURL)
+	cfg.AnalyticsAPIKey = getEnv("ANALYTICS_API_KEY", cfg.AnalyticsAPIKey)
+	cfg.AnalyticsTenantKey = getEnv("ANALYTICS_TENANT_KEY", cfg.AnalyticsTenantKey)
+	cfg.AnalyticsBatchSize = getInt("ANALYTICS_BATCH_SIZE", cfg.AnalyticsBatchSize)
+	cfg.AnalyticsFlushInterval = getDuration("ANALYTICS_FLUSH_INTERVAL", cfg.AnalyticsFlushInterval)
+	cfg.SLOs = getEnv("SLOS", cfg.SLOs)
+	cfg.SLOWindow = getDuration("SLO_WINDOW", cfg.SLOWindow)
+	cfg.SLOCheckInterval = getDuration("SLO_CHECK_INTERVAL", cfg.SLOCheckInterval)
+	cfg.SlowQueryThreshold = getDuration("SLOW_QUERY_THRESHOLD", cfg.SlowQueryThreshold)
+	cfg.SearchCanaryPercent = getFloat("SEARCH_CANARY_PERCENT", cfg.SearchCanaryPercent)
+	cfg.QueryLimits = getEnv("QUERY_LIMITS", cfg.QueryLimits)
+	cfg.MaintenanceMode = getEnv("MAINTENANCE_MODE", cfg.MaintenanceMode)
+	cfg.MaintenanceBackend = getEnv("MAINTENANCE_BACKEND", cfg.MaintenanceBackend)
+	cfg.MaintenanceRetryAfter = getDuration("MAINTENANCE_RETRY_AFTER", cfg.MaintenanceRetryAfter)
+	cfg.MaintenanceRefresh = getDuration("MAINTENANCE_REFRESH", cfg.MaintenanceRefresh)
+	cfg.SelfCheckEnabled = getBool("SELF_CHECK_ENABLED", cfg.SelfCheckEnabled)
+	cfg.SelfCheckTimeout = getDuration("SELF_CHECK_TIMEOUT", cfg.SelfCheckTimeout)
+	cfg.JWTSecret = getEnv("JWT_SECRET", cfg.JWTSecret)
+	cfg.JWTIssuer = getEnv("JWT_ISSUER", cfg.JWTIssuer)
+	cfg.JWTAudience = getEnv("JWT_AUDIENCE", cfg.JWTAudience)
+	cfg.ShippingWebhookSecret = getEnv("SHIPPING_WEBHOOK_SECRET", cfg.ShippingWebhookSecret)
+	cfg.ShippingWebhookTolerance = getDuration("SHIPPING_WEBHOOK_TOLERANCE", cfg.ShippingWebhookTolerance)
+	cfg.PublicLookupRate = getFloat("PUBLIC_LOOKUP_RATE", cfg.PublicLookupRate)
+	cfg.PublicLookupBurst = getInt("PUBLIC_LOOKUP_BURST", cfg.PublicLookupBurst)
+	cfg.ShareLinkSecret = getEnv("SHARE_LINK_SECRET", cfg.ShareLinkSecret)
+	cfg.ShareLinkTTL = getDuration("SHARE_LINK_TTL", cfg.ShareLinkTTL)
+	cfg.ShareLinkMaxTTL = getDuration("SHARE_LINK_MAX_TTL", cfg.ShareLinkMaxTTL)
+	cfg.ShareLinkBaseURL = getEnv("SHARE_LINK_BASE_URL", cfg.ShareLinkBaseURL)
+	cfg.Enrichers = getList("ENRICHERS", cfg.Enrichers)
+	cfg.GeoIPURL = getEnv("GEOIP_URL", cfg.GeoIPURL)
+	cfg.SegmentThresholds = getFloatMap("SEGMENT_THRESHOLDS", cfg.SegmentThresholds)
+	return cfg
+}
+
+func getEnv(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
+func getList(key string, fallback []string) []string {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return fallback
+	}
+	list := make([]string, 0)
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// getDurationMap lee pares "clave=duración" separados por comas.
+func getDurationMap(key string, fallback map[string]time.Duration) map[string]time.Duration {
+	items := getList(key, nil)
+	if items == nil {
+		return fallback
+	}
+	m := map[string]time.Duration{}
+	for _, item := range items {
+		k, v, ok := strings.Cut(item, "=")
+		if !ok {
+			continue
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(v))
+		if err != nil {
+			continue
+		}
+		m[strings.TrimSpace(k)] = d
+	}
+	return m
+}
+
+// getFloatMap lee pares "clave=número" separados por comas.
+func getFloatMap(key string, fallback map[string]float64) map[string]float64 {
+	items := getList(key, nil)
+	if items == nil {
+		return fallback
+	}
+	m := map[string]float64{}
+	for _, item := range items {
+		k, v, ok := strings.Cut(item, "=")
+		if !ok {
+			continue
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			continue
+		}
+		m[strings.TrimSpace(k)] = f
+	}
+	return m
+}
+
+// getIntMap lee pares "clave=entero" separados por comas.
+func getIntMap(key string, fallback map[string]int) map[string]int {
+	items := getList(key, nil)
+	if items == nil {
+		return fallback
+	}
+	m := map[string]int{}
+	for _, item := range items {
+		k, v, ok := strings.Cut(item, "=")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			continue
+		}
+		m[strings.TrimSpace(k)] = n
+	}
+	return m
+}
+
+// getTemplates lee una plantilla opcional por evento desde prefix+EVENTO.
+func getTemplates(prefix string, events []string) map[string]string {
+	m := map[string]string{}
+	for _, event := range events {
+		if v := getEnv(prefix+strings.ToUpper(event), ""); v != "" {
+			m[event] = v
+		}
+	}
+	return m
+}
+
+func getBool(key string, fallback bool) bool {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fallback
+	}
+	return b
+}
+
+func getInt(key string, fallback int) int {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fallback
+	}
+	return n
+}
+
+func getFloat(key string, fallback float64) float64 {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fallback
+	}
+	return f
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fallback
+	}
+	return d
+}
@@ -0,0 +1,327 @@
+package config
+
+import (
+	"api_sales/internal/calendar"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Validate reports every invalid setting at once, naming the environment
+// variable to fix, so a misconfigured deployment fails at boot instead of on
+// the first request.
+func (c Config) Validate() error {
+	var errs []error
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if u, err := url.Parse(c.UserServiceURL); err != nil || u.Scheme == "" || u.Host == "" {
+		add("USER_SERVICE_URL: %q is not an absolute URL", c.UserServiceURL)
+	}
+	if len(c.ListenAddrs) == 0 {
+		add("LISTEN_ADDRS: at least one address is required")
+	}
+	for _, addr := range append(append([]string{}, c.ListenAddrs...), c.AdminListenAddrs...) {
+		if !strings.HasPrefix(addr, "unix:") {
+			if _, _, err := net.SplitHostPort(addr); err != nil {
+				add("LISTEN_ADDRS, ADMIN_LISTEN_ADDRS: %q is neither host:port nor unix:/path", addr)
+			}
+		}
+	}
+	if c.SchedulerInterval <= 0 {
+		add("SCHEDULER_INTERVAL: must be greater than zero")
+	}
+	if c.ShedMaxP95Latency < 0 || c.ShedMaxErrorRate < 0 || c.ShedMaxErrorRate > 1 {
+		add("SHED_MAX_P95_LATENCY, SHED_MAX_ERROR_RATE: must be positive, the error rate at most 1")
+	}
+	if (c.ShedMaxP95Latency > 0 || c.ShedMaxErrorRate > 0) && c.ShedWindow <= 0 {
+		add("SHED_WINDOW: must be greater than zero")
+	}
+	if c.LaneInteractiveConcurrency < 0 || c.LaneBatchConcurrency < 0 {
+		add("LANE_INTERACTIVE_CONCURRENCY, LANE_BATCH_CONCURRENCY: must not be negative")
+	}
+	if (c.LaneInteractiveConcurrency > 0 || c.LaneBatchConcurrency > 0) && c.LaneQueueTimeout < 0 {
+		add("LANE_QUEUE_TIMEOUT: must not be negative")
+	}
+	for prefix, limit := range c.ConcurrencyLimits {
+		if limit <= 0 {
+			add("CONCURRENCY_LIMITS: %s must be greater than zero", prefix)
+		}
+	}
+	if len(c.ConcurrencyLimits) > 0 && (c.ConcurrencyQueueSize < 0 || c.ConcurrencyQueueTimeout < 0) {
+		add("CONCURRENCY_QUEUE_SIZE, CONCURRENCY_QUEUE_TIMEOUT: must not be negative")
+	}
+	if c.ProvisionalSales {
+		if c.VerificationInterval <= 0 {
+			add("VERIFICATION_INTERVAL: must be greater than zero")
+		}
+		if c.VerificationMaxAttempts <= 0 {
+			add("VERIFICATION_MAX_ATTEMPTS: must be greater than zero")
+		}
+		if c.VerificationBackoff <= 0 || c.VerificationMaxBackoff < c.VerificationBackoff {
+			add("VERIFICATION_BACKOFF, VERIFICATION_MAX_BACKOFF: backoff must be positive and not above the max")
+		}
+	}
+	if c.SearchCanaryPercent < 0 || c.SearchCanaryPercent > 100 {
+		add("SEARCH_CANARY_PERCENT: must be between 0 and 100")
+	}
+	if c.ClaimTTL <= 0 {
+		add("CLAIM_TTL: must be greater than zero")
+	}
+	if c.AttachmentDir != "" && c.AttachmentMaxBytes <= 0 {
+		add("ATTACHMENT_MAX_BYTES: must be greater than zero")
+	}
+	if c.ClamAVAddr != "" && c.AttachmentDir == "" {
+		add("CLAMAV_ADDR: requires ATTACHMENT_DIR")
+	}
+	if c.ClamAVAddr != "" && c.ClamAVTimeout <= 0 {
+		add("CLAMAV_TIMEOUT: must be greater than zero")
+	}
+	if c.OCRURL != "" && c.OCRTimeout <= 0 {
+		add("OCR_TIMEOUT: must be greater than zero")
+	}
+	if c.OCRURL != "" && c.ReceiptMaxBytes <= 0 {
+		add("RECEIPT_MAX_BYTES: must be greater than zero")
+	}
+	if c.UserSyncAt != "" {
+		if _, err := time.Parse("15:04", c.UserSyncAt); err != nil {
+			add("USER_SYNC_AT: %q is not a time of day as HH:MM", c.UserSyncAt)
+		}
+		if _, err := time.LoadLocation(c.UserSyncTimezone); err != nil {
+			add("USER_SYNC_TIMEZONE: %v", err)
+		}
+		if c.UserSyncCacheTTL < 24*time.Hour {
+			add("USER_SYNC_CACHE_TTL: must be at least 24h so users stay cached until the next sync")
+		}
+	}
+	if c.PendingSLA < 0 {
+		add("PENDING_SLA: must not be negative")
+	}
+	if c.PendingSLA > 0 {
+		if _, err := calendar.Parse(c.BusinessHours, c.BusinessDays, c.BusinessTimezone, c.Holidays); err != nil {
+			add("BUSINESS_HOURS, BUSINESS_DAYS, BUSINESS_TIMEZONE, HOLIDAYS: %v", err)
+		}
+		if c.SLACheckInterval <= 0 {
+			add("SLA_CHECK_INTERVAL: must be greater than zero")
+		}
+	}
+
+	switch c.LockBackend {
+	case "", LockBackendLocal, LockBackendRedis:
+	case LockBackendPostgres:
+		if c.PostgresDSN == "" {
+			add("POSTGRES_DSN: required when LOCK_BACKEND=postgres")
+		}
+	default:
+		add("LOCK_BACKEND: unknown backend %q (expected local, redis or postgres)", c.LockBackend)
+	}
+	for name, backend := range map[string]string{
+		"SALES_STORAGE_BACKEND":  c.SalesStorageBackend,
+		"SHADOW_STORAGE_BACKEND": c.ShadowStorageBackend,
+		"REPLICATION_BACKEND":    c.ReplicationBackend,
+	} {
+		switch backend {
+		case "", BackendMemory:
+		case BackendDynamoDB:
+			if c.DynamoDBTable == "" {
+				add("DYNAMODB_TABLE: required when %s=dynamodb", name)
+			}
+		case BackendMySQL:
+			if c.MySQLDSN == "" {
+				add("MYSQL_DSN: required when %s=mysql", name)
+			}
+		case BackendSQLite:
+			if c.SQLitePath == "" {
+				add("SQLITE_PATH: required when %s=sqlite", name)
+			}
+			if c.SQLiteTimeout <= 0 {
+				add("SQLITE_TIMEOUT: must be greater than zero")
+			}
+		case BackendRedis:
+			if c.RedisAddr == "" {
+				add("REDIS_ADDR: required when %s=redis", name)
+			}
+			if c.RedisSalesTTL < 0 {
+				add("REDIS_SALES_TTL: must not be negative")
+			}
+			if c.RedisSalesTimeout <= 0 {
+				add("REDIS_SALES_TIMEOUT: must be greater than zero")
+			}
+		default:
+			add("%s: unknown backend %q (expected memory, dynamodb, mysql, sqlite or redis)", name, backend)
+		}
+	}
+	if c.ReplicationBackend == BackendMySQL || c.ReplicationBackend == BackendSQLite || c.ReplicationBackend == BackendRedis {
+		add("REPLICATION_BACKEND: %s replicas are not supported (expected memory or dynamodb)", c.ReplicationBackend)
+	}
+	for _, backend := range []string{BackendSQLite, BackendRedis} {
+		if c.SalesStorageBackend == backend && c.ShadowStorageBackend == backend {
+			add("SHADOW_STORAGE_BACKEND: %s can't shadow a %s primary, both would use the same data", backend, backend)
+		}
+	}
+	if c.SalesStorageBackend == BackendMySQL || c.ShadowStorageBackend == BackendMySQL {
+		if c.MySQLMaxOpenConns < 1 {
+			add("MYSQL_MAX_OPEN_CONNS: must be at least 1")
+		}
+		if c.MySQLMaxIdleConns < 0 || c.MySQLMaxIdleConns > c.MySQLMaxOpenConns {
+			add("MYSQL_MAX_IDLE_CONNS: must be between 0 and MYSQL_MAX_OPEN_CONNS")
+		}
+		if c.MySQLTimeout <= 0 {
+			add("MYSQL_TIMEOUT: must be greater than zero")
+		}
+	}
+	for backend, attempts := range c.StorageRetryAttempts {
+		if attempts < 1 {
+			add("STORAGE_RETRY_ATTEMPTS: %s must be at least 1", backend)
+		}
+	}
+	for backend, backoff := range c.StorageRetryBackoff {
+		if backoff < 0 {
+			add("STORAGE_RETRY_BACKOFF: %s must not be negative", backend)
+		}
+	}
+	if c.ShadowStorageBackend != "" && c.ShadowStorageBackend == c.SalesStorageBackend && c.ShadowStorageBackend != BackendMemory {
+		add("SHADOW_STORAGE_BACKEND: must differ from SALES_STORAGE_BACKEND")
+	}
+	if c.ReplicationBackend == BackendDynamoDB && c.ReplicationRegion == "" {
+		add("REPLICATION_REGION: required when REPLICATION_BACKEND=dynamodb")
+	}
+	if c.ReplicationBackend != "" && c.ReplicationPromoteTimeout <= 0 {
+		add("REPLICATION_PROMOTE_TIMEOUT: must be positive")
+	}
+	for name, backend := range map[string]string{
+		"IDEMPOTENCY_BACKEND":       c.IdempotencyBackend,
+		"USER_CACHE_BACKEND":        c.UserCacheBackend,
+		"MAINTENANCE_BACKEND":       c.MaintenanceBackend,
+		"TENANT_USAGE_BACKEND":      c.TenantUsageBackend,
+		"RECURRING_STORAGE_BACKEND": c.RecurringStorageBackend,
+	} {
+		if backend != "" && backend != BackendMemory && backend != BackendRedis {
+			add("%s: unknown backend %q (expected memory or redis)", name, backend)
+		}
+	}
+
+	switch c.MaintenanceMode {
+	case "", "off", "read_only", "full":
+	default:
+		add("MAINTENANCE_MODE: unknown mode %q (expected off, read_only or full)", c.MaintenanceMode)
+	}
+
+	if c.ChatWebhookURL != "" && c.ChatProvider != "slack" && c.ChatProvider != "teams" {
+		add("CHAT_PROVIDER: unknown provider %q (expected slack or teams)", c.ChatProvider)
+	}
+	if (c.TLSClientCertFile == "") != (c.TLSClientKeyFile == "") {
+		add("TLS_CLIENT_CERT_FILE, TLS_CLIENT_KEY_FILE: both must be set for mTLS")
+	}
+	if c.FieldEncryptionKey != "" {
+		if key, err := base64.StdEncoding.DecodeString(c.FieldEncryptionKey); err != nil || len(key) != 32 {
+			add("FIELD_ENCRYPTION_KEY: must be 32 bytes encoded in base64")
+		}
+	}
+	if c.QueryLimits != "" && !json.Valid([]byte(c.QueryLimits)) {
+		add("QUERY_LIMITS: invalid JSON")
+	}
+	if c.SLOs != "" {
+		if !json.Valid([]byte(c.SLOs)) {
+			add("SLOS: invalid JSON")
+		}
+		if c.SLOWindow < 6*time.Hour {
+			add("SLO_WINDOW: must be at least 6h")
+		}
+		if c.SLOCheckInterval <= 0 {
+			add("SLO_CHECK_INTERVAL: must be greater than zero")
+		}
+	}
+	if c.TenantQuotas != "" && !json.Valid([]byte(c.TenantQuotas)) {
+		add("TENANT_QUOTAS: invalid JSON")
+	}
+	if c.TenantStatuses != "" && !json.Valid([]byte(c.TenantStatuses)) {
+		add("TENANT_STATUSES: invalid JSON")
+	}
+
+	if c.JWTSecret != "" && len(c.JWTSecret) < 32 {
+		add("JWT_SECRET: must be at least 32 bytes")
+	}
+	if c.ShippingWebhookSecret != "" && c.ShippingWebhookTolerance <= 0 {
+		add("SHIPPING_WEBHOOK_TOLERANCE: must be greater than zero")
+	}
+	if c.PublicLookupRate < 0 {
+		add("PUBLIC_LOOKUP_RATE: must not be negative")
+	}
+	if c.ShareLinkSecret != "" {
+		if len(c.ShareLinkSecret) < 32 {
+			add("SHARE_LINK_SECRET: must be at least 32 bytes")
+		}
+		if c.ShareLinkTTL <= 0 || c.ShareLinkTTL > c.ShareLinkMaxTTL {
+			add("SHARE_LINK_TTL: must be greater than zero and at most SHARE_LINK_MAX_TTL")
+		}
+	}
+
+	for _, item := range c.Enrichers {
+		name, policy, _ := strings.Cut(item, "=")
+		switch name {
+		case "geoip":
+			if c.GeoIPURL == "" {
+				add("GEOIP_URL: required by the geoip enricher")
+			}
+		case "segment":
+			if len(c.SegmentThresholds) == 0 {
+				add("SEGMENT_THRESHOLDS: required by the segment enricher")
+			}
+		default:
+			add("ENRICHERS: unknown enricher %q", name)
+		}
+		switch policy {
+		case "", "fail", "warn", "skip":
+		default:
+			add("ENRICHERS: invalid policy %q for %s (fail, warn or skip)", policy, name)
+		}
+	}
+
+	if c.ChaosEnabled {
+		if c.Environment == "production" {
+			add("CHAOS_ENABLED: fault injection is not allowed when APP_ENV=production")
+		}
+		for name, rate := range map[string]float64{
+			"CHAOS_LATENCY_RATE": c.ChaosLatencyRate,
+			"CHAOS_ERROR_RATE":   c.ChaosErrorRate,
+			"CHAOS_PARTIAL_RATE": c.ChaosPartialRate,
+		} {
+			if rate < 0 || rate > 1 {
+				add("%s: must be between 0 and 1", name)
+			}
+		}
+		if c.ChaosErrorRate+c.ChaosPartialRate > 1 {
+			add("CHAOS_ERROR_RATE, CHAOS_PARTIAL_RATE: must not add up to more than 1")
+		}
+	}
+
+	if c.AnalyticsURL != "" {
+		if len(c.AnalyticsTenantKey) < 32 {
+			add("ANALYTICS_TENANT_KEY: must be at least 32 bytes when ANALYTICS_URL is set")
+		}
+		if c.AnalyticsBatchSize <= 0 {
+			add("ANALYTICS_BATCH_SIZE: must be greater than zero")
+		}
+		if c.AnalyticsFlushInterval <= 0 {
+			add("ANALYTICS_FLUSH_INTERVAL: must be greater than zero")
+		}
+	}
+
+	if c.OpenAPIValidateResponses {
+		if !c.OpenAPIValidation {
+			add("OPENAPI_VALIDATE_RESPONSES: requires OPENAPI_VALIDATION")
+		}
+		if c.Environment == "production" {
+			add("OPENAPI_VALIDATE_RESPONSES: response validation is not allowed when APP_ENV=production")
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,181 @@
+// Package dispatch runs background deliveries (webhooks, notifications) on a
+// bounded worker pool so event storms apply backpressure instead of spawning
+// unbounded goroutines.
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+// ErrQueueFull is returned by Submit when the queue has no free slots.
+var ErrQueueFull = errors.New("dispatch queue full")
+
+// ErrClosed is returned by Submit after Close.
+var ErrClosed = errors.New("dispatch pool closed")
+
+var (
+	queueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "dispatch_queue_depth",
+		Help: "Jobs waiting in the dispatch queue.",
+	}, []string{"pool"})
+	inFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "dispatch_in_flight",
+		Help: "Jobs currently being executed by workers.",
+	}, []string{"pool"})
+	jobsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "dispatch_jobs_total",
+		Help: "Jobs handled by the dispatch pool, by result.",
+	}, []string{"pool", "result"})
+)
+
+// Job is a unit of work executed by a worker. ctx is cancelled when the pool
+// is closed without draining.
+type Job func(ctx context.Context)
+
+// Stats is a snapshot of the pool counters.
+type Stats struct {
+	QueueDepth int   `json:"queue_depth"`
+	QueueSize  int   `json:"queue_size"`
+	InFlight   int64 `json:"in_flight"`
+	Processed  int64 `json:"processed"`
+	Dropped    int64 `json:"dropped"`
+}
+
+type Pool struct {
+	name   string
+	jobs   chan Job
+	logger *zap.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+
+	inFlight  atomic.Int64
+	processed atomic.Int64
+	dropped   atomic.Int64
+}
+
+// NewPool starts workers goroutines consuming a queue of queueSize jobs.
+func NewPool(name string, workers, queueSize int, logger *zap.Logger) *Pool {
+	if workers <= 0 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pool{
+		name:   name,
+		jobs:   make(chan Job, queueSize),
+		logger: logger,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+	return p
+}
+
+// Submit enqueues job without blocking. When the queue is full the job is
+// dropped and ErrQueueFull is returned so the caller can decide what to do.
+func (p *Pool) Submit(job Job) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+
+	select {
+	case p.jobs <- job:
+		queueDepth.WithLabelValues(p.name).Set(float64(len(p.jobs)))
+		return nil
+	default:
+		p.dropped.Add(1)
+		jobsTotal.WithLabelValues(p.name, "dropped").Inc()
+		return ErrQueueFull
+	}
+}
+
+// Close stops accepting jobs and waits for queued ones to finish. If ctx ends
+// first, running jobs are cancelled and the remaining queue is discarded.
+func (p *Pool) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.jobs)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		return ctx.Err()
+	}
+}
+
+// Stats returns the current counters of the pool.
+func (p *Pool) Stats() Stats {
+	return Stats{
+		QueueDepth: len(p.jobs),
+		QueueSize:  cap(p.jobs),
+		InFlight:   p.inFlight.Load(),
+		Processed:  p.processed.Load(),
+		Dropped:    p.dropped.Load(),
+	}
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for job := range p.jobs {
+		queueDepth.WithLabelValues(p.name).Set(float64(len(p.jobs)))
+		if p.ctx.Err() != nil {
+			continue
+		}
+		p.run(job)
+	}
+}
+
+func (p *Pool) run(job Job) {
+	p.inFlight.Add(1)
+	inFlight.WithLabelValues(p.name).Inc()
+	defer func() {
+		p.inFlight.Add(-1)
+		inFlight.WithLabelValues(p.name).Dec()
+		if r := recover(); r != nil {
+			jobsTotal.WithLabelValues(p.name, "panicked").Inc()
+			p.logger.Error("dispatch job panicked", zap.String("pool", p.name), zap.Any("panic", r))
+			return
+		}
+		p.processed.Add(1)
+		jobsTotal.WithLabelValues(p.name, "processed").Inc()
+	}()
+
+	job(p.ctx)
+}
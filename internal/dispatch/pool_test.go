@@ -0,0 +1,62 @@
+package dispatch
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestPool_BackpressureWhenQueueFull verifica que se rechazan trabajos con la cola llena.
+func TestPool_BackpressureWhenQueueFull(t *testing.T) {
+	p := NewPool("test-backpressure", 1, 1, nil)
+
+	block := make(chan struct{})
+	started := make(chan struct{})
+	if err := p.Submit(func(context.Context) { close(started); <-block }); err != nil {
+		t.Fatalf("Submit returned error: %v", err)
+	}
+	<-started
+
+	if err := p.Submit(func(context.Context) {}); err != nil {
+		t.Fatalf("expected queued job, got %v", err)
+	}
+	if err := p.Submit(func(context.Context) {}); err != ErrQueueFull {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+
+	stats := p.Stats()
+	if stats.QueueDepth != 1 || stats.InFlight != 1 || stats.Dropped != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	close(block)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := p.Close(ctx); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if got := p.Stats().Processed; got != 2 {
+		t.Errorf("expected 2 processed jobs, got %d", got)
+	}
+	if err := p.Submit(func(context.Context) {}); err != ErrClosed {
+		t.Errorf("expected ErrClosed after Close, got %v", err)
+	}
+}
+
+// TestPool_RecoversPanics verifica que un trabajo que entra en pánico no detiene al worker.
+func TestPool_RecoversPanics(t *testing.T) {
+	p := NewPool("test-panic", 1, 2, nil)
+
+	var ran atomic.Bool
+	p.Submit(func(context.Context) { panic("boom") })
+	p.Submit(func(context.Context) { ran.Store(true) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	p.Close(ctx)
+
+	if !ran.Load() {
+		t.Error("expected job after panic to run")
+	}
+}
@@ -0,0 +1,132 @@
+// Package enrich provides the built-in sale enrichers: geo lookup of the
+// client IP and customer-segment tagging by amount.
+package enrich
+
+import (
+	"api_sales/internal/sales"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"resty.dev/v3"
+)
+
+// Metadata keys written by the built-in enrichers.
+const (
+	MetadataCountry = "geo_country"
+	MetadataSegment = "customer_segment"
+)
+
+// Names of the built-in enrichers, as used in the ENRICHERS setting.
+const (
+	NameGeoIP   = "geoip"
+	NameSegment = "segment"
+)
+
+// Error para ventas cuyo origen no trae una IP consultable
+var ErrNoClientIP = errors.New("no public client ip")
+
+// GeoIP tags sales with the country of the client IP, resolved by an HTTP
+// geo service that answers {"country_code": "AR"}.
+type GeoIP struct {
+	urlTemplate string
+	client      *resty.Client
+}
+
+// NewGeoIP creates a geo enricher. urlTemplate contains an {ip} placeholder,
+// e.g. https://geo.internal/v1/{ip}.
+func NewGeoIP(urlTemplate string) *GeoIP {
+	return &GeoIP{
+		urlTemplate: urlTemplate,
+		client:      resty.New().SetTimeout(2 * time.Second),
+	}
+}
+
+// SetTransport sends lookups through rt, e.g. to apply proxy and TLS settings.
+func (g *GeoIP) SetTransport(rt http.RoundTripper) {
+	g.client.SetTransport(rt)
+}
+
+// Name implements sales.Enricher.
+func (g *GeoIP) Name() string { return NameGeoIP }
+
+// Enrich implements sales.Enricher.
+func (g *GeoIP) Enrich(sale *sales.Sale, origin sales.Origin) error {
+	ip := net.ParseIP(origin.ClientIP)
+	// Las IPs privadas o de loopback no tienen país
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() {
+		return ErrNoClientIP
+	}
+
+	var result struct {
+		CountryCode string `json:"country_code"`
+	}
+	url := strings.ReplaceAll(g.urlTemplate, "{ip}", neturl.PathEscape(ip.String()))
+	resp, err := g.client.R().SetResult(&result).Get(url)
+	if err != nil {
+		return fmt.Errorf("geo lookup failed: %w", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("geo service returned status %d", resp.StatusCode())
+	}
+	if result.CountryCode == "" {
+		return fmt.Errorf("geo service returned no country for %s", ip)
+	}
+
+	setMetadata(sale, MetadataCountry, result.CountryCode)
+	return nil
+}
+
+// Segment tags sales with the customer segment of the highest threshold the
+// amount reaches. Sales below every threshold are left untagged.
+type Segment struct {
+	tiers []tier
+}
+
+type tier struct {
+	name string
+	min  float64
+}
+
+// NewSegment creates a segment enricher from segment name to minimum amount,
+// e.g. {"enterprise": 10000, "business": 1000, "retail": 0}.
+func NewSegment(thresholds map[string]float64) *Segment {
+	tiers := make([]tier, 0, len(thresholds))
+	for name, min := range thresholds {
+		tiers = append(tiers, tier{name: name, min: min})
+	}
+	// Mayor umbral primero; a igual umbral, orden alfabético para ser determinista
+	sort.Slice(tiers, func(i, j int) bool {
+		if tiers[i].min != tiers[j].min {
+			return tiers[i].min > tiers[j].min
+		}
+		return tiers[i].name < tiers[j].name
+	})
+	return &Segment{tiers: tiers}
+}
+
+// Name implements sales.Enricher.
+func (s *Segment) Name() string { return NameSegment }
+
+// Enrich implements sales.Enricher.
+func (s *Segment) Enrich(sale *sales.Sale, _ sales.Origin) error {
+	for _, t := range s.tiers {
+		if sale.Amount >= t.min {
+			setMetadata(sale, MetadataSegment, t.name)
+			return nil
+		}
+	}
+	return nil
+}
+
+func setMetadata(sale *sales.Sale, key, value string) {
+	if sale.Metadata == nil {
+		sale.Metadata = map[string]string{}
+	}
+	sale.Metadata[key] = value
+}
@@ -0,0 +1,50 @@
+package enrich
+
+import (
+	"api_sales/internal/sales"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestGeoIP_TagsCountry verifica que se consulta el servicio con la IP del cliente.
+func TestGeoIP_TagsCountry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/203.0.113.7" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"country_code":"AR"}`))
+	}))
+	defer server.Close()
+
+	geo := NewGeoIP(server.URL + "/v1/{ip}")
+	sale := &sales.Sale{ID: "s1", Amount: 10}
+	if err := geo.Enrich(sale, sales.Origin{ClientIP: "203.0.113.7"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sale.Metadata[MetadataCountry] != "AR" {
+		t.Errorf("expected country AR, got %v", sale.Metadata)
+	}
+
+	if err := geo.Enrich(&sales.Sale{}, sales.Origin{ClientIP: "10.0.0.1"}); !errors.Is(err, ErrNoClientIP) {
+		t.Errorf("expected ErrNoClientIP for private IP, got %v", err)
+	}
+}
+
+func TestSegment_HighestReachedThreshold(t *testing.T) {
+	segment := NewSegment(map[string]float64{"enterprise": 10000, "business": 1000})
+
+	cases := map[float64]string{50: "", 1000: "business", 25000: "enterprise"}
+	for amount, want := range cases {
+		sale := &sales.Sale{Amount: amount}
+		if err := segment.Enrich(sale, sales.Origin{}); err != nil {
+			t.Fatal(err)
+		}
+		if got := sale.Metadata[MetadataSegment]; got != want {
+			t.Errorf("amount %v: expected segment %q, got %q", amount, want, got)
+		}
+	}
+}
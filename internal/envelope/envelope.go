@@ -0,0 +1,154 @@
+// Package envelope encrypts individual values with per-value data keys that
+// are themselves wrapped by a KMS.
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Error para valores cifrados con un formato desconocido
+var ErrMalformed = errors.New("malformed encrypted value")
+
+// prefix marca los valores cifrados y su versión de formato.
+const prefix = "enc:v1:"
+
+// KMS generates data keys and unwraps them. Remote KMS services implement it
+// with their generate/decrypt data key calls.
+type KMS interface {
+	GenerateDataKey() (plaintext, wrapped []byte, err error)
+	DecryptDataKey(wrapped []byte) ([]byte, error)
+}
+
+// LocalKMS wraps data keys with a master key held in process. It is meant for
+// development and single-node deployments.
+type LocalKMS struct {
+	aead cipher.AEAD
+}
+
+// NewLocalKMS creates a KMS from a 32-byte master key.
+func NewLocalKMS(masterKey []byte) (*LocalKMS, error) {
+	if len(masterKey) != 32 {
+		return nil, fmt.Errorf("master key must be 32 bytes, got %d", len(masterKey))
+	}
+	aead, err := newGCM(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalKMS{aead: aead}, nil
+}
+
+func (k *LocalKMS) GenerateDataKey() ([]byte, []byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, nil, err
+	}
+	wrapped, err := seal(k.aead, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, wrapped, nil
+}
+
+func (k *LocalKMS) DecryptDataKey(wrapped []byte) ([]byte, error) {
+	return open(k.aead, wrapped)
+}
+
+// Envelope encrypts values with a fresh data key each.
+type Envelope struct {
+	kms KMS
+}
+
+func New(kms KMS) *Envelope {
+	return &Envelope{kms: kms}
+}
+
+// Encrypt returns "enc:v1:<wrapped key>:<ciphertext>", both base64 encoded.
+func (e *Envelope) Encrypt(plaintext string) (string, error) {
+	key, wrapped, err := e.kms.GenerateDataKey()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(aead, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return prefix + base64.RawStdEncoding.EncodeToString(wrapped) + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt reverses Encrypt.
+func (e *Envelope) Decrypt(value string) (string, error) {
+	rest, ok := strings.CutPrefix(value, prefix)
+	if !ok {
+		return "", ErrMalformed
+	}
+	wrappedB64, sealedB64, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", ErrMalformed
+	}
+	wrapped, err := base64.RawStdEncoding.DecodeString(wrappedB64)
+	if err != nil {
+		return "", ErrMalformed
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(sealedB64)
+	if err != nil {
+		return "", ErrMalformed
+	}
+
+	key, err := e.kms.DecryptDataKey(wrapped)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt data key: %w", err)
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(aead, sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// IsEncrypted reports whether value was produced by Encrypt.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal antepone el nonce al texto cifrado.
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(aead cipher.AEAD, sealed []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return plaintext, nil
+}
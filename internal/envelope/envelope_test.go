@@ -0,0 +1,37 @@
+package envelope
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestEnvelope_RoundTrip verifica el cifrado con claves de datos distintas por valor.
+func TestEnvelope_RoundTrip(t *testing.T) {
+	kms, err := NewLocalKMS(bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatalf("NewLocalKMS returned error: %v", err)
+	}
+	env := New(kms)
+
+	first, err := env.Encrypt("20-12345678-9")
+	if err != nil {
+		t.Fatalf("Encrypt returned error: %v", err)
+	}
+	second, _ := env.Encrypt("20-12345678-9")
+	if first == second || !IsEncrypted(first) {
+		t.Errorf("expected distinct envelope ciphertexts, got %q and %q", first, second)
+	}
+
+	plain, err := env.Decrypt(first)
+	if err != nil || plain != "20-12345678-9" {
+		t.Errorf("unexpected decryption: %q (%v)", plain, err)
+	}
+
+	other, _ := NewLocalKMS(bytes.Repeat([]byte{8}, 32))
+	if _, err := New(other).Decrypt(first); err == nil {
+		t.Error("expected decryption with another master key to fail")
+	}
+	if _, err := env.Decrypt("plain"); err != ErrMalformed {
+		t.Errorf("expected ErrMalformed, got %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+// Package erp posts approved sales to an external ERP.
+package erp
+
+import (
+	"api_sales/internal/dispatch"
+	"api_sales/internal/jsonenc"
+	"api_sales/internal/sales"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"text/template"
+	"time"
+
+	"go.uber.org/zap"
+	"resty.dev/v3"
+)
+
+// Poster sends a single sale to the ERP.
+type Poster interface {
+	Post(ctx context.Context, sale *sales.Sale) error
+}
+
+// StatusRecorder stores the posting status of a sale.
+type StatusRecorder interface {
+	SetERPPostingStatus(saleID, status string) error
+}
+
+// DefaultTemplate posts the sale as its JSON representation.
+const DefaultTemplate = `{{json .}}`
+
+// HTTPPoster posts sales as the body rendered from a mapping template. Failed
+// requests are retried by the client before Post returns an error.
+type HTTPPoster struct {
+	endpoint string
+	tmpl     *template.Template
+	client   *resty.Client
+}
+
+// NewHTTPPoster creates a poster for endpoint. The mapping template receives
+// the *sales.Sale and can use the json function to encode values; an empty
+// mapping uses DefaultTemplate.
+func NewHTTPPoster(endpoint, mapping string) (*HTTPPoster, error) {
+	if mapping == "" {
+		mapping = DefaultTemplate
+	}
+	tmpl, err := template.New("erp").Funcs(template.FuncMap{"json": toJSON}).Parse(mapping)
+	if err != nil {
+		return nil, fmt.Errorf("invalid erp mapping template: %w", err)
+	}
+
+	client := resty.New().
+		SetTimeout(10 * time.Second).
+		SetRetryCount(3).
+		SetRetryWaitTime(500 * time.Millisecond).
+		SetRetryMaxWaitTime(5 * time.Second).
+		SetAllowNonIdempotentRetry(true)
+
+	return &HTTPPoster{
+		endpoint: endpoint,
+		tmpl:     tmpl,
+		client:   client,
+	}, nil
+}
+
+// SetTransport sends postings through rt, e.g. to apply proxy and TLS settings.
+func (p *HTTPPoster) SetTransport(rt http.RoundTripper) {
+	p.client.SetTransport(rt)
+}
+
+// LoadTemplate lee una plantilla de mapeo desde disco; path vacío usa la default.
+func LoadTemplate(path string) (string, error) {
+	if path == "" {
+		return DefaultTemplate, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read erp mapping template: %w", err)
+	}
+	return string(b), nil
+}
+
+func (p *HTTPPoster) Post(ctx context.Context, sale *sales.Sale) error {
+	var body bytes.Buffer
+	if err := p.tmpl.Execute(&body, sale); err != nil {
+		return fmt.Errorf("failed to render erp payload: %w", err)
+	}
+
+	// El ID de la venta permite al ERP descartar reintentos duplicados
+	resp, err := p.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetHeader("Idempotency-Key", sale.ID).
+		SetBody(body.Bytes()).
+		Post(p.endpoint)
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return fmt.Errorf("erp returned status %d", resp.StatusCode())
+	}
+	return nil
+}
+
+func toJSON(v any) (string, error) {
+	b, err := jsonenc.Marshal(v)
+	return string(b), err
+}
+
+// Publisher implements sales.Notifier, posting every sale that becomes
+// approved on a bounded dispatch pool and recording the outcome on the sale.
+type Publisher struct {
+	poster   Poster
+	pool     *dispatch.Pool
+	recorder StatusRecorder
+	logger   *zap.Logger
+}
+
+// NewPublisher creates a publisher that posts through poster on pool.
+func NewPublisher(poster Poster, pool *dispatch.Pool, recorder StatusRecorder, logger *zap.Logger) *Publisher {
+	if logger == nil {
+		logger, _ = zap.NewProduction()
+	}
+
+	return &Publisher{
+		poster:   poster,
+		pool:     pool,
+		recorder: recorder,
+		logger:   logger,
+	}
+}
+
+// Notify queues approved sales that have not been posted yet.
+func (p *Publisher) Notify(eventType string, sale *sales.Sale) {
+	if eventType != sales.EventSaleCreated && eventType != sales.EventSaleStatusChanged {
+		return
+	}
+	if sale.Status != sales.StatusApproved || sale.ERPPosting != "" {
+		return
+	}
+
+	err := p.pool.Submit(func(ctx context.Context) {
+		p.record(sale.ID, sales.ERPPostingPending)
+		if err := p.poster.Post(ctx, sale); err != nil {
+			p.logger.Error("erp posting failed", zap.String("sale_id", sale.ID), zap.Error(err))
+			p.record(sale.ID, sales.ERPPostingFailed)
+			return
+		}
+		p.record(sale.ID, sales.ERPPostingPosted)
+	})
+	if err != nil {
+		p.logger.Warn("erp posting dropped", zap.String("sale_id", sale.ID), zap.Error(err))
+		p.record(sale.ID, sales.ERPPostingFailed)
+	}
+}
+
+func (p *Publisher) record(saleID, status string) {
+	if err := p.recorder.SetERPPostingStatus(saleID, status); err != nil {
+		p.logger.Error("failed to record erp posting status", zap.String("sale_id", saleID), zap.String("status", status), zap.Error(err))
+	}
+}
@@ -0,0 +1,60 @@
+package erp
+
+import (
+	"api_sales/internal/dispatch"
+	"api_sales/internal/sales"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeRecorder struct {
+	mu       sync.Mutex
+	statuses []string
+}
+
+func (f *fakeRecorder) SetERPPostingStatus(saleID, status string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.statuses = append(f.statuses, status)
+	return nil
+}
+
+// TestPublisher_PostsApprovedSalesWithTemplate verifica el mapeo y el estado registrado.
+func TestPublisher_PostsApprovedSalesWithTemplate(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	poster, err := NewHTTPPoster(server.URL, `{"doc":{{json .ID}},"total":{{.Amount}}}`)
+	if err != nil {
+		t.Fatalf("NewHTTPPoster returned error: %v", err)
+	}
+	pool := dispatch.NewPool("test-erp", 1, 10, nil)
+	recorder := &fakeRecorder{}
+	publisher := NewPublisher(poster, pool, recorder, nil)
+
+	publisher.Notify(sales.EventSaleStatusChanged, &sales.Sale{ID: "s1", Amount: 12.5, Status: sales.StatusApproved})
+	publisher.Notify(sales.EventSaleStatusChanged, &sales.Sale{ID: "s2", Amount: 10, Status: sales.StatusRejected})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := pool.Close(ctx); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	if body != `{"doc":"s1","total":12.5}` {
+		t.Errorf("unexpected erp payload: %s", body)
+	}
+	if len(recorder.statuses) != 2 || recorder.statuses[0] != sales.ERPPostingPending || recorder.statuses[1] != sales.ERPPostingPosted {
+		t.Errorf("unexpected recorded statuses: %v", recorder.statuses)
+	}
+}
@@ -0,0 +1,68 @@
+// Package httpclient builds the transport shared by every outbound HTTP
+// client, so proxy and TLS settings apply consistently.
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// Options configures egress for outbound clients.
+type Options struct {
+	// ProxyURL overrides the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment
+	// variables, which are honored when it is empty.
+	ProxyURL string
+	// RootCAFiles are PEM files trusted in addition to the system pool.
+	RootCAFiles []string
+	// ClientCertFile and ClientKeyFile enable mutual TLS.
+	ClientCertFile string
+	ClientKeyFile  string
+}
+
+// NewTransport returns a transport configured from opts.
+func NewTransport(opts Options) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+
+	if opts.ProxyURL != "" {
+		proxyURL, err := url.Parse(opts.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if len(opts.RootCAFiles) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		for _, path := range opts.RootCAFiles {
+			pem, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read root CA %s: %w", path, err)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates found in %s", path)
+			}
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if opts.ClientCertFile != "" || opts.ClientKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.ClientCertFile, opts.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
@@ -0,0 +1,50 @@
+package httpclient
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestNewTransport_TrustsCustomRootCA verifica que se confía en una CA privada.
+func TestNewTransport_TrustsCustomRootCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, certPEM, 0o600); err != nil {
+		t.Fatalf("WriteFile returned error: %v", err)
+	}
+
+	transport, err := NewTransport(Options{RootCAFiles: []string{caFile}})
+	if err != nil {
+		t.Fatalf("NewTransport returned error: %v", err)
+	}
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("expected the private CA to be trusted, got %v", err)
+	}
+	resp.Body.Close()
+}
+
+// TestNewTransport_ProxyOverride verifica que el proxy configurado reemplaza al del entorno.
+func TestNewTransport_ProxyOverride(t *testing.T) {
+	transport, err := NewTransport(Options{ProxyURL: "http://proxy.internal:3128"})
+	if err != nil {
+		t.Fatalf("NewTransport returned error: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://erp.example.com", nil)
+	proxy, err := transport.Proxy(req)
+	if err != nil || proxy == nil || proxy.Host != "proxy.internal:3128" {
+		t.Errorf("unexpected proxy: %v (%v)", proxy, err)
+	}
+
+	if _, err := NewTransport(Options{RootCAFiles: []string{"/does/not/exist.pem"}}); err == nil {
+		t.Error("expected an error for a missing root CA file")
+	}
+}
@@ -0,0 +1,80 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// purgeInterval espacia las pasadas que eliminan los registros vencidos, para
+// no recorrer el mapa en cada reserva.
+const purgeInterval = time.Minute
+
+// MemoryStore keeps records in process memory. It is only correct for a single
+// API instance.
+type MemoryStore struct {
+	mu       sync.Mutex
+	records  map[string]memoryRecord
+	now      func() time.Time
+	purgedAt time.Time
+}
+
+type memoryRecord struct {
+	rec       Record
+	expiresAt time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: map[string]memoryRecord{},
+		now:     time.Now,
+	}
+}
+
+func (m *MemoryStore) Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*Record, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.purgeExpired(now)
+	if current, ok := m.records[key]; ok && now.Before(current.expiresAt) {
+		rec := current.rec
+		return &rec, false, nil
+	}
+
+	m.records[key] = memoryRecord{
+		rec:       Record{Fingerprint: fingerprint},
+		expiresAt: now.Add(ttl),
+	}
+	return nil, true, nil
+}
+
+func (m *MemoryStore) Complete(ctx context.Context, key string, rec Record, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rec.Completed = true
+	m.records[key] = memoryRecord{rec: rec, expiresAt: m.now().Add(ttl)}
+	return nil
+}
+
+func (m *MemoryStore) Release(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.records, key)
+	return nil
+}
+
+// purgeExpired evita que el mapa crezca con registros vencidos.
+func (m *MemoryStore) purgeExpired(now time.Time) {
+	if now.Sub(m.purgedAt) < purgeInterval {
+		return
+	}
+	m.purgedAt = now
+	for key, r := range m.records {
+		if !now.Before(r.expiresAt) {
+			delete(m.records, key)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestMemoryStore_PurgesExpiredRecords verifica que las claves vencidas no
+// queden en el mapa.
+func TestMemoryStore_PurgesExpiredRecords(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, ok, err := store.Reserve(ctx, "k1", "f1", time.Second); err != nil || !ok {
+		t.Fatalf("expected k1 reserved, got ok=%v err=%v", ok, err)
+	}
+	if err := store.Complete(ctx, "k1", Record{Fingerprint: "f1", StatusCode: 201}, time.Second); err != nil {
+		t.Fatalf("Complete returned error: %v", err)
+	}
+	if rec, ok, _ := store.Reserve(ctx, "k1", "f1", time.Second); ok || rec == nil || !rec.Completed {
+		t.Errorf("expected the completed record replayed, got %+v (ok=%v)", rec, ok)
+	}
+
+	now = now.Add(time.Hour)
+	if _, ok, _ := store.Reserve(ctx, "k2", "f2", time.Second); !ok {
+		t.Fatal("expected k2 reserved")
+	}
+	if _, found := store.records["k1"]; found {
+		t.Error("expected the expired k1 purged from the map")
+	}
+	if len(store.records) != 1 {
+		t.Errorf("expected only k2 left, got %d records", len(store.records))
+	}
+}
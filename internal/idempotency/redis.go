@@ -0,0 +1,73 @@
+package idempotency
+
+import (
+	"api_sales/internal/jsonenc"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore shares idempotency records between API instances.
+type RedisStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+func NewRedisStore(client redis.UniversalClient) *RedisStore {
+	return &RedisStore{
+		client: client,
+		prefix: "api_sales:idempotency:",
+	}
+}
+
+func (r *RedisStore) Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*Record, bool, error) {
+	data, err := jsonenc.Marshal(Record{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, false, err
+	}
+
+	ok, err := r.client.SetNX(ctx, r.prefix+key, data, ttl).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("redis idempotency reserve: %w", err)
+	}
+	if ok {
+		return nil, true, nil
+	}
+
+	raw, err := r.client.Get(ctx, r.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// La clave expiró entre SETNX y GET: se reintenta la reserva.
+		return r.Reserve(ctx, key, fingerprint, ttl)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("redis idempotency get: %w", err)
+	}
+
+	var rec Record
+	if err := jsonenc.Unmarshal(raw, &rec); err != nil {
+		return nil, false, fmt.Errorf("redis idempotency decode: %w", err)
+	}
+	return &rec, false, nil
+}
+
+func (r *RedisStore) Complete(ctx context.Context, key string, rec Record, ttl time.Duration) error {
+	rec.Completed = true
+	data, err := jsonenc.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if err := r.client.Set(ctx, r.prefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("redis idempotency complete: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisStore) Release(ctx context.Context, key string) error {
+	if err := r.client.Del(ctx, r.prefix+key).Err(); err != nil {
+		return fmt.Errorf("redis idempotency release: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+// Package idempotency stores the outcome of requests sent with an
+// Idempotency-Key so retries replay the original response instead of being
+// executed again. Stores are pluggable so several API instances behind a load
+// balancer can share them.
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// Record is the state kept for an idempotency key. While the first request is
+// still running Completed is false.
+type Record struct {
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// Store persists idempotency records.
+type Store interface {
+	// Reserve atomically claims key for a new request. When the key already
+	// exists it returns the stored record and reserved is false.
+	Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (existing *Record, reserved bool, err error)
+	// Complete stores the final response for a reserved key.
+	Complete(ctx context.Context, key string, rec Record, ttl time.Duration) error
+	// Release drops a reservation so the request can be retried.
+	Release(ctx context.Context, key string) error
+}
@@ -0,0 +1,56 @@
+package jsonenc
+
+import (
+	"api_sales/internal/sales"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+// searchPayload arma una respuesta de búsqueda como la de GET /sales.
+func searchPayload(n int) map[string]interface{} {
+	results := make([]*sales.Sale, 0, n)
+	metadata := sales.SalesMetadata{}
+	now := time.Now()
+	for i := 0; i < n; i++ {
+		results = append(results, &sales.Sale{
+			ID:        fmt.Sprintf("9b2f5c7e-0000-4000-8000-%012d", i),
+			UserID:    fmt.Sprintf("user-%d", i%50),
+			Amount:    float64(i) + 0.5,
+			Status:    sales.StatusApproved,
+			CreatedAt: now,
+			UpdatedAt: now,
+			Version:   1,
+		})
+		metadata.Quantity++
+		metadata.Approved++
+		metadata.TotalAmount += float64(i) + 0.5
+	}
+	return map[string]interface{}{"results": results, "metadata": metadata}
+}
+
+func BenchmarkMarshalSearchResponse(b *testing.B) {
+	payload := searchPayload(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := Marshal(payload); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodeSearchResponse(b *testing.B) {
+	payload := searchPayload(1000)
+	enc := NewEncoder(io.Discard)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := enc.Encode(payload); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+// Package jsonenc selects the JSON implementation used on hot paths.
+//
+// By default it wraps encoding/json. Building with -tags=jsoniter switches
+// Marshal and Unmarshal to json-iterator, which is also what Gin uses for
+// ctx.JSON under the same tag, so the search endpoint and the stores that
+// encode sales change together:
+//
+//	go build -tags=jsoniter .
+//	go test -bench=. -benchmem ./internal/jsonenc/
+//	go test -bench=. -benchmem -tags=jsoniter ./internal/jsonenc/
+//
+// json-iterator marshals faster but builds every value in a buffer of its
+// own, while encoding/json's Encoder reuses one across calls. The streaming
+// NewEncoder and NewDecoder, used by the NDJSON exports, stay on
+// encoding/json in both builds.
+package jsonenc
+
+import "encoding/json"
+
+var (
+	NewEncoder = json.NewEncoder
+	NewDecoder = json.NewDecoder
+)
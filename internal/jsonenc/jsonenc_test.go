@@ -0,0 +1,29 @@
+package jsonenc
+
+import (
+	"api_sales/internal/sales"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestMarshal_MatchesEncodingJSON verifica que ambas implementaciones producen el mismo formato.
+func TestMarshal_MatchesEncodingJSON(t *testing.T) {
+	payload := searchPayload(3)
+	payload["at"] = time.Date(2024, 5, 1, 10, 30, 0, 123456789, time.FixedZone("ART", -3*3600))
+	payload["results"].([]*sales.Sale)[1].Metadata = map[string]string{"source": "<web & app>", "campaign": "q2\u2028"}
+	payload["empty"] = map[string]string{}
+	payload["none"] = map[string]string(nil)
+
+	want, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("json.Marshal returned error: %v", err)
+	}
+	got, err := Marshal(payload)
+	if err != nil {
+		t.Fatalf("Marshal (%s) returned error: %v", Name, err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s output differs from encoding/json:\n got: %s\nwant: %s", Name, got, want)
+	}
+}
@@ -0,0 +1,64 @@
+//go:build jsoniter
+
+package jsonenc
+
+import (
+	"slices"
+	"time"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Name identifies the implementation compiled in.
+const Name = "jsoniter"
+
+var (
+	api = jsoniter.ConfigCompatibleWithStandardLibrary
+
+	Marshal   = api.Marshal
+	Unmarshal = api.Unmarshal
+)
+
+func init() {
+	// time.Time pasa por MarshalJSON y aloca en cada campo; se escribe
+	// directamente en el buffer del stream con el mismo formato RFC3339Nano.
+	jsoniter.RegisterTypeEncoderFunc("time.Time", encodeTime, func(unsafe.Pointer) bool { return false })
+	// El encoder genérico de mapas aloca un iterador hasta para decidir si un
+	// mapa vacío se omite, como la metadata de casi todas las ventas.
+	jsoniter.RegisterTypeEncoderFunc("map[string]string", encodeStringMap, func(ptr unsafe.Pointer) bool {
+		return len(*(*map[string]string)(ptr)) == 0
+	})
+}
+
+func encodeTime(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	t := *(*time.Time)(ptr)
+	buf := append(stream.Buffer(), '"')
+	buf = t.AppendFormat(buf, time.RFC3339Nano)
+	stream.SetBuffer(append(buf, '"'))
+}
+
+// encodeStringMap escribe las claves ordenadas y con el escape HTML de
+// encoding/json.
+func encodeStringMap(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	m := *(*map[string]string)(ptr)
+	if m == nil {
+		stream.WriteNil()
+		return
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	stream.WriteObjectStart()
+	for i, k := range keys {
+		if i > 0 {
+			stream.WriteMore()
+		}
+		stream.WriteStringWithHTMLEscaped(k)
+		stream.WriteRaw(":")
+		stream.WriteStringWithHTMLEscaped(m[k])
+	}
+	stream.WriteObjectEnd()
+}
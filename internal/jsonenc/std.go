@@ -0,0 +1,13 @@
+//go:build !jsoniter
+
+package jsonenc
+
+import "encoding/json"
+
+// Name identifies the implementation compiled in.
+const Name = "encoding/json"
+
+var (
+	Marshal   = json.Marshal
+	Unmarshal = json.Unmarshal
+)
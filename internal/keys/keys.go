@@ -0,0 +1,262 @@
+// Package keys manages versioned credentials: webhook signing keys and API
+// keys. Rotating a key keeps the previous one valid for an acceptance window
+// so consumers can switch without downtime.
+package keys
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Error para claves inexistentes
+var ErrKeyNotFound = errors.New("key not found")
+
+// Error para propósitos no soportados
+var ErrInvalidPurpose = errors.New("invalid key purpose")
+
+const (
+	PurposeWebhookSigning = "webhook_signing"
+	PurposeAPI            = "api"
+
+	StatusActive   = "active"
+	StatusRetiring = "retiring"
+	StatusRetired  = "retired"
+)
+
+// DefaultRotationWindow is how long a replaced key is still accepted.
+const DefaultRotationWindow = 24 * time.Hour
+
+// Key is a versioned credential identified by its kid.
+type Key struct {
+	ID        string     `json:"kid"`
+	Purpose   string     `json:"purpose"`
+	Role      string     `json:"role,omitempty"`
+	UserID    string     `json:"user_id,omitempty"`
+	Team      []string   `json:"team,omitempty"`
+	Tenant    string     `json:"tenant,omitempty"`
+	Secret    string     `json:"secret,omitempty"`
+	Status    string     `json:"status"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Manager keeps keys in process memory.
+type Manager struct {
+	mu     sync.Mutex
+	keys   map[string]*Key
+	window time.Duration
+	now    func() time.Time
+}
+
+// NewManager creates a manager that accepts replaced keys for window.
+func NewManager(window time.Duration) *Manager {
+	return &Manager{
+		keys:   map[string]*Key{},
+		window: window,
+		now:    time.Now,
+	}
+}
+
+// Create generates a new key. A new webhook signing key replaces the active
+// one, which keeps signing until the acceptance window ends. API keys are
+// independent; the old one is retired explicitly with Retire.
+func (m *Manager) Create(purpose, role string) (*Key, error) {
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	return m.Add(purpose, role, secret)
+}
+
+// Add registers a key with a known secret, e.g. one bootstrapped from config.
+func (m *Manager) Add(purpose, role, secret string) (*Key, error) {
+	if purpose != PurposeWebhookSigning && purpose != PurposeAPI {
+		return nil, ErrInvalidPurpose
+	}
+	kid, err := randomHex(8)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if purpose == PurposeWebhookSigning {
+		for _, k := range m.keys {
+			if k.Purpose == PurposeWebhookSigning && k.Status == StatusActive {
+				m.retire(k, now.Add(m.window))
+			}
+		}
+	}
+
+	key := &Key{
+		ID:        kid,
+		Purpose:   purpose,
+		Role:      role,
+		Secret:    secret,
+		Status:    StatusActive,
+		CreatedAt: now,
+	}
+	m.keys[kid] = key
+	copied := *key
+	return &copied, nil
+}
+
+// SetOwner links an API key to the user it authenticates and the team
+// members whose data that user may also see.
+func (m *Manager) SetOwner(kid, userID string, team []string) (*Key, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key, ok := m.keys[kid]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	key.UserID = userID
+	key.Team = append([]string(nil), team...)
+	copied := *key
+	return &copied, nil
+}
+
+// SetTenant assigns the tenant whose usage and quotas the key counts against.
+func (m *Manager) SetTenant(kid, tenant string) (*Key, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key, ok := m.keys[kid]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	key.Tenant = tenant
+	copied := *key
+	return &copied, nil
+}
+
+// Retire stops accepting a key once the acceptance window ends, or right away
+// when immediate is set.
+func (m *Manager) Retire(kid string, immediate bool) (*Key, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key, ok := m.keys[kid]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	expiresAt := m.now().Add(m.window)
+	if immediate {
+		expiresAt = m.now()
+	}
+	m.retire(key, expiresAt)
+	return key.public(), nil
+}
+
+func (m *Manager) retire(key *Key, expiresAt time.Time) {
+	if key.ExpiresAt != nil && key.ExpiresAt.Before(expiresAt) {
+		return
+	}
+	key.Status = StatusRetiring
+	key.ExpiresAt = &expiresAt
+}
+
+// List returns the keys of purpose without their secrets, newest first. An
+// empty purpose lists every key.
+func (m *Manager) List(purpose string) []*Key {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	result := make([]*Key, 0, len(m.keys))
+	for _, k := range m.keys {
+		if purpose == "" || k.Purpose == purpose {
+			m.refresh(k, now)
+			result = append(result, k.public())
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) })
+	return result
+}
+
+// Authenticate returns the API key whose secret matches, if it is still
+// accepted.
+func (m *Manager) Authenticate(secret string) (*Key, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	for _, k := range m.keys {
+		if k.Purpose != PurposeAPI || subtle.ConstantTimeCompare([]byte(k.Secret), []byte(secret)) != 1 {
+			continue
+		}
+		if m.refresh(k, now) == StatusRetired {
+			return nil, false
+		}
+		return k.public(), true
+	}
+	return nil, false
+}
+
+// SigningKeys returns the webhook keys currently used to sign, the active one
+// first. During a rotation both the new and the previous key are returned.
+func (m *Manager) SigningKeys() []*Key {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	result := make([]*Key, 0, 2)
+	for _, k := range m.keys {
+		if k.Purpose == PurposeWebhookSigning && m.refresh(k, now) != StatusRetired {
+			copied := *k
+			result = append(result, &copied)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) })
+	return result
+}
+
+// refresh marca como retirada una clave cuya ventana ya venció.
+func (m *Manager) refresh(k *Key, now time.Time) string {
+	if k.ExpiresAt != nil && !now.Before(*k.ExpiresAt) {
+		k.Status = StatusRetired
+	}
+	return k.Status
+}
+
+func (k *Key) public() *Key {
+	copied := *k
+	copied.Secret = ""
+	return &copied
+}
+
+// Sign computes the signature header for body: the timestamp followed by one
+// HMAC-SHA256 per key, e.g. "t=1717171717,kid1=ab12...,kid0=cd34...".
+// Consumers verify the entry of any kid they know over "<t>.<body>".
+func Sign(signing []*Key, at time.Time, body []byte) string {
+	ts := strconv.FormatInt(at.Unix(), 10)
+	parts := []string{"t=" + ts}
+	for _, k := range signing {
+		mac := hmac.New(sha256.New, []byte(k.Secret))
+		mac.Write([]byte(ts + "."))
+		mac.Write(body)
+		parts = append(parts, k.ID+"="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	return strings.Join(parts, ",")
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate key: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
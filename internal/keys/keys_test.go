@@ -0,0 +1,66 @@
+package keys
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestManager_WebhookRotationWindow verifica que la clave anterior firma hasta que vence la ventana.
+func TestManager_WebhookRotationWindow(t *testing.T) {
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	m := NewManager(time.Hour)
+	m.now = func() time.Time { return now }
+
+	first, err := m.Create(PurposeWebhookSigning, "")
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	now = now.Add(time.Minute)
+	second, _ := m.Create(PurposeWebhookSigning, "")
+
+	signing := m.SigningKeys()
+	if len(signing) != 2 || signing[0].ID != second.ID || signing[1].ID != first.ID {
+		t.Fatalf("expected new and previous key during rotation, got %+v", signing)
+	}
+
+	body := []byte(`{"id":"evt"}`)
+	header := Sign(signing, now, body)
+	mac := hmac.New(sha256.New, []byte(first.Secret))
+	mac.Write([]byte(strings.SplitN(header, ",", 2)[0][2:] + "."))
+	mac.Write(body)
+	if !strings.Contains(header, first.ID+"="+hex.EncodeToString(mac.Sum(nil))) {
+		t.Errorf("expected a verifiable signature for the previous key in %q", header)
+	}
+
+	now = now.Add(time.Hour)
+	if signing := m.SigningKeys(); len(signing) != 1 || signing[0].ID != second.ID {
+		t.Errorf("expected only the new key after the window, got %+v", signing)
+	}
+}
+
+// TestManager_APIKeyRetirement verifica la ventana de aceptación de API keys retiradas.
+func TestManager_APIKeyRetirement(t *testing.T) {
+	now := time.Now()
+	m := NewManager(time.Hour)
+	m.now = func() time.Time { return now }
+
+	key, _ := m.Create(PurposeAPI, "admin")
+	if _, err := m.Retire(key.ID, false); err != nil {
+		t.Fatalf("Retire returned error: %v", err)
+	}
+	if got, ok := m.Authenticate(key.Secret); !ok || got.Role != "admin" || got.Secret != "" {
+		t.Errorf("expected retiring key to be accepted without exposing its secret, got %+v/%v", got, ok)
+	}
+
+	now = now.Add(time.Hour)
+	if _, ok := m.Authenticate(key.Secret); ok {
+		t.Error("expected key to be rejected after the window")
+	}
+	if _, err := m.Retire("missing", true); err != ErrKeyNotFound {
+		t.Errorf("expected ErrKeyNotFound, got %v", err)
+	}
+}
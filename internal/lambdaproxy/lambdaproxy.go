@@ -0,0 +1,91 @@
+// Package lambdaproxy serves an http.Handler from AWS Lambda behind an API
+// Gateway REST API with proxy integration.
+package lambdaproxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// Handler adapts h to the API Gateway proxy event, for lambda.Start.
+func Handler(h http.Handler) func(ctx context.Context, event events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	return func(ctx context.Context, event events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		req, err := newRequest(ctx, event)
+		if err != nil {
+			return events.APIGatewayProxyResponse{}, err
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return newResponse(w), nil
+	}
+}
+
+func newRequest(ctx context.Context, event events.APIGatewayProxyRequest) (*http.Request, error) {
+	body := []byte(event.Body)
+	if event.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(event.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode request body: %w", err)
+		}
+		body = decoded
+	}
+
+	query := url.Values{}
+	for k, v := range event.QueryStringParameters {
+		query.Set(k, v)
+	}
+	for k, vs := range event.MultiValueQueryStringParameters {
+		query[k] = vs
+	}
+	target := (&url.URL{Path: event.Path, RawQuery: query.Encode()}).RequestURI()
+
+	req, err := http.NewRequestWithContext(ctx, event.HTTPMethod, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy request: %w", err)
+	}
+	for k, v := range event.Headers {
+		req.Header.Set(k, v)
+	}
+	for k, vs := range event.MultiValueHeaders {
+		req.Header.Del(k)
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Host = req.Header.Get("Host")
+	// Gin toma la IP del cliente de RemoteAddr cuando no confía en proxies
+	if ip := event.RequestContext.Identity.SourceIP; ip != "" {
+		req.RemoteAddr = ip + ":0"
+	}
+	return req, nil
+}
+
+func newResponse(w *httptest.ResponseRecorder) events.APIGatewayProxyResponse {
+	resp := events.APIGatewayProxyResponse{
+		StatusCode:        w.Code,
+		MultiValueHeaders: map[string][]string{},
+	}
+	for k, vs := range w.Header() {
+		resp.MultiValueHeaders[k] = vs
+	}
+
+	// API Gateway solo acepta texto en el body; lo binario va en base64
+	body := w.Body.Bytes()
+	if utf8.Valid(body) && !strings.HasPrefix(w.Header().Get("Content-Type"), "application/octet-stream") {
+		resp.Body = string(body)
+	} else {
+		resp.Body = base64.StdEncoding.EncodeToString(body)
+		resp.IsBase64Encoded = true
+	}
+	return resp
+}
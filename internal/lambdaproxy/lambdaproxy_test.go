@@ -0,0 +1,46 @@
+package lambdaproxy
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// TestHandler_TranslatesProxyEvent verifica la conversión evento -> request ->
+// respuesta de API Gateway.
+func TestHandler_TranslatesProxyEvent(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || r.URL.Path != "/sales" || r.URL.Query().Get("dry_run") != "true" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		if r.Header.Get("Idempotency-Key") != "k1" || string(body) != `{"amount":10}` {
+			t.Errorf("unexpected headers or body: %v %s", r.Header, body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"s1"}`))
+	})
+
+	resp, err := Handler(h)(context.Background(), events.APIGatewayProxyRequest{
+		HTTPMethod:            http.MethodPost,
+		Path:                  "/sales",
+		Headers:               map[string]string{"Idempotency-Key": "k1"},
+		QueryStringParameters: map[string]string{"dry_run": "true"},
+		Body:                  base64.StdEncoding.EncodeToString([]byte(`{"amount":10}`)),
+		IsBase64Encoded:       true,
+	})
+	if err != nil {
+		t.Fatalf("Handler returned error: %v", err)
+	}
+	if resp.StatusCode != http.StatusCreated || resp.Body != `{"id":"s1"}` || resp.IsBase64Encoded {
+		t.Errorf("unexpected response %+v", resp)
+	}
+	if got := resp.MultiValueHeaders["Content-Type"]; len(got) != 1 || got[0] != "application/json" {
+		t.Errorf("expected Content-Type header, got %v", got)
+	}
+}
@@ -0,0 +1,69 @@
+package lock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LocalLocker is an in-process Locker for single-instance deployments and tests.
+type LocalLocker struct {
+	mu    sync.Mutex
+	locks map[string]localLock
+}
+
+type localLock struct {
+	token     string
+	expiresAt time.Time
+}
+
+func NewLocalLocker() *LocalLocker {
+	return &LocalLocker{
+		locks: map[string]localLock{},
+	}
+}
+
+func (l *LocalLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (Lease, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if current, ok := l.locks[key]; ok && now.Before(current.expiresAt) {
+		return nil, false, nil
+	}
+
+	token := uuid.NewString()
+	l.locks[key] = localLock{token: token, expiresAt: now.Add(ttl)}
+	return &localLease{locker: l, key: key, token: token}, true, nil
+}
+
+type localLease struct {
+	locker *LocalLocker
+	key    string
+	token  string
+}
+
+// Renew extiende el lock si sigue siendo nuestro y no venció.
+func (l *localLease) Renew(ctx context.Context, ttl time.Duration) error {
+	l.locker.mu.Lock()
+	defer l.locker.mu.Unlock()
+
+	now := time.Now()
+	current, ok := l.locker.locks[l.key]
+	if !ok || current.token != l.token || !now.Before(current.expiresAt) {
+		return ErrLost
+	}
+	l.locker.locks[l.key] = localLock{token: l.token, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+func (l *localLease) Unlock() error {
+	l.locker.mu.Lock()
+	defer l.locker.mu.Unlock()
+	if current, ok := l.locker.locks[l.key]; ok && current.token == l.token {
+		delete(l.locker.locks, l.key)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Package lock provides distributed locks so work that must run once across
+// all replicas (scheduler passes, background jobs) isn't executed twice.
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotAcquired is returned by Run when another holder owns the lock.
+var ErrNotAcquired = errors.New("lock not acquired")
+
+// ErrLost is returned by Lease.Renew, and by Run, when the lock expired and
+// may have been taken by someone else.
+var ErrLost = errors.New("lock lost")
+
+// Lease is a lock obtained with TryLock. It expires ttl after it was acquired
+// or last renewed.
+type Lease interface {
+	Renew(ctx context.Context, ttl time.Duration) error
+	Unlock() error
+}
+
+// Locker acquires named, expiring locks. TryLock never blocks: ok is false when
+// the lock is currently held elsewhere.
+type Locker interface {
+	TryLock(ctx context.Context, key string, ttl time.Duration) (lease Lease, ok bool, err error)
+}
+
+// Run executes fn while holding key, renewing the lease every third of ttl so
+// a run longer than ttl keeps it. It returns ErrNotAcquired without running fn
+// when the lock is held by someone else. If a renewal fails, the context of fn
+// is cancelled and Run returns ErrLost.
+func Run(ctx context.Context, l Locker, key string, ttl time.Duration, fn func(ctx context.Context) error) error {
+	lease, ok, err := l.TryLock(ctx, key, ttl)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNotAcquired
+	}
+	defer lease.Unlock()
+
+	every := ttl / 3
+	if every <= 0 {
+		return fn(ctx)
+	}
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		ticker := time.NewTicker(every)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-runCtx.Done():
+				return
+			case <-ticker.C:
+				if err := lease.Renew(runCtx, ttl); err != nil {
+					// Sin renovar no se sabe si el lock sigue siendo nuestro
+					if !errors.Is(err, ErrLost) {
+						err = fmt.Errorf("%w: %v", ErrLost, err)
+					}
+					cancel(fmt.Errorf("renew lock %s: %w", key, err))
+					return
+				}
+			}
+		}
+	}()
+
+	err = fn(runCtx)
+	cancel(nil)
+	<-renewed
+	if cause := context.Cause(runCtx); errors.Is(cause, ErrLost) {
+		return cause
+	}
+	return err
+}
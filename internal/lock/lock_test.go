@@ -0,0 +1,156 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// TestLocalLocker_ExclusiveUntilUnlock verifica la exclusión mutua del lock local.
+func TestLocalLocker_ExclusiveUntilUnlock(t *testing.T) {
+	l := NewLocalLocker()
+	ctx := context.Background()
+
+	lease, ok, err := l.TryLock(ctx, "job", time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("expected first TryLock to succeed, got ok=%v err=%v", ok, err)
+	}
+
+	if err := Run(ctx, l, "job", time.Minute, func(context.Context) error { return nil }); err != ErrNotAcquired {
+		t.Errorf("expected ErrNotAcquired while held, got %v", err)
+	}
+
+	lease.Unlock()
+	ran := false
+	if err := Run(ctx, l, "job", time.Minute, func(context.Context) error { ran = true; return nil }); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if !ran {
+		t.Error("expected fn to run after unlock")
+	}
+}
+
+// TestLocalLocker_Expires verifica que un lock vencido puede tomarse de nuevo.
+func TestLocalLocker_Expires(t *testing.T) {
+	l := NewLocalLocker()
+	ctx := context.Background()
+
+	if _, ok, _ := l.TryLock(ctx, "job", time.Millisecond); !ok {
+		t.Fatal("expected first TryLock to succeed")
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, ok, _ := l.TryLock(ctx, "job", time.Minute); !ok {
+		t.Error("expected expired lock to be acquirable")
+	}
+}
+
+// TestRun_RenewsTheLeaseWhileFnRuns verifica que una corrida más larga que el
+// ttl conserve el lock.
+func TestRun_RenewsTheLeaseWhileFnRuns(t *testing.T) {
+	l := NewLocalLocker()
+	ctx := context.Background()
+
+	err := Run(ctx, l, "job", 30*time.Millisecond, func(ctx context.Context) error {
+		time.Sleep(100 * time.Millisecond)
+		if _, ok, _ := l.TryLock(ctx, "job", time.Minute); ok {
+			t.Error("expected the lock still held past its ttl")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if _, ok, _ := l.TryLock(ctx, "job", time.Minute); !ok {
+		t.Error("expected the lock released after Run")
+	}
+}
+
+// TestRun_CancelsFnWhenTheLeaseIsLost verifica que perder el lock cancele fn.
+func TestRun_CancelsFnWhenTheLeaseIsLost(t *testing.T) {
+	l := NewLocalLocker()
+
+	err := Run(context.Background(), l, "job", 30*time.Millisecond, func(ctx context.Context) error {
+		// Otro holder se queda con la clave
+		l.mu.Lock()
+		l.locks["job"] = localLock{token: "other", expiresAt: time.Now().Add(time.Minute)}
+		l.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+			t.Error("expected fn cancelled once the lease was lost")
+			return nil
+		}
+	})
+	if !errors.Is(err, ErrLost) {
+		t.Errorf("expected ErrLost, got %v", err)
+	}
+}
+
+// TestRedisLocker_RenewsOnlyItsOwnLock verifica la renovación con el token.
+func TestRedisLocker_RenewsOnlyItsOwnLock(t *testing.T) {
+	server := miniredis.RunT(t)
+	l := NewRedisLocker(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+	ctx := context.Background()
+
+	lease, ok, err := l.TryLock(ctx, "job", time.Second)
+	if err != nil || !ok {
+		t.Fatalf("expected TryLock to succeed, got ok=%v err=%v", ok, err)
+	}
+	if err := lease.Renew(ctx, time.Minute); err != nil {
+		t.Fatalf("Renew returned error: %v", err)
+	}
+	server.FastForward(30 * time.Second)
+	if _, ok, _ := l.TryLock(ctx, "job", time.Second); ok {
+		t.Error("expected the renewed lock still held")
+	}
+
+	server.FastForward(time.Minute)
+	if other, ok, _ := l.TryLock(ctx, "job", time.Minute); !ok || other == nil {
+		t.Fatal("expected the expired lock taken by another holder")
+	}
+	if err := lease.Renew(ctx, time.Minute); !errors.Is(err, ErrLost) {
+		t.Errorf("expected ErrLost renewing a lock taken by another holder, got %v", err)
+	}
+}
+
+// TestPostgresLocker_ReleasesAfterTTL verifica que el advisory lock se suelte
+// si nadie lo renueva.
+func TestPostgresLocker_ReleasesAfterTTL(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer db.Close()
+	l := NewPostgresLocker(db)
+	ctx := context.Background()
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").WillReturnRows(sqlmock.NewRows([]string{"ok"}).AddRow(true))
+	mock.ExpectPing()
+	mock.ExpectExec("SELECT pg_advisory_unlock").WillReturnResult(sqlmock.NewResult(0, 1))
+	lease, ok, err := l.TryLock(ctx, "job", 50*time.Millisecond)
+	if err != nil || !ok {
+		t.Fatalf("expected TryLock to succeed, got ok=%v err=%v", ok, err)
+	}
+	if err := lease.Renew(ctx, 50*time.Millisecond); err != nil {
+		t.Fatalf("Renew returned error: %v", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for mock.ExpectationsWereMet() != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the lock released once its ttl passed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := lease.Renew(ctx, time.Minute); !errors.Is(err, ErrLost) {
+		t.Errorf("expected ErrLost renewing an expired lock, got %v", err)
+	}
+	if err := lease.Unlock(); err != nil {
+		t.Errorf("expected Unlock after expiry to be a no-op, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package lock
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// PostgresLocker implements Locker with session-level advisory locks held on a
+// dedicated connection. Postgres releases the lock if that connection drops;
+// otherwise the locker releases it once ttl passes without a renewal, so it
+// expires like the other lockers'.
+type PostgresLocker struct {
+	db *sql.DB
+}
+
+func NewPostgresLocker(db *sql.DB) *PostgresLocker {
+	return &PostgresLocker{db: db}
+}
+
+func (p *PostgresLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (Lease, bool, error) {
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("postgres lock %s: %w", key, err)
+	}
+
+	id := advisoryKey(key)
+	var ok bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", id).Scan(&ok); err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("postgres lock %s: %w", key, err)
+	}
+	if !ok {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	lease := &postgresLease{conn: conn, id: id, key: key, expiresAt: time.Now().Add(ttl)}
+	lease.timer = time.AfterFunc(ttl, lease.expire)
+	return lease, true, nil
+}
+
+type postgresLease struct {
+	conn *sql.Conn
+	id   int64
+	key  string
+
+	mu        sync.Mutex
+	timer     *time.Timer
+	expiresAt time.Time
+	released  bool
+}
+
+// Renew comprueba que la sesión que tiene el lock siga viva y posterga el
+// vencimiento.
+func (l *postgresLease) Renew(ctx context.Context, ttl time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.released {
+		return ErrLost
+	}
+	if err := l.conn.PingContext(ctx); err != nil {
+		return fmt.Errorf("%w: postgres lock %s: %v", ErrLost, l.key, err)
+	}
+	l.expiresAt = time.Now().Add(ttl)
+	l.timer.Reset(ttl)
+	return nil
+}
+
+func (l *postgresLease) Unlock() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.timer.Stop()
+	if l.released {
+		return nil
+	}
+	return l.release()
+}
+
+// expire libera el lock vencido; ignora los disparos de un timer que una
+// renovación ya postergó.
+func (l *postgresLease) expire() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.released || time.Now().Before(l.expiresAt) {
+		return
+	}
+	l.release()
+}
+
+// release suelta el advisory lock y devuelve la conexión al pool. Si el
+// unlock falla, la conexión se descarta para que Postgres cierre la sesión y
+// el lock con ella.
+func (l *postgresLease) release() error {
+	l.released = true
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.id)
+	if err != nil {
+		l.conn.Raw(func(any) error { return driver.ErrBadConn })
+	}
+	l.conn.Close()
+	return err
+}
+
+// advisoryKey convierte el nombre del lock en la clave bigint que espera Postgres.
+func advisoryKey(key string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,77 @@
+package lock
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// releaseScript borra la clave solo si el token sigue siendo el nuestro.
+var releaseScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0
+`)
+
+// renewScript extiende el vencimiento solo si el token sigue siendo el nuestro.
+var renewScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("pexpire", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// RedisLocker implements Locker with SET NX PX, so the lock expires on its own
+// if the holder dies before releasing it.
+type RedisLocker struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+func NewRedisLocker(client redis.UniversalClient) *RedisLocker {
+	return &RedisLocker{
+		client: client,
+		prefix: "api_sales:lock:",
+	}
+}
+
+func (r *RedisLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (Lease, bool, error) {
+	k := r.prefix + key
+	token := uuid.NewString()
+
+	ok, err := r.client.SetNX(ctx, k, token, ttl).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("redis lock %s: %w", key, err)
+	}
+	if !ok {
+		return nil, false, nil
+	}
+	return &redisLease{client: r.client, key: k, token: token}, true, nil
+}
+
+type redisLease struct {
+	client redis.UniversalClient
+	key    string
+	token  string
+}
+
+func (l *redisLease) Renew(ctx context.Context, ttl time.Duration) error {
+	renewed, err := renewScript.Run(ctx, l.client, []string{l.key}, l.token, ttl.Milliseconds()).Int()
+	if err != nil {
+		return fmt.Errorf("redis renew lock %s: %w", l.key, err)
+	}
+	if renewed == 0 {
+		return ErrLost
+	}
+	return nil
+}
+
+func (l *redisLease) Unlock() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return releaseScript.Run(ctx, l.client, []string{l.key}, l.token).Err()
+}
@@ -0,0 +1,122 @@
+// Package maintenance holds the switch that puts the API into read-only or
+// full maintenance mode, e.g. while the storage backend is migrated. The state
+// is kept in a pluggable store so every API instance sees the same mode.
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Mode is the maintenance mode of the API.
+type Mode string
+
+const (
+	// ModeOff serves every request.
+	ModeOff Mode = "off"
+	// ModeReadOnly rejects writes and keeps serving reads.
+	ModeReadOnly Mode = "read_only"
+	// ModeFull rejects every request except the admin endpoints.
+	ModeFull Mode = "full"
+)
+
+// Error para modos desconocidos
+var ErrInvalidMode = errors.New("invalid maintenance mode: expected off, read_only or full")
+
+// ParseMode validates v as a Mode; empty means ModeOff.
+func ParseMode(v string) (Mode, error) {
+	switch Mode(v) {
+	case "", ModeOff:
+		return ModeOff, nil
+	case ModeReadOnly, ModeFull:
+		return Mode(v), nil
+	default:
+		return "", ErrInvalidMode
+	}
+}
+
+// State is the persisted maintenance state. RetryAfter is sent in seconds on
+// rejected requests.
+type State struct {
+	Mode       Mode      `json:"mode"`
+	Reason     string    `json:"reason,omitempty"`
+	RetryAfter int       `json:"retry_after_seconds"`
+	UpdatedBy  string    `json:"updated_by,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at,omitempty"`
+}
+
+// Store persists the maintenance state. Load returns ok=false when no state
+// has been saved yet.
+type Store interface {
+	Load(ctx context.Context) (State, bool, error)
+	Save(ctx context.Context, state State) error
+}
+
+// Switch reads the mode from a Store, caching it for refresh so the request
+// path doesn't hit the store on every call.
+type Switch struct {
+	store   Store
+	initial State
+	refresh time.Duration
+
+	mu       sync.Mutex
+	current  State
+	loadedAt time.Time
+}
+
+// NewSwitch creates a switch over store. initial applies until a state is
+// saved, e.g. the mode configured at startup.
+func NewSwitch(store Store, initial State, refresh time.Duration) *Switch {
+	if initial.Mode == "" {
+		initial.Mode = ModeOff
+	}
+	return &Switch{store: store, initial: initial, refresh: refresh}
+}
+
+// State returns the current maintenance state. If the store fails the last
+// known state is kept.
+func (s *Switch) State(ctx context.Context) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.loadedAt.IsZero() && time.Since(s.loadedAt) < s.refresh {
+		return s.current, nil
+	}
+	state, ok, err := s.store.Load(ctx)
+	if err != nil {
+		if s.loadedAt.IsZero() {
+			return s.initial, err
+		}
+		return s.current, err
+	}
+	if !ok {
+		state = s.initial
+	}
+	s.current = state
+	s.loadedAt = time.Now()
+	return state, nil
+}
+
+// Set saves state and applies it immediately on this instance.
+func (s *Switch) Set(ctx context.Context, state State) (State, error) {
+	mode, err := ParseMode(string(state.Mode))
+	if err != nil {
+		return State{}, err
+	}
+	state.Mode = mode
+	if state.RetryAfter <= 0 {
+		state.RetryAfter = s.initial.RetryAfter
+	}
+	state.UpdatedAt = time.Now().UTC()
+	if err := s.store.Save(ctx, state); err != nil {
+		return State{}, err
+	}
+
+	s.mu.Lock()
+	s.current = state
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+	return state, nil
+}
@@ -0,0 +1,37 @@
+package maintenance
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestSwitch_PersistsMode verifica que el modo guardado se comparta entre
+// instancias que usan el mismo store.
+func TestSwitch_PersistsMode(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	initial := State{Mode: ModeOff, RetryAfter: 300}
+	sw := NewSwitch(store, initial, time.Hour)
+
+	if state, err := sw.State(ctx); err != nil || state.Mode != ModeOff {
+		t.Fatalf("expected initial mode off, got %+v err=%v", state, err)
+	}
+
+	if _, err := sw.Set(ctx, State{Mode: "paused"}); err != ErrInvalidMode {
+		t.Errorf("expected ErrInvalidMode, got %v", err)
+	}
+	state, err := sw.Set(ctx, State{Mode: ModeReadOnly, Reason: "migration", UpdatedBy: "admin1"})
+	if err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+	if state.RetryAfter != 300 {
+		t.Errorf("expected default retry after 300, got %d", state.RetryAfter)
+	}
+
+	// Otra instancia lee el estado persistido en lugar del inicial
+	other := NewSwitch(store, initial, time.Hour)
+	if state, err := other.State(ctx); err != nil || state.Mode != ModeReadOnly || state.Reason != "migration" {
+		t.Errorf("expected persisted read_only state, got %+v err=%v", state, err)
+	}
+}
@@ -0,0 +1,35 @@
+package maintenance
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryStore keeps the state in process memory. It is only correct for a
+// single API instance and is lost on restart.
+type MemoryStore struct {
+	mu    sync.Mutex
+	state *State
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (m *MemoryStore) Load(ctx context.Context) (State, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.state == nil {
+		return State{}, false, nil
+	}
+	return *m.state, true, nil
+}
+
+func (m *MemoryStore) Save(ctx context.Context, state State) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.state = &state
+	return nil
+}
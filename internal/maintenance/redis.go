@@ -0,0 +1,51 @@
+package maintenance
+
+import (
+	"api_sales/internal/jsonenc"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore shares the state between API instances and keeps it across
+// restarts.
+type RedisStore struct {
+	client redis.UniversalClient
+	key    string
+}
+
+func NewRedisStore(client redis.UniversalClient) *RedisStore {
+	return &RedisStore{
+		client: client,
+		key:    "api_sales:maintenance",
+	}
+}
+
+func (r *RedisStore) Load(ctx context.Context) (State, bool, error) {
+	raw, err := r.client.Get(ctx, r.key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return State{}, false, nil
+	}
+	if err != nil {
+		return State{}, false, fmt.Errorf("redis maintenance get: %w", err)
+	}
+
+	var state State
+	if err := jsonenc.Unmarshal(raw, &state); err != nil {
+		return State{}, false, fmt.Errorf("redis maintenance decode: %w", err)
+	}
+	return state, true, nil
+}
+
+func (r *RedisStore) Save(ctx context.Context, state State) error {
+	data, err := jsonenc.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err := r.client.Set(ctx, r.key, data, 0).Err(); err != nil {
+		return fmt.Errorf("redis maintenance save: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+// Package migrate applies versioned SQL migrations to a database. Each
+// migration is a file named NNNN_description.sql; the applied versions are
+// recorded in a tracking table, so every migration runs once per database and
+// in version order.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported dialects.
+const (
+	DialectMySQL  = "mysql"
+	DialectSQLite = "sqlite"
+)
+
+// lockTimeout es cuánto espera una instancia a que otra termine de migrar.
+const lockTimeout = 60 * time.Second
+
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.sql$`)
+
+// Migration is one versioned schema change.
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// Load reads the migrations in the top directory of fsys, in version order.
+// Every {{key}} in their SQL is replaced by vars[key], e.g. a configurable
+// table name. Versions must be unique.
+func Load(fsys fs.FS, vars map[string]string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	var migrations []Migration
+	seen := map[int]string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration %s: expected a NNNN_description.sql name", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", other, entry.Name(), version)
+		}
+		seen[version] = entry.Name()
+
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		text := string(data)
+		for k, v := range vars {
+			text = strings.ReplaceAll(text, "{{"+k+"}}", v)
+		}
+		migrations = append(migrations, Migration{Version: version, Name: match[2], SQL: text})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Runner applies migrations to db and records them in the table named table.
+type Runner struct {
+	db         *sql.DB
+	dialect    string
+	table      string
+	migrations []Migration
+}
+
+// NewRunner creates a runner for db in dialect. table must be a plain
+// identifier; it is interpolated in the statements.
+func NewRunner(db *sql.DB, dialect, table string, migrations []Migration) *Runner {
+	return &Runner{db: db, dialect: dialect, table: table, migrations: migrations}
+}
+
+// Pending returns the migrations not applied yet, without changing the
+// database.
+func (r *Runner) Pending(ctx context.Context) ([]Migration, error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return r.pending(ctx, conn)
+}
+
+// Up applies the pending migrations in version order, each in its own
+// transaction, and returns them. MySQL commits DDL statements implicitly, so
+// a failed MySQL migration may be half applied: keep them to one statement,
+// or write them to be re-run. Concurrent runners on MySQL wait for each
+// other; SQLite serializes them with its single writer.
+func (r *Runner) Up(ctx context.Context) ([]Migration, error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if r.dialect == DialectMySQL {
+		// El lock es de la conexión: se libera también si el proceso muere
+		var locked sql.NullInt64
+		if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", r.table, int(lockTimeout/time.Second)).Scan(&locked); err != nil {
+			return nil, fmt.Errorf("migrate: lock %s: %w", r.table, err)
+		}
+		if locked.Int64 != 1 {
+			return nil, fmt.Errorf("migrate: timed out waiting for another instance to migrate %s", r.table)
+		}
+		defer conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", r.table)
+	}
+
+	_, err = conn.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+r.table+" ("+
+		"version BIGINT NOT NULL PRIMARY KEY, "+
+		"name VARCHAR(255) NOT NULL, "+
+		"applied_at VARCHAR(40) NOT NULL)")
+	if err != nil {
+		return nil, fmt.Errorf("migrate: create %s: %w", r.table, err)
+	}
+	pending, err := r.pending(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	for i, m := range pending {
+		if err := r.apply(ctx, conn, m); err != nil {
+			return pending[:i], fmt.Errorf("migrate: %s: %w", m, err)
+		}
+	}
+	return pending, nil
+}
+
+func (r *Runner) apply(ctx context.Context, conn *sql.Conn, m Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, stmt := range statements(m.SQL) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	_, err = tx.ExecContext(ctx, "INSERT INTO "+r.table+" (version, name, applied_at) VALUES (?, ?, ?)",
+		m.Version, m.Name, time.Now().UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// pending lee las versiones aplicadas; sin tabla de seguimiento están todas
+// pendientes.
+func (r *Runner) pending(ctx context.Context, conn *sql.Conn) ([]Migration, error) {
+	exists := "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?"
+	if r.dialect == DialectMySQL {
+		exists = "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?"
+	}
+	var count int
+	if err := conn.QueryRowContext(ctx, exists, r.table).Scan(&count); err != nil {
+		return nil, fmt.Errorf("migrate: find %s: %w", r.table, err)
+	}
+	if count == 0 {
+		return r.migrations, nil
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT version FROM "+r.table)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: read %s: %w", r.table, err)
+	}
+	defer rows.Close()
+	applied := map[int]bool{}
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	pending := make([]Migration, 0)
+	for _, m := range r.migrations {
+		if !applied[m.Version] {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// statements separa el SQL en sentencias terminadas en ";" al final de una
+// línea, porque los drivers no aceptan varias por Exec.
+func statements(text string) []string {
+	var stmts []string
+	var current strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			stmts = append(stmts, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		stmts = append(stmts, rest)
+	}
+	return stmts
+}
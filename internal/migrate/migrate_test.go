@@ -0,0 +1,75 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	_ "modernc.org/sqlite"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestRunner_AppliesEachMigrationOnce(t *testing.T) {
+	db := openTestDB(t)
+	files := fstest.MapFS{
+		"0001_create_items.sql": {Data: []byte("-- tabla inicial\nCREATE TABLE {{table}} (id TEXT PRIMARY KEY);\n")},
+		"0002_add_name.sql":     {Data: []byte("ALTER TABLE {{table}} ADD COLUMN name TEXT;\nCREATE INDEX {{table}}_name ON {{table}} (name);\n")},
+	}
+	migrations, err := Load(files, map[string]string{"table": "items"})
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	runner := NewRunner(db, DialectSQLite, "schema_migrations", migrations)
+
+	if pending, err := runner.Pending(context.Background()); err != nil || len(pending) != 2 {
+		t.Fatalf("expected 2 pending migrations before the first run, got %v (err=%v)", pending, err)
+	}
+	applied, err := runner.Up(context.Background())
+	if err != nil || len(applied) != 2 || applied[1].String() != "0002_add_name" {
+		t.Fatalf("Up = %v, %v", applied, err)
+	}
+	if _, err := db.Exec("INSERT INTO items (id, name) VALUES ('i1', 'first')"); err != nil {
+		t.Fatalf("expected the migrated schema, got %v", err)
+	}
+	if applied, err := runner.Up(context.Background()); err != nil || len(applied) != 0 {
+		t.Errorf("expected nothing to apply on the second run, got %v (err=%v)", applied, err)
+	}
+
+	// Una migración que falla se revierte entera y frena las siguientes
+	files["0003_broken.sql"] = &fstest.MapFile{Data: []byte("ALTER TABLE items ADD COLUMN price REAL;\nALTER TABLE missing ADD COLUMN x TEXT;\n")}
+	files["0004_after.sql"] = &fstest.MapFile{Data: []byte("ALTER TABLE items ADD COLUMN notes TEXT;\n")}
+	migrations, _ = Load(files, map[string]string{"table": "items"})
+	runner = NewRunner(db, DialectSQLite, "schema_migrations", migrations)
+	if _, err := runner.Up(context.Background()); err == nil {
+		t.Fatal("expected the broken migration to fail")
+	}
+	pending, _ := runner.Pending(context.Background())
+	if len(pending) != 2 || pending[0].Version != 3 {
+		t.Errorf("expected 0003 and 0004 still pending, got %v", pending)
+	}
+	if _, err := db.Exec("UPDATE items SET price = 1"); err == nil {
+		t.Error("expected the partial migration rolled back")
+	}
+}
+
+func TestLoad_RejectsBadNamesAndDuplicateVersions(t *testing.T) {
+	for _, files := range []fstest.MapFS{
+		{"create.sql": {Data: []byte("SELECT 1;")}},
+		{"0001_a.sql": {Data: []byte("SELECT 1;")}, "1_b.sql": {Data: []byte("SELECT 1;")}},
+	} {
+		if _, err := Load(files, nil); err == nil {
+			t.Errorf("expected %v to be rejected", files)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Package ocr reads the text of scanned receipts through an HTTP OCR service.
+package ocr
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"resty.dev/v3"
+)
+
+// HTTPProvider posts the file as the request body, with its Content-Type, and
+// expects a JSON response with the recognized text: {"text": "..."}. This is
+// the shape of most self-hosted OCR services (e.g. a Tesseract wrapper).
+type HTTPProvider struct {
+	url    string
+	client *resty.Client
+}
+
+// NewHTTPProvider creates a provider for the service at url. A non-empty
+// apiKey is sent as a bearer token.
+func NewHTTPProvider(url, apiKey string, timeout time.Duration) *HTTPProvider {
+	client := resty.New().
+		SetTimeout(timeout).
+		SetRetryCount(1).
+		SetRetryWaitTime(500 * time.Millisecond)
+	if apiKey != "" {
+		client.SetAuthToken(apiKey)
+	}
+	return &HTTPProvider{url: url, client: client}
+}
+
+// SetTransport sends requests through rt, e.g. to apply proxy and TLS settings.
+func (p *HTTPProvider) SetTransport(rt http.RoundTripper) {
+	p.client.SetTransport(rt)
+}
+
+func (p *HTTPProvider) Recognize(ctx context.Context, contentType string, data []byte) (string, error) {
+	var result struct {
+		Text string `json:"text"`
+	}
+	resp, err := p.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", contentType).
+		SetBody(data).
+		SetResult(&result).
+		Post(p.url)
+	if err != nil {
+		return "", err
+	}
+	if resp.IsError() {
+		return "", fmt.Errorf("ocr provider returned status %d", resp.StatusCode())
+	}
+	return result.Text, nil
+}
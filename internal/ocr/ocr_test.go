@@ -0,0 +1,33 @@
+package ocr
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestRecognize_PostsFileAndReadsText verifica el request enviado y la lectura
+// del texto reconocido.
+func TestRecognize_PostsFileAndReadsText(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("Content-Type") != "image/png" || string(body) != "png-bytes" {
+			t.Errorf("unexpected request %q with body %q", r.Header.Get("Content-Type"), body)
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("expected the api key as bearer token, got %q", r.Header.Get("Authorization"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"text":"TOTAL 12.990"}`))
+	}))
+	defer server.Close()
+
+	provider := NewHTTPProvider(server.URL, "secret", time.Second)
+	text, err := provider.Recognize(context.Background(), "image/png", []byte("png-bytes"))
+	if err != nil || text != "TOTAL 12.990" {
+		t.Errorf("expected the recognized text, got %q, %v", text, err)
+	}
+}
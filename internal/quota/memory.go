@@ -0,0 +1,42 @@
+package quota
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryStore keeps usage in process memory. It is only correct for a single
+// API instance and is lost on restart.
+type MemoryStore struct {
+	mu     sync.Mutex
+	counts map[string]map[string]int64
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		counts: map[string]map[string]int64{},
+	}
+}
+
+func (m *MemoryStore) Incr(ctx context.Context, tenant, period, metric string, n int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := tenant + "|" + period
+	if m.counts[key] == nil {
+		m.counts[key] = map[string]int64{}
+	}
+	m.counts[key][metric] += n
+	return m.counts[key][metric], nil
+}
+
+func (m *MemoryStore) Get(ctx context.Context, tenant, period string) (map[string]int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counts := map[string]int64{}
+	for metric, n := range m.counts[tenant+"|"+period] {
+		counts[metric] = n
+	}
+	return counts, nil
+}
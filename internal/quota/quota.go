@@ -0,0 +1,193 @@
+// Package quota tracks per-tenant usage by calendar month and enforces the
+// configured rate limits and monthly quotas. Usage stores are pluggable so
+// several API instances count against the same quota.
+package quota
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Error para tenants que superan su tasa de requests por segundo
+var ErrRateLimited = errors.New("tenant rate limit exceeded")
+
+// Error para tenants que superan su cuota mensual de requests
+var ErrRequestQuotaExceeded = errors.New("tenant monthly request quota exceeded")
+
+// Error para tenants que superan su cuota mensual de ventas creadas
+var ErrSalesQuotaExceeded = errors.New("tenant monthly sales quota exceeded")
+
+const (
+	MetricRequests = "requests"
+	MetricSales    = "sales_created"
+)
+
+// Limits apply to one tenant; zero values are unlimited.
+type Limits struct {
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	Burst             int     `json:"burst"`
+	MonthlyRequests   int64   `json:"monthly_requests"`
+	MonthlySales      int64   `json:"monthly_sales"`
+}
+
+// Policy maps tenant IDs to their limits, with "*" as the default.
+type Policy map[string]Limits
+
+// For returns the limits of tenant.
+func (p Policy) For(tenant string) Limits {
+	if l, ok := p[tenant]; ok {
+		return l
+	}
+	return p["*"]
+}
+
+// Usage is what a tenant consumed in Period ("2006-01", UTC).
+type Usage struct {
+	TenantID     string `json:"tenant_id"`
+	Period       string `json:"period"`
+	Requests     int64  `json:"requests"`
+	SalesCreated int64  `json:"sales_created"`
+	Limits       Limits `json:"limits"`
+}
+
+// Store keeps the usage counters of every tenant and month.
+type Store interface {
+	// Incr adds n to metric and returns the new total.
+	Incr(ctx context.Context, tenant, period, metric string, n int64) (int64, error)
+	Get(ctx context.Context, tenant, period string) (map[string]int64, error)
+}
+
+// PeriodOf returns the billing period of t.
+func PeriodOf(t time.Time) string {
+	return t.UTC().Format("2006-01")
+}
+
+// Limiter is a per-tenant token bucket. Unlike the monthly counters it is
+// local to each instance.
+type Limiter struct {
+	policy Policy
+	now    func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func NewLimiter(policy Policy) *Limiter {
+	return &Limiter{policy: policy, now: time.Now, buckets: map[string]*bucket{}}
+}
+
+// Allow consumes a token of tenant. When the bucket is empty it returns false
+// and how long until the next token.
+func (l *Limiter) Allow(tenant string) (bool, time.Duration) {
+	limits := l.policy.For(tenant)
+	if limits.RequestsPerSecond <= 0 {
+		return true, 0
+	}
+	burst := float64(limits.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	b, ok := l.buckets[tenant]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[tenant] = b
+	}
+	b.tokens = min(burst, b.tokens+now.Sub(b.last).Seconds()*limits.RequestsPerSecond)
+	b.last = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / limits.RequestsPerSecond * float64(time.Second))
+		return false, wait
+	}
+	b.tokens--
+	return true, 0
+}
+
+// Tracker counts tenant usage in a Store and enforces the Policy.
+type Tracker struct {
+	store   Store
+	policy  Policy
+	limiter *Limiter
+	now     func() time.Time
+}
+
+func NewTracker(store Store, policy Policy) *Tracker {
+	return &Tracker{store: store, policy: policy, limiter: NewLimiter(policy), now: time.Now}
+}
+
+// Request counts a request of tenant. On error retryAfter says when the
+// tenant may try again: the next token, or the next billing period.
+func (t *Tracker) Request(ctx context.Context, tenant string) (time.Duration, error) {
+	if ok, wait := t.limiter.Allow(tenant); !ok {
+		return wait, ErrRateLimited
+	}
+
+	now := t.now()
+	total, err := t.store.Incr(ctx, tenant, PeriodOf(now), MetricRequests, 1)
+	if err != nil {
+		return 0, err
+	}
+	if limit := t.policy.For(tenant).MonthlyRequests; limit > 0 && total > limit {
+		return untilNextPeriod(now), ErrRequestQuotaExceeded
+	}
+	return 0, nil
+}
+
+// CheckSales fails with ErrSalesQuotaExceeded when tenant can't create more
+// sales this period.
+func (t *Tracker) CheckSales(ctx context.Context, tenant string) (time.Duration, error) {
+	limit := t.policy.For(tenant).MonthlySales
+	if limit <= 0 {
+		return 0, nil
+	}
+	now := t.now()
+	counts, err := t.store.Get(ctx, tenant, PeriodOf(now))
+	if err != nil {
+		return 0, err
+	}
+	if counts[MetricSales] >= limit {
+		return untilNextPeriod(now), ErrSalesQuotaExceeded
+	}
+	return 0, nil
+}
+
+// SaleCreated counts a sale created by tenant.
+func (t *Tracker) SaleCreated(ctx context.Context, tenant string) error {
+	_, err := t.store.Incr(ctx, tenant, PeriodOf(t.now()), MetricSales, 1)
+	return err
+}
+
+// Usage returns what tenant consumed in period; empty means the current one.
+func (t *Tracker) Usage(ctx context.Context, tenant, period string) (Usage, error) {
+	if period == "" {
+		period = PeriodOf(t.now())
+	}
+	counts, err := t.store.Get(ctx, tenant, period)
+	if err != nil {
+		return Usage{}, err
+	}
+	return Usage{
+		TenantID:     tenant,
+		Period:       period,
+		Requests:     counts[MetricRequests],
+		SalesCreated: counts[MetricSales],
+		Limits:       t.policy.For(tenant),
+	}, nil
+}
+
+func untilNextPeriod(now time.Time) time.Duration {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	return next.Sub(now)
+}
@@ -0,0 +1,73 @@
+package quota
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestTracker_EnforcesMonthlyQuotas verifica el conteo y las cuotas mensuales.
+func TestTracker_EnforcesMonthlyQuotas(t *testing.T) {
+	ctx := context.Background()
+	tracker := NewTracker(NewMemoryStore(), Policy{
+		"acme": {MonthlyRequests: 3, MonthlySales: 1},
+	})
+	now := time.Date(2026, 10, 31, 23, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if _, err := tracker.Request(ctx, "acme"); err != nil {
+			t.Fatalf("request %d: unexpected error %v", i, err)
+		}
+	}
+	retryAfter, err := tracker.Request(ctx, "acme")
+	if err != ErrRequestQuotaExceeded {
+		t.Fatalf("expected ErrRequestQuotaExceeded, got %v", err)
+	}
+	if retryAfter != time.Hour {
+		t.Errorf("expected retry after the period ends in 1h, got %v", retryAfter)
+	}
+
+	if _, err := tracker.CheckSales(ctx, "acme"); err != nil {
+		t.Fatalf("CheckSales returned error: %v", err)
+	}
+	tracker.SaleCreated(ctx, "acme")
+	if _, err := tracker.CheckSales(ctx, "acme"); err != ErrSalesQuotaExceeded {
+		t.Errorf("expected ErrSalesQuotaExceeded, got %v", err)
+	}
+
+	// Otros tenants sin límites no se ven afectados
+	if _, err := tracker.Request(ctx, "other"); err != nil {
+		t.Errorf("expected unlimited tenant to pass, got %v", err)
+	}
+
+	usage, err := tracker.Usage(ctx, "acme", "")
+	if err != nil {
+		t.Fatalf("Usage returned error: %v", err)
+	}
+	if usage.Period != "2026-10" || usage.Requests != 4 || usage.SalesCreated != 1 {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+}
+
+// TestLimiter_RefillsTokens verifica la tasa por segundo con burst.
+func TestLimiter_RefillsTokens(t *testing.T) {
+	l := NewLimiter(Policy{"*": {RequestsPerSecond: 2, Burst: 2}})
+	now := time.Now()
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.Allow("acme"); !ok {
+			t.Fatalf("request %d: expected burst to allow it", i)
+		}
+	}
+	ok, wait := l.Allow("acme")
+	if ok || wait != 500*time.Millisecond {
+		t.Fatalf("expected rejection with 500ms wait, got ok=%v wait=%v", ok, wait)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := l.Allow("acme"); !ok {
+		t.Error("expected a token after refill")
+	}
+}
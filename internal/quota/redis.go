@@ -0,0 +1,53 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore shares usage counters between API instances. Each tenant and
+// period is a hash that expires after the billing month has been reported.
+type RedisStore struct {
+	client    redis.UniversalClient
+	prefix    string
+	retention time.Duration
+}
+
+func NewRedisStore(client redis.UniversalClient) *RedisStore {
+	return &RedisStore{
+		client:    client,
+		prefix:    "api_sales:usage:",
+		retention: 400 * 24 * time.Hour,
+	}
+}
+
+func (r *RedisStore) Incr(ctx context.Context, tenant, period, metric string, n int64) (int64, error) {
+	key := r.prefix + tenant + ":" + period
+	pipe := r.client.TxPipeline()
+	incr := pipe.HIncrBy(ctx, key, metric, n)
+	pipe.Expire(ctx, key, r.retention)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("redis usage incr: %w", err)
+	}
+	return incr.Val(), nil
+}
+
+func (r *RedisStore) Get(ctx context.Context, tenant, period string) (map[string]int64, error) {
+	raw, err := r.client.HGetAll(ctx, r.prefix+tenant+":"+period).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis usage get: %w", err)
+	}
+	counts := make(map[string]int64, len(raw))
+	for metric, v := range raw {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("redis usage decode %s: %w", metric, err)
+		}
+		counts[metric] = n
+	}
+	return counts, nil
+}
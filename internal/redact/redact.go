@@ -0,0 +1,129 @@
+// Package redact masks sensitive fields before they leave the service in logs
+// or outbound events.
+package redact
+
+import (
+	"api_sales/internal/jsonenc"
+	"bytes"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Mask replaces the value of every sensitive field.
+const Mask = "[REDACTED]"
+
+// DefaultFields are masked when no list is configured.
+var DefaultFields = []string{"user_id", "email", "phone"}
+
+// Redactor masks values whose key is in its field list. Keys are matched
+// case-insensitively, at any depth.
+type Redactor struct {
+	fields map[string]struct{}
+}
+
+// New creates a redactor for fields.
+func New(fields []string) *Redactor {
+	r := &Redactor{fields: make(map[string]struct{}, len(fields))}
+	for _, f := range fields {
+		if f = strings.ToLower(strings.TrimSpace(f)); f != "" {
+			r.fields[f] = struct{}{}
+		}
+	}
+	return r
+}
+
+// Sensitive reports whether key is masked.
+func (r *Redactor) Sensitive(key string) bool {
+	_, ok := r.fields[strings.ToLower(key)]
+	return ok
+}
+
+// JSON returns body with every sensitive value masked. Bodies that are not
+// JSON objects or arrays are returned unchanged.
+func (r *Redactor) JSON(body []byte) ([]byte, error) {
+	var v any
+	dec := jsonenc.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return jsonenc.Marshal(r.value(v))
+}
+
+// value recorre mapas y listas enmascarando las claves sensibles.
+func (r *Redactor) value(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, inner := range t {
+			if r.Sensitive(k) {
+				t[k] = Mask
+				continue
+			}
+			t[k] = r.value(inner)
+		}
+	case []any:
+		for i, inner := range t {
+			t[i] = r.value(inner)
+		}
+	}
+	return v
+}
+
+// Core wraps a zap core so every field, including structs logged with
+// zap.Any, is redacted before it is encoded.
+func (r *Redactor) Core(core zapcore.Core) zapcore.Core {
+	return &redactingCore{Core: core, r: r}
+}
+
+type redactingCore struct {
+	zapcore.Core
+	r *Redactor
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{Core: c.Core.With(c.r.fieldsOf(fields)), r: c.r}
+}
+
+func (c *redactingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *redactingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, c.r.fieldsOf(fields))
+}
+
+func (r *Redactor) fieldsOf(fields []zapcore.Field) []zapcore.Field {
+	out := make([]zapcore.Field, len(fields))
+	for i, f := range fields {
+		out[i] = r.field(f)
+	}
+	return out
+}
+
+// field enmascara campos sensibles y, para structs, los serializa a JSON para
+// enmascarar sus claves internas.
+func (r *Redactor) field(f zapcore.Field) zapcore.Field {
+	if r.Sensitive(f.Key) {
+		return zap.String(f.Key, Mask)
+	}
+	if f.Type != zapcore.ReflectType || f.Interface == nil {
+		return f
+	}
+
+	raw, err := jsonenc.Marshal(f.Interface)
+	if err != nil {
+		return f
+	}
+	var v any
+	dec := jsonenc.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return f
+	}
+	return zap.Any(f.Key, r.value(v))
+}
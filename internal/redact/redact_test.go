@@ -0,0 +1,44 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type sale struct {
+	ID     string  `json:"id"`
+	UserID string  `json:"user_id"`
+	Amount float64 `json:"amount"`
+}
+
+// TestCore_MasksFieldsAndNestedStructs verifica el enmascarado en campos planos y structs.
+func TestCore_MasksFieldsAndNestedStructs(t *testing.T) {
+	r := New(DefaultFields)
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(r.Core(core)).With(zap.String("email", "buyer@example.com"))
+
+	logger.Info("sale created", zap.String("user_id", "user123"), zap.Any("sale", sale{ID: "s1", UserID: "user123", Amount: 10}))
+
+	fields := logs.All()[0].ContextMap()
+	if fields["user_id"] != Mask || fields["email"] != Mask {
+		t.Errorf("expected top-level fields to be masked, got %v", fields)
+	}
+	nested, ok := fields["sale"].(map[string]any)
+	if !ok || nested["user_id"] != Mask || nested["id"] != "s1" {
+		t.Errorf("expected nested user_id to be masked, got %v", fields["sale"])
+	}
+}
+
+// TestRedactor_JSON verifica el enmascarado de payloads salientes.
+func TestRedactor_JSON(t *testing.T) {
+	out, err := New([]string{"User_ID"}).JSON([]byte(`{"id":"evt","data":{"user_id":"user123","amount":12345678901234567}}`))
+	if err != nil {
+		t.Fatalf("JSON returned error: %v", err)
+	}
+	if strings.Contains(string(out), "user123") || !strings.Contains(string(out), "12345678901234567") {
+		t.Errorf("unexpected redacted payload: %s", out)
+	}
+}
@@ -0,0 +1,278 @@
+// Package replay sends recorded traffic to another instance of the API, e.g.
+// a staging deployment on a new storage backend, keeping the pace it was
+// recorded at. Requests come from the JSON access log or from the audit
+// export; see ReadAccessLog and ReadChangeFeed.
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"resty.dev/v3"
+)
+
+// salePlaceholder marca en Path el ID de la venta, que en el destino es otro.
+const salePlaceholder = "{sale}"
+
+// Request is one recorded request.
+type Request struct {
+	At     time.Time
+	Method string
+	// Path may contain {sale}, replaced by the target's ID of Sale or, when
+	// Sale is empty, by any sale the replay knows of.
+	Path string
+	Body []byte
+	Sale string
+	// Creates marks the request that creates Sale: the ID in its response
+	// replaces Sale in the requests that follow.
+	Creates bool
+}
+
+// Report summarizes a replay.
+type Report struct {
+	Sent int `json:"sent"`
+	// Failed counts the requests that got no response.
+	Failed   int            `json:"failed"`
+	Skipped  map[string]int `json:"skipped,omitempty"`
+	Statuses map[int]int    `json:"statuses"`
+	// LatencyMS holds the p50, p95 and p99 latencies of the responses.
+	LatencyMS map[string]float64 `json:"latency_ms"`
+	// MaxLagMS is how far behind the recorded pace the replay fell, e.g.
+	// because the target answered slower than the original.
+	MaxLagMS float64 `json:"max_lag_ms"`
+	Duration string  `json:"duration"`
+}
+
+// Write prints the report as indented JSON.
+func (r Report) Write(w io.Writer) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// Replayer sends requests to a target instance.
+type Replayer struct {
+	client  *resty.Client
+	speed   float64
+	workers int
+	logger  *zap.Logger
+
+	mu        sync.Mutex
+	ids       map[string]string
+	known     []string
+	next      int
+	skipped   map[string]int
+	statuses  map[int]int
+	latencies []time.Duration
+	failed    int
+	maxLag    time.Duration
+}
+
+// NewReplayer replays against the API at baseURL. speed scales the recorded
+// pace, 2 being twice as fast, and 0 sends every request as soon as a worker
+// is free. Requests of the same sale go through the same worker, in order.
+func NewReplayer(baseURL string, speed float64, workers int, timeout time.Duration, logger *zap.Logger) *Replayer {
+	return &Replayer{
+		client:   resty.New().SetBaseURL(strings.TrimSuffix(baseURL, "/")).SetTimeout(timeout),
+		speed:    speed,
+		workers:  max(workers, 1),
+		logger:   logger,
+		ids:      map[string]string{},
+		skipped:  map[string]int{},
+		statuses: map[int]int{},
+	}
+}
+
+// SetHeader sends header on every request, e.g. the credentials of the target.
+func (r *Replayer) SetHeader(name, value string) {
+	r.client.SetHeader(name, value)
+}
+
+// SetTransport sends the requests through rt.
+func (r *Replayer) SetTransport(rt http.RoundTripper) {
+	r.client.SetTransport(rt)
+}
+
+// AddSales makes existing sales of the target available to the requests
+// that take any sale, e.g. reads replayed from the access log.
+func (r *Replayer) AddSales(ids ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.known = append(r.known, ids...)
+}
+
+// Run replays requests in order until they are all answered or ctx is done.
+func (r *Replayer) Run(ctx context.Context, requests []Request) Report {
+	start := time.Now()
+	queues := make([]chan scheduled, r.workers)
+	var wg sync.WaitGroup
+	for i := range queues {
+		queues[i] = make(chan scheduled)
+		wg.Add(1)
+		go func(queue chan scheduled) {
+			defer wg.Done()
+			for s := range queue {
+				r.send(ctx, s)
+			}
+		}(queues[i])
+	}
+
+	var first time.Time
+	if len(requests) > 0 {
+		first = requests[0].At
+	}
+	next := 0
+dispatch:
+	for _, req := range requests {
+		due := start
+		if r.speed > 0 {
+			due = start.Add(time.Duration(float64(req.At.Sub(first)) / r.speed))
+		}
+		if wait := time.Until(due); wait > 0 {
+			select {
+			case <-ctx.Done():
+				break dispatch
+			case <-time.After(wait):
+			}
+		}
+
+		// Las requests de una venta van en orden por el mismo worker
+		worker := next % r.workers
+		if req.Sale != "" {
+			h := fnv.New32a()
+			h.Write([]byte(req.Sale))
+			worker = int(h.Sum32() % uint32(r.workers))
+		} else {
+			next++
+		}
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case queues[worker] <- scheduled{Request: req, due: due}:
+		}
+	}
+	for _, queue := range queues {
+		close(queue)
+	}
+	wg.Wait()
+
+	return r.report(time.Since(start))
+}
+
+type scheduled struct {
+	Request
+	due time.Time
+}
+
+func (r *Replayer) send(ctx context.Context, s scheduled) {
+	path, ok := r.resolve(s.Request)
+	if !ok {
+		r.skip("sale not created on the target")
+		return
+	}
+
+	sent := time.Now()
+	req := r.client.R().SetContext(ctx)
+	if s.Body != nil {
+		req.SetHeader("Content-Type", "application/json").SetBody(s.Body)
+	}
+	resp, err := req.Execute(s.Method, path)
+	latency := time.Since(sent)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if lag := sent.Sub(s.due); lag > r.maxLag {
+		r.maxLag = lag
+	}
+	if err != nil {
+		r.failed++
+		r.logger.Warn("replayed request failed", zap.String("method", s.Method), zap.String("path", path), zap.Error(err))
+		return
+	}
+	r.statuses[resp.StatusCode()]++
+	r.latencies = append(r.latencies, latency)
+
+	if s.Creates && !resp.IsError() {
+		var created struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(resp.Bytes(), &created); err == nil && created.ID != "" {
+			r.ids[s.Sale] = created.ID
+			r.known = append(r.known, created.ID)
+		}
+	}
+}
+
+// resolve reemplaza {sale} por el ID de la venta en el destino.
+func (r *Replayer) resolve(req Request) (string, bool) {
+	if !strings.Contains(req.Path, salePlaceholder) {
+		return req.Path, true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var id string
+	if req.Sale != "" {
+		id = r.ids[req.Sale]
+	} else if len(r.known) > 0 {
+		id = r.known[r.next%len(r.known)]
+		r.next++
+	}
+	if id == "" {
+		return "", false
+	}
+	return strings.ReplaceAll(req.Path, salePlaceholder, id), true
+}
+
+func (r *Replayer) skip(reason string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.skipped[reason]++
+}
+
+func (r *Replayer) report(elapsed time.Duration) Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := Report{
+		Failed:    r.failed,
+		Skipped:   map[string]int{},
+		Statuses:  map[int]int{},
+		LatencyMS: map[string]float64{},
+		MaxLagMS:  milliseconds(r.maxLag),
+		Duration:  elapsed.Round(time.Millisecond).String(),
+	}
+	for reason, n := range r.skipped {
+		report.Skipped[reason] = n
+	}
+	for status, n := range r.statuses {
+		report.Statuses[status] = n
+		report.Sent += n
+	}
+	report.Sent += r.failed
+
+	latencies := append([]time.Duration(nil), r.latencies...)
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	if len(latencies) > 0 {
+		for name, q := range map[string]float64{"p50": 0.5, "p95": 0.95, "p99": 0.99} {
+			report.LatencyMS[name] = milliseconds(latencies[int(q*float64(len(latencies)-1))])
+		}
+	}
+	return report
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
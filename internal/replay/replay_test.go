@@ -0,0 +1,150 @@
+package replay
+
+import (
+	"api_sales/internal/sales"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func TestReplay_RebuildsTheWritesOfTheChangeFeed(t *testing.T) {
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	draft := &sales.Sale{ID: "orig-1", UserID: "user1", Amount: 100, Status: sales.StatusDraft}
+	edited := &sales.Sale{ID: "orig-1", UserID: "user1", Amount: 120, Status: sales.StatusDraft, TaxPercent: 21}
+	pending := &sales.Sale{ID: "orig-1", UserID: "user1", Amount: 120, Status: sales.StatusPending, TaxPercent: 21}
+	completed := &sales.Sale{ID: "orig-1", UserID: "user1", Amount: 120, Status: sales.StatusApproved, TaxPercent: 21}
+	shipped := &sales.Sale{ID: "orig-1", UserID: "user1", Amount: 120, Status: sales.StatusApproved, TaxPercent: 21,
+		FulfillmentStatus: "shipped", Carrier: "dhl", TrackingNumber: "T1"}
+
+	var feed strings.Builder
+	enc := json.NewEncoder(&feed)
+	enc.Encode(sales.AuditEntry{SaleID: "orig-1", Action: sales.AuditActionDraftEdited, Before: draft, After: edited, CreatedAt: at})
+	enc.Encode(sales.AuditEntry{SaleID: "orig-1", Action: sales.AuditActionStatusChanged, Before: pending, After: completed, CreatedAt: at.Add(time.Second / 2)})
+	enc.Encode(sales.AuditEntry{SaleID: "orig-1", Action: sales.AuditActionFulfillment, Before: completed, After: shipped, CreatedAt: at.Add(time.Second)})
+	enc.Encode(sales.AuditEntry{SaleID: "orig-1", Action: sales.AuditActionDisputed, Before: shipped, After: shipped, CreatedAt: at.Add(2 * time.Second)})
+
+	requests, skipped, err := ReadChangeFeed(strings.NewReader(feed.String()))
+	if err != nil {
+		t.Fatalf("ReadChangeFeed returned error: %v", err)
+	}
+	if skipped["disputed not replayable"] != 1 {
+		t.Errorf("expected the dispute skipped, got %v", skipped)
+	}
+
+	access := `{"level":"info","ts":1717243201.5,"msg":"access","method":"GET","route":"/sales/:id","status":200}
+{"level":"info","ts":1717243201.2,"msg":"access","method":"POST","route":"/sales","status":201}
+{"level":"info","ts":1717243201.7,"msg":"access","method":"GET","route":"/sales/:id/comments/:comment_id","status":200}
+not json`
+	reads, readSkips, err := ReadAccessLog(strings.NewReader(access))
+	if err != nil {
+		t.Fatalf("ReadAccessLog returned error: %v", err)
+	}
+	if len(reads) != 1 || readSkips["write without a recorded body"] != 1 || readSkips["route parameters not recorded"] != 1 || readSkips["malformed line"] != 1 {
+		t.Errorf("expected a single replayable read, got %+v and %v", reads, readSkips)
+	}
+
+	var mu sync.Mutex
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		got = append(got, r.Method+" "+r.URL.Path+" "+string(body))
+		mu.Unlock()
+		if r.Header.Get("X-API-Key") != "staging-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method == http.MethodPost && r.URL.Path == "/sales" {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"staging-1"}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	replayer := NewReplayer(server.URL, 0, 1, time.Second, zaptest.NewLogger(t))
+	replayer.SetHeader("X-API-Key", "staging-key")
+	report := replayer.Run(context.Background(), append(requests, reads...))
+
+	want := []string{
+		`POST /sales {"amount":100,"status":"draft","user_id":"user1"}`,
+		`PATCH /sales/staging-1 {"amount":120,"tax_percent":21}`,
+		`POST /sales/staging-1/submit `,
+		`PATCH /sales/staging-1 {"status":"approved"}`,
+		`PATCH /sales/staging-1/fulfillment {"carrier":"dhl","status":"shipped","tracking_number":"T1"}`,
+		`GET /sales/staging-1 `,
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d requests, got %q", len(want), got)
+	}
+	for i, w := range want {
+		if got[i] != w {
+			t.Errorf("request %d: expected %q, got %q", i, w, got[i])
+		}
+	}
+	if report.Sent != 6 || report.Statuses[http.StatusCreated] != 1 || report.Statuses[http.StatusOK] != 5 {
+		t.Errorf("unexpected report %+v", report)
+	}
+}
+
+// verifica que la creación y el envío recreen el estado sorteado por el servicio
+func TestReadChangeFeed_ReplaysCreationAndSubmission(t *testing.T) {
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	draft := &sales.Sale{ID: "orig-1", UserID: "user1", Amount: 100, Status: sales.StatusDraft}
+	approved := &sales.Sale{ID: "orig-1", UserID: "user1", Amount: 100, Status: sales.StatusApproved}
+	posted := &sales.Sale{ID: "orig-1", UserID: "user1", Amount: 100, Status: sales.StatusApproved, ERPPosting: sales.ERPPostingPosted}
+
+	var feed strings.Builder
+	enc := json.NewEncoder(&feed)
+	enc.Encode(sales.AuditEntry{SaleID: "orig-1", Action: sales.AuditActionCreated, After: draft, CreatedAt: at})
+	enc.Encode(sales.AuditEntry{SaleID: "orig-1", Action: sales.AuditActionSubmitted, Before: draft, After: approved, CreatedAt: at.Add(time.Second)})
+	enc.Encode(sales.AuditEntry{SaleID: "orig-1", Action: sales.AuditActionERPPosting, Before: approved, After: posted, CreatedAt: at.Add(2 * time.Second)})
+
+	requests, skipped, err := ReadChangeFeed(strings.NewReader(feed.String()))
+	if err != nil {
+		t.Fatalf("ReadChangeFeed returned error: %v", err)
+	}
+	var got []string
+	for _, r := range requests {
+		got = append(got, r.Method+" "+r.Path+" "+string(r.Body))
+	}
+	want := []string{
+		`POST /sales {"amount":100,"status":"draft","user_id":"user1"}`,
+		`POST /sales/{sale}/submit `,
+		`PATCH /sales/{sale} {"status":"approved"}`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if skipped[sales.AuditActionERPPosting+" not replayable"] != 1 {
+		t.Errorf("expected the erp posting skipped, got %v", skipped)
+	}
+}
+
+func TestReplay_SkipsRequestsOfSalesNotCreated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	replayer := NewReplayer(server.URL, 0, 1, time.Second, zaptest.NewLogger(t))
+	report := replayer.Run(context.Background(), []Request{
+		{Method: http.MethodPost, Path: "/sales", Body: []byte(`{}`), Sale: "orig", Creates: true},
+		{Method: http.MethodPatch, Path: "/sales/{sale}", Body: []byte(`{}`), Sale: "orig"},
+		{Method: http.MethodGet, Path: "/sales/{sale}"},
+	})
+	if report.Sent != 1 || report.Skipped["sale not created on the target"] != 2 {
+		t.Errorf("expected only the create sent, got %+v", report)
+	}
+}
@@ -0,0 +1,241 @@
+package replay
+
+import (
+	"api_sales/internal/jsonenc"
+	"api_sales/internal/sales"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Skipped counts the recorded requests that cannot be replayed, by reason.
+type Skipped map[string]int
+
+// accessLine es una línea del access log JSON de la API.
+type accessLine struct {
+	Msg    string  `json:"msg"`
+	TS     float64 `json:"ts"`
+	Method string  `json:"method"`
+	Route  string  `json:"route"`
+	Path   string  `json:"path"`
+}
+
+// ReadAccessLog reads the JSON access log of the API, sorted by time. The log
+// has no bodies, query strings or IDs, so only reads are replayed: without
+// their query, and on /sales/:id routes against any sale the replay knows of.
+// Keep in mind that the log samples successful requests on busy routes.
+func ReadAccessLog(r io.Reader) ([]Request, Skipped, error) {
+	var requests []Request
+	skipped := Skipped{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var line accessLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			skipped["malformed line"]++
+			continue
+		}
+		if line.Msg != "access" {
+			continue
+		}
+		if line.Method != http.MethodGet && line.Method != http.MethodHead {
+			skipped["write without a recorded body"]++
+			continue
+		}
+
+		path, ok := accessPath(line)
+		if !ok {
+			skipped["route parameters not recorded"]++
+			continue
+		}
+		sec, frac := math.Modf(line.TS)
+		requests = append(requests, Request{
+			At:     time.Unix(int64(sec), int64(frac*1e9)),
+			Method: line.Method,
+			Path:   path,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	sort.SliceStable(requests, func(i, j int) bool { return requests[i].At.Before(requests[j].At) })
+	return requests, skipped, nil
+}
+
+// accessPath arma el path de una línea: solo las rutas no registradas tienen
+// el path real y de los parámetros solo se puede completar el de la venta.
+func accessPath(line accessLine) (string, bool) {
+	if line.Route == "" {
+		return line.Path, line.Path != ""
+	}
+	path := line.Route
+	if rest, ok := strings.CutPrefix(path, "/sales/:id"); ok && (rest == "" || rest[0] == '/') {
+		path = "/sales/" + salePlaceholder + rest
+	}
+	return path, !strings.Contains(path, ":") && !strings.Contains(path, "*")
+}
+
+// ReadChangeFeed reads the NDJSON export of GET /admin/audit/export and
+// rebuilds the writes behind it: every sale is created from its first
+// snapshot, then submitted, edited, moved between statuses and fulfilled the
+// way the entries record. Disputes and ERP postings are not replayed, as the
+// export lacks their input.
+// The export keeps the stored snapshots: with field encryption, sensitive
+// metadata is replayed as its ciphertext.
+func ReadChangeFeed(r io.Reader) ([]Request, Skipped, error) {
+	var requests []Request
+	skipped := Skipped{}
+	// último estado de cada venta en el destino
+	state := map[string]*sales.Sale{}
+	dec := json.NewDecoder(r)
+	for n := 1; ; n++ {
+		var entry sales.AuditEntry
+		if err := dec.Decode(&entry); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, nil, fmt.Errorf("change feed entry %d: %w", n, err)
+		}
+		if entry.After == nil {
+			skipped["entry without snapshot"]++
+			continue
+		}
+
+		last, seen := state[entry.SaleID]
+		if !seen {
+			initial := entry.Before
+			if initial == nil {
+				initial = entry.After
+			}
+			create, created := createRequest(entry.SaleID, initial, entry.CreatedAt)
+			requests = append(requests, create)
+			last = created
+		}
+		// Envíos a revisión y entradas anteriores a la auditoría de estados:
+		// el cambio se deduce del snapshot previo
+		if entry.Before != nil && entry.Before.Status != last.Status {
+			requests = append(requests, statusRequests(entry, last.Status, entry.Before.Status)...)
+		}
+
+		switch entry.Action {
+		case sales.AuditActionCreated, sales.AuditActionSubmitted, sales.AuditActionVerificationResolved:
+			// El estado lo sorteó o lo resolvió el servicio: se fija el registrado
+			from := last.Status
+			if entry.Before != nil {
+				from = entry.Before.Status
+			}
+			if from != entry.After.Status {
+				requests = append(requests, statusRequests(entry, from, entry.After.Status)...)
+			}
+		case sales.AuditActionDraftEdited, sales.AuditActionAmended:
+			before := entry.Before
+			if before == nil {
+				before = last
+			}
+			if edit := editBody(before, entry.After); len(edit) > 0 {
+				requests = append(requests, saleRequest(entry, http.MethodPatch, "", edit))
+			}
+		case sales.AuditActionStatusChanged:
+			from := last.Status
+			if entry.Before != nil {
+				from = entry.Before.Status
+			}
+			requests = append(requests, statusRequests(entry, from, entry.After.Status)...)
+		case sales.AuditActionFulfillment:
+			requests = append(requests, saleRequest(entry, http.MethodPatch, "/fulfillment", map[string]string{
+				"status":          entry.After.FulfillmentStatus,
+				"carrier":         entry.After.Carrier,
+				"tracking_number": entry.After.TrackingNumber,
+			}))
+		default:
+			skipped[entry.Action+" not replayable"]++
+		}
+		state[entry.SaleID] = entry.After
+	}
+	return requests, skipped, nil
+}
+
+// createRequest crea la venta como borrador o pendiente, los estados con los
+// que se crea por la API, y retorna el estado en que queda.
+func createRequest(saleID string, sale *sales.Sale, at time.Time) (Request, *sales.Sale) {
+	body := map[string]any{"user_id": sale.UserID, "amount": sale.Amount}
+	created := &sales.Sale{ID: saleID, Status: sales.StatusPending}
+	if sale.Status == sales.StatusDraft {
+		body["status"] = sales.StatusDraft
+		created.Status = sales.StatusDraft
+	}
+	// Las exportaciones anteriores enmascaraban la metadata sensible: esos
+	// valores no se envían
+	metadata := map[string]string{}
+	for k, v := range sale.Metadata {
+		if v != sales.MetadataMask {
+			metadata[k] = v
+		}
+	}
+	if len(metadata) > 0 {
+		body["metadata"] = metadata
+	}
+	data, _ := jsonenc.Marshal(body)
+	return Request{At: at, Method: http.MethodPost, Path: "/sales", Body: data, Sale: saleID, Creates: true}, created
+}
+
+// statusRequests lleva la venta de from a to: los borradores se envían a
+// revisión antes de cambiar de estado.
+func statusRequests(entry sales.AuditEntry, from, to string) []Request {
+	var requests []Request
+	if from == sales.StatusDraft {
+		requests = append(requests, saleRequest(entry, http.MethodPost, "/submit", nil))
+		from = sales.StatusPending
+	}
+	if to != from {
+		requests = append(requests, saleRequest(entry, http.MethodPatch, "", map[string]string{"status": to}))
+	}
+	return requests
+}
+
+func saleRequest(entry sales.AuditEntry, method, suffix string, body any) Request {
+	var data []byte
+	if body != nil {
+		data, _ = jsonenc.Marshal(body)
+	}
+	return Request{
+		At:     entry.CreatedAt,
+		Method: method,
+		Path:   "/sales/" + salePlaceholder + suffix,
+		Body:   data,
+		Sale:   entry.SaleID,
+	}
+}
+
+// editBody arma el PATCH con los campos editables que cambiaron; el monto se
+// omite si cambian las líneas, porque se recalcula a partir de ellas.
+func editBody(before, after *sales.Sale) map[string]any {
+	edit := map[string]any{}
+	if after.UserID != before.UserID {
+		edit["user_id"] = after.UserID
+	}
+	if !slices.Equal(after.LineItems, before.LineItems) {
+		items := after.LineItems
+		if items == nil {
+			items = []sales.LineItem{}
+		}
+		edit["line_items"] = items
+	} else if after.Amount != before.Amount {
+		edit["amount"] = after.Amount
+	}
+	if after.DiscountPercent != before.DiscountPercent {
+		edit["discount_percent"] = after.DiscountPercent
+	}
+	if after.TaxPercent != before.TaxPercent {
+		edit["tax_percent"] = after.TaxPercent
+	}
+	return edit
+}
@@ -0,0 +1,35 @@
+package sales
+
+// DefaultElevatedRole sees every sale in searches.
+const DefaultElevatedRole = "admin"
+
+// WithElevatedRoles replaces the roles whose searches are not restricted to
+// the caller's own sales.
+func WithElevatedRoles(roles []string) Option {
+	return func(s *Service) {
+		s.elevatedRoles = make(map[string]struct{}, len(roles))
+		for _, r := range roles {
+			s.elevatedRoles[r] = struct{}{}
+		}
+	}
+}
+
+// visibleUsers retorna los usuarios cuyas ventas puede ver el caller, o nil
+// si no hay restricción.
+func (s *Service) visibleUsers(caller *Caller) map[string]struct{} {
+	if caller == nil {
+		return nil
+	}
+	if _, ok := s.elevatedRoles[caller.Role]; ok {
+		return nil
+	}
+
+	visible := make(map[string]struct{}, len(caller.Team)+1)
+	if caller.UserID != "" {
+		visible[caller.UserID] = struct{}{}
+	}
+	for _, id := range caller.Team {
+		visible[id] = struct{}{}
+	}
+	return visible
+}
@@ -0,0 +1,151 @@
+package sales
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Error para ventas que no admiten ajustes
+var ErrNotAdjustable = errors.New("only approved sales can be adjusted")
+
+// Adjustment is a correction linked to an approved sale. The sale itself is
+// never mutated; its effective amount is Amount plus every adjustment.
+type Adjustment struct {
+	ID        string    `json:"id"`
+	SaleID    string    `json:"sale_id"`
+	UserID    string    `json:"user_id"`
+	Amount    float64   `json:"amount"`
+	Reason    string    `json:"reason"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AdjustmentStorage persists adjustments in the order they are appended.
+type AdjustmentStorage interface {
+	Append(adjustment *Adjustment) error
+	GetBySale(saleID string) ([]*Adjustment, error)
+	GetAll() ([]*Adjustment, error)
+}
+
+type LocalAdjustmentStorage struct {
+	mu          sync.RWMutex
+	adjustments []*Adjustment
+}
+
+func NewLocalAdjustmentStorage() *LocalAdjustmentStorage {
+	return &LocalAdjustmentStorage{}
+}
+
+func (l *LocalAdjustmentStorage) Append(adjustment *Adjustment) error {
+	if adjustment.ID == "" {
+		return ErrEmptyID
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.adjustments = append(l.adjustments, adjustment)
+	return nil
+}
+
+// GetBySale retorna los ajustes de una venta en orden cronológico.
+func (l *LocalAdjustmentStorage) GetBySale(saleID string) ([]*Adjustment, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	result := make([]*Adjustment, 0)
+	for _, a := range l.adjustments {
+		if a.SaleID == saleID {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+func (l *LocalAdjustmentStorage) GetAll() ([]*Adjustment, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]*Adjustment(nil), l.adjustments...), nil
+}
+
+// WithAdjustmentStorage sets where adjustments are kept. Defaults to an
+// in-memory LocalAdjustmentStorage.
+func WithAdjustmentStorage(adjustments AdjustmentStorage) Option {
+	return func(s *Service) {
+		s.adjustments = adjustments
+	}
+}
+
+// AdjustSale records a correction against an approved sale. Adjustments are
+// the explicit way to correct sales in closed accounting periods, so the
+// period lock does not apply to them.
+func (s *Service) AdjustSale(saleID string, amount float64, reason, createdBy string) (*Adjustment, error) {
+	sale, err := s.storage.Read(saleID)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	// Los reembolsos quedan en pausa hasta resolver la disputa
+	if sale.Status == StatusDisputed {
+		return nil, ErrSaleDisputed
+	}
+	if sale.Status != StatusApproved {
+		return nil, ErrNotAdjustable
+	}
+
+	amount = roundCents(amount)
+	if amount == 0 {
+		return nil, fmt.Errorf("adjustment amount must not be zero")
+	}
+	if reason == "" {
+		return nil, fmt.Errorf("adjustment reason is required")
+	}
+
+	existing, err := s.adjustments.GetBySale(saleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve adjustments: %w", err)
+	}
+	net := sale.Amount + amount
+	for _, a := range existing {
+		net += a.Amount
+	}
+	if roundCents(net) < 0 {
+		return nil, fmt.Errorf("adjustment would make the sale total negative")
+	}
+
+	adjustment := &Adjustment{
+		ID:        uuid.NewString(),
+		SaleID:    sale.ID,
+		UserID:    sale.UserID,
+		Amount:    amount,
+		Reason:    reason,
+		CreatedBy: createdBy,
+		CreatedAt: utcNow(),
+	}
+	if err := s.adjustments.Append(adjustment); err != nil {
+		s.logger.Error("failed to save adjustment", zap.String("sale_id", sale.ID), zap.Error(err))
+		return nil, fmt.Errorf("failed to save adjustment: %w", err)
+	}
+	s.stats.invalidate()
+	if err := s.summaries.Apply(sale.UserID, 0, amount, time.Time{}); err != nil {
+		s.logger.Error("failed to update user summary", zap.String("user_id", sale.UserID), zap.Error(err))
+	}
+
+	if amount < 0 {
+		s.publishRefunded(sale, adjustment)
+	}
+	s.notify(EventSaleAdjusted, sale)
+	s.logger.Info("sale adjusted", zap.String("sale_id", sale.ID), zap.String("adjustment_id", adjustment.ID), zap.Float64("amount", amount))
+	return adjustment, nil
+}
+
+// GetSaleAdjustments returns the adjustments of a sale.
+func (s *Service) GetSaleAdjustments(saleID string) ([]*Adjustment, error) {
+	if _, err := s.storage.Read(saleID); err != nil {
+		return nil, ErrNotFound
+	}
+	return s.adjustments.GetBySale(saleID)
+}
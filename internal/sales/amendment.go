@@ -0,0 +1,162 @@
+package sales
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"go.uber.org/zap"
+)
+
+// Error para ventas que ya no admiten modificaciones
+var ErrNotEditable = errors.New("sale can no longer be modified")
+
+// Error para campos que solo se pueden cambiar en borradores
+var ErrFieldNotEditable = errors.New("user_id can only be changed on draft sales")
+
+// SaleEdit describes the changes requested on a draft or pending sale. Nil
+// fields are left unchanged. When LineItems is set the amount is derived from
+// them; otherwise Amount replaces the subtotal directly.
+type SaleEdit struct {
+	UserID          *string
+	Amount          *float64
+	LineItems       *[]LineItem
+	DiscountPercent *float64
+	TaxPercent      *float64
+}
+
+// EditSale applies edit to a sale. Drafts can be changed freely; pending sales
+// can have their line items, amount, discount and tax amended, which bumps the
+// version and records an audit entry. Any other status is rejected.
+func (s *Service) EditSale(saleID string, edit SaleEdit) (*Sale, error) {
+	return s.EditSaleAs(saleID, edit, Actor{})
+}
+
+// EditSaleAs is EditSale recording actor in the audit entry.
+func (s *Service) EditSaleAs(saleID string, edit SaleEdit, actor Actor) (*Sale, error) {
+	sale, err := s.storage.Read(saleID)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	action := AuditActionAmended
+	switch sale.Status {
+	case StatusDraft:
+		action = AuditActionDraftEdited
+	case StatusPending:
+		if edit.UserID != nil {
+			return nil, ErrFieldNotEditable
+		}
+	default:
+		return nil, ErrNotEditable
+	}
+
+	if err := s.checkPeriodOpen(sale); err != nil {
+		return nil, err
+	}
+
+	if err := edit.validate(); err != nil {
+		return nil, err
+	}
+
+	before := sale.clone()
+	updated := sale.clone()
+
+	if edit.UserID != nil {
+		updated.UserID = *edit.UserID
+	}
+	subtotal := updated.subtotal()
+	if edit.LineItems != nil {
+		updated.LineItems = append([]LineItem(nil), (*edit.LineItems)...)
+		subtotal = sumLineItems(updated.LineItems)
+	} else if edit.Amount != nil {
+		updated.LineItems = nil
+		subtotal = *edit.Amount
+	}
+	if edit.DiscountPercent != nil {
+		updated.DiscountPercent = *edit.DiscountPercent
+	}
+	if edit.TaxPercent != nil {
+		updated.TaxPercent = *edit.TaxPercent
+	}
+	updated.applyPricing(subtotal)
+
+	if updated.Status == StatusPending && updated.Amount <= 0 {
+		return nil, fmt.Errorf("amount must be greater than zero")
+	}
+
+	updated.UpdatedAt = utcNow()
+	updated.Version++
+
+	if err := s.saveAudited(action, before, updated, actor); err != nil {
+		s.logger.Error("failed to update sale", zap.String("sale_id", sale.ID), zap.Error(err))
+		return nil, err
+	}
+	s.stats.invalidate()
+	s.recordSummary(before, updated)
+
+	s.notify(EventSaleUpdated, updated)
+	s.logger.Info("sale edited", zap.String("sale_id", updated.ID), zap.String("action", action), zap.Int("version", updated.Version))
+	return updated, nil
+}
+
+func (e SaleEdit) validate() error {
+	if e.Amount != nil && *e.Amount < 0 {
+		return fmt.Errorf("amount must not be negative")
+	}
+	if e.LineItems != nil {
+		for _, item := range *e.LineItems {
+			if item.Quantity <= 0 || item.UnitPrice < 0 {
+				return fmt.Errorf("invalid line item")
+			}
+		}
+	}
+	if e.DiscountPercent != nil && (*e.DiscountPercent < 0 || *e.DiscountPercent > 100) {
+		return fmt.Errorf("discount_percent must be between 0 and 100")
+	}
+	if e.TaxPercent != nil && (*e.TaxPercent < 0 || *e.TaxPercent > 100) {
+		return fmt.Errorf("tax_percent must be between 0 and 100")
+	}
+	return nil
+}
+
+// subtotal retorna el importe antes de descuento e impuestos. Las ventas sin
+// desglose usan su monto como subtotal.
+func (s *Sale) subtotal() float64 {
+	if len(s.LineItems) > 0 || s.Subtotal > 0 {
+		return s.Subtotal
+	}
+	return s.Amount
+}
+
+// applyPricing recalcula descuento, impuestos y monto final a partir del subtotal.
+func (s *Sale) applyPricing(subtotal float64) {
+	s.Subtotal = roundCents(subtotal)
+	s.Discount = roundCents(s.Subtotal * s.DiscountPercent / 100)
+	s.Tax = roundCents((s.Subtotal - s.Discount) * s.TaxPercent / 100)
+	s.Amount = roundCents(s.Subtotal - s.Discount + s.Tax)
+}
+
+func (s *Sale) clone() *Sale {
+	c := *s
+	c.LineItems = append([]LineItem(nil), s.LineItems...)
+	if s.Metadata != nil {
+		c.Metadata = make(map[string]string, len(s.Metadata))
+		for k, v := range s.Metadata {
+			c.Metadata[k] = v
+		}
+	}
+	return &c
+}
+
+func sumLineItems(items []LineItem) float64 {
+	total := 0.0
+	for _, item := range items {
+		total += float64(item.Quantity) * item.UnitPrice
+	}
+	return total
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
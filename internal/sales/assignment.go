@@ -0,0 +1,270 @@
+package sales
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultClaimTTL is how long a reviewer keeps a claimed sale without
+// deciding it before the scheduler releases it.
+const DefaultClaimTTL = 30 * time.Minute
+
+// Error para reclamos de ventas que no están pendientes
+var ErrNotPending = errors.New("only pending sales can be claimed")
+
+// Error cuando otro revisor tiene la venta asignada
+var ErrAlreadyClaimed = errors.New("sale is claimed by another reviewer")
+
+// Error para ventas sin asignación
+var ErrClaimNotFound = errors.New("sale is not claimed")
+
+// Assignment gives a reviewer a pending sale to decide until ExpiresAt.
+type Assignment struct {
+	SaleID       string    `json:"sale_id"`
+	Reviewer     string    `json:"reviewer"`
+	AutoAssigned bool      `json:"auto_assigned"`
+	ClaimedAt    time.Time `json:"claimed_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// AssignmentStorage persists the current assignment of each sale.
+type AssignmentStorage interface {
+	Set(a *Assignment) error
+	Read(saleID string) (*Assignment, bool, error)
+	Delete(saleID string) error
+	GetAll() ([]*Assignment, error)
+}
+
+type LocalAssignmentStorage struct {
+	mu sync.RWMutex
+	m  map[string]*Assignment
+}
+
+func NewLocalAssignmentStorage() *LocalAssignmentStorage {
+	return &LocalAssignmentStorage{
+		m: make(map[string]*Assignment),
+	}
+}
+
+func (l *LocalAssignmentStorage) Set(a *Assignment) error {
+	if a.SaleID == "" {
+		return ErrEmptyID
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	c := *a
+	l.m[a.SaleID] = &c
+	return nil
+}
+
+func (l *LocalAssignmentStorage) Read(saleID string) (*Assignment, bool, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	a, ok := l.m[saleID]
+	if !ok {
+		return nil, false, nil
+	}
+	c := *a
+	return &c, true, nil
+}
+
+func (l *LocalAssignmentStorage) Delete(saleID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.m, saleID)
+	return nil
+}
+
+func (l *LocalAssignmentStorage) GetAll() ([]*Assignment, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	result := make([]*Assignment, 0, len(l.m))
+	for _, a := range l.m {
+		c := *a
+		result = append(result, &c)
+	}
+	return result, nil
+}
+
+// WithAssignmentStorage sets where reviewer assignments are kept. Defaults to
+// an in-memory LocalAssignmentStorage.
+func WithAssignmentStorage(assignments AssignmentStorage) Option {
+	return func(s *Service) {
+		s.assignments = assignments
+	}
+}
+
+// WithReviewers auto-assigns every sale that enters pending to reviewers in
+// round-robin order. Claims expire after ttl; zero uses DefaultClaimTTL.
+func WithReviewers(reviewers []string, ttl time.Duration) Option {
+	return func(s *Service) {
+		s.reviewers = append([]string(nil), reviewers...)
+		if ttl > 0 {
+			s.claimTTL = ttl
+		}
+	}
+}
+
+// ClaimSale assigns a pending sale to reviewer. Claiming it again renews the
+// claim; if another reviewer holds an unexpired claim it returns that claim
+// and ErrAlreadyClaimed.
+func (s *Service) ClaimSale(saleID, reviewer string) (*Assignment, error) {
+	sale, err := s.storage.Read(saleID)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	if sale.Status != StatusPending {
+		return nil, ErrNotPending
+	}
+
+	s.assignMu.Lock()
+	defer s.assignMu.Unlock()
+
+	now := utcNow()
+	current, ok, err := s.assignments.Read(saleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read assignment: %w", err)
+	}
+	if ok && current.Reviewer != reviewer && now.Before(current.ExpiresAt) {
+		return current, ErrAlreadyClaimed
+	}
+
+	a := &Assignment{SaleID: saleID, Reviewer: reviewer, ClaimedAt: now, ExpiresAt: now.Add(s.claimTTL)}
+	if ok && current.Reviewer == reviewer {
+		a.ClaimedAt, a.AutoAssigned = current.ClaimedAt, current.AutoAssigned
+	}
+	if err := s.assignments.Set(a); err != nil {
+		s.logger.Error("failed to save assignment", zap.String("sale_id", saleID), zap.Error(err))
+		return nil, fmt.Errorf("failed to save assignment: %w", err)
+	}
+	s.logger.Info("sale claimed", zap.String("sale_id", saleID), zap.String("reviewer", reviewer))
+	return a, nil
+}
+
+// UnclaimSale returns a sale claimed by reviewer to the unassigned pool.
+func (s *Service) UnclaimSale(saleID, reviewer string) error {
+	s.assignMu.Lock()
+	defer s.assignMu.Unlock()
+
+	current, ok, err := s.assignments.Read(saleID)
+	if err != nil {
+		return fmt.Errorf("failed to read assignment: %w", err)
+	}
+	if !ok {
+		return ErrClaimNotFound
+	}
+	if current.Reviewer != reviewer {
+		return ErrAlreadyClaimed
+	}
+	return s.assignments.Delete(saleID)
+}
+
+// ReviewItem is a pending sale in a reviewer's queue.
+type ReviewItem struct {
+	Assignment *Assignment `json:"assignment"`
+	Sale       *Sale       `json:"sale"`
+}
+
+// ReviewQueue returns the pending sales assigned to reviewer, the ones
+// waiting the longest first.
+func (s *Service) ReviewQueue(reviewer string) ([]ReviewItem, error) {
+	assignments, err := s.assignments.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve assignments: %w", err)
+	}
+
+	queue := make([]ReviewItem, 0)
+	for _, a := range assignments {
+		if a.Reviewer != reviewer {
+			continue
+		}
+		sale, err := s.storage.Read(a.SaleID)
+		if err != nil || sale.Status != StatusPending {
+			continue
+		}
+		queue = append(queue, ReviewItem{Assignment: a, Sale: sale})
+	}
+	sort.Slice(queue, func(i, j int) bool {
+		return queue[i].Sale.pendingSince().Before(queue[j].Sale.pendingSince())
+	})
+	return queue, nil
+}
+
+// autoAssign asigna la venta que entró en pending al próximo revisor de la
+// rotación, si hay revisores configurados.
+func (s *Service) autoAssign(sale *Sale) {
+	if len(s.reviewers) == 0 || sale.Status != StatusPending {
+		return
+	}
+
+	s.assignMu.Lock()
+	defer s.assignMu.Unlock()
+	s.assignNext(sale.ID, utcNow())
+}
+
+// assignNext requiere assignMu.
+func (s *Service) assignNext(saleID string, now time.Time) {
+	reviewer := s.reviewers[s.nextReviewer%len(s.reviewers)]
+	s.nextReviewer++
+
+	a := &Assignment{SaleID: saleID, Reviewer: reviewer, AutoAssigned: true, ClaimedAt: now, ExpiresAt: now.Add(s.claimTTL)}
+	if err := s.assignments.Set(a); err != nil {
+		s.logger.Error("failed to auto-assign sale", zap.String("sale_id", saleID), zap.Error(err))
+		return
+	}
+	s.logger.Info("sale auto-assigned", zap.String("sale_id", saleID), zap.String("reviewer", reviewer))
+}
+
+// ReleaseExpiredClaims drops the claims that expired at now and those of sales
+// no longer pending. With auto-assignment, expired sales go to the next
+// reviewer of the rotation. It returns how many claims expired.
+func (s *Service) ReleaseExpiredClaims(now time.Time) (int, error) {
+	assignments, err := s.assignments.GetAll()
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve assignments: %w", err)
+	}
+
+	s.assignMu.Lock()
+	defer s.assignMu.Unlock()
+
+	expired := 0
+	for _, a := range assignments {
+		sale, err := s.storage.Read(a.SaleID)
+		if err != nil || sale.Status != StatusPending {
+			s.assignments.Delete(a.SaleID)
+			continue
+		}
+		// Releído bajo el lock: un reclamo renovado mientras tanto sigue vigente
+		current, ok, err := s.assignments.Read(a.SaleID)
+		if err != nil || !ok || now.Before(current.ExpiresAt) {
+			continue
+		}
+
+		expired++
+		s.logger.Info("sale claim expired", zap.String("sale_id", a.SaleID), zap.String("reviewer", current.Reviewer))
+		if err := s.assignments.Delete(a.SaleID); err != nil {
+			s.logger.Error("failed to release claim", zap.String("sale_id", a.SaleID), zap.Error(err))
+			continue
+		}
+		if len(s.reviewers) > 0 {
+			s.assignNext(a.SaleID, now)
+		}
+	}
+	return expired, nil
+}
+
+// releaseClaim suelta la asignación de una venta ya decidida.
+func (s *Service) releaseClaim(saleID string) {
+	s.assignMu.Lock()
+	defer s.assignMu.Unlock()
+	if err := s.assignments.Delete(saleID); err != nil {
+		s.logger.Warn("failed to release claim of decided sale", zap.String("sale_id", saleID), zap.Error(err))
+	}
+}
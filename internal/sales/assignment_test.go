@@ -0,0 +1,80 @@
+package sales
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// TestClaimSale_ExclusiveUntilExpiry verifica que un reclamo sea exclusivo,
+// que la decisión lo libere y que el vencimiento devuelva la venta a la cola.
+func TestClaimSale_ExclusiveUntilExpiry(t *testing.T) {
+	storage := NewLocalStorage()
+	storage.Set(&Sale{ID: "s1", Status: StatusPending, CreatedAt: utcNow()})
+	storage.Set(&Sale{ID: "s2", Status: StatusPending, CreatedAt: utcNow()})
+	storage.Set(&Sale{ID: "done", Status: StatusApproved, CreatedAt: utcNow()})
+	svc := NewService(storage, zaptest.NewLogger(t), "http://unused", WithReviewers(nil, time.Minute))
+
+	if _, err := svc.ClaimSale("done", "ana"); err != ErrNotPending {
+		t.Errorf("expected ErrNotPending, got %v", err)
+	}
+	if _, err := svc.ClaimSale("s1", "ana"); err != nil {
+		t.Fatalf("ClaimSale returned error: %v", err)
+	}
+	current, err := svc.ClaimSale("s1", "bruno")
+	if err != ErrAlreadyClaimed || current.Reviewer != "ana" {
+		t.Errorf("expected the claim to be held by ana, got %+v, %v", current, err)
+	}
+	if _, err := svc.ClaimSale("s2", "ana"); err != nil {
+		t.Fatalf("ClaimSale returned error: %v", err)
+	}
+
+	if _, err := svc.UpdateSaleStatus("s1", StatusApproved); err != nil {
+		t.Fatalf("UpdateSaleStatus returned error: %v", err)
+	}
+	queue, err := svc.ReviewQueue("ana")
+	if err != nil || len(queue) != 1 || queue[0].Sale.ID != "s2" {
+		t.Fatalf("expected only s2 in ana's queue, got %+v, %v", queue, err)
+	}
+
+	if n, err := svc.ReleaseExpiredClaims(utcNow().Add(2 * time.Minute)); err != nil || n != 1 {
+		t.Errorf("expected one expired claim, got %d, %v", n, err)
+	}
+	if _, err := svc.ClaimSale("s2", "bruno"); err != nil {
+		t.Errorf("expected the expired sale to be claimable, got %v", err)
+	}
+}
+
+// TestAutoAssign_RoundRobin verifica la rotación entre revisores y la
+// reasignación al siguiente cuando vence el reclamo.
+func TestAutoAssign_RoundRobin(t *testing.T) {
+	storage := NewLocalStorage()
+	svc := NewService(storage, zaptest.NewLogger(t), "http://unused", WithReviewers([]string{"ana", "bruno"}, time.Minute))
+	for _, id := range []string{"s1", "s2", "s3"} {
+		sale := &Sale{ID: id, Status: StatusPending, CreatedAt: utcNow()}
+		storage.Set(sale)
+		svc.autoAssign(sale)
+	}
+
+	ana, _ := svc.ReviewQueue("ana")
+	bruno, _ := svc.ReviewQueue("bruno")
+	if len(ana) != 2 || len(bruno) != 1 {
+		t.Fatalf("expected 2 sales for ana and 1 for bruno, got %d and %d", len(ana), len(bruno))
+	}
+
+	// Al vencer, s1 y s3 (de ana) y s2 (de bruno) rotan de nuevo
+	if _, err := svc.ReleaseExpiredClaims(utcNow().Add(2 * time.Minute)); err != nil {
+		t.Fatalf("ReleaseExpiredClaims returned error: %v", err)
+	}
+	ana, _ = svc.ReviewQueue("ana")
+	bruno, _ = svc.ReviewQueue("bruno")
+	if len(ana)+len(bruno) != 3 {
+		t.Errorf("expected every sale reassigned, got %d and %d", len(ana), len(bruno))
+	}
+	for _, item := range append(ana, bruno...) {
+		if !item.Assignment.AutoAssigned {
+			t.Errorf("expected %s to be auto-assigned", item.Sale.ID)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package sales
+
+import (
+	"api_sales/internal/blob"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+const (
+	ScanClean       = "clean"
+	ScanQuarantined = "quarantined"
+	ScanSkipped     = "skipped"
+)
+
+var attachmentScans = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "sales_attachment_scans_total",
+	Help: "Attachment virus scans by result: clean, infected or error.",
+}, []string{"result"})
+
+// Error para adjuntos deshabilitados
+var ErrAttachmentsDisabled = errors.New("attachments are disabled")
+
+// Error para adjuntos inexistentes
+var ErrAttachmentNotFound = errors.New("attachment not found")
+
+// Error para adjuntos vacíos o demasiado grandes
+var ErrAttachmentSize = errors.New("attachment is empty or too large")
+
+// Error para descargas de adjuntos en cuarentena
+var ErrAttachmentQuarantined = errors.New("attachment is quarantined")
+
+// Scanner inspects files before they are stored, e.g. clamav.Client.
+type Scanner interface {
+	// Scan returns the name of the threat found in data, or "" if it is clean.
+	Scan(ctx context.Context, data []byte) (string, error)
+}
+
+// Attachment is a file uploaded to a sale. Files that fail the virus scan,
+// whether infected or because the scanner errored, are kept in quarantine and
+// can't be downloaded.
+type Attachment struct {
+	ID          string     `json:"id"`
+	SaleID      string     `json:"sale_id"`
+	Filename    string     `json:"filename"`
+	ContentType string     `json:"content_type"`
+	Size        int        `json:"size"`
+	SHA256      string     `json:"sha256"`
+	UploadedBy  string     `json:"uploaded_by,omitempty"`
+	UploadedAt  time.Time  `json:"uploaded_at"`
+	ScanStatus  string     `json:"scan_status"`
+	ScanResult  string     `json:"scan_result,omitempty"`
+	ScannedAt   *time.Time `json:"scanned_at,omitempty"`
+}
+
+// blobKey separa los archivos en cuarentena de los descargables.
+func (a *Attachment) blobKey() string {
+	if a.ScanStatus == ScanQuarantined {
+		return "quarantine/" + a.SaleID + "/" + a.ID
+	}
+	return "attachments/" + a.SaleID + "/" + a.ID
+}
+
+// AttachmentStorage persists attachment records; the files are in a blob.Store.
+type AttachmentStorage interface {
+	Set(attachment *Attachment) error
+	Read(id string) (*Attachment, error)
+	GetBySale(saleID string) ([]*Attachment, error)
+}
+
+type LocalAttachmentStorage struct {
+	mu sync.RWMutex
+	m  map[string]*Attachment
+}
+
+func NewLocalAttachmentStorage() *LocalAttachmentStorage {
+	return &LocalAttachmentStorage{
+		m: make(map[string]*Attachment),
+	}
+}
+
+func (l *LocalAttachmentStorage) Set(attachment *Attachment) error {
+	if attachment.ID == "" {
+		return ErrEmptyID
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	c := *attachment
+	l.m[attachment.ID] = &c
+	return nil
+}
+
+func (l *LocalAttachmentStorage) Read(id string) (*Attachment, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	attachment, ok := l.m[id]
+	if !ok {
+		return nil, ErrAttachmentNotFound
+	}
+	c := *attachment
+	return &c, nil
+}
+
+// GetBySale retorna los adjuntos de una venta en orden de subida.
+func (l *LocalAttachmentStorage) GetBySale(saleID string) ([]*Attachment, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	result := make([]*Attachment, 0)
+	for _, attachment := range l.m {
+		if attachment.SaleID == saleID {
+			c := *attachment
+			result = append(result, &c)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].UploadedAt.Before(result[j].UploadedAt) })
+	return result, nil
+}
+
+// WithAttachments enables attachments, storing files of up to maxBytes in
+// store.
+func WithAttachments(store blob.Store, maxBytes int) Option {
+	return func(s *Service) {
+		s.attachmentFiles = store
+		s.attachmentMaxBytes = maxBytes
+	}
+}
+
+// WithAttachmentStorage sets where attachment records are kept. Defaults to
+// an in-memory LocalAttachmentStorage.
+func WithAttachmentStorage(attachments AttachmentStorage) Option {
+	return func(s *Service) {
+		s.attachments = attachments
+	}
+}
+
+// WithScanner scans every attachment before it is stored. Without it
+// attachments are stored unscanned.
+func WithScanner(scanner Scanner) Option {
+	return func(s *Service) {
+		s.scanner = scanner
+	}
+}
+
+// AttachmentsEnabled reports whether attachments can be uploaded.
+func (s *Service) AttachmentsEnabled() bool {
+	return s.attachmentFiles != nil
+}
+
+// AttachmentMaxBytes is the largest attachment accepted.
+func (s *Service) AttachmentMaxBytes() int {
+	return s.attachmentMaxBytes
+}
+
+// AddAttachment scans data and stores it as an attachment of a sale. Files
+// that fail the scan are stored in quarantine; the returned record says so.
+func (s *Service) AddAttachment(ctx context.Context, saleID, filename, contentType string, data []byte, uploadedBy string) (*Attachment, error) {
+	if !s.AttachmentsEnabled() {
+		return nil, ErrAttachmentsDisabled
+	}
+	if _, err := s.storage.Read(saleID); err != nil {
+		return nil, ErrNotFound
+	}
+	if len(data) == 0 || len(data) > s.attachmentMaxBytes {
+		return nil, ErrAttachmentSize
+	}
+
+	sum := sha256.Sum256(data)
+	attachment := &Attachment{
+		ID:          uuid.NewString(),
+		SaleID:      saleID,
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        len(data),
+		SHA256:      hex.EncodeToString(sum[:]),
+		UploadedBy:  uploadedBy,
+		UploadedAt:  utcNow(),
+		ScanStatus:  ScanSkipped,
+	}
+	if s.scanner != nil {
+		s.scanAttachment(ctx, attachment, data)
+	}
+
+	if err := s.attachmentFiles.Put(ctx, attachment.blobKey(), data); err != nil {
+		s.logger.Error("failed to store attachment", zap.String("sale_id", saleID), zap.Error(err))
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+	if err := s.attachments.Set(attachment); err != nil {
+		s.logger.Error("failed to save attachment", zap.String("sale_id", saleID), zap.Error(err))
+		return nil, fmt.Errorf("failed to save attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+// scanAttachment registra el resultado del escaneo; un error del scanner
+// también deja el archivo en cuarentena.
+func (s *Service) scanAttachment(ctx context.Context, attachment *Attachment, data []byte) {
+	threat, err := s.scanner.Scan(ctx, data)
+	now := utcNow()
+	attachment.ScannedAt = &now
+	switch {
+	case err != nil:
+		attachmentScans.WithLabelValues("error").Inc()
+		attachment.ScanStatus = ScanQuarantined
+		attachment.ScanResult = "scan failed"
+		s.logger.Error("failed to scan attachment, quarantined", zap.String("sale_id", attachment.SaleID), zap.String("attachment_id", attachment.ID), zap.Error(err))
+	case threat != "":
+		attachmentScans.WithLabelValues("infected").Inc()
+		attachment.ScanStatus = ScanQuarantined
+		attachment.ScanResult = threat
+		s.logger.Warn("infected attachment quarantined", zap.String("sale_id", attachment.SaleID), zap.String("attachment_id", attachment.ID), zap.String("threat", threat))
+	default:
+		attachmentScans.WithLabelValues("clean").Inc()
+		attachment.ScanStatus = ScanClean
+	}
+}
+
+// GetSaleAttachments returns the attachments of a sale, quarantined ones
+// included, in the order they were uploaded.
+func (s *Service) GetSaleAttachments(saleID string) ([]*Attachment, error) {
+	if !s.AttachmentsEnabled() {
+		return nil, ErrAttachmentsDisabled
+	}
+	if _, err := s.storage.Read(saleID); err != nil {
+		return nil, ErrNotFound
+	}
+	return s.attachments.GetBySale(saleID)
+}
+
+// GetAttachmentFile returns an attachment of a sale with its content.
+// Quarantined files are never returned.
+func (s *Service) GetAttachmentFile(ctx context.Context, saleID, attachmentID string) (*Attachment, []byte, error) {
+	if !s.AttachmentsEnabled() {
+		return nil, nil, ErrAttachmentsDisabled
+	}
+	attachment, err := s.attachments.Read(attachmentID)
+	if err != nil || attachment.SaleID != saleID {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	if attachment.ScanStatus == ScanQuarantined {
+		return attachment, nil, ErrAttachmentQuarantined
+	}
+	data, err := s.attachmentFiles.Get(ctx, attachment.blobKey())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	return attachment, data, nil
+}
@@ -0,0 +1,71 @@
+package sales
+
+import (
+	"api_sales/internal/blob"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+type fakeScanner struct {
+	err error
+}
+
+func (f fakeScanner) Scan(ctx context.Context, data []byte) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	if strings.Contains(string(data), "EICAR") {
+		return "Eicar-Test-Signature", nil
+	}
+	return "", nil
+}
+
+// TestAddAttachment_QuarantinesFailedScans verifica que los archivos
+// infectados o sin poder escanear queden en cuarentena y no se descarguen.
+func TestAddAttachment_QuarantinesFailedScans(t *testing.T) {
+	ctx := context.Background()
+	storage := NewLocalStorage()
+	storage.Set(&Sale{ID: "s1", Status: StatusPending, CreatedAt: utcNow()})
+	files := blob.NewMemoryStore()
+	scanner := &fakeScanner{}
+	svc := NewService(storage, zaptest.NewLogger(t), "http://unused", WithAttachments(files, 16), WithScanner(scanner))
+
+	if _, err := svc.AddAttachment(ctx, "s1", "big.txt", "text/plain", []byte(strings.Repeat("a", 17)), "ana"); err != ErrAttachmentSize {
+		t.Errorf("expected ErrAttachmentSize, got %v", err)
+	}
+
+	clean, err := svc.AddAttachment(ctx, "s1", "invoice.txt", "text/plain", []byte("invoice"), "ana")
+	if err != nil || clean.ScanStatus != ScanClean || clean.ScannedAt == nil {
+		t.Fatalf("expected a clean attachment, got %+v, %v", clean, err)
+	}
+	_, data, err := svc.GetAttachmentFile(ctx, "s1", clean.ID)
+	if err != nil || string(data) != "invoice" {
+		t.Errorf("expected the clean file back, got %q, %v", data, err)
+	}
+
+	infected, err := svc.AddAttachment(ctx, "s1", "virus.txt", "text/plain", []byte("EICAR"), "ana")
+	if err != nil || infected.ScanStatus != ScanQuarantined || infected.ScanResult != "Eicar-Test-Signature" {
+		t.Fatalf("expected a quarantined attachment, got %+v, %v", infected, err)
+	}
+	if _, _, err := svc.GetAttachmentFile(ctx, "s1", infected.ID); err != ErrAttachmentQuarantined {
+		t.Errorf("expected ErrAttachmentQuarantined, got %v", err)
+	}
+	if _, err := files.Get(ctx, "quarantine/s1/"+infected.ID); err != nil {
+		t.Errorf("expected the infected file in quarantine, got %v", err)
+	}
+
+	scanner.err = errors.New("clamd down")
+	failed, err := svc.AddAttachment(ctx, "s1", "report.txt", "text/plain", []byte("report"), "ana")
+	if err != nil || failed.ScanStatus != ScanQuarantined {
+		t.Fatalf("expected a failed scan to quarantine, got %+v, %v", failed, err)
+	}
+
+	all, err := svc.GetSaleAttachments("s1")
+	if err != nil || len(all) != 3 {
+		t.Errorf("expected 3 attachments, got %d, %v", len(all), err)
+	}
+}
@@ -0,0 +1,317 @@
+package sales
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	AuditActionCreated              = "created"
+	AuditActionSubmitted            = "submitted"
+	AuditActionVerificationResolved = "verification_resolved"
+	AuditActionDraftEdited          = "draft_edited"
+	AuditActionAmended              = "amended"
+	AuditActionStatusChanged        = "status_changed"
+	AuditActionERPPosting           = "erp_posting_changed"
+
+	AuditActionDisputed        = "disputed"
+	AuditActionDisputeResolved = "dispute_resolved"
+	AuditActionFulfillment     = "fulfillment_changed"
+)
+
+// AuditEntry records a change made to a sale, with snapshots of the sale
+// before and after the change. Entries form a hash chain: Hash covers the
+// entry content and PrevHash, the Hash of the entry appended before it.
+type AuditEntry struct {
+	ID        string    `json:"id"`
+	SaleID    string    `json:"sale_id"`
+	Action    string    `json:"action"`
+	Version   int       `json:"version"`
+	Before    *Sale     `json:"before,omitempty"`
+	After     *Sale     `json:"after,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// Actor made the change; OnBehalfOf is the impersonated user, if any.
+	Actor      string `json:"actor,omitempty"`
+	OnBehalfOf string `json:"on_behalf_of,omitempty"`
+	PrevHash   string `json:"prev_hash"`
+	Hash       string `json:"hash"`
+}
+
+// ErrAuditChainMoved is returned by Append when the entry does not chain to
+// the last stored entry, because another writer appended first.
+var ErrAuditChainMoved = errors.New("audit chain head moved")
+
+// auditAppendAttempts acota los reintentos de un append que perdió la
+// carrera por la cabeza de la cadena.
+const auditAppendAttempts = 3
+
+// AuditStorage persists audit entries in the order they are appended.
+type AuditStorage interface {
+	// Append stores entry after the last one. It fails with
+	// ErrAuditChainMoved if entry.PrevHash is not the current head.
+	Append(entry *AuditEntry) error
+	// Head returns the Hash of the last entry, or "" if there is none.
+	Head() (string, error)
+	GetBySale(saleID string) ([]*AuditEntry, error)
+	// GetAll returns every entry in append order.
+	GetAll() ([]*AuditEntry, error)
+}
+
+// TxAuditStorage is an AuditStorage kept in the same database as the sales
+// storage. Audited changes then lock the chain head, append the entry and
+// write the sale in one transaction, so every instance extends the same chain.
+type TxAuditStorage interface {
+	AuditStorage
+	// InTx returns the log as seen from tx, a transaction of the sales
+	// storage, where Head also locks the chain head until tx ends. It
+	// returns nil if tx is not a transaction on the same database.
+	InTx(tx Storage) AuditStorage
+}
+
+type LocalAuditStorage struct {
+	mu      sync.RWMutex
+	entries []*AuditEntry
+}
+
+func NewLocalAuditStorage() *LocalAuditStorage {
+	return &LocalAuditStorage{}
+}
+
+func (l *LocalAuditStorage) Append(entry *AuditEntry) error {
+	if entry.ID == "" {
+		return ErrEmptyID
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if entry.PrevHash != l.head() {
+		return ErrAuditChainMoved
+	}
+	l.entries = append(l.entries, entry)
+	return nil
+}
+
+func (l *LocalAuditStorage) Head() (string, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.head(), nil
+}
+
+func (l *LocalAuditStorage) head() string {
+	if len(l.entries) == 0 {
+		return ""
+	}
+	return l.entries[len(l.entries)-1].Hash
+}
+
+// GetBySale retorna las entradas de una venta en orden cronológico.
+func (l *LocalAuditStorage) GetBySale(saleID string) ([]*AuditEntry, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	result := make([]*AuditEntry, 0)
+	for _, e := range l.entries {
+		if e.SaleID == saleID {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+func (l *LocalAuditStorage) GetAll() ([]*AuditEntry, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]*AuditEntry(nil), l.entries...), nil
+}
+
+// GetSaleAudit returns the audit trail of a sale the caller may see, with
+// the snapshots decrypted. Callers not allowed to see sensitive metadata
+// must redact them like any other sale.
+func (s *Service) GetSaleAudit(saleID string, caller *Caller) ([]*AuditEntry, error) {
+	sale, err := s.storage.Read(saleID)
+	if err != nil || !s.canSee(caller, sale) {
+		return nil, ErrNotFound
+	}
+	entries, err := s.audit.GetBySale(saleID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*AuditEntry, len(entries))
+	for i, e := range entries {
+		opened := *e
+		if opened.Before, err = s.openSnapshot(e.Before); err != nil {
+			return nil, err
+		}
+		if opened.After, err = s.openSnapshot(e.After); err != nil {
+			return nil, err
+		}
+		result[i] = &opened
+	}
+	return result, nil
+}
+
+// recordAudit agrega una entrada de auditoría a continuación de la cabeza
+// guardada en el storage de auditoría. Un fallo se registra en el log pero no
+// revierte el cambio ya persistido.
+func (s *Service) recordAudit(entry *AuditEntry) {
+	// Los appends de esta instancia se serializan; si otra agregó primero, la
+	// entrada se vuelve a encadenar sobre la nueva cabeza
+	s.auditMu.Lock()
+	defer s.auditMu.Unlock()
+
+	var err error
+	for attempt := 0; attempt < auditAppendAttempts; attempt++ {
+		if err = appendChained(s.audit, entry); !errors.Is(err, ErrAuditChainMoved) {
+			break
+		}
+	}
+	if err != nil {
+		s.logger.Error("failed to record audit entry", zap.String("sale_id", entry.SaleID), zap.String("action", entry.Action), zap.Error(err))
+	}
+}
+
+// appendChained encadena entry a la cabeza actual de audit y la agrega.
+func appendChained(audit AuditStorage, entry *AuditEntry) error {
+	head, err := audit.Head()
+	if err != nil {
+		return err
+	}
+	entry.PrevHash = head
+	entry.Hash = auditHash(entry)
+	return audit.Append(entry)
+}
+
+// newAuditEntry arma la entrada con los snapshots cifrados como en el
+// storage de ventas: el hash cubre la metadata sensible cifrada.
+func (s *Service) newAuditEntry(action string, before, after *Sale, actor Actor) (*AuditEntry, error) {
+	entry := &AuditEntry{
+		ID:         uuid.NewString(),
+		SaleID:     after.ID,
+		Action:     action,
+		Version:    after.Version,
+		CreatedAt:  utcNow(),
+		Actor:      actor.ID,
+		OnBehalfOf: actor.OnBehalfOf,
+	}
+	var err error
+	if before != nil {
+		if entry.Before, err = s.encryptSensitive(before); err != nil {
+			return nil, err
+		}
+	}
+	if entry.After, err = s.encryptSensitive(after); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *Service) openSnapshot(sale *Sale) (*Sale, error) {
+	if sale == nil {
+		return nil, nil
+	}
+	return s.decryptSensitive(sale)
+}
+
+// saveAudited guarda updated y su entrada de auditoría. Si el storage de
+// auditoría comparte la base de las ventas, la cabeza de la cadena se lee y
+// bloquea dentro de la transacción que escribe la venta, y todo se confirma
+// junto. Si no, la entrada se agrega solo si la transacción se confirma: un
+// rollback, o un reintento de la transacción, no deja entradas ni avanza la
+// cadena.
+func (s *Service) saveAudited(action string, before, updated *Sale, actor Actor) error {
+	entry, err := s.newAuditEntry(action, before, updated, actor)
+	if err != nil {
+		return fmt.Errorf("failed to build audit entry: %w", err)
+	}
+	appended := false
+	if err := s.storage.WithTx(context.Background(), func(tx Storage) error {
+		appended = false
+		if audit := s.auditIn(tx); audit != nil {
+			if err := appendChained(audit, entry); err != nil {
+				return fmt.Errorf("failed to record audit entry: %w", err)
+			}
+			appended = true
+		}
+		return tx.Set(updated)
+	}); err != nil {
+		return err
+	}
+	if !appended {
+		s.recordAudit(entry)
+	}
+	return nil
+}
+
+// auditIn retorna el storage de auditoría dentro de tx, o nil si no puede
+// escribir en esa transacción.
+func (s *Service) auditIn(tx Storage) AuditStorage {
+	if audit, ok := s.audit.(TxAuditStorage); ok {
+		return audit.InTx(tx)
+	}
+	return nil
+}
+
+// Actor identifies who performs an audited change. OnBehalfOf is set when an
+// admin impersonates a user.
+type Actor struct {
+	ID         string
+	OnBehalfOf string
+}
+
+// auditHash calcula el hash de la entrada sin su campo Hash. Usa
+// encoding/json y no jsonenc para que el resultado no dependa del build tag.
+func auditHash(entry *AuditEntry) string {
+	unhashed := *entry
+	unhashed.Hash = ""
+	b, _ := json.Marshal(unhashed)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditVerification is the result of checking the audit hash chain.
+type AuditVerification struct {
+	Valid    bool   `json:"valid"`
+	Entries  int    `json:"entries"`
+	BrokenAt string `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// VerifyAuditChain checks that every entry links to the previous one and that
+// its content still matches its hash.
+func VerifyAuditChain(entries []*AuditEntry) AuditVerification {
+	prev := ""
+	for i, e := range entries {
+		if e.PrevHash != prev {
+			return AuditVerification{Entries: i, BrokenAt: e.ID, Reason: "previous hash mismatch"}
+		}
+		if auditHash(e) != e.Hash {
+			return AuditVerification{Entries: i, BrokenAt: e.ID, Reason: "content hash mismatch"}
+		}
+		prev = e.Hash
+	}
+	return AuditVerification{Valid: true, Entries: len(entries)}
+}
+
+// ExportAudit returns the whole audit log in append order, as stored: with
+// field encryption the sensitive metadata of the snapshots stays encrypted.
+func (s *Service) ExportAudit() ([]*AuditEntry, error) {
+	return s.audit.GetAll()
+}
+
+// VerifyAudit verifies the hash chain of the stored audit log.
+func (s *Service) VerifyAudit() (AuditVerification, error) {
+	entries, err := s.audit.GetAll()
+	if err != nil {
+		return AuditVerification{}, err
+	}
+	return VerifyAuditChain(entries), nil
+}
@@ -0,0 +1,102 @@
+package sales
+
+import (
+	"api_sales/internal/dispatch"
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var searchCanaryComparisons = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "sales_search_canary_comparisons_total",
+	Help: "Searches filtered by the storage, by how they compared to the service filter.",
+}, []string{"result"})
+
+var searchCanaryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "sales_search_canary_duration_seconds",
+	Help:    "Read and filter latency of canaried searches by code path.",
+	Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+}, []string{"path"})
+
+// WithSearchCanary serves percent of the searches, e.g. 5, through the
+// storage-filter path: storages that implement FilteringReader filter by
+// user and status themselves instead of returning every sale. Each canaried
+// search is repeated on pool through the service filter and the results are
+// compared, logging every divergence with the latency of both paths. A
+// failing canary search falls back to the service filter. Searches AsOf an
+// instant are never canaried.
+func WithSearchCanary(percent float64, pool *dispatch.Pool) Option {
+	return func(s *Service) {
+		s.searchCanaryPercent = percent
+		s.searchCanaryPool = pool
+	}
+}
+
+// searchCanaried sortea si la búsqueda va por el filtro en el storage.
+func (s *Service) searchCanaried(filter SearchFilter) bool {
+	if s.searchCanaryPercent <= 0 || s.searchCanaryPool == nil || filter.AsOf != nil {
+		return false
+	}
+	return s.random.IntN(10000) < int(s.searchCanaryPercent*100)
+}
+
+// compareSearch repite la búsqueda por el filtro del servicio y la compara
+// por IDs y versiones con got. Una escritura entre ambas lecturas también
+// diverge: se loguean las versiones para distinguirlo.
+func (s *Service) compareSearch(filter SearchFilter, got []*Sale, elapsed time.Duration) {
+	searchCanaryDuration.WithLabelValues("storage_filter").Observe(elapsed.Seconds())
+	found := make(map[string]int, len(got))
+	for _, sale := range got {
+		found[sale.ID] = sale.Version
+	}
+
+	err := s.searchCanaryPool.Submit(func(context.Context) {
+		start := time.Now()
+		all, err := s.searchStorage(filter)
+		if err != nil {
+			searchCanaryComparisons.WithLabelValues("error").Inc()
+			s.logger.Warn("service filter search failed during canary comparison", zap.Error(err))
+			return
+		}
+		want, _ := s.filterSearch(all, filter)
+		took := time.Since(start)
+		searchCanaryDuration.WithLabelValues("service_filter").Observe(took.Seconds())
+
+		var missing, extra, stale []string
+		seen := make(map[string]bool, len(want))
+		for _, sale := range want {
+			seen[sale.ID] = true
+			version, ok := found[sale.ID]
+			switch {
+			case !ok:
+				missing = append(missing, sale.ID)
+			case version != sale.Version:
+				stale = append(stale, sale.ID)
+			}
+		}
+		for id := range found {
+			if !seen[id] {
+				extra = append(extra, id)
+			}
+		}
+		if len(missing) > 0 || len(extra) > 0 || len(stale) > 0 {
+			searchCanaryComparisons.WithLabelValues("mismatch").Inc()
+			s.logger.Warn("canary search diverged from the service filter",
+				zap.Any("filters", filter.shapeFields()),
+				zap.Strings("missing", missing),
+				zap.Strings("extra", extra),
+				zap.Strings("stale", stale),
+				zap.Duration("storage_filter_latency", elapsed),
+				zap.Duration("service_filter_latency", took),
+			)
+			return
+		}
+		searchCanaryComparisons.WithLabelValues("match").Inc()
+	})
+	if err != nil {
+		searchCanaryComparisons.WithLabelValues("dropped").Inc()
+	}
+}
@@ -0,0 +1,60 @@
+package sales
+
+import (
+	"api_sales/internal/dispatch"
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// lossyStorage simula un filtro en el storage que pierde las ventas grandes.
+type lossyStorage struct {
+	*LocalStorage
+}
+
+func (l lossyStorage) Query(q SaleQuery) ([]*Sale, error) {
+	found, err := l.LocalStorage.Query(q)
+	kept := make([]*Sale, 0)
+	for _, sale := range found {
+		if sale.Amount < 100 {
+			kept = append(kept, sale)
+		}
+	}
+	return kept, err
+}
+
+// TestSearchCanary_LogsDivergencesFromTheServiceFilter verifica que el canary
+// sirve el resultado del storage y loguea lo que difiere del filtro del servicio.
+func TestSearchCanary_LogsDivergencesFromTheServiceFilter(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	pool := dispatch.NewPool("test-canary", 1, 10, nil)
+	storage := lossyStorage{NewLocalStorage()}
+	storage.Set(&Sale{ID: "small", UserID: "u1", Amount: 10, Status: StatusPending})
+	storage.Set(&Sale{ID: "large", UserID: "u1", Amount: 500, Status: StatusPending})
+	storage.Set(&Sale{ID: "other", UserID: "u2", Amount: 20, Status: StatusApproved})
+	svc := NewService(storage, zap.New(core), "http://unused", WithSearchCanary(100, pool))
+
+	results, _, err := svc.SearchSale(SearchFilter{Status: StatusPending})
+	if err != nil {
+		t.Fatalf("SearchSale returned error: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "small" {
+		t.Errorf("expected the canaried result, got %+v", results)
+	}
+	if _, _, err := svc.SearchSale(SearchFilter{Status: StatusApproved}); err != nil {
+		t.Fatalf("SearchSale returned error: %v", err)
+	}
+	if err := pool.Close(context.Background()); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	divergences := logs.FilterMessage("canary search diverged from the service filter").All()
+	if len(divergences) != 1 {
+		t.Fatalf("expected one divergence, got %v", divergences)
+	}
+	if missing := divergences[0].ContextMap()["missing"]; len(missing.([]interface{})) != 1 || missing.([]interface{})[0] != "large" {
+		t.Errorf("expected the large sale reported missing, got %v", missing)
+	}
+}
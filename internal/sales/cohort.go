@@ -0,0 +1,144 @@
+package sales
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Cohort groups the users whose first purchase was in the same month.
+type Cohort struct {
+	Month string `json:"month"`
+	Users int    `json:"users"`
+	// RepeatPurchaseRate is the share of the cohort that bought more than once.
+	RepeatPurchaseRate float64       `json:"repeat_purchase_rate"`
+	Revenue            float64       `json:"revenue"`
+	Months             []CohortMonth `json:"months"`
+}
+
+// CohortMonth is the activity of a cohort in the month Offset months after
+// its first purchase.
+type CohortMonth struct {
+	Offset      int    `json:"offset"`
+	Month       string `json:"month"`
+	ActiveUsers int    `json:"active_users"`
+	// RetentionRate is ActiveUsers over the size of the cohort.
+	RetentionRate float64 `json:"retention_rate"`
+	Purchases     int     `json:"purchases"`
+	Revenue       float64 `json:"revenue"`
+}
+
+// purchaseStatuses son los estados que cuentan como compra: aprobada, aunque
+// después se haya disputado.
+var purchaseStatuses = map[string]struct{}{StatusApproved: {}, StatusDisputed: {}}
+
+// Cohorts groups users by the month of their first purchase in loc and
+// reports, for every later month up to the last one with purchases, how many
+// of them bought again and the revenue they brought. Oldest cohort first.
+func (s *Service) Cohorts(loc *time.Location) ([]Cohort, error) {
+	allSales, err := s.storage.GetAll()
+	if err != nil {
+		s.logger.Error("Failed to get all sales from storage", zap.Error(err))
+		return nil, fmt.Errorf("failed to retrieve sales: %w", err)
+	}
+
+	purchases := make(map[string][]*Sale)
+	var last time.Time
+	for _, sale := range allSales {
+		if _, ok := purchaseStatuses[sale.Status]; !ok || sale.UserID == "" {
+			continue
+		}
+		purchases[sale.UserID] = append(purchases[sale.UserID], sale)
+		if sale.CreatedAt.After(last) {
+			last = sale.CreatedAt
+		}
+	}
+	lastMonth := monthStart(last, loc)
+
+	type activity struct {
+		users     map[string]struct{}
+		purchases int
+		revenue   float64
+	}
+	type cohortData struct {
+		start    time.Time
+		users    int
+		repeat   int
+		revenue  float64
+		byOffset map[int]*activity
+	}
+	cohorts := make(map[time.Time]*cohortData)
+	for userID, userSales := range purchases {
+		first := userSales[0].CreatedAt
+		for _, sale := range userSales {
+			if sale.CreatedAt.Before(first) {
+				first = sale.CreatedAt
+			}
+		}
+		start := monthStart(first, loc)
+		c, ok := cohorts[start]
+		if !ok {
+			c = &cohortData{start: start, byOffset: make(map[int]*activity)}
+			cohorts[start] = c
+		}
+		c.users++
+		if len(userSales) > 1 {
+			c.repeat++
+		}
+		for _, sale := range userSales {
+			offset := monthsBetween(start, monthStart(sale.CreatedAt, loc))
+			a, ok := c.byOffset[offset]
+			if !ok {
+				a = &activity{users: make(map[string]struct{})}
+				c.byOffset[offset] = a
+			}
+			a.users[userID] = struct{}{}
+			a.purchases++
+			a.revenue += sale.Amount
+			c.revenue += sale.Amount
+		}
+	}
+
+	result := make([]Cohort, 0, len(cohorts))
+	for _, c := range cohorts {
+		cohort := Cohort{
+			Month:              c.start.Format(periodLayout),
+			Users:              c.users,
+			RepeatPurchaseRate: roundRate(float64(c.repeat) / float64(c.users)),
+			Revenue:            roundCents(c.revenue),
+			Months:             make([]CohortMonth, 0),
+		}
+		// Los meses sin compras también se reportan, con actividad cero
+		for offset := 0; offset <= monthsBetween(c.start, lastMonth); offset++ {
+			month := CohortMonth{Offset: offset, Month: c.start.AddDate(0, offset, 0).Format(periodLayout)}
+			if a, ok := c.byOffset[offset]; ok {
+				month.ActiveUsers = len(a.users)
+				month.RetentionRate = roundRate(float64(len(a.users)) / float64(c.users))
+				month.Purchases = a.purchases
+				month.Revenue = roundCents(a.revenue)
+			}
+			cohort.Months = append(cohort.Months, month)
+		}
+		result = append(result, cohort)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Month < result[j].Month })
+	return result, nil
+}
+
+// monthStart retorna el primer instante del mes de t en loc.
+func monthStart(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, loc)
+}
+
+func monthsBetween(from, to time.Time) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+}
+
+// roundRate redondea una proporción a cuatro decimales.
+func roundRate(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
@@ -0,0 +1,50 @@
+package sales
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// TestCohorts_GroupsByFirstPurchaseMonth verifica la agrupación por mes de la
+// primera compra, la recompra y la retención en los meses siguientes.
+func TestCohorts_GroupsByFirstPurchaseMonth(t *testing.T) {
+	storage := NewLocalStorage()
+	jan := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	storage.Set(&Sale{ID: "a1", UserID: "a", Amount: 10, Status: StatusApproved, CreatedAt: jan})
+	storage.Set(&Sale{ID: "a2", UserID: "a", Amount: 20, Status: StatusApproved, CreatedAt: jan.AddDate(0, 2, 0)})
+	storage.Set(&Sale{ID: "b1", UserID: "b", Amount: 5, Status: StatusApproved, CreatedAt: jan.Add(time.Hour)})
+	storage.Set(&Sale{ID: "c1", UserID: "c", Amount: 7, Status: StatusApproved, CreatedAt: jan.AddDate(0, 1, 0)})
+	// Las ventas no aprobadas no cuentan como compra
+	storage.Set(&Sale{ID: "b2", UserID: "b", Amount: 100, Status: StatusRejected, CreatedAt: jan.AddDate(0, 1, 0)})
+	svc := NewService(storage, zaptest.NewLogger(t), "http://unused")
+
+	cohorts, err := svc.Cohorts(time.UTC)
+	if err != nil {
+		t.Fatalf("Cohorts returned error: %v", err)
+	}
+	if len(cohorts) != 2 || cohorts[0].Month != "2024-01" || cohorts[1].Month != "2024-02" {
+		t.Fatalf("expected the January and February cohorts, got %+v", cohorts)
+	}
+
+	january := cohorts[0]
+	if january.Users != 2 || january.RepeatPurchaseRate != 0.5 || january.Revenue != 35 {
+		t.Errorf("unexpected January cohort totals: %+v", january)
+	}
+	if len(january.Months) != 3 {
+		t.Fatalf("expected months 0 to 2 for January, got %+v", january.Months)
+	}
+	if m := january.Months[0]; m.ActiveUsers != 2 || m.RetentionRate != 1 || m.Revenue != 15 {
+		t.Errorf("unexpected month 0: %+v", m)
+	}
+	if m := january.Months[1]; m.ActiveUsers != 0 || m.Purchases != 0 {
+		t.Errorf("expected no activity in month 1, got %+v", m)
+	}
+	if m := january.Months[2]; m.Month != "2024-03" || m.ActiveUsers != 1 || m.RetentionRate != 0.5 || m.Revenue != 20 {
+		t.Errorf("unexpected month 2: %+v", m)
+	}
+	if february := cohorts[1]; february.Users != 1 || february.RepeatPurchaseRate != 0 || len(february.Months) != 2 {
+		t.Errorf("unexpected February cohort: %+v", february)
+	}
+}
@@ -0,0 +1,234 @@
+package sales
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	EventSaleCommentAdded   = "sale.comment_added"
+	EventSaleCommentUpdated = "sale.comment_updated"
+	EventSaleCommentDeleted = "sale.comment_deleted"
+)
+
+// MaxCommentLength is the longest comment body accepted, in characters.
+const MaxCommentLength = 5000
+
+// Error para comentarios inexistentes
+var ErrCommentNotFound = errors.New("comment not found")
+
+// Error para ediciones de comentarios ajenos
+var ErrNotCommentAuthor = errors.New("only the author can change a comment")
+
+// Error para comentarios vacíos o demasiado largos
+var ErrInvalidComment = errors.New("comment body must have between 1 and 5000 characters")
+
+// Comment is a message in the discussion thread of a sale. Mentions are the
+// @names found in Body.
+type Comment struct {
+	ID        string     `json:"id"`
+	SaleID    string     `json:"sale_id"`
+	Author    string     `json:"author"`
+	Body      string     `json:"body"`
+	Mentions  []string   `json:"mentions,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// CommentStorage persists comments.
+type CommentStorage interface {
+	Set(comment *Comment) error
+	Read(id string) (*Comment, error)
+	Delete(id string) error
+	GetBySale(saleID string) ([]*Comment, error)
+}
+
+type LocalCommentStorage struct {
+	mu sync.RWMutex
+	m  map[string]*Comment
+}
+
+func NewLocalCommentStorage() *LocalCommentStorage {
+	return &LocalCommentStorage{
+		m: make(map[string]*Comment),
+	}
+}
+
+func (l *LocalCommentStorage) Set(comment *Comment) error {
+	if comment.ID == "" {
+		return ErrEmptyID
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	c := *comment
+	c.Mentions = append([]string(nil), comment.Mentions...)
+	l.m[comment.ID] = &c
+	return nil
+}
+
+func (l *LocalCommentStorage) Read(id string) (*Comment, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	comment, ok := l.m[id]
+	if !ok {
+		return nil, ErrCommentNotFound
+	}
+	c := *comment
+	return &c, nil
+}
+
+func (l *LocalCommentStorage) Delete(id string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.m[id]; !ok {
+		return ErrCommentNotFound
+	}
+	delete(l.m, id)
+	return nil
+}
+
+// GetBySale retorna los comentarios de una venta en orden cronológico.
+func (l *LocalCommentStorage) GetBySale(saleID string) ([]*Comment, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	result := make([]*Comment, 0)
+	for _, comment := range l.m {
+		if comment.SaleID == saleID {
+			c := *comment
+			result = append(result, &c)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result, nil
+}
+
+// WithCommentStorage sets where comments are kept. Defaults to an in-memory
+// LocalCommentStorage.
+func WithCommentStorage(comments CommentStorage) Option {
+	return func(s *Service) {
+		s.comments = comments
+	}
+}
+
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([\w.-]*\w)`)
+
+// parseMentions retorna los @nombres del texto, sin repetir y en orden.
+func parseMentions(body string) []string {
+	mentions := make([]string, 0)
+	seen := make(map[string]struct{})
+	for _, m := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		if _, ok := seen[m[1]]; ok {
+			continue
+		}
+		seen[m[1]] = struct{}{}
+		mentions = append(mentions, m[1])
+	}
+	return mentions
+}
+
+func validCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || len([]rune(body)) > MaxCommentLength {
+		return "", ErrInvalidComment
+	}
+	return body, nil
+}
+
+// AddComment adds a comment by author to the thread of a sale.
+func (s *Service) AddComment(saleID, author, body string) (*Comment, error) {
+	sale, err := s.storage.Read(saleID)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	if body, err = validCommentBody(body); err != nil {
+		return nil, err
+	}
+
+	comment := &Comment{
+		ID:        uuid.NewString(),
+		SaleID:    saleID,
+		Author:    author,
+		Body:      body,
+		Mentions:  parseMentions(body),
+		CreatedAt: utcNow(),
+	}
+	if err := s.comments.Set(comment); err != nil {
+		s.logger.Error("failed to save comment", zap.String("sale_id", saleID), zap.Error(err))
+		return nil, fmt.Errorf("failed to save comment: %w", err)
+	}
+	s.notifyComment(EventSaleCommentAdded, sale, comment)
+	return comment, nil
+}
+
+// GetSaleComments returns the thread of a sale, oldest comment first.
+func (s *Service) GetSaleComments(saleID string) ([]*Comment, error) {
+	if _, err := s.storage.Read(saleID); err != nil {
+		return nil, ErrNotFound
+	}
+	return s.comments.GetBySale(saleID)
+}
+
+// UpdateComment replaces the body of a comment. Only its author can edit it.
+func (s *Service) UpdateComment(saleID, commentID, author, body string) (*Comment, error) {
+	sale, comment, err := s.saleComment(saleID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Author != author {
+		return nil, ErrNotCommentAuthor
+	}
+	if body, err = validCommentBody(body); err != nil {
+		return nil, err
+	}
+
+	now := utcNow()
+	comment.Body = body
+	comment.Mentions = parseMentions(body)
+	comment.UpdatedAt = &now
+	if err := s.comments.Set(comment); err != nil {
+		s.logger.Error("failed to save comment", zap.String("comment_id", commentID), zap.Error(err))
+		return nil, fmt.Errorf("failed to save comment: %w", err)
+	}
+	s.notifyComment(EventSaleCommentUpdated, sale, comment)
+	return comment, nil
+}
+
+// DeleteComment removes a comment. Only its author can delete it unless
+// moderate is set, e.g. for admins.
+func (s *Service) DeleteComment(saleID, commentID, author string, moderate bool) error {
+	sale, comment, err := s.saleComment(saleID, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.Author != author && !moderate {
+		return ErrNotCommentAuthor
+	}
+	if err := s.comments.Delete(commentID); err != nil {
+		return err
+	}
+	s.notifyComment(EventSaleCommentDeleted, sale, comment)
+	return nil
+}
+
+// saleComment lee un comentario verificando que pertenezca a la venta.
+func (s *Service) saleComment(saleID, commentID string) (*Sale, *Comment, error) {
+	sale, err := s.storage.Read(saleID)
+	if err != nil {
+		return nil, nil, ErrNotFound
+	}
+	comment, err := s.comments.Read(commentID)
+	if err != nil || comment.SaleID != saleID {
+		return nil, nil, ErrCommentNotFound
+	}
+	return sale, comment, nil
+}
@@ -0,0 +1,80 @@
+package sales
+
+import (
+	"slices"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+type commentRecorder struct {
+	recordingNotifier
+	comments []*Comment
+}
+
+func (r *commentRecorder) NotifyComment(eventType string, sale *Sale, comment *Comment) {
+	r.events = append(r.events, eventType+":"+sale.ID)
+	r.comments = append(r.comments, comment)
+}
+
+// TestComments_AuthorRulesAndEvents verifica las menciones, que solo el autor
+// edite y borre salvo moderación, y los eventos de cada cambio.
+func TestComments_AuthorRulesAndEvents(t *testing.T) {
+	storage := NewLocalStorage()
+	storage.Set(&Sale{ID: "s1", Status: StatusPending, CreatedAt: utcNow()})
+	plain := &recordingNotifier{}
+	recorder := &commentRecorder{}
+	svc := NewService(storage, zaptest.NewLogger(t), "http://unused", WithNotifier(plain), WithNotifier(recorder))
+
+	if _, err := svc.AddComment("missing", "ana", "hola"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := svc.AddComment("s1", "ana", "   "); err != ErrInvalidComment {
+		t.Errorf("expected ErrInvalidComment, got %v", err)
+	}
+
+	comment, err := svc.AddComment("s1", "ana", "@bruno can you check this? cc @carla.m, @bruno, x@example.com")
+	if err != nil {
+		t.Fatalf("AddComment returned error: %v", err)
+	}
+	if want := []string{"bruno", "carla.m"}; !slices.Equal(comment.Mentions, want) {
+		t.Errorf("expected mentions %v, got %v", want, comment.Mentions)
+	}
+
+	if _, err := svc.UpdateComment("s1", comment.ID, "bruno", "edited"); err != ErrNotCommentAuthor {
+		t.Errorf("expected ErrNotCommentAuthor, got %v", err)
+	}
+	updated, err := svc.UpdateComment("s1", comment.ID, "ana", "never mind")
+	if err != nil || updated.UpdatedAt == nil || len(updated.Mentions) != 0 {
+		t.Fatalf("expected an edited comment without mentions, got %+v, %v", updated, err)
+	}
+
+	second, _ := svc.AddComment("s1", "bruno", "ok")
+	comments, err := svc.GetSaleComments("s1")
+	if err != nil || len(comments) != 2 || comments[0].ID != comment.ID || comments[0].Body != "never mind" {
+		t.Fatalf("expected both comments oldest first, got %+v, %v", comments, err)
+	}
+
+	if err := svc.DeleteComment("s1", second.ID, "ana", false); err != ErrNotCommentAuthor {
+		t.Errorf("expected ErrNotCommentAuthor, got %v", err)
+	}
+	if err := svc.DeleteComment("s1", second.ID, "admin", true); err != nil {
+		t.Fatalf("DeleteComment returned error: %v", err)
+	}
+	if err := svc.DeleteComment("s1", second.ID, "bruno", false); err != ErrCommentNotFound {
+		t.Errorf("expected ErrCommentNotFound, got %v", err)
+	}
+
+	want := []string{
+		EventSaleCommentAdded + ":s1",
+		EventSaleCommentUpdated + ":s1",
+		EventSaleCommentAdded + ":s1",
+		EventSaleCommentDeleted + ":s1",
+	}
+	if !slices.Equal(recorder.events, want) || !slices.Equal(plain.events, want) {
+		t.Errorf("expected events %v, got %v and %v", want, recorder.events, plain.events)
+	}
+	if recorder.comments[0].Body == "never mind" {
+		t.Errorf("expected the notified comment to be a copy")
+	}
+}
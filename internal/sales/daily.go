@@ -0,0 +1,48 @@
+package sales
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DailyTotal aggregates the sales created on one calendar day of the report's
+// timezone.
+type DailyTotal struct {
+	Date        string  `json:"date"`
+	Count       int     `json:"count"`
+	TotalAmount float64 `json:"total_amount"`
+}
+
+// DailyTotals buckets every stored sale by the day it was created in loc,
+// oldest day first. Timestamps are stored in UTC, so the same sale may fall on
+// a different day depending on loc.
+func (s *Service) DailyTotals(loc *time.Location) ([]DailyTotal, error) {
+	allSales, err := s.storage.GetAll()
+	if err != nil {
+		s.logger.Error("Failed to get all sales from storage", zap.Error(err))
+		return nil, fmt.Errorf("failed to retrieve sales: %w", err)
+	}
+
+	byDay := make(map[string]*DailyTotal)
+	for _, sale := range allSales {
+		day := sale.CreatedAt.In(loc).Format(time.DateOnly)
+		total, ok := byDay[day]
+		if !ok {
+			total = &DailyTotal{Date: day}
+			byDay[day] = total
+		}
+		total.Count++
+		total.TotalAmount += sale.Amount
+	}
+
+	result := make([]DailyTotal, 0, len(byDay))
+	for _, total := range byDay {
+		total.TotalAmount = roundCents(total.TotalAmount)
+		result = append(result, *total)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Date < result[j].Date })
+	return result, nil
+}
@@ -0,0 +1,49 @@
+package sales
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// TestDailyTotals_BucketsByTimezone verifica que una venta cerca de la
+// medianoche UTC caiga en el día local de la zona pedida.
+func TestDailyTotals_BucketsByTimezone(t *testing.T) {
+	storage := NewLocalStorage()
+	svc := NewService(storage, zaptest.NewLogger(t), "http://unused")
+	storage.Set(&Sale{ID: "s1", Amount: 10, Status: StatusApproved, CreatedAt: time.Date(2024, 5, 2, 2, 0, 0, 0, time.UTC)})
+	storage.Set(&Sale{ID: "s2", Amount: 5, Status: StatusApproved, CreatedAt: time.Date(2024, 5, 2, 15, 0, 0, 0, time.UTC)})
+
+	utc, err := svc.DailyTotals(time.UTC)
+	if err != nil {
+		t.Fatalf("DailyTotals returned error: %v", err)
+	}
+	if len(utc) != 1 || utc[0].Date != "2024-05-02" || utc[0].Count != 2 {
+		t.Errorf("expected both sales on 2024-05-02 UTC, got %+v", utc)
+	}
+
+	santiago, err := time.LoadLocation("America/Santiago")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	local, err := svc.DailyTotals(santiago)
+	if err != nil {
+		t.Fatalf("DailyTotals returned error: %v", err)
+	}
+	if len(local) != 2 || local[0].Date != "2024-05-01" || local[0].TotalAmount != 10 {
+		t.Errorf("expected the early sale on the local previous day, got %+v", local)
+	}
+}
+
+func TestCreateSale_StoresUTC(t *testing.T) {
+	storage := NewLocalStorage()
+	svc := NewService(storage, zaptest.NewLogger(t), "http://unused")
+	sale, err := svc.CreateDraftSale("u1", 10)
+	if err != nil {
+		t.Fatalf("CreateDraftSale returned error: %v", err)
+	}
+	if sale.CreatedAt.Location() != time.UTC || sale.UpdatedAt.Location() != time.UTC {
+		t.Errorf("expected UTC timestamps, got %v / %v", sale.CreatedAt.Location(), sale.UpdatedAt.Location())
+	}
+}
@@ -0,0 +1,251 @@
+package sales
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Error para disputas inexistentes
+var ErrDisputeNotFound = errors.New("dispute not found")
+
+// Error para ventas que no se pueden disputar
+var ErrNotDisputable = errors.New("only approved sales can be disputed")
+
+// Error para disputas ya resueltas
+var ErrDisputeResolved = errors.New("dispute is already resolved")
+
+// Error para resultados de disputa desconocidos
+var ErrInvalidOutcome = errors.New("invalid dispute outcome: expected won or lost")
+
+// Error para ajustes sobre ventas con una disputa abierta
+var ErrSaleDisputed = errors.New("sale has an open dispute")
+
+// Dispute statuses. A dispute opens when the buyer contests the sale and is
+// won (the sale stands) or lost (the sale is charged back).
+const (
+	DisputeOpen = "open"
+	DisputeWon  = "won"
+	DisputeLost = "lost"
+)
+
+// Dispute is a chargeback claim against an approved sale. While it is open
+// the sale is disputed and accepts no adjustments.
+type Dispute struct {
+	ID         string     `json:"id"`
+	SaleID     string     `json:"sale_id"`
+	UserID     string     `json:"user_id"`
+	Amount     float64    `json:"amount"`
+	Reason     string     `json:"reason"`
+	Status     string     `json:"status"`
+	OpenedBy   string     `json:"opened_by,omitempty"`
+	OpenedAt   time.Time  `json:"opened_at"`
+	ResolvedBy string     `json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// DisputeStorage persists disputes.
+type DisputeStorage interface {
+	Set(dispute *Dispute) error
+	Read(id string) (*Dispute, error)
+	GetAll() ([]*Dispute, error)
+}
+
+type LocalDisputeStorage struct {
+	mu sync.RWMutex
+	m  map[string]*Dispute
+}
+
+func NewLocalDisputeStorage() *LocalDisputeStorage {
+	return &LocalDisputeStorage{
+		m: make(map[string]*Dispute),
+	}
+}
+
+func (l *LocalDisputeStorage) Set(dispute *Dispute) error {
+	if dispute.ID == "" {
+		return ErrEmptyID
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	d := *dispute
+	l.m[dispute.ID] = &d
+	return nil
+}
+
+func (l *LocalDisputeStorage) Read(id string) (*Dispute, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	d, ok := l.m[id]
+	if !ok {
+		return nil, ErrDisputeNotFound
+	}
+	c := *d
+	return &c, nil
+}
+
+// GetAll retorna las disputas en orden de apertura.
+func (l *LocalDisputeStorage) GetAll() ([]*Dispute, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	result := make([]*Dispute, 0, len(l.m))
+	for _, d := range l.m {
+		c := *d
+		result = append(result, &c)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].OpenedAt.Before(result[j].OpenedAt) })
+	return result, nil
+}
+
+// WithDisputeStorage sets where disputes are kept. Defaults to an in-memory
+// LocalDisputeStorage.
+func WithDisputeStorage(disputes DisputeStorage) Option {
+	return func(s *Service) {
+		s.disputes = disputes
+	}
+}
+
+// OpenDispute records a dispute against an approved sale and moves the sale
+// to disputed. Like adjustments, disputes arrive after the fact, so the
+// accounting period lock does not apply to them.
+func (s *Service) OpenDispute(saleID, reason string, openedBy Actor) (*Dispute, error) {
+	if reason == "" {
+		return nil, fmt.Errorf("dispute reason is required")
+	}
+	sale, err := s.storage.Read(saleID)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	if sale.Status != StatusApproved {
+		return nil, ErrNotDisputable
+	}
+
+	dispute := &Dispute{
+		ID:       uuid.NewString(),
+		SaleID:   sale.ID,
+		UserID:   sale.UserID,
+		Amount:   sale.Amount,
+		Reason:   reason,
+		Status:   DisputeOpen,
+		OpenedBy: openedBy.ID,
+		OpenedAt: utcNow(),
+	}
+	if err := s.disputes.Set(dispute); err != nil {
+		s.logger.Error("failed to save dispute", zap.String("sale_id", sale.ID), zap.Error(err))
+		return nil, fmt.Errorf("failed to save dispute: %w", err)
+	}
+
+	if err := s.moveDisputedSale(sale, StatusDisputed, AuditActionDisputed, openedBy); err != nil {
+		return nil, err
+	}
+	s.logger.Info("sale disputed", zap.String("sale_id", sale.ID), zap.String("dispute_id", dispute.ID))
+	return dispute, nil
+}
+
+// ResolveDispute closes an open dispute. Won returns the sale to approved;
+// lost charges it back, which the ledger records as a reversal.
+func (s *Service) ResolveDispute(disputeID, outcome string, resolvedBy Actor) (*Dispute, error) {
+	if outcome != DisputeWon && outcome != DisputeLost {
+		return nil, ErrInvalidOutcome
+	}
+	dispute, err := s.disputes.Read(disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.Status != DisputeOpen {
+		return nil, ErrDisputeResolved
+	}
+	sale, err := s.storage.Read(dispute.SaleID)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	// La venta se mueve antes de cerrar la disputa: si falla, la disputa sigue
+	// abierta y la resolución se puede reintentar
+	status := StatusApproved
+	if outcome == DisputeLost {
+		status = StatusChargedBack
+	}
+	if err := s.moveDisputedSale(sale, status, AuditActionDisputeResolved, resolvedBy); err != nil {
+		return nil, err
+	}
+
+	now := utcNow()
+	dispute.Status = outcome
+	dispute.ResolvedBy = resolvedBy.ID
+	dispute.ResolvedAt = &now
+	if err := s.disputes.Set(dispute); err != nil {
+		s.logger.Error("failed to save dispute", zap.String("dispute_id", dispute.ID), zap.Error(err))
+		return nil, fmt.Errorf("failed to save dispute: %w", err)
+	}
+	s.logger.Info("dispute resolved", zap.String("sale_id", sale.ID), zap.String("dispute_id", dispute.ID), zap.String("outcome", outcome))
+	return dispute, nil
+}
+
+// GetSaleDisputes returns the disputes of a sale in the order they were opened.
+func (s *Service) GetSaleDisputes(saleID string) ([]*Dispute, error) {
+	if _, err := s.storage.Read(saleID); err != nil {
+		return nil, ErrNotFound
+	}
+	all, err := s.disputes.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve disputes: %w", err)
+	}
+	result := make([]*Dispute, 0)
+	for _, d := range all {
+		if d.SaleID == saleID {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
+// moveDisputedSale cambia el estado de la venta por una disputa, dejando
+// registro en la auditoría.
+func (s *Service) moveDisputedSale(sale *Sale, status, action string, actor Actor) error {
+	before := sale.clone()
+	updated := sale.clone()
+	updated.Status = status
+	updated.UpdatedAt = utcNow()
+	updated.Version++
+
+	if err := s.saveAudited(action, before, updated, actor); err != nil {
+		s.logger.Error("failed to update sale", zap.String("sale_id", sale.ID), zap.Error(err))
+		return fmt.Errorf("failed to update sale: %w", err)
+	}
+	s.publishStatusChanged(updated, before.Status)
+	s.notify(EventSaleStatusChanged, updated)
+	return nil
+}
+
+// chargebackLedgerLines revierte el asiento de una venta perdida en disputa,
+// con fecha de resolución.
+func chargebackLedgerLines(sale *Sale, d *Dispute) []LedgerLine {
+	line := func(account string, debit, credit float64) LedgerLine {
+		return LedgerLine{
+			EntryID:     d.ID,
+			SaleID:      sale.ID,
+			Date:        *d.ResolvedAt,
+			Account:     account,
+			Debit:       debit,
+			Credit:      credit,
+			Description: "chargeback: " + d.Reason,
+		}
+	}
+
+	lines := []LedgerLine{
+		line(AccountRevenue, roundCents(sale.Amount-sale.Tax), 0),
+		line(AccountReceivable, 0, sale.Amount),
+	}
+	if sale.Tax > 0 {
+		lines = append(lines, line(AccountTaxPayable, sale.Tax, 0))
+	}
+	return lines
+}
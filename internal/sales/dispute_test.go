@@ -0,0 +1,151 @@
+package sales
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func newDisputeTestService(t *testing.T) (*Service, *LocalStorage) {
+	t.Helper()
+	storage := NewLocalStorage()
+	svc := NewService(storage, zaptest.NewLogger(t), "http://unused")
+	storage.Set(&Sale{ID: "s1", UserID: "u1", Status: StatusApproved, Amount: 121, Tax: 21, Version: 1})
+	return svc, storage
+}
+
+// TestDispute_LostChargesBackTheSale verifica el flujo completo de una disputa
+// perdida: ajustes en pausa y reverso en el libro.
+func TestDispute_LostChargesBackTheSale(t *testing.T) {
+	svc, storage := newDisputeTestService(t)
+
+	dispute, err := svc.OpenDispute("s1", "item not received", Actor{ID: "op-1"})
+	if err != nil {
+		t.Fatalf("OpenDispute returned error: %v", err)
+	}
+	if sale, _ := storage.Read("s1"); sale.Status != StatusDisputed {
+		t.Fatalf("expected the sale disputed, got %s", sale.Status)
+	}
+	if _, err := svc.AdjustSale("s1", -10, "refund", "op-1"); err != ErrSaleDisputed {
+		t.Errorf("expected refunds paused while disputed, got %v", err)
+	}
+	if _, err := svc.OpenDispute("s1", "again", Actor{ID: "op-1"}); err != ErrNotDisputable {
+		t.Errorf("expected a single open dispute per sale, got %v", err)
+	}
+
+	resolved, err := svc.ResolveDispute(dispute.ID, DisputeLost, Actor{ID: "op-2"})
+	if err != nil {
+		t.Fatalf("ResolveDispute returned error: %v", err)
+	}
+	if resolved.Status != DisputeLost || resolved.ResolvedAt == nil {
+		t.Errorf("unexpected resolved dispute: %+v", resolved)
+	}
+	if sale, _ := storage.Read("s1"); sale.Status != StatusChargedBack {
+		t.Errorf("expected the sale charged back, got %s", sale.Status)
+	}
+	if _, err := svc.ResolveDispute(dispute.ID, DisputeWon, Actor{ID: "op-2"}); err != ErrDisputeResolved {
+		t.Errorf("expected ErrDisputeResolved, got %v", err)
+	}
+
+	lines, err := svc.Ledger(nil, nil)
+	if err != nil {
+		t.Fatalf("Ledger returned error: %v", err)
+	}
+	balance := map[string]float64{}
+	for _, l := range lines {
+		balance[l.Account] += l.Debit - l.Credit
+	}
+	for account, total := range balance {
+		if roundCents(total) != 0 {
+			t.Errorf("expected %s to net to zero after the chargeback, got %v", account, total)
+		}
+	}
+	if len(lines) != 6 {
+		t.Errorf("expected the sale entry and its reversal, got %d lines", len(lines))
+	}
+
+	stats, err := svc.GetStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.ByStatus[StatusChargedBack] != 1 {
+		t.Errorf("expected the chargeback in the stats, got %v", stats.ByStatus)
+	}
+}
+
+func TestDispute_WonRestoresTheSale(t *testing.T) {
+	svc, storage := newDisputeTestService(t)
+
+	dispute, err := svc.OpenDispute("s1", "fraud claim", Actor{ID: "admin1", OnBehalfOf: "op-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.ResolveDispute(dispute.ID, "maybe", Actor{ID: "op-2"}); err != ErrInvalidOutcome {
+		t.Errorf("expected ErrInvalidOutcome, got %v", err)
+	}
+	if _, err := svc.ResolveDispute(dispute.ID, DisputeWon, Actor{ID: "op-2"}); err != nil {
+		t.Fatal(err)
+	}
+	if sale, _ := storage.Read("s1"); sale.Status != StatusApproved || sale.Version != 3 {
+		t.Errorf("expected the sale approved again at version 3, got %s v%d", sale.Status, sale.Version)
+	}
+	if _, err := svc.AdjustSale("s1", -10, "refund", "op-1"); err != nil {
+		t.Errorf("expected refunds allowed after a won dispute, got %v", err)
+	}
+
+	entries, _ := svc.GetSaleAudit("s1", nil)
+	if len(entries) != 2 || entries[0].Action != AuditActionDisputed || entries[1].Action != AuditActionDisputeResolved {
+		t.Errorf("expected both dispute changes audited, got %d entries", len(entries))
+	}
+	if len(entries) > 0 && (entries[0].Actor != "admin1" || entries[0].OnBehalfOf != "op-1") {
+		t.Errorf("expected actor admin1 on behalf of op-1, got %q / %q", entries[0].Actor, entries[0].OnBehalfOf)
+	}
+}
+
+// writeFailingStorage rechaza las escrituras de ventas mientras fail está
+// activo, también dentro de WithTx.
+type writeFailingStorage struct {
+	Storage
+	fail bool
+}
+
+func (w *writeFailingStorage) Set(sale *Sale) error {
+	if w.fail {
+		return errors.New("write failed")
+	}
+	return w.Storage.Set(sale)
+}
+
+func (w *writeFailingStorage) WithTx(ctx context.Context, fn func(tx Storage) error) error {
+	return fn(w)
+}
+
+// TestResolveDispute_KeepsTheDisputeOpenIfTheSaleWriteFails verifica que una
+// resolución fallida se pueda reintentar.
+func TestResolveDispute_KeepsTheDisputeOpenIfTheSaleWriteFails(t *testing.T) {
+	storage := &writeFailingStorage{Storage: NewLocalStorage()}
+	storage.Set(&Sale{ID: "s1", UserID: "u1", Status: StatusApproved, Amount: 121, Tax: 21, Version: 1})
+	svc := NewService(storage, zaptest.NewLogger(t), "http://unused")
+
+	dispute, err := svc.OpenDispute("s1", "item not received", Actor{ID: "op-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage.fail = true
+	if _, err := svc.ResolveDispute(dispute.ID, DisputeWon, Actor{ID: "op-2"}); err == nil {
+		t.Fatal("expected the failed sale write reported")
+	}
+	if disputes, _ := svc.GetSaleDisputes("s1"); len(disputes) != 1 || disputes[0].Status != DisputeOpen {
+		t.Fatalf("expected the dispute still open, got %+v", disputes)
+	}
+
+	storage.fail = false
+	if _, err := svc.ResolveDispute(dispute.ID, DisputeWon, Actor{ID: "op-2"}); err != nil {
+		t.Fatalf("expected the retry to resolve the dispute, got %v", err)
+	}
+	if sale, _ := storage.Read("s1"); sale.Status != StatusApproved {
+		t.Errorf("expected the sale approved again, got %s", sale.Status)
+	}
+}
@@ -2,13 +2,72 @@ package sales
 
 import "time"
 
-// Sale represents a sales transaction in the system.
+const (
+	StatusDraft    = "draft"
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+
+	StatusDisputed    = "disputed"
+	StatusChargedBack = "charged_back"
+)
+
+// Sale represents a sales transaction in the system. Status is the payment
+// status; FulfillmentStatus tracks delivery separately.
 type Sale struct {
-	ID        string    `json:"id"`
-	UserID    string    `json:"user_id"`
-	Amount    float64   `json:"amount"`
-	Status    string    `json:"status"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Version   int       `json:"version"`
+	ID                string            `json:"id"`
+	UserID            string            `json:"user_id"`
+	Amount            float64           `json:"amount"`
+	Status            string            `json:"status"`
+	LineItems         []LineItem        `json:"line_items,omitempty"`
+	Subtotal          float64           `json:"subtotal,omitempty"`
+	DiscountPercent   float64           `json:"discount_percent,omitempty"`
+	Discount          float64           `json:"discount,omitempty"`
+	TaxPercent        float64           `json:"tax_percent,omitempty"`
+	Tax               float64           `json:"tax,omitempty"`
+	RecurringSaleID   string            `json:"recurring_sale_id,omitempty"`
+	ERPPosting        string            `json:"erp_posting_status,omitempty"`
+	TenantID          string            `json:"tenant_id,omitempty"`
+	FulfillmentStatus string            `json:"fulfillment_status,omitempty"`
+	Carrier           string            `json:"carrier,omitempty"`
+	TrackingNumber    string            `json:"tracking_number,omitempty"`
+	ShippedAt         *time.Time        `json:"shipped_at,omitempty"`
+	DeliveredAt       *time.Time        `json:"delivered_at,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+	Version           int               `json:"version"`
+	// PendingSince is when the sale entered pending; older sales use CreatedAt.
+	PendingSince *time.Time `json:"pending_since,omitempty"`
+	// DecidedAt is when a reviewer approved or rejected the pending sale.
+	DecidedAt *time.Time `json:"decided_at,omitempty"`
+	// PendingAge and SLABreached are computed for responses of pending sales
+	// when a pending SLA is configured; they are never stored.
+	PendingAge  string `json:"pending_age,omitempty"`
+	SLABreached bool   `json:"sla_breached,omitempty"`
+}
+
+// LineItem is a single product line of a sale.
+type LineItem struct {
+	SKU         string  `json:"sku"`
+	Description string  `json:"description,omitempty"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+}
+
+// RecurringSale is a definition the scheduler uses to materialize a new Sale
+// every period until EndDate (if any) is reached.
+type RecurringSale struct {
+	ID                  string     `json:"id"`
+	UserID              string     `json:"user_id"`
+	Amount              float64    `json:"amount"`
+	Interval            string     `json:"interval"`
+	Status              string     `json:"status"`
+	NextRunAt           time.Time  `json:"next_run_at"`
+	EndDate             *time.Time `json:"end_date,omitempty"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastSaleID          string     `json:"last_sale_id,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+	Version             int        `json:"version"`
 }
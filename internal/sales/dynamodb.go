@@ -0,0 +1,194 @@
+package sales
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// DynamoDBAPI is the subset of the DynamoDB client used by DynamoDBStorage.
+type DynamoDBAPI interface {
+	PutItem(ctx context.Context, in *dynamodb.PutItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	GetItem(ctx context.Context, in *dynamodb.GetItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	Scan(ctx context.Context, in *dynamodb.ScanInput, opts ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+	Query(ctx context.Context, in *dynamodb.QueryInput, opts ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+}
+
+// DynamoDBStorage keeps each sale as a JSON document in a table whose
+// partition key is the string attribute "id". Sales with a user also carry
+// the string attribute "user_id", the partition key of the optional index
+// set with SetUserIndex.
+type DynamoDBStorage struct {
+	client    DynamoDBAPI
+	table     string
+	userIndex string
+	timeout   time.Duration
+}
+
+func NewDynamoDBStorage(client DynamoDBAPI, table string) *DynamoDBStorage {
+	return &DynamoDBStorage{
+		client:  client,
+		table:   table,
+		timeout: 5 * time.Second,
+	}
+}
+
+// SetUserIndex makes GetByUser query the global secondary index index, whose
+// partition key is "user_id" and which projects the "data" attribute. Without
+// it GetByUser scans the table.
+func (d *DynamoDBStorage) SetUserIndex(index string) {
+	d.userIndex = index
+}
+
+func (d *DynamoDBStorage) Set(sale *Sale) error {
+	if sale.ID == "" {
+		return ErrEmptyID
+	}
+	data, err := json.Marshal(sale)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+	defer cancel()
+	item := map[string]types.AttributeValue{
+		"id":   &types.AttributeValueMemberS{Value: sale.ID},
+		"data": &types.AttributeValueMemberS{Value: string(data)},
+	}
+	// DynamoDB no admite strings vacíos en claves de índices: sin usuario la
+	// venta queda fuera del índice
+	if sale.UserID != "" {
+		item["user_id"] = &types.AttributeValueMemberS{Value: sale.UserID}
+	}
+	_, err = d.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(d.table),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("dynamodb put sale: %w", err)
+	}
+	return nil
+}
+
+func (d *DynamoDBStorage) Read(id string) (*Sale, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+	defer cancel()
+	out, err := d.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(d.table),
+		Key:            map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: id}},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dynamodb get sale: %w", err)
+	}
+	if out.Item == nil {
+		return nil, ErrNotFound
+	}
+	return decodeDynamoDBSale(out.Item)
+}
+
+// GetAll recorre la tabla completa paginando el Scan.
+func (d *DynamoDBStorage) GetAll() ([]*Sale, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+	defer cancel()
+
+	sales := make([]*Sale, 0)
+	in := &dynamodb.ScanInput{TableName: aws.String(d.table)}
+	for {
+		out, err := d.client.Scan(ctx, in)
+		if err != nil {
+			return nil, fmt.Errorf("dynamodb scan sales: %w", err)
+		}
+		for _, item := range out.Items {
+			sale, err := decodeDynamoDBSale(item)
+			if err != nil {
+				return nil, err
+			}
+			sales = append(sales, sale)
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			return sales, nil
+		}
+		in.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+}
+
+// GetByUser returns the sales of userID through the user index. Reads of a
+// global secondary index are eventually consistent, so a sale written a
+// moment ago may be missing.
+func (d *DynamoDBStorage) GetByUser(userID string) ([]*Sale, error) {
+	if d.userIndex == "" {
+		all, err := d.GetAll()
+		if err != nil {
+			return nil, err
+		}
+		return ownedBy(all, userID), nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+	defer cancel()
+
+	sales := make([]*Sale, 0)
+	in := &dynamodb.QueryInput{
+		TableName:                 aws.String(d.table),
+		IndexName:                 aws.String(d.userIndex),
+		KeyConditionExpression:    aws.String("user_id = :u"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":u": &types.AttributeValueMemberS{Value: userID}},
+	}
+	for {
+		out, err := d.client.Query(ctx, in)
+		if err != nil {
+			return nil, fmt.Errorf("dynamodb query user sales: %w", err)
+		}
+		for _, item := range out.Items {
+			sale, err := decodeDynamoDBSale(item)
+			if err != nil {
+				return nil, err
+			}
+			sales = append(sales, sale)
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			return sales, nil
+		}
+		in.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+}
+
+// WithTx corre fn sin atomicidad: cada venta es un item y las llamadas no
+// comparten transacción.
+func (d *DynamoDBStorage) WithTx(ctx context.Context, fn func(tx Storage) error) error {
+	return fn(d)
+}
+
+func decodeDynamoDBSale(item map[string]types.AttributeValue) (*Sale, error) {
+	data, ok := item["data"].(*types.AttributeValueMemberS)
+	if !ok {
+		return nil, fmt.Errorf("dynamodb sale item without data attribute")
+	}
+	var sale Sale
+	if err := json.Unmarshal([]byte(data.Value), &sale); err != nil {
+		return nil, fmt.Errorf("dynamodb decode sale: %w", err)
+	}
+	return &sale, nil
+}
+
+// IsTransientDynamoDBError adds to IsTransientError the DynamoDB errors that
+// go away on their own: throttling, internal errors and transaction
+// conflicts.
+func IsTransientDynamoDBError(err error) bool {
+	var (
+		throughput *types.ProvisionedThroughputExceededException
+		limit      *types.RequestLimitExceeded
+		internal   *types.InternalServerError
+		conflict   *types.TransactionConflictException
+	)
+	if errors.As(err, &throughput) || errors.As(err, &limit) || errors.As(err, &internal) || errors.As(err, &conflict) {
+		return true
+	}
+	return IsTransientError(err)
+}
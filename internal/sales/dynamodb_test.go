@@ -0,0 +1,141 @@
+package sales
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// fakeDynamoDB guarda los items en memoria y pagina el Scan y el Query de a
+// uno. queries cuenta las consultas al índice.
+type fakeDynamoDB struct {
+	items   map[string]map[string]types.AttributeValue
+	order   []string
+	queries int
+}
+
+func (f *fakeDynamoDB) PutItem(ctx context.Context, in *dynamodb.PutItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	id := in.Item["id"].(*types.AttributeValueMemberS).Value
+	if _, ok := f.items[id]; !ok {
+		f.order = append(f.order, id)
+	}
+	f.items[id] = in.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (f *fakeDynamoDB) GetItem(ctx context.Context, in *dynamodb.GetItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	id := in.Key["id"].(*types.AttributeValueMemberS).Value
+	return &dynamodb.GetItemOutput{Item: f.items[id]}, nil
+}
+
+func (f *fakeDynamoDB) Scan(ctx context.Context, in *dynamodb.ScanInput, opts ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	start := 0
+	if in.ExclusiveStartKey != nil {
+		last := in.ExclusiveStartKey["id"].(*types.AttributeValueMemberS).Value
+		for i, id := range f.order {
+			if id == last {
+				start = i + 1
+			}
+		}
+	}
+	out := &dynamodb.ScanOutput{}
+	if start < len(f.order) {
+		id := f.order[start]
+		out.Items = []map[string]types.AttributeValue{f.items[id]}
+		if start+1 < len(f.order) {
+			out.LastEvaluatedKey = map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: id}}
+		}
+	}
+	return out, nil
+}
+
+// Query resuelve solo "user_id = :u" sobre el índice, como el de SetUserIndex.
+func (f *fakeDynamoDB) Query(ctx context.Context, in *dynamodb.QueryInput, opts ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	f.queries++
+	if in.IndexName == nil || *in.KeyConditionExpression != "user_id = :u" {
+		return nil, errors.New("unsupported query")
+	}
+	userID := in.ExpressionAttributeValues[":u"].(*types.AttributeValueMemberS).Value
+	var matching []string
+	for _, id := range f.order {
+		if owner, ok := f.items[id]["user_id"].(*types.AttributeValueMemberS); ok && owner.Value == userID {
+			matching = append(matching, id)
+		}
+	}
+	start := 0
+	if in.ExclusiveStartKey != nil {
+		last := in.ExclusiveStartKey["id"].(*types.AttributeValueMemberS).Value
+		for i, id := range matching {
+			if id == last {
+				start = i + 1
+			}
+		}
+	}
+	out := &dynamodb.QueryOutput{}
+	if start < len(matching) {
+		id := matching[start]
+		out.Items = []map[string]types.AttributeValue{f.items[id]}
+		if start+1 < len(matching) {
+			out.LastEvaluatedKey = map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: id}, "user_id": &types.AttributeValueMemberS{Value: userID}}
+		}
+	}
+	return out, nil
+}
+
+// TestDynamoDBStorage_RoundTrip verifica Set/Read/GetAll sobre DynamoDB.
+func TestDynamoDBStorage_RoundTrip(t *testing.T) {
+	storage := NewDynamoDBStorage(&fakeDynamoDB{items: map[string]map[string]types.AttributeValue{}}, "sales")
+
+	for _, id := range []string{"s1", "s2", "s3"} {
+		if err := storage.Set(&Sale{ID: id, UserID: "user123", Amount: 10, Status: "pending", Version: 1}); err != nil {
+			t.Fatalf("Set returned error: %v", err)
+		}
+	}
+
+	sale, err := storage.Read("s2")
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+	if sale.UserID != "user123" || sale.Amount != 10 {
+		t.Errorf("unexpected sale %+v", sale)
+	}
+	if _, err := storage.Read("missing"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	all, err := storage.GetAll()
+	if err != nil || len(all) != 3 {
+		t.Errorf("expected 3 sales across scan pages, got %d (err=%v)", len(all), err)
+	}
+}
+
+// TestDynamoDBStorage_GetByUser verifica la consulta al índice por usuario y
+// el Scan cuando no hay índice.
+func TestDynamoDBStorage_GetByUser(t *testing.T) {
+	client := &fakeDynamoDB{items: map[string]map[string]types.AttributeValue{}}
+	storage := NewDynamoDBStorage(client, "sales")
+	storage.Set(&Sale{ID: "s1", UserID: "user123"})
+	storage.Set(&Sale{ID: "s2", UserID: "other"})
+	storage.Set(&Sale{ID: "s3", UserID: "user123"})
+	storage.Set(&Sale{ID: "s4"})
+	if _, ok := client.items["s4"]["user_id"]; ok {
+		t.Error("expected no user_id attribute for a sale without a user")
+	}
+
+	for _, index := range []string{"", "user_id-index"} {
+		storage.SetUserIndex(index)
+		owned, err := storage.GetByUser("user123")
+		if err != nil {
+			t.Fatalf("GetByUser(%q) returned error: %v", index, err)
+		}
+		if len(owned) != 2 || owned[0].ID != "s1" || owned[1].ID != "s3" {
+			t.Errorf("index %q: unexpected sales %+v", index, owned)
+		}
+	}
+	if client.queries != 2 {
+		t.Errorf("expected 2 query pages through the index only, got %d", client.queries)
+	}
+}
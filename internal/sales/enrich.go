@@ -0,0 +1,100 @@
+package sales
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+// Error para políticas de enriquecimiento desconocidas
+var ErrInvalidEnrichPolicy = errors.New("invalid enrich policy")
+
+// Error para ventas rechazadas por un enricher con política fail
+var ErrEnrichmentFailed = errors.New("sale enrichment failed")
+
+var enrichFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "sales_enrichment_failures_total",
+	Help: "Enricher errors, by enricher and policy.",
+}, []string{"enricher", "policy"})
+
+// EnrichPolicy decides what happens when an enricher returns an error.
+type EnrichPolicy string
+
+const (
+	// EnrichFail rejects the sale.
+	EnrichFail EnrichPolicy = "fail"
+	// EnrichWarn logs a warning and saves the sale without the enricher's changes.
+	EnrichWarn EnrichPolicy = "warn"
+	// EnrichSkip saves the sale without the enricher's changes, logging only at debug level.
+	EnrichSkip EnrichPolicy = "skip"
+)
+
+// ParseEnrichPolicy validates a policy name.
+func ParseEnrichPolicy(s string) (EnrichPolicy, error) {
+	switch p := EnrichPolicy(s); p {
+	case EnrichFail, EnrichWarn, EnrichSkip:
+		return p, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidEnrichPolicy, s)
+}
+
+// Origin describes where a sale creation request came from.
+type Origin struct {
+	ClientIP  string
+	UserAgent string
+	// TenantID is stored on the sale and selects its status vocabulary.
+	TenantID string
+}
+
+// Enricher adds derived data, usually metadata entries, to a sale before it is
+// persisted.
+type Enricher interface {
+	Name() string
+	Enrich(sale *Sale, origin Origin) error
+}
+
+type enrichStep struct {
+	enricher Enricher
+	policy   EnrichPolicy
+}
+
+// WithEnricher runs e on every new sale before it is saved. Enrichers run in
+// the order they are registered.
+func WithEnricher(e Enricher, policy EnrichPolicy) Option {
+	return func(s *Service) {
+		s.enrichers = append(s.enrichers, enrichStep{enricher: e, policy: policy})
+	}
+}
+
+// enrich aplica los enrichers en orden. Cada uno trabaja sobre una copia: si
+// falla, sus cambios parciales no llegan a la venta.
+func (s *Service) enrich(sale *Sale, origin Origin) error {
+	for _, step := range s.enrichers {
+		candidate := sale.clone()
+		err := step.enricher.Enrich(candidate, origin)
+		if err == nil {
+			// Lo agregado por el enricher respeta los mismos límites que la metadata del cliente
+			err = validateMetadata(candidate.Metadata)
+		}
+		if err == nil {
+			*sale = *candidate
+			continue
+		}
+
+		enrichFailures.WithLabelValues(step.enricher.Name(), string(step.policy)).Inc()
+		fields := []zap.Field{zap.String("enricher", step.enricher.Name()), zap.String("sale_id", sale.ID), zap.Error(err)}
+		switch step.policy {
+		case EnrichSkip:
+			s.logger.Debug("sale enrichment skipped", fields...)
+		case EnrichWarn:
+			s.logger.Warn("sale enrichment failed", fields...)
+		default:
+			s.logger.Error("sale enrichment failed", fields...)
+			return fmt.Errorf("%w: %s", ErrEnrichmentFailed, step.enricher.Name())
+		}
+	}
+	return nil
+}
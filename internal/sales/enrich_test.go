@@ -0,0 +1,75 @@
+package sales
+
+import (
+	"errors"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+type enricherFunc struct {
+	name string
+	fn   func(*Sale, Origin) error
+}
+
+func (e enricherFunc) Name() string                           { return e.name }
+func (e enricherFunc) Enrich(sale *Sale, origin Origin) error { return e.fn(sale, origin) }
+
+// tagging agrega key=value y luego devuelve err, para probar que los cambios
+// parciales se descartan.
+func tagging(name, key, value string, err error) enricherFunc {
+	return enricherFunc{name: name, fn: func(sale *Sale, _ Origin) error {
+		if sale.Metadata == nil {
+			sale.Metadata = map[string]string{}
+		}
+		sale.Metadata[key] = value
+		return err
+	}}
+}
+
+// TestEnrich_PoliciesAndOrder verifica el orden y la política de cada enricher.
+func TestEnrich_PoliciesAndOrder(t *testing.T) {
+	boom := errors.New("boom")
+	var seenIP string
+	svc := NewService(NewLocalStorage(), zaptest.NewLogger(t), "http://unused",
+		WithEnricher(enricherFunc{name: "origin", fn: func(_ *Sale, o Origin) error {
+			seenIP = o.ClientIP
+			return nil
+		}}, EnrichFail),
+		WithEnricher(tagging("first", "step", "first", nil), EnrichFail),
+		WithEnricher(tagging("warned", "warned", "yes", boom), EnrichWarn),
+		WithEnricher(tagging("skipped", "skipped", "yes", boom), EnrichSkip),
+		WithEnricher(tagging("second", "step", "second", nil), EnrichFail),
+	)
+
+	sale, err := svc.CreateDraftSaleWithOrigin("u1", 10, nil, Origin{ClientIP: "203.0.113.7"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if seenIP != "203.0.113.7" {
+		t.Errorf("expected origin to reach the enricher, got %q", seenIP)
+	}
+	if sale.Metadata["step"] != "second" {
+		t.Errorf("expected enrichers to run in order, got %v", sale.Metadata)
+	}
+	if _, ok := sale.Metadata["warned"]; ok {
+		t.Error("failed warn enricher should not change the sale")
+	}
+	if _, ok := sale.Metadata["skipped"]; ok {
+		t.Error("failed skip enricher should not change the sale")
+	}
+}
+
+func TestEnrich_FailPolicyRejectsSale(t *testing.T) {
+	storage := NewLocalStorage()
+	svc := NewService(storage, zaptest.NewLogger(t), "http://unused",
+		WithEnricher(tagging("strict", "k", "v", errors.New("boom")), EnrichFail))
+
+	_, err := svc.CreateDraftSale("u1", 10)
+	if !errors.Is(err, ErrEnrichmentFailed) {
+		t.Fatalf("expected ErrEnrichmentFailed, got %v", err)
+	}
+	if all, _ := storage.GetAll(); len(all) != 0 {
+		t.Errorf("rejected sale should not be saved, found %d", len(all))
+	}
+}
@@ -0,0 +1,127 @@
+// Package events defines the typed domain events the sales service publishes
+// and a dispatcher that fans them out to subscribers (webhooks, bus
+// publishers, audit, cache invalidation) without the service knowing them.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Names of the events, the same as the event types sent to notifiers.
+const (
+	NameSaleCreated       = "sale.created"
+	NameSaleStatusChanged = "sale.status_changed"
+	NameSaleRefunded      = "sale.refunded"
+)
+
+// Event is a domain event of a sale.
+type Event interface {
+	Name() string
+}
+
+// SaleCreated is published when a sale is created, drafts included.
+type SaleCreated struct {
+	SaleID   string    `json:"sale_id"`
+	UserID   string    `json:"user_id"`
+	TenantID string    `json:"tenant_id,omitempty"`
+	Amount   float64   `json:"amount"`
+	Status   string    `json:"status"`
+	At       time.Time `json:"at"`
+}
+
+func (SaleCreated) Name() string { return NameSaleCreated }
+
+// SaleStatusChanged is published when a sale moves from one status to
+// another.
+type SaleStatusChanged struct {
+	SaleID   string    `json:"sale_id"`
+	UserID   string    `json:"user_id"`
+	TenantID string    `json:"tenant_id,omitempty"`
+	From     string    `json:"from"`
+	To       string    `json:"to"`
+	Version  int       `json:"version"`
+	At       time.Time `json:"at"`
+}
+
+func (SaleStatusChanged) Name() string { return NameSaleStatusChanged }
+
+// SaleRefunded is published when a negative adjustment gives money back on a
+// sale. Amount is the refunded amount, always positive.
+type SaleRefunded struct {
+	SaleID       string    `json:"sale_id"`
+	UserID       string    `json:"user_id"`
+	AdjustmentID string    `json:"adjustment_id"`
+	Amount       float64   `json:"amount"`
+	Reason       string    `json:"reason"`
+	RefundedBy   string    `json:"refunded_by,omitempty"`
+	At           time.Time `json:"at"`
+}
+
+func (SaleRefunded) Name() string { return NameSaleRefunded }
+
+// Handler receives the published events. It runs on the publisher's
+// goroutine, so it must not block.
+type Handler func(Event)
+
+// Dispatcher delivers every published event to the handlers subscribed to
+// its name and to the ones subscribed to all events.
+type Dispatcher struct {
+	logger *zap.Logger
+
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+	all      []Handler
+}
+
+func NewDispatcher(logger *zap.Logger) *Dispatcher {
+	return &Dispatcher{logger: logger, handlers: make(map[string][]Handler)}
+}
+
+// Subscribe registers h for the events with the given name.
+func (d *Dispatcher) Subscribe(name string, h Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers[name] = append(d.handlers[name], h)
+}
+
+// SubscribeAll registers h for every event.
+func (d *Dispatcher) SubscribeAll(h Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.all = append(d.all, h)
+}
+
+// On registers fn for the events of type T.
+func On[T Event](d *Dispatcher, fn func(T)) {
+	var zero T
+	d.Subscribe(zero.Name(), func(e Event) {
+		if typed, ok := e.(T); ok {
+			fn(typed)
+		}
+	})
+}
+
+// Publish delivers e to its subscribers in the order they subscribed. A
+// handler that panics is logged and skipped.
+func (d *Dispatcher) Publish(e Event) {
+	d.mu.RLock()
+	handlers := append(append([]Handler(nil), d.handlers[e.Name()]...), d.all...)
+	d.mu.RUnlock()
+
+	for _, h := range handlers {
+		d.deliver(h, e)
+	}
+}
+
+// deliver aísla al servicio de un handler que entra en pánico.
+func (d *Dispatcher) deliver(h Handler, e Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			d.logger.Error("event handler panicked", zap.String("event", e.Name()), zap.Any("panic", r))
+		}
+	}()
+	h(e)
+}
@@ -0,0 +1,29 @@
+package events
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// TestDispatcher_TypedAndAllSubscribers verifica que cada handler recibe sus
+// eventos y que un pánico no corta la entrega a los demás.
+func TestDispatcher_TypedAndAllSubscribers(t *testing.T) {
+	d := NewDispatcher(zaptest.NewLogger(t))
+
+	var refunds []SaleRefunded
+	var names []string
+	d.Subscribe(NameSaleRefunded, func(Event) { panic("broken subscriber") })
+	On(d, func(e SaleRefunded) { refunds = append(refunds, e) })
+	d.SubscribeAll(func(e Event) { names = append(names, e.Name()) })
+
+	d.Publish(SaleCreated{SaleID: "s1"})
+	d.Publish(SaleRefunded{SaleID: "s1", Amount: 30})
+
+	if len(refunds) != 1 || refunds[0].Amount != 30 {
+		t.Errorf("expected the refund delivered once to the typed subscriber, got %+v", refunds)
+	}
+	if len(names) != 2 || names[0] != NameSaleCreated || names[1] != NameSaleRefunded {
+		t.Errorf("expected every event delivered to the catch-all subscriber, got %v", names)
+	}
+}
@@ -0,0 +1,151 @@
+package sales
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Consistency selects where reads are served from.
+type Consistency string
+
+const (
+	// ConsistencyEventual reads from the read replica when one is configured.
+	ConsistencyEventual Consistency = "eventual"
+	// ConsistencyStrong always reads from the primary storage, so a client sees
+	// its own writes immediately.
+	ConsistencyStrong Consistency = "strong"
+)
+
+// ParseConsistency validates a consistency hint; empty means eventual.
+func ParseConsistency(v string) (Consistency, error) {
+	switch Consistency(v) {
+	case "", ConsistencyEventual:
+		return ConsistencyEventual, nil
+	case ConsistencyStrong:
+		return ConsistencyStrong, nil
+	}
+	return "", fmt.Errorf("invalid consistency value: expected %q or %q", ConsistencyEventual, ConsistencyStrong)
+}
+
+// SearchFilter holds the criteria accepted by SearchSale. Empty fields don't
+// filter. Consistency is not a filter but selects the store being searched.
+type SearchFilter struct {
+	UserID string
+	Status string
+	// FulfillmentStatus filters by delivery progress: pending, shipped or delivered.
+	FulfillmentStatus string
+	CreatedFrom       *time.Time
+	CreatedTo         *time.Time
+	// AsOf searches the sales as they were at that instant, reconstructed from
+	// their version history, instead of their current state.
+	AsOf *time.Time
+	// ExceededSLA keeps the sales whose approval or rejection took longer than
+	// it, or that have been pending for longer than it.
+	ExceededSLA time.Duration
+	Consistency Consistency
+	// Caller restricts the results to the sales the caller may see; nil means
+	// an unauthenticated, unrestricted search.
+	Caller *Caller
+}
+
+// Caller identifies who runs a search for row-level access control.
+type Caller struct {
+	UserID string
+	Role   string
+	// Team holds the user IDs of the sellers the caller may also see.
+	Team []string
+	// ImpersonatedBy identifies the admin acting on behalf of UserID.
+	ImpersonatedBy string
+}
+
+// matchesCreatedAt aplica el rango [CreatedFrom, CreatedTo] sobre la fecha de creación.
+func (f SearchFilter) matchesCreatedAt(t time.Time) bool {
+	if f.CreatedFrom != nil && t.Before(*f.CreatedFrom) {
+		return false
+	}
+	if f.CreatedTo != nil && t.After(*f.CreatedTo) {
+		return false
+	}
+	return true
+}
+
+// shapeFields describe los filtros usados para métricas y logs.
+func (f SearchFilter) shapeFields() map[string]string {
+	fields := map[string]string{"user_id": f.UserID, "status": f.Status, "fulfillment_status": f.FulfillmentStatus}
+	if f.CreatedFrom != nil {
+		fields["created_from"] = f.CreatedFrom.Format(time.RFC3339)
+	}
+	if f.CreatedTo != nil {
+		fields["created_to"] = f.CreatedTo.Format(time.RFC3339)
+	}
+	if f.AsOf != nil {
+		fields["as_of"] = f.AsOf.Format(time.RFC3339)
+	}
+	if f.ExceededSLA > 0 {
+		fields["exceeded_sla"] = f.ExceededSLA.String()
+	}
+	return fields
+}
+
+// QueryLimits are the guardrails applied to searches made by a role.
+type QueryLimits struct {
+	// RequireFilter rejects searches without user_id, status or created_from.
+	RequireFilter bool `json:"require_filter"`
+	// MaxDateRangeDays caps created_from..created_to (created_to defaults to
+	// now). Zero means no cap.
+	MaxDateRangeDays int `json:"max_date_range_days"`
+}
+
+// DefaultQueryLimits applies to roles without explicit limits.
+var DefaultQueryLimits = QueryLimits{RequireFilter: true, MaxDateRangeDays: 366}
+
+// QueryCostError explains why a search was rejected and what it needs.
+type QueryCostError struct {
+	Reason   string   `json:"reason"`
+	Required []string `json:"required"`
+}
+
+func (e *QueryCostError) Error() string {
+	return "query too expensive: " + e.Reason
+}
+
+// Check returns a *QueryCostError when filter exceeds the limits.
+func (l QueryLimits) Check(filter SearchFilter, now time.Time) error {
+	if l.RequireFilter && filter.UserID == "" && filter.Status == "" && filter.CreatedFrom == nil {
+		return &QueryCostError{
+			Reason:   "searches must be narrowed by at least one filter",
+			Required: []string{"one of: user_id, status, created_from"},
+		}
+	}
+
+	if l.MaxDateRangeDays > 0 && filter.CreatedFrom != nil {
+		to := now
+		if filter.CreatedTo != nil {
+			to = *filter.CreatedTo
+		}
+		maxRange := time.Duration(l.MaxDateRangeDays) * 24 * time.Hour
+		if to.Sub(*filter.CreatedFrom) > maxRange {
+			return &QueryCostError{
+				Reason:   fmt.Sprintf("date range exceeds %d days", l.MaxDateRangeDays),
+				Required: []string{fmt.Sprintf("created_to - created_from <= %d days", l.MaxDateRangeDays)},
+			}
+		}
+	}
+	return nil
+}
+
+// QueryPolicy maps roles to their QueryLimits.
+type QueryPolicy map[string]QueryLimits
+
+// For returns the limits of role, falling back to the "default" entry and then
+// to DefaultQueryLimits.
+func (p QueryPolicy) For(role string) QueryLimits {
+	if l, ok := p[strings.ToLower(role)]; ok {
+		return l
+	}
+	if l, ok := p["default"]; ok {
+		return l
+	}
+	return DefaultQueryLimits
+}
@@ -0,0 +1,48 @@
+package sales
+
+import (
+	"testing"
+	"time"
+)
+
+// TestQueryLimits_Check verifica los límites de costo de las búsquedas.
+func TestQueryLimits_Check(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	from := now.AddDate(0, 0, -60)
+	recent := now.AddDate(0, 0, -7)
+
+	limits := QueryLimits{RequireFilter: true, MaxDateRangeDays: 31}
+	cases := []struct {
+		name    string
+		filter  SearchFilter
+		wantErr bool
+	}{
+		{"no filters", SearchFilter{}, true},
+		{"user filter", SearchFilter{UserID: "user123"}, false},
+		{"short range", SearchFilter{CreatedFrom: &recent}, false},
+		{"huge range", SearchFilter{CreatedFrom: &from}, true},
+		{"huge range with user", SearchFilter{UserID: "user123", CreatedFrom: &from}, true},
+		{"bounded huge range", SearchFilter{CreatedFrom: &from, CreatedTo: &recent}, true},
+		{"only created_to", SearchFilter{CreatedTo: &recent}, true},
+	}
+
+	for _, tc := range cases {
+		err := limits.Check(tc.filter, now)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: expected error=%v, got %v", tc.name, tc.wantErr, err)
+		}
+		if err != nil {
+			if _, ok := err.(*QueryCostError); !ok {
+				t.Errorf("%s: expected *QueryCostError, got %T", tc.name, err)
+			}
+		}
+	}
+
+	policy := QueryPolicy{"admin": {}}
+	if err := policy.For("admin").Check(SearchFilter{}, now); err != nil {
+		t.Errorf("expected admin to run unbounded queries, got %v", err)
+	}
+	if err := policy.For("viewer").Check(SearchFilter{}, now); err == nil {
+		t.Error("expected default limits for roles without explicit limits")
+	}
+}
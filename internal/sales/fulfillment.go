@@ -0,0 +1,187 @@
+package sales
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Fulfillment statuses, tracked apart from the payment status in Sale.Status.
+const (
+	FulfillmentPending   = "pending"
+	FulfillmentShipped   = "shipped"
+	FulfillmentDelivered = "delivered"
+)
+
+// Error para estados de entrega desconocidos
+var ErrInvalidFulfillmentStatus = errors.New("invalid fulfillment status: expected shipped or delivered")
+
+// Error para envíos de ventas cuyo pago no está aprobado
+var ErrNotFulfillable = errors.New("only approved sales can be shipped")
+
+// FulfillmentUpdate moves a sale forward in the fulfillment workflow. Carrier
+// and TrackingNumber, when set, replace the recorded ones.
+type FulfillmentUpdate struct {
+	Status         string
+	Carrier        string
+	TrackingNumber string
+}
+
+// fulfillmentStatus trata como pendientes las ventas guardadas antes de que
+// existiera el seguimiento de entrega.
+func (s *Sale) fulfillmentStatus() string {
+	if s.FulfillmentStatus == "" {
+		return FulfillmentPending
+	}
+	return s.FulfillmentStatus
+}
+
+// UpdateFulfillment advances the fulfillment of a sale: pending to shipped,
+// which requires an approved payment, then shipped to delivered. It does not
+// touch the payment status, and the period lock does not apply since nothing
+// accounted changes.
+func (s *Service) UpdateFulfillment(saleID string, update FulfillmentUpdate, actor Actor) (*Sale, error) {
+	sale, err := s.storage.Read(saleID)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	current := sale.fulfillmentStatus()
+	switch update.Status {
+	case FulfillmentShipped:
+		if sale.Status != StatusApproved {
+			return nil, ErrNotFulfillable
+		}
+		if current != FulfillmentPending {
+			return nil, ErrInvalidTransition
+		}
+	case FulfillmentDelivered:
+		if current != FulfillmentShipped {
+			return nil, ErrInvalidTransition
+		}
+	default:
+		return nil, ErrInvalidFulfillmentStatus
+	}
+	if len(update.Carrier) > maxMetadataKeyLen || len(update.TrackingNumber) > maxMetadataValueLen {
+		return nil, fmt.Errorf("invalid shipment details")
+	}
+
+	return s.saveFulfillment(sale, update, utcNow(), actor)
+}
+
+// fulfillmentRank ordena los estados de entrega para descartar eventos viejos.
+var fulfillmentRank = map[string]int{FulfillmentPending: 0, FulfillmentShipped: 1, FulfillmentDelivered: 2}
+
+// ApplyFulfillmentEvent applies a carrier event to the sale whose ID or
+// tracking number is reference. Carriers retry and reorder events, so an
+// event that doesn't move the sale forward is ignored and reported with
+// applied false; a delivered event for a sale never marked shipped records
+// the shipment too. at is when the carrier observed the status.
+func (s *Service) ApplyFulfillmentEvent(reference string, update FulfillmentUpdate, at time.Time, actor Actor) (sale *Sale, applied bool, err error) {
+	if _, ok := fulfillmentRank[update.Status]; !ok || update.Status == FulfillmentPending {
+		return nil, false, ErrInvalidFulfillmentStatus
+	}
+	sale, err = s.saleByReference(reference)
+	if err != nil {
+		return nil, false, err
+	}
+	if fulfillmentRank[update.Status] <= fulfillmentRank[sale.fulfillmentStatus()] {
+		return sale, false, nil
+	}
+	if sale.Status != StatusApproved {
+		return nil, false, ErrNotFulfillable
+	}
+	if len(update.Carrier) > maxMetadataKeyLen || len(update.TrackingNumber) > maxMetadataValueLen {
+		return nil, false, fmt.Errorf("invalid shipment details")
+	}
+
+	sale, err = s.saveFulfillment(sale, update, at, actor)
+	if err != nil {
+		return nil, false, err
+	}
+	return sale, true, nil
+}
+
+// saleByReference busca la venta por ID y, si no existe, por número de
+// seguimiento en el índice del storage.
+func (s *Service) saleByReference(reference string) (*Sale, error) {
+	if reference == "" {
+		return nil, ErrNotFound
+	}
+	if sale, err := s.storage.Read(reference); err == nil {
+		return sale, nil
+	}
+	sale, err := saleWithTracking(s.storage, reference)
+	if errors.Is(err, ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sale by tracking number: %w", err)
+	}
+	return sale, nil
+}
+
+// saveFulfillment persiste el nuevo estado de entrega ya validado.
+func (s *Service) saveFulfillment(sale *Sale, update FulfillmentUpdate, at time.Time, actor Actor) (*Sale, error) {
+	at = at.UTC()
+	before := sale.clone()
+	updated := sale.clone()
+	updated.FulfillmentStatus = update.Status
+	if update.Carrier != "" {
+		updated.Carrier = update.Carrier
+	}
+	if update.TrackingNumber != "" {
+		updated.TrackingNumber = update.TrackingNumber
+	}
+	if updated.ShippedAt == nil {
+		updated.ShippedAt = &at
+	}
+	if update.Status == FulfillmentDelivered {
+		updated.DeliveredAt = &at
+	}
+	updated.UpdatedAt = utcNow()
+	updated.Version++
+
+	if err := s.saveAudited(AuditActionFulfillment, before, updated, actor); err != nil {
+		s.logger.Error("failed to update fulfillment", zap.String("sale_id", sale.ID), zap.Error(err))
+		return nil, fmt.Errorf("failed to update sale: %w", err)
+	}
+	s.notify(EventSaleFulfillmentChanged, updated)
+	s.logger.Info("sale fulfillment updated", zap.String("sale_id", sale.ID), zap.String("fulfillment_status", update.Status))
+	return updated, nil
+}
+
+// PublicStatus is what customers see when tracking a purchase without
+// authenticating: the statuses and their timestamps, nothing about the
+// buyer, the amount or the metadata.
+type PublicStatus struct {
+	Status            string     `json:"status"`
+	FulfillmentStatus string     `json:"fulfillment_status"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	ShippedAt         *time.Time `json:"shipped_at,omitempty"`
+	DeliveredAt       *time.Time `json:"delivered_at,omitempty"`
+}
+
+// PublicSaleStatus returns the public status of the sale whose ID or
+// tracking number is reference. Drafts were never purchased and are reported
+// as not found.
+func (s *Service) PublicSaleStatus(reference string) (PublicStatus, error) {
+	sale, err := s.saleByReference(reference)
+	if err != nil {
+		return PublicStatus{}, err
+	}
+	if sale.Status == StatusDraft {
+		return PublicStatus{}, ErrNotFound
+	}
+	return PublicStatus{
+		Status:            sale.Status,
+		FulfillmentStatus: sale.fulfillmentStatus(),
+		CreatedAt:         sale.CreatedAt,
+		UpdatedAt:         sale.UpdatedAt,
+		ShippedAt:         sale.ShippedAt,
+		DeliveredAt:       sale.DeliveredAt,
+	}, nil
+}
@@ -0,0 +1,51 @@
+package sales
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// TestUpdateFulfillment_Workflow verifica el flujo de entrega, independiente
+// del estado de pago.
+func TestUpdateFulfillment_Workflow(t *testing.T) {
+	storage := NewLocalStorage()
+	svc := NewService(storage, zaptest.NewLogger(t), "http://unused")
+	storage.Set(&Sale{ID: "paid", Status: StatusApproved, Amount: 10, Version: 1})
+	storage.Set(&Sale{ID: "unpaid", Status: StatusPending, Amount: 10, FulfillmentStatus: FulfillmentPending, Version: 1})
+
+	if _, err := svc.UpdateFulfillment("unpaid", FulfillmentUpdate{Status: FulfillmentShipped}, Actor{}); err != ErrNotFulfillable {
+		t.Errorf("expected unpaid sales not to ship, got %v", err)
+	}
+	if _, err := svc.UpdateFulfillment("paid", FulfillmentUpdate{Status: FulfillmentDelivered}, Actor{}); err != ErrInvalidTransition {
+		t.Errorf("expected delivery to require shipping first, got %v", err)
+	}
+	if _, err := svc.UpdateFulfillment("paid", FulfillmentUpdate{Status: "lost"}, Actor{}); err != ErrInvalidFulfillmentStatus {
+		t.Errorf("expected ErrInvalidFulfillmentStatus, got %v", err)
+	}
+
+	// La venta "paid" no tiene estado de entrega guardado: cuenta como pendiente
+	shipped, err := svc.UpdateFulfillment("paid", FulfillmentUpdate{Status: FulfillmentShipped, Carrier: "dhl", TrackingNumber: "JD0142"}, Actor{ID: "shipping-bot"})
+	if err != nil {
+		t.Fatalf("UpdateFulfillment returned error: %v", err)
+	}
+	if shipped.Status != StatusApproved || shipped.ShippedAt == nil || shipped.TrackingNumber != "JD0142" {
+		t.Errorf("unexpected shipped sale: %+v", shipped)
+	}
+
+	delivered, err := svc.UpdateFulfillment("paid", FulfillmentUpdate{Status: FulfillmentDelivered}, Actor{ID: "shipping-bot"})
+	if err != nil {
+		t.Fatalf("UpdateFulfillment returned error: %v", err)
+	}
+	if delivered.DeliveredAt == nil || delivered.Carrier != "dhl" || delivered.Version != 3 {
+		t.Errorf("unexpected delivered sale: %+v", delivered)
+	}
+
+	results, _, err := svc.SearchSale(SearchFilter{FulfillmentStatus: FulfillmentPending})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].ID != "unpaid" {
+		t.Errorf("expected only the unpaid sale pending fulfillment, got %d results", len(results))
+	}
+}
@@ -0,0 +1,151 @@
+package sales
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// StorageHealth keeps the latency and outcome of the latest storage calls,
+// the live signal used to shed load when storage degrades.
+type StorageHealth struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	samples []healthSample
+	next    int
+}
+
+type healthSample struct {
+	at      time.Time
+	latency time.Duration
+	failed  bool
+}
+
+// HealthSnapshot summarizes the storage calls within the window.
+type HealthSnapshot struct {
+	Samples    int           `json:"samples"`
+	ErrorRate  float64       `json:"error_rate"`
+	P95Latency time.Duration `json:"p95_latency"`
+}
+
+// NewStorageHealth tracks up to capacity calls made within window.
+func NewStorageHealth(window time.Duration, capacity int) *StorageHealth {
+	return &StorageHealth{window: window, samples: make([]healthSample, 0, capacity)}
+}
+
+// Observe records a storage call.
+func (h *StorageHealth) Observe(at time.Time, latency time.Duration, failed bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	sample := healthSample{at: at, latency: latency, failed: failed}
+	if len(h.samples) < cap(h.samples) {
+		h.samples = append(h.samples, sample)
+		return
+	}
+	h.samples[h.next] = sample
+	h.next = (h.next + 1) % len(h.samples)
+}
+
+// Snapshot summarizes the calls observed since now minus the window.
+func (h *StorageHealth) Snapshot(now time.Time) HealthSnapshot {
+	h.mu.Lock()
+	latencies := make([]time.Duration, 0, len(h.samples))
+	failed := 0
+	since := now.Add(-h.window)
+	for _, sample := range h.samples {
+		if sample.at.Before(since) {
+			continue
+		}
+		latencies = append(latencies, sample.latency)
+		if sample.failed {
+			failed++
+		}
+	}
+	h.mu.Unlock()
+
+	snapshot := HealthSnapshot{Samples: len(latencies)}
+	if len(latencies) == 0 {
+		return snapshot
+	}
+	slices.Sort(latencies)
+	snapshot.ErrorRate = float64(failed) / float64(len(latencies))
+	snapshot.P95Latency = latencies[(len(latencies)*95+99)/100-1]
+	return snapshot
+}
+
+// monitoredStorage mide cada llamada al storage; una venta inexistente no
+// cuenta como falla.
+type monitoredStorage struct {
+	Storage
+	health *StorageHealth
+}
+
+// NewMonitoredStorage reports the latency and errors of every call to
+// storage to health.
+func NewMonitoredStorage(storage Storage, health *StorageHealth) Storage {
+	return &monitoredStorage{Storage: storage, health: health}
+}
+
+func (m *monitoredStorage) observe(start time.Time, err error) {
+	m.health.Observe(start, time.Since(start), err != nil && !errors.Is(err, ErrNotFound))
+}
+
+func (m *monitoredStorage) Set(sale *Sale) error {
+	start := time.Now()
+	err := m.Storage.Set(sale)
+	m.observe(start, err)
+	return err
+}
+
+func (m *monitoredStorage) Read(id string) (*Sale, error) {
+	start := time.Now()
+	sale, err := m.Storage.Read(id)
+	m.observe(start, err)
+	return sale, err
+}
+
+func (m *monitoredStorage) GetAll() ([]*Sale, error) {
+	start := time.Now()
+	all, err := m.Storage.GetAll()
+	m.observe(start, err)
+	return all, err
+}
+
+func (m *monitoredStorage) GetByUser(userID string) ([]*Sale, error) {
+	start := time.Now()
+	owned, err := salesOfUser(m.Storage, userID)
+	m.observe(start, err)
+	return owned, err
+}
+
+func (m *monitoredStorage) GetByStatus(status string) ([]*Sale, error) {
+	start := time.Now()
+	found, err := salesWithStatus(m.Storage, status)
+	m.observe(start, err)
+	return found, err
+}
+
+func (m *monitoredStorage) GetByTrackingNumber(trackingNumber string) (*Sale, error) {
+	start := time.Now()
+	sale, err := saleWithTracking(m.Storage, trackingNumber)
+	m.observe(start, err)
+	return sale, err
+}
+
+func (m *monitoredStorage) Query(q SaleQuery) ([]*Sale, error) {
+	start := time.Now()
+	found, err := findSales(m.Storage, q)
+	m.observe(start, err)
+	return found, err
+}
+
+func (m *monitoredStorage) WithTx(ctx context.Context, fn func(tx Storage) error) error {
+	return m.Storage.WithTx(ctx, func(tx Storage) error {
+		return fn(&monitoredStorage{Storage: tx, health: m.health})
+	})
+}
+
+func (m *monitoredStorage) unwrap() Storage { return m.Storage }
@@ -0,0 +1,78 @@
+package sales
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// verifica que el snapshot calcula el p95 y la tasa de error solo con las
+// llamadas dentro de la ventana
+func TestStorageHealthSnapshot(t *testing.T) {
+	health := NewStorageHealth(time.Minute, 100)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	health.Observe(now.Add(-2*time.Minute), time.Second, true)
+	for i := 1; i <= 20; i++ {
+		health.Observe(now.Add(-time.Second), time.Duration(i)*time.Millisecond, i%4 == 0)
+	}
+
+	snapshot := health.Snapshot(now)
+	if snapshot.Samples != 20 {
+		t.Fatalf("expected 20 samples within the window, got %d", snapshot.Samples)
+	}
+	if snapshot.ErrorRate != 0.25 {
+		t.Errorf("expected error rate 0.25, got %v", snapshot.ErrorRate)
+	}
+	if snapshot.P95Latency != 19*time.Millisecond {
+		t.Errorf("expected p95 of 19ms, got %s", snapshot.P95Latency)
+	}
+}
+
+// verifica que el buffer conserva solo las últimas llamadas
+func TestStorageHealthKeepsLatestSamples(t *testing.T) {
+	health := NewStorageHealth(time.Minute, 3)
+	now := time.Now()
+	health.Observe(now, time.Millisecond, true)
+	for i := 0; i < 3; i++ {
+		health.Observe(now, time.Millisecond, false)
+	}
+
+	snapshot := health.Snapshot(now)
+	if snapshot.Samples != 3 || snapshot.ErrorRate != 0 {
+		t.Errorf("expected the failed call evicted, got %+v", snapshot)
+	}
+}
+
+type failingStorage struct {
+	Storage
+	err error
+}
+
+func (f *failingStorage) GetAll() ([]*Sale, error) {
+	return nil, f.err
+}
+
+// verifica que las ventas inexistentes no cuentan como fallas del storage
+func TestMonitoredStorageCountsFailures(t *testing.T) {
+	health := NewStorageHealth(time.Minute, 10)
+	storage := NewMonitoredStorage(&failingStorage{Storage: NewLocalStorage(), err: errors.New("connection refused")}, health)
+
+	if _, err := storage.Read("missing"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := storage.GetAll(); err == nil {
+		t.Fatalf("expected the storage error")
+	}
+	if err := storage.Set(&Sale{ID: "s1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	snapshot := health.Snapshot(time.Now())
+	if snapshot.Samples != 3 {
+		t.Fatalf("expected 3 samples, got %d", snapshot.Samples)
+	}
+	if snapshot.ErrorRate != 1.0/3 {
+		t.Errorf("expected only the GetAll failure counted, got rate %v", snapshot.ErrorRate)
+	}
+}
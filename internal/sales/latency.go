@@ -0,0 +1,73 @@
+package sales
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var decisionLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "sales_decision_latency_seconds",
+	Help:    "Time from a sale entering pending to its approval or rejection.",
+	Buckets: prometheus.ExponentialBuckets(60, 2, 14),
+}, []string{"outcome"})
+
+// LatencyPercentiles summarizes how long reviewers took to approve or reject
+// pending sales, in whole seconds.
+type LatencyPercentiles struct {
+	Count int     `json:"count"`
+	P50   float64 `json:"p50_seconds"`
+	P90   float64 `json:"p90_seconds"`
+	P99   float64 `json:"p99_seconds"`
+}
+
+// pendingSince retorna cuándo la venta entró en pending; las ventas anteriores
+// a PendingSince usan CreatedAt.
+func (s *Sale) pendingSince() time.Time {
+	if s.PendingSince != nil {
+		return *s.PendingSince
+	}
+	return s.CreatedAt
+}
+
+// decisionLatency retorna cuánto tardó la aprobación o el rechazo.
+func (s *Sale) decisionLatency() (time.Duration, bool) {
+	if s.DecidedAt == nil {
+		return 0, false
+	}
+	return s.DecidedAt.Sub(s.pendingSince()), true
+}
+
+// exceededSLA indica si la decisión tardó más que target o, si sigue
+// pendiente, si ya lleva más que target esperando en at.
+func (s *Sale) exceededSLA(target time.Duration, at time.Time) bool {
+	if latency, ok := s.decisionLatency(); ok {
+		return latency > target
+	}
+	return s.Status == StatusPending && at.Sub(s.pendingSince()) > target
+}
+
+// recordDecision registra la aprobación o el rechazo de una venta pendiente.
+func recordDecision(sale *Sale, at time.Time) {
+	sale.DecidedAt = &at
+	if latency, ok := sale.decisionLatency(); ok {
+		decisionLatency.WithLabelValues(sale.Status).Observe(latency.Seconds())
+	}
+}
+
+// latencyPercentiles calcula los percentiles por rango más cercano; nil sin
+// decisiones.
+func latencyPercentiles(latencies []time.Duration) *LatencyPercentiles {
+	if len(latencies) == 0 {
+		return nil
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	rank := func(p float64) float64 {
+		i := int(math.Ceil(p*float64(len(latencies)))) - 1
+		return math.Round(latencies[max(i, 0)].Seconds())
+	}
+	return &LatencyPercentiles{Count: len(latencies), P50: rank(0.5), P90: rank(0.9), P99: rank(0.99)}
+}
@@ -0,0 +1,57 @@
+package sales
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// TestDecisionLatency_StatsAndSLAFilter verifica que la aprobación registre
+// su latencia, que stats reporte percentiles y que exceeded_sla filtre tanto
+// decisiones lentas como pendientes que ya superaron el objetivo.
+func TestDecisionLatency_StatsAndSLAFilter(t *testing.T) {
+	storage := NewLocalStorage()
+	svc := NewService(storage, zaptest.NewLogger(t), "http://unused")
+	now := utcNow()
+	since := func(d time.Duration) *time.Time {
+		at := now.Add(-d)
+		return &at
+	}
+	storage.Set(&Sale{ID: "slow", Status: StatusPending, CreatedAt: now.Add(-5 * time.Hour), PendingSince: since(5 * time.Hour)})
+	storage.Set(&Sale{ID: "fast", Status: StatusPending, CreatedAt: now.Add(-time.Minute), PendingSince: since(time.Minute)})
+	storage.Set(&Sale{ID: "waiting", Status: StatusPending, CreatedAt: now.Add(-3 * time.Hour), PendingSince: since(3 * time.Hour)})
+
+	for _, id := range []string{"slow", "fast"} {
+		sale, err := svc.UpdateSaleStatus(id, StatusApproved)
+		if err != nil {
+			t.Fatalf("UpdateSaleStatus returned error: %v", err)
+		}
+		if sale.DecidedAt == nil {
+			t.Fatalf("expected %s to record when it was decided", id)
+		}
+	}
+
+	stats, err := svc.GetStats()
+	if err != nil {
+		t.Fatalf("GetStats returned error: %v", err)
+	}
+	if stats.DecisionLatency == nil || stats.DecisionLatency.Count != 2 {
+		t.Fatalf("expected latency percentiles over 2 decisions, got %+v", stats.DecisionLatency)
+	}
+	if p50, p99 := stats.DecisionLatency.P50, stats.DecisionLatency.P99; p50 < 60 || p50 > 120 || p99 < 5*3600 {
+		t.Errorf("unexpected percentiles p50=%v p99=%v", p50, p99)
+	}
+
+	results, _, err := svc.SearchSale(SearchFilter{ExceededSLA: 2 * time.Hour})
+	if err != nil {
+		t.Fatalf("SearchSale returned error: %v", err)
+	}
+	ids := map[string]bool{}
+	for _, sale := range results {
+		ids[sale.ID] = true
+	}
+	if len(results) != 2 || !ids["slow"] || !ids["waiting"] {
+		t.Errorf("expected the slow decision and the long wait, got %v", ids)
+	}
+}
@@ -0,0 +1,137 @@
+package sales
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Ledger accounts used by the export.
+const (
+	AccountReceivable = "accounts_receivable"
+	AccountRevenue    = "sales_revenue"
+	AccountTaxPayable = "tax_payable"
+)
+
+// LedgerLine is one side of a double-entry posting. Every entry (a sale or an
+// adjustment) produces lines whose debits and credits balance.
+type LedgerLine struct {
+	EntryID     string    `json:"entry_id"`
+	SaleID      string    `json:"sale_id"`
+	Date        time.Time `json:"date"`
+	Account     string    `json:"account"`
+	Debit       float64   `json:"debit"`
+	Credit      float64   `json:"credit"`
+	Description string    `json:"description"`
+}
+
+// Ledger returns the ledger lines of approved sales, adjustments and lost
+// disputes dated within [from, to], ordered by date. Disputed and charged back
+// sales keep their original entry; a lost dispute adds its reversal. Nil
+// bounds are open.
+func (s *Service) Ledger(from, to *time.Time) ([]LedgerLine, error) {
+	allSales, err := s.storage.GetAll()
+	if err != nil {
+		s.logger.Error("Failed to get all sales from storage", zap.Error(err))
+		return nil, fmt.Errorf("failed to retrieve sales: %w", err)
+	}
+	adjustments, err := s.adjustments.GetAll()
+	if err != nil {
+		s.logger.Error("Failed to get adjustments from storage", zap.Error(err))
+		return nil, fmt.Errorf("failed to retrieve adjustments: %w", err)
+	}
+
+	inRange := func(t time.Time) bool {
+		return (from == nil || !t.Before(*from)) && (to == nil || !t.After(*to))
+	}
+
+	disputes, err := s.disputes.GetAll()
+	if err != nil {
+		s.logger.Error("Failed to get disputes from storage", zap.Error(err))
+		return nil, fmt.Errorf("failed to retrieve disputes: %w", err)
+	}
+
+	lines := make([]LedgerLine, 0)
+	byID := make(map[string]*Sale, len(allSales))
+	for _, sale := range allSales {
+		byID[sale.ID] = sale
+		switch sale.Status {
+		case StatusApproved, StatusDisputed, StatusChargedBack:
+			if inRange(sale.CreatedAt) {
+				lines = append(lines, saleLedgerLines(sale)...)
+			}
+		}
+	}
+	for _, d := range disputes {
+		if sale, ok := byID[d.SaleID]; ok && d.Status == DisputeLost && inRange(*d.ResolvedAt) {
+			lines = append(lines, chargebackLedgerLines(sale, d)...)
+		}
+	}
+	for _, a := range adjustments {
+		if inRange(a.CreatedAt) {
+			lines = append(lines, adjustmentLedgerLines(a)...)
+		}
+	}
+
+	// Orden estable por fecha manteniendo juntas las líneas de cada asiento
+	sort.SliceStable(lines, func(i, j int) bool {
+		if !lines[i].Date.Equal(lines[j].Date) {
+			return lines[i].Date.Before(lines[j].Date)
+		}
+		return lines[i].EntryID < lines[j].EntryID
+	})
+	return lines, nil
+}
+
+// saleLedgerLines debita el total a cobrar y acredita ingresos e impuestos.
+func saleLedgerLines(sale *Sale) []LedgerLine {
+	line := func(account string, debit, credit float64) LedgerLine {
+		return LedgerLine{
+			EntryID:     sale.ID,
+			SaleID:      sale.ID,
+			Date:        sale.CreatedAt,
+			Account:     account,
+			Debit:       debit,
+			Credit:      credit,
+			Description: "sale " + sale.ID,
+		}
+	}
+
+	lines := []LedgerLine{
+		line(AccountReceivable, sale.Amount, 0),
+		line(AccountRevenue, 0, roundCents(sale.Amount-sale.Tax)),
+	}
+	if sale.Tax > 0 {
+		lines = append(lines, line(AccountTaxPayable, 0, sale.Tax))
+	}
+	return lines
+}
+
+// adjustmentLedgerLines registra un ajuste positivo como más ingreso y uno
+// negativo como su reverso.
+func adjustmentLedgerLines(a *Adjustment) []LedgerLine {
+	line := func(account string, debit, credit float64) LedgerLine {
+		return LedgerLine{
+			EntryID:     a.ID,
+			SaleID:      a.SaleID,
+			Date:        a.CreatedAt,
+			Account:     account,
+			Debit:       debit,
+			Credit:      credit,
+			Description: "adjustment: " + a.Reason,
+		}
+	}
+
+	if a.Amount > 0 {
+		return []LedgerLine{
+			line(AccountReceivable, a.Amount, 0),
+			line(AccountRevenue, 0, a.Amount),
+		}
+	}
+	return []LedgerLine{
+		line(AccountRevenue, -a.Amount, 0),
+		line(AccountReceivable, 0, -a.Amount),
+	}
+}
@@ -0,0 +1,83 @@
+package sales
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Error cuando otro operador tiene el lease de la venta
+var ErrSaleLocked = errors.New("sale is locked by another operator")
+
+// DefaultLockLease is how long a mutation lease is held after it is acquired.
+const DefaultLockLease = 5 * time.Second
+
+// SaleLock is a short lease an operator holds on a sale while mutating it.
+type SaleLock struct {
+	SaleID    string    `json:"sale_id"`
+	Owner     string    `json:"owner"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// MutationLocks hands out per-sale leases so concurrent edits from different
+// operators are rejected instead of silently overwriting each other.
+type MutationLocks struct {
+	mu    sync.Mutex
+	lease time.Duration
+	locks map[string]SaleLock
+	now   func() time.Time
+}
+
+func NewMutationLocks(lease time.Duration) *MutationLocks {
+	if lease <= 0 {
+		lease = DefaultLockLease
+	}
+
+	return &MutationLocks{
+		lease: lease,
+		locks: map[string]SaleLock{},
+		now:   time.Now,
+	}
+}
+
+// Acquire takes or refreshes the lease on saleID for owner. If another owner
+// holds an unexpired lease it returns that lease and ErrSaleLocked.
+func (m *MutationLocks) Acquire(saleID, owner string) (SaleLock, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if current, ok := m.locks[saleID]; ok && current.Owner != owner && now.Before(current.ExpiresAt) {
+		return current, ErrSaleLocked
+	}
+
+	l := SaleLock{SaleID: saleID, Owner: owner, ExpiresAt: now.Add(m.lease)}
+	m.locks[saleID] = l
+	m.purgeExpired(now)
+	return l, nil
+}
+
+// Release drops the lease if owner holds it.
+func (m *MutationLocks) Release(saleID, owner string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.locks[saleID]
+	if !ok || !m.now().Before(current.ExpiresAt) {
+		return nil
+	}
+	if current.Owner != owner {
+		return ErrSaleLocked
+	}
+	delete(m.locks, saleID)
+	return nil
+}
+
+// purgeExpired evita que el mapa crezca con leases vencidos.
+func (m *MutationLocks) purgeExpired(now time.Time) {
+	for id, l := range m.locks {
+		if !now.Before(l.ExpiresAt) {
+			delete(m.locks, id)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package sales
+
+import (
+	"testing"
+	"time"
+)
+
+// TestMutationLocks_RejectsOtherOwnersUntilExpiry verifica el lease por venta.
+func TestMutationLocks_RejectsOtherOwnersUntilExpiry(t *testing.T) {
+	locks := NewMutationLocks(time.Second)
+	now := time.Now()
+	locks.now = func() time.Time { return now }
+
+	if _, err := locks.Acquire("sale-1", "alice"); err != nil {
+		t.Fatalf("Acquire returned error: %v", err)
+	}
+	if _, err := locks.Acquire("sale-1", "alice"); err != nil {
+		t.Errorf("same owner should refresh the lease, got %v", err)
+	}
+
+	current, err := locks.Acquire("sale-1", "bob")
+	if err != ErrSaleLocked {
+		t.Fatalf("expected ErrSaleLocked, got %v", err)
+	}
+	if current.Owner != "alice" {
+		t.Errorf("expected lease owned by alice, got %q", current.Owner)
+	}
+	if err := locks.Release("sale-1", "bob"); err != ErrSaleLocked {
+		t.Errorf("expected ErrSaleLocked releasing someone else's lease, got %v", err)
+	}
+
+	now = now.Add(2 * time.Second)
+	if _, err := locks.Acquire("sale-1", "bob"); err != nil {
+		t.Errorf("expected expired lease to be taken over, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package sales
+
+import "errors"
+
+// MaxLookupIDs is how many sales GetSales returns in one call.
+const MaxLookupIDs = 100
+
+// Error para búsquedas por ID con demasiados IDs
+var ErrTooManyIDs = errors.New("too many ids: at most 100 per request")
+
+// GetSale returns a sale the caller may see. Sales of other users are
+// reported as not found, like in searches.
+func (s *Service) GetSale(saleID string, caller *Caller) (*Sale, error) {
+	sale, err := s.storage.Read(saleID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.canSee(caller, sale) {
+		return nil, ErrNotFound
+	}
+	return sale, nil
+}
+
+// GetSaleByReference returns the sale a customer or carrier refers to, by
+// sale ID or tracking number, if the caller may see it.
+func (s *Service) GetSaleByReference(reference string, caller *Caller) (*Sale, error) {
+	sale, err := s.saleByReference(reference)
+	if err != nil {
+		return nil, err
+	}
+	if !s.canSee(caller, sale) {
+		return nil, ErrNotFound
+	}
+	return sale, nil
+}
+
+// GetSales returns the sales with the given IDs, in request order, and the
+// IDs that don't exist or the caller may not see. Repeated IDs are returned
+// once.
+func (s *Service) GetSales(ids []string, caller *Caller, consistency Consistency) (found []*Sale, missing []string, err error) {
+	if len(ids) > MaxLookupIDs {
+		return nil, nil, ErrTooManyIDs
+	}
+
+	reader := s.reader(consistency)
+	found = make([]*Sale, 0, len(ids))
+	missing = make([]string, 0)
+	seen := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		if _, dup := seen[id]; dup {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		sale, err := reader.Read(id)
+		if err != nil || !s.canSee(caller, sale) {
+			missing = append(missing, id)
+			continue
+		}
+		found = append(found, sale)
+	}
+	return found, missing, nil
+}
+
+// canSee aplica el control de acceso por fila de las búsquedas a una venta.
+func (s *Service) canSee(caller *Caller, sale *Sale) bool {
+	visible := s.visibleUsers(caller)
+	if visible == nil {
+		return true
+	}
+	_, ok := visible[sale.UserID]
+	return ok
+}
@@ -0,0 +1,229 @@
+package sales
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Límites de metadata por venta
+const (
+	maxMetadataKeys     = 20
+	maxMetadataKeyLen   = 40
+	maxMetadataValueLen = 500
+)
+
+// MetadataMask replaces sensitive metadata values for unauthorized callers.
+const MetadataMask = "[REDACTED]"
+
+func validateMetadata(metadata map[string]string) error {
+	if len(metadata) > maxMetadataKeys {
+		return fmt.Errorf("too many metadata keys")
+	}
+	for k, v := range metadata {
+		if k == "" || len(k) > maxMetadataKeyLen || len(v) > maxMetadataValueLen {
+			return fmt.Errorf("invalid metadata entry")
+		}
+	}
+	return nil
+}
+
+// FieldEncrypter encrypts individual values at rest.
+type FieldEncrypter interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(ciphertext string) (string, error)
+}
+
+// WithFieldEncryption encrypts the metadata values under the sensitive keys
+// before they reach storage and decrypts them on read.
+func WithFieldEncryption(enc FieldEncrypter, sensitiveKeys []string) Option {
+	return func(s *Service) {
+		s.encrypter = enc
+		for _, k := range sensitiveKeys {
+			s.sensitiveKeys[strings.ToLower(k)] = struct{}{}
+		}
+	}
+}
+
+// WithSensitiveMetadata marks metadata keys as sensitive without encrypting
+// them, so they are still masked for unauthorized callers.
+func WithSensitiveMetadata(keys []string) Option {
+	return func(s *Service) {
+		for _, k := range keys {
+			s.sensitiveKeys[strings.ToLower(k)] = struct{}{}
+		}
+	}
+}
+
+func (s *Service) sensitive(key string) bool {
+	_, ok := s.sensitiveKeys[strings.ToLower(key)]
+	return ok
+}
+
+// RedactSensitive returns a copy of sale with its sensitive metadata values
+// masked, for callers not allowed to see them.
+func (s *Service) RedactSensitive(sale *Sale) *Sale {
+	redacted := sale.clone()
+	for k := range redacted.Metadata {
+		if s.sensitive(k) {
+			redacted.Metadata[k] = MetadataMask
+		}
+	}
+	return redacted
+}
+
+// encryptedStorage cifra la metadata sensible antes de delegar en el storage.
+type encryptedStorage struct {
+	Storage
+	svc *Service
+}
+
+func (e encryptedStorage) Set(sale *Sale) error {
+	stored, err := e.svc.encryptSensitive(sale)
+	if err != nil {
+		return err
+	}
+	return e.Storage.Set(stored)
+}
+
+func (e encryptedStorage) Read(id string) (*Sale, error) {
+	return encryptedReader{SaleReader: e.Storage, svc: e.svc}.Read(id)
+}
+
+func (e encryptedStorage) GetAll() ([]*Sale, error) {
+	return encryptedReader{SaleReader: e.Storage, svc: e.svc}.GetAll()
+}
+
+func (e encryptedStorage) GetByUser(userID string) ([]*Sale, error) {
+	return encryptedReader{SaleReader: e.Storage, svc: e.svc}.GetByUser(userID)
+}
+
+func (e encryptedStorage) GetByStatus(status string) ([]*Sale, error) {
+	return encryptedReader{SaleReader: e.Storage, svc: e.svc}.GetByStatus(status)
+}
+
+func (e encryptedStorage) GetByTrackingNumber(trackingNumber string) (*Sale, error) {
+	return encryptedReader{SaleReader: e.Storage, svc: e.svc}.GetByTrackingNumber(trackingNumber)
+}
+
+func (e encryptedStorage) Query(q SaleQuery) ([]*Sale, error) {
+	return encryptedReader{SaleReader: e.Storage, svc: e.svc}.Query(q)
+}
+
+func (e encryptedStorage) WithTx(ctx context.Context, fn func(tx Storage) error) error {
+	return e.Storage.WithTx(ctx, func(tx Storage) error {
+		return fn(encryptedStorage{Storage: tx, svc: e.svc})
+	})
+}
+
+func (e encryptedStorage) unwrap() Storage { return e.Storage }
+
+// encryptedReader descifra la metadata sensible de las ventas leídas, por
+// ejemplo de una réplica de lectura.
+type encryptedReader struct {
+	SaleReader
+	svc *Service
+}
+
+func (e encryptedReader) Read(id string) (*Sale, error) {
+	sale, err := e.SaleReader.Read(id)
+	if err != nil {
+		return nil, err
+	}
+	return e.decrypt(sale)
+}
+
+func (e encryptedReader) GetAll() ([]*Sale, error) {
+	all, err := e.SaleReader.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return e.decryptAll(all)
+}
+
+func (e encryptedReader) GetByUser(userID string) ([]*Sale, error) {
+	owned, err := salesOfUser(e.SaleReader, userID)
+	if err != nil {
+		return nil, err
+	}
+	return e.decryptAll(owned)
+}
+
+func (e encryptedReader) GetByStatus(status string) ([]*Sale, error) {
+	found, err := salesWithStatus(e.SaleReader, status)
+	if err != nil {
+		return nil, err
+	}
+	return e.decryptAll(found)
+}
+
+func (e encryptedReader) GetByTrackingNumber(trackingNumber string) (*Sale, error) {
+	sale, err := saleWithTracking(e.SaleReader, trackingNumber)
+	if err != nil {
+		return nil, err
+	}
+	return e.decrypt(sale)
+}
+
+func (e encryptedReader) Query(q SaleQuery) ([]*Sale, error) {
+	found, err := findSales(e.SaleReader, q)
+	if err != nil {
+		return nil, err
+	}
+	return e.decryptAll(found)
+}
+
+func (e encryptedReader) decryptAll(all []*Sale) ([]*Sale, error) {
+	result := make([]*Sale, 0, len(all))
+	for _, sale := range all {
+		decrypted, err := e.decrypt(sale)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, decrypted)
+	}
+	return result, nil
+}
+
+func (e encryptedReader) decrypt(sale *Sale) (*Sale, error) {
+	return e.svc.decryptSensitive(sale)
+}
+
+// encryptSensitive retorna una copia de sale con la metadata sensible cifrada;
+// sin encrypter la copia queda en claro, como en el storage.
+func (s *Service) encryptSensitive(sale *Sale) (*Sale, error) {
+	stored := sale.clone()
+	if s.encrypter == nil {
+		return stored, nil
+	}
+	for k, v := range stored.Metadata {
+		if !s.sensitive(k) {
+			continue
+		}
+		ciphertext, err := s.encrypter.Encrypt(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt metadata %q: %w", k, err)
+		}
+		stored.Metadata[k] = ciphertext
+	}
+	return stored, nil
+}
+
+// decryptSensitive es la inversa de encryptSensitive.
+func (s *Service) decryptSensitive(sale *Sale) (*Sale, error) {
+	if len(sale.Metadata) == 0 || s.encrypter == nil {
+		return sale, nil
+	}
+	plain := sale.clone()
+	for k, v := range plain.Metadata {
+		if !s.sensitive(k) {
+			continue
+		}
+		value, err := s.encrypter.Decrypt(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt metadata %q of sale %s: %w", k, sale.ID, err)
+		}
+		plain.Metadata[k] = value
+	}
+	return plain, nil
+}
@@ -0,0 +1,40 @@
+package sales
+
+import (
+	"api_sales/internal/migrate"
+	"embed"
+	"io/fs"
+)
+
+// Migraciones del esquema de cada backend SQL, en migrations/<dialecto>.
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+func loadMigrations(dialect string, vars map[string]string) ([]migrate.Migration, error) {
+	dir, err := fs.Sub(migrationFiles, "migrations/"+dialect)
+	if err != nil {
+		return nil, err
+	}
+	return migrate.Load(dir, vars)
+}
+
+// Migrator returns the runner of the sales table's schema migrations, tracked
+// in the table <table>_schema_migrations.
+func (m *MySQLStorage) Migrator() (*migrate.Runner, error) {
+	migrations, err := loadMigrations(migrate.DialectMySQL, map[string]string{"table": m.table})
+	if err != nil {
+		return nil, err
+	}
+	return migrate.NewRunner(m.db, migrate.DialectMySQL, m.table+"_schema_migrations", migrations), nil
+}
+
+// Migrator returns the runner of the sales table's schema migrations, tracked
+// in the table sales_schema_migrations.
+func (s *SQLiteStorage) Migrator() (*migrate.Runner, error) {
+	migrations, err := loadMigrations(migrate.DialectSQLite, nil)
+	if err != nil {
+		return nil, err
+	}
+	return migrate.NewRunner(s.db, migrate.DialectSQLite, "sales_schema_migrations", migrations), nil
+}
@@ -0,0 +1,6 @@
+-- Tabla original; IF NOT EXISTS adopta las tablas creadas antes de las migraciones
+CREATE TABLE IF NOT EXISTS `{{table}}` (
+  id VARCHAR(64) NOT NULL PRIMARY KEY,
+  data JSON NOT NULL,
+  updated_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6)
+);
@@ -0,0 +1,4 @@
+-- Búsqueda por número de seguimiento desde el endpoint público y los eventos de envío
+ALTER TABLE `{{table}}`
+  ADD COLUMN tracking_number VARCHAR(500) GENERATED ALWAYS AS (JSON_UNQUOTE(JSON_EXTRACT(data, '$.tracking_number'))) VIRTUAL,
+  ADD INDEX `{{table}}_tracking_number` (tracking_number);
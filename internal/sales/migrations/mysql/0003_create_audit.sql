@@ -0,0 +1,14 @@
+-- Auditoría en la misma base que las ventas: cada cambio, su entrada y la
+-- cabeza de la cadena se confirman en una transacción
+CREATE TABLE IF NOT EXISTS `{{table}}_audit` (
+  seq BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  id VARCHAR(64) NOT NULL UNIQUE,
+  sale_id VARCHAR(64) NOT NULL,
+  data JSON NOT NULL,
+  INDEX `{{table}}_audit_sale_id` (sale_id, seq)
+);
+CREATE TABLE IF NOT EXISTS `{{table}}_audit_head` (
+  id TINYINT NOT NULL PRIMARY KEY,
+  hash VARCHAR(64) NOT NULL
+);
+INSERT IGNORE INTO `{{table}}_audit_head` (id, hash) VALUES (1, '');
@@ -0,0 +1,6 @@
+-- Tabla original; IF NOT EXISTS adopta las tablas creadas antes de las migraciones
+CREATE TABLE IF NOT EXISTS sales (
+  id TEXT NOT NULL PRIMARY KEY,
+  data TEXT NOT NULL,
+  updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
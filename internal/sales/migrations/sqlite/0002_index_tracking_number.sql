@@ -0,0 +1,2 @@
+-- Búsqueda por número de seguimiento desde el endpoint público y los eventos de envío
+CREATE INDEX IF NOT EXISTS sales_tracking_number ON sales (json_extract(data, '$.tracking_number'));
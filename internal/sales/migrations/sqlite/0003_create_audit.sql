@@ -0,0 +1,14 @@
+-- Auditoría en la misma base que las ventas: cada cambio, su entrada y la
+-- cabeza de la cadena se confirman en una transacción
+CREATE TABLE IF NOT EXISTS sales_audit (
+  seq INTEGER PRIMARY KEY AUTOINCREMENT,
+  id TEXT NOT NULL UNIQUE,
+  sale_id TEXT NOT NULL,
+  data TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS sales_audit_sale_id ON sales_audit (sale_id, seq);
+CREATE TABLE IF NOT EXISTS sales_audit_head (
+  id INTEGER NOT NULL PRIMARY KEY CHECK (id = 1),
+  hash TEXT NOT NULL
+);
+INSERT OR IGNORE INTO sales_audit_head (id, hash) VALUES (1, '');
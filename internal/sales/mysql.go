@@ -0,0 +1,193 @@
+package sales
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlTableName limita el nombre de la tabla, que se interpola en las
+// consultas, a un identificador simple.
+var mysqlTableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,63}$`)
+
+// sqlQuerier is what MySQLStorage runs its statements on: the pool or, inside
+// WithTx, the transaction.
+type sqlQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// sqlWhere arma el WHERE de q; extract retorna la expresión SQL que lee un
+// campo del documento JSON.
+func (q SaleQuery) sqlWhere(extract func(field string) string) (string, []any) {
+	var conds []string
+	var args []any
+	if q.UserID != "" {
+		conds = append(conds, extract("user_id")+" = ?")
+		args = append(args, q.UserID)
+	}
+	if q.Status != "" {
+		conds = append(conds, extract("status")+" = ?")
+		args = append(args, q.Status)
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// MySQLStorage keeps each sale as a JSON document in a MySQL or MariaDB table
+// keyed by id. The pool (size, connection lifetime) is configured on db by
+// the caller; timeout bounds every statement.
+type MySQLStorage struct {
+	db      *sql.DB
+	q       sqlQuerier
+	table   string
+	timeout time.Duration
+}
+
+func NewMySQLStorage(db *sql.DB, table string, timeout time.Duration) (*MySQLStorage, error) {
+	if !mysqlTableName.MatchString(table) {
+		return nil, fmt.Errorf("invalid mysql table name %q", table)
+	}
+	return &MySQLStorage{db: db, q: db, table: table, timeout: timeout}, nil
+}
+
+func (m *MySQLStorage) Set(sale *Sale) error {
+	if sale.ID == "" {
+		return ErrEmptyID
+	}
+	data, err := json.Marshal(sale)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+	_, err = m.q.ExecContext(ctx, "INSERT INTO `"+m.table+"` (id, data) VALUES (?, ?) ON DUPLICATE KEY UPDATE data = VALUES(data)", sale.ID, string(data))
+	if err != nil {
+		return fmt.Errorf("mysql put sale: %w", err)
+	}
+	return nil
+}
+
+func (m *MySQLStorage) Read(id string) (*Sale, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+	var data []byte
+	err := m.q.QueryRowContext(ctx, "SELECT data FROM `"+m.table+"` WHERE id = ?", id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("mysql get sale: %w", err)
+	}
+	return decodeMySQLSale(data)
+}
+
+// GetAll lee la tabla completa en orden de id.
+func (m *MySQLStorage) GetAll() ([]*Sale, error) {
+	return m.list("SELECT data FROM `" + m.table + "` ORDER BY id")
+}
+
+// Query filtra con JSON_EXTRACT sobre el documento, sin índices: evita
+// transferir y decodificar las ventas descartadas, no leerlas.
+func (m *MySQLStorage) Query(q SaleQuery) ([]*Sale, error) {
+	where, args := q.sqlWhere(func(field string) string { return "JSON_UNQUOTE(JSON_EXTRACT(data, '$." + field + "'))" })
+	return m.list("SELECT data FROM `"+m.table+"`"+where+" ORDER BY id", args...)
+}
+
+// GetByTrackingNumber usa la columna generada e indexada tracking_number,
+// creada por la migración 0002.
+func (m *MySQLStorage) GetByTrackingNumber(trackingNumber string) (*Sale, error) {
+	found, err := m.list("SELECT data FROM `"+m.table+"` WHERE tracking_number = ? ORDER BY id LIMIT 1", trackingNumber)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, ErrNotFound
+	}
+	return found[0], nil
+}
+
+func (m *MySQLStorage) list(query string, args ...any) ([]*Sale, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+	rows, err := m.q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("mysql list sales: %w", err)
+	}
+	defer rows.Close()
+
+	sales := make([]*Sale, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("mysql list sales: %w", err)
+		}
+		sale, err := decodeMySQLSale(data)
+		if err != nil {
+			return nil, err
+		}
+		sales = append(sales, sale)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("mysql list sales: %w", err)
+	}
+	return sales, nil
+}
+
+// WithTx runs fn in a MySQL transaction, committed if fn returns nil and
+// rolled back otherwise. Nested calls join the outer transaction.
+func (m *MySQLStorage) WithTx(ctx context.Context, fn func(tx Storage) error) error {
+	if m.q != m.db {
+		return fn(m)
+	}
+	sqlTx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("mysql begin transaction: %w", err)
+	}
+	tx := &MySQLStorage{db: m.db, q: sqlTx, table: m.table, timeout: m.timeout}
+	if err := fn(tx); err != nil {
+		sqlTx.Rollback()
+		return err
+	}
+	if err := sqlTx.Commit(); err != nil {
+		return fmt.Errorf("mysql commit transaction: %w", err)
+	}
+	return nil
+}
+
+func decodeMySQLSale(data []byte) (*Sale, error) {
+	var sale Sale
+	if err := json.Unmarshal(data, &sale); err != nil {
+		return nil, fmt.Errorf("mysql decode sale: %w", err)
+	}
+	return &sale, nil
+}
+
+// IsTransientMySQLError adds to IsTransientError the MySQL errors worth
+// retrying: deadlocks, lock wait timeouts, too many connections and
+// connections the server closed.
+func IsTransientMySQLError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case 1205, 1213, 1040:
+			return true
+		}
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) {
+		return true
+	}
+	return IsTransientError(err)
+}
@@ -0,0 +1,189 @@
+package sales
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+)
+
+func newMockMySQLStorage(t *testing.T) (*MySQLStorage, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+	storage, err := NewMySQLStorage(db, "sales", time.Second)
+	if err != nil {
+		t.Fatalf("NewMySQLStorage: %v", err)
+	}
+	return storage, mock
+}
+
+func TestMySQLStorageRoundTrip(t *testing.T) {
+	storage, mock := newMockMySQLStorage(t)
+
+	mock.ExpectExec("INSERT INTO `sales` \\(id, data\\) VALUES \\(\\?, \\?\\) ON DUPLICATE KEY UPDATE").
+		WithArgs("s1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := storage.Set(&Sale{ID: "s1", UserID: "u1", Amount: 10, Status: StatusApproved}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	mock.ExpectQuery("SELECT data FROM `sales` WHERE id = \\?").
+		WithArgs("s1").
+		WillReturnRows(sqlmock.NewRows([]string{"data"}).AddRow(`{"id":"s1","user_id":"u1","amount":10,"status":"approved"}`))
+	sale, err := storage.Read("s1")
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if sale.UserID != "u1" || sale.Amount != 10 {
+		t.Errorf("unexpected sale %+v", sale)
+	}
+
+	mock.ExpectQuery("SELECT data FROM `sales` WHERE id = \\?").
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows([]string{"data"}))
+	if _, err := storage.Read("missing"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	mock.ExpectQuery("SELECT data FROM `sales` ORDER BY id").
+		WillReturnRows(sqlmock.NewRows([]string{"data"}).AddRow(`{"id":"a"}`).AddRow(`{"id":"b"}`))
+	all, err := storage.GetAll()
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+	if len(all) != 2 || all[0].ID != "a" || all[1].ID != "b" {
+		t.Errorf("unexpected sales %+v", all)
+	}
+
+	mock.ExpectQuery("SELECT data FROM `sales` WHERE JSON_UNQUOTE\\(JSON_EXTRACT\\(data, '\\$.user_id'\\)\\) = \\? AND JSON_UNQUOTE\\(JSON_EXTRACT\\(data, '\\$.status'\\)\\) = \\? ORDER BY id").
+		WithArgs("u1", StatusApproved).
+		WillReturnRows(sqlmock.NewRows([]string{"data"}).AddRow(`{"id":"s1","user_id":"u1","status":"approved"}`))
+	found, err := storage.Query(SaleQuery{UserID: "u1", Status: StatusApproved})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(found) != 1 || found[0].ID != "s1" {
+		t.Errorf("unexpected sales %+v", found)
+	}
+
+	mock.ExpectQuery("SELECT data FROM `sales` WHERE tracking_number = \\? ORDER BY id LIMIT 1").
+		WithArgs("T1").
+		WillReturnRows(sqlmock.NewRows([]string{"data"}).AddRow(`{"id":"s1","tracking_number":"T1"}`))
+	sale, err = storage.GetByTrackingNumber("T1")
+	if err != nil || sale.ID != "s1" {
+		t.Errorf("expected s1, got %+v (err=%v)", sale, err)
+	}
+	mock.ExpectQuery("SELECT data FROM `sales` WHERE tracking_number = \\?").
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows([]string{"data"}))
+	if _, err := storage.GetByTrackingNumber("missing"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestMySQLStorageWithTx(t *testing.T) {
+	storage, mock := newMockMySQLStorage(t)
+
+	// Confirma cuando fn no falla; las escrituras van por la transacción
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `sales`").WithArgs("s1", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err := storage.WithTx(context.Background(), func(tx Storage) error {
+		return tx.WithTx(context.Background(), func(inner Storage) error {
+			return inner.Set(&Sale{ID: "s1"})
+		})
+	})
+	if err != nil {
+		t.Fatalf("WithTx: %v", err)
+	}
+
+	// Revierte cuando fn falla
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	boom := errors.New("boom")
+	if err := storage.WithTx(context.Background(), func(Storage) error { return boom }); err != boom {
+		t.Errorf("expected fn's error, got %v", err)
+	}
+}
+
+func TestMySQLAuditStorageAppendsInTheSaleTransaction(t *testing.T) {
+	storage, mock := newMockMySQLStorage(t)
+	audit := storage.AuditStorage()
+	if audit.InTx(storage) != nil {
+		t.Errorf("expected no audit log outside a transaction")
+	}
+
+	// La cabeza se bloquea y se mueve en la transacción que escribe la venta
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT hash FROM `sales_audit_head` WHERE id = 1 FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}).AddRow("h0"))
+	mock.ExpectExec("INSERT INTO `sales_audit` \\(id, sale_id, data\\) VALUES").
+		WithArgs("e1", "s1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO `sales_audit_head` \\(id, hash\\) VALUES \\(1, \\?\\) ON DUPLICATE KEY UPDATE").
+		WithArgs("h1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO `sales`").WithArgs("s1", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err := storage.WithTx(context.Background(), func(tx Storage) error {
+		log := audit.InTx(&monitoredStorage{Storage: tx})
+		if log == nil {
+			t.Fatal("expected the audit log of the transaction")
+		}
+		if err := log.Append(&AuditEntry{ID: "e1", SaleID: "s1", PrevHash: "h0", Hash: "h1"}); err != nil {
+			return err
+		}
+		return tx.Set(&Sale{ID: "s1"})
+	})
+	if err != nil {
+		t.Fatalf("WithTx: %v", err)
+	}
+
+	// Fuera de una transacción abre la suya y rechaza una cabeza vieja
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT hash FROM `sales_audit_head` WHERE id = 1 FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}).AddRow("h1"))
+	mock.ExpectRollback()
+	if err := audit.Append(&AuditEntry{ID: "e2", SaleID: "s1", PrevHash: "h0"}); err != ErrAuditChainMoved {
+		t.Errorf("expected ErrAuditChainMoved, got %v", err)
+	}
+}
+
+func TestNewMySQLStorageRejectsUnsafeTableNames(t *testing.T) {
+	for _, table := range []string{"", "sales; DROP TABLE users", "sa`les", "1sales"} {
+		if _, err := NewMySQLStorage(nil, table, time.Second); err == nil {
+			t.Errorf("expected %q rejected", table)
+		}
+	}
+}
+
+func TestIsTransientMySQLError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{fmt.Errorf("mysql put sale: %w", &mysql.MySQLError{Number: 1213, Message: "Deadlock found"}), true},
+		{&mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"}, true},
+		{&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}, false},
+		{fmt.Errorf("mysql get sale: %w", driver.ErrBadConn), true},
+		{errors.New("syntax error"), false},
+	}
+	for _, tt := range tests {
+		if got := IsTransientMySQLError(tt.err); got != tt.want {
+			t.Errorf("IsTransientMySQLError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package sales
+
+import "api_sales/internal/sales/events"
+
+const (
+	EventSaleCreated            = "sale.created"
+	EventSaleUpdated            = "sale.updated"
+	EventSaleStatusChanged      = "sale.status_changed"
+	EventSaleAdjusted           = "sale.adjusted"
+	EventSalePostingChanged     = "sale.erp_posting_changed"
+	EventSaleFulfillmentChanged = "sale.fulfillment_changed"
+	EventSaleSLABreached        = "sale.sla_breached"
+)
+
+// Notifier recibe los eventos de ventas para entregarlos fuera del servicio
+// (webhooks, notificaciones). Notify no debe bloquear.
+type Notifier interface {
+	Notify(eventType string, sale *Sale)
+}
+
+// WithNotifier registers a notifier that receives every sale event.
+func WithNotifier(n Notifier) Option {
+	return func(s *Service) {
+		s.notifiers = append(s.notifiers, n)
+	}
+}
+
+func (s *Service) notify(eventType string, sale *Sale) {
+	for _, n := range s.notifiers {
+		n.Notify(eventType, sale.clone())
+	}
+}
+
+// CommentNotifier is implemented by notifiers that also deliver the comment
+// of comment events. The others receive those events with the sale only.
+type CommentNotifier interface {
+	NotifyComment(eventType string, sale *Sale, comment *Comment)
+}
+
+func (s *Service) notifyComment(eventType string, sale *Sale, comment *Comment) {
+	for _, n := range s.notifiers {
+		if cn, ok := n.(CommentNotifier); ok {
+			c := *comment
+			c.Mentions = append([]string(nil), comment.Mentions...)
+			cn.NotifyComment(eventType, sale.clone(), &c)
+			continue
+		}
+		n.Notify(eventType, sale.clone())
+	}
+}
+
+// WithEventDispatcher publishes the domain events of the Service on d, so
+// subscribers can be registered before the Service exists.
+func WithEventDispatcher(d *events.Dispatcher) Option {
+	return func(s *Service) {
+		s.events = d
+	}
+}
+
+// Events returns the dispatcher of the domain events of the Service.
+func (s *Service) Events() *events.Dispatcher {
+	return s.events
+}
+
+// subscribeStats invalida las estadísticas con cada alta o cambio de estado.
+func (s *Service) subscribeStats() {
+	invalidate := func(events.Event) { s.stats.invalidate() }
+	s.events.Subscribe(events.NameSaleCreated, invalidate)
+	s.events.Subscribe(events.NameSaleStatusChanged, invalidate)
+}
+
+func (s *Service) publishCreated(sale *Sale) {
+	s.events.Publish(events.SaleCreated{
+		SaleID:   sale.ID,
+		UserID:   sale.UserID,
+		TenantID: sale.TenantID,
+		Amount:   sale.Amount,
+		Status:   sale.Status,
+		At:       sale.CreatedAt,
+	})
+}
+
+func (s *Service) publishStatusChanged(sale *Sale, from string) {
+	s.events.Publish(events.SaleStatusChanged{
+		SaleID:   sale.ID,
+		UserID:   sale.UserID,
+		TenantID: sale.TenantID,
+		From:     from,
+		To:       sale.Status,
+		Version:  sale.Version,
+		At:       sale.UpdatedAt,
+	})
+}
+
+func (s *Service) publishRefunded(sale *Sale, adjustment *Adjustment) {
+	s.events.Publish(events.SaleRefunded{
+		SaleID:       sale.ID,
+		UserID:       sale.UserID,
+		AdjustmentID: adjustment.ID,
+		Amount:       -adjustment.Amount,
+		Reason:       adjustment.Reason,
+		RefundedBy:   adjustment.CreatedBy,
+		At:           adjustment.CreatedAt,
+	})
+}
@@ -0,0 +1,192 @@
+package sales
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Error para la carga de recibos sin proveedor de OCR
+var ErrOCRDisabled = errors.New("receipt ingestion is disabled")
+
+// Error para recibos que no son imágenes ni PDF
+var ErrUnsupportedReceipt = errors.New("receipt must be an image or a PDF")
+
+// Error para fallas del proveedor de OCR
+var ErrReceiptUnreadable = errors.New("failed to read receipt")
+
+// OCRProvider returns the text of a receipt image or PDF, e.g.
+// ocr.HTTPProvider.
+type OCRProvider interface {
+	Recognize(ctx context.Context, contentType string, data []byte) (string, error)
+}
+
+// WithOCR enables creating draft sales from receipts read by provider.
+func WithOCR(provider OCRProvider) Option {
+	return func(s *Service) {
+		s.ocr = provider
+	}
+}
+
+// OCREnabled reports whether receipts can be ingested.
+func (s *Service) OCREnabled() bool {
+	return s.ocr != nil
+}
+
+// ReceiptFields are the values read from a receipt. Amount and Date are
+// missing when they couldn't be found in Text.
+type ReceiptFields struct {
+	Amount *float64 `json:"amount,omitempty"`
+	Date   string   `json:"date,omitempty"`
+	Text   string   `json:"text"`
+}
+
+// ReceiptDraft is a draft sale pre-populated from a receipt. Attachment is
+// the receipt itself when attachments are enabled.
+type ReceiptDraft struct {
+	Sale       *Sale         `json:"sale"`
+	Extracted  ReceiptFields `json:"extracted"`
+	Attachment *Attachment   `json:"attachment,omitempty"`
+}
+
+// CreateDraftFromReceipt reads the amount and date of a receipt and creates a
+// draft sale with them for a person to confirm and submit. The date is kept
+// in the receipt_date metadata key. Nothing is guessed: a value that can't be
+// read is left empty in the draft.
+func (s *Service) CreateDraftFromReceipt(ctx context.Context, userID, filename string, data []byte, origin Origin, uploadedBy string) (*ReceiptDraft, error) {
+	if !s.OCREnabled() {
+		return nil, ErrOCRDisabled
+	}
+	contentType := http.DetectContentType(data)
+	if !strings.HasPrefix(contentType, "image/") && contentType != "application/pdf" {
+		return nil, ErrUnsupportedReceipt
+	}
+
+	text, err := s.ocr.Recognize(ctx, contentType, data)
+	if err != nil {
+		s.logger.Error("failed to read receipt", zap.Error(err))
+		return nil, fmt.Errorf("%w: %v", ErrReceiptUnreadable, err)
+	}
+	fields := extractReceipt(text)
+
+	metadata := map[string]string{"source": "receipt"}
+	if fields.Date != "" {
+		metadata["receipt_date"] = fields.Date
+	}
+	var amount float64
+	if fields.Amount != nil {
+		amount = *fields.Amount
+	}
+	sale, err := s.CreateDraftSaleWithOrigin(userID, amount, metadata, origin)
+	if err != nil {
+		return nil, err
+	}
+
+	draft := &ReceiptDraft{Sale: sale, Extracted: fields}
+	if s.AttachmentsEnabled() {
+		// El borrador ya existe; sin el adjunto igual se puede confirmar
+		if draft.Attachment, err = s.AddAttachment(ctx, sale.ID, filename, contentType, data, uploadedBy); err != nil {
+			s.logger.Warn("failed to attach receipt", zap.String("sale_id", sale.ID), zap.Error(err))
+		}
+	}
+	return draft, nil
+}
+
+var (
+	totalLinePattern = regexp.MustCompile(`(?i)\b(total|importe|monto)\b`)
+	numberPattern    = regexp.MustCompile(`\d+(?:[.,]\d+)*`)
+	isoDatePattern   = regexp.MustCompile(`\b(\d{4})-(\d{2})-(\d{2})\b`)
+	dmyDatePattern   = regexp.MustCompile(`\b(\d{1,2})[/.-](\d{1,2})[/.-](\d{4}|\d{2})\b`)
+)
+
+// extractReceipt toma el monto de la última línea de total (los subtotales e
+// impuestos suelen ir antes) y la primera fecha válida. Las fechas con barras
+// se leen día primero.
+func extractReceipt(text string) ReceiptFields {
+	fields := ReceiptFields{Text: text}
+	lines := strings.Split(text, "\n")
+
+	for i := len(lines) - 1; i >= 0 && fields.Amount == nil; i-- {
+		if !totalLinePattern.MatchString(lines[i]) {
+			continue
+		}
+		line := dmyDatePattern.ReplaceAllString(isoDatePattern.ReplaceAllString(lines[i], ""), "")
+		numbers := numberPattern.FindAllString(line, -1)
+		if len(numbers) == 0 {
+			continue
+		}
+		if amount, ok := parseAmount(numbers[len(numbers)-1]); ok && amount > 0 {
+			fields.Amount = &amount
+		}
+	}
+
+	for _, line := range lines {
+		if date, ok := findDate(line); ok {
+			fields.Date = date.Format(time.DateOnly)
+			break
+		}
+	}
+	return fields
+}
+
+// parseAmount acepta 1.234,56, 1,234.56 y 12.990: un único separador seguido
+// de uno o dos dígitos es decimal, si no es de miles.
+func parseAmount(raw string) (float64, bool) {
+	dot, comma := strings.LastIndex(raw, "."), strings.LastIndex(raw, ",")
+	decimal := -1
+	switch {
+	case dot >= 0 && comma >= 0:
+		decimal = max(dot, comma)
+	case dot >= 0 || comma >= 0:
+		sep := max(dot, comma)
+		if strings.Count(raw, raw[sep:sep+1]) == 1 && len(raw)-sep-1 <= 2 {
+			decimal = sep
+		}
+	}
+
+	var b strings.Builder
+	for i, r := range raw {
+		switch {
+		case i == decimal:
+			b.WriteByte('.')
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		}
+	}
+	amount, err := strconv.ParseFloat(b.String(), 64)
+	return amount, err == nil
+}
+
+func findDate(line string) (time.Time, bool) {
+	for _, m := range isoDatePattern.FindAllStringSubmatch(line, -1) {
+		if t, ok := validDate(m[1], m[2], m[3]); ok {
+			return t, true
+		}
+	}
+	for _, m := range dmyDatePattern.FindAllStringSubmatch(line, -1) {
+		year := m[3]
+		if len(year) == 2 {
+			year = "20" + year
+		}
+		if t, ok := validDate(year, m[2], m[1]); ok {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// validDate descarta fechas inexistentes como el 31/02.
+func validDate(year, month, day string) (time.Time, bool) {
+	y, _ := strconv.Atoi(year)
+	m, _ := strconv.Atoi(month)
+	d, _ := strconv.Atoi(day)
+	t := time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+	return t, t.Year() == y && int(t.Month()) == m && t.Day() == d
+}
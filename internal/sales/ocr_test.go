@@ -0,0 +1,65 @@
+package sales
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+type staticOCR string
+
+func (o staticOCR) Recognize(ctx context.Context, contentType string, data []byte) (string, error) {
+	return string(o), nil
+}
+
+// pngHeader es la firma con la que se detecta una imagen PNG.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n0000")
+
+// TestExtractReceipt_AmountFormatsAndDates verifica los formatos de monto y
+// fecha más comunes en recibos.
+func TestExtractReceipt_AmountFormatsAndDates(t *testing.T) {
+	cases := []struct {
+		text   string
+		amount float64
+		date   string
+	}{
+		{"Fecha: 05/03/2024\nSubtotal 10.916\nIVA 2.074\nTOTAL $ 12.990", 12990, "2024-03-05"},
+		{"2024-11-30 14:02\nItems 3\nTotal: 1,234.50", 1234.50, "2024-11-30"},
+		{"Importe total 1.234,56 EUR\n31/02/2024 01/04/24", 1234.56, "2024-04-01"},
+		{"thanks for your purchase", 0, ""},
+	}
+	for _, c := range cases {
+		fields := extractReceipt(c.text)
+		if c.amount == 0 && fields.Amount != nil {
+			t.Errorf("%q: expected no amount, got %v", c.text, *fields.Amount)
+		}
+		if c.amount != 0 && (fields.Amount == nil || *fields.Amount != c.amount) {
+			t.Errorf("%q: expected amount %v, got %v", c.text, c.amount, fields.Amount)
+		}
+		if fields.Date != c.date {
+			t.Errorf("%q: expected date %q, got %q", c.text, c.date, fields.Date)
+		}
+	}
+}
+
+// TestCreateDraftFromReceipt_PrepopulatesDraft verifica que el borrador quede
+// con el monto y la fecha leídos y que se rechacen archivos que no son recibos.
+func TestCreateDraftFromReceipt_PrepopulatesDraft(t *testing.T) {
+	svc := NewService(NewLocalStorage(), zaptest.NewLogger(t), "http://unused", WithOCR(staticOCR("05/03/2024\nTOTAL 12.990")))
+
+	if _, err := svc.CreateDraftFromReceipt(context.Background(), "u1", "notes.txt", []byte("plain text"), Origin{}, "ana"); err != ErrUnsupportedReceipt {
+		t.Errorf("expected ErrUnsupportedReceipt, got %v", err)
+	}
+
+	draft, err := svc.CreateDraftFromReceipt(context.Background(), "u1", "receipt.png", pngHeader, Origin{}, "ana")
+	if err != nil {
+		t.Fatalf("CreateDraftFromReceipt returned error: %v", err)
+	}
+	if draft.Sale.Status != StatusDraft || draft.Sale.Amount != 12990 || draft.Sale.Metadata["receipt_date"] != "2024-03-05" {
+		t.Errorf("expected a pre-populated draft, got %+v", draft.Sale)
+	}
+	if draft.Attachment != nil {
+		t.Errorf("expected no attachment with attachments disabled")
+	}
+}
@@ -0,0 +1,96 @@
+package sales
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Límites de página para los listados paginados
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// Error para cursores que no emitió el servicio
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Page is one page of sales, newest first. NextCursor is empty on the last page.
+type Page struct {
+	Results    []*Sale `json:"results"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+// ListUserSales returns the sales of userID, newest first, limit at a time.
+// cursor is the NextCursor of the previous page, or empty for the first one.
+// Unlike SearchSale it trusts userID, e.g. taken from a verified token, and
+// does not call the user service.
+func (s *Service) ListUserSales(userID string, limit int, cursor string) (Page, error) {
+	if limit <= 0 {
+		limit = DefaultPageLimit
+	}
+	limit = min(limit, MaxPageLimit)
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return Page{}, err
+	}
+
+	owned, err := salesOfUser(s.storage, userID)
+	if err != nil {
+		return Page{}, fmt.Errorf("failed to retrieve sales: %w", err)
+	}
+	// Orden total por (created_at, id) descendente para que el cursor sea estable
+	sort.Slice(owned, func(i, j int) bool {
+		return saleBefore(owned[j], owned[i])
+	})
+
+	page := Page{Results: make([]*Sale, 0, limit)}
+	for _, sale := range owned {
+		if after != nil && !saleBefore(sale, after) {
+			continue
+		}
+		if len(page.Results) == limit {
+			last := page.Results[limit-1]
+			page.NextCursor = encodeCursor(last)
+			break
+		}
+		page.Results = append(page.Results, sale)
+	}
+	return page, nil
+}
+
+func saleBefore(a, b *Sale) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+// encodeCursor codifica la posición de la última venta de la página.
+func encodeCursor(sale *Sale) string {
+	raw := strconv.FormatInt(sale.CreatedAt.UnixNano(), 10) + "|" + sale.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (*Sale, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	nanos, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, ErrInvalidCursor
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &Sale{ID: id, CreatedAt: time.Unix(0, n)}, nil
+}
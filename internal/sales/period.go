@@ -0,0 +1,144 @@
+package sales
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Error para modificaciones de ventas dentro de un periodo cerrado
+var ErrPeriodClosed = errors.New("accounting period is closed")
+
+// Error para periodos que no tienen el formato YYYY-MM
+var ErrInvalidPeriod = errors.New("invalid accounting period: expected YYYY-MM")
+
+// periodLayout is the format of accounting period identifiers, e.g. "2024-05".
+const periodLayout = "2006-01"
+
+// AccountingPeriod is a closed calendar month. Sales created within it can no
+// longer be modified.
+type AccountingPeriod struct {
+	Period   string    `json:"period"`
+	ClosedBy string    `json:"closed_by,omitempty"`
+	ClosedAt time.Time `json:"closed_at"`
+}
+
+// PeriodStorage persists closed accounting periods.
+type PeriodStorage interface {
+	Close(period *AccountingPeriod) error
+	Read(period string) (*AccountingPeriod, bool, error)
+	GetAll() ([]*AccountingPeriod, error)
+}
+
+type LocalPeriodStorage struct {
+	mu sync.RWMutex
+	m  map[string]*AccountingPeriod
+}
+
+func NewLocalPeriodStorage() *LocalPeriodStorage {
+	return &LocalPeriodStorage{
+		m: make(map[string]*AccountingPeriod),
+	}
+}
+
+func (l *LocalPeriodStorage) Close(period *AccountingPeriod) error {
+	if period.Period == "" {
+		return ErrEmptyID
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.m[period.Period] = period
+	return nil
+}
+
+func (l *LocalPeriodStorage) Read(period string) (*AccountingPeriod, bool, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	p, ok := l.m[period]
+	return p, ok, nil
+}
+
+// GetAll retorna los periodos cerrados ordenados cronológicamente.
+func (l *LocalPeriodStorage) GetAll() ([]*AccountingPeriod, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	result := make([]*AccountingPeriod, 0, len(l.m))
+	for _, p := range l.m {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Period < result[j].Period })
+	return result, nil
+}
+
+// WithPeriodStorage sets where closed accounting periods are kept. Defaults to
+// an in-memory LocalPeriodStorage.
+func WithPeriodStorage(periods PeriodStorage) Option {
+	return func(s *Service) {
+		s.periods = periods
+	}
+}
+
+// PeriodOf returns the accounting period (UTC month) that contains t.
+func PeriodOf(t time.Time) string {
+	return t.UTC().Format(periodLayout)
+}
+
+// ClosePeriod closes an accounting period and stores its snapshot. Closing an
+// already closed period returns the existing record.
+func (s *Service) ClosePeriod(period, closedBy string) (*AccountingPeriod, error) {
+	if _, err := time.Parse(periodLayout, period); err != nil {
+		return nil, ErrInvalidPeriod
+	}
+
+	existing, ok, err := s.periods.Read(period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read accounting period: %w", err)
+	}
+	if ok {
+		// Reintenta el snapshot si faltó al cerrar
+		s.ensureSnapshot(period)
+		return existing, nil
+	}
+
+	closed := &AccountingPeriod{Period: period, ClosedBy: closedBy, ClosedAt: utcNow()}
+	if err := s.periods.Close(closed); err != nil {
+		s.logger.Error("failed to close accounting period", zap.String("period", period), zap.Error(err))
+		return nil, fmt.Errorf("failed to close accounting period: %w", err)
+	}
+
+	s.logger.Info("accounting period closed", zap.String("period", period), zap.String("closed_by", closedBy))
+	s.ensureSnapshot(period)
+	return closed, nil
+}
+
+// ensureSnapshot guarda el snapshot del periodo cerrado. Un fallo no reabre el
+// periodo: se registra y se reintenta al volver a cerrarlo.
+func (s *Service) ensureSnapshot(period string) {
+	if _, err := s.snapshotPeriod(context.Background(), period); err != nil {
+		s.logger.Error("failed to store period snapshot", zap.String("period", period), zap.Error(err))
+	}
+}
+
+// ListClosedPeriods returns every closed accounting period.
+func (s *Service) ListClosedPeriods() ([]*AccountingPeriod, error) {
+	return s.periods.GetAll()
+}
+
+// checkPeriodOpen rechaza cambios sobre ventas creadas en un periodo cerrado.
+func (s *Service) checkPeriodOpen(sale *Sale) error {
+	_, closed, err := s.periods.Read(PeriodOf(sale.CreatedAt))
+	if err != nil {
+		return fmt.Errorf("failed to read accounting period: %w", err)
+	}
+	if closed {
+		return ErrPeriodClosed
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package sales
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// Estados de la publicación de una venta aprobada en el ERP
+const (
+	ERPPostingPending = "pending"
+	ERPPostingPosted  = "posted"
+	ERPPostingFailed  = "failed"
+)
+
+// erpActor figura en la auditoría de los cambios de publicación en el ERP.
+var erpActor = Actor{ID: "system:erp"}
+
+// SetERPPostingStatus records the outcome of posting a sale to the ERP. It is a
+// bookkeeping field, so it bypasses the edit rules and the period lock.
+func (s *Service) SetERPPostingStatus(saleID, status string) error {
+	switch status {
+	case ERPPostingPending, ERPPostingPosted, ERPPostingFailed:
+	default:
+		return fmt.Errorf("invalid erp posting status %q", status)
+	}
+
+	sale, err := s.storage.Read(saleID)
+	if err != nil {
+		return ErrNotFound
+	}
+	if sale.ERPPosting == status {
+		return nil
+	}
+
+	updated := sale.clone()
+	updated.ERPPosting = status
+	updated.UpdatedAt = utcNow()
+	updated.Version++
+
+	if err := s.saveAudited(AuditActionERPPosting, sale, updated, erpActor); err != nil {
+		s.logger.Error("failed to update erp posting status", zap.String("sale_id", saleID), zap.Error(err))
+		return err
+	}
+
+	s.notify(EventSalePostingChanged, updated)
+	return nil
+}
@@ -0,0 +1,48 @@
+package sales
+
+import (
+	"math/rand/v2"
+	"sync"
+)
+
+// Random is the source of every random decision of the Service, e.g. the
+// status assigned to new sales.
+type Random interface {
+	IntN(n int) int
+}
+
+// globalRandom usa el generador global de math/rand/v2, seguro para
+// goroutines concurrentes.
+type globalRandom struct{}
+
+func (globalRandom) IntN(n int) int { return rand.IntN(n) }
+
+// seededRandom serializa el acceso a un generador con semilla fija.
+type seededRandom struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewSeededRandom returns a Random that yields the same sequence for the same
+// seed, for reproducible tests.
+func NewSeededRandom(seed uint64) Random {
+	return &seededRandom{rnd: rand.New(rand.NewPCG(seed, seed))}
+}
+
+func (r *seededRandom) IntN(n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rnd.IntN(n)
+}
+
+// WithRandom replaces the random source of the Service.
+func WithRandom(r Random) Option {
+	return func(s *Service) {
+		s.random = r
+	}
+}
+
+func (s *Service) randomStatus() string {
+	statuses := []string{StatusPending, StatusApproved, StatusRejected}
+	return statuses[s.random.IntN(len(statuses))]
+}
@@ -0,0 +1,424 @@
+package sales
+
+import (
+	"api_sales/internal/lock"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Error para intervalos no soportados
+var ErrInvalidInterval = errors.New("invalid recurring interval")
+
+// Error para operaciones no permitidas en el estado actual de la definición
+var ErrInvalidRecurringState = errors.New("invalid recurring sale state")
+
+const (
+	IntervalDaily   = "daily"
+	IntervalWeekly  = "weekly"
+	IntervalMonthly = "monthly"
+
+	RecurringActive    = "active"
+	RecurringPaused    = "paused"
+	RecurringCancelled = "cancelled"
+	RecurringFinished  = "finished"
+)
+
+// MaxRecurringFailures is the number of consecutive failed payments after
+// which a recurring sale is paused automatically.
+const MaxRecurringFailures = 3
+
+// maxCatchUpRuns limits how many overdue periods are materialized for a single
+// definition in one scheduler pass.
+const maxCatchUpRuns = 31
+
+// DefaultSchedulerInterval is how often the scheduler looks for due definitions.
+const DefaultSchedulerInterval = time.Minute
+
+type RecurringService struct {
+	storage RecurringStorage
+	sales   *Service
+	logger  *zap.Logger
+	// mu serializa las lecturas y escrituras de una definición entre los
+	// handlers y el scheduler
+	mu sync.Mutex
+}
+
+// NewRecurringService creates a service that manages recurring sale
+// definitions and materializes them through the given sales Service.
+func NewRecurringService(storage RecurringStorage, sales *Service, logger *zap.Logger) *RecurringService {
+	if logger == nil {
+		logger, _ = zap.NewProduction()
+	}
+
+	return &RecurringService{
+		storage: storage,
+		sales:   sales,
+		logger:  logger,
+	}
+}
+
+// CreateRecurringSale validates and stores a new definition. The first sale is
+// materialized at startAt (or immediately when startAt is zero).
+func (r *RecurringService) CreateRecurringSale(userID string, amount float64, interval string, startAt time.Time, endDate *time.Time) (*RecurringSale, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("amount must be greater than zero")
+	}
+	if !validInterval(interval) {
+		return nil, ErrInvalidInterval
+	}
+
+	now := utcNow()
+	if startAt.IsZero() {
+		startAt = now
+	}
+	if endDate != nil && endDate.Before(startAt) {
+		return nil, fmt.Errorf("end date must be after start date")
+	}
+
+	if err := r.sales.verifyUser(userID); err != nil {
+		return nil, err
+	}
+
+	rs := &RecurringSale{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		Amount:    amount,
+		Interval:  interval,
+		Status:    RecurringActive,
+		NextRunAt: startAt,
+		EndDate:   endDate,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
+	}
+
+	if err := r.storage.Set(rs); err != nil {
+		r.logger.Error("failed to save recurring sale", zap.String("recurring_sale_id", rs.ID), zap.Error(err))
+		return nil, fmt.Errorf("failed to save recurring sale: %w", err)
+	}
+
+	r.logger.Info("recurring sale created", zap.String("recurring_sale_id", rs.ID), zap.Any("recurring_sale", rs))
+	return rs, nil
+}
+
+// GetRecurringSale returns a single definition.
+func (r *RecurringService) GetRecurringSale(id string) (*RecurringSale, error) {
+	return r.storage.Read(id)
+}
+
+// ListRecurringSales returns all definitions, optionally filtered by user and status.
+func (r *RecurringService) ListRecurringSales(userID, status string) ([]*RecurringSale, error) {
+	all, err := r.storage.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve recurring sales: %w", err)
+	}
+
+	result := make([]*RecurringSale, 0)
+	for _, rs := range all {
+		if userID != "" && rs.UserID != userID {
+			continue
+		}
+		if status != "" && rs.Status != status {
+			continue
+		}
+		result = append(result, rs)
+	}
+	return result, nil
+}
+
+// ListMaterializedSales returns the sales generated from a definition that
+// caller may see. A definition of a user the caller doesn't see is reported
+// as not found.
+func (r *RecurringService) ListMaterializedSales(id string, caller *Caller) ([]*Sale, error) {
+	rs, err := r.storage.Read(id)
+	if err != nil {
+		return nil, err
+	}
+	if !r.sales.canSee(caller, &Sale{UserID: rs.UserID}) {
+		return nil, ErrRecurringNotFound
+	}
+
+	// Las ventas se materializan a nombre del usuario de la definición
+	owned, err := salesOfUser(r.sales.storage, rs.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve sales: %w", err)
+	}
+
+	result := make([]*Sale, 0)
+	for _, sale := range owned {
+		if sale.RecurringSaleID == id && r.sales.canSee(caller, sale) {
+			result = append(result, sale)
+		}
+	}
+	return result, nil
+}
+
+// PauseRecurringSale stops materialization of an active definition.
+func (r *RecurringService) PauseRecurringSale(id string) (*RecurringSale, error) {
+	return r.transition(id, RecurringActive, RecurringPaused)
+}
+
+// ResumeRecurringSale re-activates a paused definition and resets its failure
+// counter. Periods missed while paused are skipped.
+func (r *RecurringService) ResumeRecurringSale(id string) (*RecurringSale, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rs, err := r.storage.Read(id)
+	if err != nil {
+		return nil, err
+	}
+	if rs.Status != RecurringPaused {
+		return nil, ErrInvalidRecurringState
+	}
+
+	now := utcNow()
+	for rs.NextRunAt.Before(now) {
+		rs.NextRunAt = nextRun(rs.NextRunAt, rs.Interval)
+	}
+	rs.ConsecutiveFailures = 0
+	rs.Status = RecurringActive
+	rs.UpdatedAt = now
+	rs.Version++
+
+	if err := r.storage.Set(rs); err != nil {
+		r.logger.Error("failed to update recurring sale", zap.String("recurring_sale_id", rs.ID), zap.Error(err))
+		return nil, err
+	}
+	return rs, nil
+}
+
+// CancelRecurringSale permanently stops a definition.
+func (r *RecurringService) CancelRecurringSale(id string) (*RecurringSale, error) {
+	rs, err := r.storage.Read(id)
+	if err != nil {
+		return nil, err
+	}
+	if rs.Status == RecurringCancelled || rs.Status == RecurringFinished {
+		return nil, ErrInvalidRecurringState
+	}
+	return r.transition(id, rs.Status, RecurringCancelled)
+}
+
+func (r *RecurringService) transition(id, from, to string) (*RecurringSale, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rs, err := r.storage.Read(id)
+	if err != nil {
+		return nil, err
+	}
+	if rs.Status != from {
+		return nil, ErrInvalidRecurringState
+	}
+
+	rs.Status = to
+	rs.UpdatedAt = utcNow()
+	rs.Version++
+
+	if err := r.storage.Set(rs); err != nil {
+		r.logger.Error("failed to update recurring sale", zap.String("recurring_sale_id", rs.ID), zap.Error(err))
+		return nil, err
+	}
+	return rs, nil
+}
+
+// ProcessDue materializes a sale for every active definition whose next run is
+// at or before now. It returns the number of sales created.
+func (r *RecurringService) ProcessDue(now time.Time) int {
+	all, err := r.storage.GetAll()
+	if err != nil {
+		r.logger.Error("failed to retrieve recurring sales", zap.Error(err))
+		return 0
+	}
+
+	created := 0
+	for _, rs := range all {
+		if rs.Status != RecurringActive || rs.NextRunAt.After(now) {
+			continue
+		}
+
+		read := rs.clone()
+		for runs := 0; runs < maxCatchUpRuns && rs.Status == RecurringActive && !rs.NextRunAt.After(now); runs++ {
+			if rs.EndDate != nil && rs.NextRunAt.After(*rs.EndDate) {
+				break
+			}
+			if r.materialize(rs) {
+				created++
+			}
+			rs.NextRunAt = nextRun(rs.NextRunAt, rs.Interval)
+		}
+
+		if rs.Status == RecurringActive && rs.EndDate != nil && rs.NextRunAt.After(*rs.EndDate) {
+			rs.Status = RecurringFinished
+		}
+		r.saveProgress(read, rs)
+	}
+	return created
+}
+
+// saveProgress guarda el avance de una pasada del scheduler sobre la
+// definición leída en read. Si mientras se materializaba la definición cambió,
+// por ejemplo una pausa o cancelación, se conserva ese estado y solo se agrega
+// el avance, para no volver a materializar los mismos periodos.
+func (r *RecurringService) saveProgress(read, progressed *RecurringSale) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, err := r.storage.Read(read.ID)
+	if err != nil {
+		r.logger.Error("failed to re-read recurring sale", zap.String("recurring_sale_id", read.ID), zap.Error(err))
+		return
+	}
+	if current.Version != read.Version || current.Status != read.Status {
+		r.logger.Info("recurring sale changed during a scheduler pass",
+			zap.String("recurring_sale_id", current.ID),
+			zap.String("status", current.Status),
+		)
+		if current.Status == RecurringActive {
+			current.Status = progressed.Status
+		}
+		if progressed.NextRunAt.After(current.NextRunAt) {
+			current.NextRunAt = progressed.NextRunAt
+		}
+		current.LastSaleID = progressed.LastSaleID
+		current.ConsecutiveFailures = progressed.ConsecutiveFailures
+		progressed = current
+	}
+
+	progressed.UpdatedAt = utcNow()
+	progressed.Version = current.Version + 1
+	if err := r.storage.Set(progressed); err != nil {
+		r.logger.Error("failed to update recurring sale", zap.String("recurring_sale_id", progressed.ID), zap.Error(err))
+	}
+}
+
+// materialize creates the sale for the current period. A rejected sale or a
+// creation error counts as a failed payment; after MaxRecurringFailures in a
+// row the definition is paused.
+func (r *RecurringService) materialize(rs *RecurringSale) bool {
+	sale, err := r.sales.createSale(rs.UserID, rs.Amount, rs.ID, nil, Origin{})
+	if err != nil || sale.Status == StatusRejected {
+		rs.ConsecutiveFailures++
+		r.logger.Warn("recurring sale payment failed",
+			zap.String("recurring_sale_id", rs.ID),
+			zap.Int("consecutive_failures", rs.ConsecutiveFailures),
+			zap.Error(err),
+		)
+		if rs.ConsecutiveFailures >= MaxRecurringFailures {
+			rs.Status = RecurringPaused
+			r.logger.Warn("recurring sale paused after repeated payment failures", zap.String("recurring_sale_id", rs.ID))
+		}
+	} else {
+		rs.ConsecutiveFailures = 0
+	}
+
+	if sale != nil {
+		rs.LastSaleID = sale.ID
+		return true
+	}
+	return false
+}
+
+func (rs *RecurringSale) clone() *RecurringSale {
+	c := *rs
+	if rs.EndDate != nil {
+		end := *rs.EndDate
+		c.EndDate = &end
+	}
+	return &c
+}
+
+func validInterval(interval string) bool {
+	switch interval {
+	case IntervalDaily, IntervalWeekly, IntervalMonthly:
+		return true
+	}
+	return false
+}
+
+func nextRun(from time.Time, interval string) time.Time {
+	switch interval {
+	case IntervalDaily:
+		return from.AddDate(0, 0, 1)
+	case IntervalWeekly:
+		return from.AddDate(0, 0, 7)
+	default:
+		return from.AddDate(0, 1, 0)
+	}
+}
+
+// schedulerLockKey is the distributed lock held during a scheduler pass.
+const schedulerLockKey = "scheduler:recurring-sales"
+
+// Scheduler periodically materializes due recurring sales and releases the
+// expired claims of reviewers.
+type Scheduler struct {
+	recurring *RecurringService
+	interval  time.Duration
+	locker    lock.Locker
+	logger    *zap.Logger
+}
+
+// NewScheduler creates a scheduler that ticks every interval. When several
+// replicas share storage, locker ensures only one of them runs each pass; a nil
+// locker runs every pass locally.
+func NewScheduler(recurring *RecurringService, interval time.Duration, locker lock.Locker, logger *zap.Logger) *Scheduler {
+	if logger == nil {
+		logger, _ = zap.NewProduction()
+	}
+
+	return &Scheduler{
+		recurring: recurring,
+		interval:  interval,
+		locker:    locker,
+		logger:    logger,
+	}
+}
+
+// Start blocks running the scheduler loop until ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.runPass(ctx, now)
+		}
+	}
+}
+
+func (s *Scheduler) runPass(ctx context.Context, now time.Time) {
+	process := func(context.Context) error {
+		if n := s.recurring.ProcessDue(now); n > 0 {
+			s.logger.Info("recurring sales materialized", zap.Int("count", n))
+		}
+		// Los reclamos de revisores vencidos vuelven a la cola
+		if n, err := s.recurring.sales.ReleaseExpiredClaims(now); err != nil {
+			s.logger.Error("failed to release expired claims", zap.Error(err))
+		} else if n > 0 {
+			s.logger.Info("expired claims released", zap.Int("count", n))
+		}
+		return nil
+	}
+
+	if s.locker == nil {
+		process(ctx)
+		return
+	}
+
+	err := lock.Run(ctx, s.locker, schedulerLockKey, s.interval, process)
+	switch {
+	case errors.Is(err, lock.ErrNotAcquired):
+		s.logger.Debug("scheduler pass skipped, lock held by another instance")
+	case err != nil:
+		s.logger.Error("scheduler pass failed", zap.Error(err))
+	}
+}
@@ -0,0 +1,173 @@
+package sales
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// newRecurringTestService levanta un mock del servicio de usuarios cuyo
+// resultado se puede alternar con userExists.
+func newRecurringTestService(t *testing.T, userExists *atomic.Bool) (*RecurringService, *Service) {
+	t.Helper()
+
+	mockUserServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !userExists.Load() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "user123", "name": "Test User 123"}`))
+	}))
+	t.Cleanup(mockUserServer.Close)
+
+	logger := zaptest.NewLogger(t)
+	svc := NewService(NewLocalStorage(), logger, mockUserServer.URL)
+	return NewRecurringService(NewLocalRecurringStorage(), svc, logger), svc
+}
+
+// TestProcessDue_MaterializesLinkedSales verifica que se genera una venta por periodo vencido.
+func TestProcessDue_MaterializesLinkedSales(t *testing.T) {
+	var userExists atomic.Bool
+	userExists.Store(true)
+	rsvc, _ := newRecurringTestService(t, &userExists)
+
+	start := time.Now().Add(-48 * time.Hour)
+	rs, err := rsvc.CreateRecurringSale("user123", 10, IntervalDaily, start, nil)
+	if err != nil {
+		t.Fatalf("CreateRecurringSale returned error: %v", err)
+	}
+
+	created := rsvc.ProcessDue(time.Now())
+	if created != 3 {
+		t.Fatalf("expected 3 materialized sales, got %d", created)
+	}
+
+	linked, err := rsvc.ListMaterializedSales(rs.ID, nil)
+	if err != nil {
+		t.Fatalf("ListMaterializedSales returned error: %v", err)
+	}
+	if len(linked) != 3 {
+		t.Errorf("expected 3 linked sales, got %d", len(linked))
+	}
+	for _, sale := range linked {
+		if sale.RecurringSaleID != rs.ID {
+			t.Errorf("sale %s not linked to definition %s", sale.ID, rs.ID)
+		}
+	}
+	// Control de acceso por fila, como en GetSale
+	if owned, err := rsvc.ListMaterializedSales(rs.ID, &Caller{UserID: "user123", Role: "seller"}); err != nil || len(owned) != 3 {
+		t.Errorf("expected the owner to see 3 sales, got %d (err=%v)", len(owned), err)
+	}
+	if _, err := rsvc.ListMaterializedSales(rs.ID, &Caller{UserID: "other", Role: "seller"}); err != ErrRecurringNotFound {
+		t.Errorf("expected ErrRecurringNotFound for another user, got %v", err)
+	}
+	// El storage guarda copias: se relee la definición
+	rs, _ = rsvc.GetRecurringSale(rs.ID)
+	if !rs.NextRunAt.After(time.Now()) {
+		t.Errorf("expected next run in the future, got %v", rs.NextRunAt)
+	}
+}
+
+// TestProcessDue_PausesAfterRepeatedFailures verifica la pausa automática.
+func TestProcessDue_PausesAfterRepeatedFailures(t *testing.T) {
+	var userExists atomic.Bool
+	userExists.Store(true)
+	rsvc, _ := newRecurringTestService(t, &userExists)
+
+	start := time.Now().Add(-10 * 24 * time.Hour)
+	rs, err := rsvc.CreateRecurringSale("user123", 10, IntervalDaily, start, nil)
+	if err != nil {
+		t.Fatalf("CreateRecurringSale returned error: %v", err)
+	}
+
+	userExists.Store(false)
+	rsvc.ProcessDue(time.Now())
+	rs, _ = rsvc.GetRecurringSale(rs.ID)
+
+	if rs.Status != RecurringPaused {
+		t.Fatalf("expected status %q, got %q", RecurringPaused, rs.Status)
+	}
+	if rs.ConsecutiveFailures != MaxRecurringFailures {
+		t.Errorf("expected %d consecutive failures, got %d", MaxRecurringFailures, rs.ConsecutiveFailures)
+	}
+
+	userExists.Store(true)
+	resumed, err := rsvc.ResumeRecurringSale(rs.ID)
+	if err != nil {
+		t.Fatalf("ResumeRecurringSale returned error: %v", err)
+	}
+	if resumed.Status != RecurringActive || resumed.ConsecutiveFailures != 0 {
+		t.Errorf("expected active definition with no failures, got %q/%d", resumed.Status, resumed.ConsecutiveFailures)
+	}
+}
+
+// TestProcessDue_FinishesAtEndDate verifica que la definición termina en su fecha final.
+func TestProcessDue_FinishesAtEndDate(t *testing.T) {
+	var userExists atomic.Bool
+	userExists.Store(true)
+	rsvc, _ := newRecurringTestService(t, &userExists)
+
+	start := time.Now().Add(-21 * 24 * time.Hour)
+	end := start.AddDate(0, 0, 7)
+	rs, err := rsvc.CreateRecurringSale("user123", 10, IntervalWeekly, start, &end)
+	if err != nil {
+		t.Fatalf("CreateRecurringSale returned error: %v", err)
+	}
+
+	if created := rsvc.ProcessDue(time.Now()); created != 2 {
+		t.Errorf("expected 2 materialized sales, got %d", created)
+	}
+	rs, _ = rsvc.GetRecurringSale(rs.ID)
+	if rs.Status != RecurringFinished {
+		t.Errorf("expected status %q, got %q", RecurringFinished, rs.Status)
+	}
+}
+
+// TestProcessDue_KeepsConcurrentCancellation verifica que una cancelación
+// durante la pasada del scheduler no se pisa, bajo -race.
+func TestProcessDue_KeepsConcurrentCancellation(t *testing.T) {
+	var rsvc *RecurringService
+	var id atomic.Value
+	var cancelled atomic.Bool
+	mockUserServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// La primera materialización cancela la definición desde otro goroutine
+		if rs, ok := id.Load().(string); ok && cancelled.CompareAndSwap(false, true) {
+			if _, err := rsvc.CancelRecurringSale(rs); err != nil {
+				t.Errorf("CancelRecurringSale returned error: %v", err)
+			}
+		}
+		w.Write([]byte(`{"id": "user123", "name": "Test User 123"}`))
+	}))
+	t.Cleanup(mockUserServer.Close)
+	logger := zaptest.NewLogger(t)
+	rsvc = NewRecurringService(NewLocalRecurringStorage(), NewService(NewLocalStorage(), logger, mockUserServer.URL), logger)
+
+	rs, err := rsvc.CreateRecurringSale("user123", 10, IntervalDaily, time.Now().Add(-48*time.Hour), nil)
+	if err != nil {
+		t.Fatalf("CreateRecurringSale returned error: %v", err)
+	}
+	id.Store(rs.ID)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			rsvc.ListRecurringSales("", "")
+		}
+	}()
+	rsvc.ProcessDue(time.Now())
+	<-done
+
+	got, _ := rsvc.GetRecurringSale(rs.ID)
+	if got.Status != RecurringCancelled {
+		t.Errorf("expected the cancellation kept, got status %q", got.Status)
+	}
+	if got.LastSaleID == "" || !got.NextRunAt.After(rs.NextRunAt) {
+		t.Errorf("expected the scheduler progress recorded, got %+v", got)
+	}
+}
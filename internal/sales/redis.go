@@ -0,0 +1,288 @@
+package sales
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisBatch limita los IDs de cada MGET de GetAll.
+const redisBatch = 500
+
+// RedisStorage keeps each sale as a JSON string under prefix+"sale:"+id and
+// the IDs in the sorted set prefix+"sales", so GetAll lists them in ID order.
+// With a ttl each write keeps the sale for ttl more; zero keeps sales until
+// deleted. Durability is Redis's own: configure RDB or AOF persistence on the
+// server to keep sales across restarts.
+type RedisStorage struct {
+	client  redis.UniversalClient
+	prefix  string
+	ttl     time.Duration
+	timeout time.Duration
+}
+
+func NewRedisStorage(client redis.UniversalClient, prefix string, ttl, timeout time.Duration) *RedisStorage {
+	return &RedisStorage{client: client, prefix: prefix, ttl: ttl, timeout: timeout}
+}
+
+func (r *RedisStorage) saleKey(id string) string {
+	return r.prefix + "sale:" + id
+}
+
+func (r *RedisStorage) indexKey() string {
+	return r.prefix + "sales"
+}
+
+func (r *RedisStorage) Set(sale *Sale) error {
+	return r.write([]*Sale{sale})
+}
+
+// write guarda las ventas en un MULTI/EXEC: se aplican todas o ninguna.
+func (r *RedisStorage) write(list []*Sale) error {
+	values := make([][]byte, len(list))
+	for i, sale := range list {
+		if sale.ID == "" {
+			return ErrEmptyID
+		}
+		data, err := json.Marshal(sale)
+		if err != nil {
+			return err
+		}
+		values[i] = data
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, sale := range list {
+			pipe.Set(ctx, r.saleKey(sale.ID), values[i], r.ttl)
+			pipe.ZAdd(ctx, r.indexKey(), redis.Z{Member: sale.ID})
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("redis put sale: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisStorage) Read(id string) (*Sale, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	data, err := r.client.Get(ctx, r.saleKey(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis get sale: %w", err)
+	}
+	return decodeRedisSale(data)
+}
+
+// GetAll lee el índice en orden de ID y las ventas en lotes. Los IDs de
+// ventas vencidas se quitan del índice al encontrarlos.
+func (r *RedisStorage) GetAll() ([]*Sale, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	ids, err := r.client.ZRange(ctx, r.indexKey(), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis list sales: %w", err)
+	}
+
+	sales := make([]*Sale, 0, len(ids))
+	var expired []any
+	for start := 0; start < len(ids); start += redisBatch {
+		batch := ids[start:min(start+redisBatch, len(ids))]
+		keys := make([]string, len(batch))
+		for i, id := range batch {
+			keys[i] = r.saleKey(id)
+		}
+		values, err := r.client.MGet(ctx, keys...).Result()
+		if err != nil {
+			return nil, fmt.Errorf("redis list sales: %w", err)
+		}
+		for i, value := range values {
+			data, ok := value.(string)
+			if !ok {
+				expired = append(expired, batch[i])
+				continue
+			}
+			sale, err := decodeRedisSale([]byte(data))
+			if err != nil {
+				return nil, err
+			}
+			sales = append(sales, sale)
+		}
+	}
+	if len(expired) > 0 {
+		// Si falla, la próxima lectura lo vuelve a intentar
+		r.client.ZRem(ctx, r.indexKey(), expired...)
+	}
+	return sales, nil
+}
+
+// WithTx buffers the writes of fn and applies them in a single MULTI/EXEC
+// when fn returns nil, so they are all stored or none is. Reads inside fn see
+// its own writes but are not isolated from other instances. Nested calls
+// join the outer transaction.
+func (r *RedisStorage) WithTx(ctx context.Context, fn func(tx Storage) error) error {
+	tx := &redisTx{storage: r, pending: map[string]*Sale{}}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if len(tx.pending) == 0 {
+		return nil
+	}
+	list := make([]*Sale, 0, len(tx.pending))
+	for _, sale := range tx.pending {
+		list = append(list, sale)
+	}
+	return r.write(list)
+}
+
+// redisTx acumula las escrituras de una transacción de RedisStorage.
+type redisTx struct {
+	storage *RedisStorage
+	pending map[string]*Sale
+}
+
+func (t *redisTx) Set(sale *Sale) error {
+	if sale.ID == "" {
+		return ErrEmptyID
+	}
+	copied := *sale
+	t.pending[sale.ID] = &copied
+	return nil
+}
+
+func (t *redisTx) Read(id string) (*Sale, error) {
+	if sale, ok := t.pending[id]; ok {
+		copied := *sale
+		return &copied, nil
+	}
+	return t.storage.Read(id)
+}
+
+func (t *redisTx) GetAll() ([]*Sale, error) {
+	stored, err := t.storage.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	merged := make([]*Sale, 0, len(stored)+len(t.pending))
+	for _, sale := range stored {
+		if _, ok := t.pending[sale.ID]; !ok {
+			merged = append(merged, sale)
+		}
+	}
+	for _, sale := range t.pending {
+		copied := *sale
+		merged = append(merged, &copied)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].ID < merged[j].ID })
+	return merged, nil
+}
+
+func (t *redisTx) WithTx(ctx context.Context, fn func(tx Storage) error) error {
+	return fn(t)
+}
+
+func decodeRedisSale(data []byte) (*Sale, error) {
+	var sale Sale
+	if err := json.Unmarshal(data, &sale); err != nil {
+		return nil, fmt.Errorf("redis decode sale: %w", err)
+	}
+	return &sale, nil
+}
+
+// IsTransientRedisError adds to IsTransientError the Redis replies that go
+// away on their own: a server still loading its dataset, a replica being
+// promoted and cluster slots being migrated.
+func IsTransientRedisError(err error) bool {
+	var redisErr redis.Error
+	if errors.As(err, &redisErr) {
+		for _, prefix := range []string{"LOADING", "READONLY", "TRYAGAIN", "CLUSTERDOWN", "MASTERDOWN"} {
+			if strings.HasPrefix(redisErr.Error(), prefix) {
+				return true
+			}
+		}
+	}
+	return IsTransientError(err)
+}
+
+// RedisRecurringStorage keeps the recurring sale definitions as JSON in the
+// hash prefix+"recurring", so the scheduler of every instance sees the same
+// definitions.
+type RedisRecurringStorage struct {
+	client  redis.UniversalClient
+	key     string
+	timeout time.Duration
+}
+
+func NewRedisRecurringStorage(client redis.UniversalClient, prefix string, timeout time.Duration) *RedisRecurringStorage {
+	return &RedisRecurringStorage{client: client, key: prefix + "recurring", timeout: timeout}
+}
+
+func (r *RedisRecurringStorage) Set(rs *RecurringSale) error {
+	if rs.ID == "" {
+		return ErrEmptyID
+	}
+	data, err := json.Marshal(rs)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	if err := r.client.HSet(ctx, r.key, rs.ID, data).Err(); err != nil {
+		return fmt.Errorf("redis put recurring sale: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisRecurringStorage) Read(id string) (*RecurringSale, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	data, err := r.client.HGet(ctx, r.key, id).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrRecurringNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis get recurring sale: %w", err)
+	}
+	return decodeRedisRecurringSale(data)
+}
+
+// GetAll retorna las definiciones en orden de ID.
+func (r *RedisRecurringStorage) GetAll() ([]*RecurringSale, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	values, err := r.client.HGetAll(ctx, r.key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis list recurring sales: %w", err)
+	}
+
+	defs := make([]*RecurringSale, 0, len(values))
+	for _, data := range values {
+		rs, err := decodeRedisRecurringSale([]byte(data))
+		if err != nil {
+			return nil, err
+		}
+		defs = append(defs, rs)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].ID < defs[j].ID })
+	return defs, nil
+}
+
+func decodeRedisRecurringSale(data []byte) (*RecurringSale, error) {
+	var rs RecurringSale
+	if err := json.Unmarshal(data, &rs); err != nil {
+		return nil, fmt.Errorf("redis decode recurring sale: %w", err)
+	}
+	return &rs, nil
+}
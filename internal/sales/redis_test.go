@@ -0,0 +1,172 @@
+package sales
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestRedisStorage(t *testing.T, ttl time.Duration) (*RedisStorage, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewRedisStorage(client, "test:", ttl, time.Second), server
+}
+
+func TestRedisStorageRoundTrip(t *testing.T) {
+	storage, server := newTestRedisStorage(t, 0)
+
+	storage.Set(&Sale{ID: "b", UserID: "u1", Amount: 10, Status: StatusPending})
+	storage.Set(&Sale{ID: "a", UserID: "u2", Amount: 5, Status: StatusApproved})
+	if err := storage.Set(&Sale{ID: "b", UserID: "u1", Amount: 20, Status: StatusApproved}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := storage.Set(&Sale{}); err != ErrEmptyID {
+		t.Errorf("expected ErrEmptyID, got %v", err)
+	}
+
+	sale, err := storage.Read("b")
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if sale.Amount != 20 || sale.Status != StatusApproved {
+		t.Errorf("expected the last write, got %+v", sale)
+	}
+	if _, err := storage.Read("missing"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	all, err := storage.GetAll()
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+	if len(all) != 2 || all[0].ID != "a" || all[1].ID != "b" {
+		t.Errorf("unexpected sales %+v", all)
+	}
+	if ttl := server.TTL("test:sale:a"); ttl != 0 {
+		t.Errorf("expected no expiry without a ttl, got %v", ttl)
+	}
+}
+
+// verifica que las ventas vencidas desaparezcan también del índice
+func TestRedisStorageExpiresSales(t *testing.T) {
+	storage, server := newTestRedisStorage(t, time.Hour)
+
+	storage.Set(&Sale{ID: "old"})
+	server.FastForward(30 * time.Minute)
+	storage.Set(&Sale{ID: "new"})
+	server.FastForward(45 * time.Minute)
+
+	if _, err := storage.Read("old"); err != ErrNotFound {
+		t.Errorf("expected the old sale expired, got %v", err)
+	}
+	all, err := storage.GetAll()
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+	if len(all) != 1 || all[0].ID != "new" {
+		t.Errorf("unexpected sales %+v", all)
+	}
+	if members, _ := server.ZMembers("test:sales"); len(members) != 1 {
+		t.Errorf("expected the expired ID removed from the index, got %v", members)
+	}
+}
+
+func TestRedisStorageWithTx(t *testing.T) {
+	storage, _ := newTestRedisStorage(t, 0)
+	storage.Set(&Sale{ID: "s1", Amount: 1})
+
+	// Las lecturas ven las escrituras de la transacción, que se guardan al final
+	err := storage.WithTx(context.Background(), func(tx Storage) error {
+		tx.Set(&Sale{ID: "s2", Amount: 2})
+		return tx.WithTx(context.Background(), func(inner Storage) error {
+			if all, _ := inner.GetAll(); len(all) != 2 {
+				t.Errorf("expected the pending write visible, got %+v", all)
+			}
+			if _, err := storage.Read("s2"); err != ErrNotFound {
+				t.Errorf("expected nothing stored before the commit, got %v", err)
+			}
+			return inner.Set(&Sale{ID: "s1", Amount: 10})
+		})
+	})
+	if err != nil {
+		t.Fatalf("WithTx: %v", err)
+	}
+	if sale, _ := storage.Read("s1"); sale == nil || sale.Amount != 10 {
+		t.Errorf("expected the committed edit, got %+v", sale)
+	}
+	if _, err := storage.Read("s2"); err != nil {
+		t.Errorf("expected the committed sale, got %v", err)
+	}
+
+	// Descarta las escrituras cuando fn falla
+	boom := errors.New("boom")
+	err = storage.WithTx(context.Background(), func(tx Storage) error {
+		tx.Set(&Sale{ID: "s3"})
+		return boom
+	})
+	if err != boom {
+		t.Errorf("expected fn's error, got %v", err)
+	}
+	if _, err := storage.Read("s3"); err != ErrNotFound {
+		t.Errorf("expected the write discarded, got %v", err)
+	}
+}
+
+func TestIsTransientRedisError(t *testing.T) {
+	storage, server := newTestRedisStorage(t, 0)
+	server.SetError("LOADING Redis is loading the dataset in memory")
+	_, err := storage.Read("s1")
+	if !IsTransientRedisError(err) {
+		t.Errorf("expected LOADING to be transient, got %v", err)
+	}
+	server.SetError("WRONGTYPE Operation against a key holding the wrong kind of value")
+	_, err = storage.Read("s1")
+	if IsTransientRedisError(err) {
+		t.Errorf("expected WRONGTYPE not to be transient, got %v", err)
+	}
+	if IsTransientRedisError(fmt.Errorf("redis get sale: %w", redis.Nil)) {
+		t.Error("expected redis.Nil not to be transient")
+	}
+}
+
+func TestRedisRecurringStorageRoundTrip(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	storage := NewRedisRecurringStorage(client, "test:", time.Second)
+
+	next := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	storage.Set(&RecurringSale{ID: "r2", UserID: "u1", Amount: 10, Interval: "monthly", NextRunAt: next})
+	if err := storage.Set(&RecurringSale{ID: "r1", UserID: "u2", Amount: 5, Interval: "weekly", NextRunAt: next}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := storage.Set(&RecurringSale{}); err != ErrEmptyID {
+		t.Errorf("expected ErrEmptyID, got %v", err)
+	}
+
+	// otra instancia sobre el mismo servidor ve las mismas definiciones
+	other := NewRedisRecurringStorage(client, "test:", time.Second)
+	rs, err := other.Read("r2")
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if rs.Amount != 10 || !rs.NextRunAt.Equal(next) {
+		t.Errorf("unexpected definition %+v", rs)
+	}
+	if _, err := other.Read("missing"); err != ErrRecurringNotFound {
+		t.Errorf("expected ErrRecurringNotFound, got %v", err)
+	}
+
+	all, err := other.GetAll()
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+	if len(all) != 2 || all[0].ID != "r1" || all[1].ID != "r2" {
+		t.Errorf("expected r1, r2 in order, got %+v", all)
+	}
+}
@@ -0,0 +1,256 @@
+package sales
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var replicationLag = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "sales_replication_lag_seconds",
+	Help: "Age of the oldest sale change not yet written to the secondary storage.",
+})
+
+var replicationPending = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "sales_replication_pending",
+	Help: "Sales changed on the primary storage and not yet replicated.",
+})
+
+var replicationErrors = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "sales_replication_errors_total",
+	Help: "Failed writes to the secondary storage; the sale is retried.",
+})
+
+// Error para promociones repetidas
+var ErrAlreadyPromoted = errors.New("secondary storage already promoted")
+
+// ReplicationStatus is a snapshot of the replication to the secondary storage.
+type ReplicationStatus struct {
+	Pending    int        `json:"pending"`
+	LagSeconds float64    `json:"lag_seconds"`
+	LastSyncAt *time.Time `json:"last_sync_at,omitempty"`
+	Promoted   bool       `json:"promoted"`
+	PromotedAt *time.Time `json:"promoted_at,omitempty"`
+}
+
+// ReplicatedStorage serves from the primary storage and replicates every
+// write asynchronously to a secondary one, e.g. in another region. Changes
+// are tailed from the writes themselves: each Set marks the sale pending and
+// a background loop copies its latest version, so bursts of writes to the
+// same sale are coalesced and a slow secondary never blocks a request.
+// Promote switches reads and writes to the secondary for disaster recovery.
+type ReplicatedStorage struct {
+	primary   Storage
+	secondary Storage
+	retry     time.Duration
+	logger    *zap.Logger
+
+	mu         sync.Mutex
+	pending    map[string]time.Time
+	lastSyncAt time.Time
+	promotedAt time.Time
+	wake       chan struct{}
+}
+
+func NewReplicatedStorage(primary, secondary Storage, logger *zap.Logger) *ReplicatedStorage {
+	return &ReplicatedStorage{
+		primary:   primary,
+		secondary: secondary,
+		retry:     time.Second,
+		logger:    logger,
+		pending:   make(map[string]time.Time),
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// active retorna el storage que atiende las requests.
+func (r *ReplicatedStorage) active() Storage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.promotedAt.IsZero() {
+		return r.secondary
+	}
+	return r.primary
+}
+
+func (r *ReplicatedStorage) Set(sale *Sale) error {
+	storage := r.active()
+	if err := storage.Set(sale); err != nil {
+		return err
+	}
+	if storage == r.primary {
+		r.markPending(sale.ID, time.Now())
+	}
+	return nil
+}
+
+func (r *ReplicatedStorage) Read(id string) (*Sale, error) {
+	return r.active().Read(id)
+}
+
+func (r *ReplicatedStorage) GetAll() ([]*Sale, error) {
+	return r.active().GetAll()
+}
+
+// WithTx corre fn en una transacción del storage activo. Las ventas escritas
+// se marcan pendientes aunque la transacción no confirme: se copia la
+// versión del primario, así que replicarlas de más no cambia nada.
+func (r *ReplicatedStorage) WithTx(ctx context.Context, fn func(tx Storage) error) error {
+	storage := r.active()
+	return storage.WithTx(ctx, func(tx Storage) error {
+		return fn(&replicatedTx{Storage: tx, replicated: r, primary: storage == r.primary})
+	})
+}
+
+// unwrap retorna el primario: los cambios auditados se guardan con él.
+func (r *ReplicatedStorage) unwrap() Storage { return r.primary }
+
+// replicatedTx marca pendientes las escrituras hechas dentro de WithTx.
+type replicatedTx struct {
+	Storage
+	replicated *ReplicatedStorage
+	primary    bool
+}
+
+func (t *replicatedTx) Set(sale *Sale) error {
+	if err := t.Storage.Set(sale); err != nil {
+		return err
+	}
+	if t.primary {
+		t.replicated.markPending(sale.ID, time.Now())
+	}
+	return nil
+}
+
+func (t *replicatedTx) unwrap() Storage { return t.Storage }
+
+func (r *ReplicatedStorage) markPending(id string, at time.Time) {
+	r.mu.Lock()
+	if _, ok := r.pending[id]; !ok {
+		r.pending[id] = at
+	}
+	r.mu.Unlock()
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start backfills the sales already in the primary storage and replicates
+// changes until ctx ends or the secondary is promoted.
+func (r *ReplicatedStorage) Start(ctx context.Context) {
+	all, err := r.primary.GetAll()
+	if err != nil {
+		r.logger.Error("failed to list sales for the replication backfill", zap.Error(err))
+	}
+	now := time.Now()
+	for _, sale := range all {
+		r.markPending(sale.ID, now)
+	}
+
+	ticker := time.NewTicker(r.retry)
+	defer ticker.Stop()
+	for {
+		if r.Status().Promoted {
+			return
+		}
+		r.sync()
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync copia la última versión de cada venta pendiente al secundario; las que
+// fallan quedan pendientes para el próximo intento.
+func (r *ReplicatedStorage) sync() {
+	r.mu.Lock()
+	batch := make(map[string]time.Time, len(r.pending))
+	for id, at := range r.pending {
+		batch[id] = at
+	}
+	r.mu.Unlock()
+
+	for id, changedAt := range batch {
+		sale, err := r.primary.Read(id)
+		if err == nil {
+			err = r.secondary.Set(sale.clone())
+		}
+		if err != nil {
+			replicationErrors.Inc()
+			r.logger.Warn("failed to replicate sale", zap.String("sale_id", id), zap.Error(err))
+			continue
+		}
+
+		r.mu.Lock()
+		// Si cambió mientras se copiaba queda pendiente con la hora original
+		if at, ok := r.pending[id]; ok && at.Equal(changedAt) {
+			delete(r.pending, id)
+		}
+		r.lastSyncAt = time.Now()
+		r.mu.Unlock()
+	}
+	r.updateMetrics()
+}
+
+func (r *ReplicatedStorage) updateMetrics() {
+	status := r.Status()
+	replicationPending.Set(float64(status.Pending))
+	replicationLag.Set(status.LagSeconds)
+}
+
+// Status returns how far behind the secondary is.
+func (r *ReplicatedStorage) Status() ReplicationStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	status := ReplicationStatus{Pending: len(r.pending), Promoted: !r.promotedAt.IsZero()}
+	now := time.Now()
+	for _, at := range r.pending {
+		status.LagSeconds = max(status.LagSeconds, now.Sub(at).Seconds())
+	}
+	if !r.lastSyncAt.IsZero() {
+		at := r.lastSyncAt
+		status.LastSyncAt = &at
+	}
+	if status.Promoted {
+		at := r.promotedAt
+		status.PromotedAt = &at
+	}
+	return status
+}
+
+// Promote makes the secondary storage serve every read and write and stops
+// replicating. It first tries to copy the pending changes until ctx ends; the
+// returned status reports what could not be copied, which is lost if the
+// primary region is gone.
+func (r *ReplicatedStorage) Promote(ctx context.Context) (ReplicationStatus, error) {
+	if r.Status().Promoted {
+		return ReplicationStatus{}, ErrAlreadyPromoted
+	}
+	for r.Status().Pending > 0 && ctx.Err() == nil {
+		r.sync()
+		if r.Status().Pending > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(r.retry):
+			}
+		}
+	}
+
+	r.mu.Lock()
+	r.promotedAt = time.Now()
+	r.mu.Unlock()
+	status := r.Status()
+	r.logger.Warn("secondary storage promoted", zap.Int("unreplicated", status.Pending))
+	r.updateMetrics()
+	return status, nil
+}
@@ -0,0 +1,63 @@
+package sales
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// TestReplicatedStorage_ReplicatesAndPromotes verifica el backfill, la copia
+// asíncrona de los cambios y que tras la promoción el secundario atienda todo.
+func TestReplicatedStorage_ReplicatesAndPromotes(t *testing.T) {
+	primary, secondary := NewLocalStorage(), NewLocalStorage()
+	if err := primary.Set(&Sale{ID: "old", UserID: "user123", Amount: 5, Status: StatusPending, Version: 1}); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+	replicated := NewReplicatedStorage(primary, secondary, zaptest.NewLogger(t))
+
+	if err := replicated.Set(&Sale{ID: "s1", UserID: "user123", Amount: 10, Status: StatusPending, Version: 1}); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+	if status := replicated.Status(); status.Pending != 1 {
+		t.Fatalf("expected 1 pending change before replicating, got %+v", status)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go replicated.Start(ctx)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for replicated.Status().Pending > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	status := replicated.Status()
+	if status.Pending != 0 || status.LagSeconds != 0 || status.LastSyncAt == nil {
+		t.Fatalf("expected the secondary to catch up, got %+v", status)
+	}
+	for _, id := range []string{"old", "s1"} {
+		if _, err := secondary.Read(id); err != nil {
+			t.Errorf("expected sale %s in the secondary, got %v", id, err)
+		}
+	}
+
+	status, err := replicated.Promote(context.Background())
+	if err != nil || !status.Promoted || status.PromotedAt == nil {
+		t.Fatalf("expected the promotion to succeed, got %+v, %v", status, err)
+	}
+	if _, err := replicated.Promote(context.Background()); err != ErrAlreadyPromoted {
+		t.Errorf("expected ErrAlreadyPromoted, got %v", err)
+	}
+
+	// Tras promover las escrituras van solo al secundario
+	if err := replicated.Set(&Sale{ID: "s2", UserID: "user123", Amount: 20, Status: StatusPending, Version: 1}); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+	if _, err := primary.Read("s2"); err != ErrNotFound {
+		t.Errorf("expected the promoted storage to skip the primary, got %v", err)
+	}
+	if _, err := replicated.Read("s2"); err != nil {
+		t.Errorf("expected to read s2 from the secondary, got %v", err)
+	}
+}
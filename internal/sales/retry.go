@@ -0,0 +1,202 @@
+package sales
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var storageRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "sales_storage_retries_total",
+	Help: "Storage operations retried after a transient error, by backend and operation.",
+}, []string{"backend", "op"})
+
+var storageRetriesExhausted = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "sales_storage_retries_exhausted_total",
+	Help: "Storage operations that kept failing with transient errors on every attempt.",
+}, []string{"backend", "op"})
+
+// RetryError is returned when a storage operation failed with a transient
+// error on every attempt. It wraps the last error.
+type RetryError struct {
+	Backend  string
+	Op       string
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("%s storage %s failed after %d attempts: %v", e.Backend, e.Op, e.Attempts, e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// RetryPolicy says how often and how fast a storage operation is retried.
+// Transient decides which errors are worth retrying; defaults to
+// IsTransientError.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+	Transient   func(error) bool
+}
+
+// delay duplica el backoff por intento hasta MaxBackoff y sortea entre la
+// mitad y el total, para que los reintentos de varias requests no coincidan.
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := p.Backoff << (attempt - 1)
+	if d <= 0 || (p.MaxBackoff > 0 && d > p.MaxBackoff) {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// IsTransientError reports whether err is worth retrying: connection resets,
+// timeouts and deadlocks. Missing sales and invalid input never are.
+func IsTransientError(err error) bool {
+	if err == nil || errors.Is(err, ErrNotFound) || errors.Is(err, ErrEmptyID) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return strings.Contains(strings.ToLower(err.Error()), "deadlock")
+}
+
+// RetryingStorage retries the operations of storage that fail with a
+// transient error, waiting a jittered exponential backoff between attempts.
+// When every attempt fails it gives up with a *RetryError.
+type RetryingStorage struct {
+	storage Storage
+	backend string
+	policy  RetryPolicy
+	logger  *zap.Logger
+	sleep   func(time.Duration)
+}
+
+func NewRetryingStorage(storage Storage, backend string, policy RetryPolicy, logger *zap.Logger) *RetryingStorage {
+	if policy.Transient == nil {
+		policy.Transient = IsTransientError
+	}
+	return &RetryingStorage{
+		storage: storage,
+		backend: backend,
+		policy:  policy,
+		logger:  logger,
+		sleep:   time.Sleep,
+	}
+}
+
+func (r *RetryingStorage) do(op string, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !r.policy.Transient(err) {
+			return err
+		}
+		if attempt >= r.policy.MaxAttempts {
+			storageRetriesExhausted.WithLabelValues(r.backend, op).Inc()
+			r.logger.Warn("storage operation failed on every attempt", zap.String("backend", r.backend), zap.String("op", op), zap.Int("attempts", attempt), zap.Error(err))
+			return &RetryError{Backend: r.backend, Op: op, Attempts: attempt, Err: err}
+		}
+		storageRetries.WithLabelValues(r.backend, op).Inc()
+		delay := r.policy.delay(attempt)
+		r.logger.Debug("retrying storage operation", zap.String("backend", r.backend), zap.String("op", op), zap.Int("attempt", attempt), zap.Duration("delay", delay), zap.Error(err))
+		r.sleep(delay)
+	}
+}
+
+func (r *RetryingStorage) Set(sale *Sale) error {
+	return r.do("set", func() error {
+		return r.storage.Set(sale)
+	})
+}
+
+func (r *RetryingStorage) Read(id string) (*Sale, error) {
+	var sale *Sale
+	err := r.do("read", func() error {
+		var err error
+		sale, err = r.storage.Read(id)
+		return err
+	})
+	return sale, err
+}
+
+func (r *RetryingStorage) GetAll() ([]*Sale, error) {
+	var all []*Sale
+	err := r.do("get_all", func() error {
+		var err error
+		all, err = r.storage.GetAll()
+		return err
+	})
+	return all, err
+}
+
+func (r *RetryingStorage) GetByUser(userID string) ([]*Sale, error) {
+	var owned []*Sale
+	err := r.do("get_by_user", func() error {
+		var err error
+		owned, err = salesOfUser(r.storage, userID)
+		return err
+	})
+	return owned, err
+}
+
+func (r *RetryingStorage) GetByStatus(status string) ([]*Sale, error) {
+	var found []*Sale
+	err := r.do("get_by_status", func() error {
+		var err error
+		found, err = salesWithStatus(r.storage, status)
+		return err
+	})
+	return found, err
+}
+
+func (r *RetryingStorage) GetByTrackingNumber(trackingNumber string) (*Sale, error) {
+	var sale *Sale
+	err := r.do("get_by_tracking_number", func() error {
+		var err error
+		sale, err = saleWithTracking(r.storage, trackingNumber)
+		return err
+	})
+	return sale, err
+}
+
+func (r *RetryingStorage) Query(q SaleQuery) ([]*Sale, error) {
+	var found []*Sale
+	err := r.do("query", func() error {
+		var err error
+		found, err = findSales(r.storage, q)
+		return err
+	})
+	return found, err
+}
+
+// WithTx reintenta la transacción completa, por ejemplo ante un deadlock, así
+// que fn puede correr más de una vez.
+func (r *RetryingStorage) WithTx(ctx context.Context, fn func(tx Storage) error) error {
+	return r.do("tx", func() error {
+		return r.storage.WithTx(ctx, fn)
+	})
+}
+
+func (r *RetryingStorage) unwrap() Storage { return r.storage }
@@ -0,0 +1,96 @@
+package sales
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap/zaptest"
+)
+
+// flakyStorage falla las primeras failures llamadas con err.
+type flakyStorage struct {
+	Storage
+	failures int
+	err      error
+	calls    int
+}
+
+func (f *flakyStorage) Read(id string) (*Sale, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, f.err
+	}
+	return f.Storage.Read(id)
+}
+
+func newTestRetryingStorage(t *testing.T, inner Storage, attempts int) (*RetryingStorage, *[]time.Duration) {
+	storage := NewRetryingStorage(inner, "test", RetryPolicy{MaxAttempts: attempts, Backoff: 10 * time.Millisecond, MaxBackoff: 15 * time.Millisecond}, zaptest.NewLogger(t))
+	delays := &[]time.Duration{}
+	storage.sleep = func(d time.Duration) { *delays = append(*delays, d) }
+	return storage, delays
+}
+
+// verifica que los errores transitorios se reintentan con backoff acotado
+func TestRetryingStorageRetriesTransientErrors(t *testing.T) {
+	local := NewLocalStorage()
+	local.Set(&Sale{ID: "s1", Amount: 10})
+	inner := &flakyStorage{Storage: local, failures: 2, err: fmt.Errorf("read sale: %w", syscall.ECONNRESET)}
+	storage, delays := newTestRetryingStorage(t, inner, 3)
+
+	sale, err := storage.Read("s1")
+	if err != nil {
+		t.Fatalf("expected the read to succeed on the third attempt, got %v", err)
+	}
+	if sale.ID != "s1" || inner.calls != 3 {
+		t.Errorf("expected sale s1 after 3 calls, got %+v after %d", sale, inner.calls)
+	}
+	if len(*delays) != 2 {
+		t.Fatalf("expected 2 backoffs, got %v", *delays)
+	}
+	for i, d := range *delays {
+		if d < 5*time.Millisecond || d > 15*time.Millisecond {
+			t.Errorf("backoff %d out of range: %s", i, d)
+		}
+	}
+}
+
+// verifica que al agotar los intentos se retorna un RetryError con el último error
+func TestRetryingStorageGivesUp(t *testing.T) {
+	inner := &flakyStorage{Storage: NewLocalStorage(), failures: 10, err: errors.New("deadlock detected")}
+	storage, _ := newTestRetryingStorage(t, inner, 3)
+
+	_, err := storage.Read("s1")
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("expected a RetryError, got %v", err)
+	}
+	if retryErr.Attempts != 3 || retryErr.Op != "read" || inner.calls != 3 {
+		t.Errorf("expected 3 read attempts, got %+v after %d calls", retryErr, inner.calls)
+	}
+}
+
+// verifica que los errores permanentes no se reintentan
+func TestRetryingStorageSkipsPermanentErrors(t *testing.T) {
+	for _, err := range []error{ErrNotFound, errors.New("validation failed")} {
+		inner := &flakyStorage{Storage: NewLocalStorage(), failures: 10, err: err}
+		storage, _ := newTestRetryingStorage(t, inner, 3)
+		if _, got := storage.Read("s1"); !errors.Is(got, err) || inner.calls != 1 {
+			t.Errorf("expected %v returned after one call, got %v after %d", err, got, inner.calls)
+		}
+	}
+}
+
+// verifica los errores transitorios propios de DynamoDB
+func TestIsTransientDynamoDBError(t *testing.T) {
+	throttled := fmt.Errorf("dynamodb put sale: %w", &types.ProvisionedThroughputExceededException{})
+	if !IsTransientDynamoDBError(throttled) {
+		t.Errorf("expected throttling to be transient")
+	}
+	if IsTransientDynamoDBError(&types.ResourceNotFoundException{}) {
+		t.Errorf("expected a missing table not to be transient")
+	}
+}
@@ -0,0 +1,165 @@
+package sales
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Revision is a version of a sale as it was persisted at RecordedAt.
+type Revision struct {
+	Sale       *Sale     `json:"sale"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// RevisionStorage persists every version of the sales in the order they are
+// written.
+type RevisionStorage interface {
+	Append(rev *Revision) error
+	// GetAll returns every revision in append order.
+	GetAll() ([]*Revision, error)
+}
+
+type LocalRevisionStorage struct {
+	mu        sync.RWMutex
+	revisions []*Revision
+}
+
+func NewLocalRevisionStorage() *LocalRevisionStorage {
+	return &LocalRevisionStorage{}
+}
+
+func (l *LocalRevisionStorage) Append(rev *Revision) error {
+	if rev.Sale == nil || rev.Sale.ID == "" {
+		return ErrEmptyID
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.revisions = append(l.revisions, rev)
+	return nil
+}
+
+func (l *LocalRevisionStorage) GetAll() ([]*Revision, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]*Revision(nil), l.revisions...), nil
+}
+
+// WithRevisionStorage sets where the version history of the sales is kept.
+// Defaults to an in-memory LocalRevisionStorage.
+func WithRevisionStorage(revisions RevisionStorage) Option {
+	return func(s *Service) {
+		s.revisions = revisions
+	}
+}
+
+// revisionStorage guarda una copia de cada versión escrita. Va por debajo del
+// cifrado, así que la metadata sensible queda cifrada también en el historial.
+type revisionStorage struct {
+	Storage
+	svc *Service
+}
+
+func (r revisionStorage) Set(sale *Sale) error {
+	if err := r.Storage.Set(sale); err != nil {
+		return err
+	}
+	// Sin la revisión la venta ya quedó guardada: se avisa y se sigue
+	if err := r.svc.revisions.Append(&Revision{Sale: sale.clone(), RecordedAt: utcNow()}); err != nil {
+		r.svc.logger.Error("failed to record sale revision", zap.String("sale_id", sale.ID), zap.Error(err))
+	}
+	return nil
+}
+
+func (r revisionStorage) GetByUser(userID string) ([]*Sale, error) {
+	return salesOfUser(r.Storage, userID)
+}
+
+func (r revisionStorage) GetByStatus(status string) ([]*Sale, error) {
+	return salesWithStatus(r.Storage, status)
+}
+
+func (r revisionStorage) GetByTrackingNumber(trackingNumber string) (*Sale, error) {
+	return saleWithTracking(r.Storage, trackingNumber)
+}
+
+func (r revisionStorage) Query(q SaleQuery) ([]*Sale, error) {
+	return findSales(r.Storage, q)
+}
+
+func (r revisionStorage) WithTx(ctx context.Context, fn func(tx Storage) error) error {
+	return r.Storage.WithTx(ctx, func(tx Storage) error {
+		return fn(revisionStorage{Storage: tx, svc: r.svc})
+	})
+}
+
+func (r revisionStorage) unwrap() Storage { return r.Storage }
+
+// seedRevisions registra la versión actual de las ventas que todavía no tienen
+// historial, fechada en su UpdatedAt, para poder reconstruirlas aunque sean
+// anteriores a él.
+func (s *Service) seedRevisions() {
+	revisions, err := s.revisions.GetAll()
+	if err != nil {
+		s.logger.Error("failed to read sale revisions", zap.Error(err))
+		return
+	}
+	all, err := s.storage.GetAll()
+	if err != nil {
+		s.logger.Error("failed to list sales to seed their revisions", zap.Error(err))
+		return
+	}
+
+	known := make(map[string]struct{}, len(revisions))
+	for _, rev := range revisions {
+		known[rev.Sale.ID] = struct{}{}
+	}
+	for _, sale := range all {
+		if _, ok := known[sale.ID]; ok {
+			continue
+		}
+		if err := s.revisions.Append(&Revision{Sale: sale.clone(), RecordedAt: sale.UpdatedAt}); err != nil {
+			s.logger.Error("failed to record sale revision", zap.String("sale_id", sale.ID), zap.Error(err))
+		}
+	}
+}
+
+// salesAsOf reconstruye el estado de las ventas en el instante at con la
+// última revisión de cada una registrada hasta at.
+func (s *Service) salesAsOf(at time.Time) ([]*Sale, error) {
+	revisions, err := s.revisions.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve sale revisions: %w", err)
+	}
+
+	latest := make(map[string]*Sale)
+	for _, rev := range revisions {
+		if rev.RecordedAt.After(at) {
+			continue
+		}
+		latest[rev.Sale.ID] = rev.Sale
+	}
+	result := make([]*Sale, 0, len(latest))
+	for _, sale := range latest {
+		snapshot := sale.clone()
+		if s.encrypter != nil {
+			if snapshot, err = (encryptedReader{svc: s}).decrypt(snapshot); err != nil {
+				return nil, err
+			}
+		}
+		result = append(result, snapshot)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
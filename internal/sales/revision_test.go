@@ -0,0 +1,48 @@
+package sales
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// TestSearchSale_AsOfReconstructsPastState verifica que as_of devuelva el
+// estado de cada venta en ese instante y omita las creadas después.
+func TestSearchSale_AsOfReconstructsPastState(t *testing.T) {
+	storage := NewLocalStorage()
+	// Anterior al historial: queda registrada al crear el servicio
+	storage.Set(&Sale{ID: "legacy", UserID: "u1", Amount: 7, Status: StatusApproved, CreatedAt: time.Now().Add(-time.Hour), UpdatedAt: time.Now().Add(-time.Hour)})
+	svc := NewService(storage, zaptest.NewLogger(t), "http://unused")
+
+	sale := &Sale{ID: "s1", UserID: "u1", Amount: 10, Status: StatusPending, CreatedAt: utcNow(), UpdatedAt: utcNow(), Version: 1}
+	if err := svc.storage.Set(sale); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	asOf := time.Now()
+	time.Sleep(5 * time.Millisecond)
+
+	if _, err := svc.UpdateSaleStatus(sale.ID, StatusApproved); err != nil {
+		t.Fatalf("UpdateSaleStatus returned error: %v", err)
+	}
+	if _, err := svc.CreateDraftSale("u1", 99); err != nil {
+		t.Fatalf("CreateDraftSale returned error: %v", err)
+	}
+
+	results, metadata, err := svc.SearchSale(SearchFilter{AsOf: &asOf})
+	if err != nil {
+		t.Fatalf("SearchSale returned error: %v", err)
+	}
+	if len(results) != 2 || results[0].ID != "legacy" || results[1].ID != sale.ID {
+		t.Fatalf("expected the legacy sale and the submitted sale, got %+v", results)
+	}
+	if results[1].Status != StatusPending || metadata.Pending != 1 || metadata.TotalAmount != 17 {
+		t.Errorf("expected the sale still pending at as_of, got %s with metadata %+v", results[1].Status, metadata)
+	}
+
+	current, _ := svc.GetSale(sale.ID, nil)
+	if current.Status != StatusApproved {
+		t.Errorf("expected as_of not to alter the current state, got %s", current.Status)
+	}
+}
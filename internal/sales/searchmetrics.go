@@ -0,0 +1,101 @@
+package sales
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+// DefaultSlowQueryThreshold is the search latency above which a query is
+// logged as slow.
+const DefaultSlowQueryThreshold = 500 * time.Millisecond
+
+var searchDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "sales_search_duration_seconds",
+	Help:    "SearchSale latency by filter shape.",
+	Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+}, []string{"filter", "outcome"})
+
+var searchResults = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "sales_search_results",
+	Help:    "Number of sales returned by SearchSale by filter shape.",
+	Buckets: prometheus.ExponentialBuckets(1, 4, 8),
+}, []string{"filter"})
+
+// WithSlowQueryThreshold sets the latency above which searches are logged with
+// their timing breakdown. Zero disables slow-query logging.
+func WithSlowQueryThreshold(d time.Duration) Option {
+	return func(s *Service) {
+		s.slowQueryThreshold = d
+	}
+}
+
+// searchTrace acumula el desglose de tiempos de una búsqueda.
+type searchTrace struct {
+	start   time.Time
+	filters map[string]string
+	phases  []zap.Field
+	last    time.Time
+}
+
+func newSearchTrace(filters map[string]string) *searchTrace {
+	now := time.Now()
+	return &searchTrace{start: now, last: now, filters: filters}
+}
+
+// phase registra el tiempo transcurrido desde la fase anterior.
+func (t *searchTrace) phase(name string) {
+	now := time.Now()
+	t.phases = append(t.phases, zap.Duration(name, now.Sub(t.last)))
+	t.last = now
+}
+
+// shape describe qué filtros se usaron, p.ej. "status,user_id" o "none".
+func (t *searchTrace) shape() string {
+	used := make([]string, 0, len(t.filters))
+	for name, value := range t.filters {
+		if value != "" {
+			used = append(used, name)
+		}
+	}
+	if len(used) == 0 {
+		return "none"
+	}
+	sort.Strings(used)
+	return strings.Join(used, ",")
+}
+
+// observeSearch exporta las métricas de la búsqueda y la registra si fue lenta.
+func (s *Service) observeSearch(t *searchTrace, results int, err error) {
+	elapsed := time.Since(t.start)
+	shape := t.shape()
+
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	} else {
+		searchResults.WithLabelValues(shape).Observe(float64(results))
+	}
+	searchDuration.WithLabelValues(shape, outcome).Observe(elapsed.Seconds())
+
+	if s.slowQueryThreshold <= 0 || elapsed < s.slowQueryThreshold {
+		return
+	}
+
+	fields := []zap.Field{
+		zap.String("filter_shape", shape),
+		zap.Any("filters", t.filters),
+		zap.Int("results_count", results),
+		zap.Duration("total", elapsed),
+		zap.Duration("threshold", s.slowQueryThreshold),
+	}
+	fields = append(fields, t.phases...)
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	s.logger.Warn("slow sales search", fields...)
+}
@@ -1,26 +1,37 @@
 package sales
 
 import (
+	"api_sales/internal/blob"
+	"api_sales/internal/calendar"
+	"api_sales/internal/dispatch"
+	"api_sales/internal/sales/events"
 	"errors"
 	"fmt"
-	"math/rand"
 	"net/http"
+	neturl "net/url"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 	"resty.dev/v3"
 )
 
 type User struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Phone string `json:"phone,omitempty"`
 }
 
 type UserClient struct {
 	baseURL string
 	client  *resty.Client
+	cache   UserCache
+	// inflight agrupa las consultas concurrentes por el mismo usuario
+	inflight singleflight.Group
 }
 
 func NewUserClient(baseURL string) *UserClient {
@@ -32,7 +43,56 @@ func NewUserClient(baseURL string) *UserClient {
 
 // GetUserByID hace una petición GET al servicio de usuarios para verificar si un usuario existe.
 func (uc *UserClient) GetUserByID(userID string) (*User, error) {
-	url := fmt.Sprintf("%s/%s", uc.baseURL, userID)
+	// Si el cache falla se consulta directamente al servicio de usuarios
+	if uc.cache != nil {
+		if cached, ok, err := uc.cache.Get(userID); err == nil && ok {
+			return cached, nil
+		}
+	}
+
+	// Las consultas simultáneas por el mismo usuario comparten una sola petición
+	v, err, _ := uc.inflight.Do(userID, func() (interface{}, error) {
+		return uc.fetchUser(userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	user := *v.(*User)
+	return &user, nil
+}
+
+// fetchUser consulta el servicio de usuarios sin pasar por el cache.
+func (uc *UserClient) fetchUser(userID string) (*User, error) {
+	user, err := uc.requestUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if uc.cache != nil {
+		uc.cache.Set(userID, user)
+	}
+	return user, nil
+}
+
+// refreshUser consulta el servicio de usuarios y, si el cache lo permite,
+// guarda el usuario por ttl en lugar del TTL del cache.
+func (uc *UserClient) refreshUser(userID string, ttl time.Duration) (*User, error) {
+	user, err := uc.requestUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	switch cache := uc.cache.(type) {
+	case nil:
+	case ttlUserCache:
+		err = cache.SetWithTTL(userID, user, ttl)
+	default:
+		err = cache.Set(userID, user)
+	}
+	return user, err
+}
+
+func (uc *UserClient) requestUser(userID string) (*User, error) {
+	// El ID se escapa: puede traer caracteres que rompen la URL
+	url := fmt.Sprintf("%s/%s", uc.baseURL, neturl.PathEscape(userID))
 	var user User
 
 	resp, err := uc.client.R().
@@ -61,71 +121,419 @@ var ErrInvalidTransition = errors.New("invalid status transition")
 // Error para estados inválidos
 var ErrInvalidStatus = errors.New("invalid status value")
 
+// Error para operaciones que solo aplican a borradores
+var ErrNotDraft = errors.New("sale is not a draft")
+
 type Service struct {
-	storage    Storage
-	logger     *zap.Logger
-	userClient *UserClient
+	storage      Storage
+	replica      SaleReader
+	audit        AuditStorage
+	summaries    SummaryStorage
+	periods      PeriodStorage
+	adjustments  AdjustmentStorage
+	vocabularies VocabularyStorage
+	disputes     DisputeStorage
+	shareLinks   ShareLinkStorage
+	comments     CommentStorage
+	revisions    RevisionStorage
+	snapshots    blob.Store
+	attachments  AttachmentStorage
+	stats        statsCache
+	notifiers    []Notifier
+	enrichers    []enrichStep
+	logger       *zap.Logger
+	userClient   *UserClient
+
+	slowQueryThreshold time.Duration
+	random             Random
+
+	// Canary del filtrado en el storage para ese porcentaje de las búsquedas
+	searchCanaryPercent float64
+	searchCanaryPool    *dispatch.Pool
+
+	// attachmentFiles en nil deshabilita los adjuntos
+	attachmentFiles    blob.Store
+	attachmentMaxBytes int
+	scanner            Scanner
+	ocr                OCRProvider
+
+	// provisionalSales habilita el modo degradado ante caídas del servicio de usuarios
+	provisionalSales bool
+	verifications    VerificationStorage
+	verifyAttempts   int
+	verifyBackoff    time.Duration
+	verifyMaxBackoff time.Duration
+	verifyMu         sync.Mutex
+
+	// Eventos de dominio tipados para los suscriptores desacoplados del servicio
+	events *events.Dispatcher
+
+	// Serializa los appends de auditoría fuera de una transacción
+	auditMu sync.Mutex
+
+	// Metadata sensible: se enmascara y, con encrypter, se cifra en el storage
+	encrypter     FieldEncrypter
+	sensitiveKeys map[string]struct{}
+
+	// Roles que ven todas las ventas en las búsquedas
+	elevatedRoles map[string]struct{}
+
+	// SLA de ventas pendientes medido en horario hábil; slaAlerted son las
+	// ventas ya alertadas
+	slaCalendar *calendar.Calendar
+	slaTarget   time.Duration
+	slaMu       sync.Mutex
+	slaAlerted  map[string]struct{}
+
+	// Asignación de ventas pendientes a revisores, con rotación automática
+	// cuando hay reviewers configurados
+	assignments  AssignmentStorage
+	reviewers    []string
+	claimTTL     time.Duration
+	assignMu     sync.Mutex
+	nextReviewer int
+}
+
+// Option configura dependencias opcionales del Service.
+type Option func(*Service)
+
+// WithUserCache makes the user client consult cache before calling the user
+// service.
+func WithUserCache(cache UserCache) Option {
+	return func(s *Service) {
+		s.userClient.cache = cache
+	}
+}
+
+// WithHTTPTransport makes the user client send its requests through rt, e.g.
+// to apply proxy and TLS settings.
+func WithHTTPTransport(rt http.RoundTripper) Option {
+	return func(s *Service) {
+		s.userClient.client.SetTransport(rt)
+	}
+}
+
+// WithReadReplica serves eventually consistent searches from replica, which may
+// lag behind the primary storage. Strong reads keep using the primary.
+func WithReadReplica(replica SaleReader) Option {
+	return func(s *Service) {
+		s.replica = replica
+	}
+}
+
+// reader elige el storage según la consistencia pedida.
+func (s *Service) reader(c Consistency) SaleReader {
+	if c == ConsistencyStrong || s.replica == nil {
+		return s.storage
+	}
+	return s.replica
+}
+
+// WithAuditStorage sets where the service records audit entries. Defaults to
+// the log of the sales storage's database for the SQL backends, and to an
+// in-memory LocalAuditStorage otherwise.
+func WithAuditStorage(audit AuditStorage) Option {
+	return func(s *Service) {
+		s.audit = audit
+	}
 }
 
 // Metadata para la respuesta de búsqueda
 type SalesMetadata struct {
-	Quantity    int     `json:"quantity"`
-	Approved    int     `json:"approved"`
-	Rejected    int     `json:"rejected"`
-	Pending     int     `json:"pending"`
-	TotalAmount float64 `json:"total_amount"`
+	Quantity int `json:"quantity"`
+	Approved int `json:"approved"`
+	Rejected int `json:"rejected"`
+	Pending  int `json:"pending"`
+	Draft    int `json:"draft"`
+	// ByStatus cuenta todos los estados, incluidos los propios de cada tenant
+	ByStatus    map[string]int `json:"by_status,omitempty"`
+	TotalAmount float64        `json:"total_amount"`
+	// Adjustments suma los ajustes; solo la calculan los reportes globales
+	Adjustments float64 `json:"adjustments,omitempty"`
+	// DecisionLatency resume los tiempos de aprobación y rechazo; solo la
+	// calculan los reportes globales
+	DecisionLatency *LatencyPercentiles `json:"decision_latency,omitempty"`
+
+	// UpdatedAt más reciente entre las ventas agregadas
+	lastModified time.Time
+}
+
+// LastModified returns the most recent UpdatedAt among the aggregated sales.
+func (m SalesMetadata) LastModified() time.Time {
+	return m.lastModified
+}
+
+// add suma una venta a los metadatos.
+func (m *SalesMetadata) add(sale *Sale) {
+	m.Quantity++
+	m.TotalAmount += sale.Amount
+	if sale.UpdatedAt.After(m.lastModified) {
+		m.lastModified = sale.UpdatedAt
+	}
+	if m.ByStatus == nil {
+		m.ByStatus = map[string]int{}
+	}
+	m.ByStatus[sale.Status]++
+	switch sale.Status {
+	case StatusApproved:
+		m.Approved++
+	case StatusRejected:
+		m.Rejected++
+	case StatusPending:
+		m.Pending++
+	case StatusDraft:
+		m.Draft++
+	}
 }
 
-func NewService(storage Storage, logger *zap.Logger, userAPIURL string) *Service {
+// utcNow es la hora con la que se guardan los timestamps: siempre en UTC, para
+// que los reportes no dependan de la zona del servidor.
+func utcNow() time.Time {
+	return time.Now().UTC()
+}
+
+func NewService(storage Storage, logger *zap.Logger, userAPIURL string, opts ...Option) *Service {
 	if logger == nil {
 		logger, _ = zap.NewProduction()
 		defer logger.Sync()
 	}
 
-	return &Service{
-		storage:    storage,
-		logger:     logger,
-		userClient: NewUserClient(userAPIURL),
+	s := &Service{
+		storage:            storage,
+		summaries:          NewLocalSummaryStorage(),
+		periods:            NewLocalPeriodStorage(),
+		adjustments:        NewLocalAdjustmentStorage(),
+		vocabularies:       NewLocalVocabularyStorage(),
+		disputes:           NewLocalDisputeStorage(),
+		shareLinks:         NewLocalShareLinkStorage(),
+		comments:           NewLocalCommentStorage(),
+		revisions:          NewLocalRevisionStorage(),
+		snapshots:          blob.NewMemoryStore(),
+		attachments:        NewLocalAttachmentStorage(),
+		verifications:      NewLocalVerificationStorage(),
+		verifyAttempts:     DefaultVerificationAttempts,
+		verifyBackoff:      DefaultVerificationBackoff,
+		verifyMaxBackoff:   DefaultVerificationMaxBackoff,
+		logger:             logger,
+		userClient:         NewUserClient(userAPIURL),
+		slowQueryThreshold: DefaultSlowQueryThreshold,
+		random:             globalRandom{},
+		sensitiveKeys:      map[string]struct{}{},
+		elevatedRoles:      map[string]struct{}{DefaultElevatedRole: {}},
+		slaAlerted:         map[string]struct{}{},
+		assignments:        NewLocalAssignmentStorage(),
+		claimTTL:           DefaultClaimTTL,
+	}
+	s.events = events.NewDispatcher(logger)
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.audit == nil {
+		s.audit = defaultAuditStorage(storage)
+	}
+	s.subscribeStats()
+	s.seedRevisions()
+	s.storage = revisionStorage{Storage: s.storage, svc: s}
+	if s.encrypter != nil {
+		s.storage = encryptedStorage{Storage: s.storage, svc: s}
+		if s.replica != nil {
+			s.replica = encryptedReader{SaleReader: s.replica, svc: s}
+		}
 	}
+	return s
 }
 
 func (s *Service) CreateSale(userID string, amount float64) (*Sale, error) {
+	return s.createSale(userID, amount, "", nil, Origin{})
+}
+
+// CreateSaleWithMetadata creates a sale carrying free-form metadata.
+func (s *Service) CreateSaleWithMetadata(userID string, amount float64, metadata map[string]string) (*Sale, error) {
+	return s.createSale(userID, amount, "", metadata, Origin{})
+}
+
+// CreateSaleWithOrigin creates a sale carrying free-form metadata and passes
+// the request origin to the enrichers.
+func (s *Service) CreateSaleWithOrigin(userID string, amount float64, metadata map[string]string, origin Origin) (*Sale, error) {
+	return s.createSale(userID, amount, "", metadata, origin)
+}
+
+// createSale valida y persiste una venta, vinculándola opcionalmente a una
+// definición recurrente.
+func (s *Service) createSale(userID string, amount float64, recurringSaleID string, metadata map[string]string, origin Origin) (*Sale, error) {
 	if amount <= 0 {
 		return nil, fmt.Errorf("amount must be greater than zero")
 	}
+	if err := validateMetadata(metadata); err != nil {
+		return nil, err
+	}
+
+	provisional := false
+	if err := s.verifyUser(userID); err != nil {
+		// En modo degradado la venta queda provisoria hasta validar al usuario
+		if !s.provisionalSales || err.Error() != "error validating user" {
+			return nil, err
+		}
+		provisional = true
+	}
 
+	sale := &Sale{
+		ID:                uuid.NewString(),
+		UserID:            userID,
+		Amount:            amount,
+		Status:            s.randomStatus(),
+		FulfillmentStatus: FulfillmentPending,
+		RecurringSaleID:   recurringSaleID,
+		TenantID:          origin.TenantID,
+		Metadata:          metadata,
+		CreatedAt:         utcNow(),
+		UpdatedAt:         utcNow(),
+		Version:           1,
+	}
+	if provisional {
+		sale.Status = StatusPendingVerification
+	}
+	if sale.Status == StatusPending {
+		since := sale.CreatedAt
+		sale.PendingSince = &since
+	}
+	if err := s.enrich(sale, origin); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveAudited(AuditActionCreated, nil, sale, Actor{}); err != nil {
+		s.logger.Error("failed to save sale", zap.String("sale_id", sale.ID), zap.Error(err))
+		return nil, fmt.Errorf("failed to save sale: %w", err)
+	}
+	s.publishCreated(sale)
+	s.recordSummary(nil, sale)
+	s.autoAssign(sale)
+
+	s.notify(EventSaleCreated, sale)
+	s.logger.Info("sale created", zap.String("sale_id", sale.ID), zap.Any("sale", sale))
+	return sale, nil
+}
+
+// GetUser returns a user from the user service, through the user cache.
+func (s *Service) GetUser(userID string) (*User, error) {
+	return s.userClient.GetUserByID(userID)
+}
+
+// verifyUser comprueba contra el servicio de usuarios que el usuario exista.
+func (s *Service) verifyUser(userID string) error {
 	user, err := s.userClient.GetUserByID(userID)
 	if err != nil {
 		s.logger.Error("error al validar usuario con el servicio externo", zap.String("user_id", userID), zap.Error(err))
 		if strings.Contains(err.Error(), "usuario no encontrado") {
-			return nil, fmt.Errorf("user not found")
+			return fmt.Errorf("user not found")
 		}
 
-		return nil, fmt.Errorf("error validating user")
+		return fmt.Errorf("error validating user")
 	}
 
 	fmt.Printf("Usuario %s encontrado y validado: %v\n", userID, user)
+	return nil
+}
+
+// CreateDraftSale stores a sale in draft status. Drafts skip amount and user
+// validation until they are submitted.
+func (s *Service) CreateDraftSale(userID string, amount float64) (*Sale, error) {
+	return s.CreateDraftSaleWithMetadata(userID, amount, nil)
+}
+
+// CreateDraftSaleWithMetadata creates a draft carrying free-form metadata.
+func (s *Service) CreateDraftSaleWithMetadata(userID string, amount float64, metadata map[string]string) (*Sale, error) {
+	return s.CreateDraftSaleWithOrigin(userID, amount, metadata, Origin{})
+}
+
+// CreateDraftSaleWithOrigin creates a draft carrying free-form metadata and
+// passes the request origin to the enrichers.
+func (s *Service) CreateDraftSaleWithOrigin(userID string, amount float64, metadata map[string]string, origin Origin) (*Sale, error) {
+	if amount < 0 {
+		return nil, fmt.Errorf("amount must not be negative")
+	}
+	if err := validateMetadata(metadata); err != nil {
+		return nil, err
+	}
 
 	sale := &Sale{
 		ID:        uuid.NewString(),
 		UserID:    userID,
 		Amount:    amount,
-		Status:    getRandomStatus(),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		Status:    StatusDraft,
+		TenantID:  origin.TenantID,
+		Metadata:  metadata,
+		CreatedAt: utcNow(),
+		UpdatedAt: utcNow(),
 		Version:   1,
 	}
+	if err := s.enrich(sale, origin); err != nil {
+		return nil, err
+	}
 
-	if err := s.storage.Set(sale); err != nil {
-		s.logger.Error("failed to save sale", zap.String("sale_id", sale.ID), zap.Error(err))
+	if err := s.saveAudited(AuditActionCreated, nil, sale, Actor{}); err != nil {
+		s.logger.Error("failed to save draft sale", zap.String("sale_id", sale.ID), zap.Error(err))
 		return nil, fmt.Errorf("failed to save sale: %w", err)
 	}
+	s.publishCreated(sale)
+	s.recordSummary(nil, sale)
+	s.autoAssign(sale)
 
-	s.logger.Info("sale created", zap.String("sale_id", sale.ID), zap.Any("sale", sale))
+	s.notify(EventSaleCreated, sale)
+	s.logger.Info("draft sale created", zap.String("sale_id", sale.ID), zap.Any("sale", sale))
 	return sale, nil
 }
 
-func (s *Service) SearchSale(userID, status string) ([]*Sale, SalesMetadata, error) {
+// SubmitSale runs the full creation validation on a draft and moves it into the
+// normal workflow.
+func (s *Service) SubmitSale(saleID string) (*Sale, error) {
+	sale, err := s.storage.Read(saleID)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	if sale.Status != StatusDraft {
+		return nil, ErrNotDraft
+	}
+
+	if err := s.checkPeriodOpen(sale); err != nil {
+		return nil, err
+	}
+
+	if sale.Amount <= 0 {
+		return nil, fmt.Errorf("amount must be greater than zero")
+	}
+
+	if err := s.verifyUser(sale.UserID); err != nil {
+		return nil, err
+	}
+
+	before := sale.clone()
+	sale.Status = s.randomStatus()
+	sale.FulfillmentStatus = FulfillmentPending
+	sale.UpdatedAt = utcNow()
+	sale.Version++
+	if sale.Status == StatusPending {
+		since := sale.UpdatedAt
+		sale.PendingSince = &since
+	}
+
+	if err := s.saveAudited(AuditActionSubmitted, before, sale, Actor{}); err != nil {
+		s.logger.Error("failed to submit sale", zap.String("sale_id", sale.ID), zap.Error(err))
+		return nil, err
+	}
+	s.publishStatusChanged(sale, StatusDraft)
+	s.autoAssign(sale)
+
+	s.notify(EventSaleStatusChanged, sale)
+	s.logger.Info("draft sale submitted", zap.String("sale_id", sale.ID), zap.String("status", sale.Status))
+	return sale, nil
+}
+
+func (s *Service) SearchSale(filter SearchFilter) (results []*Sale, metadata SalesMetadata, err error) {
+	userID, status := filter.UserID, filter.Status
+	trace := newSearchTrace(filter.shapeFields())
+	defer func() { s.observeSearch(trace, len(results), err) }()
 
 	//0. Validar que el usuario existe llamando a la API de usuarios
 	if userID != "" {
@@ -137,101 +545,204 @@ func (s *Service) SearchSale(userID, status string) ([]*Sale, SalesMetadata, err
 		if userExists == nil {
 			return nil, SalesMetadata{}, fmt.Errorf("usuario no encontrado: %s", userID)
 		}
+		trace.phase("user_validation")
 	}
 
 	// 1. Validar el status
 	var parsedStatus string
 	if status != "" {
-		switch status {
-		case "pending":
-			parsedStatus = status
-		case "rejected":
-			parsedStatus = status
-		case "approved":
-			parsedStatus = status
-		default:
+		if !s.knownStatus(status) {
 			s.logger.Warn("Invalid status filter provided", zap.String("statusFilter", status))
 			return nil, SalesMetadata{}, fmt.Errorf("invalid status value")
 		}
+		parsedStatus = status
 	}
 
-	// 2. Obtener todas las ventas del storage
-	allSales, err := s.storage.GetAll()
+	// 2. Obtener las ventas del storage (réplica salvo lectura fuerte); las
+	// búsquedas del canary las filtran en el storage
+	start := time.Now()
+	canary := s.searchCanaried(filter)
+	var allSales []*Sale
+	if canary {
+		allSales, err = findSales(s.reader(filter.Consistency), SaleQuery{UserID: userID, Status: parsedStatus})
+		if err != nil {
+			s.logger.Warn("canary search failed, falling back to the service filter", zap.Error(err))
+			searchCanaryComparisons.WithLabelValues("error").Inc()
+			canary = false
+		}
+	}
+	if !canary {
+		allSales, err = s.searchStorage(filter)
+	}
 	if err != nil {
 		s.logger.Error("Failed to get all sales from storage", zap.Error(err))
 		return nil, SalesMetadata{}, fmt.Errorf("failed to retrieve sales: %w", err)
 	}
+	trace.phase("storage")
 
 	// 3. Filtrar y calcular metadatos
+	filteredSales, metadata := s.filterSearch(allSales, filter)
+	trace.phase("filter")
+	if canary {
+		s.compareSearch(filter, filteredSales, time.Since(start))
+	}
+
+	s.logger.Info("Sales search completed",
+		zap.String("userID_filter", userID),
+		zap.String("status_filter", status),
+		zap.Int("results_count", len(filteredSales)),
+		zap.Any("metadata", metadata),
+	)
 
+	return filteredSales, metadata, nil
+
+}
+
+// searchStorage lee las ventas entre las que busca filter: solo las del
+// usuario o del estado si el storage las indexa, o su estado en AsOf según el
+// historial de versiones.
+func (s *Service) searchStorage(filter SearchFilter) ([]*Sale, error) {
+	switch {
+	case filter.AsOf != nil:
+		return s.salesAsOf(*filter.AsOf)
+	case filter.UserID != "":
+		return salesOfUser(s.reader(filter.Consistency), filter.UserID)
+	case filter.Status != "":
+		return salesWithStatus(s.reader(filter.Consistency), filter.Status)
+	default:
+		return s.reader(filter.Consistency).GetAll()
+	}
+}
+
+// filterSearch aplica filter sobre all y calcula los metadatos del resultado.
+func (s *Service) filterSearch(all []*Sale, filter SearchFilter) ([]*Sale, SalesMetadata) {
+	var metadata SalesMetadata
 	filteredSales := make([]*Sale, 0)
-	metadata := SalesMetadata{}
+	visible := s.visibleUsers(filter.Caller)
+	slaAt := utcNow()
+	if filter.AsOf != nil {
+		slaAt = *filter.AsOf
+	}
 
-	for _, sale := range allSales {
+	for _, sale := range all {
 		// Filtrar por UserID
-		if userID != "" && sale.UserID != userID {
+		if filter.UserID != "" && sale.UserID != filter.UserID {
 			continue
 		}
 
+		// Control de acceso por fila
+		if visible != nil {
+			if _, ok := visible[sale.UserID]; !ok {
+				continue
+			}
+		}
+
 		// Filtrar por Status
-		if status != "" && sale.Status != string(parsedStatus) {
+		if filter.Status != "" && sale.Status != filter.Status {
 			continue
 		}
-
-		filteredSales = append(filteredSales, sale)
-		metadata.Quantity++
-		metadata.TotalAmount += sale.Amount
-		switch sale.Status {
-		case "approved":
-			metadata.Approved++
-		case "rejected":
-			metadata.Rejected++
-		case "pending":
-			metadata.Pending++
+		if filter.FulfillmentStatus != "" && sale.fulfillmentStatus() != filter.FulfillmentStatus {
+			continue
 		}
-	}
-
-	s.logger.Info("Sales search completed",
-		zap.String("userID_filter", userID),
-		zap.String("status_filter", status),
-		zap.Int("results_count", len(filteredSales)),
-		zap.Any("metadata", metadata),
-	)
 
-	return filteredSales, metadata, nil
+		// Filtrar por fecha de creación
+		if !filter.matchesCreatedAt(sale.CreatedAt) {
+			continue
+		}
+		if filter.ExceededSLA > 0 && !sale.exceededSLA(filter.ExceededSLA, slaAt) {
+			continue
+		}
 
+		filteredSales = append(filteredSales, sale)
+		metadata.add(sale)
+	}
+	return filteredSales, metadata
 }
 
 // Modificar el estado de una venta
 func (s *Service) UpdateSaleStatus(saleID, newStatus string) (*Sale, error) {
+	return s.UpdateSaleStatusAs(saleID, newStatus, Actor{})
+}
+
+// UpdateSaleStatusAs is UpdateSaleStatus recording actor in the audit entry.
+func (s *Service) UpdateSaleStatusAs(saleID, newStatus string, actor Actor) (*Sale, error) {
 	sale, err := s.storage.Read(saleID)
 	if err != nil {
 		return nil, ErrNotFound
 	}
 
-	if newStatus != "approved" && newStatus != "rejected" {
-		return nil, ErrInvalidStatus
-
+	if newStatus == StatusApproved || newStatus == StatusRejected {
+		if sale.Status != StatusPending {
+			return nil, ErrInvalidTransition
+		}
+	} else {
+		// Los demás estados solo existen en el vocabulario del tenant de la venta
+		custom, ok, err := s.customStatus(sale.TenantID, newStatus)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read status vocabulary: %w", err)
+		}
+		if !ok {
+			return nil, ErrInvalidStatus
+		}
+		if !slices.Contains(custom.From, sale.Status) {
+			return nil, ErrInvalidTransition
+		}
 	}
 
-	if sale.Status != "pending" {
-		return nil, ErrInvalidTransition
+	if err := s.checkPeriodOpen(sale); err != nil {
+		return nil, err
 	}
 
+	before := sale.clone()
+	from := sale.Status
 	sale.Status = newStatus
-	sale.UpdatedAt = time.Now()
+	sale.UpdatedAt = utcNow()
 	sale.Version++
+	if newStatus == StatusApproved || newStatus == StatusRejected {
+		recordDecision(sale, sale.UpdatedAt)
+	}
 
-	if err := s.storage.Set(sale); err != nil {
+	if err := s.saveAudited(AuditActionStatusChanged, before, sale, actor); err != nil {
 		s.logger.Error("failed to update sale", zap.String("sale_id", sale.ID), zap.Error(err))
 		return nil, err
 	}
+	s.publishStatusChanged(sale, from)
+	if sale.Status != StatusPending {
+		s.releaseClaim(sale.ID)
+	}
+	s.notify(EventSaleStatusChanged, sale)
 
 	return sale, nil
 }
 
-func getRandomStatus() string {
-	statuses := []string{"pending", "approved", "rejected"}
-	randomIndex := rand.Intn(len(statuses))
-	return statuses[randomIndex]
+// SaleState is the part of a sale a client needs to resolve a conflicting
+// transition without reading the whole sale again.
+type SaleState struct {
+	Status            string `json:"current_status"`
+	FulfillmentStatus string `json:"current_fulfillment_status"`
+	Version           int    `json:"version"`
+}
+
+// CurrentState returns the status and version of a sale.
+func (s *Service) CurrentState(saleID string) (SaleState, error) {
+	sale, err := s.storage.Read(saleID)
+	if err != nil {
+		return SaleState{}, ErrNotFound
+	}
+	return SaleState{Status: sale.Status, FulfillmentStatus: sale.fulfillmentStatus(), Version: sale.Version}, nil
+}
+
+// RepeatedStatusChange reports whether asking to move a sale to newStatus
+// repeats a change already applied, e.g. a double-clicked approve: the sale
+// is in newStatus and was last updated within window (zero means any time).
+// It returns the current sale in that case.
+func (s *Service) RepeatedStatusChange(saleID, newStatus string, window time.Duration) (*Sale, bool) {
+	sale, err := s.storage.Read(saleID)
+	if err != nil || sale.Status != newStatus || !s.knownStatus(newStatus) {
+		return nil, false
+	}
+	if window > 0 && time.Since(sale.UpdatedAt) > window {
+		return nil, false
+	}
+	return sale, true
 }
@@ -1,11 +1,17 @@
 package sales
 
 import (
+	"api_sales/internal/sales/events"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 // TestNewService verifica la inicialización del servicio.
@@ -57,3 +63,568 @@ func TestCreateSale_UserNotFound(t *testing.T) {
 		t.Errorf("Expected error containing '%s', got '%s'", expectedErr, err.Error())
 	}
 }
+
+// TestDraftSale_SubmitFlow prueba edición y envío de un borrador.
+func TestDraftSale_SubmitFlow(t *testing.T) {
+	mockUserServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "user123", "name": "Test User 123"}`))
+	}))
+	defer mockUserServer.Close()
+
+	svc := NewService(NewLocalStorage(), zaptest.NewLogger(t), mockUserServer.URL)
+
+	draft, err := svc.CreateDraftSale("", 0)
+	if err != nil {
+		t.Fatalf("CreateDraftSale returned error: %v", err)
+	}
+	if draft.Status != StatusDraft {
+		t.Fatalf("expected status %q, got %q", StatusDraft, draft.Status)
+	}
+
+	// Un borrador sin monto no puede enviarse.
+	if _, err := svc.SubmitSale(draft.ID); err == nil || err.Error() != "amount must be greater than zero" {
+		t.Fatalf("expected amount validation error, got %v", err)
+	}
+
+	userID, amount := "user123", 42.5
+	if _, err := svc.EditSale(draft.ID, SaleEdit{UserID: &userID, Amount: &amount}); err != nil {
+		t.Fatalf("EditSale returned error: %v", err)
+	}
+
+	submitted, err := svc.SubmitSale(draft.ID)
+	if err != nil {
+		t.Fatalf("SubmitSale returned error: %v", err)
+	}
+	if submitted.Status == StatusDraft {
+		t.Error("expected submitted sale to leave draft status")
+	}
+	if submitted.Version != 3 {
+		t.Errorf("expected version 3, got %d", submitted.Version)
+	}
+
+	if _, err := svc.SubmitSale(draft.ID); err != ErrNotDraft {
+		t.Errorf("expected ErrNotDraft submitting twice, got %v", err)
+	}
+}
+
+// TestEditSale_AmendPendingSale prueba la modificación de una venta pendiente.
+func TestEditSale_AmendPendingSale(t *testing.T) {
+	storage := NewLocalStorage()
+	svc := NewService(storage, zaptest.NewLogger(t), "http://localhost:8080/users")
+
+	sale := &Sale{ID: "sale-1", UserID: "user123", Amount: 100, Status: StatusPending, Version: 1}
+	if err := storage.Set(sale); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+
+	items := []LineItem{
+		{SKU: "A", Quantity: 2, UnitPrice: 50},
+		{SKU: "B", Quantity: 1, UnitPrice: 100},
+	}
+	discount, tax := 10.0, 21.0
+	amended, err := svc.EditSale(sale.ID, SaleEdit{LineItems: &items, DiscountPercent: &discount, TaxPercent: &tax})
+	if err != nil {
+		t.Fatalf("EditSale returned error: %v", err)
+	}
+
+	if amended.Subtotal != 200 || amended.Discount != 20 || amended.Tax != 37.8 || amended.Amount != 217.8 {
+		t.Errorf("unexpected pricing: subtotal=%v discount=%v tax=%v amount=%v",
+			amended.Subtotal, amended.Discount, amended.Tax, amended.Amount)
+	}
+	if amended.Version != 2 {
+		t.Errorf("expected version 2, got %d", amended.Version)
+	}
+
+	entries, err := svc.GetSaleAudit(sale.ID, nil)
+	if err != nil {
+		t.Fatalf("GetSaleAudit returned error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Action != AuditActionAmended {
+		t.Fatalf("expected one %q audit entry, got %+v", AuditActionAmended, entries)
+	}
+	if entries[0].Before.Amount != 100 || entries[0].After.Amount != 217.8 {
+		t.Errorf("unexpected audit snapshots: before=%v after=%v", entries[0].Before.Amount, entries[0].After.Amount)
+	}
+
+	userID := "other"
+	if _, err := svc.EditSale(sale.ID, SaleEdit{UserID: &userID}); err != ErrFieldNotEditable {
+		t.Errorf("expected ErrFieldNotEditable, got %v", err)
+	}
+
+	if _, err := svc.UpdateSaleStatus(sale.ID, StatusApproved); err != nil {
+		t.Fatalf("UpdateSaleStatus returned error: %v", err)
+	}
+	if _, err := svc.EditSale(sale.ID, SaleEdit{LineItems: &items}); err != ErrNotEditable {
+		t.Errorf("expected ErrNotEditable after approval, got %v", err)
+	}
+}
+
+// TestGetUserByID_CoalescesConcurrentLookups verifica que las consultas concurrentes comparten una petición.
+func TestGetUserByID_CoalescesConcurrentLookups(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	mockUserServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "user123", "name": "Test User 123"}`))
+	}))
+	defer mockUserServer.Close()
+
+	uc := NewUserClient(mockUserServer.URL)
+
+	const callers = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := uc.GetUserByID("user123")
+			errs <- err
+		}()
+	}
+
+	// Espera a que la primera petición llegue antes de liberarla.
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("GetUserByID returned error: %v", err)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected a single upstream call, got %d", calls.Load())
+	}
+}
+
+// TestSearchSale_LogsSlowQueries verifica el log de búsquedas lentas con su desglose.
+func TestSearchSale_LogsSlowQueries(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	svc := NewService(NewLocalStorage(), zap.New(core), "http://localhost:8080/users", WithSlowQueryThreshold(time.Nanosecond))
+
+	if _, _, err := svc.SearchSale(SearchFilter{Status: StatusApproved}); err != nil {
+		t.Fatalf("SearchSale returned error: %v", err)
+	}
+
+	entries := logs.FilterMessage("slow sales search").All()
+	if len(entries) != 1 {
+		t.Fatalf("expected one slow query log, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["filter_shape"] != "status" {
+		t.Errorf("expected filter_shape %q, got %v", "status", fields["filter_shape"])
+	}
+	for _, phase := range []string{"storage", "filter"} {
+		if _, ok := fields[phase]; !ok {
+			t.Errorf("expected %q timing in slow query log", phase)
+		}
+	}
+}
+
+// TestSearchSale_StrongConsistencyReadsPrimary verifica que una lectura fuerte
+// ignora la réplica aunque todavía no haya recibido la venta.
+func TestSearchSale_StrongConsistencyReadsPrimary(t *testing.T) {
+	primary := NewLocalStorage()
+	primary.Set(&Sale{ID: "s1", UserID: "u1", Status: StatusPending, Amount: 10, CreatedAt: time.Now()})
+	svc := NewService(primary, zaptest.NewLogger(t), "http://localhost:8080/users", WithReadReplica(NewLocalStorage()))
+
+	eventual, _, err := svc.SearchSale(SearchFilter{Status: StatusPending})
+	if err != nil {
+		t.Fatalf("SearchSale returned error: %v", err)
+	}
+	if len(eventual) != 0 {
+		t.Errorf("expected the lagging replica to return no sales, got %d", len(eventual))
+	}
+
+	strong, _, err := svc.SearchSale(SearchFilter{Status: StatusPending, Consistency: ConsistencyStrong})
+	if err != nil {
+		t.Fatalf("SearchSale returned error: %v", err)
+	}
+	if len(strong) != 1 {
+		t.Errorf("expected 1 sale from the primary, got %d", len(strong))
+	}
+}
+
+// TestUserSummary_UpdatedOnWrite verifica que el resumen por usuario sigue las
+// altas y ediciones sin recorrer el storage.
+func TestUserSummary_UpdatedOnWrite(t *testing.T) {
+	svc := NewService(NewLocalStorage(), zaptest.NewLogger(t), "http://localhost:8080/users")
+
+	first, err := svc.CreateDraftSale("user123", 40)
+	if err != nil {
+		t.Fatalf("CreateDraftSale returned error: %v", err)
+	}
+	if _, err := svc.CreateDraftSale("user123", 60); err != nil {
+		t.Fatalf("CreateDraftSale returned error: %v", err)
+	}
+
+	summary, err := svc.GetUserSummary("user123")
+	if err != nil {
+		t.Fatalf("GetUserSummary returned error: %v", err)
+	}
+	if summary.Count != 2 || summary.TotalAmount != 100 || summary.LastSaleAt == nil {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+
+	other := "user456"
+	if _, err := svc.EditSale(first.ID, SaleEdit{UserID: &other}); err != nil {
+		t.Fatalf("EditSale returned error: %v", err)
+	}
+
+	summary, _ = svc.GetUserSummary("user123")
+	if summary.Count != 1 || summary.TotalAmount != 60 {
+		t.Errorf("expected the moved draft to leave user123, got %+v", summary)
+	}
+	moved, _ := svc.GetUserSummary(other)
+	if moved.Count != 1 || moved.TotalAmount != 40 {
+		t.Errorf("expected the moved draft in %s, got %+v", other, moved)
+	}
+}
+
+// TestClosePeriod_BlocksChanges verifica que las ventas de un periodo cerrado no se modifican.
+func TestClosePeriod_BlocksChanges(t *testing.T) {
+	storage := NewLocalStorage()
+	svc := NewService(storage, zaptest.NewLogger(t), "http://localhost:8080/users")
+
+	may := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+	storage.Set(&Sale{ID: "may", UserID: "user123", Amount: 100, Status: StatusPending, CreatedAt: may, Version: 1})
+	storage.Set(&Sale{ID: "june", UserID: "user123", Amount: 100, Status: StatusPending, CreatedAt: may.AddDate(0, 1, 0), Version: 1})
+
+	if _, err := svc.ClosePeriod("2024/05", "admin"); err != ErrInvalidPeriod {
+		t.Errorf("expected ErrInvalidPeriod, got %v", err)
+	}
+	if _, err := svc.ClosePeriod("2024-05", "admin"); err != nil {
+		t.Fatalf("ClosePeriod returned error: %v", err)
+	}
+
+	amount := 50.0
+	if _, err := svc.EditSale("may", SaleEdit{Amount: &amount}); err != ErrPeriodClosed {
+		t.Errorf("expected ErrPeriodClosed editing, got %v", err)
+	}
+	if _, err := svc.UpdateSaleStatus("may", StatusApproved); err != ErrPeriodClosed {
+		t.Errorf("expected ErrPeriodClosed approving, got %v", err)
+	}
+	if _, err := svc.UpdateSaleStatus("june", StatusApproved); err != nil {
+		t.Errorf("expected sales in open periods to be modifiable, got %v", err)
+	}
+}
+
+// TestDomainEvents_StatusChangeAndRefund verifica los eventos tipados que
+// publica el servicio.
+func TestDomainEvents_StatusChangeAndRefund(t *testing.T) {
+	storage := NewLocalStorage()
+	storage.Set(&Sale{ID: "sale-1", UserID: "user123", Amount: 100, Status: StatusPending, Version: 1})
+	dispatcher := events.NewDispatcher(zaptest.NewLogger(t))
+	var changes []events.SaleStatusChanged
+	var refunds []events.SaleRefunded
+	events.On(dispatcher, func(e events.SaleStatusChanged) { changes = append(changes, e) })
+	events.On(dispatcher, func(e events.SaleRefunded) { refunds = append(refunds, e) })
+	svc := NewService(storage, zaptest.NewLogger(t), "http://localhost:8080/users", WithEventDispatcher(dispatcher))
+
+	if _, err := svc.UpdateSaleStatus("sale-1", StatusApproved); err != nil {
+		t.Fatalf("UpdateSaleStatus returned error: %v", err)
+	}
+	if _, err := svc.AdjustSale("sale-1", 5, "shipping", "op-1"); err != nil {
+		t.Fatalf("AdjustSale returned error: %v", err)
+	}
+	if _, err := svc.AdjustSale("sale-1", -30, "partial refund", "op-1"); err != nil {
+		t.Fatalf("AdjustSale returned error: %v", err)
+	}
+
+	if len(changes) != 1 || changes[0].From != StatusPending || changes[0].To != StatusApproved || changes[0].Version != 2 {
+		t.Errorf("expected one pending to approved change, got %+v", changes)
+	}
+	if len(refunds) != 1 || refunds[0].Amount != 30 || refunds[0].RefundedBy != "op-1" {
+		t.Errorf("expected only the negative adjustment published as a refund of 30, got %+v", refunds)
+	}
+}
+
+// TestAdjustSale_ClosedPeriod verifica que los ajustes corrigen ventas cerradas sin modificarlas.
+func TestAdjustSale_ClosedPeriod(t *testing.T) {
+	storage := NewLocalStorage()
+	svc := NewService(storage, zaptest.NewLogger(t), "http://localhost:8080/users")
+
+	createdAt := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+	storage.Set(&Sale{ID: "sale-1", UserID: "user123", Amount: 100, Status: StatusApproved, CreatedAt: createdAt, Version: 1})
+	storage.Set(&Sale{ID: "sale-2", UserID: "user123", Amount: 100, Status: StatusRejected, CreatedAt: createdAt, Version: 1})
+	if _, err := svc.ClosePeriod("2024-05", "admin"); err != nil {
+		t.Fatalf("ClosePeriod returned error: %v", err)
+	}
+
+	if _, err := svc.AdjustSale("sale-1", -30, "partial refund", "op-1"); err != nil {
+		t.Fatalf("AdjustSale returned error: %v", err)
+	}
+	if _, err := svc.AdjustSale("sale-1", -80, "too much", "op-1"); err == nil {
+		t.Error("expected an error when the net amount would be negative")
+	}
+	if _, err := svc.AdjustSale("sale-2", 10, "rejected", "op-1"); err != ErrNotAdjustable {
+		t.Errorf("expected ErrNotAdjustable, got %v", err)
+	}
+
+	sale, _ := storage.Read("sale-1")
+	if sale.Amount != 100 || sale.Version != 1 {
+		t.Errorf("expected the sale to remain untouched, got amount=%v version=%d", sale.Amount, sale.Version)
+	}
+	stats, err := svc.GetStats()
+	if err != nil {
+		t.Fatalf("GetStats returned error: %v", err)
+	}
+	if stats.Adjustments != -30 {
+		t.Errorf("expected -30 in adjustments, got %v", stats.Adjustments)
+	}
+}
+
+// TestLedger_BalancedEntries verifica que cada asiento del libro balancea.
+func TestLedger_BalancedEntries(t *testing.T) {
+	storage := NewLocalStorage()
+	svc := NewService(storage, zaptest.NewLogger(t), "http://localhost:8080/users")
+
+	now := time.Now()
+	storage.Set(&Sale{ID: "taxed", UserID: "u1", Status: StatusApproved, Subtotal: 100, TaxPercent: 21, Tax: 21, Amount: 121, CreatedAt: now})
+	storage.Set(&Sale{ID: "plain", UserID: "u1", Status: StatusApproved, Amount: 50, CreatedAt: now})
+	storage.Set(&Sale{ID: "pending", UserID: "u1", Status: StatusPending, Amount: 70, CreatedAt: now})
+	if _, err := svc.AdjustSale("plain", -20, "discount", "op-1"); err != nil {
+		t.Fatalf("AdjustSale returned error: %v", err)
+	}
+
+	lines, err := svc.Ledger(nil, nil)
+	if err != nil {
+		t.Fatalf("Ledger returned error: %v", err)
+	}
+	if len(lines) != 7 {
+		t.Fatalf("expected 7 ledger lines, got %d", len(lines))
+	}
+
+	balance := make(map[string]float64)
+	for _, l := range lines {
+		if l.SaleID == "pending" {
+			t.Errorf("pending sales must not be posted")
+		}
+		balance[l.EntryID] += l.Debit - l.Credit
+	}
+	for entry, b := range balance {
+		if roundCents(b) != 0 {
+			t.Errorf("entry %s does not balance: %v", entry, b)
+		}
+	}
+}
+
+// upperEncrypter es un cifrado trivial y reversible para los tests.
+type upperEncrypter struct{}
+
+func (upperEncrypter) Encrypt(v string) (string, error) { return "enc:" + v, nil }
+func (upperEncrypter) Decrypt(v string) (string, error) { return v[len("enc:"):], nil }
+
+// TestFieldEncryption_SensitiveMetadata verifica el cifrado en storage y el enmascarado.
+func TestFieldEncryption_SensitiveMetadata(t *testing.T) {
+	storage := NewLocalStorage()
+	svc := NewService(storage, zaptest.NewLogger(t), "http://localhost:8080/users", WithFieldEncryption(upperEncrypter{}, []string{"tax_id"}))
+
+	draft, err := svc.CreateDraftSaleWithMetadata("user123", 10, map[string]string{"tax_id": "20-123", "channel": "web"})
+	if err != nil {
+		t.Fatalf("CreateDraftSaleWithMetadata returned error: %v", err)
+	}
+
+	raw, _ := storage.Read(draft.ID)
+	if raw.Metadata["tax_id"] != "enc:20-123" || raw.Metadata["channel"] != "web" {
+		t.Errorf("expected only sensitive values encrypted at rest, got %v", raw.Metadata)
+	}
+
+	results, _, err := svc.SearchSale(SearchFilter{Status: StatusDraft})
+	if err != nil || len(results) != 1 {
+		t.Fatalf("SearchSale returned %d results, err %v", len(results), err)
+	}
+	if results[0].Metadata["tax_id"] != "20-123" {
+		t.Errorf("expected decrypted value on read, got %q", results[0].Metadata["tax_id"])
+	}
+	if masked := svc.RedactSensitive(results[0]); masked.Metadata["tax_id"] != MetadataMask || results[0].Metadata["tax_id"] != "20-123" {
+		t.Errorf("expected a masked copy, got %v", masked.Metadata)
+	}
+
+	// Los snapshots de auditoría también se cifran
+	amount := 20.0
+	if _, err := svc.EditSale(draft.ID, SaleEdit{Amount: &amount}); err != nil {
+		t.Fatalf("EditSale returned error: %v", err)
+	}
+	stored, _ := svc.ExportAudit()
+	if len(stored) != 2 || stored[0].After.Metadata["tax_id"] != "enc:20-123" || stored[1].Before.Metadata["tax_id"] != "enc:20-123" || stored[1].After.Metadata["tax_id"] != "enc:20-123" {
+		t.Errorf("expected the audit snapshots encrypted at rest, got %+v", stored)
+	}
+	if result, _ := svc.VerifyAudit(); !result.Valid {
+		t.Errorf("expected a valid audit chain, got %+v", result)
+	}
+	entries, err := svc.GetSaleAudit(draft.ID, &Caller{UserID: "user123", Role: "seller"})
+	if err != nil || len(entries) != 2 || entries[1].After.Metadata["tax_id"] != "20-123" {
+		t.Errorf("expected the decrypted audit trail, got %+v, err %v", entries, err)
+	}
+	if _, err := svc.GetSaleAudit(draft.ID, &Caller{UserID: "other", Role: "seller"}); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound for a caller who can't see the sale, got %v", err)
+	}
+}
+
+// TestSearchSale_RowLevelAccess verifica que cada caller solo ve sus ventas y las de su equipo.
+func TestSearchSale_RowLevelAccess(t *testing.T) {
+	storage := NewLocalStorage()
+	svc := NewService(storage, zaptest.NewLogger(t), "http://localhost:8080/users")
+
+	for _, owner := range []string{"alice", "bob", "carol"} {
+		storage.Set(&Sale{ID: owner, UserID: owner, Amount: 10, Status: StatusPending, CreatedAt: time.Now()})
+	}
+
+	cases := []struct {
+		name   string
+		caller *Caller
+		want   int
+	}{
+		{"anonymous", nil, 3},
+		{"own sales", &Caller{UserID: "alice", Role: "seller"}, 1},
+		{"team", &Caller{UserID: "alice", Role: "seller", Team: []string{"bob"}}, 2},
+		{"elevated", &Caller{UserID: "alice", Role: DefaultElevatedRole}, 3},
+		{"no user", &Caller{Role: "seller"}, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			results, _, err := svc.SearchSale(SearchFilter{Status: StatusPending, Caller: tc.caller})
+			if err != nil {
+				t.Fatalf("SearchSale returned error: %v", err)
+			}
+			if len(results) != tc.want {
+				t.Errorf("expected %d results, got %d", tc.want, len(results))
+			}
+		})
+	}
+}
+
+// TestShareLinks_RowLevelAccess verifica que solo se comparten las ventas que el caller puede ver.
+func TestShareLinks_RowLevelAccess(t *testing.T) {
+	storage := NewLocalStorage()
+	svc := NewService(storage, zaptest.NewLogger(t), "http://localhost:8080/users")
+	storage.Set(&Sale{ID: "s1", UserID: "alice", Amount: 10, Status: StatusApproved, CreatedAt: time.Now()})
+
+	bob := &Caller{UserID: "bob", Role: "seller"}
+	if _, err := svc.CreateShareLink("s1", time.Hour, Actor{ID: "bob"}, bob); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound sharing another user's sale, got %v", err)
+	}
+	alice := &Caller{UserID: "alice", Role: "seller"}
+	if _, err := svc.CreateShareLink("s1", time.Hour, Actor{ID: "alice"}, alice); err != nil {
+		t.Fatalf("CreateShareLink returned error: %v", err)
+	}
+	if _, err := svc.GetSaleShareLinks("s1", bob); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound listing another user's links, got %v", err)
+	}
+	if links, err := svc.GetSaleShareLinks("s1", alice); err != nil || len(links) != 1 {
+		t.Errorf("expected the owner's link, got %v (err=%v)", links, err)
+	}
+}
+
+// TestAuditChain_DetectsTampering verifica el encadenado de hashes de auditoría.
+func TestAuditChain_DetectsTampering(t *testing.T) {
+	svc := NewService(NewLocalStorage(), zaptest.NewLogger(t), "http://localhost:8080/users")
+
+	draft, err := svc.CreateDraftSale("user123", 10)
+	if err != nil {
+		t.Fatalf("CreateDraftSale returned error: %v", err)
+	}
+	for _, amount := range []float64{20, 30, 40} {
+		amount := amount
+		if _, err := svc.EditSale(draft.ID, SaleEdit{Amount: &amount}); err != nil {
+			t.Fatalf("EditSale returned error: %v", err)
+		}
+	}
+
+	result, err := svc.VerifyAudit()
+	if err != nil {
+		t.Fatalf("VerifyAudit returned error: %v", err)
+	}
+	// La creación y las tres ediciones
+	if !result.Valid || result.Entries != 4 {
+		t.Fatalf("expected a valid chain of 4 entries, got %+v", result)
+	}
+
+	entries, _ := svc.ExportAudit()
+	entries[1].After.Amount = 999
+	if result := VerifyAuditChain(entries); result.Valid || result.BrokenAt != entries[1].ID {
+		t.Errorf("expected tampering detected at %s, got %+v", entries[1].ID, result)
+	}
+}
+
+// TestEditSaleAs_RecordsBothIdentities verifica que la auditoría guarde al
+// admin y al usuario suplantado.
+func TestEditSaleAs_RecordsBothIdentities(t *testing.T) {
+	svc := NewService(NewLocalStorage(), zaptest.NewLogger(t), "http://localhost:8080/users")
+
+	draft, err := svc.CreateDraftSale("user123", 10)
+	if err != nil {
+		t.Fatalf("CreateDraftSale returned error: %v", err)
+	}
+	amount := 25.0
+	if _, err := svc.EditSaleAs(draft.ID, SaleEdit{Amount: &amount}, Actor{ID: "admin1", OnBehalfOf: "user123"}); err != nil {
+		t.Fatalf("EditSaleAs returned error: %v", err)
+	}
+
+	entries, err := svc.ExportAudit()
+	if err != nil {
+		t.Fatalf("ExportAudit returned error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Action != AuditActionCreated {
+		t.Fatalf("expected the creation and the edit audited, got %+v", entries)
+	}
+	if entries[1].Actor != "admin1" || entries[1].OnBehalfOf != "user123" {
+		t.Errorf("expected actor admin1 on behalf of user123, got %q / %q", entries[1].Actor, entries[1].OnBehalfOf)
+	}
+}
+
+// TestUpdateSaleStatusAs_AuditsTheDecision verifica que las aprobaciones
+// queden en la cadena de auditoría con ambas identidades.
+func TestUpdateSaleStatusAs_AuditsTheDecision(t *testing.T) {
+	storage := NewLocalStorage()
+	svc := NewService(storage, zaptest.NewLogger(t), "http://localhost:8080/users")
+	storage.Set(&Sale{ID: "s1", UserID: "user123", Amount: 10, Status: StatusPending, Version: 1, CreatedAt: time.Now()})
+
+	if _, err := svc.UpdateSaleStatusAs("s1", StatusApproved, Actor{ID: "admin1", OnBehalfOf: "user123"}); err != nil {
+		t.Fatalf("UpdateSaleStatusAs returned error: %v", err)
+	}
+
+	entries, err := svc.GetSaleAudit("s1", nil)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected 1 audit entry, got %d, err %v", len(entries), err)
+	}
+	e := entries[0]
+	if e.Action != AuditActionStatusChanged || e.Before.Status != StatusPending || e.After.Status != StatusApproved {
+		t.Errorf("unexpected status entry %+v", e)
+	}
+	if e.Actor != "admin1" || e.OnBehalfOf != "user123" {
+		t.Errorf("expected actor admin1 on behalf of user123, got %q / %q", e.Actor, e.OnBehalfOf)
+	}
+	if result, _ := svc.VerifyAudit(); !result.Valid || result.Entries != 1 {
+		t.Errorf("expected the decision in the hash chain, got %+v", result)
+	}
+}
+
+// TestRandomStatus_SeededIsReproducible verifica que la misma semilla asigne
+// los mismos estados.
+func TestRandomStatus_SeededIsReproducible(t *testing.T) {
+	statuses := func() []string {
+		svc := NewService(NewLocalStorage(), zaptest.NewLogger(t), "http://localhost:8080/users", WithRandom(NewSeededRandom(7)))
+		got := make([]string, 20)
+		for i := range got {
+			got[i] = svc.randomStatus()
+		}
+		return got
+	}
+
+	first, second := statuses(), statuses()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("expected the same statuses for the same seed, got %v and %v", first, second)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package sales
+
+import (
+	"api_sales/internal/dispatch"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var shadowComparisons = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "sales_shadow_storage_comparisons_total",
+	Help: "Reads compared between the primary and the candidate storage by result.",
+}, []string{"op", "result"})
+
+var shadowWriteErrors = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "sales_shadow_storage_write_errors_total",
+	Help: "Writes that succeeded on the primary storage but failed on the candidate.",
+})
+
+// ShadowStorage validates a candidate backend under live traffic. Writes go
+// to the primary and then to the candidate; reads are served by the primary
+// and compared against the candidate on pool, logging every mismatch. The
+// candidate never fails or slows down a request beyond its write.
+type ShadowStorage struct {
+	primary   Storage
+	candidate Storage
+	pool      *dispatch.Pool
+	logger    *zap.Logger
+}
+
+func NewShadowStorage(primary, candidate Storage, pool *dispatch.Pool, logger *zap.Logger) *ShadowStorage {
+	return &ShadowStorage{
+		primary:   primary,
+		candidate: candidate,
+		pool:      pool,
+		logger:    logger,
+	}
+}
+
+func (s *ShadowStorage) Set(sale *Sale) error {
+	if err := s.primary.Set(sale); err != nil {
+		return err
+	}
+	// Copia propia: el storage local guarda punteros
+	if err := s.candidate.Set(sale.clone()); err != nil {
+		shadowWriteErrors.Inc()
+		s.logger.Warn("shadow storage write failed", zap.String("sale_id", sale.ID), zap.Error(err))
+	}
+	return nil
+}
+
+func (s *ShadowStorage) Read(id string) (*Sale, error) {
+	sale, err := s.primary.Read(id)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return sale, err
+	}
+
+	// Se serializa ahora: la venta puede mutar antes de la comparación
+	var want []byte
+	if sale != nil {
+		want, _ = json.Marshal(sale)
+	}
+	s.compare("read", func() {
+		got, cerr := s.candidate.Read(id)
+		switch {
+		case cerr != nil && !errors.Is(cerr, ErrNotFound):
+			s.result("read", "error", zap.String("sale_id", id), zap.Error(cerr))
+		case (got == nil) != (want == nil):
+			s.result("read", "mismatch", zap.String("sale_id", id), zap.Bool("primary_found", want != nil), zap.Bool("candidate_found", got != nil))
+		case got != nil:
+			if b, _ := json.Marshal(got); !bytes.Equal(b, want) {
+				s.result("read", "mismatch", zap.String("sale_id", id), zap.ByteString("primary", want), zap.ByteString("candidate", b))
+				return
+			}
+			s.result("read", "match")
+		default:
+			s.result("read", "match")
+		}
+	})
+	return sale, err
+}
+
+// GetAll compara solo IDs y versiones para no serializar la tabla completa
+// en cada búsqueda.
+func (s *ShadowStorage) GetAll() ([]*Sale, error) {
+	all, err := s.primary.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	want := make(map[string]int, len(all))
+	for _, sale := range all {
+		want[sale.ID] = sale.Version
+	}
+	s.compare("get_all", func() {
+		got, cerr := s.candidate.GetAll()
+		if cerr != nil {
+			s.result("get_all", "error", zap.Error(cerr))
+			return
+		}
+		var missing, stale []string
+		for _, sale := range got {
+			if v, ok := want[sale.ID]; ok && v != sale.Version {
+				stale = append(stale, sale.ID)
+			}
+		}
+		seen := make(map[string]bool, len(got))
+		for _, sale := range got {
+			seen[sale.ID] = true
+		}
+		for id := range want {
+			if !seen[id] {
+				missing = append(missing, id)
+			}
+		}
+		if len(missing) > 0 || len(stale) > 0 || len(got) != len(want) {
+			s.result("get_all", "mismatch",
+				zap.Int("primary_count", len(want)),
+				zap.Int("candidate_count", len(got)),
+				zap.Strings("missing", missing),
+				zap.Strings("stale", stale),
+			)
+			return
+		}
+		s.result("get_all", "match")
+	})
+	return all, nil
+}
+
+// WithTx corre fn en una transacción del primario; el candidato recibe las
+// escrituras a medida que ocurren, igual que fuera de la transacción.
+func (s *ShadowStorage) WithTx(ctx context.Context, fn func(tx Storage) error) error {
+	return s.primary.WithTx(ctx, func(tx Storage) error {
+		return fn(NewShadowStorage(tx, s.candidate, s.pool, s.logger))
+	})
+}
+
+func (s *ShadowStorage) unwrap() Storage { return s.primary }
+
+func (s *ShadowStorage) compare(op string, fn func()) {
+	if err := s.pool.Submit(func(context.Context) { fn() }); err != nil {
+		shadowComparisons.WithLabelValues(op, "dropped").Inc()
+	}
+}
+
+func (s *ShadowStorage) result(op, result string, fields ...zap.Field) {
+	shadowComparisons.WithLabelValues(op, result).Inc()
+	if result != "match" {
+		s.logger.Warn("shadow storage "+result, append(fields, zap.String("op", op))...)
+	}
+}
@@ -0,0 +1,46 @@
+package sales
+
+import (
+	"api_sales/internal/dispatch"
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestShadowStorage_DualWritesAndLogsMismatches verifica la doble escritura y
+// la comparación asíncrona de lecturas contra el backend candidato.
+func TestShadowStorage_DualWritesAndLogsMismatches(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	pool := dispatch.NewPool("test-shadow", 1, 10, nil)
+	primary, candidate := NewLocalStorage(), NewLocalStorage()
+	shadow := NewShadowStorage(primary, candidate, pool, zap.New(core))
+
+	if err := shadow.Set(&Sale{ID: "s1", UserID: "user123", Amount: 10, Status: StatusPending, Version: 1}); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+	if _, err := candidate.Read("s1"); err != nil {
+		t.Fatalf("expected the sale in the candidate storage, got %v", err)
+	}
+
+	// El candidato diverge: la lectura sale del primario y se loguea la diferencia
+	drifted, _ := candidate.Read("s1")
+	drifted.Amount = 99
+	candidate.Set(drifted)
+	sale, err := shadow.Read("s1")
+	if err != nil || sale.Amount != 10 {
+		t.Fatalf("expected the primary sale, got %+v err=%v", sale, err)
+	}
+	if _, err := shadow.GetAll(); err != nil {
+		t.Fatalf("GetAll returned error: %v", err)
+	}
+	if err := pool.Close(context.Background()); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	mismatches := logs.FilterMessage("shadow storage mismatch").All()
+	if len(mismatches) != 1 || mismatches[0].ContextMap()["op"] != "read" {
+		t.Errorf("expected one read mismatch, got %v", mismatches)
+	}
+}
@@ -0,0 +1,220 @@
+package sales
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Error para links compartidos inexistentes
+var ErrShareLinkNotFound = errors.New("share link not found")
+
+// Error para links compartidos vencidos
+var ErrShareLinkExpired = errors.New("share link expired")
+
+// Error para links compartidos revocados
+var ErrShareLinkRevoked = errors.New("share link revoked")
+
+// ShareLink grants read-only access to the receipt of a sale until ExpiresAt
+// or until it is revoked.
+type ShareLink struct {
+	ID        string     `json:"id"`
+	SaleID    string     `json:"sale_id"`
+	CreatedBy string     `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedBy string     `json:"revoked_by,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// ShareLinkStorage persists share links.
+type ShareLinkStorage interface {
+	Set(link *ShareLink) error
+	Read(id string) (*ShareLink, error)
+	GetAll() ([]*ShareLink, error)
+}
+
+type LocalShareLinkStorage struct {
+	mu sync.RWMutex
+	m  map[string]*ShareLink
+}
+
+func NewLocalShareLinkStorage() *LocalShareLinkStorage {
+	return &LocalShareLinkStorage{
+		m: make(map[string]*ShareLink),
+	}
+}
+
+func (l *LocalShareLinkStorage) Set(link *ShareLink) error {
+	if link.ID == "" {
+		return ErrEmptyID
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	c := *link
+	l.m[link.ID] = &c
+	return nil
+}
+
+func (l *LocalShareLinkStorage) Read(id string) (*ShareLink, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	link, ok := l.m[id]
+	if !ok {
+		return nil, ErrShareLinkNotFound
+	}
+	c := *link
+	return &c, nil
+}
+
+// GetAll retorna los links en orden de creación.
+func (l *LocalShareLinkStorage) GetAll() ([]*ShareLink, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	result := make([]*ShareLink, 0, len(l.m))
+	for _, link := range l.m {
+		c := *link
+		result = append(result, &c)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result, nil
+}
+
+// WithShareLinkStorage sets where share links are kept. Defaults to an
+// in-memory LocalShareLinkStorage.
+func WithShareLinkStorage(links ShareLinkStorage) Option {
+	return func(s *Service) {
+		s.shareLinks = links
+	}
+}
+
+// Receipt is the view of a sale behind a share link: what the customer paid
+// and where the purchase is, without the buyer, tenant or metadata.
+type Receipt struct {
+	ID                string     `json:"id"`
+	Amount            float64    `json:"amount"`
+	Status            string     `json:"status"`
+	LineItems         []LineItem `json:"line_items,omitempty"`
+	Subtotal          float64    `json:"subtotal,omitempty"`
+	Discount          float64    `json:"discount,omitempty"`
+	Tax               float64    `json:"tax,omitempty"`
+	FulfillmentStatus string     `json:"fulfillment_status"`
+	Carrier           string     `json:"carrier,omitempty"`
+	TrackingNumber    string     `json:"tracking_number,omitempty"`
+	ShippedAt         *time.Time `json:"shipped_at,omitempty"`
+	DeliveredAt       *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// CreateShareLink grants access to the receipt of a sale for ttl. Drafts
+// and sales the caller may not see can't be shared.
+func (s *Service) CreateShareLink(saleID string, ttl time.Duration, actor Actor, caller *Caller) (*ShareLink, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("share link ttl must be positive")
+	}
+	sale, err := s.storage.Read(saleID)
+	if err != nil || sale.Status == StatusDraft || !s.canSee(caller, sale) {
+		return nil, ErrNotFound
+	}
+
+	now := utcNow()
+	link := &ShareLink{
+		ID:        uuid.NewString(),
+		SaleID:    sale.ID,
+		CreatedBy: actor.ID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+	if err := s.shareLinks.Set(link); err != nil {
+		s.logger.Error("failed to save share link", zap.String("sale_id", sale.ID), zap.Error(err))
+		return nil, fmt.Errorf("failed to save share link: %w", err)
+	}
+	s.logger.Info("share link created", zap.String("sale_id", sale.ID), zap.String("link_id", link.ID), zap.Time("expires_at", link.ExpiresAt))
+	return link, nil
+}
+
+// RevokeShareLink stops a link from granting access before it expires.
+// Revoking it again keeps the original revocation.
+func (s *Service) RevokeShareLink(linkID string, actor Actor) (*ShareLink, error) {
+	link, err := s.shareLinks.Read(linkID)
+	if err != nil {
+		return nil, err
+	}
+	if link.RevokedAt != nil {
+		return link, nil
+	}
+
+	now := utcNow()
+	link.RevokedBy = actor.ID
+	link.RevokedAt = &now
+	if err := s.shareLinks.Set(link); err != nil {
+		s.logger.Error("failed to save share link", zap.String("link_id", link.ID), zap.Error(err))
+		return nil, fmt.Errorf("failed to save share link: %w", err)
+	}
+	s.logger.Info("share link revoked", zap.String("sale_id", link.SaleID), zap.String("link_id", link.ID))
+	return link, nil
+}
+
+// GetSaleShareLinks returns the links of a sale in the order they were
+// created, if caller may see the sale.
+func (s *Service) GetSaleShareLinks(saleID string, caller *Caller) ([]*ShareLink, error) {
+	sale, err := s.storage.Read(saleID)
+	if err != nil || !s.canSee(caller, sale) {
+		return nil, ErrNotFound
+	}
+	all, err := s.shareLinks.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve share links: %w", err)
+	}
+	result := make([]*ShareLink, 0)
+	for _, link := range all {
+		if link.SaleID == saleID {
+			result = append(result, link)
+		}
+	}
+	return result, nil
+}
+
+// SharedReceipt returns the receipt behind a link that is neither expired nor
+// revoked at now.
+func (s *Service) SharedReceipt(linkID string, now time.Time) (*Receipt, error) {
+	link, err := s.shareLinks.Read(linkID)
+	if err != nil {
+		return nil, err
+	}
+	if link.RevokedAt != nil {
+		return nil, ErrShareLinkRevoked
+	}
+	if !now.Before(link.ExpiresAt) {
+		return nil, ErrShareLinkExpired
+	}
+	sale, err := s.storage.Read(link.SaleID)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	return &Receipt{
+		ID:                sale.ID,
+		Amount:            sale.Amount,
+		Status:            sale.Status,
+		LineItems:         sale.LineItems,
+		Subtotal:          sale.Subtotal,
+		Discount:          sale.Discount,
+		Tax:               sale.Tax,
+		FulfillmentStatus: sale.fulfillmentStatus(),
+		Carrier:           sale.Carrier,
+		TrackingNumber:    sale.TrackingNumber,
+		ShippedAt:         sale.ShippedAt,
+		DeliveredAt:       sale.DeliveredAt,
+		CreatedAt:         sale.CreatedAt,
+		UpdatedAt:         sale.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,166 @@
+package sales
+
+import (
+	"api_sales/internal/calendar"
+	"api_sales/internal/lock"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var slaBreaches = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "sales_pending_sla_breaches_total",
+	Help: "Sales that stayed pending longer than the SLA, counted once per sale.",
+})
+
+// WithPendingSLA measures how long sales sit in pending in the business hours
+// of cal; a sale pending for longer than target breaches the SLA.
+func WithPendingSLA(cal *calendar.Calendar, target time.Duration) Option {
+	return func(s *Service) {
+		s.slaCalendar = cal
+		s.slaTarget = target
+	}
+}
+
+// PendingAge returns the business time a pending sale has been waiting at now
+// and whether it breached the SLA. ok is false for sales that are not pending
+// or when no SLA is configured.
+func (s *Service) PendingAge(sale *Sale, now time.Time) (age time.Duration, breached, ok bool) {
+	if s.slaCalendar == nil || sale.Status != StatusPending {
+		return 0, false, false
+	}
+	age = s.slaCalendar.Between(sale.pendingSince(), now)
+	return age, age > s.slaTarget, true
+}
+
+// WithSLAStatus returns sales with PendingAge and SLABreached filled in for
+// the pending ones, as of now. The sales passed in are not modified.
+func (s *Service) WithSLAStatus(sales []*Sale, now time.Time) []*Sale {
+	if s.slaCalendar == nil {
+		return sales
+	}
+	result := make([]*Sale, len(sales))
+	for i, sale := range sales {
+		result[i] = sale
+		if age, breached, ok := s.PendingAge(sale, now); ok {
+			annotated := sale.clone()
+			annotated.PendingAge = age.String()
+			annotated.SLABreached = breached
+			result[i] = annotated
+		}
+	}
+	return result
+}
+
+// CheckPendingSLA emits EventSaleSLABreached for each pending sale that
+// breached the SLA since the last check and returns how many it found. Each
+// sale is alerted once while it stays pending.
+func (s *Service) CheckPendingSLA(now time.Time) (int, error) {
+	if s.slaCalendar == nil {
+		return 0, nil
+	}
+	allSales, err := s.storage.GetAll()
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve sales: %w", err)
+	}
+
+	s.slaMu.Lock()
+	defer s.slaMu.Unlock()
+
+	pending := make(map[string]struct{})
+	breaches := 0
+	for _, sale := range allSales {
+		age, breached, ok := s.PendingAge(sale, now)
+		if !ok {
+			continue
+		}
+		pending[sale.ID] = struct{}{}
+		if _, alerted := s.slaAlerted[sale.ID]; !breached || alerted {
+			continue
+		}
+
+		s.slaAlerted[sale.ID] = struct{}{}
+		breaches++
+		slaBreaches.Inc()
+		annotated := sale.clone()
+		annotated.PendingAge = age.String()
+		annotated.SLABreached = true
+		s.logger.Warn("pending sale breached the SLA", zap.String("sale_id", sale.ID), zap.Duration("pending_age", age))
+		s.notify(EventSaleSLABreached, annotated)
+	}
+	// Las que dejaron pending ya no necesitan recordarse
+	for id := range s.slaAlerted {
+		if _, ok := pending[id]; !ok {
+			delete(s.slaAlerted, id)
+		}
+	}
+	return breaches, nil
+}
+
+// slaMonitorLockKey is the distributed lock held during an SLA check.
+const slaMonitorLockKey = "sla-monitor:pending-sales"
+
+// SLAMonitor periodically alerts on pending sales that breached the SLA.
+type SLAMonitor struct {
+	service  *Service
+	interval time.Duration
+	locker   lock.Locker
+	logger   *zap.Logger
+}
+
+// NewSLAMonitor creates a monitor that checks every interval. Like the
+// Scheduler, a non-nil locker makes only one replica run each check.
+func NewSLAMonitor(service *Service, interval time.Duration, locker lock.Locker, logger *zap.Logger) *SLAMonitor {
+	if logger == nil {
+		logger, _ = zap.NewProduction()
+	}
+
+	return &SLAMonitor{
+		service:  service,
+		interval: interval,
+		locker:   locker,
+		logger:   logger,
+	}
+}
+
+// Start blocks running the checks until ctx is cancelled.
+func (m *SLAMonitor) Start(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.runCheck(ctx, now)
+		}
+	}
+}
+
+func (m *SLAMonitor) runCheck(ctx context.Context, now time.Time) {
+	check := func(context.Context) error {
+		_, err := m.service.CheckPendingSLA(now)
+		return err
+	}
+
+	if m.locker == nil {
+		if err := check(ctx); err != nil {
+			m.logger.Error("SLA check failed", zap.Error(err))
+		}
+		return
+	}
+
+	err := lock.Run(ctx, m.locker, slaMonitorLockKey, m.interval, check)
+	switch {
+	case errors.Is(err, lock.ErrNotAcquired):
+		m.logger.Debug("SLA check skipped, lock held by another instance")
+	case err != nil:
+		m.logger.Error("SLA check failed", zap.Error(err))
+	}
+}
@@ -0,0 +1,59 @@
+package sales
+
+import (
+	"api_sales/internal/calendar"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+type recordingNotifier struct {
+	events []string
+}
+
+func (r *recordingNotifier) Notify(eventType string, sale *Sale) {
+	r.events = append(r.events, eventType+":"+sale.ID)
+}
+
+// TestPendingSLA_MeasuresBusinessHoursAndAlertsOnce verifica la antigüedad en
+// horario hábil y que cada incumplimiento se alerte una sola vez.
+func TestPendingSLA_MeasuresBusinessHoursAndAlertsOnce(t *testing.T) {
+	cal, err := calendar.Parse("09:00-18:00", []string{"mon", "tue", "wed", "thu", "fri"}, "UTC", nil)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	notifier := &recordingNotifier{}
+	storage := NewLocalStorage()
+	friday := time.Date(2024, 5, 3, 17, 0, 0, 0, time.UTC)
+	storage.Set(&Sale{ID: "late", Status: StatusPending, CreatedAt: friday, PendingSince: &friday})
+	storage.Set(&Sale{ID: "done", Status: StatusApproved, CreatedAt: friday})
+	svc := NewService(storage, zaptest.NewLogger(t), "http://unused", WithPendingSLA(cal, 90*time.Minute), WithNotifier(notifier))
+
+	monday := friday.Add(65 * time.Hour)
+	all, _ := storage.GetAll()
+	for _, sale := range svc.WithSLAStatus(all, monday) {
+		switch sale.ID {
+		case "late":
+			if sale.PendingAge != "2h0m0s" || !sale.SLABreached {
+				t.Errorf("expected 2h of business time and a breach, got %q breached=%v", sale.PendingAge, sale.SLABreached)
+			}
+		case "done":
+			if sale.PendingAge != "" || sale.SLABreached {
+				t.Errorf("expected no SLA status on approved sales, got %+v", sale)
+			}
+		}
+	}
+	if stored, _ := storage.Read("late"); stored.PendingAge != "" {
+		t.Error("expected the stored sale to stay without SLA status")
+	}
+
+	for range 2 {
+		if _, err := svc.CheckPendingSLA(monday); err != nil {
+			t.Fatalf("CheckPendingSLA returned error: %v", err)
+		}
+	}
+	if len(notifier.events) != 1 || notifier.events[0] != EventSaleSLABreached+":late" {
+		t.Errorf("expected a single breach alert, got %v", notifier.events)
+	}
+}
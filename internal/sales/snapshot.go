@@ -0,0 +1,193 @@
+package sales
+
+import (
+	"api_sales/internal/blob"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Error para periodos sin snapshot de cierre
+var ErrSnapshotNotFound = errors.New("period snapshot not found")
+
+// Error para exports que no coinciden con el checksum del snapshot
+var ErrSnapshotCorrupted = errors.New("period snapshot export does not match its checksum")
+
+// PeriodSnapshot is the immutable record of a closed accounting period: the
+// totals at close time and a checksummed export of every sale created in it.
+type PeriodSnapshot struct {
+	Period    string         `json:"period"`
+	CreatedAt time.Time      `json:"created_at"`
+	Totals    SalesMetadata  `json:"totals"`
+	Export    SnapshotExport `json:"export"`
+}
+
+// SnapshotExport describes the NDJSON export stored with a snapshot.
+type SnapshotExport struct {
+	Key     string `json:"key"`
+	SHA256  string `json:"sha256"`
+	Size    int    `json:"size"`
+	Records int    `json:"records"`
+}
+
+// WithSnapshotStore sets where period snapshots are stored. Defaults to an
+// in-memory blob.MemoryStore.
+func WithSnapshotStore(store blob.Store) Option {
+	return func(s *Service) {
+		s.snapshots = store
+	}
+}
+
+func snapshotManifestKey(period string) string {
+	return "snapshots/" + period + "/manifest.json"
+}
+
+func snapshotExportKey(period string) string {
+	return "snapshots/" + period + "/sales.ndjson"
+}
+
+// snapshotPeriod guarda el snapshot de un periodo cerrado. Es idempotente: si
+// ya existe se retorna el guardado, y un export de un intento anterior que no
+// llegó a escribir el manifest se reutiliza tal cual.
+func (s *Service) snapshotPeriod(ctx context.Context, period string) (*PeriodSnapshot, error) {
+	if existing, err := s.GetPeriodSnapshot(ctx, period); err == nil {
+		return existing, nil
+	} else if err != ErrSnapshotNotFound {
+		return nil, err
+	}
+
+	export, snapshot, err := s.buildSnapshot(period)
+	if err != nil {
+		return nil, err
+	}
+	err = s.snapshots.Put(ctx, snapshot.Export.Key, export)
+	if err == blob.ErrExists {
+		if export, err = s.snapshots.Get(ctx, snapshot.Export.Key); err != nil {
+			return nil, fmt.Errorf("failed to read snapshot export: %w", err)
+		}
+		snapshot.Export.SHA256, snapshot.Export.Size = checksum(export), len(export)
+		snapshot.Export.Records = bytes.Count(export, []byte("\n"))
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to store snapshot export: %w", err)
+	}
+
+	manifest, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot manifest: %w", err)
+	}
+	if err := s.snapshots.Put(ctx, snapshotManifestKey(period), manifest); err == blob.ErrExists {
+		// Otra instancia lo escribió primero
+		return s.GetPeriodSnapshot(ctx, period)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to store snapshot manifest: %w", err)
+	}
+
+	s.logger.Info("period snapshot stored", zap.String("period", period), zap.Int("records", snapshot.Export.Records), zap.String("sha256", snapshot.Export.SHA256))
+	return snapshot, nil
+}
+
+// buildSnapshot arma el export NDJSON de las ventas creadas en el periodo,
+// ordenadas por creación, y sus totales con los ajustes del periodo. La
+// metadata sensible se enmascara como en las respuestas a no administradores.
+func (s *Service) buildSnapshot(period string) ([]byte, *PeriodSnapshot, error) {
+	allSales, err := s.storage.GetAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve sales: %w", err)
+	}
+	adjustments, err := s.adjustments.GetAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve adjustments: %w", err)
+	}
+
+	inPeriod := make([]*Sale, 0)
+	for _, sale := range allSales {
+		if PeriodOf(sale.CreatedAt) == period {
+			inPeriod = append(inPeriod, sale)
+		}
+	}
+	sort.Slice(inPeriod, func(i, j int) bool {
+		if !inPeriod[i].CreatedAt.Equal(inPeriod[j].CreatedAt) {
+			return inPeriod[i].CreatedAt.Before(inPeriod[j].CreatedAt)
+		}
+		return inPeriod[i].ID < inPeriod[j].ID
+	})
+
+	// encoding/json y no jsonenc, para que el checksum no dependa del build tag
+	var export bytes.Buffer
+	enc := json.NewEncoder(&export)
+	totals := SalesMetadata{}
+	for _, sale := range inPeriod {
+		totals.add(sale)
+		if err := enc.Encode(s.RedactSensitive(sale)); err != nil {
+			return nil, nil, fmt.Errorf("failed to encode sale %s: %w", sale.ID, err)
+		}
+	}
+	for _, a := range adjustments {
+		if PeriodOf(a.CreatedAt) == period {
+			totals.Adjustments += a.Amount
+		}
+	}
+	totals.Adjustments = roundCents(totals.Adjustments)
+
+	return export.Bytes(), &PeriodSnapshot{
+		Period:    period,
+		CreatedAt: utcNow(),
+		Totals:    totals,
+		Export: SnapshotExport{
+			Key:     snapshotExportKey(period),
+			SHA256:  checksum(export.Bytes()),
+			Size:    export.Len(),
+			Records: len(inPeriod),
+		},
+	}, nil
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// GetPeriodSnapshot returns the snapshot stored when period was closed.
+func (s *Service) GetPeriodSnapshot(ctx context.Context, period string) (*PeriodSnapshot, error) {
+	if _, err := time.Parse(periodLayout, period); err != nil {
+		return nil, ErrInvalidPeriod
+	}
+	data, err := s.snapshots.Get(ctx, snapshotManifestKey(period))
+	if err == blob.ErrNotFound {
+		return nil, ErrSnapshotNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot manifest: %w", err)
+	}
+	var snapshot PeriodSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot manifest: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// GetPeriodSnapshotExport returns the export of a period snapshot after
+// checking it against the checksum recorded in the snapshot.
+func (s *Service) GetPeriodSnapshotExport(ctx context.Context, period string) ([]byte, *PeriodSnapshot, error) {
+	snapshot, err := s.GetPeriodSnapshot(ctx, period)
+	if err != nil {
+		return nil, nil, err
+	}
+	export, err := s.snapshots.Get(ctx, snapshot.Export.Key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read snapshot export: %w", err)
+	}
+	if checksum(export) != snapshot.Export.SHA256 {
+		s.logger.Error("period snapshot export checksum mismatch", zap.String("period", period))
+		return nil, nil, ErrSnapshotCorrupted
+	}
+	return export, snapshot, nil
+}
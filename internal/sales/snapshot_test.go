@@ -0,0 +1,54 @@
+package sales
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// TestClosePeriod_StoresImmutableSnapshot verifica que el cierre guarde los
+// totales y el export del periodo, y que cambios posteriores no los alteren.
+func TestClosePeriod_StoresImmutableSnapshot(t *testing.T) {
+	ctx := context.Background()
+	storage := NewLocalStorage()
+	may := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	storage.Set(&Sale{ID: "s1", UserID: "u1", Amount: 10, Status: StatusApproved, CreatedAt: may})
+	storage.Set(&Sale{ID: "s2", UserID: "u1", Amount: 5, Status: StatusPending, CreatedAt: may.Add(time.Hour)})
+	storage.Set(&Sale{ID: "june", UserID: "u1", Amount: 99, Status: StatusApproved, CreatedAt: may.AddDate(0, 1, 0)})
+	svc := NewService(storage, zaptest.NewLogger(t), "http://unused")
+
+	if _, err := svc.GetPeriodSnapshot(ctx, "2024-05"); err != ErrSnapshotNotFound {
+		t.Fatalf("expected ErrSnapshotNotFound before closing, got %v", err)
+	}
+	if _, err := svc.ClosePeriod("2024-05", "admin1"); err != nil {
+		t.Fatalf("ClosePeriod returned error: %v", err)
+	}
+
+	snapshot, err := svc.GetPeriodSnapshot(ctx, "2024-05")
+	if err != nil {
+		t.Fatalf("GetPeriodSnapshot returned error: %v", err)
+	}
+	if snapshot.Totals.Quantity != 2 || snapshot.Totals.TotalAmount != 15 || snapshot.Export.Records != 2 {
+		t.Errorf("expected the two May sales in the snapshot, got %+v", snapshot)
+	}
+	export, _, err := svc.GetPeriodSnapshotExport(ctx, "2024-05")
+	if err != nil {
+		t.Fatalf("GetPeriodSnapshotExport returned error: %v", err)
+	}
+	if lines := bytes.Split(bytes.TrimSpace(export), []byte("\n")); len(lines) != 2 || !bytes.Contains(lines[0], []byte(`"id":"s1"`)) {
+		t.Errorf("expected one NDJSON line per sale in creation order, got %s", export)
+	}
+
+	// Una venta agregada por fuera no cambia el snapshot al volver a cerrar
+	storage.Set(&Sale{ID: "late", UserID: "u1", Amount: 50, Status: StatusApproved, CreatedAt: may})
+	if _, err := svc.ClosePeriod("2024-05", "admin1"); err != nil {
+		t.Fatalf("ClosePeriod returned error: %v", err)
+	}
+	again, err := svc.GetPeriodSnapshot(ctx, "2024-05")
+	if err != nil || again.Export.SHA256 != snapshot.Export.SHA256 || again.Totals.TotalAmount != 15 {
+		t.Errorf("expected the snapshot to stay unchanged, got %+v, %v", again, err)
+	}
+}
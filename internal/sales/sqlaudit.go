@@ -0,0 +1,224 @@
+package sales
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// sqlAuditQueries son las sentencias de un dialecto sobre las tablas de la
+// migración 0003: las entradas y la fila única con la cabeza de la cadena.
+type sqlAuditQueries struct {
+	dialect string
+	insert  string
+	bySale  string
+	all     string
+	head    string
+	// lockHead bloquea la cabeza hasta el fin de la transacción y la lee
+	lockHead []string
+	setHead  string
+}
+
+func mysqlAuditQueries(table string) sqlAuditQueries {
+	entries, head := "`"+table+"_audit`", "`"+table+"_audit_head`"
+	return sqlAuditQueries{
+		dialect:  "mysql",
+		insert:   "INSERT INTO " + entries + " (id, sale_id, data) VALUES (?, ?, ?)",
+		bySale:   "SELECT data FROM " + entries + " WHERE sale_id = ? ORDER BY seq",
+		all:      "SELECT data FROM " + entries + " ORDER BY seq",
+		head:     "SELECT hash FROM " + head + " WHERE id = 1",
+		lockHead: []string{"SELECT hash FROM " + head + " WHERE id = 1 FOR UPDATE"},
+		setHead:  "INSERT INTO " + head + " (id, hash) VALUES (1, ?) ON DUPLICATE KEY UPDATE hash = VALUES(hash)",
+	}
+}
+
+var sqliteAuditQueries = sqlAuditQueries{
+	dialect: "sqlite",
+	insert:  "INSERT INTO sales_audit (id, sale_id, data) VALUES (?, ?, ?)",
+	bySale:  "SELECT data FROM sales_audit WHERE sale_id = ? ORDER BY seq",
+	all:     "SELECT data FROM sales_audit ORDER BY seq",
+	head:    "SELECT hash FROM sales_audit_head WHERE id = 1",
+	// SQLite no tiene FOR UPDATE: la escritura toma el lock de escritor de la
+	// base antes de leer, así otra transacción no lee la misma cabeza
+	lockHead: []string{
+		"UPDATE sales_audit_head SET hash = hash WHERE id = 1",
+		"SELECT hash FROM sales_audit_head WHERE id = 1",
+	},
+	setHead: "INSERT INTO sales_audit_head (id, hash) VALUES (1, ?) ON CONFLICT (id) DO UPDATE SET hash = excluded.hash",
+}
+
+// sqlAuditStorage guarda la auditoría en la base de un storage SQL. Con q en
+// una transacción, Head bloquea la cabeza y Append escribe en esa transacción.
+type sqlAuditStorage struct {
+	db      *sql.DB
+	q       sqlQuerier
+	queries sqlAuditQueries
+	timeout time.Duration
+}
+
+// AuditStorage returns the audit log kept in the same database, in the
+// <table>_audit tables.
+func (m *MySQLStorage) AuditStorage() TxAuditStorage {
+	return &sqlAuditStorage{db: m.db, q: m.db, queries: mysqlAuditQueries(m.table), timeout: m.timeout}
+}
+
+// AuditStorage returns the audit log kept in the same database, in the
+// sales_audit tables.
+func (s *SQLiteStorage) AuditStorage() TxAuditStorage {
+	return &sqlAuditStorage{db: s.db, q: s.db, queries: sqliteAuditQueries, timeout: s.timeout}
+}
+
+func (a *sqlAuditStorage) InTx(tx Storage) AuditStorage {
+	q, ok := sqlTxOf(tx, a.db)
+	if !ok {
+		return nil
+	}
+	return &sqlAuditStorage{db: a.db, q: q, queries: a.queries, timeout: a.timeout}
+}
+
+// Append fuera de una transacción abre la suya, para comparar la cabeza y
+// moverla junto con el insert.
+func (a *sqlAuditStorage) Append(entry *AuditEntry) error {
+	if entry.ID == "" {
+		return ErrEmptyID
+	}
+	if a.q == a.db {
+		return a.withTx(func(tx *sqlAuditStorage) error { return tx.Append(entry) })
+	}
+
+	head, err := a.Head()
+	if err != nil {
+		return err
+	}
+	if entry.PrevHash != head {
+		return ErrAuditChainMoved
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	defer cancel()
+	if _, err := a.q.ExecContext(ctx, a.queries.insert, entry.ID, entry.SaleID, string(data)); err != nil {
+		return fmt.Errorf("%s append audit entry: %w", a.queries.dialect, err)
+	}
+	if _, err := a.q.ExecContext(ctx, a.queries.setHead, entry.Hash); err != nil {
+		return fmt.Errorf("%s update audit head: %w", a.queries.dialect, err)
+	}
+	return nil
+}
+
+// Head dentro de una transacción bloquea la cabeza hasta que termine.
+func (a *sqlAuditStorage) Head() (string, error) {
+	statements := []string{a.queries.head}
+	if a.q != a.db {
+		statements = a.queries.lockHead
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	defer cancel()
+	for _, stmt := range statements[:len(statements)-1] {
+		if _, err := a.q.ExecContext(ctx, stmt); err != nil {
+			return "", fmt.Errorf("%s lock audit head: %w", a.queries.dialect, err)
+		}
+	}
+	var head string
+	err := a.q.QueryRowContext(ctx, statements[len(statements)-1]).Scan(&head)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s get audit head: %w", a.queries.dialect, err)
+	}
+	return head, nil
+}
+
+func (a *sqlAuditStorage) GetBySale(saleID string) ([]*AuditEntry, error) {
+	return a.list(a.queries.bySale, saleID)
+}
+
+func (a *sqlAuditStorage) GetAll() ([]*AuditEntry, error) {
+	return a.list(a.queries.all)
+}
+
+func (a *sqlAuditStorage) list(query string, args ...any) ([]*AuditEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	defer cancel()
+	rows, err := a.q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s list audit entries: %w", a.queries.dialect, err)
+	}
+	defer rows.Close()
+
+	entries := make([]*AuditEntry, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("%s list audit entries: %w", a.queries.dialect, err)
+		}
+		var entry AuditEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("%s decode audit entry: %w", a.queries.dialect, err)
+		}
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s list audit entries: %w", a.queries.dialect, err)
+	}
+	return entries, nil
+}
+
+func (a *sqlAuditStorage) withTx(fn func(tx *sqlAuditStorage) error) error {
+	sqlTx, err := a.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("%s begin transaction: %w", a.queries.dialect, err)
+	}
+	if err := fn(&sqlAuditStorage{db: a.db, q: sqlTx, queries: a.queries, timeout: a.timeout}); err != nil {
+		sqlTx.Rollback()
+		return err
+	}
+	if err := sqlTx.Commit(); err != nil {
+		return fmt.Errorf("%s commit transaction: %w", a.queries.dialect, err)
+	}
+	return nil
+}
+
+// sqlTxOf retorna la transacción SQL abierta sobre db detrás de tx,
+// atravesando los decoradores.
+func sqlTxOf(tx Storage, db *sql.DB) (sqlQuerier, bool) {
+	for {
+		switch t := tx.(type) {
+		case *MySQLStorage:
+			return t.q, t.db == db && t.q != t.db
+		case *SQLiteStorage:
+			return t.q, t.db == db && t.q != t.db
+		case storageDecorator:
+			tx = t.unwrap()
+		default:
+			return nil, false
+		}
+	}
+}
+
+// storageDecorator es un storage que envuelve a otro; unwrap retorna el
+// envuelto.
+type storageDecorator interface {
+	unwrap() Storage
+}
+
+// defaultAuditStorage usa la auditoría de la base del storage de ventas si
+// la tiene y, si no, una en memoria.
+func defaultAuditStorage(storage Storage) AuditStorage {
+	for {
+		switch s := storage.(type) {
+		case interface{ AuditStorage() TxAuditStorage }:
+			return s.AuditStorage()
+		case storageDecorator:
+			storage = s.unwrap()
+		default:
+			return NewLocalAuditStorage()
+		}
+	}
+}
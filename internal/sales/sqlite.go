@@ -0,0 +1,138 @@
+package sales
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SQLiteStorage keeps each sale as a JSON document in the sales table of a
+// SQLite file, for single-node deployments and local demos. SQLite allows a
+// single writer, so db should be limited to one open connection; timeout
+// bounds every statement, including the wait for that connection.
+type SQLiteStorage struct {
+	db      *sql.DB
+	q       sqlQuerier
+	timeout time.Duration
+}
+
+func NewSQLiteStorage(db *sql.DB, timeout time.Duration) *SQLiteStorage {
+	return &SQLiteStorage{db: db, q: db, timeout: timeout}
+}
+
+func (s *SQLiteStorage) Set(sale *Sale) error {
+	if sale.ID == "" {
+		return ErrEmptyID
+	}
+	data, err := json.Marshal(sale)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	_, err = s.q.ExecContext(ctx, "INSERT INTO sales (id, data) VALUES (?, ?) "+
+		"ON CONFLICT (id) DO UPDATE SET data = excluded.data, updated_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now')", sale.ID, string(data))
+	if err != nil {
+		return fmt.Errorf("sqlite put sale: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStorage) Read(id string) (*Sale, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	var data []byte
+	err := s.q.QueryRowContext(ctx, "SELECT data FROM sales WHERE id = ?", id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sqlite get sale: %w", err)
+	}
+	return decodeSQLiteSale(data)
+}
+
+// GetAll lee la tabla completa en orden de id.
+func (s *SQLiteStorage) GetAll() ([]*Sale, error) {
+	return s.list("SELECT data FROM sales ORDER BY id")
+}
+
+// Query filtra con json_extract sobre el documento, sin índices: evita
+// decodificar las ventas descartadas, no leerlas.
+func (s *SQLiteStorage) Query(q SaleQuery) ([]*Sale, error) {
+	where, args := q.sqlWhere(func(field string) string { return "json_extract(data, '$." + field + "')" })
+	return s.list("SELECT data FROM sales"+where+" ORDER BY id", args...)
+}
+
+// GetByTrackingNumber usa el índice sobre el número de seguimiento del
+// documento, creado por la migración 0002.
+func (s *SQLiteStorage) GetByTrackingNumber(trackingNumber string) (*Sale, error) {
+	found, err := s.list("SELECT data FROM sales WHERE json_extract(data, '$.tracking_number') = ? ORDER BY id LIMIT 1", trackingNumber)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, ErrNotFound
+	}
+	return found[0], nil
+}
+
+func (s *SQLiteStorage) list(query string, args ...any) ([]*Sale, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	rows, err := s.q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite list sales: %w", err)
+	}
+	defer rows.Close()
+
+	sales := make([]*Sale, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("sqlite list sales: %w", err)
+		}
+		sale, err := decodeSQLiteSale(data)
+		if err != nil {
+			return nil, err
+		}
+		sales = append(sales, sale)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("sqlite list sales: %w", err)
+	}
+	return sales, nil
+}
+
+// WithTx runs fn in a SQLite transaction, committed if fn returns nil and
+// rolled back otherwise. Nested calls join the outer transaction.
+func (s *SQLiteStorage) WithTx(ctx context.Context, fn func(tx Storage) error) error {
+	if s.q != s.db {
+		return fn(s)
+	}
+	sqlTx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("sqlite begin transaction: %w", err)
+	}
+	tx := &SQLiteStorage{db: s.db, q: sqlTx, timeout: s.timeout}
+	if err := fn(tx); err != nil {
+		sqlTx.Rollback()
+		return err
+	}
+	if err := sqlTx.Commit(); err != nil {
+		return fmt.Errorf("sqlite commit transaction: %w", err)
+	}
+	return nil
+}
+
+func decodeSQLiteSale(data []byte) (*Sale, error) {
+	var sale Sale
+	if err := json.Unmarshal(data, &sale); err != nil {
+		return nil, fmt.Errorf("sqlite decode sale: %w", err)
+	}
+	return &sale, nil
+}
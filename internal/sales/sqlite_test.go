@@ -0,0 +1,219 @@
+package sales
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+	_ "modernc.org/sqlite"
+)
+
+func openTestSQLite(t *testing.T, path string) *SQLiteStorage {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	storage := NewSQLiteStorage(db, time.Second)
+	migrator, err := storage.Migrator()
+	if err != nil {
+		t.Fatalf("Migrator: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return storage
+}
+
+// verifica que las ventas sobreviven a reabrir el archivo
+func TestSQLiteStoragePersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sales.db")
+	storage := openTestSQLite(t, path)
+
+	if err := storage.Set(&Sale{ID: "b", UserID: "u1", Amount: 10, Status: StatusPending}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	storage.Set(&Sale{ID: "a", UserID: "u2", Amount: 5, Status: StatusApproved})
+	storage.Set(&Sale{ID: "b", UserID: "u1", Amount: 20, Status: StatusApproved})
+	if err := storage.Set(&Sale{}); err != ErrEmptyID {
+		t.Errorf("expected ErrEmptyID, got %v", err)
+	}
+
+	reopened := openTestSQLite(t, path)
+	sale, err := reopened.Read("b")
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if sale.Amount != 20 || sale.Status != StatusApproved {
+		t.Errorf("expected the last write, got %+v", sale)
+	}
+	if _, err := reopened.Read("missing"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	all, err := reopened.GetAll()
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+	if len(all) != 2 || all[0].ID != "a" || all[1].ID != "b" {
+		t.Errorf("unexpected sales %+v", all)
+	}
+}
+
+// verifica el filtro por usuario y estado sobre el documento JSON
+func TestSQLiteStorageQuery(t *testing.T) {
+	storage := openTestSQLite(t, filepath.Join(t.TempDir(), "sales.db"))
+	storage.Set(&Sale{ID: "a", UserID: "u1", Status: StatusPending})
+	storage.Set(&Sale{ID: "b", UserID: "u1", Status: StatusApproved})
+	storage.Set(&Sale{ID: "c", UserID: "u2", Status: StatusPending})
+
+	for _, tc := range []struct {
+		query SaleQuery
+		want  []string
+	}{
+		{SaleQuery{UserID: "u1"}, []string{"a", "b"}},
+		{SaleQuery{Status: StatusPending}, []string{"a", "c"}},
+		{SaleQuery{UserID: "u1", Status: StatusPending}, []string{"a"}},
+		{SaleQuery{}, []string{"a", "b", "c"}},
+	} {
+		found, err := storage.Query(tc.query)
+		if err != nil {
+			t.Fatalf("Query(%+v): %v", tc.query, err)
+		}
+		var ids []string
+		for _, sale := range found {
+			ids = append(ids, sale.ID)
+		}
+		if !slices.Equal(ids, tc.want) {
+			t.Errorf("Query(%+v): expected %v, got %v", tc.query, tc.want, ids)
+		}
+	}
+}
+
+func TestSQLiteStorageGetByTrackingNumber(t *testing.T) {
+	storage := openTestSQLite(t, filepath.Join(t.TempDir(), "sales.db"))
+	storage.Set(&Sale{ID: "b", TrackingNumber: "T1"})
+	storage.Set(&Sale{ID: "a", TrackingNumber: "T1"})
+	storage.Set(&Sale{ID: "c"})
+
+	sale, err := storage.GetByTrackingNumber("T1")
+	if err != nil || sale.ID != "a" {
+		t.Errorf("expected the first sale by ID, got %+v (err=%v)", sale, err)
+	}
+	if _, err := storage.GetByTrackingNumber("missing"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	var id, parent, unused int
+	var detail string
+	err = storage.db.QueryRow("EXPLAIN QUERY PLAN SELECT data FROM sales WHERE json_extract(data, '$.tracking_number') = ? ORDER BY id LIMIT 1", "T1").
+		Scan(&id, &parent, &unused, &detail)
+	if err != nil {
+		t.Fatalf("EXPLAIN QUERY PLAN: %v", err)
+	}
+	if !strings.Contains(detail, "sales_tracking_number") {
+		t.Errorf("expected the lookup to use the tracking number index, got %q", detail)
+	}
+}
+
+func TestSQLiteStorageWithTx(t *testing.T) {
+	storage := openTestSQLite(t, filepath.Join(t.TempDir(), "sales.db"))
+
+	// Confirma cuando fn no falla; las llamadas anidadas usan la misma transacción
+	err := storage.WithTx(context.Background(), func(tx Storage) error {
+		return tx.WithTx(context.Background(), func(inner Storage) error {
+			return inner.Set(&Sale{ID: "s1"})
+		})
+	})
+	if err != nil {
+		t.Fatalf("WithTx: %v", err)
+	}
+	if _, err := storage.Read("s1"); err != nil {
+		t.Errorf("expected the committed sale, got %v", err)
+	}
+
+	// Revierte cuando fn falla
+	boom := errors.New("boom")
+	err = storage.WithTx(context.Background(), func(tx Storage) error {
+		tx.Set(&Sale{ID: "s2"})
+		return boom
+	})
+	if err != boom {
+		t.Errorf("expected fn's error, got %v", err)
+	}
+	if _, err := storage.Read("s2"); err != ErrNotFound {
+		t.Errorf("expected the write rolled back, got %v", err)
+	}
+}
+
+// verifica que las migraciones adopten la tabla creada antes de que existieran
+func TestSQLiteStorageMigratesExistingTables(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sales.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer db.Close()
+	db.Exec("CREATE TABLE sales (id TEXT NOT NULL PRIMARY KEY, data TEXT NOT NULL, updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')))")
+	db.Exec(`INSERT INTO sales (id, data) VALUES ('s1', '{"id":"s1","amount":10}')`)
+
+	storage := NewSQLiteStorage(db, time.Second)
+	migrator, err := storage.Migrator()
+	if err != nil {
+		t.Fatalf("Migrator: %v", err)
+	}
+	if pending, err := migrator.Pending(context.Background()); err != nil || len(pending) == 0 {
+		t.Fatalf("expected pending migrations, got %v (err=%v)", pending, err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if sale, err := storage.Read("s1"); err != nil || sale.Amount != 10 {
+		t.Errorf("expected the existing sale kept, got %+v (err=%v)", sale, err)
+	}
+	if pending, _ := migrator.Pending(context.Background()); len(pending) != 0 {
+		t.Errorf("expected no pending migrations, got %v", pending)
+	}
+}
+
+// verifica que dos instancias sobre la misma base extiendan una sola cadena
+func TestSQLiteAuditStorage_ChainsAcrossInstances(t *testing.T) {
+	storage := openTestSQLite(t, filepath.Join(t.TempDir(), "sales.db"))
+	first := NewService(storage, zaptest.NewLogger(t), "http://localhost:8080/users")
+	second := NewService(storage, zaptest.NewLogger(t), "http://localhost:8080/users")
+
+	draft, err := first.CreateDraftSale("user123", 10)
+	if err != nil {
+		t.Fatalf("CreateDraftSale: %v", err)
+	}
+	for i, svc := range []*Service{second, first, second} {
+		amount := float64(20 + i)
+		if _, err := svc.EditSale(draft.ID, SaleEdit{Amount: &amount}); err != nil {
+			t.Fatalf("EditSale: %v", err)
+		}
+	}
+
+	result, err := first.VerifyAudit()
+	if err != nil || !result.Valid || result.Entries != 4 {
+		t.Fatalf("expected a valid chain of 4 entries, got %+v (err=%v)", result, err)
+	}
+	entries, err := second.GetSaleAudit(draft.ID, nil)
+	if err != nil || len(entries) != 4 || entries[0].Action != AuditActionCreated {
+		t.Errorf("expected the whole trail from the other instance, got %+v (err=%v)", entries, err)
+	}
+
+	// Un append que no encadena con la cabeza guardada se rechaza
+	audit := storage.AuditStorage()
+	if err := audit.Append(&AuditEntry{ID: "stale", SaleID: draft.ID, PrevHash: entries[0].Hash}); err != ErrAuditChainMoved {
+		t.Errorf("expected ErrAuditChainMoved, got %v", err)
+	}
+	if head, _ := audit.Head(); head != entries[3].Hash {
+		t.Errorf("expected the head unchanged, got %q", head)
+	}
+}
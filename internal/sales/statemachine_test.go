@@ -0,0 +1,125 @@
+package sales
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"pgregory.net/rapid"
+)
+
+// legalTransitions es el workflow permitido: los estados finales no cambian.
+var legalTransitions = map[string][]string{
+	StatusDraft:    {StatusDraft, StatusPending, StatusApproved, StatusRejected},
+	StatusPending:  {StatusPending, StatusApproved, StatusRejected},
+	StatusApproved: {StatusApproved},
+	StatusRejected: {StatusRejected},
+}
+
+// saleSnapshot copia los campos observados: el storage local guarda punteros.
+type saleSnapshot struct {
+	status    string
+	version   int
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+// TestStatusMachine_Properties genera secuencias aleatorias de operaciones y
+// verifica que nunca se permita una transición ilegal y que Version y
+// UpdatedAt avancen solo con cada escritura exitosa.
+func TestStatusMachine_Properties(t *testing.T) {
+	users := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": "user123"}`))
+	}))
+	defer users.Close()
+
+	statuses := []string{StatusDraft, StatusPending, StatusApproved, StatusRejected, "", "bogus"}
+
+	rapid.Check(t, func(t *rapid.T) {
+		storage := NewLocalStorage()
+		svc := NewService(storage, zap.NewNop(), users.URL, WithRandom(NewSeededRandom(rapid.Uint64().Draw(t, "seed"))))
+		var ids []string
+
+		snapshot := func(id string) saleSnapshot {
+			sale, err := storage.Read(id)
+			if err != nil {
+				t.Fatalf("sale %s disappeared: %v", id, err)
+			}
+			return saleSnapshot{sale.Status, sale.Version, sale.CreatedAt, sale.UpdatedAt}
+		}
+		pick := func() string {
+			return rapid.SampledFrom(ids).Draw(t, "sale")
+		}
+
+		t.Repeat(map[string]func(*rapid.T){
+			"createDraft": func(t *rapid.T) {
+				draft, err := svc.CreateDraftSale("user123", rapid.Float64Range(0, 1000).Draw(t, "amount"))
+				if err != nil {
+					t.Fatalf("CreateDraftSale returned error: %v", err)
+				}
+				ids = append(ids, draft.ID)
+			},
+			"submit": func(t *rapid.T) {
+				if len(ids) == 0 {
+					t.Skip("no sales yet")
+				}
+				id := pick()
+				before := snapshot(id)
+				_, err := svc.SubmitSale(id)
+				checkWrite(t, before, snapshot(id), err)
+			},
+			"updateStatus": func(t *rapid.T) {
+				if len(ids) == 0 {
+					t.Skip("no sales yet")
+				}
+				id := pick()
+				before := snapshot(id)
+				_, err := svc.UpdateSaleStatus(id, rapid.SampledFrom(statuses).Draw(t, "status"))
+				checkWrite(t, before, snapshot(id), err)
+			},
+			"edit": func(t *rapid.T) {
+				if len(ids) == 0 {
+					t.Skip("no sales yet")
+				}
+				id := pick()
+				before := snapshot(id)
+				amount := rapid.Float64Range(-10, 1000).Draw(t, "amount")
+				_, err := svc.EditSale(id, SaleEdit{Amount: &amount})
+				after := snapshot(id)
+				checkWrite(t, before, after, err)
+				if after.status != before.status {
+					t.Fatalf("edit changed status from %s to %s", before.status, after.status)
+				}
+			},
+		})
+	})
+}
+
+// checkWrite verifica las invariantes de una operación sobre una venta.
+func checkWrite(t *rapid.T, before, after saleSnapshot, err error) {
+	legal := false
+	for _, to := range legalTransitions[before.status] {
+		legal = legal || to == after.status
+	}
+	if !legal {
+		t.Fatalf("illegal transition %s -> %s", before.status, after.status)
+	}
+
+	if err != nil {
+		if after != before {
+			t.Fatalf("failed operation (%v) modified the sale: %+v -> %+v", err, before, after)
+		}
+		return
+	}
+	if after.version != before.version+1 {
+		t.Fatalf("expected version %d after a write, got %d", before.version+1, after.version)
+	}
+	if after.updatedAt.Before(before.updatedAt) || after.updatedAt.Before(after.createdAt) {
+		t.Fatalf("UpdatedAt went backwards: %v -> %v (created %v)", before.updatedAt, after.updatedAt, after.createdAt)
+	}
+	if !after.createdAt.Equal(before.createdAt) {
+		t.Fatalf("CreatedAt changed: %v -> %v", before.createdAt, after.createdAt)
+	}
+}
@@ -0,0 +1,110 @@
+package sales
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// statsCache guarda los metadatos agregados de todas las ventas hasta la
+// próxima escritura o hasta que vence ttl. generation avanza con cada
+// invalidación, así un cálculo que empezó antes de una escritura no queda
+// guardado como válido.
+type statsCache struct {
+	mu         sync.RWMutex
+	valid      bool
+	value      SalesMetadata
+	generation uint64
+	ttl        time.Duration
+	setAt      time.Time
+}
+
+func (c *statsCache) get() (SalesMetadata, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.ttl > 0 && time.Since(c.setAt) >= c.ttl {
+		return SalesMetadata{}, false
+	}
+	return c.value, c.valid
+}
+
+// current retorna la generación contra la que se calcula un valor nuevo.
+func (c *statsCache) current() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.generation
+}
+
+// set guarda m solo si no hubo invalidaciones desde generation.
+func (c *statsCache) set(m SalesMetadata, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation != generation {
+		return
+	}
+	c.value, c.valid, c.setAt = m, true, time.Now()
+}
+
+func (c *statsCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.valid = false
+	c.generation++
+}
+
+// WithStatsTTL recomputes the cached aggregate statistics at least every ttl.
+// The cache is per process and only writes through this Service invalidate
+// it: set a ttl when several instances share the storage, so writes on the
+// others show up within ttl. Zero keeps the result until the next local write.
+func WithStatsTTL(ttl time.Duration) Option {
+	return func(s *Service) {
+		s.stats.ttl = ttl
+	}
+}
+
+// GetStats returns aggregate metadata over every stored sale, including the sum
+// of adjustments. The result is cached until the next write, or for the
+// WithStatsTTL bound.
+func (s *Service) GetStats() (SalesMetadata, error) {
+	if m, ok := s.stats.get(); ok {
+		return m, nil
+	}
+	return s.refreshStats()
+}
+
+func (s *Service) refreshStats() (SalesMetadata, error) {
+	generation := s.stats.current()
+	allSales, err := s.storage.GetAll()
+	if err != nil {
+		s.logger.Error("Failed to get all sales from storage", zap.Error(err))
+		return SalesMetadata{}, fmt.Errorf("failed to retrieve sales: %w", err)
+	}
+
+	metadata := SalesMetadata{}
+	latencies := make([]time.Duration, 0)
+	for _, sale := range allSales {
+		metadata.add(sale)
+		if latency, ok := sale.decisionLatency(); ok {
+			latencies = append(latencies, latency)
+		}
+	}
+	metadata.DecisionLatency = latencyPercentiles(latencies)
+
+	adjustments, err := s.adjustments.GetAll()
+	if err != nil {
+		s.logger.Error("Failed to get adjustments from storage", zap.Error(err))
+		return SalesMetadata{}, fmt.Errorf("failed to retrieve adjustments: %w", err)
+	}
+	for _, a := range adjustments {
+		metadata.Adjustments += a.Amount
+		if a.CreatedAt.After(metadata.lastModified) {
+			metadata.lastModified = a.CreatedAt
+		}
+	}
+	metadata.Adjustments = roundCents(metadata.Adjustments)
+
+	s.stats.set(metadata, generation)
+	return metadata, nil
+}
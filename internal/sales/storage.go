@@ -1,24 +1,184 @@
 package sales
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"sync"
+)
 
 var ErrNotFound = errors.New("sale not found")
 
 var ErrEmptyID = errors.New("empty sale ID")
 
-type Storage interface {
-	Set(sale *Sale) error
+// SaleReader serves sales, e.g. from a search index or a read replica.
+type SaleReader interface {
 	Read(id string) (*Sale, error)
 	GetAll() ([]*Sale, error)
 }
 
+// SaleWriter persists sales.
+type SaleWriter interface {
+	Set(sale *Sale) error
+	// WithTx runs fn with a storage scoped to a transaction, committed only
+	// when fn returns nil. Backends without transactions run fn on themselves.
+	WithTx(ctx context.Context, fn func(tx Storage) error) error
+}
+
+// UserSaleReader is implemented by storages that list the sales of a user
+// without reading every sale, e.g. through a secondary index.
+type UserSaleReader interface {
+	GetByUser(userID string) ([]*Sale, error)
+}
+
+// salesOfUser usa el índice por usuario del storage si lo tiene y si no
+// filtra GetAll.
+func salesOfUser(reader SaleReader, userID string) ([]*Sale, error) {
+	if indexed, ok := reader.(UserSaleReader); ok {
+		return indexed.GetByUser(userID)
+	}
+	all, err := reader.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return ownedBy(all, userID), nil
+}
+
+// ownedBy filtra las ventas de userID.
+func ownedBy(all []*Sale, userID string) []*Sale {
+	owned := make([]*Sale, 0)
+	for _, sale := range all {
+		if sale.UserID == userID {
+			owned = append(owned, sale)
+		}
+	}
+	return owned
+}
+
+// StatusSaleReader is implemented by storages that list the sales in a
+// status without reading every sale.
+type StatusSaleReader interface {
+	GetByStatus(status string) ([]*Sale, error)
+}
+
+// salesWithStatus usa el índice por estado del storage si lo tiene y si no
+// filtra GetAll.
+func salesWithStatus(reader SaleReader, status string) ([]*Sale, error) {
+	if indexed, ok := reader.(StatusSaleReader); ok {
+		return indexed.GetByStatus(status)
+	}
+	all, err := reader.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	found := make([]*Sale, 0)
+	for _, sale := range all {
+		if sale.Status == status {
+			found = append(found, sale)
+		}
+	}
+	return found, nil
+}
+
+// TrackingSaleReader is implemented by storages that find a sale by its
+// shipment tracking number without reading every sale. On other storages
+// sales are found by ID only.
+type TrackingSaleReader interface {
+	GetByTrackingNumber(trackingNumber string) (*Sale, error)
+}
+
+// saleWithTracking usa el índice por número de seguimiento del storage. Sin
+// índice retorna ErrNotFound en lugar de recorrer todas las ventas: la
+// búsqueda la disparan pedidos anónimos.
+func saleWithTracking(reader SaleReader, trackingNumber string) (*Sale, error) {
+	if indexed, ok := reader.(TrackingSaleReader); ok {
+		return indexed.GetByTrackingNumber(trackingNumber)
+	}
+	return nil, ErrNotFound
+}
+
+// SaleQuery holds the filters of a search a storage can apply itself. Empty
+// fields don't filter.
+type SaleQuery struct {
+	UserID string
+	Status string
+}
+
+func (q SaleQuery) matches(sale *Sale) bool {
+	return (q.UserID == "" || sale.UserID == q.UserID) && (q.Status == "" || sale.Status == q.Status)
+}
+
+// FilteringReader is implemented by storages that filter a search themselves
+// instead of returning every sale.
+type FilteringReader interface {
+	Query(q SaleQuery) ([]*Sale, error)
+}
+
+// findSales delega el filtro en el storage si lo soporta y si no filtra
+// GetAll, o las ventas del usuario o del estado si el storage las indexa.
+func findSales(reader SaleReader, q SaleQuery) ([]*Sale, error) {
+	if filtering, ok := reader.(FilteringReader); ok {
+		return filtering.Query(q)
+	}
+	var all []*Sale
+	var err error
+	switch {
+	case q.UserID != "":
+		all, err = salesOfUser(reader, q.UserID)
+	case q.Status != "":
+		all, err = salesWithStatus(reader, q.Status)
+	default:
+		all, err = reader.GetAll()
+	}
+	if err != nil {
+		return nil, err
+	}
+	found := make([]*Sale, 0)
+	for _, sale := range all {
+		if q.matches(sale) {
+			found = append(found, sale)
+		}
+	}
+	return found, nil
+}
+
+// Storage is the store the Service reads its own writes from.
+type Storage interface {
+	SaleReader
+	SaleWriter
+}
+
+// splitStorage lee de reader y escribe en writer.
+type splitStorage struct {
+	SaleReader
+	SaleWriter
+}
+
+// NewSplitStorage composes a Storage that writes to writer and reads from
+// reader, e.g. a write store whose changes reader indexes. Reads inside
+// WithTx go to the writer's transaction. Pass it to NewService only when
+// reader sees writes immediately; for lagging readers use WithReadReplica.
+func NewSplitStorage(writer SaleWriter, reader SaleReader) Storage {
+	return splitStorage{SaleReader: reader, SaleWriter: writer}
+}
+
+// LocalStorage keeps sales in memory, indexed by user, status and tracking
+// number so lookups on them don't scan every sale. It is safe for concurrent use: it
+// stores and returns copies, so callers may edit the sales they read.
 type LocalStorage struct {
-	m map[string]*Sale
+	mu sync.RWMutex
+	m  map[string]*Sale
+	// IDs de las ventas por usuario, por estado y por número de seguimiento
+	byUser     map[string]map[string]struct{}
+	byStatus   map[string]map[string]struct{}
+	byTracking map[string]map[string]struct{}
 }
 
 func NewLocalStorage() *LocalStorage {
 	return &LocalStorage{
-		m: map[string]*Sale{},
+		m:          map[string]*Sale{},
+		byUser:     map[string]map[string]struct{}{},
+		byStatus:   map[string]map[string]struct{}{},
+		byTracking: map[string]map[string]struct{}{},
 	}
 }
 
@@ -26,23 +186,175 @@ func (l *LocalStorage) Set(sale *Sale) error {
 	if sale.ID == "" {
 		return ErrEmptyID
 	}
-	l.m[sale.ID] = sale
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if old, ok := l.m[sale.ID]; ok {
+		unindex(l.byUser, old.UserID, old.ID)
+		unindex(l.byStatus, old.Status, old.ID)
+		if old.TrackingNumber != "" {
+			unindex(l.byTracking, old.TrackingNumber, old.ID)
+		}
+	}
+	l.m[sale.ID] = sale.clone()
+	index(l.byUser, sale.UserID, sale.ID)
+	index(l.byStatus, sale.Status, sale.ID)
+	if sale.TrackingNumber != "" {
+		index(l.byTracking, sale.TrackingNumber, sale.ID)
+	}
 	return nil
 }
 
+func index(idx map[string]map[string]struct{}, key, id string) {
+	ids, ok := idx[key]
+	if !ok {
+		ids = map[string]struct{}{}
+		idx[key] = ids
+	}
+	ids[id] = struct{}{}
+}
+
+func unindex(idx map[string]map[string]struct{}, key, id string) {
+	delete(idx[key], id)
+	if len(idx[key]) == 0 {
+		delete(idx, key)
+	}
+}
+
 func (l *LocalStorage) Read(id string) (*Sale, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
 	s, ok := l.m[id]
 	if !ok {
 		return nil, ErrNotFound
 	}
-	return s, nil
+	return s.clone(), nil
 }
 
 // GetAll retorna todas las ventas en local storage.
 func (l *LocalStorage) GetAll() ([]*Sale, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.all(), nil
+}
+
+// all copia todas las ventas; requiere el lock tomado.
+func (l *LocalStorage) all() []*Sale {
 	sales := make([]*Sale, 0, len(l.m))
 	for _, s := range l.m {
-		sales = append(sales, s)
+		sales = append(sales, s.clone())
+	}
+	return sales
+}
+
+func (l *LocalStorage) GetByUser(userID string) ([]*Sale, error) {
+	return l.Query(SaleQuery{UserID: userID})
+}
+
+func (l *LocalStorage) GetByStatus(status string) ([]*Sale, error) {
+	return l.Query(SaleQuery{Status: status})
+}
+
+// GetByTrackingNumber retorna la venta de menor ID con ese número de
+// seguimiento, como el ORDER BY id de los storages SQL.
+func (l *LocalStorage) GetByTrackingNumber(trackingNumber string) (*Sale, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	first := ""
+	for id := range l.byTracking[trackingNumber] {
+		if first == "" || id < first {
+			first = id
+		}
+	}
+	if first == "" {
+		return nil, ErrNotFound
+	}
+	return l.m[first].clone(), nil
+}
+
+// Query recorre el índice más chico entre el del usuario y el del estado;
+// sin filtros recorre todas las ventas.
+func (l *LocalStorage) Query(q SaleQuery) ([]*Sale, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var ids map[string]struct{}
+	indexed := false
+	if q.UserID != "" {
+		ids, indexed = l.byUser[q.UserID], true
+	}
+	if byStatus := l.byStatus[q.Status]; q.Status != "" && (!indexed || len(byStatus) < len(ids)) {
+		ids, indexed = byStatus, true
+	}
+	if !indexed {
+		return l.all(), nil
+	}
+
+	sales := make([]*Sale, 0, len(ids))
+	for id := range ids {
+		if s := l.m[id]; q.matches(s) {
+			sales = append(sales, s.clone())
+		}
 	}
 	return sales, nil
 }
+
+// WithTx no abre transacción: las escrituras de fn quedan aplicadas aunque
+// retorne error, y cada llamada toma el lock por separado.
+func (l *LocalStorage) WithTx(ctx context.Context, fn func(tx Storage) error) error {
+	return fn(l)
+}
+
+var ErrRecurringNotFound = errors.New("recurring sale not found")
+
+// RecurringStorage persists recurring sale definitions.
+type RecurringStorage interface {
+	Set(rs *RecurringSale) error
+	Read(id string) (*RecurringSale, error)
+	GetAll() ([]*RecurringSale, error)
+}
+
+// LocalRecurringStorage keeps the definitions in memory. It is safe for
+// concurrent use and, like LocalStorage, stores and returns copies.
+type LocalRecurringStorage struct {
+	mu sync.RWMutex
+	m  map[string]*RecurringSale
+}
+
+func NewLocalRecurringStorage() *LocalRecurringStorage {
+	return &LocalRecurringStorage{
+		m: map[string]*RecurringSale{},
+	}
+}
+
+func (l *LocalRecurringStorage) Set(rs *RecurringSale) error {
+	if rs.ID == "" {
+		return ErrEmptyID
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.m[rs.ID] = rs.clone()
+	return nil
+}
+
+func (l *LocalRecurringStorage) Read(id string) (*RecurringSale, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	rs, ok := l.m[id]
+	if !ok {
+		return nil, ErrRecurringNotFound
+	}
+	return rs.clone(), nil
+}
+
+// GetAll retorna todas las definiciones recurrentes en local storage.
+func (l *LocalRecurringStorage) GetAll() ([]*RecurringSale, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	defs := make([]*RecurringSale, 0, len(l.m))
+	for _, rs := range l.m {
+		defs = append(defs, rs.clone())
+	}
+	return defs, nil
+}
@@ -0,0 +1,241 @@
+package sales
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// txStorage aplica las escrituras de WithTx solo cuando fn no falla y el
+// commit, que falla con commitErr, se confirma.
+type txStorage struct {
+	*LocalStorage
+	commits   int
+	commitErr error
+}
+
+func (t *txStorage) WithTx(ctx context.Context, fn func(tx Storage) error) error {
+	pending := NewLocalStorage()
+	all, _ := t.GetAll()
+	for _, sale := range all {
+		pending.Set(sale)
+	}
+	if err := fn(pending); err != nil {
+		return err
+	}
+	if t.commitErr != nil {
+		return t.commitErr
+	}
+	t.LocalStorage = pending
+	t.commits++
+	return nil
+}
+
+// verifica que la edición de una venta y su auditoría se guardan en una transacción
+func TestEditSaleCommitsInTransaction(t *testing.T) {
+	storage := &txStorage{LocalStorage: NewLocalStorage()}
+	storage.Set(&Sale{ID: "s1", UserID: "user123", Amount: 10, Status: StatusPending, Version: 1})
+	svc := NewService(storage, zaptest.NewLogger(t), "http://unused")
+
+	amount := 25.0
+	if _, err := svc.EditSale("s1", SaleEdit{Amount: &amount}); err != nil {
+		t.Fatalf("EditSale returned error: %v", err)
+	}
+	if storage.commits != 1 {
+		t.Fatalf("expected 1 commit, got %d", storage.commits)
+	}
+	sale, _ := storage.Read("s1")
+	if sale.Amount != 25 || sale.Version != 2 {
+		t.Errorf("expected the committed edit, got amount %v version %d", sale.Amount, sale.Version)
+	}
+	if entries, _ := svc.GetSaleAudit("s1", nil); len(entries) != 1 {
+		t.Errorf("expected 1 audit entry, got %d", len(entries))
+	}
+	if revisions, _ := svc.revisions.GetAll(); len(revisions) != 2 {
+		t.Errorf("expected the seeded and the edited revision, got %d", len(revisions))
+	}
+}
+
+// verifica que un commit fallido no deja la entrada en la auditoría ni en la cadena
+func TestEditSaleRollbackLeavesNoAudit(t *testing.T) {
+	storage := &txStorage{LocalStorage: NewLocalStorage(), commitErr: errors.New("commit failed")}
+	storage.Set(&Sale{ID: "s1", UserID: "user123", Amount: 10, Status: StatusPending, Version: 1})
+	svc := NewService(storage, zaptest.NewLogger(t), "http://unused")
+
+	amount := 25.0
+	if _, err := svc.EditSale("s1", SaleEdit{Amount: &amount}); err == nil {
+		t.Fatal("expected the failed commit returned")
+	}
+	if entries, _ := svc.ExportAudit(); len(entries) != 0 {
+		t.Errorf("expected no audit entry for the rolled back edit, got %d", len(entries))
+	}
+
+	storage.commitErr = nil
+	if _, err := svc.EditSale("s1", SaleEdit{Amount: &amount}); err != nil {
+		t.Fatalf("EditSale returned error: %v", err)
+	}
+	if result, _ := svc.VerifyAudit(); !result.Valid || result.Entries != 1 {
+		t.Errorf("expected a chain with only the committed edit, got %+v", result)
+	}
+}
+
+// verifica que sin transacciones WithTx corre fn sobre el mismo storage
+func TestLocalStorageWithTx(t *testing.T) {
+	storage := NewLocalStorage()
+	errFailed := errors.New("failed")
+	err := storage.WithTx(context.Background(), func(tx Storage) error {
+		tx.Set(&Sale{ID: "s1"})
+		return errFailed
+	})
+	if err != errFailed {
+		t.Fatalf("expected the fn error, got %v", err)
+	}
+	if _, err := storage.Read("s1"); err != nil {
+		t.Errorf("expected the write applied without a transaction, got %v", err)
+	}
+}
+
+// verifica con -race que Set, Read y GetAll pueden correr a la vez y que
+// editar una venta leída no cambia la guardada
+func TestLocalStorageConcurrentAccess(t *testing.T) {
+	storage := NewLocalStorage()
+	storage.Set(&Sale{ID: "shared", Amount: 1, Metadata: map[string]string{"k": "v"}})
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				storage.Set(&Sale{ID: fmt.Sprintf("s%d-%d", w, i), Amount: float64(i)})
+				if sale, err := storage.Read("shared"); err == nil {
+					sale.Amount = float64(w)
+					sale.Metadata["k"] = "edited"
+				}
+				all, _ := storage.GetAll()
+				for _, sale := range all {
+					_ = sale.Amount
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	all, _ := storage.GetAll()
+	if len(all) != 8*100+1 {
+		t.Errorf("expected every write stored, got %d sales", len(all))
+	}
+	if sale, _ := storage.Read("shared"); sale.Amount != 1 || sale.Metadata["k"] != "v" {
+		t.Errorf("expected the stored sale unchanged by the readers, got %+v", sale)
+	}
+}
+
+// verifica que los índices siguen los cambios de usuario y estado
+func TestLocalStorageQuery(t *testing.T) {
+	storage := NewLocalStorage()
+	storage.Set(&Sale{ID: "a", UserID: "u1", Status: StatusPending})
+	storage.Set(&Sale{ID: "b", UserID: "u1", Status: StatusPending})
+	storage.Set(&Sale{ID: "c", UserID: "u2", Status: StatusApproved})
+	// Cambian de estado y de usuario: salen de los índices anteriores
+	storage.Set(&Sale{ID: "b", UserID: "u1", Status: StatusApproved})
+	storage.Set(&Sale{ID: "c", UserID: "u3", Status: StatusApproved})
+
+	for _, tc := range []struct {
+		query SaleQuery
+		want  []string
+	}{
+		{SaleQuery{UserID: "u1"}, []string{"a", "b"}},
+		{SaleQuery{UserID: "u2"}, nil},
+		{SaleQuery{Status: StatusApproved}, []string{"b", "c"}},
+		{SaleQuery{Status: StatusPending}, []string{"a"}},
+		{SaleQuery{UserID: "u1", Status: StatusApproved}, []string{"b"}},
+		{SaleQuery{}, []string{"a", "b", "c"}},
+	} {
+		found, err := storage.Query(tc.query)
+		if err != nil {
+			t.Fatalf("Query(%+v): %v", tc.query, err)
+		}
+		var ids []string
+		for _, sale := range found {
+			ids = append(ids, sale.ID)
+		}
+		slices.Sort(ids)
+		if !slices.Equal(ids, tc.want) {
+			t.Errorf("Query(%+v): expected %v, got %v", tc.query, tc.want, ids)
+		}
+	}
+
+	// Editar una venta devuelta no toca el índice
+	found, _ := storage.GetByStatus(StatusPending)
+	found[0].Status = StatusRejected
+	if pending, _ := storage.GetByStatus(StatusPending); len(pending) != 1 {
+		t.Errorf("expected the stored sale still pending, got %+v", pending)
+	}
+}
+
+func TestLocalStorageGetByTrackingNumber(t *testing.T) {
+	storage := NewLocalStorage()
+	storage.Set(&Sale{ID: "a", TrackingNumber: "T1"})
+	storage.Set(&Sale{ID: "b"})
+	// Cambia el número de seguimiento: sale del índice anterior
+	storage.Set(&Sale{ID: "b", TrackingNumber: "T2"})
+	storage.Set(&Sale{ID: "a", TrackingNumber: "T3"})
+
+	if _, err := storage.GetByTrackingNumber("T1"); err != ErrNotFound {
+		t.Errorf("expected the replaced tracking number unindexed, got %v", err)
+	}
+	for number, id := range map[string]string{"T2": "b", "T3": "a"} {
+		sale, err := storage.GetByTrackingNumber(number)
+		if err != nil || sale.ID != id {
+			t.Errorf("GetByTrackingNumber(%s): expected %s, got %+v (err=%v)", number, id, sale, err)
+		}
+	}
+
+	// Sin índice no se recorren las ventas
+	if _, err := saleWithTracking(readOnly{storage}, "T2"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound without a tracking index, got %v", err)
+	}
+}
+
+// readOnly expone solo la lectura de un storage, como un índice de búsqueda.
+type readOnly struct {
+	SaleReader
+}
+
+// verifica que un storage compuesto escribe en el writer y lee del reader
+func TestSplitStorage(t *testing.T) {
+	writer := NewLocalStorage()
+	index := NewLocalStorage()
+	index.Set(&Sale{ID: "indexed", UserID: "u1", Status: StatusPending, Amount: 10})
+	svc := NewService(NewSplitStorage(writer, readOnly{index}), zaptest.NewLogger(t), "http://unused")
+
+	if _, err := svc.GetSale("indexed", nil); err != nil {
+		t.Fatalf("expected the sale read from the reader, got %v", err)
+	}
+	if _, err := svc.CreateDraftSale("user123", 40); err != nil {
+		t.Fatalf("CreateDraftSale returned error: %v", err)
+	}
+	all, _ := writer.GetAll()
+	if len(all) != 1 || all[0].ID == "indexed" {
+		t.Errorf("expected the new draft in the writer only, got %d sales", len(all))
+	}
+}
+
+// verifica que GetSale distingue una venta inexistente de un fallo del storage
+func TestGetSale_PropagatesStorageErrors(t *testing.T) {
+	readErr := errors.New("connection reset")
+	storage := &flakyStorage{Storage: NewLocalStorage(), failures: 1, err: readErr}
+	svc := NewService(storage, zaptest.NewLogger(t), "http://unused")
+
+	if _, err := svc.GetSale("s1", nil); !errors.Is(err, readErr) {
+		t.Errorf("expected the storage error, got %v", err)
+	}
+	if _, err := svc.GetSale("s1", nil); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
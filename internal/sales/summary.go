@@ -0,0 +1,107 @@
+package sales
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// UserSummary aggregates the sales of a single user. It is maintained on every
+// write so reading it never scans the sales storage.
+type UserSummary struct {
+	UserID      string     `json:"user_id"`
+	Count       int        `json:"count"`
+	TotalAmount float64    `json:"total_amount"`
+	LastSaleAt  *time.Time `json:"last_sale_at,omitempty"`
+}
+
+// SummaryStorage persists per-user summaries. Apply must add the deltas
+// atomically so concurrent writers don't lose updates.
+type SummaryStorage interface {
+	Apply(userID string, count int, amount float64, saleAt time.Time) error
+	Read(userID string) (*UserSummary, error)
+}
+
+type LocalSummaryStorage struct {
+	mu sync.Mutex
+	m  map[string]*UserSummary
+}
+
+func NewLocalSummaryStorage() *LocalSummaryStorage {
+	return &LocalSummaryStorage{
+		m: make(map[string]*UserSummary),
+	}
+}
+
+// Apply suma los deltas al resumen del usuario; un saleAt cero no modifica
+// LastSaleAt.
+func (l *LocalSummaryStorage) Apply(userID string, count int, amount float64, saleAt time.Time) error {
+	if userID == "" {
+		return ErrEmptyID
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	summary, ok := l.m[userID]
+	if !ok {
+		summary = &UserSummary{UserID: userID}
+		l.m[userID] = summary
+	}
+	summary.Count += count
+	summary.TotalAmount = roundCents(summary.TotalAmount + amount)
+	if !saleAt.IsZero() && (summary.LastSaleAt == nil || saleAt.After(*summary.LastSaleAt)) {
+		at := saleAt
+		summary.LastSaleAt = &at
+	}
+	return nil
+}
+
+// Read retorna una copia del resumen; un usuario sin ventas tiene resumen vacío.
+func (l *LocalSummaryStorage) Read(userID string) (*UserSummary, error) {
+	if userID == "" {
+		return nil, ErrEmptyID
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	summary, ok := l.m[userID]
+	if !ok {
+		return &UserSummary{UserID: userID}, nil
+	}
+	copied := *summary
+	return &copied, nil
+}
+
+// WithSummaryStorage sets where per-user summaries are kept. Defaults to an
+// in-memory LocalSummaryStorage.
+func WithSummaryStorage(summaries SummaryStorage) Option {
+	return func(s *Service) {
+		s.summaries = summaries
+	}
+}
+
+// GetUserSummary returns the materialized summary of a user's sales.
+func (s *Service) GetUserSummary(userID string) (*UserSummary, error) {
+	return s.summaries.Read(userID)
+}
+
+// recordSummary mueve una venta entre resúmenes: resta before (si hay) y suma
+// after. Un fallo se registra en el log pero no revierte la venta persistida.
+func (s *Service) recordSummary(before, after *Sale) {
+	if before != nil && after != nil && before.UserID == after.UserID && before.Amount == after.Amount {
+		return
+	}
+	if before != nil {
+		if err := s.summaries.Apply(before.UserID, -1, -before.Amount, time.Time{}); err != nil {
+			s.logger.Error("failed to update user summary", zap.String("user_id", before.UserID), zap.Error(err))
+		}
+	}
+	if after != nil {
+		if err := s.summaries.Apply(after.UserID, 1, after.Amount, after.CreatedAt); err != nil {
+			s.logger.Error("failed to update user summary", zap.String("user_id", after.UserID), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package sales
+
+import (
+	"fmt"
+	"slices"
+)
+
+// AllowedTransitions returns the statuses sale can move to next: the
+// built-in workflow (submit, approve or reject, dispute) plus the custom
+// statuses of its tenant. Resolving a dispute is only offered when
+// resolveDisputes is set, since it is reserved to admins. Sales in a closed
+// accounting period can only be disputed.
+func (s *Service) AllowedTransitions(sale *Sale, resolveDisputes bool) ([]string, error) {
+	periodOpen := true
+	if err := s.checkPeriodOpen(sale); err == ErrPeriodClosed {
+		periodOpen = false
+	} else if err != nil {
+		return nil, err
+	}
+
+	allowed := make([]string, 0)
+	switch sale.Status {
+	case StatusDraft:
+		if periodOpen {
+			allowed = append(allowed, StatusPending)
+		}
+	case StatusPending:
+		if periodOpen {
+			allowed = append(allowed, StatusApproved, StatusRejected)
+		}
+	case StatusApproved:
+		allowed = append(allowed, StatusDisputed)
+	case StatusDisputed:
+		if resolveDisputes {
+			allowed = append(allowed, StatusApproved, StatusChargedBack)
+		}
+	}
+
+	if periodOpen && sale.TenantID != "" {
+		custom, err := s.vocabularies.Read(sale.TenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read status vocabulary: %w", err)
+		}
+		for _, st := range custom {
+			if slices.Contains(st.From, sale.Status) && !slices.Contains(allowed, st.Name) {
+				allowed = append(allowed, st.Name)
+			}
+		}
+	}
+	return allowed, nil
+}
@@ -0,0 +1,52 @@
+package sales
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// TestAllowedTransitions_MatchesWorkflow verifica que las transiciones
+// ofrecidas coincidan con las que el servicio acepta.
+func TestAllowedTransitions_MatchesWorkflow(t *testing.T) {
+	storage := NewLocalStorage()
+	svc := NewService(storage, zaptest.NewLogger(t), "http://unused")
+	if err := svc.SetTenantStatuses("acme", []CustomStatus{{Name: "escalated", From: []string{StatusApproved}}}); err != nil {
+		t.Fatalf("SetTenantStatuses returned error: %v", err)
+	}
+	now := time.Now()
+
+	cases := []struct {
+		name            string
+		sale            *Sale
+		resolveDisputes bool
+		want            []string
+	}{
+		{"draft", &Sale{Status: StatusDraft, CreatedAt: now}, false, []string{StatusPending}},
+		{"pending", &Sale{Status: StatusPending, CreatedAt: now}, false, []string{StatusApproved, StatusRejected}},
+		{"approved with vocabulary", &Sale{Status: StatusApproved, TenantID: "acme", CreatedAt: now}, false, []string{StatusDisputed, "escalated"}},
+		{"disputed", &Sale{Status: StatusDisputed, CreatedAt: now}, false, []string{}},
+		{"disputed as admin", &Sale{Status: StatusDisputed, CreatedAt: now}, true, []string{StatusApproved, StatusChargedBack}},
+		{"rejected", &Sale{Status: StatusRejected, CreatedAt: now}, true, []string{}},
+	}
+	for _, tc := range cases {
+		got, err := svc.AllowedTransitions(tc.sale, tc.resolveDisputes)
+		if err != nil {
+			t.Fatalf("%s: AllowedTransitions returned error: %v", tc.name, err)
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+
+	// En un período cerrado solo queda la disputa
+	closed := &Sale{Status: StatusPending, CreatedAt: now}
+	if _, err := svc.ClosePeriod(PeriodOf(now), "op-1"); err != nil {
+		t.Fatalf("ClosePeriod returned error: %v", err)
+	}
+	if got, _ := svc.AllowedTransitions(closed, true); len(got) != 0 {
+		t.Errorf("expected no transitions in a closed period, got %v", got)
+	}
+}
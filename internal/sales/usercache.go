@@ -0,0 +1,116 @@
+package sales
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// UserCache keeps users already validated against the user service. Only
+// existing users are cached.
+type UserCache interface {
+	Get(userID string) (*User, bool, error)
+	Set(userID string, user *User) error
+}
+
+// ttlUserCache lo implementan los caches que aceptan un TTL por entrada, como
+// el de la sincronización nocturna.
+type ttlUserCache interface {
+	SetWithTTL(userID string, user *User, ttl time.Duration) error
+}
+
+// LocalUserCache is an in-process UserCache.
+type LocalUserCache struct {
+	mu    sync.RWMutex
+	ttl   time.Duration
+	users map[string]cachedUser
+}
+
+type cachedUser struct {
+	user      User
+	expiresAt time.Time
+}
+
+func NewLocalUserCache(ttl time.Duration) *LocalUserCache {
+	return &LocalUserCache{
+		ttl:   ttl,
+		users: map[string]cachedUser{},
+	}
+}
+
+func (c *LocalUserCache) Get(userID string) (*User, bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	cu, ok := c.users[userID]
+	if !ok || time.Now().After(cu.expiresAt) {
+		return nil, false, nil
+	}
+	u := cu.user
+	return &u, true, nil
+}
+
+func (c *LocalUserCache) Set(userID string, user *User) error {
+	return c.SetWithTTL(userID, user, c.ttl)
+}
+
+func (c *LocalUserCache) SetWithTTL(userID string, user *User, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.users[userID] = cachedUser{user: *user, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// RedisUserCache shares the user cache between API instances.
+type RedisUserCache struct {
+	client redis.UniversalClient
+	ttl    time.Duration
+	prefix string
+}
+
+func NewRedisUserCache(client redis.UniversalClient, ttl time.Duration) *RedisUserCache {
+	return &RedisUserCache{
+		client: client,
+		ttl:    ttl,
+		prefix: "api_sales:user:",
+	}
+}
+
+func (c *RedisUserCache) Get(userID string) (*User, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	raw, err := c.client.Get(ctx, c.prefix+userID).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var u User
+	if err := json.Unmarshal(raw, &u); err != nil {
+		return nil, false, err
+	}
+	return &u, true, nil
+}
+
+func (c *RedisUserCache) Set(userID string, user *User) error {
+	return c.SetWithTTL(userID, user, c.ttl)
+}
+
+func (c *RedisUserCache) SetWithTTL(userID string, user *User, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	data, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(ctx, c.prefix+userID, data, ttl).Err()
+}
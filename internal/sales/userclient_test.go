@@ -0,0 +1,70 @@
+package sales
+
+import (
+	"api_sales/internal/vcr"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// replayBaseURL es el host ficticio contra el que se reproducen los cassettes
+const replayBaseURL = "http://user-service.invalid/users"
+
+// newRecordedUserClient devuelve un UserClient que reproduce el cassette
+// indicado. Con VCR_MODE=record lo vuelve a grabar contra USER_SERVICE_URL
+// (p. ej. staging).
+func newRecordedUserClient(t *testing.T, cassette string) *UserClient {
+	t.Helper()
+	path := filepath.Join("testdata", "cassettes", cassette+".json")
+	mode := vcr.ModeFromEnv()
+
+	baseURL := replayBaseURL
+	if mode == vcr.ModeRecord {
+		baseURL = os.Getenv("USER_SERVICE_URL")
+		if baseURL == "" {
+			t.Skip("USER_SERVICE_URL is required to record cassettes")
+		}
+	}
+
+	rec, err := vcr.New(path, mode, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := rec.Stop(); err != nil {
+			t.Errorf("failed to save cassette: %v", err)
+		}
+	})
+
+	uc := NewUserClient(baseURL)
+	uc.client.SetTransport(rec)
+	return uc
+}
+
+// TestUserClient_RecordedInteractions verifica las respuestas del servicio de
+// usuarios grabadas en el cassette.
+func TestUserClient_RecordedInteractions(t *testing.T) {
+	uc := newRecordedUserClient(t, "user_client")
+
+	user, err := uc.GetUserByID("user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.ID != "user-123" || user.Name == "" {
+		t.Errorf("unexpected user: %+v", user)
+	}
+
+	if _, err := uc.GetUserByID("missing-user"); err == nil || !strings.Contains(err.Error(), "usuario no encontrado") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+
+	// El ID se escapa antes de armar la URL
+	if _, err := uc.GetUserByID("a/b"); err == nil || !strings.Contains(err.Error(), "usuario no encontrado") {
+		t.Errorf("expected not found error for escaped id, got %v", err)
+	}
+
+	if _, err := uc.GetUserByID("broken-user"); err == nil || !strings.Contains(err.Error(), "estado inesperado (503)") {
+		t.Errorf("expected unexpected status error, got %v", err)
+	}
+}
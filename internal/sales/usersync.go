@@ -0,0 +1,192 @@
+package sales
+
+import (
+	"api_sales/internal/lock"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var userSyncLastSuccess = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "sales_user_sync_last_success_timestamp_seconds",
+	Help: "Unix time of the last user sync that refreshed every known user.",
+})
+
+// UserSyncOptions bounds the work done by SyncUsers.
+type UserSyncOptions struct {
+	// Concurrency is the number of parallel calls to the user service.
+	Concurrency int
+	// CacheTTL is how long the refreshed users stay cached; it should outlast
+	// the time until the next sync.
+	CacheTTL time.Duration
+}
+
+// UserSyncResult summarizes a user sync.
+type UserSyncResult struct {
+	Users     int           `json:"users"`
+	Refreshed int           `json:"refreshed"`
+	Missing   int           `json:"missing"`
+	Failed    int           `json:"failed"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// SyncUsers fetches every user with a sale from the user service and stores
+// it in the user cache, so that daytime requests rarely wait on the user
+// service. Unlike WarmUp it bypasses the cache, refreshing what is there. It
+// stops early when ctx is cancelled.
+func (s *Service) SyncUsers(ctx context.Context, opts UserSyncOptions) (UserSyncResult, error) {
+	start := time.Now()
+	result := UserSyncResult{}
+
+	allSales, err := s.storage.GetAll()
+	if err != nil {
+		return result, err
+	}
+	seen := map[string]bool{}
+	userIDs := make([]string, 0)
+	for _, sale := range allSales {
+		if sale.UserID != "" && !seen[sale.UserID] {
+			seen[sale.UserID] = true
+			userIDs = append(userIDs, sale.UserID)
+		}
+	}
+	result.Users = len(userIDs)
+
+	concurrency := max(opts.Concurrency, 1)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan string)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for userID := range jobs {
+				_, err := s.userClient.refreshUser(userID, opts.CacheTTL)
+				mu.Lock()
+				switch {
+				case err == nil:
+					result.Refreshed++
+				case strings.Contains(err.Error(), "usuario no encontrado"):
+					result.Missing++
+				default:
+					result.Failed++
+					s.logger.Warn("failed to sync user", zap.String("user_id", userID), zap.Error(err))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, userID := range userIDs {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- userID:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	result.Duration = time.Since(start)
+	if ctx.Err() == nil && result.Failed == 0 {
+		userSyncLastSuccess.SetToCurrentTime()
+	}
+	s.logger.Info("user sync completed",
+		zap.Int("users", result.Users),
+		zap.Int("refreshed", result.Refreshed),
+		zap.Int("missing", result.Missing),
+		zap.Int("failed", result.Failed),
+		zap.Duration("duration", result.Duration),
+	)
+	return result, ctx.Err()
+}
+
+// userSyncLockKey is the distributed lock held during a user sync.
+const userSyncLockKey = "user-sync:nightly"
+
+// userSyncTimeout limita cada corrida para que no se superponga con la siguiente.
+const userSyncTimeout = time.Hour
+
+// UserSync runs SyncUsers once a day at a fixed time of day.
+type UserSync struct {
+	service *Service
+	at      time.Duration
+	loc     *time.Location
+	opts    UserSyncOptions
+	locker  lock.Locker
+	logger  *zap.Logger
+}
+
+// NewUserSync creates a job that syncs at the time of day at (offset from
+// midnight) in loc. Like the Scheduler, a non-nil locker makes only one
+// replica run each sync.
+func NewUserSync(service *Service, at time.Duration, loc *time.Location, opts UserSyncOptions, locker lock.Locker, logger *zap.Logger) *UserSync {
+	if logger == nil {
+		logger, _ = zap.NewProduction()
+	}
+
+	return &UserSync{
+		service: service,
+		at:      at,
+		loc:     loc,
+		opts:    opts,
+		locker:  locker,
+		logger:  logger,
+	}
+}
+
+// Start blocks running a sync every day until ctx is cancelled.
+func (j *UserSync) Start(ctx context.Context) {
+	for {
+		timer := time.NewTimer(time.Until(j.next(time.Now())))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			j.run(ctx)
+		}
+	}
+}
+
+// next retorna la próxima hora de sincronización posterior a now.
+func (j *UserSync) next(now time.Time) time.Time {
+	local := now.In(j.loc)
+	// La hora se arma en horario local para respetar los cambios de horario
+	run := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, int(j.at), j.loc)
+	if !run.After(now) {
+		run = time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, int(j.at), j.loc)
+	}
+	return run
+}
+
+func (j *UserSync) run(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, userSyncTimeout)
+	defer cancel()
+	sync := func(ctx context.Context) error {
+		_, err := j.service.SyncUsers(ctx, j.opts)
+		return err
+	}
+
+	if j.locker == nil {
+		if err := sync(ctx); err != nil {
+			j.logger.Error("user sync failed", zap.Error(err))
+		}
+		return
+	}
+
+	err := lock.Run(ctx, j.locker, userSyncLockKey, userSyncTimeout, sync)
+	switch {
+	case errors.Is(err, lock.ErrNotAcquired):
+		j.logger.Debug("user sync skipped, lock held by another instance")
+	case err != nil:
+		j.logger.Error("user sync failed", zap.Error(err))
+	}
+}
@@ -0,0 +1,74 @@
+package sales
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// TestSyncUsers_RefreshesCacheWithLongTTL verifica que la sincronización
+// consulte aunque el usuario esté en cache y lo guarde con su propio TTL.
+func TestSyncUsers_RefreshesCacheWithLongTTL(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if strings.HasSuffix(r.URL.Path, "/gone") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "x", "name": "Test User"}`))
+	}))
+	defer server.Close()
+
+	storage := NewLocalStorage()
+	for i, userID := range []string{"u1", "u2", "u1", "gone"} {
+		storage.Set(&Sale{ID: string(rune('a' + i)), UserID: userID, Amount: 10, Status: StatusApproved})
+	}
+	cache := NewLocalUserCache(time.Millisecond)
+	cache.SetWithTTL("u1", &User{ID: "u1", Name: "Stale"}, time.Hour)
+	svc := NewService(storage, zaptest.NewLogger(t), server.URL, WithUserCache(cache))
+
+	result, err := svc.SyncUsers(context.Background(), UserSyncOptions{Concurrency: 2, CacheTTL: time.Hour})
+	if err != nil {
+		t.Fatalf("SyncUsers returned error: %v", err)
+	}
+	if result.Users != 3 || result.Refreshed != 2 || result.Missing != 1 || result.Failed != 0 {
+		t.Errorf("unexpected sync result: %+v", result)
+	}
+
+	// Con el TTL del cache ya habrían vencido
+	time.Sleep(5 * time.Millisecond)
+	calls.Store(0)
+	user, err := svc.GetUser("u1")
+	if err != nil || user.Name != "Test User" {
+		t.Errorf("expected the refreshed user, got %+v, %v", user, err)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("expected no calls to the user service, got %d", calls.Load())
+	}
+}
+
+// TestUserSync_NextRun verifica la próxima corrida respecto de la hora local.
+func TestUserSync_NextRun(t *testing.T) {
+	loc, err := time.LoadLocation("America/Santiago")
+	if err != nil {
+		t.Skip("timezone database not available")
+	}
+	job := NewUserSync(nil, 3*time.Hour, loc, UserSyncOptions{}, nil, zaptest.NewLogger(t))
+
+	before := time.Date(2024, 5, 10, 2, 0, 0, 0, loc)
+	if next := job.next(before); !next.Equal(time.Date(2024, 5, 10, 3, 0, 0, 0, loc)) {
+		t.Errorf("expected 03:00 the same day, got %v", next)
+	}
+	after := time.Date(2024, 5, 10, 3, 0, 0, 0, loc)
+	if next := job.next(after); !next.Equal(time.Date(2024, 5, 11, 3, 0, 0, 0, loc)) {
+		t.Errorf("expected 03:00 the next day, got %v", next)
+	}
+}
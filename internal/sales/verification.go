@@ -0,0 +1,368 @@
+package sales
+
+import (
+	"api_sales/internal/lock"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+// StatusPendingVerification is a provisional sale created while the user
+// service was unavailable. The VerificationWorker moves it to its real status
+// once the user is validated, or rejects it if the user doesn't exist.
+const StatusPendingVerification = "pending_verification"
+
+const (
+	EventSaleVerified           = "sale.verified"
+	EventSaleVerificationFailed = "sale.verification_failed"
+)
+
+const (
+	DefaultVerificationAttempts   = 10
+	DefaultVerificationBackoff    = 30 * time.Second
+	DefaultVerificationMaxBackoff = time.Hour
+)
+
+var verificationAttempts = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "sales_verification_attempts_total",
+	Help: "User re-validations of provisional sales by result: verified, rejected, retry or stuck.",
+}, []string{"result"})
+
+// verificationActor figura en la auditoría de las ventas provisorias resueltas.
+var verificationActor = Actor{ID: "system:verification"}
+
+// Error para acciones sobre ventas sin verificación pendiente
+var ErrVerificationNotFound = errors.New("sale is not pending verification")
+
+// Verification tracks the re-validation of the user of a provisional sale.
+// After MaxAttempts failed attempts it is Stuck and only retried on request.
+type Verification struct {
+	SaleID        string    `json:"sale_id"`
+	UserID        string    `json:"user_id"`
+	Attempts      int       `json:"attempts"`
+	LastError     string    `json:"last_error,omitempty"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	Stuck         bool      `json:"stuck"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// VerificationStorage persists the verification of each provisional sale.
+type VerificationStorage interface {
+	Set(v *Verification) error
+	Read(saleID string) (*Verification, bool, error)
+	Delete(saleID string) error
+	GetAll() ([]*Verification, error)
+}
+
+type LocalVerificationStorage struct {
+	mu sync.RWMutex
+	m  map[string]*Verification
+}
+
+func NewLocalVerificationStorage() *LocalVerificationStorage {
+	return &LocalVerificationStorage{
+		m: make(map[string]*Verification),
+	}
+}
+
+func (l *LocalVerificationStorage) Set(v *Verification) error {
+	if v.SaleID == "" {
+		return ErrEmptyID
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	c := *v
+	l.m[v.SaleID] = &c
+	return nil
+}
+
+func (l *LocalVerificationStorage) Read(saleID string) (*Verification, bool, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	v, ok := l.m[saleID]
+	if !ok {
+		return nil, false, nil
+	}
+	c := *v
+	return &c, true, nil
+}
+
+func (l *LocalVerificationStorage) Delete(saleID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.m, saleID)
+	return nil
+}
+
+func (l *LocalVerificationStorage) GetAll() ([]*Verification, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	result := make([]*Verification, 0, len(l.m))
+	for _, v := range l.m {
+		c := *v
+		result = append(result, &c)
+	}
+	return result, nil
+}
+
+// WithVerificationStorage sets where verifications are kept. Defaults to an
+// in-memory LocalVerificationStorage.
+func WithVerificationStorage(verifications VerificationStorage) Option {
+	return func(s *Service) {
+		s.verifications = verifications
+	}
+}
+
+// WithProvisionalSales enables the degradation mode: when the user service
+// is unavailable new sales are stored as pending_verification instead of
+// failing. Each failed re-validation waits twice as long as the previous
+// one, from backoff up to maxBackoff, and after maxAttempts the sale is stuck
+// until an admin retries it. Zero values use the defaults.
+func WithProvisionalSales(maxAttempts int, backoff, maxBackoff time.Duration) Option {
+	return func(s *Service) {
+		s.provisionalSales = true
+		if maxAttempts > 0 {
+			s.verifyAttempts = maxAttempts
+		}
+		if backoff > 0 {
+			s.verifyBackoff = backoff
+		}
+		if maxBackoff > 0 {
+			s.verifyMaxBackoff = maxBackoff
+		}
+	}
+}
+
+// retryDelay retorna la espera exponencial luego de attempts fallos.
+func (s *Service) retryDelay(attempts int) time.Duration {
+	delay := s.verifyBackoff
+	for i := 1; i < attempts && delay < s.verifyMaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, s.verifyMaxBackoff)
+}
+
+// VerifyProvisionalSales re-validates the users of the provisional sales due
+// at now, promoting them to their status or rejecting them. It returns how
+// many were resolved.
+func (s *Service) VerifyProvisionalSales(now time.Time) (int, error) {
+	s.verifyMu.Lock()
+	defer s.verifyMu.Unlock()
+
+	all, err := s.storage.GetAll()
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve sales: %w", err)
+	}
+	resolved := 0
+	for _, sale := range all {
+		if sale.Status != StatusPendingVerification {
+			continue
+		}
+		v, ok, err := s.verifications.Read(sale.ID)
+		if err != nil {
+			return resolved, fmt.Errorf("failed to read verification: %w", err)
+		}
+		// Las que no tienen seguimiento (p. ej. tras un reinicio) se intentan ya
+		if !ok {
+			v = &Verification{SaleID: sale.ID, UserID: sale.UserID, NextAttemptAt: now, CreatedAt: sale.CreatedAt}
+		}
+		if v.Stuck || v.NextAttemptAt.After(now) {
+			continue
+		}
+		if s.verifySale(sale, v, now) {
+			resolved++
+		}
+	}
+	return resolved, nil
+}
+
+// verifySale hace un intento y reporta si la venta quedó resuelta.
+func (s *Service) verifySale(sale *Sale, v *Verification, now time.Time) bool {
+	_, err := s.userClient.GetUserByID(sale.UserID)
+	switch {
+	case err == nil:
+		verificationAttempts.WithLabelValues("verified").Inc()
+		return s.resolveProvisional(sale, s.randomStatus(), EventSaleVerified, now)
+	case strings.Contains(err.Error(), "usuario no encontrado"):
+		verificationAttempts.WithLabelValues("rejected").Inc()
+		return s.resolveProvisional(sale, StatusRejected, EventSaleVerificationFailed, now)
+	}
+
+	v.Attempts++
+	v.LastError = err.Error()
+	v.NextAttemptAt = now.Add(s.retryDelay(v.Attempts))
+	if v.Attempts >= s.verifyAttempts {
+		v.Stuck = true
+		verificationAttempts.WithLabelValues("stuck").Inc()
+		s.logger.Error("provisional sale stuck, user could not be verified", zap.String("sale_id", sale.ID), zap.Int("attempts", v.Attempts), zap.Error(err))
+	} else {
+		verificationAttempts.WithLabelValues("retry").Inc()
+		s.logger.Warn("failed to verify provisional sale, retrying", zap.String("sale_id", sale.ID), zap.Time("next_attempt_at", v.NextAttemptAt), zap.Error(err))
+	}
+	if err := s.verifications.Set(v); err != nil {
+		s.logger.Error("failed to save verification", zap.String("sale_id", sale.ID), zap.Error(err))
+	}
+	return false
+}
+
+func (s *Service) resolveProvisional(sale *Sale, status, event string, now time.Time) bool {
+	before := sale.clone()
+	from := sale.Status
+	sale.Status = status
+	sale.UpdatedAt = now.UTC()
+	sale.Version++
+	if status == StatusPending {
+		since := sale.UpdatedAt
+		sale.PendingSince = &since
+	}
+	if err := s.saveAudited(AuditActionVerificationResolved, before, sale, verificationActor); err != nil {
+		s.logger.Error("failed to resolve provisional sale", zap.String("sale_id", sale.ID), zap.Error(err))
+		return false
+	}
+	if err := s.verifications.Delete(sale.ID); err != nil {
+		s.logger.Error("failed to delete verification", zap.String("sale_id", sale.ID), zap.Error(err))
+	}
+	s.publishStatusChanged(sale, from)
+	s.autoAssign(sale)
+
+	s.logger.Info("provisional sale resolved", zap.String("sale_id", sale.ID), zap.String("status", status))
+	s.notify(event, sale)
+	s.notify(EventSaleStatusChanged, sale)
+	return true
+}
+
+// PendingVerification is a provisional sale with its verification.
+type PendingVerification struct {
+	Verification
+	Sale *Sale `json:"sale"`
+}
+
+// PendingVerifications lists the provisional sales, oldest first. With
+// stuckOnly it only lists those that ran out of attempts.
+func (s *Service) PendingVerifications(stuckOnly bool) ([]PendingVerification, error) {
+	all, err := s.storage.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve sales: %w", err)
+	}
+	result := make([]PendingVerification, 0)
+	for _, sale := range all {
+		if sale.Status != StatusPendingVerification {
+			continue
+		}
+		v, ok, err := s.verifications.Read(sale.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read verification: %w", err)
+		}
+		if !ok {
+			v = &Verification{SaleID: sale.ID, UserID: sale.UserID, NextAttemptAt: sale.CreatedAt, CreatedAt: sale.CreatedAt}
+		}
+		if stuckOnly && !v.Stuck {
+			continue
+		}
+		result = append(result, PendingVerification{Verification: *v, Sale: sale})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result, nil
+}
+
+// RetryVerification makes the worker try a provisional sale again on its
+// next run, with a fresh set of attempts.
+func (s *Service) RetryVerification(saleID string) (*Verification, error) {
+	s.verifyMu.Lock()
+	defer s.verifyMu.Unlock()
+
+	sale, err := s.storage.Read(saleID)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	if sale.Status != StatusPendingVerification {
+		return nil, ErrVerificationNotFound
+	}
+	v, ok, err := s.verifications.Read(saleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read verification: %w", err)
+	}
+	if !ok {
+		v = &Verification{SaleID: sale.ID, UserID: sale.UserID, CreatedAt: sale.CreatedAt}
+	}
+	v.Attempts = 0
+	v.Stuck = false
+	v.NextAttemptAt = utcNow()
+	if err := s.verifications.Set(v); err != nil {
+		return nil, fmt.Errorf("failed to save verification: %w", err)
+	}
+	return v, nil
+}
+
+// verificationLockKey is the distributed lock held during a verification run.
+const verificationLockKey = "verification-worker:provisional-sales"
+
+// VerificationWorker periodically re-validates the provisional sales.
+type VerificationWorker struct {
+	service  *Service
+	interval time.Duration
+	locker   lock.Locker
+	logger   *zap.Logger
+}
+
+// NewVerificationWorker creates a worker that runs every interval. Like the
+// Scheduler, a non-nil locker makes only one replica run at a time.
+func NewVerificationWorker(service *Service, interval time.Duration, locker lock.Locker, logger *zap.Logger) *VerificationWorker {
+	if logger == nil {
+		logger, _ = zap.NewProduction()
+	}
+
+	return &VerificationWorker{
+		service:  service,
+		interval: interval,
+		locker:   locker,
+		logger:   logger,
+	}
+}
+
+// Start blocks running the verifications until ctx is cancelled.
+func (w *VerificationWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			w.runPass(ctx, now)
+		}
+	}
+}
+
+func (w *VerificationWorker) runPass(ctx context.Context, now time.Time) {
+	verify := func(context.Context) error {
+		_, err := w.service.VerifyProvisionalSales(now)
+		return err
+	}
+
+	if w.locker == nil {
+		if err := verify(ctx); err != nil {
+			w.logger.Error("verification run failed", zap.Error(err))
+		}
+		return
+	}
+
+	err := lock.Run(ctx, w.locker, verificationLockKey, w.interval, verify)
+	switch {
+	case errors.Is(err, lock.ErrNotAcquired):
+		w.logger.Debug("verification run skipped, lock held by another instance")
+	case err != nil:
+		w.logger.Error("verification run failed", zap.Error(err))
+	}
+}
@@ -0,0 +1,100 @@
+package sales
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+type fixedRandom int
+
+func (f fixedRandom) IntN(n int) int { return int(f) % n }
+
+// TestProvisionalSales_VerifiedRejectedAndStuck verifica que una caída del
+// servicio de usuarios cree ventas provisorias y que el worker las promueva,
+// rechace o marque trabadas con backoff.
+func TestProvisionalSales_VerifiedRejectedAndStuck(t *testing.T) {
+	var down atomic.Bool
+	down.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case down.Load() || strings.HasSuffix(r.URL.Path, "/flaky"):
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case strings.HasSuffix(r.URL.Path, "/gone"):
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id": "x", "name": "Test User"}`))
+		}
+	}))
+	defer server.Close()
+
+	notifier := &recordingNotifier{}
+	svc := NewService(NewLocalStorage(), zaptest.NewLogger(t), server.URL,
+		WithRandom(fixedRandom(0)), WithNotifier(notifier),
+		WithProvisionalSales(2, time.Minute, time.Hour))
+
+	ok, err := svc.CreateSale("ok", 10)
+	if err != nil || ok.Status != StatusPendingVerification {
+		t.Fatalf("expected a provisional sale, got %+v, %v", ok, err)
+	}
+	gone, _ := svc.CreateSale("gone", 10)
+	flaky, _ := svc.CreateSale("flaky", 10)
+	down.Store(false)
+
+	now := utcNow()
+	resolved, err := svc.VerifyProvisionalSales(now)
+	if err != nil || resolved != 2 {
+		t.Fatalf("expected 2 resolved sales, got %d, %v", resolved, err)
+	}
+	if sale, _ := svc.storage.Read(ok.ID); sale.Status != StatusPending || sale.PendingSince == nil {
+		t.Errorf("expected the verified sale to be pending, got %+v", sale)
+	}
+	if sale, _ := svc.storage.Read(gone.ID); sale.Status != StatusRejected {
+		t.Errorf("expected the sale of a missing user to be rejected, got %s", sale.Status)
+	}
+
+	// Antes del backoff no se reintenta
+	if resolved, _ := svc.VerifyProvisionalSales(now.Add(30 * time.Second)); resolved != 0 {
+		t.Errorf("expected no retry before the backoff, got %d", resolved)
+	}
+	svc.VerifyProvisionalSales(now.Add(time.Minute))
+	stuck, err := svc.PendingVerifications(true)
+	if err != nil || len(stuck) != 1 || stuck[0].SaleID != flaky.ID || stuck[0].Attempts != 2 {
+		t.Fatalf("expected the flaky sale to be stuck after 2 attempts, got %+v, %v", stuck, err)
+	}
+
+	if _, err := svc.RetryVerification(ok.ID); err != ErrVerificationNotFound {
+		t.Errorf("expected ErrVerificationNotFound, got %v", err)
+	}
+	v, err := svc.RetryVerification(flaky.ID)
+	if err != nil || v.Stuck || v.Attempts != 0 {
+		t.Errorf("expected a fresh verification, got %+v, %v", v, err)
+	}
+
+	want := map[string]bool{
+		EventSaleVerified + ":" + ok.ID:             true,
+		EventSaleVerificationFailed + ":" + gone.ID: true,
+	}
+	for _, e := range notifier.events {
+		delete(want, e)
+	}
+	if len(want) != 0 {
+		t.Errorf("missing events %v in %v", want, notifier.events)
+	}
+}
+
+// TestRetryDelay_DoublesUpToMax verifica el backoff exponencial con tope.
+func TestRetryDelay_DoublesUpToMax(t *testing.T) {
+	svc := NewService(NewLocalStorage(), zaptest.NewLogger(t), "http://unused", WithProvisionalSales(0, time.Minute, 5*time.Minute))
+	for attempts, want := range map[int]time.Duration{1: time.Minute, 2: 2 * time.Minute, 3: 4 * time.Minute, 4: 5 * time.Minute, 20: 5 * time.Minute} {
+		if got := svc.retryDelay(attempts); got != want {
+			t.Errorf("retryDelay(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package sales
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Error para vocabularios de estados inválidos
+var ErrInvalidVocabulary = errors.New("invalid status vocabulary")
+
+// CustomStatus is a tenant-defined terminal status, e.g. written_off, that a
+// sale can move to from any of the statuses in From. From may name built-in
+// statuses other than draft and disputed, which are left only through their
+// own flows, or other custom statuses of the tenant; a custom status not
+// listed in any From is final.
+type CustomStatus struct {
+	Name string   `json:"name"`
+	From []string `json:"from"`
+}
+
+// VocabularyStorage persists the custom statuses of every tenant.
+type VocabularyStorage interface {
+	Set(tenant string, statuses []CustomStatus) error
+	Read(tenant string) ([]CustomStatus, error)
+	GetAll() (map[string][]CustomStatus, error)
+}
+
+type LocalVocabularyStorage struct {
+	mu sync.RWMutex
+	m  map[string][]CustomStatus
+}
+
+func NewLocalVocabularyStorage() *LocalVocabularyStorage {
+	return &LocalVocabularyStorage{
+		m: make(map[string][]CustomStatus),
+	}
+}
+
+func (l *LocalVocabularyStorage) Set(tenant string, statuses []CustomStatus) error {
+	if tenant == "" {
+		return ErrEmptyID
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(statuses) == 0 {
+		delete(l.m, tenant)
+		return nil
+	}
+	l.m[tenant] = slices.Clone(statuses)
+	return nil
+}
+
+// Read retorna los estados del tenant; sin vocabulario propio devuelve nil.
+func (l *LocalVocabularyStorage) Read(tenant string) ([]CustomStatus, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return slices.Clone(l.m[tenant]), nil
+}
+
+func (l *LocalVocabularyStorage) GetAll() (map[string][]CustomStatus, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	result := make(map[string][]CustomStatus, len(l.m))
+	for tenant, statuses := range l.m {
+		result[tenant] = slices.Clone(statuses)
+	}
+	return result, nil
+}
+
+// WithVocabularyStorage sets where tenant status vocabularies are kept.
+// Defaults to an in-memory LocalVocabularyStorage.
+func WithVocabularyStorage(storage VocabularyStorage) Option {
+	return func(s *Service) {
+		s.vocabularies = storage
+	}
+}
+
+// isBuiltinStatus indica si status es uno de los estados propios del servicio.
+func isBuiltinStatus(status string) bool {
+	switch status {
+	case StatusDraft, StatusPending, StatusApproved, StatusRejected, StatusDisputed, StatusChargedBack, StatusPendingVerification:
+		return true
+	}
+	return false
+}
+
+// validateVocabulary comprueba nombres únicos, que no pisen los estados
+// propios y que cada origen exista.
+func validateVocabulary(statuses []CustomStatus) error {
+	names := make(map[string]struct{}, len(statuses))
+	for _, st := range statuses {
+		if st.Name == "" || len(st.Name) > maxMetadataKeyLen {
+			return fmt.Errorf("%w: status name must have 1 to %d characters", ErrInvalidVocabulary, maxMetadataKeyLen)
+		}
+		if isBuiltinStatus(st.Name) {
+			return fmt.Errorf("%w: %q is a built-in status", ErrInvalidVocabulary, st.Name)
+		}
+		if _, dup := names[st.Name]; dup {
+			return fmt.Errorf("%w: duplicate status %q", ErrInvalidVocabulary, st.Name)
+		}
+		names[st.Name] = struct{}{}
+	}
+	for _, st := range statuses {
+		if len(st.From) == 0 {
+			return fmt.Errorf("%w: %q needs at least one status in from", ErrInvalidVocabulary, st.Name)
+		}
+		for _, from := range st.From {
+			_, custom := names[from]
+			// Los borradores, las disputas y las ventas provisorias solo salen por sus propios flujos
+			if from == st.Name || from == StatusDraft || from == StatusDisputed || from == StatusPendingVerification || (!custom && !isBuiltinStatus(from)) {
+				return fmt.Errorf("%w: %q cannot be reached from %q", ErrInvalidVocabulary, st.Name, from)
+			}
+		}
+	}
+	return nil
+}
+
+// SetTenantStatuses replaces the custom statuses of tenant. An empty list
+// removes them; sales already in a removed status keep it.
+func (s *Service) SetTenantStatuses(tenant string, statuses []CustomStatus) error {
+	if tenant == "" {
+		return fmt.Errorf("%w: tenant is required", ErrInvalidVocabulary)
+	}
+	if err := validateVocabulary(statuses); err != nil {
+		return err
+	}
+	return s.vocabularies.Set(tenant, statuses)
+}
+
+// TenantStatuses returns the custom statuses of tenant.
+func (s *Service) TenantStatuses(tenant string) ([]CustomStatus, error) {
+	return s.vocabularies.Read(tenant)
+}
+
+// customStatus busca name en el vocabulario del tenant.
+func (s *Service) customStatus(tenant, name string) (CustomStatus, bool, error) {
+	if tenant == "" {
+		return CustomStatus{}, false, nil
+	}
+	statuses, err := s.vocabularies.Read(tenant)
+	if err != nil {
+		return CustomStatus{}, false, err
+	}
+	for _, st := range statuses {
+		if st.Name == name {
+			return st, true, nil
+		}
+	}
+	return CustomStatus{}, false, nil
+}
+
+// knownStatus indica si status es propio o lo define algún tenant; se usa
+// para validar filtros de búsqueda.
+func (s *Service) knownStatus(status string) bool {
+	if isBuiltinStatus(status) {
+		return true
+	}
+	all, err := s.vocabularies.GetAll()
+	if err != nil {
+		s.logger.Warn("failed to read status vocabularies", zap.Error(err))
+		return false
+	}
+	for _, statuses := range all {
+		for _, st := range statuses {
+			if st.Name == status {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package sales
+
+import (
+	"errors"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// TestCustomStatus_TransitionsPerTenant verifica que los estados propios solo
+// apliquen a las ventas del tenant y desde los estados configurados.
+func TestCustomStatus_TransitionsPerTenant(t *testing.T) {
+	storage := NewLocalStorage()
+	svc := NewService(storage, zaptest.NewLogger(t), "http://unused")
+	err := svc.SetTenantStatuses("acme", []CustomStatus{
+		{Name: "escalated", From: []string{StatusApproved}},
+		{Name: "written_off", From: []string{StatusApproved, "escalated"}},
+	})
+	if err != nil {
+		t.Fatalf("SetTenantStatuses returned error: %v", err)
+	}
+
+	storage.Set(&Sale{ID: "acme-1", TenantID: "acme", Status: StatusApproved, Amount: 10})
+	storage.Set(&Sale{ID: "acme-2", TenantID: "acme", Status: StatusPending, Amount: 10})
+	storage.Set(&Sale{ID: "other-1", TenantID: "other", Status: StatusApproved, Amount: 10})
+
+	if _, err := svc.UpdateSaleStatus("acme-1", "escalated"); err != nil {
+		t.Fatalf("expected approved -> escalated, got %v", err)
+	}
+	if _, err := svc.UpdateSaleStatus("acme-1", "written_off"); err != nil {
+		t.Fatalf("expected escalated -> written_off, got %v", err)
+	}
+	// written_off es final: no figura en ningún from
+	if _, err := svc.UpdateSaleStatus("acme-1", "escalated"); err != ErrInvalidTransition {
+		t.Errorf("expected ErrInvalidTransition out of a final status, got %v", err)
+	}
+	if _, err := svc.UpdateSaleStatus("acme-2", "written_off"); err != ErrInvalidTransition {
+		t.Errorf("expected ErrInvalidTransition from pending, got %v", err)
+	}
+	if _, err := svc.UpdateSaleStatus("other-1", "written_off"); err != ErrInvalidStatus {
+		t.Errorf("expected ErrInvalidStatus for another tenant, got %v", err)
+	}
+
+	results, metadata, err := svc.SearchSale(SearchFilter{Status: "written_off"})
+	if err != nil {
+		t.Fatalf("SearchSale returned error: %v", err)
+	}
+	if len(results) != 1 || metadata.ByStatus["written_off"] != 1 {
+		t.Errorf("expected the written off sale counted, got %d results and %v", len(results), metadata.ByStatus)
+	}
+}
+
+func TestSetTenantStatuses_RejectsInvalidVocabulary(t *testing.T) {
+	svc := NewService(NewLocalStorage(), zaptest.NewLogger(t), "http://unused")
+
+	cases := map[string][]CustomStatus{
+		"builtin name":   {{Name: StatusApproved, From: []string{StatusPending}}},
+		"duplicate":      {{Name: "x", From: []string{StatusApproved}}, {Name: "x", From: []string{StatusApproved}}},
+		"no from":        {{Name: "x"}},
+		"unknown from":   {{Name: "x", From: []string{"bogus"}}},
+		"from draft":     {{Name: "x", From: []string{StatusDraft}}},
+		"from disputed":  {{Name: "x", From: []string{StatusDisputed}}},
+		"self reference": {{Name: "x", From: []string{"x"}}},
+	}
+	for name, statuses := range cases {
+		if err := svc.SetTenantStatuses("acme", statuses); !errors.Is(err, ErrInvalidVocabulary) {
+			t.Errorf("%s: expected ErrInvalidVocabulary, got %v", name, err)
+		}
+	}
+}
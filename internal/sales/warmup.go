@@ -0,0 +1,103 @@
+package sales
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// WarmUpOptions bounds the work done by WarmUp.
+type WarmUpOptions struct {
+	// MaxUsers caps how many distinct users are preloaded into the user cache.
+	MaxUsers int
+	// Concurrency is the number of parallel calls to the user service.
+	Concurrency int
+}
+
+// WarmUpResult summarizes a warm-up run.
+type WarmUpResult struct {
+	UsersLoaded int           `json:"users_loaded"`
+	UsersFailed int           `json:"users_failed"`
+	Sales       int           `json:"sales"`
+	Duration    time.Duration `json:"duration"`
+}
+
+// WarmUp preloads aggregate statistics and the user cache from storage so the
+// first requests after a deploy don't hit cold paths. It stops early when ctx
+// is cancelled.
+func (s *Service) WarmUp(ctx context.Context, opts WarmUpOptions) (WarmUpResult, error) {
+	start := time.Now()
+	result := WarmUpResult{}
+
+	metadata, err := s.refreshStats()
+	if err != nil {
+		return result, err
+	}
+	result.Sales = metadata.Quantity
+
+	allSales, err := s.storage.GetAll()
+	if err != nil {
+		return result, err
+	}
+
+	// Usuarios distintos, hasta MaxUsers
+	seen := map[string]bool{}
+	userIDs := make([]string, 0)
+	for _, sale := range allSales {
+		if sale.UserID == "" || seen[sale.UserID] {
+			continue
+		}
+		if opts.MaxUsers > 0 && len(userIDs) >= opts.MaxUsers {
+			break
+		}
+		seen[sale.UserID] = true
+		userIDs = append(userIDs, sale.UserID)
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan string)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for userID := range jobs {
+				_, err := s.userClient.GetUserByID(userID)
+				mu.Lock()
+				if err != nil {
+					result.UsersFailed++
+				} else {
+					result.UsersLoaded++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, userID := range userIDs {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- userID:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	result.Duration = time.Since(start)
+	s.logger.Info("cache warm-up completed",
+		zap.Int("sales", result.Sales),
+		zap.Int("users_loaded", result.UsersLoaded),
+		zap.Int("users_failed", result.UsersFailed),
+		zap.Duration("duration", result.Duration),
+	)
+	return result, ctx.Err()
+}
@@ -0,0 +1,103 @@
+package sales
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// TestWarmUp_PreloadsUsersAndStats verifica que luego del warm-up no se llama al servicio de usuarios.
+func TestWarmUp_PreloadsUsersAndStats(t *testing.T) {
+	var calls atomic.Int32
+	mockUserServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "x", "name": "Test User"}`))
+	}))
+	defer mockUserServer.Close()
+
+	storage := NewLocalStorage()
+	for i, userID := range []string{"u1", "u2", "u1", "u3"} {
+		storage.Set(&Sale{ID: string(rune('a' + i)), UserID: userID, Amount: 10, Status: StatusApproved})
+	}
+
+	svc := NewService(storage, zaptest.NewLogger(t), mockUserServer.URL, WithUserCache(NewLocalUserCache(time.Minute)))
+
+	result, err := svc.WarmUp(context.Background(), WarmUpOptions{MaxUsers: 10, Concurrency: 2})
+	if err != nil {
+		t.Fatalf("WarmUp returned error: %v", err)
+	}
+	if result.UsersLoaded != 3 || result.Sales != 4 {
+		t.Errorf("unexpected warm-up result: %+v", result)
+	}
+
+	calls.Store(0)
+	stats, err := svc.GetStats()
+	if err != nil {
+		t.Fatalf("GetStats returned error: %v", err)
+	}
+	if stats.Quantity != 4 || stats.Approved != 4 || stats.TotalAmount != 40 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if err := svc.verifyUser("u1"); err != nil {
+		t.Fatalf("verifyUser returned error: %v", err)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("expected cached user lookup, got %d upstream calls", calls.Load())
+	}
+}
+
+// invalidatingStorage simula una escritura concurrente durante el cálculo de las estadísticas.
+type invalidatingStorage struct {
+	Storage
+	svc  *Service
+	once bool
+}
+
+func (s *invalidatingStorage) GetAll() ([]*Sale, error) {
+	all, err := s.Storage.GetAll()
+	if s.svc != nil && !s.once {
+		s.once = true
+		s.Storage.Set(&Sale{ID: "late", UserID: "u1", Amount: 5, Status: StatusApproved})
+		s.svc.stats.invalidate()
+	}
+	return all, err
+}
+
+func TestGetStats_DiscardsResultsComputedAcrossAWrite(t *testing.T) {
+	storage := &invalidatingStorage{Storage: NewLocalStorage()}
+	storage.Storage.Set(&Sale{ID: "a", UserID: "u1", Amount: 10, Status: StatusApproved})
+	svc := NewService(storage, zaptest.NewLogger(t), "http://unused", WithStatsTTL(time.Hour))
+	storage.svc = svc
+
+	first, err := svc.GetStats()
+	if err != nil {
+		t.Fatalf("GetStats returned error: %v", err)
+	}
+	if first.Quantity != 1 {
+		t.Errorf("expected the stats read before the write, got %+v", first)
+	}
+	second, err := svc.GetStats()
+	if err != nil {
+		t.Fatalf("GetStats returned error: %v", err)
+	}
+	if second.Quantity != 2 {
+		t.Errorf("expected the stale result not cached, got %+v", second)
+	}
+
+	svc.stats.ttl = time.Nanosecond
+	storage.Storage.Set(&Sale{ID: "remote", UserID: "u2", Amount: 1, Status: StatusApproved})
+	time.Sleep(time.Millisecond)
+	third, err := svc.GetStats()
+	if err != nil {
+		t.Fatalf("GetStats returned error: %v", err)
+	}
+	if third.Quantity != 3 {
+		t.Errorf("expected the write of another instance within the ttl, got %+v", third)
+	}
+}
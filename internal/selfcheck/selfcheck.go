@@ -0,0 +1,101 @@
+// Package selfcheck runs the startup diagnostics of the API and builds a
+// readiness report, so misconfiguration is caught at boot with an actionable
+// message.
+package selfcheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	StatusOK      = "ok"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// ErrSkipped marks a check that doesn't apply to the current configuration.
+var ErrSkipped = errors.New("skipped")
+
+// Check is a single startup diagnostic. Hint tells the operator how to fix it
+// when Run fails.
+type Check struct {
+	Name string
+	Hint string
+	Run  func(ctx context.Context) error
+}
+
+// Result is the outcome of a check.
+type Result struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Hint     string `json:"hint,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report is the readiness report printed at startup.
+type Report struct {
+	Ready  bool     `json:"ready"`
+	Checks []Result `json:"checks"`
+}
+
+// Run executes the checks in order, each with its own timeout.
+func Run(ctx context.Context, timeout time.Duration, checks []Check) Report {
+	report := Report{Ready: true, Checks: make([]Result, 0, len(checks))}
+	for _, check := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		start := time.Now()
+		err := check.Run(checkCtx)
+		cancel()
+
+		result := Result{Name: check.Name, Status: StatusOK, Duration: time.Since(start).Round(time.Millisecond).String()}
+		switch {
+		case errors.Is(err, ErrSkipped):
+			result.Status = StatusSkipped
+			result.Error = err.Error()
+		case err != nil:
+			result.Status = StatusFailed
+			result.Error = err.Error()
+			result.Hint = check.Hint
+			report.Ready = false
+		}
+		report.Checks = append(report.Checks, result)
+	}
+	return report
+}
+
+// Write prints the report as indented JSON.
+func (r Report) Write(w io.Writer) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// Reachable checks that url answers HTTP. Any response below 500 counts, as
+// the endpoint may require a path or credentials the check doesn't send.
+func Reachable(client *http.Client, url string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,38 @@
+package selfcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestRun_ReportsFailuresWithHints verifica el reporte de readiness.
+func TestRun_ReportsFailuresWithHints(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	report := Run(context.Background(), time.Second, []Check{
+		{Name: "reachable", Run: Reachable(srv.Client(), srv.URL)},
+		{Name: "optional", Run: func(context.Context) error { return fmt.Errorf("%w: not configured", ErrSkipped) }},
+		{Name: "broken", Hint: "fix it", Run: func(context.Context) error { return errors.New("boom") }},
+	})
+
+	if report.Ready {
+		t.Fatal("expected report not ready")
+	}
+	want := []string{StatusOK, StatusSkipped, StatusFailed}
+	for i, result := range report.Checks {
+		if result.Status != want[i] {
+			t.Errorf("check %s: expected %s, got %s", result.Name, want[i], result.Status)
+		}
+	}
+	if report.Checks[2].Hint != "fix it" || report.Checks[1].Hint != "" {
+		t.Errorf("expected hint only on failed checks, got %+v", report.Checks)
+	}
+}
@@ -0,0 +1,39 @@
+package shipping
+
+import (
+	"testing"
+	"time"
+)
+
+// TestVerify verifica la firma, su frescura y que cubra el cuerpo exacto.
+func TestVerify(t *testing.T) {
+	body := []byte(`{"reference":"s1","status":"delivered"}`)
+	now := time.Unix(1717171717, 0)
+	header := Sign("secret", now, body)
+
+	if err := Verify("secret", header, body, now.Add(time.Minute), 5*time.Minute); err != nil {
+		t.Errorf("expected a valid signature, got %v", err)
+	}
+	if err := Verify("other", header, body, now, 5*time.Minute); err != ErrInvalidSignature {
+		t.Errorf("expected ErrInvalidSignature for the wrong secret, got %v", err)
+	}
+	if err := Verify("secret", header, []byte(`{"reference":"s2","status":"delivered"}`), now, 5*time.Minute); err != ErrInvalidSignature {
+		t.Errorf("expected ErrInvalidSignature for a tampered body, got %v", err)
+	}
+	if err := Verify("secret", header, body, now.Add(time.Hour), 5*time.Minute); err != ErrStaleSignature {
+		t.Errorf("expected ErrStaleSignature, got %v", err)
+	}
+	if err := Verify("secret", "", body, now, 5*time.Minute); err != ErrInvalidSignature {
+		t.Errorf("expected ErrInvalidSignature without header, got %v", err)
+	}
+}
+
+func TestEvent_FulfillmentStatus(t *testing.T) {
+	cases := map[string]string{"in_transit": "shipped", "DELIVERED": "delivered", "label_created": ""}
+	for carrier, want := range cases {
+		got, ok := Event{Status: carrier}.FulfillmentStatus()
+		if got != want || ok != (want != "") {
+			t.Errorf("%s: expected %q, got %q (ok=%v)", carrier, want, got, ok)
+		}
+	}
+}
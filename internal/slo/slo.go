@@ -0,0 +1,324 @@
+// Package slo tracks per-endpoint service level objectives, e.g. 99% of
+// POST /sales answered under 300ms, and alerts when an error budget burns
+// too fast. Each instance tracks the requests it serves; the
+// slo_requests_total counter carries the same good/bad split to the metrics
+// pipeline for fleet-wide burn rates.
+package slo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var sloRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "slo_requests_total",
+	Help: "Requests counted by each SLO, by whether they met it.",
+}, []string{"slo", "result"})
+
+var sloBudgetRemaining = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "slo_error_budget_remaining",
+	Help: "Fraction of the error budget left in the SLO window; negative once exhausted.",
+}, []string{"slo"})
+
+// Alert severities.
+const (
+	SeverityPage   = "page"
+	SeverityTicket = "ticket"
+)
+
+// burnPolicy alerta cuando la ventana larga y la corta superan el mismo
+// burn rate: la larga evita alertas por picos y la corta que la alerta siga
+// activa cuando el problema ya pasó. Umbrales del SRE workbook para 30 días.
+type burnPolicy struct {
+	severity string
+	long     time.Duration
+	short    time.Duration
+	rate     float64
+}
+
+var burnPolicies = []burnPolicy{
+	// 2% del presupuesto en una hora
+	{SeverityPage, time.Hour, 5 * time.Minute, 14.4},
+	// 5% del presupuesto en seis horas
+	{SeverityTicket, 6 * time.Hour, 30 * time.Minute, 6},
+}
+
+// Objective is an SLO on one route: Target of the requests must not fail
+// with a 5xx and, when LatencyMS is set, must be answered within it.
+type Objective struct {
+	Name      string  `json:"name"`
+	Method    string  `json:"method"`
+	Route     string  `json:"route"`
+	Target    float64 `json:"target"`
+	LatencyMS int     `json:"latency_ms,omitempty"`
+}
+
+// Validate checks the fields of o.
+func (o Objective) Validate() error {
+	if o.Name == "" || o.Method == "" || o.Route == "" {
+		return fmt.Errorf("slo: name, method and route are required")
+	}
+	if o.Target <= 0 || o.Target >= 1 {
+		return fmt.Errorf("slo %s: target must be between 0 and 1, exclusive", o.Name)
+	}
+	if o.LatencyMS < 0 {
+		return fmt.Errorf("slo %s: latency_ms must not be negative", o.Name)
+	}
+	return nil
+}
+
+// Status is the state of an SLO served on GET /admin/slo.
+type Status struct {
+	Objective
+	Window string `json:"window"`
+	Total  int64  `json:"total"`
+	Good   int64  `json:"good"`
+	// SLI is the fraction of good requests in the window, 1 without requests.
+	SLI float64 `json:"sli"`
+	// BudgetRemaining is the fraction of the error budget left in the window;
+	// negative once it is exhausted.
+	BudgetRemaining float64 `json:"budget_remaining"`
+	// BurnRates is how fast the budget burns over each alert window, where 1
+	// spends exactly the budget over the SLO window.
+	BurnRates map[string]float64 `json:"burn_rates"`
+	// Alerting is the severity of the firing alert, if any.
+	Alerting string `json:"alerting,omitempty"`
+}
+
+// Alert is raised when an SLO's budget burns faster than a policy allows.
+type Alert struct {
+	Name            string
+	Severity        string
+	Window          string
+	BurnRate        float64
+	BudgetRemaining float64
+}
+
+// BudgetPercent is BudgetRemaining as a percentage, for alert templates.
+func (a Alert) BudgetPercent() float64 {
+	return a.BudgetRemaining * 100
+}
+
+// bucket cuenta los requests de un minuto.
+type bucket struct {
+	minute int64
+	good   int64
+	total  int64
+}
+
+type tracked struct {
+	Objective
+	buckets  []bucket
+	alerting string
+}
+
+func (t *tracked) observe(minute int64, good bool) {
+	b := &t.buckets[minute%int64(len(t.buckets))]
+	if b.minute != minute {
+		*b = bucket{minute: minute}
+	}
+	b.total++
+	if good {
+		b.good++
+	}
+}
+
+// sum suma los minutos de la última ventana d hasta minute inclusive.
+func (t *tracked) sum(minute int64, d time.Duration) (good, total int64) {
+	n := min(int64(d/time.Minute), int64(len(t.buckets)))
+	for m := minute - n + 1; m <= minute; m++ {
+		if b := t.buckets[m%int64(len(t.buckets))]; b.minute == m {
+			good += b.good
+			total += b.total
+		}
+	}
+	return good, total
+}
+
+// burnRate es la tasa de error de la ventana sobre la permitida.
+func (t *tracked) burnRate(minute int64, d time.Duration) float64 {
+	good, total := t.sum(minute, d)
+	if total == 0 {
+		return 0
+	}
+	return float64(total-good) / float64(total) / (1 - t.Target)
+}
+
+// Tracker counts the requests of every objective in one-minute buckets over
+// the SLO window.
+type Tracker struct {
+	window  time.Duration
+	logger  *zap.Logger
+	alerter func(Alert)
+
+	mu      sync.Mutex
+	byRoute map[string][]*tracked
+	all     []*tracked
+	now     func() time.Time
+}
+
+// NewTracker tracks objectives over window, e.g. 30 days.
+func NewTracker(objectives []Objective, window time.Duration, logger *zap.Logger) (*Tracker, error) {
+	if window < 6*time.Hour {
+		return nil, fmt.Errorf("slo window must cover the 6h alert window")
+	}
+	t := &Tracker{window: window, logger: logger, byRoute: map[string][]*tracked{}, now: time.Now}
+	seen := map[string]bool{}
+	for _, o := range objectives {
+		if err := o.Validate(); err != nil {
+			return nil, err
+		}
+		if seen[o.Name] {
+			return nil, fmt.Errorf("slo %s defined twice", o.Name)
+		}
+		seen[o.Name] = true
+		tr := &tracked{Objective: o, buckets: make([]bucket, window/time.Minute)}
+		key := o.Method + " " + o.Route
+		t.byRoute[key] = append(t.byRoute[key], tr)
+		t.all = append(t.all, tr)
+	}
+	return t, nil
+}
+
+// SetAlerter makes Check call fn when an SLO starts burning too fast, or
+// escalates from ticket to page. Alerts are always logged.
+func (t *Tracker) SetAlerter(fn func(Alert)) {
+	t.alerter = fn
+}
+
+// Observe counts a request to route against the objectives defined for it.
+func (t *Tracker) Observe(method, route string, status int, latency time.Duration) {
+	objectives := t.byRoute[method+" "+route]
+	if len(objectives) == 0 {
+		return
+	}
+	minute := t.now().Unix() / 60
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, o := range objectives {
+		good := status < 500 && (o.LatencyMS == 0 || latency <= time.Duration(o.LatencyMS)*time.Millisecond)
+		o.observe(minute, good)
+		result := "good"
+		if !good {
+			result = "bad"
+		}
+		sloRequests.WithLabelValues(o.Name, result).Inc()
+	}
+}
+
+// Status returns the state of every objective, in configuration order.
+func (t *Tracker) Status() []Status {
+	minute := t.now().Unix() / 60
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	statuses := make([]Status, 0, len(t.all))
+	for _, o := range t.all {
+		statuses = append(statuses, t.status(o, minute))
+	}
+	return statuses
+}
+
+func (t *Tracker) status(o *tracked, minute int64) Status {
+	good, total := o.sum(minute, t.window)
+	s := Status{
+		Objective:       o.Objective,
+		Window:          t.window.String(),
+		Total:           total,
+		Good:            good,
+		SLI:             1,
+		BudgetRemaining: 1,
+		BurnRates:       map[string]float64{},
+		Alerting:        o.alerting,
+	}
+	if total > 0 {
+		s.SLI = float64(good) / float64(total)
+		s.BudgetRemaining = 1 - float64(total-good)/(float64(total)*(1-o.Target))
+	}
+	for _, p := range burnPolicies {
+		s.BurnRates[shortDuration(p.long)] = o.burnRate(minute, p.long)
+		s.BurnRates[shortDuration(p.short)] = o.burnRate(minute, p.short)
+	}
+	return s
+}
+
+// Check evaluates the burn-rate policies of every objective and alerts on
+// the ones that started firing.
+func (t *Tracker) Check() {
+	minute := t.now().Unix() / 60
+
+	t.mu.Lock()
+	var alerts []Alert
+	for _, o := range t.all {
+		status := t.status(o, minute)
+		sloBudgetRemaining.WithLabelValues(o.Name).Set(status.BudgetRemaining)
+
+		severity, policy := "", burnPolicy{}
+		for _, p := range burnPolicies {
+			if o.burnRate(minute, p.long) > p.rate && o.burnRate(minute, p.short) > p.rate {
+				severity, policy = p.severity, p
+				break
+			}
+		}
+		if severity == o.alerting {
+			continue
+		}
+		if severity == "" {
+			t.logger.Info("slo burn rate back within budget", zap.String("slo", o.Name))
+		} else if o.alerting != SeverityPage {
+			// Pasar de page a ticket no vuelve a alertar: sigue quemando
+			alerts = append(alerts, Alert{
+				Name:            o.Name,
+				Severity:        severity,
+				Window:          shortDuration(policy.long),
+				BurnRate:        o.burnRate(minute, policy.long),
+				BudgetRemaining: status.BudgetRemaining,
+			})
+		}
+		o.alerting = severity
+	}
+	t.mu.Unlock()
+
+	for _, a := range alerts {
+		t.logger.Warn("slo error budget burning too fast",
+			zap.String("slo", a.Name),
+			zap.String("severity", a.Severity),
+			zap.String("window", a.Window),
+			zap.Float64("burn_rate", a.BurnRate),
+			zap.Float64("budget_remaining", a.BudgetRemaining),
+		)
+		if t.alerter != nil {
+			t.alerter(a)
+		}
+	}
+}
+
+// Start runs Check every interval until ctx is done.
+func (t *Tracker) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.Check()
+		}
+	}
+}
+
+// shortDuration formatea las ventanas como 5m, 1h o 6h.
+func shortDuration(d time.Duration) string {
+	if d%time.Hour == 0 {
+		return fmt.Sprintf("%dh", d/time.Hour)
+	}
+	return fmt.Sprintf("%dm", d/time.Minute)
+}
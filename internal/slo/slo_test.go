@@ -0,0 +1,107 @@
+package slo
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func newTestTracker(t *testing.T, now *time.Time) *Tracker {
+	tracker, err := NewTracker([]Objective{
+		{Name: "create_sale", Method: http.MethodPost, Route: "/sales", Target: 0.99, LatencyMS: 300},
+	}, 30*24*time.Hour, zaptest.NewLogger(t))
+	if err != nil {
+		t.Fatalf("NewTracker returned error: %v", err)
+	}
+	tracker.now = func() time.Time { return *now }
+	return tracker
+}
+
+func TestTracker_CountsSlowAndFailedRequestsAgainstTheBudget(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tracker := newTestTracker(t, &now)
+
+	for i := 0; i < 97; i++ {
+		tracker.Observe(http.MethodPost, "/sales", http.StatusCreated, 100*time.Millisecond)
+	}
+	tracker.Observe(http.MethodPost, "/sales", http.StatusBadRequest, 10*time.Millisecond)
+	tracker.Observe(http.MethodPost, "/sales", http.StatusCreated, time.Second)
+	tracker.Observe(http.MethodPost, "/sales", http.StatusServiceUnavailable, 10*time.Millisecond)
+	// Otras rutas no cuentan
+	tracker.Observe(http.MethodGet, "/sales", http.StatusInternalServerError, time.Second)
+
+	status := tracker.Status()[0]
+	if status.Total != 100 || status.Good != 98 {
+		t.Fatalf("expected 98 of 100 good requests, got %+v", status)
+	}
+	// 2 malos contra un presupuesto de 1: agotado al doble
+	if status.BudgetRemaining > -0.99 || status.BudgetRemaining < -1.01 {
+		t.Errorf("expected the budget overspent by 100%%, got %v", status.BudgetRemaining)
+	}
+	if rate := status.BurnRates["5m"]; rate < 1.99 || rate > 2.01 {
+		t.Errorf("expected a 2x burn rate, got %v", status.BurnRates)
+	}
+
+	// Fuera de la ventana corta ya no cuentan
+	now = now.Add(10 * time.Minute)
+	if status := tracker.Status()[0]; status.BurnRates["5m"] != 0 || status.Total != 100 {
+		t.Errorf("expected only the SLO window to keep the requests, got %+v", status)
+	}
+}
+
+func TestTracker_AlertsOncePerSeverity(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tracker := newTestTracker(t, &now)
+	var alerts []Alert
+	tracker.SetAlerter(func(a Alert) { alerts = append(alerts, a) })
+
+	// Una hora con 20% de errores quema a 20x: page
+	for minute := 0; minute < 60; minute++ {
+		for i := 0; i < 10; i++ {
+			code := http.StatusCreated
+			if i < 2 {
+				code = http.StatusInternalServerError
+			}
+			tracker.Observe(http.MethodPost, "/sales", code, time.Millisecond)
+		}
+		now = now.Add(time.Minute)
+	}
+	now = now.Add(-time.Minute)
+	tracker.Check()
+	tracker.Check()
+	if len(alerts) != 1 || alerts[0].Severity != SeverityPage || alerts[0].Window != "1h" {
+		t.Fatalf("expected a single page, got %+v", alerts)
+	}
+	if tracker.Status()[0].Alerting != SeverityPage {
+		t.Errorf("expected the status to report the page")
+	}
+
+	// Bajar de page a ticket no vuelve a alertar
+	now = now.Add(10 * time.Minute)
+	tracker.Check()
+	if len(alerts) != 1 || tracker.Status()[0].Alerting != SeverityTicket {
+		t.Errorf("expected the page downgraded to a ticket silently, got %+v", alerts)
+	}
+
+	// Sin errores en las ventanas cortas la alerta se apaga
+	now = now.Add(30 * time.Minute)
+	tracker.Observe(http.MethodPost, "/sales", http.StatusCreated, time.Millisecond)
+	tracker.Check()
+	if len(alerts) != 1 || tracker.Status()[0].Alerting != "" {
+		t.Errorf("expected the alert resolved without a new one, got %+v", alerts)
+	}
+}
+
+func TestNewTracker_RejectsInvalidObjectives(t *testing.T) {
+	for _, objectives := range [][]Objective{
+		{{Name: "a", Method: "GET", Route: "/sales", Target: 1}},
+		{{Name: "a", Route: "/sales", Target: 0.9}},
+		{{Name: "a", Method: "GET", Route: "/sales", Target: 0.9}, {Name: "a", Method: "POST", Route: "/sales", Target: 0.9}},
+	} {
+		if _, err := NewTracker(objectives, 24*time.Hour, zaptest.NewLogger(t)); err == nil {
+			t.Errorf("expected %+v to be rejected", objectives)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+// Package sms alerts buyers by text message when their high-value sales are
+// approved.
+package sms
+
+import (
+	"api_sales/internal/dispatch"
+	"api_sales/internal/sales"
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"resty.dev/v3"
+)
+
+// Provider sends a single text message.
+type Provider interface {
+	Send(ctx context.Context, to, body string) error
+}
+
+// DefaultTwilioURL is the Twilio API base URL. Compatible providers can be
+// used by pointing TwilioProvider at their own base URL.
+const DefaultTwilioURL = "https://api.twilio.com"
+
+// TwilioProvider sends messages through the Twilio Messages API.
+type TwilioProvider struct {
+	accountSID string
+	from       string
+	client     *resty.Client
+}
+
+// NewTwilioProvider creates a provider authenticated with accountSID and
+// authToken that sends from the given number. An empty baseURL uses
+// DefaultTwilioURL.
+func NewTwilioProvider(baseURL, accountSID, authToken, from string) *TwilioProvider {
+	if baseURL == "" {
+		baseURL = DefaultTwilioURL
+	}
+
+	client := resty.New().
+		SetBaseURL(baseURL).
+		SetBasicAuth(accountSID, authToken).
+		SetTimeout(10 * time.Second).
+		SetRetryCount(2).
+		SetRetryWaitTime(500 * time.Millisecond)
+
+	return &TwilioProvider{
+		accountSID: accountSID,
+		from:       from,
+		client:     client,
+	}
+}
+
+// SetTransport sends messages through rt, e.g. to apply proxy and TLS settings.
+func (p *TwilioProvider) SetTransport(rt http.RoundTripper) {
+	p.client.SetTransport(rt)
+}
+
+func (p *TwilioProvider) Send(ctx context.Context, to, body string) error {
+	resp, err := p.client.R().
+		SetContext(ctx).
+		SetFormData(map[string]string{
+			"To":   to,
+			"From": p.from,
+			"Body": body,
+		}).
+		Post("/2010-04-01/Accounts/" + p.accountSID + "/Messages.json")
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return fmt.Errorf("sms provider returned status %d", resp.StatusCode())
+	}
+	return nil
+}
+
+// OptOutStore records the users that don't want SMS alerts.
+type OptOutStore interface {
+	OptOut(userID string) error
+	OptIn(userID string) error
+	OptedOut(userID string) (bool, error)
+}
+
+// MemoryOptOutStore keeps opt-outs in process memory. It is only correct for a
+// single API instance.
+type MemoryOptOutStore struct {
+	mu    sync.RWMutex
+	users map[string]struct{}
+}
+
+func NewMemoryOptOutStore() *MemoryOptOutStore {
+	return &MemoryOptOutStore{
+		users: map[string]struct{}{},
+	}
+}
+
+func (m *MemoryOptOutStore) OptOut(userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.users[userID] = struct{}{}
+	return nil
+}
+
+func (m *MemoryOptOutStore) OptIn(userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.users, userID)
+	return nil
+}
+
+func (m *MemoryOptOutStore) OptedOut(userID string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.users[userID]
+	return ok, nil
+}
+
+// UserLookup resolves the buyer of a sale.
+type UserLookup interface {
+	GetUser(userID string) (*sales.User, error)
+}
+
+// Alerter implements sales.Notifier, texting the buyer when a sale at or above
+// the threshold is approved.
+type Alerter struct {
+	provider  Provider
+	optOuts   OptOutStore
+	users     UserLookup
+	threshold float64
+	pool      *dispatch.Pool
+	logger    *zap.Logger
+}
+
+// NewAlerter creates an alerter that sends through provider on pool.
+func NewAlerter(provider Provider, optOuts OptOutStore, users UserLookup, threshold float64, pool *dispatch.Pool, logger *zap.Logger) *Alerter {
+	if logger == nil {
+		logger, _ = zap.NewProduction()
+	}
+
+	return &Alerter{
+		provider:  provider,
+		optOuts:   optOuts,
+		users:     users,
+		threshold: threshold,
+		pool:      pool,
+		logger:    logger,
+	}
+}
+
+// Notify queues an alert for approved high-value sales.
+func (a *Alerter) Notify(eventType string, sale *sales.Sale) {
+	if eventType != sales.EventSaleCreated && eventType != sales.EventSaleStatusChanged {
+		return
+	}
+	if sale.Status != sales.StatusApproved || sale.Amount < a.threshold {
+		return
+	}
+
+	err := a.pool.Submit(func(ctx context.Context) {
+		if err := a.alert(ctx, sale); err != nil {
+			a.logger.Error("sms alert failed", zap.String("sale_id", sale.ID), zap.Error(err))
+		}
+	})
+	if err != nil {
+		a.logger.Warn("sms alert dropped", zap.String("sale_id", sale.ID), zap.Error(err))
+	}
+}
+
+func (a *Alerter) alert(ctx context.Context, sale *sales.Sale) error {
+	optedOut, err := a.optOuts.OptedOut(sale.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to check opt-out: %w", err)
+	}
+	if optedOut {
+		return nil
+	}
+
+	user, err := a.users.GetUser(sale.UserID)
+	if err != nil {
+		return err
+	}
+	// Sin teléfono no hay a quién avisar
+	if user.Phone == "" {
+		return nil
+	}
+
+	body := fmt.Sprintf("Your purchase %s for %.2f has been approved.", sale.ID, sale.Amount)
+	return a.provider.Send(ctx, user.Phone, body)
+}
@@ -0,0 +1,58 @@
+package sms
+
+import (
+	"api_sales/internal/dispatch"
+	"api_sales/internal/sales"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type staticUsers map[string]*sales.User
+
+func (u staticUsers) GetUser(userID string) (*sales.User, error) {
+	return u[userID], nil
+}
+
+// TestAlerter_RespectsThresholdAndOptOut verifica que solo se avisa a compradores sin opt-out.
+func TestAlerter_RespectsThresholdAndOptOut(t *testing.T) {
+	var mu sync.Mutex
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, _, ok := r.BasicAuth(); !ok || user != "AC123" {
+			t.Errorf("expected basic auth with the account SID")
+		}
+		r.ParseForm()
+		mu.Lock()
+		sent = append(sent, r.PostForm.Get("To"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	optOuts := NewMemoryOptOutStore()
+	optOuts.OptOut("quiet")
+	users := staticUsers{
+		"buyer": {ID: "buyer", Phone: "+5491100000000"},
+		"quiet": {ID: "quiet", Phone: "+5491100000001"},
+	}
+	pool := dispatch.NewPool("test-sms", 1, 10, nil)
+	alerter := NewAlerter(NewTwilioProvider(server.URL, "AC123", "token", "+100"), optOuts, users, 1000, pool, nil)
+
+	alerter.Notify(sales.EventSaleStatusChanged, &sales.Sale{ID: "s1", UserID: "buyer", Amount: 1500, Status: sales.StatusApproved})
+	alerter.Notify(sales.EventSaleStatusChanged, &sales.Sale{ID: "s2", UserID: "buyer", Amount: 10, Status: sales.StatusApproved})
+	alerter.Notify(sales.EventSaleStatusChanged, &sales.Sale{ID: "s3", UserID: "quiet", Amount: 1500, Status: sales.StatusApproved})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := pool.Close(ctx); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	if len(sent) != 1 || sent[0] != "+5491100000000" {
+		t.Errorf("expected a single sms to the buyer, got %v", sent)
+	}
+}
@@ -0,0 +1,210 @@
+// Package vcr records outbound HTTP interactions to cassette files and
+// replays them, so client tests run offline against real responses.
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Mode selects whether a Recorder replays a cassette or records a new one.
+type Mode int
+
+const (
+	// ModeReplay answers every request from the cassette without touching
+	// the network.
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to the real service and writes the
+	// interactions to the cassette on Stop.
+	ModeRecord
+)
+
+// ModeEnv is the environment variable that switches tests to recording.
+const ModeEnv = "VCR_MODE"
+
+// Error para peticiones sin interacción grabada en el cassette
+var ErrNoInteraction = errors.New("vcr: no recorded interaction for request")
+
+// recordedHeaders son las cabeceras de respuesta que se guardan; el resto
+// (cookies, trazas) no aporta al test y puede traer datos sensibles
+var recordedHeaders = []string{"Content-Type", "Retry-After"}
+
+// Request is the recorded side of an interaction. Only the path and query are
+// kept so a cassette recorded against staging replays against any host.
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response is the recorded answer to a Request.
+type Response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+// Interaction pairs a request with the response it got.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Cassette is the file format of a recording.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder is an http.RoundTripper that records or replays interactions.
+type Recorder struct {
+	path string
+	mode Mode
+	real http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// ModeFromEnv returns ModeRecord when VCR_MODE is "record" and ModeReplay
+// otherwise.
+func ModeFromEnv() Mode {
+	if strings.EqualFold(os.Getenv(ModeEnv), "record") {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+// New creates a recorder for the cassette at path. In replay mode the
+// cassette must exist; in record mode requests go through real, or
+// http.DefaultTransport when it is nil.
+func New(path string, mode Mode, real http.RoundTripper) (*Recorder, error) {
+	if real == nil {
+		real = http.DefaultTransport
+	}
+	r := &Recorder{path: path, mode: mode, real: real}
+
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := newRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.mode == ModeRecord {
+		return r.record(req, recorded)
+	}
+	return r.replay(req, recorded)
+}
+
+// Stop writes the cassette in record mode. It is a no-op when replaying.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette dir: %w", err)
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+func (r *Recorder) record(req *http.Request, recorded Request) (*http.Response, error) {
+	resp, err := r.real.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	header := http.Header{}
+	for _, name := range recordedHeaders {
+		if v := resp.Header.Values(name); len(v) > 0 {
+			header[name] = v
+		}
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request:  recorded,
+		Response: Response{Status: resp.StatusCode, Header: header, Body: string(body)},
+	})
+	r.mu.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Las peticiones repetidas consumen las interacciones en el orden en que
+	// se grabaron
+	for i, in := range r.cassette.Interactions {
+		if r.used[i] || in.Request != recorded {
+			continue
+		}
+		r.used[i] = true
+		header := in.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.Status, http.StatusText(in.Response.Status)),
+			StatusCode:    in.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, recorded.Method, recorded.URL)
+}
+
+// newRequest reduce la petición a lo que se compara al reproducir.
+func newRequest(req *http.Request) (Request, error) {
+	recorded := Request{Method: req.Method, URL: req.URL.RequestURI()}
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return Request{}, fmt.Errorf("failed to read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		recorded.Body = string(body)
+	}
+	return recorded, nil
+}
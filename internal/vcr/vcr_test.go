@@ -0,0 +1,69 @@
+package vcr
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRecorder_RecordThenReplay verifica que lo grabado se reproduce sin red.
+func TestRecorder_RecordThenReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Write([]byte(`{"path":"` + r.URL.Path + `"}`))
+	}))
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	rec, err := New(path, ModeRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: rec}
+	resp, err := client.Get(server.URL + "/users/1?expand=true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if err := rec.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+
+	player, err := New(path, ModeReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = &http.Client{Transport: player}
+	// Otro host: solo cuentan el método, la ruta y la query
+	resp, err = client.Get("http://staging.invalid/users/1?expand=true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || string(body) != `{"path":"/users/1"}` {
+		t.Errorf("unexpected replay: %d %s", resp.StatusCode, body)
+	}
+	if resp.Header.Get("Set-Cookie") != "" {
+		t.Error("Set-Cookie should not be recorded")
+	}
+
+	// Cada interacción se consume una sola vez
+	_, err = client.Get("http://staging.invalid/users/1?expand=true")
+	if err == nil || !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("expected ErrNoInteraction, got %v", err)
+	}
+}
+
+func TestNew_ReplayRequiresCassette(t *testing.T) {
+	_, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay, nil)
+	if err == nil || !strings.Contains(err.Error(), "cassette") {
+		t.Errorf("expected missing cassette error, got %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+// Package webhook delivers sale events to the configured HTTP endpoints.
+package webhook
+
+import (
+	"api_sales/internal/dispatch"
+	"api_sales/internal/jsonenc"
+	"api_sales/internal/keys"
+	"api_sales/internal/redact"
+	"api_sales/internal/sales"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"resty.dev/v3"
+)
+
+// Event is the payload posted to every webhook endpoint.
+type Event struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      *sales.Sale `json:"data"`
+	// Comment is set on comment events.
+	Comment *sales.Comment `json:"comment,omitempty"`
+}
+
+// Sender implements sales.Notifier, queueing one delivery per endpoint on a
+// bounded dispatch pool.
+type Sender struct {
+	endpoints []string
+	pool      *dispatch.Pool
+	client    *resty.Client
+	signer    Signer
+	redactor  *redact.Redactor
+	logger    *zap.Logger
+}
+
+// Signer provides the keys used to sign deliveries.
+type Signer interface {
+	SigningKeys() []*keys.Key
+}
+
+// NewSender creates a sender that posts to endpoints through pool.
+func NewSender(endpoints []string, pool *dispatch.Pool, logger *zap.Logger) *Sender {
+	client := resty.New().
+		SetTimeout(10 * time.Second).
+		SetRetryCount(3).
+		SetRetryWaitTime(500 * time.Millisecond).
+		SetRetryMaxWaitTime(5 * time.Second).
+		SetAllowNonIdempotentRetry(true)
+
+	return &Sender{
+		endpoints: endpoints,
+		pool:      pool,
+		client:    client,
+		logger:    logger,
+	}
+}
+
+// SetTransport sends deliveries through rt, e.g. to apply proxy and TLS settings.
+func (s *Sender) SetTransport(rt http.RoundTripper) {
+	s.client.SetTransport(rt)
+}
+
+// SetSigner signs every delivery with the keys of signer in the X-Signature
+// header. Without signing keys deliveries are sent unsigned.
+func (s *Sender) SetSigner(signer Signer) {
+	s.signer = signer
+}
+
+// SetRedactor masks the sensitive fields of every event before it is signed
+// and sent.
+func (s *Sender) SetRedactor(r *redact.Redactor) {
+	s.redactor = r
+}
+
+// Notify queues the event for every endpoint. Events that don't fit in the
+// queue are dropped and logged rather than blocking the request path.
+func (s *Sender) Notify(eventType string, sale *sales.Sale) {
+	s.send(Event{
+		ID:        uuid.NewString(),
+		Type:      eventType,
+		CreatedAt: time.Now(),
+		Data:      sale,
+	})
+}
+
+// NotifyComment implements sales.CommentNotifier, sending the comment along
+// with the sale.
+func (s *Sender) NotifyComment(eventType string, sale *sales.Sale, comment *sales.Comment) {
+	s.send(Event{
+		ID:        uuid.NewString(),
+		Type:      eventType,
+		CreatedAt: time.Now(),
+		Data:      sale,
+		Comment:   comment,
+	})
+}
+
+func (s *Sender) send(event Event) {
+	for _, endpoint := range s.endpoints {
+		endpoint := endpoint
+		err := s.pool.Submit(func(ctx context.Context) {
+			if err := s.deliver(ctx, endpoint, event); err != nil {
+				s.logger.Error("webhook delivery failed",
+					zap.String("endpoint", endpoint),
+					zap.String("event_id", event.ID),
+					zap.String("event_type", event.Type),
+					zap.Error(err),
+				)
+			}
+		})
+		if err != nil {
+			s.logger.Warn("webhook dropped", zap.String("endpoint", endpoint), zap.String("event_type", event.Type), zap.Error(err))
+		}
+	}
+}
+
+func (s *Sender) deliver(ctx context.Context, endpoint string, event Event) error {
+	body, err := jsonenc.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	if s.redactor != nil {
+		if body, err = s.redactor.JSON(body); err != nil {
+			return fmt.Errorf("failed to redact event: %w", err)
+		}
+	}
+
+	req := s.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetHeader("X-Event-ID", event.ID).
+		SetHeader("X-Event-Type", event.Type).
+		SetBody(body)
+	if s.signer != nil {
+		if signing := s.signer.SigningKeys(); len(signing) > 0 {
+			req.SetHeader("X-Signature", keys.Sign(signing, time.Now(), body))
+			req.SetHeader("X-Signature-Key-ID", signing[0].ID)
+		}
+	}
+
+	resp, err := req.Post(endpoint)
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return fmt.Errorf("endpoint returned status %d", resp.StatusCode())
+	}
+	return nil
+}
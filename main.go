@@ -2,16 +2,43 @@ package main
 
 import (
 	"api_sales/api"
+	"api_sales/internal/config"
+	"api_sales/salesapi"
+	"context"
+	"flag"
 	"fmt"
-
-	"github.com/gin-gonic/gin"
+	"os"
 )
 
 func main() {
-	r := gin.Default()
-	api.InitRoutes(r)
+	migrateOnly := flag.Bool("migrate", false, "apply the pending schema migrations of the SQL sales storage and exit")
+	flag.Parse()
+
+	cfg := config.Load()
+	if *migrateOnly {
+		if err := api.Migrate(context.Background(), cfg, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "migration failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if cfg.SelfCheckEnabled {
+		// Falla al arrancar, no en la primera request, si algo está mal configurado
+		report := api.SelfCheck(context.Background(), cfg)
+		report.Write(os.Stderr)
+		if !report.Ready {
+			fmt.Fprintln(os.Stderr, "startup self-check failed; set SELF_CHECK_ENABLED=false to skip it")
+			os.Exit(1)
+		}
+	}
+
+	srv, err := salesapi.New(salesapi.WithConfig(cfg))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error initializing server: %v\n", err)
+		os.Exit(1)
+	}
 
-	if err := r.Run(":8081"); err != nil {
+	if err := srv.Serve(); err != nil {
 		panic(fmt.Errorf("error trying to start server: %v", err))
 	}
 }
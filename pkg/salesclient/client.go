@@ -0,0 +1,246 @@
+// Package salesclient is the Go client of the sales API:
+//
+//	client, err := salesclient.New("https://sales.internal",
+//		salesclient.WithAPIKey(os.Getenv("SALES_API_KEY")),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	sale, err := client.CreateSale(ctx, salesclient.CreateSaleInput{UserID: "user123", Amount: 250})
+//
+// Reads, status changes and creations are retried on connection errors, 429
+// and 5xx; creations carry an Idempotency-Key so a retry never duplicates a
+// sale. Failed calls return an *APIError.
+package salesclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client calls the sales API. It is safe for concurrent use.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	apiKey     string
+	token      string
+	userAgent  string
+	retry      RetryPolicy
+}
+
+// RetryPolicy controls how retryable calls are retried: up to MaxAttempts
+// in total, waiting Backoff before the second and doubling it per attempt up
+// to MaxBackoff. A Retry-After header from the API takes precedence.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sends the requests through httpClient instead of a client
+// with a 30 second timeout.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithAPIKey authenticates every request with an API key.
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithBearerToken authenticates every request as a customer, as required by
+// MySales.
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithUserAgent identifies the consumer in the API logs.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithRetryPolicy replaces the default policy of 3 attempts starting at
+// 100ms; MaxAttempts 1 disables retries.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// New returns a client of the API at baseURL.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	parsed, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base url %q: expected http or https", baseURL)
+	}
+	c := &Client{
+		baseURL:    parsed,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		userAgent:  "salesclient",
+		retry:      RetryPolicy{MaxAttempts: 3, Backoff: 100 * time.Millisecond, MaxBackoff: 2 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.retry.MaxAttempts < 1 {
+		c.retry.MaxAttempts = 1
+	}
+	return c, nil
+}
+
+// request describe una llamada; retry indica si repetirla es seguro.
+type request struct {
+	method  string
+	path    string
+	query   url.Values
+	body    any
+	header  http.Header
+	retry   bool
+	success int
+}
+
+// do ejecuta la llamada, reintentando si corresponde, y decodifica la
+// respuesta en out cuando no es nil.
+func (c *Client) do(ctx context.Context, req request, out any) error {
+	var body []byte
+	if req.body != nil {
+		var err error
+		if body, err = json.Marshal(req.body); err != nil {
+			return fmt.Errorf("salesclient: encode request: %w", err)
+		}
+	}
+
+	attempts := 1
+	if req.retry {
+		attempts = c.retry.MaxAttempts
+	}
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, req, body)
+		if err == nil {
+			err = decodeResponse(resp, req.success, out)
+		}
+		if err == nil || attempt >= attempts || !retryable(ctx, err) {
+			return err
+		}
+		lastErr = err
+
+		wait := c.backoff(attempt, err)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("salesclient: %w (last error: %v)", ctx.Err(), lastErr)
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, req request, body []byte) (*http.Response, error) {
+	target := c.baseURL.JoinPath(req.path)
+	if len(req.query) > 0 {
+		target.RawQuery = req.query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, target.String(), reader)
+	if err != nil {
+		return nil, fmt.Errorf("salesclient: build request: %w", err)
+	}
+	for name, values := range req.header {
+		httpReq.Header[name] = values
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", c.userAgent)
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		httpReq.Header.Set("X-API-Key", c.apiKey)
+	}
+	if c.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("salesclient: %s %s: %w", req.method, req.path, err)
+	}
+	return resp, nil
+}
+
+func decodeResponse(resp *http.Response, success int, out any) error {
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("salesclient: read response: %w", err)
+	}
+	if resp.StatusCode != success {
+		return newAPIError(resp, data)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("salesclient: decode response: %w", err)
+	}
+	return nil
+}
+
+// retryable reintenta los errores de conexión, 429 y 5xx, salvo que el
+// contexto del llamador ya haya terminado.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		return true
+	}
+	return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= http.StatusInternalServerError
+}
+
+func (c *Client) backoff(attempt int, err error) time.Duration {
+	wait := c.retry.Backoff << (attempt - 1)
+	if apiErr, ok := err.(*APIError); ok && apiErr.RetryAfter > 0 {
+		wait = apiErr.RetryAfter
+	}
+	if c.retry.MaxBackoff > 0 && wait > c.retry.MaxBackoff {
+		wait = c.retry.MaxBackoff
+	}
+	return wait
+}
+
+// retryAfter interpreta Retry-After en segundos; la forma de fecha no la usa
+// la API.
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
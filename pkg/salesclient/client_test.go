@@ -0,0 +1,203 @@
+package salesclient
+
+import (
+	"api_sales/internal/config"
+	"api_sales/internal/sales"
+	"api_sales/salesapi"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zaptest"
+)
+
+const pendingID = "3f2b8c1e-6a4d-4e2f-9b7a-1c5d8e9f0a21"
+
+// newTestAPI levanta la API real sobre un storage en memoria con una venta
+// pending sembrada.
+func newTestAPI(t *testing.T) *Client {
+	gin.SetMode(gin.TestMode)
+	users := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": "user123", "name": "Test User"}`))
+	}))
+	t.Cleanup(users.Close)
+
+	storage := sales.NewLocalStorage()
+	storage.Set(&sales.Sale{ID: pendingID, UserID: "user123", Amount: 10, Status: sales.StatusPending, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	cfg := config.Default()
+	cfg.UserServiceURL = users.URL + "/users"
+	srv, err := salesapi.New(salesapi.WithConfig(cfg), salesapi.WithStorage(storage), salesapi.WithLogger(zaptest.NewLogger(t)))
+	if err != nil {
+		t.Fatalf("salesapi.New returned error: %v", err)
+	}
+	api := httptest.NewServer(srv.Handler())
+	t.Cleanup(api.Close)
+
+	client, err := New(api.URL)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	return client
+}
+
+// TestClient_AgainstTheAPI verifica los métodos tipados contra la API real.
+func TestClient_AgainstTheAPI(t *testing.T) {
+	client := newTestAPI(t)
+	ctx := context.Background()
+
+	created, err := client.CreateSale(ctx, CreateSaleInput{UserID: "user123", Amount: 42, Status: "draft"})
+	if err != nil {
+		t.Fatalf("CreateSale returned error: %v", err)
+	}
+	if created.ID == "" || created.Status != "draft" || created.Amount != 42 {
+		t.Errorf("unexpected sale %+v", created)
+	}
+	amount := 50.0
+	edited, err := client.EditSale(ctx, created.ID, SaleEdit{Amount: &amount})
+	if err != nil || edited.Amount != 50 {
+		t.Fatalf("EditSale = %+v, %v", edited, err)
+	}
+	submitted, err := client.SubmitSale(ctx, created.ID)
+	if err != nil || submitted.Status == "draft" {
+		t.Fatalf("SubmitSale = %+v, %v", submitted, err)
+	}
+
+	sale, err := client.GetSale(ctx, pendingID)
+	if err != nil {
+		t.Fatalf("GetSale returned error: %v", err)
+	}
+	if len(sale.AllowedTransitions) == 0 {
+		t.Errorf("expected the allowed transitions of a pending sale, got %+v", sale)
+	}
+	approved, err := client.UpdateStatus(ctx, pendingID, "approved")
+	if err != nil || approved.Status != "approved" {
+		t.Fatalf("UpdateStatus = %+v, %v", approved, err)
+	}
+	// Repetir la transición es seguro
+	if _, err := client.UpdateStatus(ctx, pendingID, "approved"); err != nil {
+		t.Errorf("expected the repeated transition to succeed, got %v", err)
+	}
+	if _, err := client.GetSale(ctx, "0b9e4c2a-1d3f-4a5b-8c6d-7e8f9a0b1c2d"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := client.UpdateStatus(ctx, pendingID, "rejected"); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+
+	result, err := client.SearchSales(ctx, SearchParams{UserID: "user123", Status: "approved"})
+	if err != nil {
+		t.Fatalf("SearchSales returned error: %v", err)
+	}
+	if len(result.Results) == 0 || result.Results[0].Status != "approved" || result.Metadata.Approved != len(result.Results) {
+		t.Errorf("unexpected search result %+v", result)
+	}
+	found, missing, err := client.GetSalesByID(ctx, pendingID, "0b9e4c2a-1d3f-4a5b-8c6d-7e8f9a0b1c2d")
+	if err != nil || len(found) != 1 || len(missing) != 1 {
+		t.Errorf("GetSalesByID = %v, %v, %v", found, missing, err)
+	}
+	stats, err := client.GetStats(ctx)
+	if err != nil || stats.Quantity != 2 {
+		t.Errorf("GetStats = %+v, %v", stats, err)
+	}
+}
+
+// TestCreateSale_RetriesWithTheSameIdempotencyKey verifica que el reintento
+// reuse la clave y respete Retry-After.
+func TestCreateSale_RetriesWithTheSameIdempotencyKey(t *testing.T) {
+	var mu sync.Mutex
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error":"storage unavailable"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"s1","user_id":"user123","amount":10,"status":"pending"}`))
+	}))
+	defer server.Close()
+
+	client, _ := New(server.URL, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}))
+	sale, err := client.CreateSale(context.Background(), CreateSaleInput{UserID: "user123", Amount: 10})
+	if err != nil {
+		t.Fatalf("CreateSale returned error: %v", err)
+	}
+	if sale.ID != "s1" {
+		t.Errorf("unexpected sale %+v", sale)
+	}
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("expected 2 attempts with the same key, got %q", keys)
+	}
+}
+
+// TestEditSale_NotRetried verifica que las llamadas no idempotentes fallen al
+// primer error, con el mensaje de la API.
+func TestEditSale_NotRetried(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error":"storage unavailable"}`))
+	}))
+	defer server.Close()
+
+	client, _ := New(server.URL, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}))
+	amount := 5.0
+	_, err := client.EditSale(context.Background(), "s1", SaleEdit{Amount: &amount})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable || apiErr.Message != "storage unavailable" {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+// TestMySales_IteratesEveryPage verifica que el iterador siga next_cursor y
+// envíe el token.
+func TestMySales_IteratesEveryPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer customer-token" || r.URL.Query().Get("limit") != "2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			w.Write([]byte(`{"results":[{"id":"s1"},{"id":"s2"}],"next_cursor":"c2"}`))
+		case "c2":
+			w.Write([]byte(`{"results":[{"id":"s3"}]}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := New(server.URL, WithBearerToken("customer-token"))
+	var ids []string
+	for sale, err := range client.MySales(context.Background(), 2) {
+		if err != nil {
+			t.Fatalf("MySales returned error: %v", err)
+		}
+		ids = append(ids, sale.ID)
+	}
+	if fmt.Sprint(ids) != "[s1 s2 s3]" {
+		t.Errorf("unexpected sales %v", ids)
+	}
+
+	unauthenticated, _ := New(server.URL)
+	for _, err := range unauthenticated.MySales(context.Background(), 2) {
+		if !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("expected ErrUnauthorized, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package salesclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Errors matched with errors.Is against an *APIError.
+var (
+	// Error para ventas o rutas inexistentes (404)
+	ErrNotFound = errors.New("salesclient: not found")
+	// Error para transiciones o ediciones en conflicto con el estado actual (409)
+	ErrConflict = errors.New("salesclient: conflict")
+	// Error para credenciales ausentes o inválidas (401 y 403)
+	ErrUnauthorized = errors.New("salesclient: unauthorized")
+)
+
+// APIError is a response of the API outside the expected status.
+type APIError struct {
+	StatusCode int
+	// Message is the "error" field of the response body.
+	Message string
+	// Body is the raw response, for the extra fields some errors carry such
+	// as the current status of a conflicting sale.
+	Body []byte
+	// RetryAfter is the wait the API asked for, if any.
+	RetryAfter time.Duration
+}
+
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode, Body: body, RetryAfter: retryAfter(resp)}
+	var payload struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &payload) == nil {
+		apiErr.Message = payload.Error
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("salesclient: %d %s", e.StatusCode, e.Message)
+}
+
+// Is maps the status code to ErrNotFound, ErrConflict or ErrUnauthorized.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	}
+	return false
+}
@@ -0,0 +1,298 @@
+package salesclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sale is a sale as returned by the API.
+type Sale struct {
+	ID                string            `json:"id"`
+	UserID            string            `json:"user_id"`
+	Amount            float64           `json:"amount"`
+	Status            string            `json:"status"`
+	LineItems         []LineItem        `json:"line_items,omitempty"`
+	Subtotal          float64           `json:"subtotal,omitempty"`
+	DiscountPercent   float64           `json:"discount_percent,omitempty"`
+	Discount          float64           `json:"discount,omitempty"`
+	TaxPercent        float64           `json:"tax_percent,omitempty"`
+	Tax               float64           `json:"tax,omitempty"`
+	RecurringSaleID   string            `json:"recurring_sale_id,omitempty"`
+	ERPPosting        string            `json:"erp_posting_status,omitempty"`
+	FulfillmentStatus string            `json:"fulfillment_status,omitempty"`
+	Carrier           string            `json:"carrier,omitempty"`
+	TrackingNumber    string            `json:"tracking_number,omitempty"`
+	ShippedAt         *time.Time        `json:"shipped_at,omitempty"`
+	DeliveredAt       *time.Time        `json:"delivered_at,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+	Version           int               `json:"version"`
+	PendingSince      *time.Time        `json:"pending_since,omitempty"`
+	DecidedAt         *time.Time        `json:"decided_at,omitempty"`
+	PendingAge        string            `json:"pending_age,omitempty"`
+	SLABreached       bool              `json:"sla_breached,omitempty"`
+	// AllowedTransitions is only filled by GetSale and GetSaleByReference.
+	AllowedTransitions []string `json:"allowed_transitions,omitempty"`
+}
+
+// LineItem is a line of a sale.
+type LineItem struct {
+	SKU         string  `json:"sku"`
+	Description string  `json:"description,omitempty"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+}
+
+// SalesMetadata are the totals of a search or of GET /sales/stats.
+type SalesMetadata struct {
+	Quantity    int            `json:"quantity"`
+	Approved    int            `json:"approved"`
+	Rejected    int            `json:"rejected"`
+	Pending     int            `json:"pending"`
+	Draft       int            `json:"draft"`
+	ByStatus    map[string]int `json:"by_status,omitempty"`
+	TotalAmount float64        `json:"total_amount"`
+	Adjustments float64        `json:"adjustments,omitempty"`
+}
+
+// CreateSaleInput is a new sale. Status is empty to let the API assign it, or
+// "draft".
+type CreateSaleInput struct {
+	UserID   string            `json:"user_id"`
+	Amount   float64           `json:"amount"`
+	Status   string            `json:"status,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// IdempotencyKey identifies the creation across retries, including the
+	// caller's own; a random key is used when it is empty.
+	IdempotencyKey string `json:"-"`
+}
+
+// SaleEdit changes the fields of a draft or pending sale; nil fields are
+// left as they are.
+type SaleEdit struct {
+	UserID          *string     `json:"user_id,omitempty"`
+	Amount          *float64    `json:"amount,omitempty"`
+	LineItems       *[]LineItem `json:"line_items,omitempty"`
+	DiscountPercent *float64    `json:"discount_percent,omitempty"`
+	TaxPercent      *float64    `json:"tax_percent,omitempty"`
+}
+
+// SearchParams filters SearchSales; zero fields don't filter.
+type SearchParams struct {
+	UserID            string
+	Status            string
+	FulfillmentStatus string
+	CreatedFrom       time.Time
+	CreatedTo         time.Time
+	// Strong reads bypass caches and replicas.
+	Strong bool
+}
+
+// SearchResult is a page of SearchSales with the totals of the matches.
+type SearchResult struct {
+	Results  []Sale        `json:"results"`
+	Metadata SalesMetadata `json:"metadata"`
+}
+
+// SalePage is a page of MySales.
+type SalePage struct {
+	Results    []Sale `json:"results"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// CreateSale creates a sale. It is retried with the same idempotency key, so
+// the API creates it at most once.
+func (c *Client) CreateSale(ctx context.Context, input CreateSaleInput) (*Sale, error) {
+	key := input.IdempotencyKey
+	if key == "" {
+		key = newIdempotencyKey()
+	}
+	var sale Sale
+	err := c.do(ctx, request{
+		method:  http.MethodPost,
+		path:    "/sales",
+		body:    input,
+		header:  http.Header{"Idempotency-Key": {key}},
+		retry:   true,
+		success: http.StatusCreated,
+	}, &sale)
+	if err != nil {
+		return nil, err
+	}
+	return &sale, nil
+}
+
+// GetSale returns the sale with id, or ErrNotFound.
+func (c *Client) GetSale(ctx context.Context, id string) (*Sale, error) {
+	return c.getSale(ctx, "/sales/"+url.PathEscape(id))
+}
+
+// GetSaleByReference returns the sale with that ID or tracking number.
+func (c *Client) GetSaleByReference(ctx context.Context, reference string) (*Sale, error) {
+	return c.getSale(ctx, "/sales/by-reference/"+url.PathEscape(reference))
+}
+
+func (c *Client) getSale(ctx context.Context, path string) (*Sale, error) {
+	var sale Sale
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, retry: true, success: http.StatusOK}, &sale); err != nil {
+		return nil, err
+	}
+	return &sale, nil
+}
+
+// UpdateStatus moves a sale to status. Repeating a transition the sale already
+// made succeeds, which is what makes the call safe to retry.
+func (c *Client) UpdateStatus(ctx context.Context, id, status string) (*Sale, error) {
+	var sale Sale
+	err := c.do(ctx, request{
+		method:  http.MethodPatch,
+		path:    "/sales/" + url.PathEscape(id),
+		body:    map[string]string{"status": status},
+		retry:   true,
+		success: http.StatusOK,
+	}, &sale)
+	if err != nil {
+		return nil, err
+	}
+	return &sale, nil
+}
+
+// EditSale changes the fields of a sale. It is not retried: a retry after a
+// lost response could apply the edit over a newer one.
+func (c *Client) EditSale(ctx context.Context, id string, edit SaleEdit) (*Sale, error) {
+	var sale Sale
+	err := c.do(ctx, request{
+		method:  http.MethodPatch,
+		path:    "/sales/" + url.PathEscape(id),
+		body:    edit,
+		success: http.StatusOK,
+	}, &sale)
+	if err != nil {
+		return nil, err
+	}
+	return &sale, nil
+}
+
+// SubmitSale moves a draft to pending.
+func (c *Client) SubmitSale(ctx context.Context, id string) (*Sale, error) {
+	var sale Sale
+	err := c.do(ctx, request{method: http.MethodPost, path: "/sales/" + url.PathEscape(id) + "/submit", success: http.StatusOK}, &sale)
+	if err != nil {
+		return nil, err
+	}
+	return &sale, nil
+}
+
+// SearchSales returns the sales matching params.
+func (c *Client) SearchSales(ctx context.Context, params SearchParams) (*SearchResult, error) {
+	query := url.Values{}
+	setQuery(query, "user_id", params.UserID)
+	setQuery(query, "status", params.Status)
+	setQuery(query, "fulfillment_status", params.FulfillmentStatus)
+	if !params.CreatedFrom.IsZero() {
+		query.Set("created_from", params.CreatedFrom.Format(time.RFC3339))
+	}
+	if !params.CreatedTo.IsZero() {
+		query.Set("created_to", params.CreatedTo.Format(time.RFC3339))
+	}
+	if params.Strong {
+		query.Set("consistency", "strong")
+	}
+
+	var result SearchResult
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/sales", query: query, retry: true, success: http.StatusOK}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetSalesByID returns the sales with the given IDs and the IDs that don't
+// exist.
+func (c *Client) GetSalesByID(ctx context.Context, ids ...string) ([]Sale, []string, error) {
+	var result struct {
+		Results []Sale   `json:"results"`
+		Missing []string `json:"missing"`
+	}
+	query := url.Values{"ids": {strings.Join(ids, ",")}}
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/sales", query: query, retry: true, success: http.StatusOK}, &result); err != nil {
+		return nil, nil, err
+	}
+	return result.Results, result.Missing, nil
+}
+
+// GetStats returns the totals of every sale.
+func (c *Client) GetStats(ctx context.Context) (*SalesMetadata, error) {
+	var stats SalesMetadata
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/sales/stats", retry: true, success: http.StatusOK}, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// ListMySales returns a page of the bearer token user's sales, newest first.
+// An empty cursor starts from the newest; limit 0 uses the API default.
+func (c *Client) ListMySales(ctx context.Context, limit int, cursor string) (*SalePage, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	setQuery(query, "cursor", cursor)
+
+	var page SalePage
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/me/sales", query: query, retry: true, success: http.StatusOK}, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// MySales iterates over every sale of the bearer token user, fetching pages
+// of limit sales as it goes. Iteration stops after the first error.
+//
+//	for sale, err := range client.MySales(ctx, 100) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (c *Client) MySales(ctx context.Context, limit int) iter.Seq2[Sale, error] {
+	return func(yield func(Sale, error) bool) {
+		cursor := ""
+		for {
+			page, err := c.ListMySales(ctx, limit, cursor)
+			if err != nil {
+				yield(Sale{}, err)
+				return
+			}
+			for _, sale := range page.Results {
+				if !yield(sale, nil) {
+					return
+				}
+			}
+			if page.NextCursor == "" {
+				return
+			}
+			cursor = page.NextCursor
+		}
+	}
+}
+
+func setQuery(query url.Values, name, value string) {
+	if value != "" {
+		query.Set(name, value)
+	}
+}
+
+// newIdempotencyKey genera 128 bits aleatorios en hexadecimal.
+func newIdempotencyKey() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
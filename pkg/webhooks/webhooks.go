@@ -0,0 +1,298 @@
+// Package webhooks verifies and decodes the sale events the sales API posts
+// to webhook endpoints:
+//
+//	verifier := webhooks.NewVerifier(map[string]string{kid: secret})
+//	http.Handle("/hooks/sales", verifier.Handler(func(ctx context.Context, event *webhooks.Event) error {
+//		switch event.Type {
+//		case webhooks.EventSaleStatusChanged:
+//			...
+//		}
+//		return nil
+//	}))
+//
+// The handler rejects unsigned, tampered and stale deliveries and runs the
+// callback once per event ID, even when the API redelivers it.
+package webhooks
+
+import (
+	"api_sales/pkg/salesclient"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Headers of every delivery. SignatureHeader carries "t=<unix>" followed by
+// one "<kid>=<hex>" entry per signing key, each the HMAC-SHA256 of
+// "<t>.<body>" with that key's secret; during a key rotation it carries both.
+const (
+	SignatureHeader = "X-Signature"
+	KeyIDHeader     = "X-Signature-Key-ID"
+	EventIDHeader   = "X-Event-ID"
+	EventTypeHeader = "X-Event-Type"
+)
+
+// Event types sent by the API.
+const (
+	EventSaleCreated            = "sale.created"
+	EventSaleUpdated            = "sale.updated"
+	EventSaleStatusChanged      = "sale.status_changed"
+	EventSaleAdjusted           = "sale.adjusted"
+	EventSalePostingChanged     = "sale.erp_posting_changed"
+	EventSaleFulfillmentChanged = "sale.fulfillment_changed"
+	EventSaleSLABreached        = "sale.sla_breached"
+	EventSaleCommentAdded       = "sale.comment_added"
+	EventSaleCommentUpdated     = "sale.comment_updated"
+	EventSaleCommentDeleted     = "sale.comment_deleted"
+	EventSaleVerified           = "sale.verified"
+	EventSaleVerificationFailed = "sale.verification_failed"
+)
+
+// DefaultTolerance is how far the signature timestamp may be from now.
+const DefaultTolerance = 5 * time.Minute
+
+// maxBodySize limita el cuerpo leído de cada entrega.
+const maxBodySize = 1 << 20
+
+// Error para firmas ausentes, mal formadas, de claves desconocidas o que no coinciden
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Error para firmas fuera de la ventana de tolerancia
+var ErrStaleSignature = errors.New("webhook signature timestamp outside tolerance")
+
+// Event is the payload of a delivery.
+type Event struct {
+	ID        string            `json:"id"`
+	Type      string            `json:"type"`
+	CreatedAt time.Time         `json:"created_at"`
+	Data      *salesclient.Sale `json:"data"`
+	// Comment is set on the sale.comment_* events.
+	Comment *Comment `json:"comment,omitempty"`
+}
+
+// Comment is the comment of a sale.comment_* event.
+type Comment struct {
+	ID        string     `json:"id"`
+	SaleID    string     `json:"sale_id"`
+	Author    string     `json:"author"`
+	Body      string     `json:"body"`
+	Mentions  []string   `json:"mentions,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// Verify checks header against body with secrets, keyed by key ID, and
+// rejects signatures older or newer than tolerance. Entries of unknown keys
+// are ignored, so consumers can add the new key before a rotation.
+func Verify(secrets map[string]string, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var ts string
+	signatures := map[string]string{}
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if k == "t" {
+			ts = v
+		} else if k != "" {
+			signatures[k] = v
+		}
+	}
+	if ts == "" || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if d := now.Sub(time.Unix(unix, 0)); d > tolerance || d < -tolerance {
+		return ErrStaleSignature
+	}
+
+	for kid, sig := range signatures {
+		secret, ok := secrets[kid]
+		if !ok {
+			continue
+		}
+		got, err := hex.DecodeString(sig)
+		if err == nil && hmac.Equal(got, mac(secret, ts, body)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// Sign returns the signature header for body as the API sends it, for
+// consumers' tests.
+func Sign(kid, secret string, at time.Time, body []byte) string {
+	ts := strconv.FormatInt(at.Unix(), 10)
+	return "t=" + ts + "," + kid + "=" + hex.EncodeToString(mac(secret, ts, body))
+}
+
+func mac(secret, ts string, body []byte) []byte {
+	m := hmac.New(sha256.New, []byte(secret))
+	m.Write([]byte(ts + "."))
+	m.Write(body)
+	return m.Sum(nil)
+}
+
+// Decode parses and validates an event body.
+func Decode(body []byte) (*Event, error) {
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("invalid webhook event: %w", err)
+	}
+	if event.ID == "" || !strings.HasPrefix(event.Type, "sale.") || event.Data == nil {
+		return nil, fmt.Errorf("invalid webhook event: id, a sale.* type and data are required")
+	}
+	return &event, nil
+}
+
+// ReplayCache remembers the events already processed.
+type ReplayCache interface {
+	// Claim records id for ttl and reports false if it was already recorded.
+	Claim(ctx context.Context, id string, ttl time.Duration) (bool, error)
+	// Release forgets id, so a redelivery after a failure is processed.
+	Release(ctx context.Context, id string) error
+}
+
+// MemoryReplayCache is an in-process ReplayCache, enough for a single
+// consumer instance; replicated consumers need a shared one.
+type MemoryReplayCache struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+	now  func() time.Time
+}
+
+func NewMemoryReplayCache() *MemoryReplayCache {
+	return &MemoryReplayCache{seen: map[string]time.Time{}, now: time.Now}
+}
+
+func (c *MemoryReplayCache) Claim(ctx context.Context, id string, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	// Limpia las entradas vencidas para que el mapa no crezca sin límite
+	for seenID, expiresAt := range c.seen {
+		if now.After(expiresAt) {
+			delete(c.seen, seenID)
+		}
+	}
+	if _, ok := c.seen[id]; ok {
+		return false, nil
+	}
+	c.seen[id] = now.Add(ttl)
+	return true, nil
+}
+
+func (c *MemoryReplayCache) Release(ctx context.Context, id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.seen, id)
+	return nil
+}
+
+// Verifier checks and decodes deliveries.
+type Verifier struct {
+	secrets   map[string]string
+	tolerance time.Duration
+	replays   ReplayCache
+	now       func() time.Time
+}
+
+// Option configures a Verifier.
+type Option func(*Verifier)
+
+// WithTolerance replaces DefaultTolerance.
+func WithTolerance(tolerance time.Duration) Option {
+	return func(v *Verifier) {
+		v.tolerance = tolerance
+	}
+}
+
+// WithReplayCache replaces the in-process MemoryReplayCache, e.g. with one
+// shared by every consumer instance.
+func WithReplayCache(cache ReplayCache) Option {
+	return func(v *Verifier) {
+		v.replays = cache
+	}
+}
+
+// NewVerifier accepts deliveries signed with any of secrets, keyed by the
+// key ID the API reports for them.
+func NewVerifier(secrets map[string]string, opts ...Option) *Verifier {
+	v := &Verifier{
+		secrets:   secrets,
+		tolerance: DefaultTolerance,
+		replays:   NewMemoryReplayCache(),
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Parse reads, verifies and decodes the delivery in r. It does not check for
+// replays; Handler does.
+func (v *Verifier) Parse(r *http.Request) (*Event, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read webhook body: %w", err)
+	}
+	if len(body) > maxBodySize {
+		return nil, fmt.Errorf("webhook body larger than %d bytes", maxBodySize)
+	}
+	if err := Verify(v.secrets, r.Header.Get(SignatureHeader), body, v.now(), v.tolerance); err != nil {
+		return nil, err
+	}
+	return Decode(body)
+}
+
+// Handler verifies every delivery and runs fn once per event ID. It answers
+// 401 to bad signatures, 400 to invalid events and 200 to events already
+// processed, so the API stops redelivering them. When fn fails it answers 500
+// and the event is processed again on redelivery.
+func (v *Verifier) Handler(fn func(ctx context.Context, event *Event) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		event, err := v.Parse(r)
+		if errors.Is(err, ErrInvalidSignature) || errors.Is(err, ErrStaleSignature) {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// La firma vale por la tolerancia hacia ambos lados: recordar el ID
+		// ese tiempo cubre cualquier reenvío de la misma entrega firmada
+		first, err := v.replays.Claim(r.Context(), event.ID, 2*v.tolerance)
+		if err != nil {
+			http.Error(w, "replay cache unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		if !first {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if err := fn(r.Context(), event); err != nil {
+			v.replays.Release(r.Context(), event.ID)
+			http.Error(w, "failed to process event", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
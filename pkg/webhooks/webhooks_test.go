@@ -0,0 +1,112 @@
+package webhooks
+
+import (
+	"api_sales/internal/dispatch"
+	"api_sales/internal/keys"
+	"api_sales/internal/sales"
+	"api_sales/internal/webhook"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// TestHandler_AcceptsTheAPIDeliveries verifica el helper contra el Sender
+// real, con dos secretos configurados como durante una rotación.
+func TestHandler_AcceptsTheAPIDeliveries(t *testing.T) {
+	manager := keys.NewManager(time.Hour)
+	current, err := manager.Create(keys.PurposeWebhookSigning, "")
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+
+	received := make(chan *Event, 1)
+	verifier := NewVerifier(map[string]string{"retired": "old-secret", current.ID: current.Secret})
+	server := httptest.NewServer(verifier.Handler(func(ctx context.Context, event *Event) error {
+		received <- event
+		return nil
+	}))
+	defer server.Close()
+
+	pool := dispatch.NewPool("webhook_test", 1, 1, zaptest.NewLogger(t))
+	defer pool.Close(context.Background())
+	sender := webhook.NewSender([]string{server.URL}, pool, zaptest.NewLogger(t))
+	sender.SetSigner(manager)
+	sender.Notify(sales.EventSaleStatusChanged, &sales.Sale{ID: "s1", UserID: "user123", Amount: 10, Status: sales.StatusApproved, TenantID: "acme"})
+
+	select {
+	case event := <-received:
+		if event.Type != EventSaleStatusChanged || event.Data.ID != "s1" || event.Data.Status != "approved" {
+			t.Errorf("unexpected event %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the delivery to be accepted")
+	}
+}
+
+func TestHandler_RejectsForgedStaleAndReplayedDeliveries(t *testing.T) {
+	now := time.Unix(1_717_171_717, 0)
+	calls := 0
+	fail := false
+	verifier := NewVerifier(map[string]string{"kid1": "secret"})
+	verifier.now = func() time.Time { return now }
+	handler := verifier.Handler(func(ctx context.Context, event *Event) error {
+		calls++
+		if fail {
+			return errors.New("downstream unavailable")
+		}
+		return nil
+	})
+
+	body := `{"id":"evt-1","type":"sale.created","created_at":"2024-05-31T16:08:37Z","data":{"id":"s1","status":"pending"}}`
+	post := func(body, signature string) int {
+		req := httptest.NewRequest(http.MethodPost, "/hooks", strings.NewReader(body))
+		req.Header.Set(SignatureHeader, signature)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	tests := []struct {
+		name      string
+		body      string
+		signature string
+		want      int
+	}{
+		{"unsigned", body, "", http.StatusUnauthorized},
+		{"unknown key", body, Sign("kid2", "secret", now, []byte(body)), http.StatusUnauthorized},
+		{"tampered body", strings.Replace(body, "pending", "approved", 1), Sign("kid1", "secret", now, []byte(body)), http.StatusUnauthorized},
+		{"stale", body, Sign("kid1", "secret", now.Add(-10*time.Minute), []byte(body)), http.StatusUnauthorized},
+		{"not a sale event", `{"id":"evt-2","type":"user.created","data":{}}`, Sign("kid1", "secret", now, []byte(`{"id":"evt-2","type":"user.created","data":{}}`)), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if got := post(tt.body, tt.signature); got != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, got)
+		}
+	}
+	if calls != 0 {
+		t.Fatalf("expected no rejected delivery processed, got %d calls", calls)
+	}
+
+	// Un fallo del consumidor libera el ID para que el reenvío se procese
+	signature := Sign("kid1", "secret", now, []byte(body))
+	fail = true
+	if got := post(body, signature); got != http.StatusInternalServerError {
+		t.Errorf("expected 500 when the callback fails, got %d", got)
+	}
+	fail = false
+	if got := post(body, signature); got != http.StatusNoContent {
+		t.Errorf("expected the redelivery processed, got %d", got)
+	}
+	if got := post(body, signature); got != http.StatusOK {
+		t.Errorf("expected the replay acknowledged, got %d", got)
+	}
+	if calls != 2 {
+		t.Errorf("expected the callback run for the failed and the redelivered attempt only, got %d calls", calls)
+	}
+}
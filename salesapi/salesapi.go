@@ -0,0 +1,290 @@
+// Package salesapi embeds the sales API in another Go program:
+//
+//	srv, err := salesapi.New(
+//		salesapi.WithConfig(cfg),
+//		salesapi.WithStorage(storage),
+//		salesapi.WithListener(ln),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	go srv.Serve()
+//	defer srv.Shutdown(ctx)
+//
+// Tests can also call srv.Handler() directly without opening a listener.
+package salesapi
+
+import (
+	"api_sales/api"
+	"api_sales/internal/config"
+	"api_sales/internal/sales"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Server is an in-process instance of the sales API.
+type Server struct {
+	cfg        config.Config
+	deps       api.Dependencies
+	middleware []gin.HandlerFunc
+	listeners  []net.Listener
+	admin      []net.Listener
+	addrs      []string
+	adminAddrs []string
+
+	engine      *gin.Engine
+	adminEngine *gin.Engine
+	servers     []*http.Server
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithConfig replaces the default configuration (config.Default).
+func WithConfig(cfg config.Config) Option {
+	return func(s *Server) {
+		s.cfg = cfg
+	}
+}
+
+// WithStorage stores the sales in storage instead of the configured backend.
+func WithStorage(storage sales.Storage) Option {
+	return func(s *Server) {
+		s.deps.Storage = storage
+	}
+}
+
+// WithLogger sends the API logs to logger.
+func WithLogger(logger *zap.Logger) Option {
+	return func(s *Server) {
+		s.deps.Logger = logger
+	}
+}
+
+// WithNotifier subscribes n to every sale event.
+func WithNotifier(n sales.Notifier) Option {
+	return func(s *Server) {
+		s.deps.Notifiers = append(s.deps.Notifiers, n)
+	}
+}
+
+// WithMiddleware runs handlers before every route, ahead of authentication.
+func WithMiddleware(handlers ...gin.HandlerFunc) Option {
+	return func(s *Server) {
+		s.middleware = append(s.middleware, handlers...)
+	}
+}
+
+// WithRouteMiddleware runs handlers on every route after authentication,
+// maintenance and quotas, unlike WithMiddleware.
+func WithRouteMiddleware(handlers ...gin.HandlerFunc) Option {
+	return func(s *Server) {
+		s.deps.Middleware = append(s.deps.Middleware, handlers...)
+	}
+}
+
+// WithRoutes registers extra public routes next to the built-in ones.
+func WithRoutes(registrars ...api.RouteRegistrar) Option {
+	return func(s *Server) {
+		s.deps.Routes = append(s.deps.Routes, registrars...)
+	}
+}
+
+// WithAdminRoutes registers extra routes under /admin, restricted to the
+// admin role.
+func WithAdminRoutes(registrars ...api.RouteRegistrar) Option {
+	return func(s *Server) {
+		s.deps.AdminRoutes = append(s.deps.AdminRoutes, registrars...)
+	}
+}
+
+// WithListener serves the public API on ln, e.g. a listener on a random port
+// in tests. It can be given several times.
+func WithListener(ln net.Listener) Option {
+	return func(s *Server) {
+		s.listeners = append(s.listeners, ln)
+	}
+}
+
+// WithAdminListener serves /admin and /metrics only on ln instead of on the
+// public listeners. It can be given several times.
+func WithAdminListener(ln net.Listener) Option {
+	return func(s *Server) {
+		s.admin = append(s.admin, ln)
+	}
+}
+
+// WithAddr replaces cfg.ListenAddrs; addr is host:port or unix:/path.
+func WithAddr(addr ...string) Option {
+	return func(s *Server) {
+		s.addrs = addr
+	}
+}
+
+// WithAdminAddr replaces cfg.AdminListenAddrs; addr is host:port or unix:/path.
+func WithAdminAddr(addr ...string) Option {
+	return func(s *Server) {
+		s.adminAddrs = addr
+	}
+}
+
+// New builds the API with opts and opens its listeners: the given ones or,
+// without them, the configured addresses. Call Serve to accept connections.
+func New(opts ...Option) (*Server, error) {
+	s := &Server{
+		cfg:    config.Default(),
+		engine: gin.New(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.addrs == nil {
+		s.addrs = s.cfg.ListenAddrs
+	}
+	if s.adminAddrs == nil {
+		s.adminAddrs = s.cfg.AdminListenAddrs
+	}
+
+	s.engine.Use(gin.Recovery())
+	s.engine.Use(s.middleware...)
+	if len(s.admin) > 0 || len(s.adminAddrs) > 0 {
+		s.adminEngine = gin.New()
+		s.adminEngine.Use(gin.Recovery())
+		s.adminEngine.Use(s.middleware...)
+		s.deps.Admin = s.adminEngine
+	}
+	if err := api.InitRoutesWithDependencies(s.engine, s.cfg, s.deps); err != nil {
+		return nil, err
+	}
+
+	public, err := listenAll(s.listeners, s.addrs)
+	if err != nil {
+		return nil, err
+	}
+	admin, err := listenAll(s.admin, s.adminAddrs)
+	if err != nil {
+		closeAll(public)
+		return nil, err
+	}
+	s.listeners, s.admin = public, admin
+	for range public {
+		s.servers = append(s.servers, &http.Server{Handler: s.engine})
+	}
+	for range admin {
+		s.servers = append(s.servers, &http.Server{Handler: s.adminEngine})
+	}
+	return s, nil
+}
+
+// Handler returns the public API handler, for use with httptest or another
+// mux.
+func (s *Server) Handler() http.Handler {
+	return s.engine
+}
+
+// AdminHandler returns the handler of the admin routes, or nil when they are
+// served by Handler.
+func (s *Server) AdminHandler() http.Handler {
+	if s.adminEngine == nil {
+		return nil
+	}
+	return s.adminEngine
+}
+
+// Addr returns the address of the first public listener.
+func (s *Server) Addr() string {
+	if len(s.listeners) == 0 {
+		return ""
+	}
+	return s.listeners[0].Addr().String()
+}
+
+// AdminAddr returns the address of the first admin listener, or "" when the
+// admin routes are served on the public listeners.
+func (s *Server) AdminAddr() string {
+	if len(s.admin) == 0 {
+		return ""
+	}
+	return s.admin[0].Addr().String()
+}
+
+// Serve accepts connections on every listener until Shutdown is called and
+// then returns nil. The first listener error stops every listener.
+func (s *Server) Serve() error {
+	listeners := append(append([]net.Listener{}, s.listeners...), s.admin...)
+	errs := make(chan error, len(listeners))
+	for i, ln := range listeners {
+		srv, ln := s.servers[i], ln
+		go func() { errs <- srv.Serve(ln) }()
+	}
+
+	for range listeners {
+		if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+			s.Shutdown(context.Background())
+			return err
+		}
+	}
+	return nil
+}
+
+// Shutdown stops accepting connections and waits for in-flight requests.
+func (s *Server) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, srv := range s.servers {
+		errs = append(errs, srv.Shutdown(ctx))
+	}
+	// Los listeners que Serve no llegó a usar se cierran igual
+	closeAll(s.listeners)
+	closeAll(s.admin)
+	return errors.Join(errs...)
+}
+
+// listenAll abre las direcciones que no tengan ya un listener explícito.
+func listenAll(listeners []net.Listener, addrs []string) ([]net.Listener, error) {
+	if len(listeners) > 0 {
+		return listeners, nil
+	}
+	opened := make([]net.Listener, 0, len(addrs))
+	for _, addr := range addrs {
+		ln, err := listen(addr)
+		if err != nil {
+			closeAll(opened)
+			return nil, err
+		}
+		opened = append(opened, ln)
+	}
+	return opened, nil
+}
+
+// listen abre host:port o unix:/ruta, borrando un socket viejo si quedó.
+func listen(addr string) (net.Listener, error) {
+	if path, ok := strings.CutPrefix(addr, "unix:"); ok {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to remove stale socket %s: %w", path, err)
+		}
+		ln, err := net.Listen("unix", path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+		}
+		return ln, nil
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	return ln, nil
+}
+
+func closeAll(listeners []net.Listener) {
+	for _, ln := range listeners {
+		ln.Close()
+	}
+}
@@ -0,0 +1,198 @@
+package salesapi
+
+import (
+	"api_sales/api"
+	"api_sales/internal/config"
+	"api_sales/internal/sales"
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zaptest"
+)
+
+type recordingNotifier struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recordingNotifier) Notify(eventType string, sale *sales.Sale) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, eventType)
+}
+
+// TestNew_ServesWithInjectedDependencies verifica que la API embebida use el
+// storage, el middleware y los notifiers recibidos.
+func TestNew_ServesWithInjectedDependencies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	users := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": "user123", "name": "Test User"}`))
+	}))
+	defer users.Close()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen returned error: %v", err)
+	}
+
+	cfg := config.Default()
+	cfg.UserServiceURL = users.URL + "/users"
+	storage := sales.NewLocalStorage()
+	notifier := &recordingNotifier{}
+	srv, err := New(
+		WithConfig(cfg),
+		WithStorage(storage),
+		WithLogger(zaptest.NewLogger(t)),
+		WithNotifier(notifier),
+		WithMiddleware(func(ctx *gin.Context) {
+			ctx.Header("X-Embedded", "true")
+			ctx.Next()
+		}),
+		WithListener(ln),
+	)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	go srv.Serve()
+	defer srv.Shutdown(context.Background())
+
+	resp, err := http.Post("http://"+srv.Addr()+"/sales", "application/json", bytes.NewBufferString(`{"user_id": "user123", "amount": 10}`))
+	if err != nil {
+		t.Fatalf("POST /sales returned error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("X-Embedded") != "true" {
+		t.Error("expected the injected middleware to run")
+	}
+
+	stored, err := storage.GetAll()
+	if err != nil || len(stored) != 1 {
+		t.Errorf("expected the sale in the injected storage, got %d (err=%v)", len(stored), err)
+	}
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+	if len(notifier.events) == 0 || notifier.events[0] != sales.EventSaleCreated {
+		t.Errorf("expected a sale created event, got %v", notifier.events)
+	}
+}
+
+// TestNew_AdminRoutesOnlyOnInternalListener verifica que /admin y /metrics se
+// sirvan solo en el listener interno, incluso sobre un socket Unix.
+func TestNew_AdminRoutesOnlyOnInternalListener(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	socket := filepath.Join(t.TempDir(), "admin.sock")
+
+	srv, err := New(
+		WithLogger(zaptest.NewLogger(t)),
+		WithAddr("127.0.0.1:0"),
+		WithAdminAddr("unix:"+socket),
+	)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	go srv.Serve()
+	defer srv.Shutdown(context.Background())
+
+	resp, err := http.Get("http://" + srv.Addr() + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics returned error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected /metrics hidden on the public listener, got %d", resp.StatusCode)
+	}
+
+	unixClient := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	resp, err = unixClient.Get("http://admin/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics over the unix socket returned error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected /metrics on the admin listener, got %d", resp.StatusCode)
+	}
+}
+
+// TestNew_RegistersPluginRoutes verifica que las rutas y el middleware de la
+// aplicación embebida corran detrás de la autenticación de la API.
+func TestNew_RegistersPluginRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := config.Default()
+	cfg.AdminAPIKey = "admin-secret"
+
+	var roles []string
+	srv, err := New(
+		WithConfig(cfg),
+		WithLogger(zaptest.NewLogger(t)),
+		WithAddr("127.0.0.1:0"),
+		WithRouteMiddleware(func(ctx *gin.Context) {
+			roles = append(roles, api.Role(ctx))
+			ctx.Next()
+		}),
+		WithRoutes(func(r gin.IRouter, svc *sales.Service) {
+			r.GET("/custom/hello", func(ctx *gin.Context) {
+				ctx.JSON(http.StatusOK, gin.H{"message": "hello"})
+			})
+		}),
+		WithAdminRoutes(func(r gin.IRouter, svc *sales.Service) {
+			r.GET("/custom", func(ctx *gin.Context) {
+				ctx.JSON(http.StatusOK, gin.H{"message": "admin"})
+			})
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	defer srv.Shutdown(context.Background())
+
+	get := func(path, apiKey string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := get("/custom/hello", ""); code != http.StatusOK {
+		t.Errorf("expected the plugin route to be served, got %d", code)
+	}
+	if code := get("/admin/custom", ""); code != http.StatusForbidden {
+		t.Errorf("expected the admin plugin route to require the admin role, got %d", code)
+	}
+	if code := get("/admin/custom", "admin-secret"); code != http.StatusOK {
+		t.Errorf("expected the admin plugin route for admins, got %d", code)
+	}
+	if len(roles) != 3 || roles[2] != "admin" {
+		t.Errorf("expected the plugin middleware to see the authenticated role, got %v", roles)
+	}
+}
+
+func TestNew_DuplicatePluginRouteIsAnError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	_, err := New(
+		WithLogger(zaptest.NewLogger(t)),
+		WithAddr("127.0.0.1:0"),
+		WithRoutes(func(r gin.IRouter, svc *sales.Service) {
+			r.GET("/ping", func(ctx *gin.Context) {})
+		}),
+	)
+	if err == nil {
+		t.Fatal("expected an error for a route that is already registered")
+	}
+}
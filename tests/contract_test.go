@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// update regenera los golden files: go test ./tests/ -run Contract -update
+var update = flag.Bool("update", false, "rewrite the golden files of the contract tests")
+
+var (
+	uuidPattern      = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+	timestampPattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}`)
+	hashPattern      = regexp.MustCompile(`^[0-9a-f]{64}$`)
+)
+
+// normalizeJSON reemplaza los valores que cambian en cada corrida (IDs,
+// fechas, hashes) para que el golden file solo capture el formato.
+func normalizeJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = normalizeJSON(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeJSON(item)
+		}
+		return v
+	case string:
+		switch {
+		case uuidPattern.MatchString(v):
+			return "<uuid>"
+		case timestampPattern.MatchString(v):
+			return "<timestamp>"
+		case hashPattern.MatchString(v):
+			return "<hash>"
+		}
+	}
+	return v
+}
+
+// assertGolden compara el status y el body normalizado con
+// testdata/golden/<name>.json.
+func assertGolden(t *testing.T, name string, w *httptest.ResponseRecorder) {
+	t.Helper()
+
+	var body interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("%s: response is not JSON: %v", name, err)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	enc.Encode(map[string]interface{}{
+		"status": w.Code,
+		"body":   normalizeJSON(body),
+	})
+	got := buf.Bytes()
+
+	path := filepath.Join("testdata", "golden", name+".json")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create golden dir: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("failed to write golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%s: missing golden file, run with -update: %v", name, err)
+	}
+	assert.Equal(t, string(want), string(got), "%s: wire format drifted from %s; run with -update if intended", name, path)
+}
+
+// TestContract_GoldenResponses fija el formato JSON de cada endpoint público.
+func TestContract_GoldenResponses(t *testing.T) {
+	router, userMockServer := InitRoutesTests()
+	defer userMockServer.Close()
+
+	do := func(method, target string, body interface{}) *httptest.ResponseRecorder {
+		var payload bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&payload).Encode(body)
+		}
+		req := httptest.NewRequest(method, target, &payload)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	created := do(http.MethodPost, "/sales", map[string]interface{}{"user_id": "user123", "amount": 150.75})
+	assertGolden(t, "create_sale", created)
+	var sale struct {
+		ID string `json:"id"`
+	}
+	json.Unmarshal(created.Body.Bytes(), &sale)
+
+	assertGolden(t, "create_sale_invalid_amount", do(http.MethodPost, "/sales", map[string]interface{}{"user_id": "user123", "amount": -1}))
+	assertGolden(t, "create_sale_unknown_user", do(http.MethodPost, "/sales", map[string]interface{}{"user_id": "nobody", "amount": 10}))
+	assertGolden(t, "update_sale_status", do(http.MethodPatch, "/sales/"+sale.ID, map[string]interface{}{"status": "approved"}))
+	assertGolden(t, "update_sale_not_found", do(http.MethodPatch, "/sales/"+missingID, map[string]interface{}{"status": "approved"}))
+	assertGolden(t, "search_sales", do(http.MethodGet, "/sales?user_id=user123", nil))
+	assertGolden(t, "sales_stats", do(http.MethodGet, "/sales/stats", nil))
+	assertGolden(t, "sale_audit", do(http.MethodGet, fmt.Sprintf("/sales/%s/audit", sale.ID), nil))
+	assertGolden(t, "sale_adjustment", do(http.MethodPost, fmt.Sprintf("/sales/%s/adjustments", sale.ID), map[string]interface{}{"amount": -10, "reason": "discount"}))
+	assertGolden(t, "user_summary", do(http.MethodGet, "/users/user123/sales/summary", nil))
+	assertGolden(t, "recurring_sale", do(http.MethodPost, "/recurring-sales", map[string]interface{}{"user_id": "user123", "amount": 20, "interval": "monthly"}))
+	assertGolden(t, "ping", do(http.MethodGet, "/ping", nil))
+}
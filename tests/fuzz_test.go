@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// Los fuzz targets corren sus seeds con go test; para explorar:
+//
+//	go test ./tests/ -run '^$' -fuzz FuzzCreateSale -fuzztime 30s
+
+// assertWellFormed falla ante errores 5xx o respuestas que no son JSON: toda
+// entrada inválida debe terminar en un 4xx con mensaje.
+func assertWellFormed(t *testing.T, w *httptest.ResponseRecorder) {
+	t.Helper()
+	if w.Code >= http.StatusInternalServerError {
+		t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+	}
+	if w.Code != http.StatusNotModified && w.Body.Len() > 0 && !json.Valid(w.Body.Bytes()) {
+		t.Fatalf("response is not valid JSON: %q", w.Body.String())
+	}
+}
+
+// FuzzCreateSale envía cuerpos arbitrarios a POST /sales.
+func FuzzCreateSale(f *testing.F) {
+	for _, seed := range []string{
+		`{"user_id": "user123", "amount": 150.75}`,
+		`{"user_id": "user123", "amount": 1e308}`,
+		`{"user_id": "user123", "amount": -0}`,
+		`{"user_id": "üser\u0000", "amount": 1}`,
+		`{"user_id": "user123", "amount": 10, "status": "draft", "metadata": {"k": "v"}}`,
+		`{"user_id": 5}`,
+		`{`,
+		`null`,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	router, userMockServer := InitRoutesTests()
+	f.Cleanup(userMockServer.Close)
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		req := httptest.NewRequest(http.MethodPost, "/sales", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assertWellFormed(t, w)
+	})
+}
+
+// FuzzPatchSale envía IDs y cuerpos arbitrarios a PATCH /sales/:id.
+func FuzzPatchSale(f *testing.F) {
+	f.Add("missing", []byte(`{"status": "approved"}`))
+	f.Add(missingID, []byte(`{"status": "approved"}`))
+	f.Add("%E2%80%AE", []byte(`{"amount": 1e400}`))
+	f.Add("a%2Fb", []byte(`{"line_items": [{"quantity": -1}]}`))
+	f.Add("..", []byte(`{"status": "☃"}`))
+
+	router, userMockServer := InitRoutesTests()
+	f.Cleanup(userMockServer.Close)
+
+	f.Fuzz(func(t *testing.T, id string, body []byte) {
+		req, err := http.NewRequest(http.MethodPatch, "/sales/"+url.PathEscape(id), bytes.NewReader(body))
+		if err != nil {
+			t.Skip()
+		}
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assertWellFormed(t, w)
+	})
+}
+
+// FuzzSearchSale envía query strings arbitrarios a GET /sales.
+func FuzzSearchSale(f *testing.F) {
+	f.Add("user_id=user123&status=approved")
+	f.Add("from=2026-13-01&to=yesterday")
+	f.Add("limit=-1&offset=99999999999999999999")
+	f.Add("consistency=strong&user_id=%00")
+	f.Add("min_amount=NaN&max_amount=Inf")
+
+	router, userMockServer := InitRoutesTests()
+	f.Cleanup(userMockServer.Close)
+
+	f.Fuzz(func(t *testing.T, query string) {
+		req, err := http.NewRequest(http.MethodGet, "/sales?"+query, nil)
+		if err != nil {
+			t.Skip()
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assertWellFormed(t, w)
+	})
+}
@@ -2,17 +2,40 @@ package tests
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"api_sales/api"
+	"api_sales/internal/config"
 	"api_sales/internal/sales"
+	"api_sales/internal/shipping"
+	"api_sales/internal/slo"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/ugorji/go/codec"
+)
+
+// testRandomSeed hace que la primera venta creada quede en estado pending.
+const testRandomSeed = 2
+
+// IDs de las ventas sembradas en los tests: las rutas /sales/:id solo aceptan
+// UUIDs.
+const (
+	saleID    = "3f0c2a9e-5b1d-4c7a-8e2f-6d4b1a0c9e11"
+	slowID    = "3f0c2a9e-5b1d-4c7a-8e2f-6d4b1a0c9e12"
+	missingID = "3f0c2a9e-5b1d-4c7a-8e2f-6d4b1a0c9e13"
 )
 
 func InitRoutesTests() (*gin.Engine, *httptest.Server) {
@@ -33,8 +56,14 @@ func InitRoutesTests() (*gin.Engine, *httptest.Server) {
 		}
 	}))
 
-	// 3. Inicializar las rutas de la API de ventas
-	api.InitRoutes2(router, userMockServer.URL+"/users")
+	// 3. Inicializar las rutas de la API de ventas con una semilla fija para
+	// que los estados asignados sean reproducibles
+	cfg := config.Default()
+	cfg.UserServiceURL = userMockServer.URL + "/users"
+	cfg.RandomSeed = testRandomSeed
+	if err := api.InitRoutesWithConfig(router, cfg); err != nil {
+		panic(err)
+	}
 
 	return router, userMockServer
 }
@@ -68,7 +97,7 @@ func TestSalesHappyPath_FullFlow(t *testing.T) {
 		assert.NotEmpty(t, createdSale.ID, "Expected sale ID to be generated")
 		assert.Equal(t, "user123", createdSale.UserID, "Expected correct UserID in created sale")
 		assert.Equal(t, 150.75, createdSale.Amount, "Expected correct Amount in created sale")
-		assert.Contains(t, []string{"pending", "approved", "rejected"}, createdSale.Status, "Expected a valid status in created sale")
+		assert.Equal(t, "pending", createdSale.Status, "Expected the seeded status in created sale")
 		assert.Equal(t, 1, createdSale.Version, "Expected initial version to be 1")
 
 		saleID = createdSale.ID
@@ -151,3 +180,1134 @@ func TestSalesHappyPath_FullFlow(t *testing.T) {
 		assert.Equal(t, 1, response.Metadata.Approved, "Expected metadata approved count to be 1 by status")
 	})
 }
+
+// TestCreateSale_IdempotencyKeyReplaysResponse prueba que un reintento con la misma clave no crea otra venta.
+func TestCreateSale_IdempotencyKeyReplaysResponse(t *testing.T) {
+	router, userMockServer := InitRoutesTests()
+	defer userMockServer.Close()
+
+	bodyBytes, _ := json.Marshal(map[string]interface{}{"user_id": "user123", "amount": 99.9})
+
+	send := func(body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/sales", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", "retry-abc")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := send(bodyBytes)
+	assert.Equal(t, http.StatusCreated, first.Code, "Expected HTTP 201 Created on first request")
+
+	second := send(bodyBytes)
+	assert.Equal(t, http.StatusCreated, second.Code, "Expected replayed HTTP 201 Created")
+	assert.Equal(t, "true", second.Header().Get("Idempotent-Replayed"), "Expected replay header")
+	assert.Equal(t, first.Body.String(), second.Body.String(), "Expected identical replayed body")
+
+	otherBody, _ := json.Marshal(map[string]interface{}{"user_id": "user123", "amount": 10})
+	conflict := send(otherBody)
+	assert.Equal(t, http.StatusUnprocessableEntity, conflict.Code, "Expected 422 when reusing the key with another body")
+
+	req := httptest.NewRequest(http.MethodGet, "/sales?user_id=user123", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response struct {
+		Results []sales.Sale `json:"results"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Results, 1, "Expected a single sale despite the retry")
+}
+
+// TestSearchSale_ResponseCacheInvalidatedOnWrite prueba el cache de búsquedas y su invalidación.
+func TestSearchSale_ResponseCacheInvalidatedOnWrite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	userMockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "user123", "name": "Test User 123"}`))
+	}))
+	defer userMockServer.Close()
+
+	cfg := config.Default()
+	cfg.UserServiceURL = userMockServer.URL + "/users"
+	cfg.ResponseCacheTTLs = map[string]time.Duration{"/sales": time.Minute}
+	assert.NoError(t, api.InitRoutesWithConfig(router, cfg))
+
+	search := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sales"+query, nil))
+		return w
+	}
+
+	assert.Equal(t, "MISS", search("?user_id=user123&status=").Header().Get("X-Cache"))
+	assert.Equal(t, "HIT", search("?status=&user_id=user123").Header().Get("X-Cache"), "Expected equivalent filters to share the cache entry")
+
+	bodyBytes, _ := json.Marshal(map[string]interface{}{"user_id": "user123", "amount": 5})
+	req := httptest.NewRequest(http.MethodPost, "/sales", bytes.NewBuffer(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	w := search("?user_id=user123")
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"), "Expected the write to invalidate cached searches")
+
+	var response struct {
+		Results []sales.Sale `json:"results"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Results, 1)
+}
+
+// TestSearchSale_ConditionalRequests prueba ETag/Last-Modified y las respuestas 304.
+func TestSearchSale_ConditionalRequests(t *testing.T) {
+	router, userMockServer := InitRoutesTests()
+	defer userMockServer.Close()
+
+	bodyBytes, _ := json.Marshal(map[string]interface{}{"user_id": "user123", "amount": 20})
+	req := httptest.NewRequest(http.MethodPost, "/sales", bytes.NewBuffer(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sales?user_id=user123", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	lastModified := w.Header().Get("Last-Modified")
+	assert.NotEmpty(t, etag, "Expected an ETag header")
+	assert.NotEmpty(t, lastModified, "Expected a Last-Modified header")
+	assert.NotEmpty(t, w.Header().Get("Cache-Control"), "Expected a Cache-Control header")
+
+	req = httptest.NewRequest(http.MethodGet, "/sales?user_id=user123", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code, "Expected 304 for a matching ETag")
+	assert.Empty(t, w.Body.String(), "Expected no body on 304")
+
+	req = httptest.NewRequest(http.MethodGet, "/sales?user_id=user123", nil)
+	req.Header.Set("If-Modified-Since", lastModified)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code, "Expected 304 when not modified since Last-Modified")
+
+	req = httptest.NewRequest(http.MethodGet, "/sales?user_id=user123", nil)
+	req.Header.Set("If-None-Match", `W/"stale"`)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, "Expected 200 for a stale ETag")
+}
+
+// TestVersion_ReturnsBuildInfo verifica el endpoint de versión del build.
+func TestVersion_ReturnsBuildInfo(t *testing.T) {
+	router, userMockServer := InitRoutesTests()
+	defer userMockServer.Close()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/version", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var info map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &info))
+	assert.Equal(t, "dev", info["version"])
+	assert.Contains(t, info, "commit")
+	assert.Contains(t, info, "build_time")
+	assert.Contains(t, info, "features")
+}
+
+// TestAccessLog_SamplesSuccessfulRequests verifica que el access log JSON
+// descarte los 2xx muestreados y siempre registre los errores.
+func TestAccessLog_SamplesSuccessfulRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	output := filepath.Join(t.TempDir(), "access.log")
+
+	cfg := config.Default()
+	cfg.AccessLogOutput = output
+	cfg.AccessLogSampleAfter = 0
+	cfg.AccessLogSampleRates = map[string]float64{"/ping": 0}
+	assert.NoError(t, api.InitRoutesWithConfig(router, cfg))
+
+	for i := 0; i < 3; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+	}
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	data, err := os.ReadFile(output)
+	assert.NoError(t, err)
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	assert.Len(t, lines, 1, "Expected only the 404 in the access log")
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(lines[0], &entry))
+	assert.Equal(t, float64(http.StatusNotFound), entry["status"])
+	assert.Equal(t, "/missing", entry["path"])
+	assert.Equal(t, false, entry["slow"])
+}
+
+// TestShippingWebhook_SignedOutOfOrderEvents verifica la firma de los eventos
+// del carrier y que un evento viejo no haga retroceder la entrega.
+func TestShippingWebhook_SignedOutOfOrderEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	userMockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": "user123", "name": "Test User 123"}`))
+	}))
+	defer userMockServer.Close()
+
+	cfg := config.Default()
+	cfg.UserServiceURL = userMockServer.URL + "/users"
+	cfg.RandomSeed = testRandomSeed
+	cfg.ShippingWebhookSecret = "carrier-secret"
+	assert.NoError(t, api.InitRoutesWithConfig(router, cfg))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/sales", bytes.NewBufferString(`{"user_id": "user123", "amount": 20}`)))
+	var sale sales.Sale
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &sale))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPatch, "/sales/"+sale.ID, bytes.NewBufferString(`{"status": "approved"}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	send := func(body, signature string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/shipping", bytes.NewBufferString(body))
+		req.Header.Set(shipping.SignatureHeader, signature)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	delivered := fmt.Sprintf(`{"event_id": "e2", "reference": %q, "carrier": "dhl", "tracking_number": "JD0142", "status": "delivered"}`, sale.ID)
+	shipped := fmt.Sprintf(`{"event_id": "e1", "reference": %q, "carrier": "dhl", "status": "in_transit"}`, sale.ID)
+
+	w = send(delivered, shipping.Sign("wrong-secret", time.Now(), []byte(delivered)))
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "Expected unsigned events to be rejected")
+
+	w = send(delivered, shipping.Sign("carrier-secret", time.Now(), []byte(delivered)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"result":"applied"`)
+
+	// El evento de tránsito llega tarde: se reconoce sin cambios
+	w = send(shipped, shipping.Sign("carrier-secret", time.Now(), []byte(shipped)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"result":"duplicate"`)
+	assert.Contains(t, w.Body.String(), `"fulfillment_status":"delivered"`)
+}
+
+// TestMySales_ScopedToTokenUser verifica que /me/sales solo devuelva las
+// ventas del usuario del token, paginadas.
+func TestMySales_ScopedToTokenUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	const secret = "customer-token-secret-0123456789abcdef"
+
+	storage := sales.NewLocalStorage()
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	for i, userID := range []string{"user123", "user123", "user123", "other"} {
+		storage.Set(&sales.Sale{ID: fmt.Sprintf("s%d", i), UserID: userID, Amount: 10, Status: sales.StatusApproved, CreatedAt: base.Add(time.Duration(i) * time.Hour)})
+	}
+	cfg := config.Default()
+	cfg.JWTSecret = secret
+	cfg.HTTPCacheMaxAge = time.Minute
+	assert.NoError(t, api.InitRoutesWithDependencies(router, cfg, api.Dependencies{Storage: storage}))
+
+	sign := func(key, subject string) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+			Subject:   subject,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		}).SignedString([]byte(key))
+		assert.NoError(t, err)
+		return token
+	}
+	get := func(url, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, get("/me/sales", "").Code, "Expected a token to be required")
+	assert.Equal(t, http.StatusUnauthorized, get("/me/sales", sign("forged-secret-0123456789abcdefghij", "other")).Code, "Expected forged tokens to be rejected")
+
+	token := sign(secret, "user123")
+	var page sales.Page
+	w := get("/me/sales?limit=2", token)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Len(t, page.Results, 2)
+	assert.Equal(t, "s2", page.Results[0].ID, "Expected newest first")
+	assert.NotEmpty(t, page.NextCursor)
+
+	w = get("/me/sales?limit=2&cursor="+page.NextCursor, token)
+	page = sales.Page{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Len(t, page.Results, 1)
+	assert.Equal(t, "s0", page.Results[0].ID)
+	assert.Empty(t, page.NextCursor, "Expected the last page to have no cursor")
+
+	// El token también acota la búsqueda general al propio usuario
+	w = get("/sales?status=approved", token)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"s0"`)
+	assert.NotContains(t, w.Body.String(), `"id":"s3"`, "Expected other users' sales hidden")
+	// Son las ventas de un cliente: ningún cache compartido debe guardarlas
+	assert.Equal(t, "private, max-age=60", w.Header().Get("Cache-Control"))
+	w = get("/sales/stats", token)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Header().Get("Cache-Control"), "public")
+}
+
+func TestPublicSaleStatus_LimitedFieldsAndRateLimited(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	storage := sales.NewLocalStorage()
+	shipped := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	storage.Set(&sales.Sale{ID: saleID, UserID: "user123", Amount: 250, Status: sales.StatusApproved, FulfillmentStatus: sales.FulfillmentShipped,
+		TrackingNumber: "TRK-1", ShippedAt: &shipped, Metadata: map[string]string{"email": "buyer@example.com"}, CreatedAt: shipped.Add(-time.Hour)})
+	storage.Set(&sales.Sale{ID: "d1", UserID: "user123", Amount: 10, Status: sales.StatusDraft})
+	cfg := config.Default()
+	cfg.PublicLookupRate = 0.001
+	cfg.PublicLookupBurst = 3
+	assert.NoError(t, api.InitRoutesWithDependencies(router, cfg, api.Dependencies{Storage: storage}))
+
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		return w
+	}
+
+	w := get("/public/sales/TRK-1/status")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "approved", body["status"])
+	assert.Equal(t, "shipped", body["fulfillment_status"])
+	assert.NotNil(t, body["shipped_at"])
+	for _, field := range []string{"id", "user_id", "amount", "metadata", "tracking_number"} {
+		assert.NotContains(t, body, field, "Expected sensitive fields excluded")
+	}
+
+	assert.Equal(t, http.StatusNotFound, get("/public/sales/d1/status").Code, "Expected drafts hidden")
+	assert.Equal(t, http.StatusNotFound, get("/public/sales/unknown/status").Code)
+
+	w = get("/public/sales/" + saleID + "/status")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code, "Expected the burst to be exhausted")
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+}
+
+func TestShareLink_SignedExpiringAndRevocable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	storage := sales.NewLocalStorage()
+	storage.Set(&sales.Sale{ID: saleID, UserID: "user123", Amount: 99, Status: sales.StatusApproved, Metadata: map[string]string{"email": "buyer@example.com"}, CreatedAt: time.Now()})
+	cfg := config.Default()
+	cfg.ShareLinkSecret = "share-link-secret-0123456789abcdef"
+	cfg.ShareLinkBaseURL = "https://shop.example.com"
+	assert.NoError(t, api.InitRoutesWithDependencies(router, cfg, api.Dependencies{Storage: storage}))
+
+	do := func(method, url, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/sales/"+saleID+"/share", `{"ttl_seconds":99999999}`).Code, "Expected the ttl capped")
+
+	w := do(http.MethodPost, "/sales/"+saleID+"/share", `{"ttl_seconds":3600}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var link struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &link))
+	path, ok := strings.CutPrefix(link.URL, "https://shop.example.com")
+	assert.True(t, ok, "Expected the configured base URL, got %s", link.URL)
+
+	w = do(http.MethodGet, path, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"`+saleID+`"`)
+	assert.NotContains(t, w.Body.String(), "user123")
+	assert.NotContains(t, w.Body.String(), "buyer@example.com")
+	assert.Equal(t, "private, no-store", w.Header().Get("Cache-Control"))
+
+	// Extender el vencimiento invalida la firma
+	parts := strings.Split(strings.TrimPrefix(path, "/shared/"), ".")
+	forged := "/shared/" + parts[0] + "." + strconv.FormatInt(time.Now().Add(1000*time.Hour).Unix(), 10) + "." + parts[2]
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, forged, "").Code, "Expected tampered tokens rejected")
+
+	assert.Equal(t, http.StatusOK, do(http.MethodDelete, "/share-links/"+link.ID, "").Code)
+	assert.Equal(t, http.StatusGone, do(http.MethodGet, path, "").Code, "Expected revoked links to stop working")
+}
+
+func TestPatchSale_RepeatedTransitionIsIdempotent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	storage := sales.NewLocalStorage()
+	storage.Set(&sales.Sale{ID: saleID, UserID: "user123", Amount: 10, Status: sales.StatusPending, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	assert.NoError(t, api.InitRoutesWithDependencies(router, config.Default(), api.Dependencies{Storage: storage}))
+
+	patch := func(url, status string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(`{"status":"`+status+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, patch("/sales/"+saleID, "approved").Code)
+	w := patch("/sales/"+saleID, "approved")
+	assert.Equal(t, http.StatusOK, w.Code, "Expected a double-clicked approve to return the current state")
+	assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+	assert.Contains(t, w.Body.String(), `"version":1`, "Expected the repeat not to change the sale")
+
+	assert.Equal(t, http.StatusConflict, patch("/sales/"+saleID+"?strict=true", "approved").Code)
+	w = patch("/sales/"+saleID, "rejected")
+	assert.Equal(t, http.StatusConflict, w.Code, "Expected real conflicts to stay 409")
+
+	// El 409 trae el estado actual para resolver el conflicto sin otro GET
+	var conflict map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &conflict))
+	assert.Equal(t, "approved", conflict["current_status"])
+	assert.Equal(t, float64(1), conflict["version"])
+}
+
+func TestGetSale_IncludesAllowedTransitions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	storage := sales.NewLocalStorage()
+	storage.Set(&sales.Sale{ID: saleID, UserID: "user123", Amount: 10, Status: sales.StatusPending, CreatedAt: time.Now()})
+	assert.NoError(t, api.InitRoutesWithDependencies(router, config.Default(), api.Dependencies{Storage: storage}))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sales/"+saleID, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		ID                 string   `json:"id"`
+		AllowedTransitions []string `json:"allowed_transitions"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, saleID, body.ID)
+	assert.Equal(t, []string{"approved", "rejected"}, body.AllowedTransitions)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sales/"+missingID, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetSale_HALLinksAreOptIn(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	storage := sales.NewLocalStorage()
+	storage.Set(&sales.Sale{ID: saleID, UserID: "user123", Amount: 10, Status: sales.StatusApproved, CreatedAt: time.Now()})
+	assert.NoError(t, api.InitRoutesWithDependencies(router, config.Default(), api.Dependencies{Storage: storage}))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sales/"+saleID, nil))
+	assert.NotContains(t, w.Body.String(), "_links", "Expected links only when requested")
+
+	req := httptest.NewRequest(http.MethodGet, "/sales/"+saleID, nil)
+	req.Header.Set("Accept", "application/hal+json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/hal+json")
+	var body struct {
+		Links map[string]struct {
+			Href   string `json:"href"`
+			Method string `json:"method"`
+		} `json:"_links"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "/sales/"+saleID, body.Links["self"].Href)
+	assert.Equal(t, http.MethodPost, body.Links["refund"].Method)
+	assert.Contains(t, body.Links, "dispute")
+	assert.NotContains(t, body.Links, "approve", "Expected no approve link on an approved sale")
+}
+
+func TestGetSales_ByIDsReportsMissing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	storage := sales.NewLocalStorage()
+	for _, id := range []string{"a", "b", "c"} {
+		storage.Set(&sales.Sale{ID: id, UserID: "user123", Amount: 10, Status: sales.StatusApproved, CreatedAt: time.Now()})
+	}
+	assert.NoError(t, api.InitRoutesWithDependencies(router, config.Default(), api.Dependencies{Storage: storage}))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sales?ids=c,zz,a", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Results []sales.Sale `json:"results"`
+		Missing []string     `json:"missing"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Len(t, body.Results, 2)
+	assert.Equal(t, "c", body.Results[0].ID, "Expected request order")
+	assert.Equal(t, []string{"zz"}, body.Missing)
+
+	ids := make([]string, sales.MaxLookupIDs+1)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i)
+	}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sales?ids="+strings.Join(ids, ","), nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestReports_TimezoneAware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	storage := sales.NewLocalStorage()
+	created := time.Date(2024, 5, 2, 2, 0, 0, 0, time.UTC)
+	storage.Set(&sales.Sale{ID: saleID, UserID: "user123", Amount: 10, Status: sales.StatusApproved, CreatedAt: created, UpdatedAt: created})
+	assert.NoError(t, api.InitRoutesWithDependencies(router, config.Default(), api.Dependencies{Storage: storage}))
+
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		return w
+	}
+
+	w := get("/sales/stats?tz=America/Santiago")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var stats struct {
+		Quantity int    `json:"quantity"`
+		Timezone string `json:"timezone"`
+		Daily    []sales.DailyTotal
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	assert.Equal(t, 1, stats.Quantity)
+	assert.Equal(t, "America/Santiago", stats.Timezone)
+	assert.Equal(t, []sales.DailyTotal{{Date: "2024-05-01", Count: 1, TotalAmount: 10}}, stats.Daily)
+	assert.NotContains(t, get("/sales/stats").Body.String(), "daily", "Expected the plain stats without tz")
+	assert.Equal(t, http.StatusBadRequest, get("/sales/stats?tz=Mars/Olympus").Code)
+
+	w = get("/ledger?format=csv&tz=America/Santiago")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "2024-05-01T22:00:00-04:00", "Expected dates with the explicit local offset")
+	assert.Contains(t, get("/ledger?format=csv").Body.String(), "2024-05-02T02:00:00Z")
+}
+
+func TestSearch_FlexibleTimeFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	storage := sales.NewLocalStorage()
+	now := time.Now().UTC()
+	storage.Set(&sales.Sale{ID: "recent", UserID: "user123", Amount: 10, Status: sales.StatusApproved, CreatedAt: now.Add(-time.Hour), UpdatedAt: now})
+	storage.Set(&sales.Sale{ID: "old", UserID: "user123", Amount: 10, Status: sales.StatusApproved, CreatedAt: now.AddDate(0, 0, -10), UpdatedAt: now})
+	assert.NoError(t, api.InitRoutesWithDependencies(router, config.Default(), api.Dependencies{Storage: storage}))
+
+	search := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sales?status=approved&"+query, nil))
+		return w
+	}
+
+	w := search("created_from=-24h")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"recent"`)
+	assert.NotContains(t, w.Body.String(), `"id":"old"`)
+
+	w = search("created_to=-7d")
+	assert.Contains(t, w.Body.String(), `"id":"old"`)
+	assert.NotContains(t, w.Body.String(), `"id":"recent"`)
+
+	// Una fecha como límite superior incluye el día completo
+	day := now.AddDate(0, 0, -10).Format(time.DateOnly)
+	w = search("created_from=" + day + "&created_to=" + day)
+	assert.Contains(t, w.Body.String(), `"id":"old"`)
+
+	w = search("created_from=last-tuesday")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "RFC3339", "Expected the error to list the accepted formats")
+	assert.Contains(t, w.Body.String(), "yesterday")
+}
+
+func TestResponses_MessagePackOnRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	storage := sales.NewLocalStorage()
+	storage.Set(&sales.Sale{ID: saleID, UserID: "user123", Amount: 12.5, Status: sales.StatusApproved, Version: 3, CreatedAt: time.Now()})
+	assert.NoError(t, api.InitRoutesWithDependencies(router, config.Default(), api.Dependencies{Storage: storage}))
+
+	req := httptest.NewRequest(http.MethodGet, "/sales/"+saleID, nil)
+	req.Header.Set("Accept", "application/msgpack")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/msgpack")
+
+	var body map[string]any
+	handle := new(codec.MsgpackHandle)
+	handle.RawToString = true
+	assert.NoError(t, codec.NewDecoderBytes(w.Body.Bytes(), handle).Decode(&body))
+	assert.Equal(t, saleID, body["id"])
+	assert.EqualValues(t, 3, body["version"], "Expected integers kept as integers")
+	assert.EqualValues(t, 12.5, body["amount"])
+
+	// Los errores también respetan el formato pedido
+	req = httptest.NewRequest(http.MethodGet, "/sales/"+missingID, nil)
+	req.Header.Set("Accept", "application/msgpack")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/msgpack")
+
+	// Las exportaciones que no son JSON no se tocan
+	req = httptest.NewRequest(http.MethodGet, "/ledger?format=csv", nil)
+	req.Header.Set("Accept", "application/msgpack")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+}
+
+func TestSearch_NDJSONStream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	storage := sales.NewLocalStorage()
+	for _, id := range []string{"a", "b"} {
+		storage.Set(&sales.Sale{ID: id, UserID: "user123", Amount: 10, Status: sales.StatusApproved, CreatedAt: time.Now()})
+	}
+	cfg := config.Default()
+	cfg.ResponseCacheTTLs = map[string]time.Duration{"/sales": time.Minute}
+	assert.NoError(t, api.InitRoutesWithDependencies(router, cfg, api.Dependencies{Storage: storage}))
+
+	search := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/sales?status=approved", nil)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// El JSON cacheado no debe servirse a quien pide NDJSON
+	assert.Contains(t, search("application/json").Body.String(), `"results"`)
+	w := search("application/x-ndjson")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	assert.Len(t, lines, 3, "Expected one line per sale plus the metadata line")
+	var sale sales.Sale
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &sale))
+	assert.NotEmpty(t, sale.ID)
+	var trailer struct {
+		Metadata sales.SalesMetadata `json:"metadata"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(lines[2]), &trailer))
+	assert.Equal(t, 2, trailer.Metadata.Quantity)
+}
+
+func TestSearch_AsOfReturnsHistoricalState(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	storage := sales.NewLocalStorage()
+	now := time.Now().UTC()
+	storage.Set(&sales.Sale{ID: saleID, UserID: "user123", Amount: 10, Status: sales.StatusPending, CreatedAt: now.Add(-2 * time.Hour), UpdatedAt: now.Add(-2 * time.Hour), Version: 1})
+	assert.NoError(t, api.InitRoutesWithDependencies(router, config.Default(), api.Dependencies{Storage: storage}))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPatch, "/sales/"+saleID, strings.NewReader(`{"status":"approved"}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	search := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sales?"+query, nil))
+		return w
+	}
+
+	// Hace una hora la venta todavía estaba pendiente
+	w = search("status=pending&as_of=-1h")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"`+saleID+`"`)
+	assert.NotContains(t, search("status=pending").Body.String(), `"id":"`+saleID+`"`)
+
+	// Antes de su creación la venta no existía
+	assert.NotContains(t, search("as_of="+now.Add(-3*time.Hour).Format(time.RFC3339)).Body.String(), `"id":"`+saleID+`"`)
+
+	w = search("as_of=+1h")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// flakyStorage falla todas las llamadas mientras failing está activo.
+type flakyStorage struct {
+	sales.Storage
+	failing bool
+}
+
+func (f *flakyStorage) Read(id string) (*sales.Sale, error) {
+	if f.failing {
+		return nil, fmt.Errorf("storage unavailable")
+	}
+	return f.Storage.Read(id)
+}
+
+func (f *flakyStorage) GetAll() ([]*sales.Sale, error) {
+	if f.failing {
+		return nil, fmt.Errorf("storage unavailable")
+	}
+	return f.Storage.GetAll()
+}
+
+func TestLoadShedding_ShedsReportsWhileStorageFails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	now := time.Now().UTC()
+	storage := &flakyStorage{Storage: sales.NewLocalStorage()}
+	storage.Set(&sales.Sale{ID: saleID, UserID: "user123", Amount: 10, Status: sales.StatusPending, CreatedAt: now, UpdatedAt: now, Version: 1})
+	cfg := config.Default()
+	cfg.ShedMaxErrorRate = 0.5
+	cfg.ShedMinSamples = 3
+	assert.NoError(t, api.InitRoutesWithDependencies(router, cfg, api.Dependencies{Storage: storage}))
+
+	send := func(method, url, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, url, strings.NewReader(body)))
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/sales/stats", "").Code)
+
+	storage.failing = true
+	for i := 0; i < 5; i++ {
+		assert.NotEqual(t, http.StatusServiceUnavailable, send(http.MethodGet, "/sales/"+saleID, "").Code, "Expected reads of a sale not shed")
+	}
+	storage.failing = false
+
+	w := send(http.MethodGet, "/sales/stats", "")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, "Expected stats shed while storage fails")
+	assert.Equal(t, "5", w.Header().Get("Retry-After"))
+
+	w = send(http.MethodPatch, "/sales/"+saleID, `{"status":"approved"}`)
+	assert.Equal(t, http.StatusOK, w.Code, "Expected updates served while shedding")
+}
+
+// blockingStorage detiene las lecturas de la venta slowID hasta que se cierra
+// release.
+type blockingStorage struct {
+	sales.Storage
+	reading chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingStorage) Read(id string) (*sales.Sale, error) {
+	if id == slowID {
+		b.reading <- struct{}{}
+		<-b.release
+	}
+	return b.Storage.Read(id)
+}
+
+func TestPriorityLanes_BatchDoesNotStarveInteractive(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	now := time.Now().UTC()
+	storage := &blockingStorage{Storage: sales.NewLocalStorage(), reading: make(chan struct{}, 1), release: make(chan struct{})}
+	storage.Set(&sales.Sale{ID: saleID, UserID: "user123", Amount: 10, Status: sales.StatusPending, CreatedAt: now, UpdatedAt: now, Version: 1})
+	cfg := config.Default()
+	cfg.LaneBatchConcurrency = 1
+	cfg.LaneInteractiveConcurrency = 1
+	cfg.LaneQueueTimeout = 10 * time.Millisecond
+	assert.NoError(t, api.InitRoutesWithDependencies(router, cfg, api.Dependencies{Storage: storage}))
+
+	batch := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/sales/"+slowID, nil)
+		req.Header.Set("X-Request-Class", "batch")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- batch() }()
+	<-storage.reading
+
+	w := batch()
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, "Expected the batch lane full")
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPatch, "/sales/"+saleID, strings.NewReader(`{"status":"approved"}`)))
+	assert.Equal(t, http.StatusOK, w.Code, "Expected interactive requests served while the batch lane is full")
+
+	close(storage.release)
+	assert.Equal(t, http.StatusNotFound, (<-done).Code)
+}
+
+func TestConcurrencyLimit_RejectsBeyondTheQueue(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	storage := &blockingStorage{Storage: sales.NewLocalStorage(), reading: make(chan struct{}, 1), release: make(chan struct{})}
+	storage.Set(&sales.Sale{ID: saleID, UserID: "user123", Amount: 10, Status: sales.StatusPending})
+	cfg := config.Default()
+	cfg.ConcurrencyLimits = map[string]int{"/sales": 1}
+	cfg.ConcurrencyQueueSize = 0
+	assert.NoError(t, api.InitRoutesWithDependencies(router, cfg, api.Dependencies{Storage: storage}))
+
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		return w
+	}
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- get("/sales/" + slowID) }()
+	<-storage.reading
+
+	w := get("/sales/" + saleID)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, "Expected a fast rejection with the group full")
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusOK, get("/ping").Code, "Expected other route groups unaffected")
+
+	close(storage.release)
+	assert.Equal(t, http.StatusNotFound, (<-done).Code)
+	assert.Equal(t, http.StatusOK, get("/sales/"+saleID).Code, "Expected the slot released")
+}
+
+func TestSaleRoutes_ValidateIDAndLookUpByReference(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	storage := sales.NewLocalStorage()
+	storage.Set(&sales.Sale{ID: saleID, UserID: "user123", Amount: 10, Status: sales.StatusApproved, TrackingNumber: "TRK-9", CreatedAt: time.Now()})
+	assert.NoError(t, api.InitRoutesWithDependencies(router, config.Default(), api.Dependencies{Storage: storage}))
+
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		return w
+	}
+
+	// Un ID mal formado es un 400; un UUID inexistente, un 404
+	w := get("/sales/not-a-uuid")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid sale id")
+	assert.Equal(t, http.StatusBadRequest, get("/sales/{"+saleID+"}/audit").Code, "Expected only the canonical form")
+	assert.Equal(t, http.StatusNotFound, get("/sales/"+missingID).Code)
+	assert.Equal(t, http.StatusOK, get("/sales/"+saleID).Code)
+
+	w = get("/sales/by-reference/TRK-9")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"`+saleID+`"`)
+	assert.Equal(t, http.StatusOK, get("/sales/by-reference/"+saleID).Code)
+	assert.Equal(t, http.StatusNotFound, get("/sales/by-reference/TRK-0").Code)
+}
+
+func TestRouting_UnknownRoutesAndWrongMethods(t *testing.T) {
+	router, userMockServer := InitRoutesTests()
+	defer userMockServer.Close()
+
+	do := func(method, url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, url, nil))
+		return w
+	}
+
+	w := do(http.MethodDelete, "/ping")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET", w.Header().Get("Allow"))
+	var body map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "method not allowed", body["error"])
+	assert.Equal(t, []any{"GET"}, body["allowed"])
+
+	// Con barra final no hay redirección, sea cual sea el método
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		w = do(method, "/sales/")
+		assert.Equal(t, http.StatusNotFound, w.Code, method)
+		assert.Empty(t, w.Header().Get("Location"))
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+		assert.Contains(t, w.Body.String(), `"path":"/sales/"`)
+	}
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/nope").Code)
+}
+
+func TestOpenAPIValidation_RejectsRequestsOffTheSpec(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	userMockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": "user123", "name": "Test User 123"}`))
+	}))
+	defer userMockServer.Close()
+
+	storage := sales.NewLocalStorage()
+	storage.Set(&sales.Sale{ID: saleID, UserID: "user123", Amount: 10, Status: sales.StatusPending, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	cfg := config.Default()
+	cfg.UserServiceURL = userMockServer.URL + "/users"
+	cfg.OpenAPIValidation = true
+	cfg.OpenAPIValidateResponses = true
+	assert.NoError(t, api.InitRoutesWithDependencies(router, cfg, api.Dependencies{Storage: storage}))
+
+	do := func(method, url, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, body := range []string{`{"user_id": "user123", "amount": "ten"}`, `{"amount": 10}`, `{"user_id": "user123", "amount": 10, "status": "approved"}`} {
+		w := do(http.MethodPost, "/sales", body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+		assert.Contains(t, w.Body.String(), "request does not match the API schema", body)
+	}
+	w := do(http.MethodPatch, "/sales/"+saleID+"?strict=maybe", `{"status": "approved"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "strict")
+
+	// Las respuestas de las operaciones válidas cumplen el contrato
+	assert.Equal(t, http.StatusCreated, do(http.MethodPost, "/sales", `{"user_id": "user123", "amount": 10}`).Code)
+	assert.Equal(t, http.StatusOK, do(http.MethodPatch, "/sales/"+saleID, `{"status": "approved"}`).Code)
+	for _, url := range []string{"/sales/" + saleID, "/sales?user_id=user123", "/sales/stats", "/ping"} {
+		w := do(http.MethodGet, url, "")
+		assert.Equal(t, http.StatusOK, w.Code, url)
+		assert.NotContains(t, w.Body.String(), "does not match", url)
+	}
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/sales/"+missingID, "").Code)
+}
+
+func TestSQLiteBackend_KeepsSalesAcrossRestarts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	_, userMockServer := InitRoutesTests()
+	defer userMockServer.Close()
+
+	cfg := config.Default()
+	cfg.UserServiceURL = userMockServer.URL + "/users"
+	cfg.SalesStorageBackend = config.BackendSQLite
+	cfg.SQLitePath = filepath.Join(t.TempDir(), "sales.db")
+	assert.NoError(t, cfg.Validate())
+	start := func() *gin.Engine {
+		router := gin.New()
+		assert.NoError(t, api.InitRoutesWithConfig(router, cfg))
+		return router
+	}
+
+	router := start()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/sales", strings.NewReader(`{"user_id": "user123", "amount": 42}`)))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var created sales.Sale
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	// Un segundo router sobre el mismo archivo simula el reinicio
+	router = start()
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sales/"+created.ID, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"amount":42`)
+}
+
+func TestRedisBackend_SharesSalesBetweenInstances(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	_, userMockServer := InitRoutesTests()
+	defer userMockServer.Close()
+
+	server := miniredis.RunT(t)
+	cfg := config.Default()
+	cfg.UserServiceURL = userMockServer.URL + "/users"
+	cfg.SalesStorageBackend = config.BackendRedis
+	cfg.RedisAddr = server.Addr()
+	cfg.RedisSalesTTL = 24 * time.Hour
+	assert.NoError(t, cfg.Validate())
+	start := func() *gin.Engine {
+		router := gin.New()
+		assert.NoError(t, api.InitRoutesWithConfig(router, cfg))
+		return router
+	}
+
+	w := httptest.NewRecorder()
+	start().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/sales", strings.NewReader(`{"user_id": "user123", "amount": 42}`)))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var created sales.Sale
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, 24*time.Hour, server.TTL("api_sales:sale:"+created.ID))
+
+	// Otra instancia sobre el mismo Redis ve la venta
+	w = httptest.NewRecorder()
+	start().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sales/"+created.ID, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"amount":42`)
+}
+
+func TestSLOs_ReportTheErrorBudgetOnAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	_, userMockServer := InitRoutesTests()
+	defer userMockServer.Close()
+
+	cfg := config.Default()
+	cfg.UserServiceURL = userMockServer.URL + "/users"
+	cfg.AdminAPIKey = "admin-secret"
+	cfg.SLOs = `[{"name":"create_sale","method":"POST","route":"/sales","target":0.99,"latency_ms":5000}]`
+	assert.NoError(t, cfg.Validate())
+	router := gin.New()
+	assert.NoError(t, api.InitRoutesWithConfig(router, cfg))
+
+	for _, body := range []string{`{"user_id": "user123", "amount": 42}`, `{"amount": -1}`} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/sales", strings.NewReader(body)))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/slo", nil)
+	req.Header.Set("X-API-Key", "admin-secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		SLOs []slo.Status `json:"slos"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	// El 400 es un error del cliente y no consume presupuesto
+	if assert.Len(t, resp.SLOs, 1) {
+		assert.Equal(t, int64(2), resp.SLOs[0].Total)
+		assert.Equal(t, int64(2), resp.SLOs[0].Good)
+		assert.Equal(t, 1.0, resp.SLOs[0].BudgetRemaining)
+	}
+}
+
+func TestMigrations_RequiredBeforeStartWithoutMigrateOnStart(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	_, userMockServer := InitRoutesTests()
+	defer userMockServer.Close()
+
+	cfg := config.Default()
+	cfg.UserServiceURL = userMockServer.URL + "/users"
+	cfg.SalesStorageBackend = config.BackendSQLite
+	cfg.SQLitePath = filepath.Join(t.TempDir(), "sales.db")
+	cfg.MigrateOnStart = false
+	assert.NoError(t, cfg.Validate())
+
+	err := api.InitRoutesWithConfig(gin.New(), cfg)
+	assert.ErrorContains(t, err, "pending schema migrations")
+
+	var out strings.Builder
+	assert.NoError(t, api.Migrate(context.Background(), cfg, &out))
+	assert.Equal(t, "sqlite: applied 0001_create_sales\nsqlite: applied 0002_index_tracking_number\nsqlite: applied 0003_create_audit\n", out.String())
+	assert.NoError(t, api.InitRoutesWithConfig(gin.New(), cfg))
+}
+
+// TestAuditExport_VerifiesOffline verifica que la exportación conserva el
+// encadenado de hashes y la metadata sensible cifrada.
+func TestAuditExport_VerifiesOffline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	_, userMockServer := InitRoutesTests()
+	defer userMockServer.Close()
+
+	cfg := config.Default()
+	cfg.UserServiceURL = userMockServer.URL + "/users"
+	cfg.AdminAPIKey = "admin-secret"
+	cfg.SensitiveMetadataKeys = []string{"tax_id"}
+	cfg.FieldEncryptionKey = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+	assert.NoError(t, cfg.Validate())
+	router := gin.New()
+	assert.NoError(t, api.InitRoutesWithConfig(router, cfg))
+
+	do := func(method, path, body, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/sales", `{"user_id": "user123", "amount": 10, "status": "draft", "metadata": {"tax_id": "20-123"}}`, "")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var created struct {
+		ID string `json:"id"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	for _, amount := range []string{"20", "30"} {
+		assert.Equal(t, http.StatusOK, do(http.MethodPatch, "/sales/"+created.ID, `{"amount": `+amount+`}`, "").Code)
+	}
+
+	assert.Equal(t, http.StatusForbidden, do(http.MethodGet, "/admin/audit/export", "", "").Code, "Expected the export restricted to admins")
+	w = do(http.MethodGet, "/admin/audit/export", "", "admin-secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "20-123", "Expected the sensitive metadata encrypted")
+
+	var entries []*sales.AuditEntry
+	dec := json.NewDecoder(strings.NewReader(w.Body.String()))
+	for dec.More() {
+		var entry sales.AuditEntry
+		assert.NoError(t, dec.Decode(&entry))
+		entries = append(entries, &entry)
+	}
+	// La creación y las dos ediciones
+	assert.Len(t, entries, 3)
+	result := sales.VerifyAuditChain(entries)
+	assert.True(t, result.Valid, "Expected the exported chain to verify, got %+v", result)
+}
+
+// TestSaleLease_OwnedByTheAuthenticatedCaller verifica que el dueño del
+// lease sale de la autenticación y no de un header que el cliente controla.
+func TestSaleLease_OwnedByTheAuthenticatedCaller(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	_, userMockServer := InitRoutesTests()
+	defer userMockServer.Close()
+
+	cfg := config.Default()
+	cfg.UserServiceURL = userMockServer.URL + "/users"
+	cfg.AdminAPIKey = "admin-secret"
+	assert.NoError(t, cfg.Validate())
+	router := gin.New()
+	assert.NoError(t, api.InitRoutesWithConfig(router, cfg))
+
+	do := func(method, path, body, apiKey, operator string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		if operator != "" {
+			req.Header.Set("X-Operator-ID", operator)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/sales", `{"user_id": "user123", "amount": 10, "status": "draft"}`, "", "")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var created struct {
+		ID string `json:"id"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	w = do(http.MethodPost, "/sales/"+created.ID+"/lock", "", "admin-secret", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var lease sales.SaleLock
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &lease))
+	assert.True(t, strings.HasPrefix(lease.Owner, "key:"), "Expected the API key as owner, got %q", lease.Owner)
+
+	// Un cliente anónimo que copia el dueño en el header no toma ni libera el lease
+	assert.Equal(t, http.StatusLocked, do(http.MethodPatch, "/sales/"+created.ID, `{"amount": 20}`, "", lease.Owner).Code)
+	assert.Equal(t, http.StatusLocked, do(http.MethodDelete, "/sales/"+created.ID+"/lock", "", "", lease.Owner).Code)
+
+	assert.Equal(t, http.StatusOK, do(http.MethodPatch, "/sales/"+created.ID, `{"amount": 20}`, "admin-secret", "").Code)
+	assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/sales/"+created.ID+"/lock", "", "admin-secret", "").Code)
+}